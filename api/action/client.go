@@ -76,13 +76,70 @@ func (c *Client) ListCompleted(arg params.Entities) (params.ActionsByReceivers,
 	return results, err
 }
 
-// Cancel attempts to cancel a queued up Action from running.
-func (c *Client) Cancel(arg params.Actions) (params.ActionResults, error) {
+// Cancel takes a list of Entities representing Actions, and cancels
+// each of them. Pending Actions are cancelled at once; running Actions
+// are stopped by their units.
+func (c *Client) Cancel(arg params.Entities) (params.ActionResults, error) {
 	results := params.ActionResults{}
 	err := c.facade.FacadeCall("Cancel", arg, &results)
 	return results, err
 }
 
+// Schedule takes a list of Actions with cron schedules, and arranges
+// for each to be enqueued on its receiver every time the schedule
+// fires.
+func (c *Client) Schedule(arg params.ScheduleActions) (params.ScheduledActionResults, error) {
+	results := params.ScheduledActionResults{}
+	if c.BestAPIVersion() < 1 {
+		return results, errors.NotImplementedf("Schedule")
+	}
+	err := c.facade.FacadeCall("Schedule", arg, &results)
+	return results, err
+}
+
+// ListSchedules returns the scheduled actions for each of the given
+// units, or for every unit in the environment if none are given.
+func (c *Client) ListSchedules(arg params.Entities) (params.ScheduledActionResults, error) {
+	results := params.ScheduledActionResults{}
+	if c.BestAPIVersion() < 1 {
+		return results, errors.NotImplementedf("ListSchedules")
+	}
+	err := c.facade.FacadeCall("ListSchedules", arg, &results)
+	return results, err
+}
+
+// Unschedule removes the scheduled actions with the given ids.
+func (c *Client) Unschedule(arg params.ScheduledActionIds) (params.ErrorResults, error) {
+	results := params.ErrorResults{}
+	if c.BestAPIVersion() < 1 {
+		return results, errors.NotImplementedf("Unschedule")
+	}
+	err := c.facade.FacadeCall("Unschedule", arg, &results)
+	return results, err
+}
+
+// EnqueueOperations takes a list of Actions, each to be enqueued on
+// many units of a service, and starts an operation for each.
+func (c *Client) EnqueueOperations(arg params.ServiceActions) (params.ActionOperationResults, error) {
+	results := params.ActionOperationResults{}
+	if c.BestAPIVersion() < 1 {
+		return results, errors.NotImplementedf("EnqueueOperations")
+	}
+	err := c.facade.FacadeCall("EnqueueOperations", arg, &results)
+	return results, err
+}
+
+// ActionOperations returns the action operations with the given ids,
+// or unique id prefixes.
+func (c *Client) ActionOperations(arg params.ActionOperationIds) (params.ActionOperationResults, error) {
+	results := params.ActionOperationResults{}
+	if c.BestAPIVersion() < 1 {
+		return results, errors.NotImplementedf("ActionOperations")
+	}
+	err := c.facade.FacadeCall("ActionOperations", arg, &results)
+	return results, err
+}
+
 // servicesCharmActions is a batched query for the charm.Actions for a slice
 // of services by Entity.
 func (c *Client) servicesCharmActions(arg params.Entities) (params.ServicesCharmActionsResults, error) {
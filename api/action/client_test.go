@@ -12,6 +12,7 @@ import (
 	"gopkg.in/juju/charm.v6-unstable"
 
 	"github.com/juju/juju/api/action"
+	basetesting "github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/apiserver/params"
 )
 
@@ -132,3 +133,29 @@ func patchServiceCharmActions(c *gc.C, apiCli *action.Client, patchResults []par
 		},
 	)
 }
+
+func (s *actionSuite) TestSchedulesNotSupported(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Errorf("unexpected call to %s", request)
+		return nil
+	})
+	client := action.NewClient(apiCaller)
+	_, err := client.Schedule(params.ScheduleActions{})
+	c.Check(err, gc.ErrorMatches, "Schedule not implemented")
+	_, err = client.ListSchedules(params.Entities{})
+	c.Check(err, gc.ErrorMatches, "ListSchedules not implemented")
+	_, err = client.Unschedule(params.ScheduledActionIds{})
+	c.Check(err, gc.ErrorMatches, "Unschedule not implemented")
+}
+
+func (s *actionSuite) TestOperationsNotSupported(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Errorf("unexpected call to %s", request)
+		return nil
+	})
+	client := action.NewClient(apiCaller)
+	_, err := client.EnqueueOperations(params.ServiceActions{})
+	c.Check(err, gc.ErrorMatches, "EnqueueOperations not implemented")
+	_, err = client.ActionOperations(params.ActionOperationIds{})
+	c.Check(err, gc.ErrorMatches, "ActionOperations not implemented")
+}
@@ -0,0 +1,25 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionscheduler
+
+import (
+	"github.com/juju/juju/api/base"
+)
+
+const apiName = "ActionScheduler"
+
+// Facade allows calls to "ActionScheduler" endpoints.
+type Facade struct {
+	facade base.FacadeCaller
+}
+
+// NewFacade returns an "ActionScheduler" Facade.
+func NewFacade(caller base.APICaller) *Facade {
+	return &Facade{base.NewFacadeCaller(caller, apiName)}
+}
+
+// RunDueScheduledActions calls "ActionScheduler.RunDueScheduledActions".
+func (f *Facade) RunDueScheduledActions() error {
+	return f.facade.FacadeCall("RunDueScheduledActions", nil, nil)
+}
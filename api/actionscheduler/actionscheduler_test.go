@@ -0,0 +1,45 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionscheduler_test
+
+import (
+	"errors"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/actionscheduler"
+	basetesting "github.com/juju/juju/api/base/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type actionSchedulerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&actionSchedulerSuite{})
+
+func (s *actionSchedulerSuite) TestRunDueScheduledActions(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "ActionScheduler")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "RunDueScheduledActions")
+		c.Check(arg, gc.IsNil)
+		c.Check(result, gc.IsNil)
+		called = true
+		return nil
+	})
+	err := actionscheduler.NewFacade(apiCaller).RunDueScheduledActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *actionSchedulerSuite) TestRunDueScheduledActionsError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("boom")
+	})
+	err := actionscheduler.NewFacade(apiCaller).RunDueScheduledActions()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -36,8 +37,17 @@ type Client struct {
 	st     *state
 }
 
-// Status returns the status of the juju environment.
+// Status returns the status of the juju environment. Patterns may
+// include structured filter terms such as "workload=blocked", which
+// are only supported by version 1 of the Client facade.
 func (c *Client) Status(patterns []string) (*params.FullStatus, error) {
+	if c.BestAPIVersion() < 1 {
+		for _, pattern := range patterns {
+			if strings.ContainsAny(pattern, "=<>") {
+				return nil, errors.NewNotSupported(nil, fmt.Sprintf("status filter %q not supported by this API server", pattern))
+			}
+		}
+	}
 	var result params.FullStatus
 	p := params.StatusParams{Patterns: patterns}
 	if err := c.facade.FacadeCall("FullStatus", p, &result); err != nil {
@@ -65,6 +75,92 @@ func (c *Client) UnitStatusHistory(kind params.HistoryKind, unitName string, siz
 	return &results, nil
 }
 
+// EnvironStatusHistory retrieves the most recent status history entries
+// of all the machines, services and units in the environment, oldest
+// first, restricted as described by args.
+func (c *Client) EnvironStatusHistory(args params.EnvironStatusHistoryArgs) ([]params.EntityStatusHistory, error) {
+	if c.BestAPIVersion() < 1 {
+		return nil, errors.NotImplementedf("EnvironStatusHistory")
+	}
+	var result params.EnvironStatusHistoryResult
+	err := c.facade.FacadeCall("EnvironStatusHistory", args, &result)
+	if err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return nil, errors.NotImplementedf("EnvironStatusHistory")
+		}
+		return nil, errors.Trace(err)
+	}
+	return result.Statuses, nil
+}
+
+// LogArchives returns the archives of pruned logs in the environment
+// which hold records from the given time range. Either end of the
+// range may be nil.
+func (c *Client) LogArchives(from, to *time.Time) ([]params.LogArchive, error) {
+	if c.BestAPIVersion() < 1 {
+		return nil, errors.NotImplementedf("LogArchives")
+	}
+	var result params.LogArchivesResult
+	args := params.LogArchivesArgs{From: from, To: to}
+	err := c.facade.FacadeCall("LogArchives", args, &result)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Archives, nil
+}
+
+// DownloadLogArchive returns a reader for the gzip-compressed contents
+// of the log archive with the given id. The reader must be closed
+// after use.
+func (c *Client) DownloadLogArchive(id string) (io.ReadCloser, error) {
+	req, err := http.NewRequest("GET", "/logarchive?id="+url.QueryEscape(id), nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot create download request")
+	}
+	httpClient, err := c.st.HTTPClient()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var resp *http.Response
+	if err := httpClient.Do(req, nil, &resp); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return resp.Body, nil
+}
+
+// RemoveLogArchive removes the log archive with the given id.
+func (c *Client) RemoveLogArchive(id string) error {
+	req, err := http.NewRequest("DELETE", "/logarchive?id="+url.QueryEscape(id), nil)
+	if err != nil {
+		return errors.Annotate(err, "cannot create remove request")
+	}
+	httpClient, err := c.st.HTTPClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(httpClient.Do(req, nil, nil))
+}
+
+// ImportLogArchive adds the records in the given gzip-compressed log
+// archive to the logs of the environment, returning the number of
+// records added.
+func (c *Client) ImportLogArchive(r io.ReadSeeker) (int, error) {
+	req, err := http.NewRequest("POST", "/logarchive", nil)
+	if err != nil {
+		return 0, errors.Annotate(err, "cannot create import request")
+	}
+	req.Header.Set("Content-Type", params.ContentTypeGzip)
+	httpClient, err := c.st.HTTPClient()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	var resp params.LogArchiveImportResult
+	if err := httpClient.Do(req, r, &resp); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return resp.Count, nil
+}
+
 // LegacyStatus is a stub version of Status that 1.16 introduced. Should be
 // removed along with structs when api versioning makes it safe to do so.
 func (c *Client) LegacyStatus() (*params.LegacyStatus, error) {
@@ -276,6 +372,25 @@ func (c *Client) AddServiceUnitsWithPlacement(service string, numUnits int, plac
 	return results.Units, err
 }
 
+// AddServiceUnitsAttachingStorage adds a unit to a service, attaching
+// the specified storage instances, which must have been detached from
+// other units of the service. The placement directives, if any, are
+// used to assign the unit to a machine.
+func (c *Client) AddServiceUnitsAttachingStorage(service string, placement []*instance.Placement, storage []string) ([]string, error) {
+	if c.BestAPIVersion() < 1 {
+		return nil, errors.NotImplementedf("AddServiceUnitsAttachingStorage")
+	}
+	args := params.AddServiceUnits{
+		ServiceName:   service,
+		NumUnits:      1,
+		Placement:     placement,
+		AttachStorage: storage,
+	}
+	results := new(params.AddServiceUnitsResults)
+	err := c.facade.FacadeCall("AddServiceUnitsAttachingStorage", args, results)
+	return results.Units, err
+}
+
 // DestroyServiceUnits decreases the number of units dedicated to a service.
 func (c *Client) DestroyServiceUnits(unitNames ...string) error {
 	params := params.DestroyServiceUnits{unitNames}
@@ -789,17 +904,43 @@ type DebugLogParams struct {
 	// Replay tells the server to start at the start of the log file rather
 	// than the end. If replay is true, backlog is ignored.
 	Replay bool
+	// StartTime, if not zero, excludes log messages logged before it.
+	StartTime time.Time
+	// EndTime, if not zero, excludes log messages logged after it. Once
+	// that time has passed and all the matching messages logged up to
+	// it have been sent, the socket is closed.
+	EndTime time.Time
+	// MessageRegex, if set, excludes log messages which do not match the
+	// regular expression. It uses Go regexp syntax, and is evaluated by
+	// the server.
+	MessageRegex string
+	// Labels, if set, excludes log messages which do not carry all of
+	// the given labels with the given values, such as "hook": "install".
+	Labels map[string]string
+	// JSON tells the server to send each log message as a line holding
+	// a JSON-encoded params.DebugLogRecord, rather than as text.
+	JSON bool
 }
 
 // WatchDebugLog returns a ReadCloser that the caller can read the log
 // lines from. Only log lines that match the filtering specified in
 // the DebugLogParams are returned. It returns an error that satisfies
 // errors.IsNotImplemented when the API server does not support the
-// end-point.
+// end-point, and one that satisfies errors.IsNotSupported when the
+// time range, message regex or JSON output are requested from an API
+// server that does not support them.
 //
 // TODO(dimitern) We already have errors.IsNotImplemented - why do we
 // need to define a different error for this purpose here?
 func (c *Client) WatchDebugLog(args DebugLogParams) (io.ReadCloser, error) {
+	// Older API servers ignore the parameters they do not know about,
+	// so refuse to send them rather than return unfiltered logs. They
+	// were added along with version 1 of the Client facade.
+	if c.BestAPIVersion() < 1 {
+		if err := checkDebugLogParamsV0(args); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	// The websocket connection just hangs if the server doesn't have the log
 	// end point. So do a version check, as version was added at the same time
 	// as the remote end point.
@@ -826,6 +967,28 @@ func (c *Client) WatchDebugLog(args DebugLogParams) (io.ReadCloser, error) {
 	if args.Level != loggo.UNSPECIFIED {
 		attrs.Set("level", fmt.Sprint(args.Level))
 	}
+	if !args.StartTime.IsZero() {
+		attrs.Set("startTime", args.StartTime.UTC().Format(time.RFC3339Nano))
+	}
+	if !args.EndTime.IsZero() {
+		attrs.Set("endTime", args.EndTime.UTC().Format(time.RFC3339Nano))
+	}
+	if args.MessageRegex != "" {
+		attrs.Set("messageRegex", args.MessageRegex)
+	}
+	if len(args.Labels) > 0 {
+		labelNames := make([]string, 0, len(args.Labels))
+		for name := range args.Labels {
+			labelNames = append(labelNames, name)
+		}
+		sort.Strings(labelNames)
+		for _, name := range labelNames {
+			attrs.Add("label", name+"="+args.Labels[name])
+		}
+	}
+	if args.JSON {
+		attrs.Set("format", "json")
+	}
 
 	connection, err := c.st.ConnectStream("/log", attrs)
 	if err != nil {
@@ -833,3 +996,23 @@ func (c *Client) WatchDebugLog(args DebugLogParams) (io.ReadCloser, error) {
 	}
 	return connection, nil
 }
+
+// checkDebugLogParamsV0 returns an error if the params use any
+// debug-log filters or formats that are not supported by API servers
+// without version 1 of the Client facade.
+func checkDebugLogParamsV0(args DebugLogParams) error {
+	var unsupported string
+	switch {
+	case !args.StartTime.IsZero() || !args.EndTime.IsZero():
+		unsupported = "debug-log time range"
+	case args.MessageRegex != "":
+		unsupported = "debug-log message regex"
+	case args.JSON:
+		unsupported = "debug-log JSON output"
+	case len(args.Labels) > 0:
+		unsupported = "debug-log labels"
+	default:
+		return nil
+	}
+	return errors.NewNotSupported(nil, unsupported+" not supported by this API server")
+}
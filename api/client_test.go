@@ -14,6 +14,7 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/httprequest"
@@ -193,6 +194,59 @@ func envEndpoint(c *gc.C, apiState api.Connection, destination string) string {
 	return path.Join("/environment", envTag.Id(), destination)
 }
 
+func (s *clientSuite) TestLogArchives(c *gc.C) {
+	client := s.APIState.Client()
+	from := time.Date(2015, 10, 21, 16, 0, 0, 0, time.UTC)
+	archive := params.LogArchive{Id: "a", Start: from, End: from.Add(time.Hour), Count: 2, Size: 10}
+	cleanup := api.PatchClientFacadeCall(client,
+		func(request string, paramsIn interface{}, response interface{}) error {
+			c.Assert(request, gc.Equals, "LogArchives")
+			c.Assert(paramsIn, jc.DeepEquals, params.LogArchivesArgs{From: &from})
+			response.(*params.LogArchivesResult).Archives = []params.LogArchive{archive}
+			return nil
+		},
+	)
+	defer cleanup()
+
+	archives, err := client.LogArchives(&from, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(archives, jc.DeepEquals, []params.LogArchive{archive})
+}
+
+func (s *clientSuite) TestLogArchivesNotImplemented(c *gc.C) {
+	st := api.NewTestingState(api.TestingStateParams{
+		FacadeVersions: map[string][]int{
+			"Client": {0},
+		},
+	})
+	_, err := st.Client().LogArchives(nil, nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
+func (s *clientSuite) TestImportLogArchive(c *gc.C) {
+	client := s.APIState.Client()
+	var called bool
+
+	// ImportLogArchive does not use the facades, so instead of
+	// patching the facade call, we set up a fake endpoint to test.
+	defer fakeAPIEndpoint(c, client, envEndpoint(c, s.APIState, "logarchive"), "POST",
+		func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			c.Check(r.Header.Get("Content-Type"), gc.Equals, params.ContentTypeGzip)
+			defer r.Body.Close()
+			data, err := ioutil.ReadAll(r.Body)
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(string(data), gc.Equals, "archive")
+			httprequest.WriteJSON(w, http.StatusOK, &params.LogArchiveImportResult{Count: 3})
+		},
+	).Close()
+
+	count, err := client.ImportLogArchive(strings.NewReader("archive"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(count, gc.Equals, 3)
+}
+
 func (s *clientSuite) TestClientEnvironmentUUID(c *gc.C) {
 	environ, err := s.State.Environment()
 	c.Assert(err, jc.ErrorIsNil)
@@ -445,6 +499,11 @@ func (s *clientSuite) TestWatchDebugLogParamsEncoded(c *gc.C) {
 		Backlog:       200,
 		Level:         loggo.ERROR,
 		Replay:        true,
+		StartTime:     time.Date(2015, 6, 19, 15, 0, 0, 0, time.UTC),
+		EndTime:       time.Date(2015, 6, 19, 16, 30, 0, 500, time.UTC),
+		MessageRegex:  "hook fail",
+		Labels:        map[string]string{"relation": "1", "hook": "db-relation-joined"},
+		JSON:          true,
 	}
 
 	client := s.APIState.Client()
@@ -462,7 +521,46 @@ func (s *clientSuite) TestWatchDebugLogParamsEncoded(c *gc.C) {
 		"backlog":       {"200"},
 		"level":         {"ERROR"},
 		"replay":        {"true"},
+		"startTime":     {"2015-06-19T15:00:00Z"},
+		"endTime":       {"2015-06-19T16:30:00.0000005Z"},
+		"messageRegex":  {"hook fail"},
+		"label":         {"hook=db-relation-joined", "relation=1"},
+		"format":        {"json"},
+	})
+}
+
+func (s *clientSuite) TestWatchDebugLogParamsNotSupported(c *gc.C) {
+	st := api.NewTestingState(api.TestingStateParams{
+		FacadeVersions: map[string][]int{
+			"Client": {0},
+		},
 	})
+	client := st.Client()
+	for i, test := range []struct {
+		params api.DebugLogParams
+		err    string
+	}{{
+		params: api.DebugLogParams{StartTime: time.Now()},
+		err:    "debug-log time range not supported by this API server",
+	}, {
+		params: api.DebugLogParams{EndTime: time.Now()},
+		err:    "debug-log time range not supported by this API server",
+	}, {
+		params: api.DebugLogParams{MessageRegex: "hook fail"},
+		err:    "debug-log message regex not supported by this API server",
+	}, {
+		params: api.DebugLogParams{JSON: true},
+		err:    "debug-log JSON output not supported by this API server",
+	}, {
+		params: api.DebugLogParams{Labels: map[string]string{"hook": "install"}},
+		err:    "debug-log labels not supported by this API server",
+	}} {
+		c.Logf("test %d", i)
+		reader, err := client.WatchDebugLog(test.params)
+		c.Check(err, gc.ErrorMatches, test.err)
+		c.Check(err, jc.Satisfies, errors.IsNotSupported)
+		c.Check(reader, gc.IsNil)
+	}
 }
 
 func (s *clientSuite) TestConnectStreamRootPath(c *gc.C) {
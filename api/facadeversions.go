@@ -11,7 +11,8 @@ package api
 // New facades should start at 1.
 // Facades that existed before versioning start at 0.
 var facadeVersions = map[string]int{
-	"Action":                       0,
+	"Action":                       1,
+	"ActionScheduler":              1,
 	"Addresser":                    1,
 	"Agent":                        1,
 	"AllWatcher":                   0,
@@ -21,7 +22,7 @@ var facadeVersions = map[string]int{
 	"Block":                        1,
 	"Charms":                       1,
 	"CharmRevisionUpdater":         0,
-	"Client":                       0,
+	"Client":                       1,
 	"Cleaner":                      1,
 	"Deployer":                     0,
 	"DiskManager":                  1,
@@ -36,13 +37,16 @@ var facadeVersions = map[string]int{
 	"InstancePoller":               1,
 	"KeyManager":                   0,
 	"KeyUpdater":                   0,
+	"LeadershipAdmin":              1,
 	"LeadershipService":            1,
-	"Logger":                       0,
-	"MachineManager":               1,
+	"Logger":                       1,
+	"MachineManager":               2,
+	"MachineDrainer":               1,
 	"Machiner":                     0,
-	"MetricsManager":               0,
+	"MetricsManager":               1,
 	"MeterStatus":                  1,
 	"MetricsAdder":                 1,
+	"MetricsDebug":                 1,
 	"Networker":                    0,
 	"NotifyWatcher":                0,
 	"Pinger":                       0,
@@ -52,18 +56,20 @@ var facadeVersions = map[string]int{
 	"Resumer":                      1,
 	"Rsyslog":                      0,
 	"Service":                      2,
-	"Storage":                      1,
+	"Storage":                      2,
 	"Spaces":                       1,
 	"Subnets":                      1,
 	"StatusHistory":                1,
-	"StorageProvisioner":           1,
+	"StorageProvisioner":           2,
 	"StringsWatcher":               0,
 	"SystemManager":                1,
 	"Upgrader":                     0,
 	"UnitAssigner":                 1,
-	"Uniter":                       2,
+	"Uniter":                       3,
 	"UserManager":                  0,
 	"VolumeAttachmentsWatcher":     1,
+	"WebhookDispatcher":            1,
+	"Webhooks":                     1,
 }
 
 // bestVersion tries to find the newest version in the version list that we can
@@ -0,0 +1,95 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package leadershipadmin provides a client for inspecting service
+// leadership and forcibly revoking it.
+package leadershipadmin
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the leadership admin API.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the leadership admin API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "LeadershipAdmin")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Leases returns the current leadership of every service that has a
+// leader.
+func (c *Client) Leases() ([]params.LeadershipLease, error) {
+	var result params.LeadershipLeasesResult
+	if err := c.facade.FacadeCall("Leases", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Leases, nil
+}
+
+// RevokeLeadership forcibly ends the leadership of the named services,
+// so that other units can take over.
+func (c *Client) RevokeLeadership(serviceNames []string) ([]params.ErrorResult, error) {
+	args := params.Entities{Entities: make([]params.Entity, len(serviceNames))}
+	for i, serviceName := range serviceNames {
+		if !names.IsValidService(serviceName) {
+			return nil, errors.NotValidf("service name %q", serviceName)
+		}
+		args.Entities[i].Tag = names.NewServiceTag(serviceName).String()
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("RevokeLeadership", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(serviceNames) {
+		return nil, errors.Errorf("expected %d results, got %d", len(serviceNames), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// LeadershipHistory returns at most size of the most recent leadership
+// changes of the named service, oldest first.
+func (c *Client) LeadershipHistory(serviceName string, size int) ([]params.LeadershipChange, error) {
+	if !names.IsValidService(serviceName) {
+		return nil, errors.NotValidf("service name %q", serviceName)
+	}
+	args := params.LeadershipHistoryQueries{
+		Queries: []params.LeadershipHistoryQuery{{
+			ServiceTag: names.NewServiceTag(serviceName).String(),
+			Size:       size,
+		}},
+	}
+	var results params.LeadershipHistoryResults
+	if err := c.facade.FacadeCall("LeadershipHistory", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, err
+	}
+	return results.Results[0].Changes, nil
+}
+
+// WatchLeadershipChanges returns a StringsWatcher that notifies of the
+// names of services whose leader changes. The initial event is empty.
+func (c *Client) WatchLeadershipChanges() (watcher.StringsWatcher, error) {
+	var result params.StringsWatchResult
+	if err := c.facade.FacadeCall("WatchLeadershipChanges", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return watcher.NewStringsWatcher(c.facade.RawAPICaller(), result), nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leadershipadmin_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/leadershipadmin"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type leadershipAdminSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&leadershipAdminSuite{})
+
+func (s *leadershipAdminSuite) TestLeases(c *gc.C) {
+	expiry := time.Date(2015, 10, 1, 0, 0, 0, 0, time.UTC)
+	leases := []params.LeadershipLease{{
+		Namespace:  "service-leadership",
+		ServiceTag: "service-mysql",
+		UnitTag:    "unit-mysql-0",
+		Expiry:     expiry,
+	}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "LeadershipAdmin")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Leases")
+			c.Check(a, gc.IsNil)
+			if result, ok := result.(*params.LeadershipLeasesResult); ok {
+				result.Leases = leases
+			}
+			return nil
+		})
+	client := leadershipadmin.NewClient(apiCaller)
+	result, err := client.Leases()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, leases)
+}
+
+func (s *leadershipAdminSuite) TestRevokeLeadership(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "LeadershipAdmin")
+			c.Check(request, gc.Equals, "RevokeLeadership")
+			c.Check(a, jc.DeepEquals, params.Entities{Entities: []params.Entity{
+				{Tag: "service-mysql"},
+				{Tag: "service-wordpress"},
+			}})
+			if results, ok := result.(*params.ErrorResults); ok {
+				results.Results = []params.ErrorResult{{}, {Error: common.ServerError(common.ErrPerm)}}
+			}
+			return nil
+		})
+	client := leadershipadmin.NewClient(apiCaller)
+	results, err := client.RevokeLeadership([]string{"mysql", "wordpress"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Assert(results[1].Error, gc.ErrorMatches, "permission denied")
+}
+
+func (s *leadershipAdminSuite) TestRevokeLeadershipInvalidService(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		})
+	client := leadershipadmin.NewClient(apiCaller)
+	_, err := client.RevokeLeadership([]string{"mysql/0"})
+	c.Assert(err, gc.ErrorMatches, `service name "mysql/0" not valid`)
+}
+
+func (s *leadershipAdminSuite) TestLeadershipHistory(c *gc.C) {
+	changes := []params.LeadershipChange{{
+		ServiceTag: "service-mysql",
+		Previous:   "unit-mysql-0",
+		Leader:     "unit-mysql-1",
+		Time:       time.Date(2015, 10, 1, 0, 0, 0, 0, time.UTC),
+	}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "LeadershipAdmin")
+			c.Check(request, gc.Equals, "LeadershipHistory")
+			c.Check(a, jc.DeepEquals, params.LeadershipHistoryQueries{
+				Queries: []params.LeadershipHistoryQuery{{ServiceTag: "service-mysql", Size: 5}},
+			})
+			if results, ok := result.(*params.LeadershipHistoryResults); ok {
+				results.Results = []params.LeadershipHistoryResult{{Changes: changes}}
+			}
+			return nil
+		})
+	client := leadershipadmin.NewClient(apiCaller)
+	result, err := client.LeadershipHistory("mysql", 5)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, changes)
+}
+
+func (s *leadershipAdminSuite) TestLeadershipHistoryError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			if results, ok := result.(*params.LeadershipHistoryResults); ok {
+				results.Results = []params.LeadershipHistoryResult{{
+					Error: common.ServerError(common.ErrPerm),
+				}}
+			}
+			return nil
+		})
+	client := leadershipadmin.NewClient(apiCaller)
+	_, err := client.LeadershipHistory("mysql", 5)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *leadershipAdminSuite) TestWatchLeadershipChangesError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "LeadershipAdmin")
+			c.Check(request, gc.Equals, "WatchLeadershipChanges")
+			c.Check(a, gc.IsNil)
+			if result, ok := result.(*params.StringsWatchResult); ok {
+				result.Error = common.ServerError(common.ErrPerm)
+			}
+			return nil
+		})
+	client := leadershipadmin.NewClient(apiCaller)
+	_, err := client.WatchLeadershipChanges()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
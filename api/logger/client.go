@@ -0,0 +1,78 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logger
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the logger API for managing the logging
+// overrides of individual agents. Overrides are managed through
+// version 1 of the Logger facade.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for managing logging overrides.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Logger")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// SetLoggingOverride sets logging configuration for the agent of the
+// machine or unit with the given tag, on top of the environment's
+// logging-config, until the given expiry time.
+func (c *Client) SetLoggingOverride(tag names.Tag, config string, expires time.Time) error {
+	args := params.LoggingOverrides{
+		Overrides: []params.LoggingOverride{{
+			Tag:     tag.String(),
+			Config:  config,
+			Expires: expires,
+		}},
+	}
+	if c.BestAPIVersion() < 1 {
+		return errors.NotImplementedf("SetLoggingOverrides")
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetLoggingOverrides", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// RemoveLoggingOverride removes the logging override of the agent with
+// the given tag before it expires.
+func (c *Client) RemoveLoggingOverride(tag names.Tag) error {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: tag.String()}},
+	}
+	if c.BestAPIVersion() < 1 {
+		return errors.NotImplementedf("RemoveLoggingOverrides")
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("RemoveLoggingOverrides", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// LoggingOverrides returns the logging overrides in the environment
+// that have not yet expired.
+func (c *Client) LoggingOverrides() ([]params.LoggingOverride, error) {
+	if c.BestAPIVersion() < 1 {
+		return nil, errors.NotImplementedf("LoggingOverrides")
+	}
+	var result params.LoggingOverrides
+	if err := c.facade.FacadeCall("LoggingOverrides", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Overrides, nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logger_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/logger"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+// loggerV1Caller is an API caller supporting version 1 of the Logger
+// facade, which manages logging overrides.
+type loggerV1Caller struct {
+	basetesting.APICallerFunc
+}
+
+func (loggerV1Caller) BestFacadeVersion(facade string) int {
+	return 1
+}
+
+func (s *clientSuite) TestSetLoggingOverride(c *gc.C) {
+	expires := time.Date(2015, 10, 1, 0, 0, 0, 0, time.UTC)
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Logger")
+			c.Check(version, gc.Equals, 1)
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "SetLoggingOverrides")
+			c.Check(a, jc.DeepEquals, params.LoggingOverrides{
+				Overrides: []params.LoggingOverride{{
+					Tag:     "unit-mysql-0",
+					Config:  "juju.worker.uniter=TRACE",
+					Expires: expires,
+				}},
+			})
+			if result, ok := result.(*params.ErrorResults); ok {
+				result.Results = []params.ErrorResult{{
+					Error: &params.Error{Message: "boom"},
+				}}
+			}
+			return nil
+		})
+	client := logger.NewClient(loggerV1Caller{apiCaller})
+	err := client.SetLoggingOverride(names.NewUnitTag("mysql/0"), "juju.worker.uniter=TRACE", expires)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *clientSuite) TestRemoveLoggingOverride(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Logger")
+			c.Check(request, gc.Equals, "RemoveLoggingOverrides")
+			c.Check(a, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "machine-0"}},
+			})
+			if result, ok := result.(*params.ErrorResults); ok {
+				result.Results = []params.ErrorResult{{}}
+			}
+			return nil
+		})
+	client := logger.NewClient(loggerV1Caller{apiCaller})
+	err := client.RemoveLoggingOverride(names.NewMachineTag("0"))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *clientSuite) TestLoggingOverrides(c *gc.C) {
+	overrides := []params.LoggingOverride{{
+		Tag:     "machine-0",
+		Config:  "juju=DEBUG",
+		Expires: time.Date(2015, 10, 1, 0, 0, 0, 0, time.UTC),
+	}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Logger")
+			c.Check(request, gc.Equals, "LoggingOverrides")
+			c.Check(a, gc.IsNil)
+			if result, ok := result.(*params.LoggingOverrides); ok {
+				result.Overrides = overrides
+			}
+			return nil
+		})
+	client := logger.NewClient(loggerV1Caller{apiCaller})
+	result, err := client.LoggingOverrides()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, overrides)
+}
+
+func (s *clientSuite) TestLoggingOverridesNotImplemented(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Errorf("unexpected call to %s.%s", objType, request)
+			return nil
+		})
+	client := logger.NewClient(apiCaller)
+	err := client.SetLoggingOverride(names.NewMachineTag("0"), "juju=DEBUG", time.Now())
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	err = client.RemoveLoggingOverride(names.NewMachineTag("0"))
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	_, err = client.LoggingOverrides()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
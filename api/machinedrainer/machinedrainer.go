@@ -0,0 +1,31 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinedrainer
+
+import (
+	"time"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+const apiName = "MachineDrainer"
+
+// Facade allows calls to "MachineDrainer" endpoints.
+type Facade struct {
+	facade base.FacadeCaller
+}
+
+// NewFacade returns a "MachineDrainer" Facade.
+func NewFacade(caller base.APICaller) *Facade {
+	return &Facade{base.NewFacadeCaller(caller, apiName)}
+}
+
+// AdvanceMachineDrains calls "MachineDrainer.AdvanceMachineDrains".
+func (f *Facade) AdvanceMachineDrains(replacementTimeout time.Duration) error {
+	args := params.AdvanceMachineDrainsArgs{
+		ReplacementTimeout: replacementTimeout,
+	}
+	return f.facade.FacadeCall("AdvanceMachineDrains", args, nil)
+}
@@ -0,0 +1,49 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinedrainer_test
+
+import (
+	"errors"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/machinedrainer"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type machineDrainerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&machineDrainerSuite{})
+
+func (s *machineDrainerSuite) TestAdvanceMachineDrains(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MachineDrainer")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "AdvanceMachineDrains")
+		c.Check(arg, jc.DeepEquals, params.AdvanceMachineDrainsArgs{
+			ReplacementTimeout: time.Minute,
+		})
+		c.Check(result, gc.IsNil)
+		called = true
+		return nil
+	})
+	err := machinedrainer.NewFacade(apiCaller).AdvanceMachineDrains(time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *machineDrainerSuite) TestAdvanceMachineDrainsError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("boom")
+	})
+	err := machinedrainer.NewFacade(apiCaller).AdvanceMachineDrains(time.Minute)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -5,6 +5,7 @@ package machinemanager
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
@@ -36,3 +37,55 @@ func (client *Client) AddMachines(machineParams []params.AddMachineParams) ([]pa
 	}
 	return results.Machines, err
 }
+
+// DrainMachines marks the given machines as unschedulable and starts
+// evacuating their units onto other machines.
+func (client *Client) DrainMachines(machines ...string) ([]params.ErrorResult, error) {
+	return client.machinesCall("DrainMachines", machines)
+}
+
+// UndrainMachines allows the given machines to accept new units again,
+// abandoning any drains in progress.
+func (client *Client) UndrainMachines(machines ...string) ([]params.ErrorResult, error) {
+	return client.machinesCall("UndrainMachines", machines)
+}
+
+func (client *Client) machinesCall(method string, machines []string) ([]params.ErrorResult, error) {
+	if client.BestAPIVersion() < 2 {
+		return nil, errors.NotImplementedf("%s", method)
+	}
+	args := machineEntities(machines)
+	results := new(params.ErrorResults)
+	if err := client.facade.FacadeCall(method, args, results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(machines) {
+		return nil, errors.Errorf("expected %d result, got %d", len(machines), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// MachineDrains returns the progress of the most recent drain of each
+// of the given machines.
+func (client *Client) MachineDrains(machines ...string) ([]params.MachineDrainResult, error) {
+	if client.BestAPIVersion() < 2 {
+		return nil, errors.NotImplementedf("MachineDrains")
+	}
+	args := machineEntities(machines)
+	results := new(params.MachineDrainResults)
+	if err := client.facade.FacadeCall("MachineDrains", args, results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(machines) {
+		return nil, errors.Errorf("expected %d result, got %d", len(machines), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+func machineEntities(machines []string) params.Entities {
+	args := params.Entities{Entities: make([]params.Entity, len(machines))}
+	for i, id := range machines {
+		args.Entities[i].Tag = names.NewMachineTag(id).String()
+	}
+	return args
+}
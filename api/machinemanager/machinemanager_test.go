@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 
+	jujuerrors "github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -23,6 +24,16 @@ type MachinemanagerSuite struct {
 	coretesting.BaseSuite
 }
 
+// machineManagerV2Caller reports that the server supports version 2
+// of the MachineManager facade, which adds machine drains.
+type machineManagerV2Caller struct {
+	testing.APICallerFunc
+}
+
+func (machineManagerV2Caller) BestFacadeVersion(facade string) int {
+	return 2
+}
+
 func (s *MachinemanagerSuite) TestAddMachines(c *gc.C) {
 	apiResult := []params.AddMachinesResult{
 		{Machine: "machine-1", Error: nil},
@@ -116,3 +127,68 @@ func (s *MachinemanagerSuite) TestAddMachinesResultCountInvalid(c *gc.C) {
 		c.Check(err, gc.ErrorMatches, fmt.Sprintf("expected 1 result, got %d", n))
 	}
 }
+
+func (s *MachinemanagerSuite) TestDrainMachines(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MachineManager")
+		c.Check(request, gc.Equals, "DrainMachines")
+		c.Check(arg, gc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "machine-1"}, {Tag: "machine-2"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}, {Error: &params.Error{Message: "boom"}}},
+		}
+		return nil
+	})
+	st := machinemanager.NewClient(machineManagerV2Caller{apiCaller})
+	results, err := st.DrainMachines("1", "2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Assert(results[1].Error, gc.ErrorMatches, "boom")
+}
+
+func (s *MachinemanagerSuite) TestUndrainMachinesResultCountMismatch(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "UndrainMachines")
+		return nil
+	})
+	st := machinemanager.NewClient(machineManagerV2Caller{apiCaller})
+	_, err := st.UndrainMachines("1")
+	c.Assert(err, gc.ErrorMatches, "expected 1 result, got 0")
+}
+
+func (s *MachinemanagerSuite) TestMachineDrains(c *gc.C) {
+	apiResult := []params.MachineDrainResult{{
+		Result: &params.MachineDrain{MachineId: "1", Status: "pending"},
+	}}
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "MachineDrains")
+		c.Check(arg, gc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "machine-1"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.MachineDrainResults{})
+		*(result.(*params.MachineDrainResults)) = params.MachineDrainResults{Results: apiResult}
+		return nil
+	})
+	st := machinemanager.NewClient(machineManagerV2Caller{apiCaller})
+	results, err := st.MachineDrains("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, apiResult)
+}
+
+func (s *MachinemanagerSuite) TestDrainsNotSupported(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Errorf("unexpected call to %s", request)
+		return nil
+	})
+	st := machinemanager.NewClient(apiCaller)
+	_, err := st.DrainMachines("1")
+	c.Check(err, gc.ErrorMatches, "DrainMachines not implemented")
+	c.Check(err, jc.Satisfies, jujuerrors.IsNotImplemented)
+	_, err = st.UndrainMachines("1")
+	c.Check(err, gc.ErrorMatches, "UndrainMachines not implemented")
+	_, err = st.MachineDrains("1")
+	c.Check(err, gc.ErrorMatches, "MachineDrains not implemented")
+}
@@ -0,0 +1,111 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package metricsdebug contains the implementation of a client to
+// access metrics recorded by units.
+package metricsdebug
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the metrics debug API.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// MetricsDebugClient defines the methods on the metricsdebug API end point.
+type MetricsDebugClient interface {
+	// GetMetrics returns the metrics recorded for a unit or service
+	// since the given time.
+	GetMetrics(tag names.Tag, since time.Time) ([]params.MetricResult, error)
+
+	// AddMetricThresholds adds metric thresholds to the environment.
+	AddMetricThresholds(thresholds []params.MetricThreshold) ([]params.StringResult, error)
+
+	// RemoveMetricThresholds removes metric thresholds from the environment.
+	RemoveMetricThresholds(ids []string) ([]params.ErrorResult, error)
+
+	// MetricThresholds returns the metric thresholds defined in the environment.
+	MetricThresholds() ([]params.MetricThreshold, error)
+
+	// MetricAlerts returns the alerts raised by metric thresholds.
+	MetricAlerts(since time.Time) ([]params.MetricAlert, error)
+}
+
+var _ MetricsDebugClient = (*Client)(nil)
+
+// NewClient creates a new client for accessing the metricsdebug API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "MetricsDebug")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// GetMetrics will receive metrics collected by the given unit or service
+// since the given time. A zero time returns all stored metrics.
+func (c *Client) GetMetrics(tag names.Tag, since time.Time) ([]params.MetricResult, error) {
+	p := params.MetricsQueries{Queries: []params.MetricsQuery{{
+		Tag:   tag.String(),
+		Since: since,
+	}}}
+	results := new(params.MetricResults)
+	if err := c.facade.FacadeCall("GetMetrics", p, results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results[0].Metrics, nil
+}
+
+// AddMetricThresholds adds the given metric thresholds to the
+// environment. The result for each threshold holds its ID, or
+// an error.
+func (c *Client) AddMetricThresholds(thresholds []params.MetricThreshold) ([]params.StringResult, error) {
+	p := params.MetricThresholds{Thresholds: thresholds}
+	results := new(params.StringResults)
+	if err := c.facade.FacadeCall("AddMetricThresholds", p, results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
+// RemoveMetricThresholds removes the metric thresholds with the given IDs.
+func (c *Client) RemoveMetricThresholds(ids []string) ([]params.ErrorResult, error) {
+	p := params.MetricThresholdIds{Ids: ids}
+	results := new(params.ErrorResults)
+	if err := c.facade.FacadeCall("RemoveMetricThresholds", p, results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
+// MetricThresholds returns the metric thresholds defined in the environment.
+func (c *Client) MetricThresholds() ([]params.MetricThreshold, error) {
+	result := new(params.MetricThresholds)
+	if err := c.facade.FacadeCall("MetricThresholds", nil, result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Thresholds, nil
+}
+
+// MetricAlerts returns the alerts raised by metric thresholds since
+// the given time, along with any alerts that are still active.
+func (c *Client) MetricAlerts(since time.Time) ([]params.MetricAlert, error) {
+	p := params.MetricAlertsQuery{Since: since}
+	result := new(params.MetricAlertsResult)
+	if err := c.facade.FacadeCall("MetricAlerts", p, result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Alerts, nil
+}
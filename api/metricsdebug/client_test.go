@@ -0,0 +1,190 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsdebug_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/metricsdebug"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type metricsDebugMockSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&metricsDebugMockSuite{})
+
+func (s *metricsDebugMockSuite) TestGetMetrics(c *gc.C) {
+	since := time.Date(2015, 10, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Now()
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "MetricsDebug")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "GetMetrics")
+			c.Check(a, jc.DeepEquals, params.MetricsQueries{Queries: []params.MetricsQuery{{
+				Tag:   "unit-wordpress-1",
+				Since: since,
+			}}})
+			if results, ok := result.(*params.MetricResults); ok {
+				results.Results = []params.EntityMetrics{{
+					Metrics: []params.MetricResult{{
+						Key:   "pings",
+						Value: "5",
+						Time:  now,
+						Unit:  "wordpress/1",
+					}},
+				}}
+			}
+			return nil
+		})
+	client := metricsdebug.NewClient(apiCaller)
+	metrics, err := client.GetMetrics(names.NewUnitTag("wordpress/1"), since)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metrics, jc.DeepEquals, []params.MetricResult{{
+		Key:   "pings",
+		Value: "5",
+		Time:  now,
+		Unit:  "wordpress/1",
+	}})
+}
+
+func (s *metricsDebugMockSuite) TestGetMetricsEntityError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			if results, ok := result.(*params.MetricResults); ok {
+				results.Results = []params.EntityMetrics{{
+					Error: common.ServerError(common.ErrPerm),
+				}}
+			}
+			return nil
+		})
+	client := metricsdebug.NewClient(apiCaller)
+	_, err := client.GetMetrics(names.NewServiceTag("wordpress"), time.Time{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *metricsDebugMockSuite) TestGetMetricsFacadeCallError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			return common.ServerError(common.ErrPerm)
+		})
+	client := metricsdebug.NewClient(apiCaller)
+	_, err := client.GetMetrics(names.NewServiceTag("wordpress"), time.Time{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *metricsDebugMockSuite) TestAddMetricThresholds(c *gc.C) {
+	thresholds := []params.MetricThreshold{{
+		Service:  "mysql",
+		Key:      "connections",
+		Operator: ">",
+		Value:    500,
+		Duration: 5 * time.Minute,
+		Status:   "AMBER",
+	}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "MetricsDebug")
+			c.Check(request, gc.Equals, "AddMetricThresholds")
+			c.Check(a, jc.DeepEquals, params.MetricThresholds{Thresholds: thresholds})
+			if results, ok := result.(*params.StringResults); ok {
+				results.Results = []params.StringResult{{Result: "0"}}
+			}
+			return nil
+		})
+	client := metricsdebug.NewClient(apiCaller)
+	results, err := client.AddMetricThresholds(thresholds)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.StringResult{{Result: "0"}})
+}
+
+func (s *metricsDebugMockSuite) TestRemoveMetricThresholds(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "MetricsDebug")
+			c.Check(request, gc.Equals, "RemoveMetricThresholds")
+			c.Check(a, jc.DeepEquals, params.MetricThresholdIds{Ids: []string{"0", "1"}})
+			if results, ok := result.(*params.ErrorResults); ok {
+				results.Results = []params.ErrorResult{{}, {Error: common.ServerError(common.ErrPerm)}}
+			}
+			return nil
+		})
+	client := metricsdebug.NewClient(apiCaller)
+	results, err := client.RemoveMetricThresholds([]string{"0", "1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Assert(results[1].Error, gc.ErrorMatches, "permission denied")
+}
+
+func (s *metricsDebugMockSuite) TestMetricThresholds(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "MetricsDebug")
+			c.Check(request, gc.Equals, "MetricThresholds")
+			if results, ok := result.(*params.MetricThresholds); ok {
+				results.Thresholds = []params.MetricThreshold{{Id: "0", Service: "mysql"}}
+			}
+			return nil
+		})
+	client := metricsdebug.NewClient(apiCaller)
+	thresholds, err := client.MetricThresholds()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(thresholds, jc.DeepEquals, []params.MetricThreshold{{Id: "0", Service: "mysql"}})
+}
+
+func (s *metricsDebugMockSuite) TestMetricAlerts(c *gc.C) {
+	since := time.Date(2015, 10, 1, 0, 0, 0, 0, time.UTC)
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "MetricsDebug")
+			c.Check(request, gc.Equals, "MetricAlerts")
+			c.Check(a, jc.DeepEquals, params.MetricAlertsQuery{Since: since})
+			if results, ok := result.(*params.MetricAlertsResult); ok {
+				results.Alerts = []params.MetricAlert{{Id: "alert", UnitTag: "unit-mysql-0"}}
+			}
+			return nil
+		})
+	client := metricsdebug.NewClient(apiCaller)
+	alerts, err := client.MetricAlerts(since)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alerts, jc.DeepEquals, []params.MetricAlert{{Id: "alert", UnitTag: "unit-mysql-0"}})
+}
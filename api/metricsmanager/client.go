@@ -24,6 +24,7 @@ type Client struct {
 type MetricsManagerClient interface {
 	CleanupOldMetrics() error
 	SendMetrics() error
+	EvaluateThresholds() error
 }
 
 var _ MetricsManagerClient = (*Client)(nil)
@@ -68,3 +69,24 @@ func (c *Client) SendMetrics() error {
 	}
 	return results.OneError()
 }
+
+// EvaluateThresholds checks recorded metrics against the metric
+// thresholds defined in the environment.
+func (c *Client) EvaluateThresholds() error {
+	if c.BestAPIVersion() < 1 {
+		return errors.NotImplementedf("EvaluateThresholds")
+	}
+	envTag, err := c.st.EnvironTag()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	p := params.Entities{Entities: []params.Entity{
+		{envTag.String()},
+	}}
+	results := new(params.ErrorResults)
+	err = c.facade.FacadeCall("EvaluateThresholds", p, results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
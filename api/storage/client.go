@@ -114,3 +114,100 @@ func (c *Client) AddToUnit(storages []params.StorageAddParams) ([]params.ErrorRe
 	}
 	return out.Results, nil
 }
+
+// CreateVolumeSnapshots requests snapshots of the volumes assigned to
+// the specified storage instances.
+func (c *Client) CreateVolumeSnapshots(tags []names.StorageTag) ([]params.VolumeSnapshotDetailsResult, error) {
+	if c.BestAPIVersion() < 2 {
+		return nil, errors.NotImplementedf("CreateVolumeSnapshots")
+	}
+	entities := make([]params.Entity, len(tags))
+	for i, tag := range tags {
+		entities[i] = params.Entity{Tag: tag.String()}
+	}
+	out := params.VolumeSnapshotDetailsResults{}
+	if err := c.facade.FacadeCall("CreateVolumeSnapshots", params.Entities{Entities: entities}, &out); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return out.Results, nil
+}
+
+// ListVolumeSnapshots lists the volume snapshots of the specified
+// storage instances. If no storage instances are specified, a list of
+// all volume snapshots is returned.
+func (c *Client) ListVolumeSnapshots(tags []names.StorageTag) ([]params.VolumeSnapshotDetailsResult, error) {
+	if c.BestAPIVersion() < 2 {
+		return nil, errors.NotImplementedf("ListVolumeSnapshots")
+	}
+	storage := make([]string, len(tags))
+	for i, tag := range tags {
+		storage[i] = tag.String()
+	}
+	args := params.VolumeSnapshotFilter{Storage: storage}
+	out := params.VolumeSnapshotDetailsResults{}
+	if err := c.facade.FacadeCall("ListVolumeSnapshots", args, &out); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return out.Results, nil
+}
+
+// DestroyVolumeSnapshots destroys the volume snapshots with the
+// specified IDs.
+func (c *Client) DestroyVolumeSnapshots(ids []string) ([]params.ErrorResult, error) {
+	if c.BestAPIVersion() < 2 {
+		return nil, errors.NotImplementedf("DestroyVolumeSnapshots")
+	}
+	out := params.ErrorResults{}
+	if err := c.facade.FacadeCall("DestroyVolumeSnapshots", params.VolumeSnapshotIds{Ids: ids}, &out); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return out.Results, nil
+}
+
+// RestoreVolumeSnapshots adds storage to units, with volumes created
+// from the specified volume snapshots.
+func (c *Client) RestoreVolumeSnapshots(snapshots []params.VolumeSnapshotRestoreParams) ([]params.ErrorResult, error) {
+	if c.BestAPIVersion() < 2 {
+		return nil, errors.NotImplementedf("RestoreVolumeSnapshots")
+	}
+	out := params.ErrorResults{}
+	in := params.VolumeSnapshotsRestoreParams{Snapshots: snapshots}
+	if err := c.facade.FacadeCall("RestoreVolumeSnapshots", in, &out); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return out.Results, nil
+}
+
+// ResizeStorage requests that the specified storage instances be
+// grown to the specified sizes, in MiB.
+func (c *Client) ResizeStorage(storages []params.StorageResizeArg) ([]params.ErrorResult, error) {
+	out := params.ErrorResults{}
+	in := params.StorageResizeArgs{Storages: storages}
+	if err := c.facade.FacadeCall("ResizeStorage", in, &out); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return out.Results, nil
+}
+
+// DetachStorage detaches the specified storage instances from the units
+// that own them, so that the storage may later be attached to another
+// unit of the same service.
+func (c *Client) DetachStorage(ids []params.StorageAttachmentId) ([]params.ErrorResult, error) {
+	out := params.ErrorResults{}
+	in := params.StorageAttachmentIds{Ids: ids}
+	if err := c.facade.FacadeCall("DetachStorage", in, &out); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return out.Results, nil
+}
+
+// AttachStorage attaches the specified detached storage instances
+// to units of the services that own the storage.
+func (c *Client) AttachStorage(ids []params.StorageAttachmentId) ([]params.ErrorResult, error) {
+	out := params.ErrorResults{}
+	in := params.StorageAttachmentIds{Ids: ids}
+	if err := c.facade.FacadeCall("AttachStorage", in, &out); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return out.Results, nil
+}
@@ -25,6 +25,16 @@ type storageMockSuite struct {
 
 var _ = gc.Suite(&storageMockSuite{})
 
+// storageV2Caller reports that the server supports version 2 of
+// the Storage facade.
+type storageV2Caller struct {
+	basetesting.APICallerFunc
+}
+
+func (storageV2Caller) BestFacadeVersion(facade string) int {
+	return 2
+}
+
 func (s *storageMockSuite) TestShow(c *gc.C) {
 	one := "shared-fs/0"
 	oneTag := names.NewStorageTag(one)
@@ -564,3 +574,188 @@ func (s *storageMockSuite) TestAddToUnitFacadeCallError(c *gc.C) {
 	c.Assert(errors.Cause(err), gc.ErrorMatches, msg)
 	c.Assert(found, gc.HasLen, 0)
 }
+
+func (s *storageMockSuite) TestCreateVolumeSnapshots(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "CreateVolumeSnapshots")
+			c.Assert(a, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{"storage-data-0"}},
+			})
+			if results, ok := result.(*params.VolumeSnapshotDetailsResults); ok {
+				results.Results = []params.VolumeSnapshotDetailsResult{{
+					Result: &params.VolumeSnapshotDetails{Id: "0/0", StorageTag: "storage-data-0"},
+				}}
+			}
+			return nil
+		})
+	storageClient := storage.NewClient(storageV2Caller{apiCaller})
+	found, err := storageClient.CreateVolumeSnapshots([]names.StorageTag{names.NewStorageTag("data/0")})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.DeepEquals, []params.VolumeSnapshotDetailsResult{{
+		Result: &params.VolumeSnapshotDetails{Id: "0/0", StorageTag: "storage-data-0"},
+	}})
+}
+
+func (s *storageMockSuite) TestListVolumeSnapshots(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "ListVolumeSnapshots")
+			c.Assert(a, jc.DeepEquals, params.VolumeSnapshotFilter{
+				Storage: []string{"storage-data-0"},
+			})
+			if results, ok := result.(*params.VolumeSnapshotDetailsResults); ok {
+				results.Results = []params.VolumeSnapshotDetailsResult{{
+					Result: &params.VolumeSnapshotDetails{Id: "0/0", SnapshotId: "snap-0"},
+				}}
+			}
+			return nil
+		})
+	storageClient := storage.NewClient(storageV2Caller{apiCaller})
+	found, err := storageClient.ListVolumeSnapshots([]names.StorageTag{names.NewStorageTag("data/0")})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.DeepEquals, []params.VolumeSnapshotDetailsResult{{
+		Result: &params.VolumeSnapshotDetails{Id: "0/0", SnapshotId: "snap-0"},
+	}})
+}
+
+func (s *storageMockSuite) TestDestroyVolumeSnapshots(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "DestroyVolumeSnapshots")
+			c.Assert(a, jc.DeepEquals, params.VolumeSnapshotIds{Ids: []string{"0/0", "1"}})
+			if results, ok := result.(*params.ErrorResults); ok {
+				results.Results = []params.ErrorResult{{}, {Error: &params.Error{Message: "boom"}}}
+			}
+			return nil
+		})
+	storageClient := storage.NewClient(storageV2Caller{apiCaller})
+	found, err := storageClient.DestroyVolumeSnapshots([]string{"0/0", "1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.DeepEquals, []params.ErrorResult{{}, {Error: &params.Error{Message: "boom"}}})
+}
+
+func (s *storageMockSuite) TestRestoreVolumeSnapshots(c *gc.C) {
+	snapshots := []params.VolumeSnapshotRestoreParams{
+		{Id: "0/0", UnitTag: "unit-mysql-0", StorageName: "data"},
+	}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "RestoreVolumeSnapshots")
+			c.Assert(a, jc.DeepEquals, params.VolumeSnapshotsRestoreParams{Snapshots: snapshots})
+			if results, ok := result.(*params.ErrorResults); ok {
+				results.Results = []params.ErrorResult{{}}
+			}
+			return nil
+		})
+	storageClient := storage.NewClient(storageV2Caller{apiCaller})
+	found, err := storageClient.RestoreVolumeSnapshots(snapshots)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.DeepEquals, []params.ErrorResult{{}})
+}
+
+func (s *storageMockSuite) TestVolumeSnapshotsNotSupported(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Errorf("unexpected call to %s", request)
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	_, err := storageClient.CreateVolumeSnapshots(nil)
+	c.Check(err, gc.ErrorMatches, "CreateVolumeSnapshots not implemented")
+	_, err = storageClient.ListVolumeSnapshots(nil)
+	c.Check(err, gc.ErrorMatches, "ListVolumeSnapshots not implemented")
+	_, err = storageClient.DestroyVolumeSnapshots(nil)
+	c.Check(err, gc.ErrorMatches, "DestroyVolumeSnapshots not implemented")
+	_, err = storageClient.RestoreVolumeSnapshots(nil)
+	c.Check(err, gc.ErrorMatches, "RestoreVolumeSnapshots not implemented")
+}
+
+func (s *storageMockSuite) TestResizeStorage(c *gc.C) {
+	storages := []params.StorageResizeArg{
+		{StorageTag: "storage-data-0", Size: 2048},
+	}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "ResizeStorage")
+			c.Assert(a, jc.DeepEquals, params.StorageResizeArgs{Storages: storages})
+			if results, ok := result.(*params.ErrorResults); ok {
+				results.Results = []params.ErrorResult{{}}
+			}
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	found, err := storageClient.ResizeStorage(storages)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.DeepEquals, []params.ErrorResult{{}})
+}
+
+func (s *storageMockSuite) TestDetachStorage(c *gc.C) {
+	s.assertStorageAttachmentCall(c, "DetachStorage", (*storage.Client).DetachStorage)
+}
+
+func (s *storageMockSuite) TestAttachStorage(c *gc.C) {
+	s.assertStorageAttachmentCall(c, "AttachStorage", (*storage.Client).AttachStorage)
+}
+
+func (s *storageMockSuite) assertStorageAttachmentCall(
+	c *gc.C, method string,
+	call func(*storage.Client, []params.StorageAttachmentId) ([]params.ErrorResult, error),
+) {
+	ids := []params.StorageAttachmentId{
+		{StorageTag: "storage-data-0", UnitTag: "unit-mysql-0"},
+	}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, method)
+			c.Assert(a, jc.DeepEquals, params.StorageAttachmentIds{Ids: ids})
+			if results, ok := result.(*params.ErrorResults); ok {
+				results.Results = []params.ErrorResult{{}}
+			}
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	found, err := call(storageClient, ids)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.DeepEquals, []params.ErrorResult{{}})
+}
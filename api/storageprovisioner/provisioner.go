@@ -93,6 +93,21 @@ func (st *State) WatchFilesystems() (watcher.StringsWatcher, error) {
 	return st.watchStorageEntities("WatchFilesystems")
 }
 
+// WatchVolumeSnapshots watches for lifecycle changes to volume
+// snapshots scoped to the entity with the tag passed to NewState.
+func (st *State) WatchVolumeSnapshots() (watcher.StringsWatcher, error) {
+	if st.facade.BestAPIVersion() < 2 {
+		return nil, errors.NotImplementedf("WatchVolumeSnapshots")
+	}
+	return st.watchStorageEntities("WatchVolumeSnapshots")
+}
+
+// WatchStorageResizes watches for the addition and removal of storage
+// resize requests scoped to the entity with the tag passed to NewState.
+func (st *State) WatchStorageResizes() (watcher.StringsWatcher, error) {
+	return st.watchStorageEntities("WatchStorageResizes")
+}
+
 func (st *State) watchStorageEntities(method string) (watcher.StringsWatcher, error) {
 	var results params.StringsWatchResults
 	args := params.Entities{
@@ -313,6 +328,75 @@ func (st *State) SetVolumeInfo(volumes []params.Volume) ([]params.ErrorResult, e
 	return results.Results, nil
 }
 
+// VolumeSnapshotParams returns the parameters for taking or deleting
+// the volume snapshots with the specified IDs.
+func (st *State) VolumeSnapshotParams(ids []string) ([]params.VolumeSnapshotParamsResult, error) {
+	if st.facade.BestAPIVersion() < 2 {
+		return nil, errors.NotImplementedf("VolumeSnapshotParams")
+	}
+	args := params.VolumeSnapshotIds{Ids: ids}
+	var results params.VolumeSnapshotParamsResults
+	err := st.facade.FacadeCall("VolumeSnapshotParams", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(ids) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(ids), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
+// StorageResizeParams returns the parameters for carrying out the
+// storage resize requests with the specified IDs.
+func (st *State) StorageResizeParams(ids []string) ([]params.StorageResizeParamsResult, error) {
+	args := params.StorageResizeIds{Ids: ids}
+	var results params.StorageResizeParamsResults
+	err := st.facade.FacadeCall("StorageResizeParams", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(ids) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(ids), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
+// SetVolumeSnapshotInfo records the details of newly taken volume
+// snapshots.
+func (st *State) SetVolumeSnapshotInfo(snapshots []params.VolumeSnapshotInfo) ([]params.ErrorResult, error) {
+	if st.facade.BestAPIVersion() < 2 {
+		return nil, errors.NotImplementedf("SetVolumeSnapshotInfo")
+	}
+	args := params.VolumeSnapshotInfos{Snapshots: snapshots}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("SetVolumeSnapshotInfo", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(snapshots) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(snapshots), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
+// SetVolumeSnapshotErrors records that volume snapshots cannot be
+// taken, and why.
+func (st *State) SetVolumeSnapshotErrors(snapshots []params.VolumeSnapshotError) ([]params.ErrorResult, error) {
+	if st.facade.BestAPIVersion() < 2 {
+		return nil, errors.NotImplementedf("SetVolumeSnapshotErrors")
+	}
+	args := params.VolumeSnapshotErrors{Snapshots: snapshots}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("SetVolumeSnapshotErrors", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(snapshots) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(snapshots), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
 // SetFilesystemInfo records the details of newly provisioned filesystems.
 func (st *State) SetFilesystemInfo(filesystems []params.Filesystem) ([]params.ErrorResult, error) {
 	args := params.Filesystems{Filesystems: filesystems}
@@ -423,6 +507,39 @@ func (st *State) Remove(tags []names.Tag) ([]params.ErrorResult, error) {
 	return results.Results, nil
 }
 
+// RemoveVolumeSnapshots removes the volume snapshots with the
+// specified IDs from state.
+func (st *State) RemoveVolumeSnapshots(ids []string) ([]params.ErrorResult, error) {
+	if st.facade.BestAPIVersion() < 2 {
+		return nil, errors.NotImplementedf("RemoveVolumeSnapshots")
+	}
+	args := params.VolumeSnapshotIds{Ids: ids}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("RemoveVolumeSnapshots", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(ids) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(ids), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
+// CompleteStorageResizes records the new sizes of resized storage,
+// and removes the storage resize requests with the specified IDs.
+func (st *State) CompleteStorageResizes(ids []string) ([]params.ErrorResult, error) {
+	args := params.StorageResizeIds{Ids: ids}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("CompleteStorageResizes", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(ids) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(ids), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
 // RemoveAttachments removes the attachments with the specified IDs from state.
 func (st *State) RemoveAttachments(ids []params.MachineStorageId) ([]params.ErrorResult, error) {
 	var results params.ErrorResults
@@ -23,6 +23,16 @@ type provisionerSuite struct {
 	coretesting.BaseSuite
 }
 
+// storageProvisionerV2Caller reports that the server supports version 2
+// of the StorageProvisioner facade, which adds volume snapshots.
+type storageProvisionerV2Caller struct {
+	testing.APICallerFunc
+}
+
+func (storageProvisionerV2Caller) BestFacadeVersion(facade string) int {
+	return 2
+}
+
 func (s *provisionerSuite) TestNewState(c *gc.C) {
 	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
 		return nil
@@ -527,6 +537,214 @@ func (s *provisionerSuite) TestSetVolumeInfo(c *gc.C) {
 	c.Assert(errorResults[0].Error, gc.IsNil)
 }
 
+func (s *provisionerSuite) TestVolumeSnapshotParams(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 2)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "VolumeSnapshotParams")
+		c.Check(arg, gc.DeepEquals, params.VolumeSnapshotIds{Ids: []string{"0/1"}})
+		c.Assert(result, gc.FitsTypeOf, &params.VolumeSnapshotParamsResults{})
+		*(result.(*params.VolumeSnapshotParamsResults)) = params.VolumeSnapshotParamsResults{
+			Results: []params.VolumeSnapshotParamsResult{{
+				Result: params.VolumeSnapshotParams{
+					Id:        "0/1",
+					Life:      params.Alive,
+					VolumeTag: "volume-0-0",
+					VolumeId:  "volume-0-0",
+					Provider:  "loop",
+					Size:      1024,
+				},
+			}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := storageprovisioner.NewState(storageProvisionerV2Caller{apiCaller}, names.NewMachineTag("0"))
+	results, err := st.VolumeSnapshotParams([]string{"0/1"})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(results, jc.DeepEquals, []params.VolumeSnapshotParamsResult{{
+		Result: params.VolumeSnapshotParams{
+			Id:        "0/1",
+			Life:      params.Alive,
+			VolumeTag: "volume-0-0",
+			VolumeId:  "volume-0-0",
+			Provider:  "loop",
+			Size:      1024,
+		},
+	}})
+}
+
+func (s *provisionerSuite) TestSetVolumeSnapshotInfo(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 2)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "SetVolumeSnapshotInfo")
+		c.Check(arg, gc.DeepEquals, params.VolumeSnapshotInfos{
+			Snapshots: []params.VolumeSnapshotInfo{{
+				Id: "0/1", SnapshotId: "snapshot-0-1", Size: 1024,
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: nil}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := storageprovisioner.NewState(storageProvisionerV2Caller{apiCaller}, names.NewMachineTag("0"))
+	errorResults, err := st.SetVolumeSnapshotInfo([]params.VolumeSnapshotInfo{{
+		Id: "0/1", SnapshotId: "snapshot-0-1", Size: 1024,
+	}})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(errorResults, gc.HasLen, 1)
+	c.Assert(errorResults[0].Error, gc.IsNil)
+}
+
+func (s *provisionerSuite) TestSetVolumeSnapshotErrors(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 2)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "SetVolumeSnapshotErrors")
+		c.Check(arg, gc.DeepEquals, params.VolumeSnapshotErrors{
+			Snapshots: []params.VolumeSnapshotError{{
+				Id: "0/1", Error: "snapshots not supported",
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: nil}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := storageprovisioner.NewState(storageProvisionerV2Caller{apiCaller}, names.NewMachineTag("0"))
+	errorResults, err := st.SetVolumeSnapshotErrors([]params.VolumeSnapshotError{{
+		Id: "0/1", Error: "snapshots not supported",
+	}})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(errorResults, gc.HasLen, 1)
+	c.Assert(errorResults[0].Error, gc.IsNil)
+}
+
+func (s *provisionerSuite) TestRemoveVolumeSnapshots(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 2)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "RemoveVolumeSnapshots")
+		c.Check(arg, gc.DeepEquals, params.VolumeSnapshotIds{Ids: []string{"0/1"}})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "yoink"}}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := storageprovisioner.NewState(storageProvisionerV2Caller{apiCaller}, names.NewMachineTag("0"))
+	errorResults, err := st.RemoveVolumeSnapshots([]string{"0/1"})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(errorResults, gc.HasLen, 1)
+	c.Assert(errorResults[0].Error, gc.ErrorMatches, "yoink")
+}
+
+func (s *provisionerSuite) TestVolumeSnapshotsNotSupported(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Errorf("unexpected call to %s", request)
+		return nil
+	})
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("0"))
+	_, err := st.WatchVolumeSnapshots()
+	c.Check(err, gc.ErrorMatches, "WatchVolumeSnapshots not implemented")
+	_, err = st.VolumeSnapshotParams([]string{"0/1"})
+	c.Check(err, gc.ErrorMatches, "VolumeSnapshotParams not implemented")
+	_, err = st.SetVolumeSnapshotInfo(nil)
+	c.Check(err, gc.ErrorMatches, "SetVolumeSnapshotInfo not implemented")
+	_, err = st.SetVolumeSnapshotErrors(nil)
+	c.Check(err, gc.ErrorMatches, "SetVolumeSnapshotErrors not implemented")
+	_, err = st.RemoveVolumeSnapshots([]string{"0/1"})
+	c.Check(err, gc.ErrorMatches, "RemoveVolumeSnapshots not implemented")
+}
+
+func (s *provisionerSuite) TestStorageResizeParams(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "StorageResizeParams")
+		c.Check(arg, gc.DeepEquals, params.StorageResizeIds{Ids: []string{"0/1"}})
+		c.Assert(result, gc.FitsTypeOf, &params.StorageResizeParamsResults{})
+		*(result.(*params.StorageResizeParamsResults)) = params.StorageResizeParamsResults{
+			Results: []params.StorageResizeParamsResult{{
+				Result: params.StorageResizeParams{
+					Id:        "0/1",
+					Life:      params.Alive,
+					VolumeTag: "volume-0-0",
+					VolumeId:  "volume-0-0",
+					Provider:  "loop",
+					Size:      2048,
+				},
+			}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("0"))
+	results, err := st.StorageResizeParams([]string{"0/1"})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(results, jc.DeepEquals, []params.StorageResizeParamsResult{{
+		Result: params.StorageResizeParams{
+			Id:        "0/1",
+			Life:      params.Alive,
+			VolumeTag: "volume-0-0",
+			VolumeId:  "volume-0-0",
+			Provider:  "loop",
+			Size:      2048,
+		},
+	}})
+}
+
+func (s *provisionerSuite) TestCompleteStorageResizes(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "CompleteStorageResizes")
+		c.Check(arg, gc.DeepEquals, params.StorageResizeIds{Ids: []string{"0/1"}})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "yoink"}}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("0"))
+	errorResults, err := st.CompleteStorageResizes([]string{"0/1"})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(errorResults, gc.HasLen, 1)
+	c.Assert(errorResults[0].Error, gc.ErrorMatches, "yoink")
+}
+
 func (s *provisionerSuite) TestSetFilesystemInfo(c *gc.C) {
 	var callCount int
 	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
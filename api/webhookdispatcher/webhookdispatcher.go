@@ -0,0 +1,62 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhookdispatcher
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+const apiName = "WebhookDispatcher"
+
+// Facade allows calls to "WebhookDispatcher" endpoints.
+type Facade struct {
+	facade base.FacadeCaller
+}
+
+// NewFacade returns a "WebhookDispatcher" Facade.
+func NewFacade(caller base.APICaller) *Facade {
+	return &Facade{base.NewFacadeCaller(caller, apiName)}
+}
+
+// WatchAll returns an AllWatcher, from which the changes to the
+// environment can be read.
+func (f *Facade) WatchAll() (*api.AllWatcher, error) {
+	var result params.AllWatcherId
+	if err := f.facade.FacadeCall("WatchAll", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return api.NewAllWatcher(f.facade.RawAPICaller(), &result.AllWatcherId), nil
+}
+
+// Webhooks returns every webhook in the environment, and the
+// controller addresses that must never be delivered to.
+func (f *Facade) Webhooks() (params.WebhookTargetsResult, error) {
+	var result params.WebhookTargetsResult
+	if err := f.facade.FacadeCall("Webhooks", nil, &result); err != nil {
+		return params.WebhookTargetsResult{}, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// AddDeadLetter records a delivery that could not be made to the
+// webhook with the given id.
+func (f *Facade) AddDeadLetter(id, payload string, attempts int, deliveryErr string) error {
+	args := params.AddWebhookDeadLetters{
+		DeadLetters: []params.AddWebhookDeadLetter{{
+			WebhookId: id,
+			Payload:   payload,
+			Attempts:  attempts,
+			Error:     deliveryErr,
+		}},
+	}
+	var results params.ErrorResults
+	if err := f.facade.FacadeCall("AddDeadLetters", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
@@ -0,0 +1,92 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhookdispatcher_test
+
+import (
+	"errors"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/webhookdispatcher"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type webhookDispatcherSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&webhookDispatcherSuite{})
+
+func (s *webhookDispatcherSuite) TestWatchAll(c *gc.C) {
+	var calls []string
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		calls = append(calls, objType+"."+request)
+		switch objType {
+		case "WebhookDispatcher":
+			c.Check(request, gc.Equals, "WatchAll")
+			c.Check(arg, gc.IsNil)
+			*(result.(*params.AllWatcherId)) = params.AllWatcherId{AllWatcherId: "42"}
+		case "AllWatcher":
+			c.Check(id, gc.Equals, "42")
+		}
+		return nil
+	})
+	w, err := webhookdispatcher.NewFacade(apiCaller).WatchAll()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w.Stop(), jc.ErrorIsNil)
+	c.Assert(calls, jc.DeepEquals, []string{"WebhookDispatcher.WatchAll", "AllWatcher.Stop"})
+}
+
+func (s *webhookDispatcherSuite) TestWebhooks(c *gc.C) {
+	expect := params.WebhookTargetsResult{
+		Webhooks: []params.WebhookTarget{{
+			Id:     "hook-1",
+			URL:    "https://example.com/hook",
+			Secret: "s3cret",
+		}},
+		ControllerAddresses: []string{"10.0.0.1"},
+	}
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "WebhookDispatcher")
+		c.Check(request, gc.Equals, "Webhooks")
+		c.Check(arg, gc.IsNil)
+		*(result.(*params.WebhookTargetsResult)) = expect
+		return nil
+	})
+	result, err := webhookdispatcher.NewFacade(apiCaller).Webhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, expect)
+}
+
+func (s *webhookDispatcherSuite) TestAddDeadLetter(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "WebhookDispatcher")
+		c.Check(request, gc.Equals, "AddDeadLetters")
+		c.Check(arg, jc.DeepEquals, params.AddWebhookDeadLetters{
+			DeadLetters: []params.AddWebhookDeadLetter{{
+				WebhookId: "hook-1",
+				Payload:   "{}",
+				Attempts:  5,
+				Error:     "503 Service Unavailable",
+			}},
+		})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+		}
+		return nil
+	})
+	err := webhookdispatcher.NewFacade(apiCaller).AddDeadLetter("hook-1", "{}", 5, "503 Service Unavailable")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *webhookDispatcherSuite) TestWebhooksError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("boom")
+	})
+	_, err := webhookdispatcher.NewFacade(apiCaller).Webhooks()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
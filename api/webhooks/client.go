@@ -0,0 +1,89 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package webhooks provides a client for managing the webhooks that
+// environment changes are delivered to.
+package webhooks
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the webhooks API.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the webhooks API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Webhooks")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// AddWebhook creates a webhook that changes to the environment are
+// delivered to, signed with the given secret. Empty kinds or statuses
+// match everything.
+func (c *Client) AddWebhook(url string, kinds, statuses []string, secret string) (params.Webhook, error) {
+	args := params.AddWebhooks{
+		Webhooks: []params.AddWebhookParams{{
+			URL:      url,
+			Kinds:    kinds,
+			Statuses: statuses,
+			Secret:   secret,
+		}},
+	}
+	var results params.WebhookResults
+	if err := c.facade.FacadeCall("AddWebhooks", args, &results); err != nil {
+		return params.Webhook{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return params.Webhook{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return params.Webhook{}, err
+	}
+	return results.Results[0].Webhook, nil
+}
+
+// ListWebhooks returns all webhooks in the environment.
+func (c *Client) ListWebhooks() ([]params.Webhook, error) {
+	var result params.ListWebhooksResult
+	if err := c.facade.FacadeCall("ListWebhooks", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Webhooks, nil
+}
+
+// RemoveWebhooks removes the webhooks with the given ids.
+func (c *Client) RemoveWebhooks(ids []string) ([]params.ErrorResult, error) {
+	args := params.WebhookIds{Ids: ids}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("RemoveWebhooks", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(ids) {
+		return nil, errors.Errorf("expected %d results, got %d", len(ids), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// DeadLetters returns the deliveries that could not be made to the
+// webhook with the given id, oldest first.
+func (c *Client) DeadLetters(id string) ([]params.WebhookDeadLetter, error) {
+	args := params.WebhookIds{Ids: []string{id}}
+	var results params.WebhookDeadLettersResults
+	if err := c.facade.FacadeCall("WebhookDeadLetters", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, err
+	}
+	return results.Results[0].DeadLetters, nil
+}
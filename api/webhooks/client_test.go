@@ -0,0 +1,151 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhooks_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/webhooks"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type webhooksSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&webhooksSuite{})
+
+func (s *webhooksSuite) TestAddWebhook(c *gc.C) {
+	hook := params.Webhook{
+		Id:      "hook-1",
+		URL:     "https://example.com/hook",
+		Kinds:   []string{"unit"},
+		Owner:   "admin",
+		Created: time.Date(2015, 10, 1, 0, 0, 0, 0, time.UTC),
+	}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Webhooks")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "AddWebhooks")
+			c.Check(a, jc.DeepEquals, params.AddWebhooks{
+				Webhooks: []params.AddWebhookParams{{
+					URL:    "https://example.com/hook",
+					Kinds:  []string{"unit"},
+					Secret: "s3cret",
+				}},
+			})
+			if result, ok := result.(*params.WebhookResults); ok {
+				result.Results = []params.WebhookResult{{Webhook: hook}}
+			}
+			return nil
+		})
+	client := webhooks.NewClient(apiCaller)
+	result, err := client.AddWebhook("https://example.com/hook", []string{"unit"}, nil, "s3cret")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, hook)
+}
+
+func (s *webhooksSuite) TestAddWebhookError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			if result, ok := result.(*params.WebhookResults); ok {
+				result.Results = []params.WebhookResult{{
+					Error: &params.Error{Message: "cannot add webhook: no secret given"},
+				}}
+			}
+			return nil
+		})
+	client := webhooks.NewClient(apiCaller)
+	_, err := client.AddWebhook("https://example.com/hook", nil, nil, "")
+	c.Assert(err, gc.ErrorMatches, "cannot add webhook: no secret given")
+}
+
+func (s *webhooksSuite) TestListWebhooks(c *gc.C) {
+	hooks := []params.Webhook{{Id: "hook-1", URL: "https://example.com/hook"}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Webhooks")
+			c.Check(request, gc.Equals, "ListWebhooks")
+			c.Check(a, gc.IsNil)
+			if result, ok := result.(*params.ListWebhooksResult); ok {
+				result.Webhooks = hooks
+			}
+			return nil
+		})
+	client := webhooks.NewClient(apiCaller)
+	result, err := client.ListWebhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, hooks)
+}
+
+func (s *webhooksSuite) TestRemoveWebhooks(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Webhooks")
+			c.Check(request, gc.Equals, "RemoveWebhooks")
+			c.Check(a, jc.DeepEquals, params.WebhookIds{Ids: []string{"hook-1", "hook-2"}})
+			if result, ok := result.(*params.ErrorResults); ok {
+				result.Results = []params.ErrorResult{{}, {
+					Error: &params.Error{Message: `webhook "hook-2" not found`},
+				}}
+			}
+			return nil
+		})
+	client := webhooks.NewClient(apiCaller)
+	results, err := client.RemoveWebhooks([]string{"hook-1", "hook-2"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Assert(results[1].Error, gc.ErrorMatches, `webhook "hook-2" not found`)
+}
+
+func (s *webhooksSuite) TestDeadLetters(c *gc.C) {
+	letters := []params.WebhookDeadLetter{{
+		WebhookId: "hook-1",
+		Payload:   "[]",
+		Attempts:  5,
+		Error:     "503 Service Unavailable",
+		Time:      time.Date(2015, 10, 1, 0, 0, 0, 0, time.UTC),
+	}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Webhooks")
+			c.Check(request, gc.Equals, "WebhookDeadLetters")
+			c.Check(a, jc.DeepEquals, params.WebhookIds{Ids: []string{"hook-1"}})
+			if result, ok := result.(*params.WebhookDeadLettersResults); ok {
+				result.Results = []params.WebhookDeadLettersResult{{DeadLetters: letters}}
+			}
+			return nil
+		})
+	client := webhooks.NewClient(apiCaller)
+	result, err := client.DeadLetters("hook-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, letters)
+}
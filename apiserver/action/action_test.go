@@ -6,6 +6,7 @@ package action_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
@@ -30,6 +31,7 @@ type actionSuite struct {
 	jujutesting.JujuConnSuite
 
 	action     *action.ActionAPI
+	actionV1   *action.ActionAPIV1
 	authorizer apiservertesting.FakeAuthorizer
 	resources  *common.Resources
 
@@ -54,6 +56,8 @@ func (s *actionSuite) SetUpTest(c *gc.C) {
 	var err error
 	s.action, err = action.NewActionAPI(s.State, nil, s.authorizer)
 	c.Assert(err, jc.ErrorIsNil)
+	s.actionV1, err = action.NewActionAPIV1(s.State, nil, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
 
 	factory := jujuFactory.NewFactory(s.State)
 
@@ -217,6 +221,148 @@ func (s *actionSuite) TestEnqueue(c *gc.C) {
 	c.Assert(actions, gc.HasLen, 0)
 }
 
+func (s *actionSuite) TestSchedule(c *gc.C) {
+	arg := params.ScheduleActions{
+		Actions: []params.ScheduleAction{
+			// Good.
+			{Receiver: s.wordpressUnit.Tag().String(), Name: "fakeaction", Schedule: "30 2 * * *",
+				Parameters: map[string]interface{}{"foo": "bar"}},
+			// Service tag instead of Unit tag.
+			{Receiver: s.wordpress.Tag().String(), Name: "fakeaction", Schedule: "@daily"},
+			// Bad schedule.
+			{Receiver: s.mysqlUnit.Tag().String(), Name: "fakeaction", Schedule: "every day"},
+		},
+	}
+	res, err := s.actionV1.Schedule(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 3)
+
+	c.Assert(res.Results[0].Error, gc.IsNil)
+	scheduled := res.Results[0].Result
+	c.Assert(scheduled, gc.NotNil)
+	c.Assert(scheduled.Receiver, gc.Equals, s.wordpressUnit.Tag().String())
+	c.Assert(scheduled.Name, gc.Equals, "fakeaction")
+	c.Assert(scheduled.Schedule, gc.Equals, "30 2 * * *")
+	c.Assert(scheduled.Parameters, jc.DeepEquals, map[string]interface{}{"foo": "bar"})
+	c.Assert(scheduled.Runs, gc.HasLen, 0)
+
+	c.Assert(res.Results[1].Error, gc.DeepEquals, &params.Error{Message: "id not found", Code: "not found"})
+	c.Assert(res.Results[2].Error, gc.ErrorMatches, `cron spec "every day": expected 5 fields, got 2`)
+
+	stateScheduled, err := s.State.ScheduledAction(scheduled.Id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stateScheduled.Receiver(), gc.Equals, s.wordpressUnit.Name())
+}
+
+func (s *actionSuite) TestListSchedules(c *gc.C) {
+	wordpressScheduled, err := s.wordpressUnit.ScheduleAction("fakeaction", "@daily", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	mysqlScheduled, err := s.mysqlUnit.ScheduleAction("fakeaction", "@hourly", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	action, err := mysqlScheduled.Run(mysqlScheduled.NextRun())
+	c.Assert(err, jc.ErrorIsNil)
+
+	res, err := s.actionV1.ListSchedules(params.Entities{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 2)
+
+	res, err = s.actionV1.ListSchedules(params.Entities{Entities: []params.Entity{
+		{Tag: s.mysqlUnit.Tag().String()},
+		{Tag: "machine-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 2)
+	c.Assert(res.Results[0].Error, gc.IsNil)
+	c.Assert(res.Results[0].Result.Id, gc.Equals, mysqlScheduled.Id())
+	c.Assert(res.Results[0].Result.Runs, gc.HasLen, 1)
+	c.Assert(res.Results[0].Result.Runs[0].ActionTag, gc.Equals, action.Tag().String())
+	c.Assert(res.Results[1].Error, gc.DeepEquals, &params.Error{Message: "id not found", Code: "not found"})
+
+	res, err = s.actionV1.ListSchedules(params.Entities{Entities: []params.Entity{
+		{Tag: s.wordpressUnit.Tag().String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 1)
+	c.Assert(res.Results[0].Result.Id, gc.Equals, wordpressScheduled.Id())
+}
+
+func (s *actionSuite) TestUnschedule(c *gc.C) {
+	scheduled, err := s.wordpressUnit.ScheduleAction("fakeaction", "@daily", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	res, err := s.actionV1.Unschedule(params.ScheduledActionIds{Ids: []string{scheduled.Id(), "missing"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 2)
+	c.Assert(res.Results[0].Error, gc.IsNil)
+	c.Assert(res.Results[1].Error, gc.ErrorMatches, `scheduled action "missing" not found`)
+
+	all, err := s.State.ScheduledActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 0)
+}
+
+func (s *actionSuite) TestEnqueueOperations(c *gc.C) {
+	factory := jujuFactory.NewFactory(s.State)
+	unit1 := factory.MakeUnit(c, &jujuFactory.UnitParams{
+		Service: s.wordpress,
+		Machine: s.machine1,
+	})
+	arg := params.ServiceActions{
+		Actions: []params.ServiceAction{
+			// Good, one at a time.
+			{Service: s.wordpress.Tag().String(), Name: "fakeaction", BatchSize: 1},
+			// Unit tag instead of Service tag.
+			{Service: s.wordpressUnit.Tag().String(), Name: "fakeaction"},
+			// Unit of another service.
+			{Service: s.mysql.Tag().String(), Name: "fakeaction", Units: []string{s.wordpressUnit.Name()}},
+		},
+	}
+	res, err := s.actionV1.EnqueueOperations(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 3)
+
+	c.Assert(res.Results[0].Error, gc.IsNil)
+	op := res.Results[0].Operation
+	c.Assert(op, gc.NotNil)
+	c.Assert(op.Service, gc.Equals, s.wordpress.Tag().String())
+	c.Assert(op.Name, gc.Equals, "fakeaction")
+	c.Assert(op.BatchSize, gc.Equals, 1)
+	c.Assert(op.Units, gc.HasLen, 2)
+	c.Assert(op.Units[0].Unit, gc.Equals, s.wordpressUnit.Name())
+	c.Assert(op.Units[0].Status, gc.Equals, string(state.ActionPending))
+	c.Assert(op.Units[0].ActionTag, gc.Not(gc.Equals), "")
+	c.Assert(op.Units[1], gc.DeepEquals, params.ActionOperationUnit{
+		Unit:   unit1.Name(),
+		Status: "waiting",
+	})
+
+	c.Assert(res.Results[1].Error, gc.DeepEquals, &params.Error{Message: "id not found", Code: "not found"})
+	c.Assert(res.Results[2].Error, gc.ErrorMatches, `unit "wordpress/0" does not belong to service "mysql"`)
+}
+
+func (s *actionSuite) TestActionOperations(c *gc.C) {
+	op, err := s.wordpress.EnqueueActionOperation("fakeaction", nil, nil, 0)
+	c.Assert(err, jc.ErrorIsNil)
+
+	res, err := s.actionV1.ActionOperations(params.ActionOperationIds{
+		Ids: []string{op.Id()[:8], "missing"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 2)
+	c.Assert(res.Results[0].Error, gc.IsNil)
+	c.Assert(res.Results[0].Operation.Id, gc.Equals, op.Id())
+	c.Assert(res.Results[0].Operation.Units, gc.HasLen, 1)
+	c.Assert(res.Results[0].Operation.Units[0].Status, gc.Equals, string(state.ActionPending))
+	c.Assert(res.Results[1].Error, gc.ErrorMatches, `operation "missing" not found`)
+
+	// The empty prefix matches every operation.
+	_, err = s.mysql.EnqueueActionOperation("fakeaction", nil, nil, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	res, err = s.actionV1.ActionOperations(params.ActionOperationIds{Ids: []string{""}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results[0].Error, gc.ErrorMatches, `operation id prefix "" is ambiguous`)
+}
+
 type testCaseAction struct {
 	Name       string
 	Parameters map[string]interface{}
@@ -574,6 +720,49 @@ func (s *actionSuite) TestCancel(c *gc.C) {
 	c.Assert(myActions[1].Status, gc.Equals, params.ActionCancelled)
 }
 
+func (s *actionSuite) TestCancelRunningAndFinished(c *gc.C) {
+	running, err := s.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	running, err = running.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	finished, err := s.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = finished.Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.action.Cancel(params.Entities{Entities: []params.Entity{
+		{Tag: running.Tag().String()},
+		{Tag: finished.Tag().String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Status, gc.Equals, params.ActionRunning)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot cancel action ".*": action ".*" already completed`)
+
+	running, err = s.State.Action(running.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(running.CancelRequested(), jc.IsTrue)
+}
+
+func (s *actionSuite) TestEnqueueWithTimeout(c *gc.C) {
+	results, err := s.action.Enqueue(params.Actions{Actions: []params.Action{{
+		Receiver: s.wordpressUnit.Tag().String(),
+		Name:     "fakeaction",
+		Timeout:  time.Minute,
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Action.Timeout, gc.Equals, time.Minute)
+
+	tag, err := names.ParseActionTag(results.Results[0].Action.Tag)
+	c.Assert(err, jc.ErrorIsNil)
+	action, err := s.State.ActionByTag(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Timeout(), gc.Equals, time.Minute)
+}
+
 func (s *actionSuite) TestServicesCharmActions(c *gc.C) {
 	actionSchemas := map[string]map[string]interface{}{
 		"snapshot": {
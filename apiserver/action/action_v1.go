@@ -0,0 +1,239 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Action", 1, NewActionAPIV1)
+}
+
+// ActionAPIV1 implements version 1 of the Action API facade. It adds
+// scheduled actions and action operations to version 0.
+type ActionAPIV1 struct {
+	ActionAPI
+}
+
+// NewActionAPIV1 returns an initialized ActionAPIV1.
+func NewActionAPIV1(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*ActionAPIV1, error) {
+	api, err := NewActionAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &ActionAPIV1{*api}, nil
+}
+
+// Schedule takes a list of Actions with cron schedules, and arranges
+// for each to be enqueued on its receiver every time the schedule
+// fires. Only units may be the receivers of scheduled actions.
+func (a *ActionAPIV1) Schedule(arg params.ScheduleActions) (params.ScheduledActionResults, error) {
+	response := params.ScheduledActionResults{Results: make([]params.ScheduledActionResult, len(arg.Actions))}
+	for i, action := range arg.Actions {
+		currentResult := &response.Results[i]
+		unitTag, err := names.ParseUnitTag(action.Receiver)
+		if err != nil {
+			currentResult.Error = common.ServerError(common.ErrBadId)
+			continue
+		}
+		unit, err := a.state.Unit(unitTag.Id())
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
+		scheduled, err := unit.ScheduleAction(action.Name, action.Schedule, action.Parameters)
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
+		currentResult.Result = makeScheduledAction(scheduled)
+	}
+	return response, nil
+}
+
+// ListSchedules returns the scheduled actions for each of the given
+// units, or for every unit in the environment if none are given.
+func (a *ActionAPIV1) ListSchedules(arg params.Entities) (params.ScheduledActionResults, error) {
+	var response params.ScheduledActionResults
+	if len(arg.Entities) == 0 {
+		scheduled, err := a.state.ScheduledActions()
+		if err != nil {
+			return response, common.ServerError(err)
+		}
+		for _, sa := range scheduled {
+			response.Results = append(response.Results, params.ScheduledActionResult{
+				Result: makeScheduledAction(sa),
+			})
+		}
+		return response, nil
+	}
+	for _, entity := range arg.Entities {
+		unitTag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			response.Results = append(response.Results, params.ScheduledActionResult{
+				Error: common.ServerError(common.ErrBadId),
+			})
+			continue
+		}
+		scheduled, err := a.state.ScheduledActionsForUnit(unitTag.Id())
+		if err != nil {
+			response.Results = append(response.Results, params.ScheduledActionResult{
+				Error: common.ServerError(err),
+			})
+			continue
+		}
+		for _, sa := range scheduled {
+			response.Results = append(response.Results, params.ScheduledActionResult{
+				Result: makeScheduledAction(sa),
+			})
+		}
+	}
+	return response, nil
+}
+
+// Unschedule removes the scheduled actions with the given ids. Actions
+// that have already been enqueued by the schedules are not affected.
+func (a *ActionAPIV1) Unschedule(arg params.ScheduledActionIds) (params.ErrorResults, error) {
+	response := params.ErrorResults{Results: make([]params.ErrorResult, len(arg.Ids))}
+	for i, id := range arg.Ids {
+		scheduled, err := a.state.ScheduledAction(id)
+		if err == nil {
+			err = scheduled.Remove()
+		}
+		response.Results[i].Error = common.ServerError(err)
+	}
+	return response, nil
+}
+
+// makeScheduledAction converts a *state.ScheduledAction to a
+// params.ScheduledAction.
+func makeScheduledAction(sa *state.ScheduledAction) *params.ScheduledAction {
+	result := &params.ScheduledAction{
+		Id:         sa.Id(),
+		Receiver:   names.NewUnitTag(sa.Receiver()).String(),
+		Name:       sa.Name(),
+		Parameters: sa.Parameters(),
+		Schedule:   sa.Schedule(),
+		Created:    sa.Created(),
+		NextRun:    sa.NextRun(),
+	}
+	for _, run := range sa.Runs() {
+		paramsRun := params.ScheduledActionRun{
+			Enqueued: run.Enqueued,
+			Error:    run.Error,
+		}
+		if run.ActionId != "" {
+			paramsRun.ActionTag = names.NewActionTag(run.ActionId).String()
+		}
+		result.Runs = append(result.Runs, paramsRun)
+	}
+	return result
+}
+
+// EnqueueOperations takes a list of Actions, each to be enqueued on
+// many units of a service, and starts an operation for each.
+func (a *ActionAPIV1) EnqueueOperations(arg params.ServiceActions) (params.ActionOperationResults, error) {
+	response := params.ActionOperationResults{Results: make([]params.ActionOperationResult, len(arg.Actions))}
+	for i, action := range arg.Actions {
+		currentResult := &response.Results[i]
+		serviceTag, err := names.ParseServiceTag(action.Service)
+		if err != nil {
+			currentResult.Error = common.ServerError(common.ErrBadId)
+			continue
+		}
+		service, err := a.state.Service(serviceTag.Id())
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
+		op, err := service.EnqueueActionOperation(action.Name, action.Parameters, action.Units, action.BatchSize)
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
+		currentResult.Operation, err = makeActionOperation(op)
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+		}
+	}
+	return response, nil
+}
+
+// ActionOperations returns the action operation for each of the given
+// ids. Each id may be abbreviated to a prefix that matches exactly one
+// operation.
+func (a *ActionAPIV1) ActionOperations(arg params.ActionOperationIds) (params.ActionOperationResults, error) {
+	response := params.ActionOperationResults{Results: make([]params.ActionOperationResult, len(arg.Ids))}
+	for i, id := range arg.Ids {
+		currentResult := &response.Results[i]
+		ops, err := a.state.FindActionOperationsByPrefix(id)
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
+		if len(ops) == 0 {
+			currentResult.Error = common.ServerError(errors.NotFoundf("operation %q", id))
+			continue
+		}
+		if len(ops) > 1 {
+			currentResult.Error = common.ServerError(errors.Errorf("operation id prefix %q is ambiguous", id))
+			continue
+		}
+		currentResult.Operation, err = makeActionOperation(ops[0])
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+		}
+	}
+	return response, nil
+}
+
+// makeActionOperation converts a *state.ActionOperation to a
+// params.ActionOperation, reporting the progress of its action on each
+// unit.
+func makeActionOperation(op *state.ActionOperation) (*params.ActionOperation, error) {
+	actions, err := op.Actions()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	byUnit := make(map[string]*state.Action)
+	for _, action := range actions {
+		byUnit[action.Receiver()] = action
+	}
+	failures := op.Failures()
+	waiting := make(map[string]bool)
+	for _, unitName := range op.Waiting() {
+		waiting[unitName] = true
+	}
+	result := &params.ActionOperation{
+		Id:         op.Id(),
+		Service:    names.NewServiceTag(op.Service()).String(),
+		Name:       op.Name(),
+		Parameters: op.Parameters(),
+		BatchSize:  op.BatchSize(),
+		Enqueued:   op.Enqueued(),
+		Units:      make([]params.ActionOperationUnit, len(op.Units())),
+	}
+	for i, unitName := range op.Units() {
+		unit := params.ActionOperationUnit{Unit: unitName}
+		if action, ok := byUnit[unitName]; ok {
+			_, message := action.Results()
+			unit.ActionTag = action.ActionTag().String()
+			unit.Status = string(action.Status())
+			unit.Message = message
+		} else if message, ok := failures[unitName]; ok {
+			unit.Status = string(state.ActionFailed)
+			unit.Message = message
+		} else if waiting[unitName] {
+			unit.Status = "waiting"
+		}
+		result.Units[i] = unit
+	}
+	return result, nil
+}
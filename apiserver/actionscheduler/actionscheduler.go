@@ -0,0 +1,51 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The actionscheduler package implements the API interface
+// used by the actionscheduler worker.
+
+package actionscheduler
+
+import (
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("ActionScheduler", 1, NewActionSchedulerAPI)
+}
+
+// StateInterface defines the state methods used by the ActionScheduler
+// facade.
+type StateInterface interface {
+	RunDueScheduledActions() error
+}
+
+var getState = func(st *state.State) StateInterface {
+	return st
+}
+
+// ActionSchedulerAPI implements the API used by the actionscheduler
+// worker.
+type ActionSchedulerAPI struct {
+	st StateInterface
+}
+
+// NewActionSchedulerAPI creates a new instance of the ActionScheduler
+// API.
+func NewActionSchedulerAPI(
+	st *state.State,
+	_ *common.Resources,
+	authorizer common.Authorizer,
+) (*ActionSchedulerAPI, error) {
+	if !authorizer.AuthEnvironManager() {
+		return nil, common.ErrPerm
+	}
+	return &ActionSchedulerAPI{st: getState(st)}, nil
+}
+
+// RunDueScheduledActions enqueues every scheduled action whose next
+// run time has passed.
+func (api *ActionSchedulerAPI) RunDueScheduledActions() error {
+	return api.st.RunDueScheduledActions()
+}
@@ -0,0 +1,70 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionscheduler_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/actionscheduler"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ActionSchedulerSuite struct {
+	coretesting.BaseSuite
+
+	st         *mockState
+	api        *actionscheduler.ActionSchedulerAPI
+	authoriser apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&ActionSchedulerSuite{})
+
+func (s *ActionSchedulerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+
+	s.authoriser = apiservertesting.FakeAuthorizer{
+		EnvironManager: true,
+	}
+	s.st = &mockState{&testing.Stub{}}
+	actionscheduler.PatchState(s, s.st)
+	var err error
+	s.api, err = actionscheduler.NewActionSchedulerAPI(nil, nil, s.authoriser)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ActionSchedulerSuite) TestNewActionSchedulerAPIRequiresEnvironManager(c *gc.C) {
+	anAuthoriser := s.authoriser
+	anAuthoriser.EnvironManager = false
+	api, err := actionscheduler.NewActionSchedulerAPI(nil, nil, anAuthoriser)
+	c.Assert(api, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(common.ServerError(err), jc.Satisfies, params.IsCodeUnauthorized)
+}
+
+func (s *ActionSchedulerSuite) TestRunDueScheduledActions(c *gc.C) {
+	err := s.api.RunDueScheduledActions()
+	c.Assert(err, jc.ErrorIsNil)
+	s.st.CheckCallNames(c, "RunDueScheduledActions")
+}
+
+func (s *ActionSchedulerSuite) TestRunDueScheduledActionsFailure(c *gc.C) {
+	s.st.SetErrors(errors.New("boom!"))
+	err := s.api.RunDueScheduledActions()
+	c.Assert(err, gc.ErrorMatches, "boom!")
+}
+
+type mockState struct {
+	*testing.Stub
+}
+
+func (st *mockState) RunDueScheduledActions() error {
+	st.MethodCall(st, "RunDueScheduledActions")
+	return st.NextErr()
+}
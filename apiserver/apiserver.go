@@ -6,6 +6,7 @@ package apiserver
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
 	"net"
 	"net/http"
 	"strings"
@@ -24,6 +25,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/feature"
+	"github.com/juju/juju/loglabels"
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/jsoncodec"
 	"github.com/juju/juju/state"
@@ -270,9 +272,9 @@ func (n *requestNotifier) ServerRequest(hdr *rpc.Header, body interface{}) {
 	// Until secrets are removed, we only log the body of the requests at trace level
 	// which is below the default level of debug.
 	if logger.IsTraceEnabled() {
-		logger.Tracef("<- [%X] %s %s", n.id, n.tag(), jsoncodec.DumpRequest(hdr, body))
-	} else {
-		logger.Debugf("<- [%X] %s %s", n.id, n.tag(), jsoncodec.DumpRequest(hdr, "'params redacted'"))
+		n.requestLogger(hdr).Tracef("<- [%X] %s %s", n.id, n.tag(), jsoncodec.DumpRequest(hdr, body))
+	} else if logger.IsDebugEnabled() {
+		n.requestLogger(hdr).Debugf("<- [%X] %s %s", n.id, n.tag(), jsoncodec.DumpRequest(hdr, "'params redacted'"))
 	}
 }
 
@@ -284,12 +286,22 @@ func (n *requestNotifier) ServerReply(req rpc.Request, hdr *rpc.Header, body int
 	// Until secrets are removed, we only log the body of the requests at trace level
 	// which is below the default level of debug.
 	if logger.IsTraceEnabled() {
-		logger.Tracef("-> [%X] %s %s", n.id, n.tag(), jsoncodec.DumpRequest(hdr, body))
-	} else {
-		logger.Debugf("-> [%X] %s %s %s %s[%q].%s", n.id, n.tag(), timeSpent, jsoncodec.DumpRequest(hdr, "'body redacted'"), req.Type, req.Id, req.Action)
+		n.requestLogger(hdr).Tracef("-> [%X] %s %s", n.id, n.tag(), jsoncodec.DumpRequest(hdr, body))
+	} else if logger.IsDebugEnabled() {
+		n.requestLogger(hdr).Debugf("-> [%X] %s %s %s %s[%q].%s", n.id, n.tag(), timeSpent, jsoncodec.DumpRequest(hdr, "'body redacted'"), req.Type, req.Id, req.Action)
 	}
 }
 
+// requestLogger returns a logger that labels its records with the
+// request with the given header, so the request and its reply can be
+// found in debug-log. As requests are frequent, callers should only
+// create one when the level they log at is enabled.
+func (n *requestNotifier) requestLogger(hdr *rpc.Header) loglabels.Logger {
+	return loglabels.NewLogger(logger, map[string]string{
+		loglabels.Request: fmt.Sprintf("%X:%d", n.id, hdr.RequestId),
+	})
+}
+
 func (n *requestNotifier) join(req *http.Request) {
 	logger.Infof("[%X] API connection from %s", n.id, req.RemoteAddr)
 }
@@ -347,10 +359,17 @@ func (srv *Server) run(lis net.Listener) {
 			newLogSinkHandler(httpCtxt, srv.logDir))
 		handleAll(mux, "/environment/:envuuid/log",
 			newDebugLogDBHandler(httpCtxt, srvDying))
+		handleAll(mux, "/environment/:envuuid/logarchive",
+			&logArchiveHandler{
+				ctxt: httpCtxt,
+			},
+		)
 	} else {
 		handleAll(mux, "/environment/:envuuid/log",
 			newDebugLogFileHandler(httpCtxt, srvDying, srv.logDir))
 	}
+	handleAll(mux, "/environment/:envuuid/events",
+		newEventsHandler(httpCtxt, srvDying))
 	handleAll(mux, "/environment/:envuuid/charms",
 		&charmsHandler{
 			ctxt:    httpCtxt,
@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/highavailability"
+	"github.com/juju/juju/apiserver/metricsender"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/apiserver/service"
 	"github.com/juju/juju/environs/config"
@@ -347,6 +348,37 @@ func (c *Client) AddServiceUnitsWithPlacement(args params.AddServiceUnits) (para
 	return params.AddServiceUnitsResults{Units: unitNames}, nil
 }
 
+// addServiceUnitAttachingStorage adds a unit to a service, attaching
+// the storage specified in args to it.
+func addServiceUnitAttachingStorage(st *state.State, args params.AddServiceUnits) (*state.Unit, error) {
+	if args.NumUnits != 1 {
+		return nil, errors.New("must add exactly one unit when attaching storage")
+	}
+	if args.ToMachineSpec != "" {
+		return nil, errors.New("cannot use ToMachineSpec when attaching storage")
+	}
+	if len(args.AttachStorage) == 0 {
+		return nil, errors.New("no storage specified")
+	}
+	storageTags := make([]names.StorageTag, len(args.AttachStorage))
+	for i, tag := range args.AttachStorage {
+		storageTag, err := names.ParseStorageTag(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		storageTags[i] = storageTag
+	}
+	service, err := st.Service(args.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+	var placement *instance.Placement
+	if len(args.Placement) > 0 {
+		placement = args.Placement[0]
+	}
+	return jjj.AddUnitAttachingStorage(st, service, placement, storageTags)
+}
+
 // DestroyServiceUnits removes a given set of service units.
 func (c *Client) DestroyServiceUnits(args params.DestroyServiceUnits) error {
 	if err := c.check.RemoveAllowed(); err != nil {
@@ -826,22 +858,46 @@ func (c *Client) EnvironmentSet(args params.EnvironmentSet) error {
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
-	// Make sure we don't allow changing agent-version.
-	checkAgentVersion := func(updateAttrs map[string]interface{}, removeAttrs []string, oldConfig *config.Config) error {
+	// Make sure we don't allow changing agent-version, or selecting
+	// a metric sender that cannot be used.
+	checkConfig := func(updateAttrs map[string]interface{}, removeAttrs []string, oldConfig *config.Config) error {
 		if v, found := updateAttrs["agent-version"]; found {
 			oldVersion, _ := oldConfig.AgentVersion()
 			if v != oldVersion.String() {
 				return fmt.Errorf("agent-version cannot be changed")
 			}
 		}
-		return nil
+		return checkMetricsSender(updateAttrs, oldConfig)
 	}
 	// Replace any deprecated attributes with their new values.
 	attrs := config.ProcessDeprecatedAttributes(args.Config)
 	// TODO(waigani) 2014-3-11 #1167616
 	// Add a txn retry loop to ensure that the settings on disk have not
 	// changed underneath us.
-	return c.api.stateAccessor.UpdateEnvironConfig(attrs, nil, checkAgentVersion)
+	return c.api.stateAccessor.UpdateEnvironConfig(attrs, nil, checkConfig)
+}
+
+// checkMetricsSender returns an error if the metric sender selected by
+// applying updateAttrs to oldConfig is not registered, or cannot
+// deliver metrics to the configured target.
+func checkMetricsSender(updateAttrs map[string]interface{}, oldConfig *config.Config) error {
+	_, nameFound := updateAttrs[config.MetricsSenderKey]
+	_, targetFound := updateAttrs[config.MetricsSenderURLKey]
+	if !nameFound && !targetFound {
+		return nil
+	}
+	cfg, err := oldConfig.Apply(updateAttrs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	name, ok := cfg.MetricsSender()
+	if !ok {
+		return nil
+	}
+	if _, err := metricsender.NewSender(name, cfg.MetricsSenderURL()); err != nil {
+		return errors.Annotatef(err, "invalid %s", config.MetricsSenderKey)
+	}
+	return nil
 }
 
 // EnvironmentUnset implements the server-side part of the
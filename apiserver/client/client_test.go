@@ -844,6 +844,17 @@ func (s *clientSuite) TestClientAddServiceUnitsToNewContainer(c *gc.C) {
 	c.Assert(mid, gc.Equals, machine.Id()+"/lxc/0")
 }
 
+func (s *clientSuite) TestClientAddServiceUnitsAttachingStorage(c *gc.C) {
+	s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	client := s.APIState.Client()
+	_, err := client.AddServiceUnitsAttachingStorage("dummy", nil, []string{"storage-data-0"})
+	c.Assert(err, gc.ErrorMatches, `cannot add unit to service "dummy": storage instance "data/0" not found`)
+	_, err = client.AddServiceUnitsAttachingStorage("dummy", nil, []string{"volume-0"})
+	c.Assert(err, gc.ErrorMatches, `"volume-0" is not a valid storage tag`)
+	_, err = client.AddServiceUnitsAttachingStorage("dummy", nil, nil)
+	c.Assert(err, gc.ErrorMatches, "no storage specified")
+}
+
 var clientAddServiceUnitsWithPlacementTests = []struct {
 	about      string
 	service    string // if not set, defaults to 'dummy'
@@ -2127,6 +2138,27 @@ func (s *serverSuite) TestClientEnvironmentSetCannotChangeAgentVersion(c *gc.C)
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *serverSuite) TestClientEnvironmentSetMetricsSender(c *gc.C) {
+	args := params.EnvironmentSet{
+		map[string]interface{}{
+			"metrics-sender":     "carrier-pigeon",
+			"metrics-sender-url": "localhost:1234",
+		},
+	}
+	err := s.client.EnvironmentSet(args)
+	c.Assert(err, gc.ErrorMatches, `invalid metrics-sender: metric sender "carrier-pigeon" not found`)
+
+	args.Config["metrics-sender"] = "file"
+	args.Config["metrics-sender-url"] = "/etc/passwd"
+	err = s.client.EnvironmentSet(args)
+	c.Assert(err, gc.ErrorMatches, `invalid metrics-sender: cannot create metric sender "file": metrics file name "/etc/passwd" not valid`)
+
+	args.Config["metrics-sender-url"] = "metrics.log"
+	err = s.client.EnvironmentSet(args)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEnvValue(c, "metrics-sender", "file")
+}
+
 func (s *serverSuite) TestClientEnvironmentUnset(c *gc.C) {
 	err := s.State.UpdateEnvironConfig(map[string]interface{}{"abc": 123}, nil, nil)
 	c.Assert(err, jc.ErrorIsNil)
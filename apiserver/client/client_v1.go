@@ -0,0 +1,57 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Client", 1, NewClientV1)
+}
+
+// ClientV1 serves client-specific API methods. It adds
+// EnvironStatusHistory, AddServiceUnitsAttachingStorage and
+// LogArchives to version 0 of the Client facade, and accepts
+// structured filter terms such as "workload=blocked" in FullStatus
+// patterns.
+type ClientV1 struct {
+	Client
+}
+
+// NewClientV1 creates a new instance of version 1 of the Client facade.
+func NewClientV1(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*ClientV1, error) {
+	client, err := NewClient(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientV1{*client}, nil
+}
+
+// AddServiceUnitsAttachingStorage adds a unit to a service, attaching
+// storage instances that were detached from other units of the service.
+func (c *ClientV1) AddServiceUnitsAttachingStorage(args params.AddServiceUnits) (params.AddServiceUnitsResults, error) {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return params.AddServiceUnitsResults{}, errors.Trace(err)
+	}
+	unit, err := addServiceUnitAttachingStorage(c.api.state(), args)
+	if err != nil {
+		return params.AddServiceUnitsResults{}, err
+	}
+	return params.AddServiceUnitsResults{Units: []string{unit.String()}}, nil
+}
+
+// FullStatus gives the information needed for juju status over the
+// api. Unlike version 0, patterns may include structured filter terms.
+func (c *ClientV1) FullStatus(args params.StatusParams) (params.FullStatus, error) {
+	patterns, filters, err := splitStatusPatterns(args.Patterns)
+	if err != nil {
+		return params.FullStatus{}, errors.Trace(err)
+	}
+	return c.fullStatus(patterns, filters)
+}
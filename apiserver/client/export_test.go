@@ -16,6 +16,38 @@ var (
 	MatchSubnet     = matchSubnet
 )
 
+// ParseStatusFilter parses a structured status filter term, returning
+// any error.
+func ParseStatusFilter(term string) error {
+	_, err := parseStatusFilter(term)
+	return err
+}
+
+// MatchUnitStatus reports whether a unit with the given statuses
+// matches every one of the status, workload, agent and since filter
+// terms.
+func MatchUnitStatus(terms []string, agentStatus, workloadStatus state.StatusInfo) (bool, error) {
+	var filters []statusFilter
+	for _, term := range terms {
+		filter, err := parseStatusFilter(term)
+		if err != nil {
+			return false, err
+		}
+		filters = append(filters, filter)
+	}
+	var named namedStatus
+	for _, filter := range sinceFiltersLast(filters) {
+		matches := filter.matchUnitStatus(agentStatus, workloadStatus, &named)
+		if filter.op == "!=" {
+			matches = !matches
+		}
+		if !matches {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 // Status exports
 var (
 	ProcessMachines   = processMachines
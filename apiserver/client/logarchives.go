@@ -0,0 +1,42 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// LogArchives returns the archives of pruned logs in the environment
+// which hold records from the given time range. Archived logs are
+// downloaded and imported through the logarchive HTTP endpoint.
+func (c *ClientV1) LogArchives(args params.LogArchivesArgs) (params.LogArchivesResult, error) {
+	var from, to time.Time
+	if args.From != nil {
+		from = *args.From
+	}
+	if args.To != nil {
+		to = *args.To
+	}
+	archives, err := c.api.stateAccessor.LogArchives(from, to)
+	if err != nil {
+		return params.LogArchivesResult{}, errors.Trace(err)
+	}
+	result := params.LogArchivesResult{
+		Archives: make([]params.LogArchive, len(archives)),
+	}
+	for i, archive := range archives {
+		result.Archives[i] = params.LogArchive{
+			Id:    archive.Id,
+			Start: archive.Start,
+			End:   archive.End,
+			Count: archive.Count,
+			Size:  archive.Size,
+		}
+	}
+	return result, nil
+}
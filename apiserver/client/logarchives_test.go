@@ -0,0 +1,80 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&logArchivesSuite{})
+
+type logArchivesSuite struct {
+	testing.BaseSuite
+	st  *logArchivesState
+	api *client.ClientV1
+}
+
+func (s *logArchivesSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.st = &logArchivesState{}
+	client.PatchState(s, s.st)
+	authorizer := &apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("user")}
+	var err error
+	s.api, err = client.NewClientV1(nil, nil, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *logArchivesSuite) TestLogArchives(c *gc.C) {
+	t0 := time.Unix(1000, 0)
+	t1 := time.Unix(2000, 0)
+	s.st.archives = []state.LogArchive{{
+		Id:    "a",
+		Start: t0,
+		End:   t1,
+		Count: 10,
+		Size:  100,
+	}}
+	result, err := s.api.LogArchives(params.LogArchivesArgs{From: &t0})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.st.from, gc.Equals, t0)
+	c.Assert(s.st.to.IsZero(), jc.IsTrue)
+	c.Assert(result.Archives, jc.DeepEquals, []params.LogArchive{{
+		Id:    "a",
+		Start: t0,
+		End:   t1,
+		Count: 10,
+		Size:  100,
+	}})
+}
+
+func (s *logArchivesSuite) TestLogArchivesNone(c *gc.C) {
+	result, err := s.api.LogArchives(params.LogArchivesArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Archives, gc.HasLen, 0)
+}
+
+type logArchivesState struct {
+	client.StateInterface
+	archives []state.LogArchive
+	from, to time.Time
+}
+
+func (m *logArchivesState) LogArchives(from, to time.Time) ([]state.LogArchive, error) {
+	m.from, m.to = from, to
+	return m.archives, nil
+}
+
+func (m *logArchivesState) EnvironUUID() string {
+	return "uuid"
+}
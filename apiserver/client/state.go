@@ -4,6 +4,8 @@
 package client
 
 import (
+	"time"
+
 	"github.com/juju/names"
 	"gopkg.in/juju/charm.v6-unstable"
 
@@ -59,6 +61,8 @@ type stateInterface interface {
 	AddEnvironmentUser(user, createdBy names.UserTag, displayName string) (*state.EnvironmentUser, error)
 	RemoveEnvironmentUser(names.UserTag) error
 	Watch() *state.Multiwatcher
+	EnvironStatusHistory(state.StatusHistoryFilter) ([]state.HistoricalStatus, error)
+	LogArchives(from, to time.Time) ([]state.LogArchive, error)
 	AbortCurrentUpgrade() error
 	APIHostPorts() ([][]network.HostPort, error)
 }
@@ -74,3 +78,7 @@ func (s *stateShim) Unit(name string) (Unit, error) {
 	}
 	return u, nil
 }
+
+func (s *stateShim) LogArchives(from, to time.Time) ([]state.LogArchive, error) {
+	return state.LogArchives(s.State, from, to)
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"github.com/juju/utils/set"
 	"gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/juju/charm.v6-unstable/hooks"
@@ -84,16 +85,74 @@ func (c *Client) UnitStatusHistory(args params.StatusHistory) (params.UnitStatus
 	return statuses, nil
 }
 
+// EnvironStatusHistory returns the most recent status history entries
+// of all the machines, services and units in the environment, oldest
+// first.
+func (c *ClientV1) EnvironStatusHistory(args params.EnvironStatusHistoryArgs) (params.EnvironStatusHistoryResult, error) {
+	if args.Size < 1 {
+		return params.EnvironStatusHistoryResult{}, errors.Errorf("invalid history size: %d", args.Size)
+	}
+	filter := state.StatusHistoryFilter{Size: args.Size}
+	if args.From != nil {
+		filter.From = *args.From
+	}
+	if args.To != nil {
+		filter.To = *args.To
+	}
+	for _, status := range args.Statuses {
+		filter.Statuses = append(filter.Statuses, state.Status(status))
+	}
+	history, err := c.api.stateAccessor.EnvironStatusHistory(filter)
+	if err != nil {
+		return params.EnvironStatusHistoryResult{}, errors.Trace(err)
+	}
+	result := params.EnvironStatusHistoryResult{
+		Statuses: make([]params.EntityStatusHistory, len(history)),
+	}
+	for i, h := range history {
+		result.Statuses[i] = params.EntityStatusHistory{
+			Tag:    h.Tag.String(),
+			Kind:   historyKind(h),
+			Status: params.Status(h.Status),
+			Info:   h.Message,
+			Data:   h.Data,
+			Since:  h.Since,
+		}
+	}
+	return result, nil
+}
+
+// historyKind returns the kind of the given status history entry.
+func historyKind(h state.HistoricalStatus) params.HistoryKind {
+	switch h.Tag.Kind() {
+	case names.MachineTagKind:
+		return params.KindMachine
+	case names.ServiceTagKind:
+		return params.KindService
+	}
+	if h.Agent {
+		return params.KindAgent
+	}
+	return params.KindWorkload
+}
+
 // FullStatus gives the information needed for juju status over the api
 func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error) {
+	return c.fullStatus(args.Patterns, nil)
+}
+
+// fullStatus returns the status of the entities matching the patterns
+// and every one of the structured filter terms.
+func (c *Client) fullStatus(patterns []string, filters []statusFilter) (params.FullStatus, error) {
 	cfg, err := c.api.stateAccessor.EnvironConfig()
 	if err != nil {
 		return params.FullStatus{}, errors.Annotate(err, "could not get environ config")
 	}
 	var noStatus params.FullStatus
+	filtered := len(patterns) > 0 || len(filters) > 0
 	var context statusContext
 	if context.services, context.units, context.latestCharms, err =
-		fetchAllServicesAndUnits(c.api.stateAccessor, len(args.Patterns) <= 0); err != nil {
+		fetchAllServicesAndUnits(c.api.stateAccessor, !filtered); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch services and units")
 	} else if context.machines, err = fetchMachines(c.api.stateAccessor, nil); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch machines")
@@ -105,8 +164,15 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 
 	logger.Debugf("Services: %v", context.services)
 
-	if len(args.Patterns) > 0 {
-		predicate := BuildPredicateFor(args.Patterns)
+	if filtered {
+		var predicates []Predicate
+		if len(patterns) > 0 {
+			predicates = append(predicates, BuildPredicateFor(patterns))
+		}
+		if len(filters) > 0 {
+			predicates = append(predicates, buildStatusFilterPredicate(filters, context.machineById))
+		}
+		predicate := andPredicates(predicates...)
 
 		// Filter units
 		unfilteredSvcs := make(set.Strings)
@@ -128,7 +194,12 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 
 				// Track which services are utilized by the units so
 				// that we can be sure to not filter that service out.
+				// The services of subordinates are kept too, as the
+				// subordinates are shown with the unit.
 				unfilteredSvcs.Add(unit.ServiceName())
+				for _, subName := range unit.SubordinateNames() {
+					unfilteredSvcs.Add(strings.Split(subName, "/")[0])
+				}
 				machineId, err := unit.AssignedMachineId()
 				if err != nil {
 					return noStatus, err
@@ -686,6 +757,19 @@ func (context *statusContext) processUnit(unit *state.Unit, serviceCharm string)
 	return result
 }
 
+// machineById returns the machine, which may be a container, with the
+// given id.
+func (context *statusContext) machineById(id string) (*state.Machine, error) {
+	for _, machines := range context.machines {
+		for _, m := range machines {
+			if m.Id() == id {
+				return m, nil
+			}
+		}
+	}
+	return nil, errors.NotFoundf("machine %q", id)
+}
+
 func (context *statusContext) unitByName(name string) *state.Unit {
 	serviceName := strings.Split(name, "/")[0]
 	return context.units[serviceName][name]
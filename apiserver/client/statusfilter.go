@@ -0,0 +1,369 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/state"
+)
+
+// Status filter keys.
+const (
+	filterStatus        = "status"
+	filterWorkload      = "workload"
+	filterAgent         = "agent"
+	filterSince         = "since"
+	filterMachineSeries = "machine-series"
+	filterServiceCharm  = "service-charm"
+)
+
+// statusFilterRegexp matches a structured status filter term such as
+// "workload=blocked" or "since>10m".
+var statusFilterRegexp = regexp.MustCompile(`^([a-z-]+)(!=|>=|<=|=|>|<)(.+)$`)
+
+// statusFilter holds a single term of the structured status filter
+// language. Terms have the form <key><op><value>, where key is one of:
+//
+//	status          unit agent or workload status, or machine status
+//	workload        unit workload status
+//	agent           unit agent status, or machine status
+//	machine-series  series of a machine, or of the machine hosting a unit
+//	service-charm   charm name or URL of a service or of a unit's service
+//	since           time since the status of a unit or machine last changed
+//
+// A unit's since is measured from the status named by the other terms:
+// its workload status for workload terms, its agent status for agent
+// terms, and whichever status matched for status terms. Otherwise it is
+// measured from the workload status.
+//
+// All keys but since are compared with = or != against a comma
+// separated list of values. The since key is compared with <, <=, > or
+// >= against a duration such as 10m.
+type statusFilter struct {
+	key    string
+	op     string
+	values []string
+	age    time.Duration
+}
+
+// isStatusFilter reports whether the given status pattern is a
+// structured filter term rather than a name, status, address or port
+// pattern.
+func isStatusFilter(pattern string) bool {
+	return strings.ContainsAny(pattern, "=<>")
+}
+
+// splitStatusPatterns separates structured filter terms from the
+// remaining status patterns, parsing the filter terms.
+func splitStatusPatterns(patterns []string) ([]string, []statusFilter, error) {
+	var rest []string
+	var filters []statusFilter
+	for _, pattern := range patterns {
+		if !isStatusFilter(pattern) {
+			rest = append(rest, pattern)
+			continue
+		}
+		filter, err := parseStatusFilter(pattern)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		filters = append(filters, filter)
+	}
+	return rest, filters, nil
+}
+
+// parseStatusFilter parses a single structured filter term.
+func parseStatusFilter(term string) (statusFilter, error) {
+	parts := statusFilterRegexp.FindStringSubmatch(term)
+	if parts == nil {
+		return statusFilter{}, invalidStatusFilter(term, "expected <key><op><value>")
+	}
+	filter := statusFilter{
+		key: parts[1],
+		op:  parts[2],
+	}
+	value := parts[3]
+	switch filter.key {
+	case filterSince:
+		switch filter.op {
+		case ">", ">=", "<", "<=":
+		default:
+			return statusFilter{}, invalidStatusFilter(term, "since must be compared with <, <=, > or >=")
+		}
+		age, err := time.ParseDuration(value)
+		if err != nil || age < 0 {
+			return statusFilter{}, invalidStatusFilter(term, "expected a duration such as 10m")
+		}
+		filter.age = age
+		return filter, nil
+	case filterStatus, filterWorkload, filterAgent, filterMachineSeries, filterServiceCharm:
+	default:
+		return statusFilter{}, invalidStatusFilter(term, "unknown key %q", filter.key)
+	}
+	if filter.op != "=" && filter.op != "!=" {
+		return statusFilter{}, invalidStatusFilter(term, "%s must be compared with = or !=", filter.key)
+	}
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		if !validFilterValue(filter.key, v) {
+			return statusFilter{}, invalidStatusFilter(term, "unknown value %q for %s", v, filter.key)
+		}
+		filter.values = append(filter.values, v)
+	}
+	if len(filter.values) == 0 {
+		return statusFilter{}, invalidStatusFilter(term, "no value")
+	}
+	return filter, nil
+}
+
+func invalidStatusFilter(term, format string, args ...interface{}) error {
+	msg := fmt.Sprintf("invalid status filter %q: %s", term, fmt.Sprintf(format, args...))
+	return errors.NewNotValid(nil, msg)
+}
+
+// validFilterValue reports whether the value is acceptable for the
+// given key. Only status values are checked.
+func validFilterValue(key, value string) bool {
+	status := state.Status(value)
+	switch key {
+	case filterStatus:
+		return status.KnownAgentStatus() || status.KnownWorkloadStatus()
+	case filterWorkload:
+		return status.KnownWorkloadStatus()
+	case filterAgent:
+		return status.KnownAgentStatus()
+	}
+	return true
+}
+
+// buildStatusFilterPredicate returns a Predicate which evaluates a
+// machine, service or unit against all of the given filters. The
+// getMachine function is used to find the machine hosting a unit.
+//
+// A machine or service only matches if every filter applies to it
+// directly; filters which only apply to units (such as workload) never
+// match machines or services, which are instead kept in the status
+// because they host or run a matching unit.
+func buildStatusFilterPredicate(filters []statusFilter, getMachine func(id string) (*state.Machine, error)) Predicate {
+	ordered := sinceFiltersLast(filters)
+	return func(i interface{}) (bool, error) {
+		var named namedStatus
+		for _, filter := range ordered {
+			var matches, ok bool
+			var err error
+			switch entity := i.(type) {
+			default:
+				panic(errors.Errorf("Programming error. We should only ever pass in machines, services, or units. Received %T.", i))
+			case *state.Machine:
+				matches, ok, err = filter.matchMachine(entity)
+			case *state.Unit:
+				matches, ok, err = filter.matchUnit(entity, getMachine, &named)
+			case *state.Service:
+				matches, ok, err = filter.matchService(entity)
+			}
+			if err != nil {
+				return false, errors.Trace(err)
+			}
+			if !ok {
+				return false, nil
+			}
+			if filter.op == "!=" {
+				matches = !matches
+			}
+			if !matches {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+}
+
+// sinceFiltersLast returns the filters with the since terms moved to
+// the end. Since terms are measured from the status named by the other
+// terms, so they must be evaluated last.
+func sinceFiltersLast(filters []statusFilter) []statusFilter {
+	var ordered []statusFilter
+	for _, filter := range filters {
+		if filter.key != filterSince {
+			ordered = append(ordered, filter)
+		}
+	}
+	for _, filter := range filters {
+		if filter.key == filterSince {
+			ordered = append(ordered, filter)
+		}
+	}
+	return ordered
+}
+
+// namedStatus records when the unit status named by a filter's status,
+// workload or agent terms was set.
+type namedStatus struct {
+	since *time.Time
+	set   bool
+}
+
+// name records the since time of the status named by a term. Only
+// terms that select a status with = name it.
+func (n *namedStatus) name(f statusFilter, since *time.Time) {
+	if f.op == "=" {
+		n.since = since
+		n.set = true
+	}
+}
+
+// matchUnit reports whether the filter matches the given unit. The
+// second result is false if the filter does not apply to units.
+func (f statusFilter) matchUnit(u *state.Unit, getMachine func(string) (*state.Machine, error), named *namedStatus) (bool, bool, error) {
+	switch f.key {
+	case filterStatus, filterWorkload, filterAgent, filterSince:
+		agentStatus, err := u.AgentStatus()
+		if err != nil {
+			return false, false, errors.Trace(err)
+		}
+		workloadStatus, err := u.Status()
+		if err != nil {
+			return false, false, errors.Trace(err)
+		}
+		return f.matchUnitStatus(agentStatus, workloadStatus, named), true, nil
+	case filterMachineSeries:
+		id, err := u.AssignedMachineId()
+		if errors.IsNotAssigned(err) {
+			return false, true, nil
+		} else if err != nil {
+			return false, false, errors.Trace(err)
+		}
+		m, err := getMachine(id)
+		if err != nil {
+			return false, false, errors.Trace(err)
+		}
+		return matchValue(f.values, m.Series()), true, nil
+	case filterServiceCharm:
+		svc, err := u.Service()
+		if err != nil {
+			return false, false, errors.Trace(err)
+		}
+		return f.matchService(svc)
+	}
+	return false, false, nil
+}
+
+// matchUnitStatus reports whether a status, workload, agent or since
+// filter matches a unit with the given statuses. Status, workload and
+// agent terms record the status they select in named, and since terms
+// are measured from it, or from the workload status if none was
+// named.
+func (f statusFilter) matchUnitStatus(agentStatus, workloadStatus state.StatusInfo, named *namedStatus) bool {
+	switch f.key {
+	case filterStatus:
+		if matches, _, _ := matchAgentStatus(f.values, agentStatus.Status); matches {
+			named.name(f, agentStatus.Since)
+			return true
+		}
+		matches, _, _ := matchWorkloadStatus(f.values, workloadStatus.Status, agentStatus.Status)
+		named.name(f, workloadStatus.Since)
+		return matches
+	case filterWorkload:
+		matches, _, _ := matchWorkloadStatus(f.values, workloadStatus.Status, agentStatus.Status)
+		named.name(f, workloadStatus.Since)
+		return matches
+	case filterAgent:
+		matches, _, _ := matchAgentStatus(f.values, agentStatus.Status)
+		named.name(f, agentStatus.Since)
+		return matches
+	case filterSince:
+		if named.set {
+			return f.matchSince(named.since)
+		}
+		return f.matchSince(workloadStatus.Since)
+	}
+	return false
+}
+
+// matchMachine reports whether the filter matches the given machine.
+// The second result is false if the filter does not apply to
+// machines.
+func (f statusFilter) matchMachine(m *state.Machine) (bool, bool, error) {
+	switch f.key {
+	case filterStatus, filterAgent, filterSince:
+		statusInfo, err := m.Status()
+		if err != nil {
+			return false, false, errors.Trace(err)
+		}
+		if f.key == filterSince {
+			return f.matchSince(statusInfo.Since), true, nil
+		}
+		matches, _, _ := matchAgentStatus(f.values, statusInfo.Status)
+		return matches, true, nil
+	case filterMachineSeries:
+		return matchValue(f.values, m.Series()), true, nil
+	}
+	return false, false, nil
+}
+
+// matchService reports whether the filter matches the given service.
+// The second result is false if the filter does not apply to
+// services.
+func (f statusFilter) matchService(s *state.Service) (bool, bool, error) {
+	if f.key != filterServiceCharm {
+		return false, false, nil
+	}
+	curl, _ := s.CharmURL()
+	if curl == nil {
+		return false, true, nil
+	}
+	return matchValue(f.values, curl.Name, curl.String()), true, nil
+}
+
+// matchSince reports whether the time elapsed since the given status
+// change satisfies the filter.
+func (f statusFilter) matchSince(since *time.Time) bool {
+	if since == nil || since.IsZero() {
+		return false
+	}
+	age := time.Since(*since)
+	switch f.op {
+	case ">":
+		return age > f.age
+	case ">=":
+		return age >= f.age
+	case "<":
+		return age < f.age
+	case "<=":
+		return age <= f.age
+	}
+	return false
+}
+
+func matchValue(values []string, candidates ...string) bool {
+	for _, v := range values {
+		for _, candidate := range candidates {
+			if v == candidate {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// andPredicates returns a Predicate which matches only if all the
+// given predicates match.
+func andPredicates(predicates ...Predicate) Predicate {
+	return func(i interface{}) (bool, error) {
+		for _, p := range predicates {
+			if matches, err := p(i); err != nil || !matches {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+}
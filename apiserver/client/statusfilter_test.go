@@ -0,0 +1,124 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/state"
+)
+
+type statusFilterSuite struct{}
+
+var _ = gc.Suite(&statusFilterSuite{})
+
+func (*statusFilterSuite) TestParseValid(c *gc.C) {
+	for i, term := range []string{
+		"status=error",
+		"status=error,blocked",
+		"status!=active",
+		"workload=blocked",
+		"agent=idle",
+		"since>10m",
+		"since>=1h30m",
+		"since<30s",
+		"since<=0s",
+		"machine-series=trusty,precise",
+		"service-charm=mysql",
+		"service-charm=cs:trusty/mysql-1",
+	} {
+		c.Logf("test %d: %q", i, term)
+		c.Check(client.ParseStatusFilter(term), jc.ErrorIsNil)
+	}
+}
+
+func (*statusFilterSuite) TestParseInvalid(c *gc.C) {
+	for i, test := range []struct {
+		term string
+		err  string
+	}{{
+		term: "=error",
+		err:  `invalid status filter "=error": expected <key><op><value>`,
+	}, {
+		term: "status=",
+		err:  `invalid status filter "status=": expected <key><op><value>`,
+	}, {
+		term: "colour=blue",
+		err:  `invalid status filter "colour=blue": unknown key "colour"`,
+	}, {
+		term: "status>error",
+		err:  `invalid status filter "status>error": status must be compared with = or !=`,
+	}, {
+		term: "status=error,bogus",
+		err:  `invalid status filter "status=error,bogus": unknown value "bogus" for status`,
+	}, {
+		term: "workload=idle",
+		err:  `invalid status filter "workload=idle": unknown value "idle" for workload`,
+	}, {
+		term: "agent=blocked",
+		err:  `invalid status filter "agent=blocked": unknown value "blocked" for agent`,
+	}, {
+		term: "machine-series=,",
+		err:  `invalid status filter "machine-series=,": no value`,
+	}, {
+		term: "since=10m",
+		err:  `invalid status filter "since=10m": since must be compared with <, <=, > or >=`,
+	}, {
+		term: "since>ten",
+		err:  `invalid status filter "since>ten": expected a duration such as 10m`,
+	}} {
+		c.Logf("test %d: %q", i, test.term)
+		err := client.ParseStatusFilter(test.term)
+		c.Check(err, gc.ErrorMatches, test.err)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
+func (*statusFilterSuite) TestSinceMeasuredFromNamedStatus(c *gc.C) {
+	recent := time.Now().Add(-time.Minute)
+	old := time.Now().Add(-2 * time.Hour)
+	agentStatus := state.StatusInfo{Status: state.StatusIdle, Since: &recent}
+	workloadStatus := state.StatusInfo{Status: state.StatusBlocked, Since: &old}
+	for i, test := range []struct {
+		terms   []string
+		matches bool
+	}{{
+		terms:   []string{"workload=blocked", "since>1h"},
+		matches: true,
+	}, {
+		terms:   []string{"since>1h", "workload=blocked"},
+		matches: true,
+	}, {
+		terms:   []string{"status=blocked", "since>1h"},
+		matches: true,
+	}, {
+		terms:   []string{"status=idle", "since>1h"},
+		matches: false,
+	}, {
+		terms:   []string{"status=idle", "since<1h"},
+		matches: true,
+	}, {
+		terms:   []string{"agent=idle", "since>1h"},
+		matches: false,
+	}, {
+		terms:   []string{"agent=idle", "since<1h"},
+		matches: true,
+	}, {
+		terms:   []string{"since>1h"},
+		matches: true,
+	}, {
+		terms:   []string{"agent!=executing", "since>1h"},
+		matches: true,
+	}} {
+		c.Logf("test %d: %v", i, test.terms)
+		matches, err := client.MatchUnitStatus(test.terms, agentStatus, workloadStatus)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(matches, gc.Equals, test.matches)
+	}
+}
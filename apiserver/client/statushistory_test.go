@@ -23,7 +23,7 @@ var _ = gc.Suite(&statusHistoryTestSuite{})
 type statusHistoryTestSuite struct {
 	testing.BaseSuite
 	st  *mockState
-	api *client.Client
+	api *client.ClientV1
 }
 
 func (s *statusHistoryTestSuite) SetUpTest(c *gc.C) {
@@ -32,7 +32,7 @@ func (s *statusHistoryTestSuite) SetUpTest(c *gc.C) {
 	tag := names.NewUserTag("user")
 	authorizer := &apiservertesting.FakeAuthorizer{Tag: tag}
 	var err error
-	s.api, err = client.NewClient(nil, nil, authorizer)
+	s.api, err = client.NewClientV1(nil, nil, authorizer)
 	c.Assert(err, jc.ErrorIsNil)
 }
 
@@ -170,10 +170,78 @@ func (s *statusHistoryTestSuite) TestStatusHistoryCombined(c *gc.C) {
 	checkStatusInfo(c, h.Statuses, expected)
 }
 
+func (s *statusHistoryTestSuite) TestEnvironStatusHistory(c *gc.C) {
+	t0 := time.Unix(1000, 0)
+	t1 := time.Unix(1001, 0)
+	t2 := time.Unix(1002, 0)
+	t3 := time.Unix(1003, 0)
+	s.st.envHistory = []state.HistoricalStatus{{
+		StatusInfo: state.StatusInfo{Status: state.StatusError, Message: "disk full", Since: &t0},
+		Tag:        names.NewMachineTag("0"),
+	}, {
+		StatusInfo: state.StatusInfo{Status: state.StatusBlocked, Since: &t1},
+		Tag:        names.NewServiceTag("mysql"),
+	}, {
+		StatusInfo: state.StatusInfo{Status: state.StatusBlocked, Message: "waiting", Since: &t2},
+		Tag:        names.NewUnitTag("mysql/0"),
+	}, {
+		StatusInfo: state.StatusInfo{Status: state.StatusIdle, Since: &t3},
+		Tag:        names.NewUnitTag("mysql/0"),
+		Agent:      true,
+	}}
+	from := time.Unix(900, 0)
+	h, err := s.api.EnvironStatusHistory(params.EnvironStatusHistoryArgs{
+		Size:     10,
+		From:     &from,
+		Statuses: []params.Status{params.StatusError, params.StatusBlocked, params.StatusIdle},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.st.envHistoryFilter, jc.DeepEquals, state.StatusHistoryFilter{
+		Size:     10,
+		From:     from,
+		Statuses: []state.Status{state.StatusError, state.StatusBlocked, state.StatusIdle},
+	})
+	c.Assert(h.Statuses, jc.DeepEquals, []params.EntityStatusHistory{{
+		Tag:    "machine-0",
+		Kind:   params.KindMachine,
+		Status: params.StatusError,
+		Info:   "disk full",
+		Since:  &t0,
+	}, {
+		Tag:    "service-mysql",
+		Kind:   params.KindService,
+		Status: params.StatusBlocked,
+		Since:  &t1,
+	}, {
+		Tag:    "unit-mysql-0",
+		Kind:   params.KindWorkload,
+		Status: params.StatusBlocked,
+		Info:   "waiting",
+		Since:  &t2,
+	}, {
+		Tag:    "unit-mysql-0",
+		Kind:   params.KindAgent,
+		Status: params.StatusIdle,
+		Since:  &t3,
+	}})
+}
+
+func (s *statusHistoryTestSuite) TestEnvironStatusHistorySizeRequired(c *gc.C) {
+	_, err := s.api.EnvironStatusHistory(params.EnvironStatusHistoryArgs{})
+	c.Assert(err, gc.ErrorMatches, "invalid history size: 0")
+}
+
 type mockState struct {
 	client.StateInterface
-	unitHistory  []state.StatusInfo
-	agentHistory []state.StatusInfo
+	unitHistory      []state.StatusInfo
+	agentHistory     []state.StatusInfo
+	envHistory       []state.HistoricalStatus
+	envHistoryFilter state.StatusHistoryFilter
+}
+
+func (m *mockState) EnvironStatusHistory(filter state.StatusHistoryFilter) ([]state.HistoricalStatus, error) {
+	m.envHistoryFilter = filter
+	return m.envHistory, nil
 }
 
 func (m *mockState) EnvironUUID() string {
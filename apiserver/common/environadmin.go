@@ -0,0 +1,28 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/state"
+)
+
+// HasEnvironAdminAccess returns whether the user may administer the
+// environment: whether they own it or administer the controller.
+func HasEnvironAdminAccess(st *state.State, user names.UserTag) (bool, error) {
+	env, err := st.Environment()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if env.Owner().Canonical() == user.Canonical() {
+		return true, nil
+	}
+	isAdmin, err := st.IsControllerAdministrator(user)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return isAdmin, nil
+}
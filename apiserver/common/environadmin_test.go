@@ -0,0 +1,49 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type environAdminSuite struct {
+	testing.JujuConnSuite
+}
+
+var _ = gc.Suite(&environAdminSuite{})
+
+func (s *environAdminSuite) TestHasEnvironAdminAccess(c *gc.C) {
+	owner := s.Factory.MakeUser(c, &factory.UserParams{NoEnvUser: true}).UserTag()
+	other := s.Factory.MakeUser(c, &factory.UserParams{NoEnvUser: true}).UserTag()
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{Owner: owner})
+	defer st.Close()
+	// Users of the controller's environment administer the
+	// controller.
+	controllerAdmin := s.Factory.MakeUser(c, nil).UserTag()
+
+	for i, test := range []struct {
+		user   names.UserTag
+		expect bool
+	}{{
+		user:   owner,
+		expect: true,
+	}, {
+		user:   controllerAdmin,
+		expect: true,
+	}, {
+		user:   other,
+		expect: false,
+	}} {
+		c.Logf("test %d: %s", i, test.user)
+		ok, err := common.HasEnvironAdminAccess(st, test.user)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(ok, gc.Equals, test.expect)
+	}
+}
@@ -23,6 +23,7 @@ type fakeStorage struct {
 	volumeAttachment       func(names.MachineTag, names.VolumeTag) (state.VolumeAttachment, error)
 	blockDevices           func(names.MachineTag) ([]state.BlockDeviceInfo, error)
 	watchVolumeAttachment  func(names.MachineTag, names.VolumeTag) state.NotifyWatcher
+	watchVolume            func(names.VolumeTag) state.NotifyWatcher
 	watchBlockDevices      func(names.MachineTag) state.NotifyWatcher
 	watchStorageAttachment func(names.StorageTag, names.UnitTag) state.NotifyWatcher
 }
@@ -52,6 +53,11 @@ func (s *fakeStorage) WatchVolumeAttachment(m names.MachineTag, v names.VolumeTa
 	return s.watchVolumeAttachment(m, v)
 }
 
+func (s *fakeStorage) WatchVolume(v names.VolumeTag) state.NotifyWatcher {
+	s.MethodCall(s, "WatchVolume", v)
+	return s.watchVolume(v)
+}
+
 func (s *fakeStorage) WatchBlockDevices(m names.MachineTag) state.NotifyWatcher {
 	s.MethodCall(s, "WatchBlockDevices", m)
 	return s.watchBlockDevices(m)
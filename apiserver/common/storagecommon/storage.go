@@ -51,6 +51,12 @@ type StorageInterface interface {
 	// corresponding to the identfified machine and volume.
 	WatchVolumeAttachment(names.MachineTag, names.VolumeTag) state.NotifyWatcher
 
+	// WatchVolume watches for changes to the specified volume.
+	WatchVolume(names.VolumeTag) state.NotifyWatcher
+
+	// WatchFilesystem watches for changes to the specified filesystem.
+	WatchFilesystem(names.FilesystemTag) state.NotifyWatcher
+
 	// WatchBlockDevices watches for changes to block devices associated
 	// with the specified machine.
 	WatchBlockDevices(names.MachineTag) state.NotifyWatcher
@@ -134,6 +140,7 @@ func volumeStorageAttachmentInfo(
 	return &storage.StorageAttachmentInfo{
 		storage.StorageKindBlock,
 		devicePath,
+		volumeInfo.Size,
 	}, nil
 }
 
@@ -155,15 +162,20 @@ func filesystemStorageAttachmentInfo(
 	if err != nil {
 		return nil, errors.Annotate(err, "getting filesystem attachment info")
 	}
+	filesystemInfo, err := filesystem.Info()
+	if err != nil {
+		return nil, errors.Annotate(err, "getting filesystem info")
+	}
 	return &storage.StorageAttachmentInfo{
 		storage.StorageKindFilesystem,
 		filesystemAttachmentInfo.MountPoint,
+		filesystemInfo.Size,
 	}, nil
 }
 
 // WatchStorageAttachment returns a state.NotifyWatcher that reacts to changes
 // to the VolumeAttachmentInfo or FilesystemAttachmentInfo corresponding to the
-// tags specified.
+// tags specified, and to the size of the underlying volume or filesystem.
 func WatchStorageAttachment(
 	st StorageInterface,
 	storageTag names.StorageTag,
@@ -194,6 +206,7 @@ func WatchStorageAttachment(
 			// or have the filter ignore changes until the volume
 			// attachment is provisioned.
 			st.WatchBlockDevices(machineTag),
+			st.WatchVolume(volume.VolumeTag()),
 		}
 	case state.StorageKindFilesystem:
 		filesystem, err := st.StorageInstanceFilesystem(storageTag)
@@ -202,6 +215,7 @@ func WatchStorageAttachment(
 		}
 		watchers = []state.NotifyWatcher{
 			st.WatchFilesystemAttachment(machineTag, filesystem.FilesystemTag()),
+			st.WatchFilesystem(filesystem.FilesystemTag()),
 		}
 	default:
 		return nil, errors.Errorf("invalid storage kind %v", storageInstance.Kind())
@@ -83,6 +83,7 @@ func (s *storageAttachmentInfoSuite) TestStorageAttachmentInfoPersistentDeviceNa
 	c.Assert(info, jc.DeepEquals, &storage.StorageAttachmentInfo{
 		Kind:     storage.StorageKindBlock,
 		Location: filepath.FromSlash("/dev/sda"),
+		Size:     1024,
 	})
 }
 
@@ -104,6 +105,7 @@ func (s *storageAttachmentInfoSuite) TestStorageAttachmentInfoPersistentDeviceLi
 	c.Assert(info, jc.DeepEquals, &storage.StorageAttachmentInfo{
 		Kind:     storage.StorageKindBlock,
 		Location: "/dev/disk/by-id/verbatim",
+		Size:     1024,
 	})
 }
 
@@ -115,6 +117,7 @@ func (s *storageAttachmentInfoSuite) TestStorageAttachmentInfoPersistentHardware
 	c.Assert(info, jc.DeepEquals, &storage.StorageAttachmentInfo{
 		Kind:     storage.StorageKindBlock,
 		Location: filepath.FromSlash("/dev/disk/by-id/whatever"),
+		Size:     1024,
 	})
 }
 
@@ -135,6 +138,7 @@ func (s *storageAttachmentInfoSuite) TestStorageAttachmentInfoMatchingBlockDevic
 	c.Assert(info, jc.DeepEquals, &storage.StorageAttachmentInfo{
 		Kind:     storage.StorageKindBlock,
 		Location: filepath.FromSlash("/dev/sdb"),
+		Size:     1024,
 	})
 }
 
@@ -157,6 +161,7 @@ type watchStorageAttachmentSuite struct {
 	volume                   *fakeVolume
 	volumeAttachmentWatcher  *fakeNotifyWatcher
 	blockDevicesWatcher      *fakeNotifyWatcher
+	volumeWatcher            *fakeNotifyWatcher
 	storageAttachmentWatcher *fakeNotifyWatcher
 }
 
@@ -174,9 +179,11 @@ func (s *watchStorageAttachmentSuite) SetUpTest(c *gc.C) {
 	s.volume = &fakeVolume{tag: names.NewVolumeTag("0")}
 	s.volumeAttachmentWatcher = &fakeNotifyWatcher{ch: make(chan struct{}, 1)}
 	s.blockDevicesWatcher = &fakeNotifyWatcher{ch: make(chan struct{}, 1)}
+	s.volumeWatcher = &fakeNotifyWatcher{ch: make(chan struct{}, 1)}
 	s.storageAttachmentWatcher = &fakeNotifyWatcher{ch: make(chan struct{}, 1)}
 	s.volumeAttachmentWatcher.ch <- struct{}{}
 	s.blockDevicesWatcher.ch <- struct{}{}
+	s.volumeWatcher.ch <- struct{}{}
 	s.storageAttachmentWatcher.ch <- struct{}{}
 	s.st = &fakeStorage{
 		storageInstance: func(tag names.StorageTag) (state.StorageInstance, error) {
@@ -191,6 +198,9 @@ func (s *watchStorageAttachmentSuite) SetUpTest(c *gc.C) {
 		watchBlockDevices: func(names.MachineTag) state.NotifyWatcher {
 			return s.blockDevicesWatcher
 		},
+		watchVolume: func(names.VolumeTag) state.NotifyWatcher {
+			return s.volumeWatcher
+		},
 		watchStorageAttachment: func(names.StorageTag, names.UnitTag) state.NotifyWatcher {
 			return s.storageAttachmentWatcher
 		},
@@ -215,6 +225,12 @@ func (s *watchStorageAttachmentSuite) TestWatchStorageAttachmentBlockDevicesChan
 	})
 }
 
+func (s *watchStorageAttachmentSuite) TestWatchStorageAttachmentVolumeChanges(c *gc.C) {
+	s.testWatchBlockStorageAttachment(c, func() {
+		s.volumeWatcher.ch <- struct{}{}
+	})
+}
+
 func (s *watchStorageAttachmentSuite) testWatchBlockStorageAttachment(c *gc.C, change func()) {
 	s.testWatchStorageAttachment(c, change)
 	s.st.CheckCallNames(c,
@@ -222,6 +238,7 @@ func (s *watchStorageAttachmentSuite) testWatchBlockStorageAttachment(c *gc.C, c
 		"StorageInstanceVolume",
 		"WatchVolumeAttachment",
 		"WatchBlockDevices",
+		"WatchVolume",
 		"WatchStorageAttachment",
 	)
 }
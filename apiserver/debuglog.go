@@ -8,8 +8,11 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -17,6 +20,7 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/loglabels"
 	"github.com/juju/juju/state"
 )
 
@@ -68,6 +72,18 @@ func newDebugLogHandler(
 //      - has no meaning if 'replay' is true
 //   level -> string one of [TRACE, DEBUG, INFO, WARNING, ERROR]
 //   replay -> string - one of [true, false], if true, start the file from the start
+//   startTime -> string - RFC3339 time; only show lines logged at or after it
+//   endTime -> string - RFC3339 time; only show lines logged at or before it,
+//      and stop once it has passed and they have all been sent
+//   messageRegex -> string - only show lines whose messages match this Go regular
+//      expression
+//   label -> []string - "name=value" pairs; only show lines carrying all of these labels,
+//      such as "hook=install"
+//   format -> string - one of [text, json]; with json, each line is a JSON-encoded
+//      params.DebugLogRecord
+//
+// The startTime, endTime, messageRegex, label and format arguments are
+// only supported when logs are stored in the database.
 func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	server := websocket.Server{
 		Handler: func(conn *websocket.Conn) {
@@ -150,6 +166,11 @@ type debugLogParams struct {
 	excludeEntity []string
 	includeModule []string
 	excludeModule []string
+	startTime     time.Time
+	endTime       time.Time
+	messageRegex  string
+	labels        map[string]string
+	jsonFormat    bool
 }
 
 func readDebugLogParams(queryMap url.Values) (*debugLogParams, error) {
@@ -189,6 +210,48 @@ func readDebugLogParams(queryMap url.Values) (*debugLogParams, error) {
 		params.filterLevel = level
 	}
 
+	if value := queryMap.Get("startTime"); value != "" {
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, errors.Errorf("startTime value %q is not a valid RFC3339 time", value)
+		}
+		params.startTime = t
+	}
+
+	if value := queryMap.Get("endTime"); value != "" {
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, errors.Errorf("endTime value %q is not a valid RFC3339 time", value)
+		}
+		params.endTime = t
+	}
+
+	if value := queryMap.Get("messageRegex"); value != "" {
+		if _, err := regexp.Compile(value); err != nil {
+			return nil, errors.Errorf("messageRegex value %q is not a valid regular expression", value)
+		}
+		params.messageRegex = value
+	}
+
+	for _, value := range queryMap["label"] {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || loglabels.ValidateName(parts[0]) != nil {
+			return nil, errors.Errorf("label value %q is not of the form name=value", value)
+		}
+		if params.labels == nil {
+			params.labels = make(map[string]string)
+		}
+		params.labels[parts[0]] = parts[1]
+	}
+
+	switch value := queryMap.Get("format"); value {
+	case "", "text":
+	case "json":
+		params.jsonFormat = true
+	default:
+		return nil, errors.Errorf("format value %q is not one of %q, %q", value, "text", "json")
+	}
+
 	params.includeEntity = queryMap["includeEntity"]
 	params.excludeEntity = queryMap["excludeEntity"]
 	params.includeModule = queryMap["includeModule"]
@@ -4,12 +4,14 @@
 package apiserver
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/juju/errors"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
 )
 
@@ -40,8 +42,11 @@ func handleDebugLogDBRequest(
 				return errors.Annotate(tailer.Err(), "tailer stopped")
 			}
 
-			line := formatLogRecord(rec)
-			_, err := socket.Write([]byte(line))
+			line, err := formatDebugLogRecord(rec, reqParams.jsonFormat)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			_, err = socket.Write([]byte(line))
 			if err != nil {
 				return errors.Annotate(err, "sending failed")
 			}
@@ -58,12 +63,16 @@ func handleDebugLogDBRequest(
 
 func makeLogTailerParams(reqParams *debugLogParams) *state.LogTailerParams {
 	params := &state.LogTailerParams{
+		StartTime:     reqParams.startTime,
+		EndTime:       reqParams.endTime,
+		MessageRegex:  reqParams.messageRegex,
 		MinLevel:      reqParams.filterLevel,
 		InitialLines:  int(reqParams.backlog),
 		IncludeEntity: reqParams.includeEntity,
 		ExcludeEntity: reqParams.excludeEntity,
 		IncludeModule: reqParams.includeModule,
 		ExcludeModule: reqParams.excludeModule,
+		Labels:        reqParams.labels,
 	}
 	if reqParams.fromTheStart {
 		params.InitialLines = 0
@@ -72,16 +81,44 @@ func makeLogTailerParams(reqParams *debugLogParams) *state.LogTailerParams {
 }
 
 func formatLogRecord(r *state.LogRecord) string {
+	location := r.Location
+	if r.Imported {
+		// Distinguish records imported from a log archive from
+		// those logged by the agents.
+		location += " (imported)"
+	}
 	return fmt.Sprintf("%s: %s %s %s %s %s\n",
 		r.Entity,
 		formatTime(r.Time),
 		r.Level.String(),
 		r.Module,
-		r.Location,
+		location,
 		r.Message,
 	)
 }
 
+// formatDebugLogRecord returns the line sent to clients for the
+// record, either as text or as JSON holding all of its fields.
+func formatDebugLogRecord(r *state.LogRecord, asJSON bool) (string, error) {
+	if !asJSON {
+		return formatLogRecord(r), nil
+	}
+	data, err := json.Marshal(params.DebugLogRecord{
+		Time:     r.Time.UTC(),
+		Entity:   r.Entity,
+		Module:   r.Module,
+		Location: r.Location,
+		Level:    r.Level.String(),
+		Message:  r.Message,
+		Labels:   r.Labels,
+		Imported: r.Imported,
+	})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return string(data) + "\n", nil
+}
+
 func formatTime(t time.Time) string {
 	return t.In(time.UTC).Format("2006-01-02 15:04:05")
 }
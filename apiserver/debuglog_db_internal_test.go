@@ -28,6 +28,8 @@ func (s *debugLogDBIntSuite) SetUpTest(c *gc.C) {
 }
 
 func (s *debugLogDBIntSuite) TestParamConversion(c *gc.C) {
+	startTime := time.Date(2015, 6, 19, 15, 0, 0, 0, time.UTC)
+	endTime := time.Date(2015, 6, 19, 16, 0, 0, 0, time.UTC)
 	reqParams := &debugLogParams{
 		fromTheStart:  false,
 		backlog:       11,
@@ -36,22 +38,26 @@ func (s *debugLogDBIntSuite) TestParamConversion(c *gc.C) {
 		includeModule: []string{"bar"},
 		excludeEntity: []string{"baz"},
 		excludeModule: []string{"qux"},
+		startTime:     startTime,
+		endTime:       endTime,
+		messageRegex:  "fail(ed|ure)",
+		labels:        map[string]string{"hook": "install"},
 	}
 
 	called := false
 	s.PatchValue(&newLogTailer, func(_ state.LoggingState, params *state.LogTailerParams) state.LogTailer {
 		called = true
 
-		// Start time will be used once the client is extended to send
-		// time range arguments.
-		c.Assert(params.StartTime.IsZero(), jc.IsTrue)
-
+		c.Assert(params.StartTime, gc.Equals, startTime)
+		c.Assert(params.EndTime, gc.Equals, endTime)
+		c.Assert(params.MessageRegex, gc.Equals, "fail(ed|ure)")
 		c.Assert(params.MinLevel, gc.Equals, loggo.INFO)
 		c.Assert(params.InitialLines, gc.Equals, 11)
 		c.Assert(params.IncludeEntity, jc.DeepEquals, []string{"foo"})
 		c.Assert(params.IncludeModule, jc.DeepEquals, []string{"bar"})
 		c.Assert(params.ExcludeEntity, jc.DeepEquals, []string{"baz"})
 		c.Assert(params.ExcludeModule, jc.DeepEquals, []string{"qux"})
+		c.Assert(params.Labels, jc.DeepEquals, map[string]string{"hook": "install"})
 
 		return newFakeLogTailer()
 	})
@@ -123,6 +129,82 @@ func (s *debugLogDBIntSuite) TestFullRequest(c *gc.C) {
 	s.assertStops(c, done, tailer)
 }
 
+func (s *debugLogDBIntSuite) TestJSONFormat(c *gc.C) {
+	tailer := newFakeLogTailer()
+	tailer.logsCh <- &state.LogRecord{
+		Time:     time.Date(2015, 6, 19, 15, 34, 37, 123000000, time.UTC),
+		Entity:   "machine-99",
+		Module:   "some.where",
+		Location: "code.go:42",
+		Level:    loggo.INFO,
+		Message:  "stuff \"happened\"",
+	}
+	s.PatchValue(&newLogTailer, func(_ state.LoggingState, params *state.LogTailerParams) state.LogTailer {
+		return tailer
+	})
+
+	stop := make(chan struct{})
+	done := s.runRequest(&debugLogParams{jsonFormat: true}, stop)
+
+	s.assertOutput(c, []string{
+		"ok",
+		`{"time":"2015-06-19T15:34:37.123Z","entity":"machine-99","module":"some.where",` +
+			`"location":"code.go:42","level":"INFO","message":"stuff \"happened\""}` + "\n",
+	})
+
+	close(stop)
+	s.assertStops(c, done, tailer)
+}
+
+func (s *debugLogDBIntSuite) TestJSONFormatLabels(c *gc.C) {
+	tailer := newFakeLogTailer()
+	tailer.logsCh <- &state.LogRecord{
+		Time:     time.Date(2015, 6, 19, 15, 34, 37, 123000000, time.UTC),
+		Entity:   "unit-mysql-0",
+		Module:   "unit.mysql/0.install",
+		Location: "install:1",
+		Level:    loggo.INFO,
+		Message:  "installing",
+		Labels:   map[string]string{"hook": "install"},
+	}
+	s.PatchValue(&newLogTailer, func(_ state.LoggingState, params *state.LogTailerParams) state.LogTailer {
+		return tailer
+	})
+
+	stop := make(chan struct{})
+	done := s.runRequest(&debugLogParams{jsonFormat: true}, stop)
+
+	s.assertOutput(c, []string{
+		"ok",
+		`{"time":"2015-06-19T15:34:37.123Z","entity":"unit-mysql-0","module":"unit.mysql/0.install",` +
+			`"location":"install:1","level":"INFO","message":"installing","labels":{"hook":"install"}}` + "\n",
+	})
+
+	close(stop)
+	s.assertStops(c, done, tailer)
+}
+
+func (s *debugLogDBIntSuite) TestImportedRecords(c *gc.C) {
+	record := &state.LogRecord{
+		Time:     time.Date(2015, 6, 19, 15, 34, 37, 0, time.UTC),
+		Entity:   "machine-99",
+		Module:   "some.where",
+		Location: "code.go:42",
+		Level:    loggo.INFO,
+		Message:  "stuff happened",
+		Imported: true,
+	}
+
+	line, err := formatDebugLogRecord(record, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(line, gc.Equals, "machine-99: 2015-06-19 15:34:37 INFO some.where code.go:42 (imported) stuff happened\n")
+
+	line, err = formatDebugLogRecord(record, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(line, gc.Equals, `{"time":"2015-06-19T15:34:37Z","entity":"machine-99","module":"some.where",`+
+		`"location":"code.go:42","level":"INFO","message":"stuff happened","imported":true}`+"\n")
+}
+
 func (s *debugLogDBIntSuite) TestRequestStopsWhenTailerStops(c *gc.C) {
 	tailer := newFakeLogTailer()
 	s.PatchValue(&newLogTailer, func(_ state.LoggingState, params *state.LogTailerParams) state.LogTailer {
@@ -12,6 +12,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 	"github.com/juju/utils/tailer"
@@ -35,6 +36,14 @@ func (h *debugLogFileHandler) handle(
 	socket debugLogSocket,
 	stop <-chan struct{},
 ) error {
+	// Only the database-backed handler can filter by time, message
+	// and labels, or send full records as JSON.
+	if !params.startTime.IsZero() || !params.endTime.IsZero() || params.messageRegex != "" ||
+		len(params.labels) > 0 || params.jsonFormat {
+		err := errors.NotSupportedf("time range, message regex, labels and JSON output with log files")
+		socket.sendError(err)
+		return err
+	}
 	stream := newLogFileStream(params)
 
 	// Open log file.
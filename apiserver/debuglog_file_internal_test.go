@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -352,6 +353,46 @@ func (s *debugLogFileIntSuite) TestParamErrors(c *gc.C) {
 
 	_, err = readDebugLogParams(url.Values{"level": []string{"foo"}})
 	c.Assert(err, gc.ErrorMatches, `level value "foo" is not one of "TRACE", "DEBUG", "INFO", "WARNING", "ERROR"`)
+
+	_, err = readDebugLogParams(url.Values{"startTime": []string{"foo"}})
+	c.Assert(err, gc.ErrorMatches, `startTime value "foo" is not a valid RFC3339 time`)
+
+	_, err = readDebugLogParams(url.Values{"endTime": []string{"foo"}})
+	c.Assert(err, gc.ErrorMatches, `endTime value "foo" is not a valid RFC3339 time`)
+
+	_, err = readDebugLogParams(url.Values{"messageRegex": []string{"("}})
+	c.Assert(err, gc.ErrorMatches, `messageRegex value "\(" is not a valid regular expression`)
+
+	_, err = readDebugLogParams(url.Values{"format": []string{"yaml"}})
+	c.Assert(err, gc.ErrorMatches, `format value "yaml" is not one of "text", "json"`)
+
+	_, err = readDebugLogParams(url.Values{"label": []string{"hook"}})
+	c.Assert(err, gc.ErrorMatches, `label value "hook" is not of the form name=value`)
+
+	_, err = readDebugLogParams(url.Values{"label": []string{"f.hook=install"}})
+	c.Assert(err, gc.ErrorMatches, `label value "f.hook=install" is not of the form name=value`)
+}
+
+func (s *debugLogFileIntSuite) TestLabelParams(c *gc.C) {
+	params, err := readDebugLogParams(url.Values{
+		"label": []string{"hook=db-relation-joined", "relation=1", "request=a=b"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(params.labels, jc.DeepEquals, map[string]string{
+		"hook":     "db-relation-joined",
+		"relation": "1",
+		"request":  "a=b",
+	})
+}
+
+func (s *debugLogFileIntSuite) TestDatabaseOnlyParams(c *gc.C) {
+	params, err := readDebugLogParams(url.Values{"format": []string{"json"}})
+	c.Assert(err, jc.ErrorIsNil)
+	sock := newFakeDebugLogSocket()
+	handler := &debugLogFileHandler{logDir: c.MkDir()}
+	err = handler.handle(nil, params, sock, nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(<-sock.writes, gc.Equals, "err: time range, message regex, labels and JSON output with log files not supported")
 }
 
 type agentMatchTest struct {
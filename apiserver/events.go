@@ -0,0 +1,155 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+// eventsHeartbeatInterval is the interval between comments sent to
+// keep idle event streams, and any proxies in front of them, alive.
+var eventsHeartbeatInterval = 30 * time.Second
+
+// eventsHandler streams changes to an environment as Server-Sent
+// Events.
+type eventsHandler struct {
+	ctxt httpContext
+	stop <-chan struct{}
+}
+
+func newEventsHandler(ctxt httpContext, stop <-chan struct{}) *eventsHandler {
+	return &eventsHandler{
+		ctxt: ctxt,
+		stop: stop,
+	}
+}
+
+// ServeHTTP streams the changes seen by an environment's allwatcher as
+// a text/event-stream. Each batch of changes is sent as a "deltas"
+// event whose data is the JSON-encoded list of deltas and whose id is
+// the position of the stream after the batch.
+//
+// A client may resume a stream by passing the id of the last event it
+// received in the Last-Event-ID header or the "position" query
+// parameter. If the position cannot be resumed, a "reset" event is sent
+// and the stream starts again with the entire environment.
+func (h *eventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	st, _, err := h.ctxt.stateForRequestAuthenticatedUser(r)
+	if err != nil {
+		sendError(w, err)
+		return
+	}
+	switch r.Method {
+	case "GET":
+		if err := h.processGet(w, r, st); err != nil {
+			logger.Errorf("GET(%s) failed: %v", r.URL, err)
+		}
+	default:
+		sendError(w, errors.MethodNotAllowedf("unsupported method: %q", r.Method))
+	}
+}
+
+// processGet handles an events GET request.
+func (h *eventsHandler) processGet(w http.ResponseWriter, r *http.Request, st *state.State) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		err := errors.NotSupportedf("streaming")
+		sendError(w, err)
+		return err
+	}
+	position := r.Header.Get("Last-Event-ID")
+	if position == "" {
+		position = r.URL.Query().Get("position")
+	}
+	watcher, resumed, err := st.WatchFrom(position)
+	if errors.IsNotValid(err) {
+		err = errors.NewBadRequest(err, "")
+	}
+	if err != nil {
+		sendError(w, err)
+		return errors.Trace(err)
+	}
+	defer watcher.Stop()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if position != "" && !resumed {
+		if err := writeEvent(w, "", "reset", "{}"); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	flusher.Flush()
+
+	// Next blocks, so run it in its own goroutine. Only one call is
+	// outstanding at a time, so Position may be read after each
+	// result is received. The channel is buffered so that the
+	// goroutine can exit once the watcher is stopped, even if no
+	// one is left to receive its result.
+	type nextResult struct {
+		deltas []multiwatcher.Delta
+		err    error
+	}
+	results := make(chan nextResult, 1)
+	next := func() {
+		deltas, err := watcher.Next()
+		results <- nextResult{deltas, err}
+	}
+	go next()
+
+	var closed <-chan bool
+	if notifier, ok := w.(http.CloseNotifier); ok {
+		closed = notifier.CloseNotify()
+	}
+	heartbeat := time.NewTicker(eventsHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-h.stop:
+			return nil
+		case <-closed:
+			return nil
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return errors.Trace(err)
+			}
+		case result := <-results:
+			if result.err == state.ErrStopped {
+				return nil
+			} else if result.err != nil {
+				return errors.Trace(result.err)
+			}
+			data, err := json.Marshal(result.deltas)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if err := writeEvent(w, watcher.Position(), "deltas", string(data)); err != nil {
+				return errors.Trace(err)
+			}
+			go next()
+		}
+		flusher.Flush()
+	}
+}
+
+// writeEvent writes a single Server-Sent Event. The data must not
+// contain newlines.
+func writeEvent(w io.Writer, id, event, data string) error {
+	if id != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
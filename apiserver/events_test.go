@@ -0,0 +1,149 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+type eventsSuite struct {
+	authHttpSuite
+}
+
+var _ = gc.Suite(&eventsSuite{})
+
+func (s *eventsSuite) eventsURL(c *gc.C, position string) string {
+	uri := s.baseURL(c)
+	uri.Path = fmt.Sprintf("/environment/%s/events", s.envUUID)
+	if position != "" {
+		uri.RawQuery = "position=" + position
+	}
+	return uri.String()
+}
+
+// event holds a single Server-Sent Event.
+type event struct {
+	id    string
+	event string
+	data  string
+}
+
+func readEvent(c *gc.C, reader *bufio.Reader) event {
+	var ev event
+	for {
+		line, err := reader.ReadString('\n')
+		c.Assert(err, jc.ErrorIsNil)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			if ev.event != "" {
+				return ev
+			}
+		case strings.HasPrefix(line, ":"):
+			// Comment used as a heartbeat.
+		case strings.HasPrefix(line, "id: "):
+			ev.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			ev.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			ev.data = strings.TrimPrefix(line, "data: ")
+		default:
+			c.Fatalf("unexpected line %q", line)
+		}
+	}
+}
+
+func (s *eventsSuite) openStream(c *gc.C, p httpRequestParams) (*http.Response, *bufio.Reader) {
+	if p.method == "" {
+		p.method = "GET"
+	}
+	resp := s.authRequest(c, p)
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), gc.Equals, "text/event-stream")
+	return resp, bufio.NewReader(resp.Body)
+}
+
+func readDeltas(c *gc.C, reader *bufio.Reader) (string, []multiwatcher.Delta) {
+	ev := readEvent(c, reader)
+	c.Assert(ev.event, gc.Equals, "deltas")
+	c.Assert(ev.id, gc.Not(gc.Equals), "")
+	var deltas []multiwatcher.Delta
+	err := json.Unmarshal([]byte(ev.data), &deltas)
+	c.Assert(err, jc.ErrorIsNil)
+	return ev.id, deltas
+}
+
+func machineIds(deltas []multiwatcher.Delta) []string {
+	var ids []string
+	for _, d := range deltas {
+		if m, ok := d.Entity.(*multiwatcher.MachineInfo); ok {
+			ids = append(ids, m.Id)
+		}
+	}
+	return ids
+}
+
+func (s *eventsSuite) TestRequiresAuth(c *gc.C) {
+	resp := s.sendRequest(c, httpRequestParams{method: "GET", url: s.eventsURL(c, "")})
+	body := assertResponse(c, resp, http.StatusUnauthorized, params.ContentTypeJSON)
+	c.Assert(string(body), jc.Contains, "no credentials provided")
+}
+
+func (s *eventsSuite) TestRequiresGET(c *gc.C) {
+	resp := s.authRequest(c, httpRequestParams{method: "POST", url: s.eventsURL(c, "")})
+	body := assertResponse(c, resp, http.StatusMethodNotAllowed, params.ContentTypeJSON)
+	c.Assert(string(body), jc.Contains, `unsupported method: \"POST\"`)
+}
+
+func (s *eventsSuite) TestInvalidPosition(c *gc.C) {
+	resp := s.authRequest(c, httpRequestParams{method: "GET", url: s.eventsURL(c, "bad")})
+	body := assertResponse(c, resp, http.StatusBadRequest, params.ContentTypeJSON)
+	c.Assert(string(body), jc.Contains, `watcher position \"bad\" not valid`)
+}
+
+func (s *eventsSuite) TestStreamAndResume(c *gc.C) {
+	s.Factory.MakeMachine(c, nil)
+	resp, reader := s.openStream(c, httpRequestParams{url: s.eventsURL(c, "")})
+	position, deltas := readDeltas(c, reader)
+	c.Assert(machineIds(deltas), jc.DeepEquals, []string{"0"})
+	resp.Body.Close()
+
+	s.Factory.MakeMachine(c, nil)
+	s.BackingState.StartSync()
+
+	// The position may be given in the Last-Event-ID header, as
+	// browsers do when reconnecting.
+	client := utils.GetNonValidatingHTTPClient()
+	resp, reader = s.openStream(c, httpRequestParams{
+		url: s.eventsURL(c, ""),
+		do: func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("Last-Event-ID", position)
+			return client.Do(req)
+		},
+	})
+	defer resp.Body.Close()
+	_, deltas = readDeltas(c, reader)
+	c.Assert(machineIds(deltas), jc.DeepEquals, []string{"1"})
+}
+
+func (s *eventsSuite) TestResetOnUnknownPosition(c *gc.C) {
+	s.Factory.MakeMachine(c, nil)
+	resp, reader := s.openStream(c, httpRequestParams{url: s.eventsURL(c, "unknown:1")})
+	defer resp.Body.Close()
+	ev := readEvent(c, reader)
+	c.Assert(ev.event, gc.Equals, "reset")
+	_, deltas := readDeltas(c, reader)
+	c.Assert(machineIds(deltas), jc.DeepEquals, []string{"0"})
+}
@@ -202,3 +202,5 @@ func (logFileLine *logFileLine) LogLineAgentTag() string {
 func (logFileLine *logFileLine) LogLineAgentName() string {
 	return logFileLine.agentName
 }
+
+var MaxLogArchiveUploadSize = &maxLogArchiveUploadSize
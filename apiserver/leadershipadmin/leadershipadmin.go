@@ -0,0 +1,210 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package leadershipadmin provides an API server facade for
+// inspecting service leadership, and for forcibly handing it
+// over when a leader is stuck.
+package leadershipadmin
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.leadershipadmin")
+
+func init() {
+	common.RegisterStandardFacade("LeadershipAdmin", 1, NewAPI)
+}
+
+// LeadershipAdmin defines the methods on the leadership admin API end point.
+type LeadershipAdmin interface {
+	// Leases returns the current leadership of every service that
+	// has a leader.
+	Leases() (params.LeadershipLeasesResult, error)
+
+	// RevokeLeadership forcibly ends the leadership of the given
+	// services.
+	RevokeLeadership(args params.Entities) (params.ErrorResults, error)
+
+	// LeadershipHistory returns the most recent leadership changes of
+	// the given services.
+	LeadershipHistory(args params.LeadershipHistoryQueries) (params.LeadershipHistoryResults, error)
+
+	// WatchLeadershipChanges returns a StringsWatcher that notifies
+	// of the names of services whose leader changes.
+	WatchLeadershipChanges() (params.StringsWatchResult, error)
+}
+
+// API implements the LeadershipAdmin interface and is the concrete
+// implementation of the api end point.
+type API struct {
+	state      leadershipState
+	resources  *common.Resources
+	authorizer common.Authorizer
+}
+
+var _ LeadershipAdmin = (*API)(nil)
+
+// createAPI returns a new leadership admin API facade.
+func createAPI(
+	st leadershipState,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		state:      st,
+		resources:  resources,
+		authorizer: authorizer,
+	}, nil
+}
+
+// NewAPI returns a new leadership admin API facade.
+func NewAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*API, error) {
+	return createAPI(getState(st), resources, authorizer)
+}
+
+// Leases returns the current leadership of every service that has a
+// leader, ordered by service name.
+func (api *API) Leases() (params.LeadershipLeasesResult, error) {
+	leases, err := api.state.LeadershipInspector().Leases()
+	if err != nil {
+		return params.LeadershipLeasesResult{}, common.ServerError(err)
+	}
+	serviceNames := make([]string, 0, len(leases))
+	for serviceName := range leases {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+
+	namespace := api.state.LeadershipNamespace()
+	result := params.LeadershipLeasesResult{
+		Leases: make([]params.LeadershipLease, len(serviceNames)),
+	}
+	for i, serviceName := range serviceNames {
+		lease := leases[serviceName]
+		result.Leases[i] = params.LeadershipLease{
+			Namespace:  namespace,
+			ServiceTag: names.NewServiceTag(serviceName).String(),
+			UnitTag:    names.NewUnitTag(lease.Holder).String(),
+			Expiry:     lease.Expiry,
+		}
+	}
+	return result, nil
+}
+
+// RevokeLeadership forcibly ends the leadership of the given services,
+// so that another unit of each can take over without the current leader's
+// agent being stopped. Only controller administrators may revoke
+// leadership.
+func (api *API) RevokeLeadership(args params.Entities) (params.ErrorResults, error) {
+	if err := api.checkCanRevoke(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	revoker := api.state.LeadershipRevoker()
+	for i, entity := range args.Entities {
+		serviceTag, err := names.ParseServiceTag(entity.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		logger.Infof("revoking leadership of %s on behalf of %s",
+			names.ReadableString(serviceTag),
+			names.ReadableString(api.authorizer.GetAuthTag()),
+		)
+		err = revoker.RevokeLeadership(serviceTag.Id())
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// LeadershipHistory returns the most recent leadership changes of each
+// of the given services, oldest first.
+func (api *API) LeadershipHistory(args params.LeadershipHistoryQueries) (params.LeadershipHistoryResults, error) {
+	results := params.LeadershipHistoryResults{
+		Results: make([]params.LeadershipHistoryResult, len(args.Queries)),
+	}
+	for i, query := range args.Queries {
+		changes, err := api.leadershipHistory(query)
+		results.Results[i].Changes = changes
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *API) leadershipHistory(query params.LeadershipHistoryQuery) ([]params.LeadershipChange, error) {
+	serviceTag, err := names.ParseServiceTag(query.ServiceTag)
+	if err != nil {
+		return nil, err
+	}
+	history, err := api.state.LeadershipHistory(serviceTag.Id(), query.Size)
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]params.LeadershipChange, len(history))
+	for i, change := range history {
+		changes[i] = params.LeadershipChange{
+			ServiceTag: query.ServiceTag,
+			Leader:     names.NewUnitTag(change.Leader).String(),
+			Time:       change.Time,
+		}
+		if change.Previous != "" {
+			changes[i].Previous = names.NewUnitTag(change.Previous).String()
+		}
+	}
+	return changes, nil
+}
+
+// WatchLeadershipChanges returns a StringsWatcher that notifies of the
+// names of services whose leader changes. The initial event is always
+// empty; use LeadershipHistory to read earlier changes.
+func (api *API) WatchLeadershipChanges() (params.StringsWatchResult, error) {
+	result := params.StringsWatchResult{}
+	watch := api.state.WatchLeadershipChanges()
+	// Consume the initial event and forward it to the result.
+	if changes, ok := <-watch.Changes(); ok {
+		result.StringsWatcherId = api.resources.Register(watch)
+		result.Changes = changes
+	} else {
+		err := watcher.EnsureErr(watch)
+		return result, fmt.Errorf("cannot obtain initial leadership changes: %v", err)
+	}
+	return result, nil
+}
+
+// checkCanRevoke returns an error if leadership revocation is blocked,
+// or if the authenticated user is not a controller administrator.
+func (api *API) checkCanRevoke() error {
+	if err := common.NewBlockChecker(api.state).ChangeAllowed(); err != nil {
+		return err
+	}
+	// AuthClient was checked at creation time, so this is a user tag.
+	apiUser, _ := api.authorizer.GetAuthTag().(names.UserTag)
+	isAdmin, err := api.state.IsControllerAdministrator(apiUser)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !isAdmin {
+		return common.ErrPerm
+	}
+	return nil
+}
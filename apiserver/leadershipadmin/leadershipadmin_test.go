@@ -0,0 +1,262 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leadershipadmin_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/leadershipadmin"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/leadership"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type leadershipAdminSuite struct {
+	coretesting.BaseSuite
+
+	authorizer apiservertesting.FakeAuthorizer
+	resources  *common.Resources
+	state      *mockState
+	api        *leadershipadmin.API
+}
+
+var _ = gc.Suite(&leadershipAdminSuite{})
+
+func (s *leadershipAdminSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("admin")}
+	s.state = &mockState{
+		leases: map[string]leadership.Lease{
+			"wordpress": {Holder: "wordpress/1", Expiry: time.Unix(200, 0)},
+			"mysql":     {Holder: "mysql/0", Expiry: time.Unix(100, 0)},
+		},
+		history: map[string][]state.LeadershipChange{
+			"mysql": {{
+				ServiceName: "mysql",
+				Leader:      "mysql/0",
+				Time:        time.Unix(10, 0),
+			}, {
+				ServiceName: "mysql",
+				Previous:    "mysql/0",
+				Leader:      "mysql/1",
+				Time:        time.Unix(20, 0),
+			}},
+		},
+		admin: true,
+	}
+	s.resources = common.NewResources()
+	s.AddCleanup(func(*gc.C) { s.resources.StopAll() })
+	var err error
+	s.api, err = leadershipadmin.CreateAPI(s.state, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *leadershipAdminSuite) TestNewAPIRequiresClient(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{Tag: names.NewUnitTag("mysql/0")}
+	_, err := leadershipadmin.CreateAPI(s.state, common.NewResources(), authorizer)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *leadershipAdminSuite) TestLeases(c *gc.C) {
+	result, err := s.api.Leases()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.LeadershipLeasesResult{
+		Leases: []params.LeadershipLease{{
+			Namespace:  "service-leadership",
+			ServiceTag: "service-mysql",
+			UnitTag:    "unit-mysql-0",
+			Expiry:     time.Unix(100, 0),
+		}, {
+			Namespace:  "service-leadership",
+			ServiceTag: "service-wordpress",
+			UnitTag:    "unit-wordpress-1",
+			Expiry:     time.Unix(200, 0),
+		}},
+	})
+}
+
+func (s *leadershipAdminSuite) TestLeasesError(c *gc.C) {
+	s.state.err = errors.New("boom")
+	_, err := s.api.Leases()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *leadershipAdminSuite) TestRevokeLeadership(c *gc.C) {
+	results, err := s.api.RevokeLeadership(params.Entities{Entities: []params.Entity{
+		{Tag: "service-mysql"},
+		{Tag: "service-postgresql"},
+		{Tag: "unit-mysql-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `leader of service "postgresql" not found`)
+	c.Assert(results.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `"unit-mysql-0" is not a valid service tag`)
+	c.Assert(s.state.revoked, jc.DeepEquals, []string{"mysql", "postgresql"})
+}
+
+func (s *leadershipAdminSuite) TestRevokeLeadershipNotAdmin(c *gc.C) {
+	s.state.admin = false
+	_, err := s.api.RevokeLeadership(params.Entities{Entities: []params.Entity{{Tag: "service-mysql"}}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(s.state.revoked, gc.HasLen, 0)
+}
+
+func (s *leadershipAdminSuite) TestRevokeLeadershipBlocked(c *gc.C) {
+	s.state.blocked = true
+	_, err := s.api.RevokeLeadership(params.Entities{Entities: []params.Entity{{Tag: "service-mysql"}}})
+	c.Assert(params.IsCodeOperationBlocked(err), jc.IsTrue)
+	c.Assert(err, gc.ErrorMatches, "TestRevokeLeadershipBlocked")
+	c.Assert(s.state.revoked, gc.HasLen, 0)
+}
+
+func (s *leadershipAdminSuite) TestLeadershipHistory(c *gc.C) {
+	results, err := s.api.LeadershipHistory(params.LeadershipHistoryQueries{
+		Queries: []params.LeadershipHistoryQuery{
+			{ServiceTag: "service-mysql", Size: 10},
+			{ServiceTag: "service-wordpress", Size: 10},
+			{ServiceTag: "unit-mysql-0", Size: 10},
+			{ServiceTag: "service-mysql", Size: 0},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.LeadershipHistoryResults{
+		Results: []params.LeadershipHistoryResult{{
+			Changes: []params.LeadershipChange{{
+				ServiceTag: "service-mysql",
+				Leader:     "unit-mysql-0",
+				Time:       time.Unix(10, 0),
+			}, {
+				ServiceTag: "service-mysql",
+				Previous:   "unit-mysql-0",
+				Leader:     "unit-mysql-1",
+				Time:       time.Unix(20, 0),
+			}},
+		}, {
+			Changes: []params.LeadershipChange{},
+		}, {
+			Error: &params.Error{Message: `"unit-mysql-0" is not a valid service tag`},
+		}, {
+			Error: &params.Error{Message: "history size 0 not valid"},
+		}},
+	})
+}
+
+func (s *leadershipAdminSuite) TestWatchLeadershipChanges(c *gc.C) {
+	result, err := s.api.WatchLeadershipChanges()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringsWatchResult{
+		StringsWatcherId: "1",
+		Changes:          []string{},
+	})
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+}
+
+type mockState struct {
+	leases  map[string]leadership.Lease
+	history map[string][]state.LeadershipChange
+	revoked []string
+	admin   bool
+	blocked bool
+	err     error
+}
+
+func (st *mockState) GetBlockForType(t state.BlockType) (state.Block, bool, error) {
+	if st.blocked && t == state.ChangeBlock {
+		return mockBlock{t, "TestRevokeLeadershipBlocked"}, true, nil
+	}
+	return nil, false, nil
+}
+
+func (st *mockState) LeadershipInspector() leadership.Inspector {
+	return st
+}
+
+func (st *mockState) LeadershipRevoker() leadership.Revoker {
+	return st
+}
+
+func (st *mockState) LeadershipNamespace() string {
+	return "service-leadership"
+}
+
+func (st *mockState) IsControllerAdministrator(user names.UserTag) (bool, error) {
+	return st.admin, nil
+}
+
+func (st *mockState) LeadershipHistory(serviceName string, size int) ([]state.LeadershipChange, error) {
+	if size < 1 {
+		return nil, errors.NotValidf("history size %d", size)
+	}
+	history := st.history[serviceName]
+	if len(history) > size {
+		history = history[len(history)-size:]
+	}
+	return history, nil
+}
+
+func (st *mockState) WatchLeadershipChanges() state.StringsWatcher {
+	changes := make(chan []string, 1)
+	// Simulate initial event.
+	changes <- []string{}
+	return &fakeStringsWatcher{changes}
+}
+
+func (st *mockState) Leases() (map[string]leadership.Lease, error) {
+	return st.leases, st.err
+}
+
+func (st *mockState) RevokeLeadership(serviceName string) error {
+	st.revoked = append(st.revoked, serviceName)
+	if _, ok := st.leases[serviceName]; !ok {
+		return errors.NotFoundf("leader of service %q", serviceName)
+	}
+	delete(st.leases, serviceName)
+	return nil
+}
+
+type mockBlock struct {
+	state.Block
+	t   state.BlockType
+	msg string
+}
+
+func (b mockBlock) Type() state.BlockType {
+	return b.t
+}
+
+func (b mockBlock) Message() string {
+	return b.msg
+}
+
+type fakeStringsWatcher struct {
+	changes chan []string
+}
+
+func (*fakeStringsWatcher) Stop() error {
+	return nil
+}
+
+func (*fakeStringsWatcher) Kill() {}
+
+func (*fakeStringsWatcher) Wait() error {
+	return nil
+}
+
+func (*fakeStringsWatcher) Err() error {
+	return nil
+}
+
+func (w *fakeStringsWatcher) Changes() <-chan []string {
+	return w.changes
+}
@@ -0,0 +1,46 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leadershipadmin
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/leadership"
+	"github.com/juju/juju/state"
+)
+
+// leadershipState defines the state methods that the leadership
+// admin facade uses.
+type leadershipState interface {
+	common.BlockGetter
+
+	// LeadershipInspector returns a leadership.Inspector for services
+	// in the environment.
+	LeadershipInspector() leadership.Inspector
+
+	// LeadershipRevoker returns a leadership.Revoker for services in
+	// the environment.
+	LeadershipRevoker() leadership.Revoker
+
+	// LeadershipNamespace returns the name of the lease namespace in
+	// which service leadership is recorded.
+	LeadershipNamespace() string
+
+	// LeadershipHistory returns at most size of the most recent
+	// leadership changes of the named service, oldest first.
+	LeadershipHistory(serviceName string, size int) ([]state.LeadershipChange, error)
+
+	// WatchLeadershipChanges returns a StringsWatcher that notifies of
+	// the names of services whose leader changes.
+	WatchLeadershipChanges() state.StringsWatcher
+
+	// IsControllerAdministrator returns whether the user has access to
+	// the state server environment.
+	IsControllerAdministrator(user names.UserTag) (bool, error)
+}
+
+var getState = func(st *state.State) leadershipState {
+	return st
+}
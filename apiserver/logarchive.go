@@ -0,0 +1,158 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// maxLogArchiveUploadSize is the maximum size of a compressed log
+// archive which may be imported.
+var maxLogArchiveUploadSize int64 = 64 << 20
+
+// logArchiveHandler handles the download, removal and import of
+// archives of pruned logs.
+type logArchiveHandler struct {
+	ctxt httpContext
+}
+
+// ServeHTTP sends the archive with the id given in the "id" query
+// parameter in response to a GET request and removes it in response
+// to a DELETE request, and imports the archive in the body of a POST
+// request into the environment's logs. Only administrators of the
+// environment may remove or import archives.
+func (h *logArchiveHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	st, entity, err := h.ctxt.stateForRequestAuthenticatedUser(r)
+	if err != nil {
+		sendError(w, err)
+		return
+	}
+	if r.Method == "DELETE" || r.Method == "POST" {
+		if err := checkEnvironAdmin(st, entity.Tag().(names.UserTag)); err != nil {
+			sendError(w, err)
+			return
+		}
+	}
+
+	switch r.Method {
+	case "GET":
+		archive, reader, err := h.processGet(r, st)
+		if err != nil {
+			logger.Errorf("GET(%s) failed: %v", r.URL, err)
+			sendError(w, err)
+			return
+		}
+		defer reader.Close()
+		h.sendArchive(w, archive, reader)
+	case "DELETE":
+		if err := h.processDelete(r, st); err != nil {
+			sendError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	case "POST":
+		count, err := h.processPost(w, r, st)
+		if err != nil {
+			sendError(w, err)
+			return
+		}
+		sendStatusAndJSON(w, http.StatusOK, &params.LogArchiveImportResult{Count: count})
+	default:
+		sendError(w, errors.MethodNotAllowedf("unsupported method: %q", r.Method))
+	}
+}
+
+// checkEnvironAdmin returns an error unless the user may administer
+// the environment.
+func checkEnvironAdmin(st *state.State, user names.UserTag) error {
+	isAdmin, err := common.HasEnvironAdminAccess(st, user)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !isAdmin {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// archiveId returns the id of the log archive named in a request.
+func (h *logArchiveHandler) archiveId(r *http.Request) (string, error) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		return "", errors.BadRequestf("expected id query argument")
+	}
+	return id, nil
+}
+
+// processGet opens the log archive requested by a GET request.
+func (h *logArchiveHandler) processGet(r *http.Request, st *state.State) (state.LogArchive, io.ReadCloser, error) {
+	id, err := h.archiveId(r)
+	if err != nil {
+		return state.LogArchive{}, nil, errors.Trace(err)
+	}
+	archive, reader, err := state.OpenLogArchive(st, id)
+	if err != nil {
+		return state.LogArchive{}, nil, errors.Trace(err)
+	}
+	return archive, reader, nil
+}
+
+// processDelete removes the log archive requested by a DELETE request.
+func (h *logArchiveHandler) processDelete(r *http.Request, st *state.State) error {
+	id, err := h.archiveId(r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	blockChecker := common.NewBlockChecker(st)
+	if err := blockChecker.RemoveAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := state.RemoveLogArchive(st, id); err != nil {
+		return errors.Trace(err)
+	}
+	logger.Infof("removed log archive %q of environment %s", id, st.EnvironUUID())
+	return nil
+}
+
+// processPost imports the log archive in the body of a POST request,
+// returning the number of records imported.
+func (h *logArchiveHandler) processPost(w http.ResponseWriter, r *http.Request, st *state.State) (int, error) {
+	defer r.Body.Close()
+	if ctype := r.Header.Get("Content-Type"); ctype != params.ContentTypeGzip {
+		return 0, errors.BadRequestf("expected Content-Type %q, got %q", params.ContentTypeGzip, ctype)
+	}
+	blockChecker := common.NewBlockChecker(st)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return 0, errors.Trace(err)
+	}
+	body := http.MaxBytesReader(w, r.Body, maxLogArchiveUploadSize)
+	count, err := state.ImportLogArchive(st, body)
+	if errors.IsNotValid(err) {
+		err = errors.NewBadRequest(err, "")
+	}
+	if err != nil {
+		return count, errors.Trace(err)
+	}
+	logger.Infof("imported %d archived logs into environment %s", count, st.EnvironUUID())
+	return count, nil
+}
+
+// sendArchive streams the contents of a log archive.
+func (h *logArchiveHandler) sendArchive(w http.ResponseWriter, archive state.LogArchive, reader io.Reader) {
+	w.Header().Set("Content-Type", params.ContentTypeGzip)
+	w.Header().Set("Content-Length", fmt.Sprint(archive.Size))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, reader); err != nil {
+		logger.Errorf("failed to send log archive %q: %v", archive.Id, err)
+	}
+}
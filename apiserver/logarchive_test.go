@@ -0,0 +1,248 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/apiserver"
+	commontesting "github.com/juju/juju/apiserver/common/testing"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type logArchiveSuite struct {
+	authHttpSuite
+	commontesting.BlockHelper
+}
+
+var _ = gc.Suite(&logArchiveSuite{})
+
+func (s *logArchiveSuite) SetUpTest(c *gc.C) {
+	s.SetInitialFeatureFlags("db-log")
+	s.authHttpSuite.SetUpTest(c)
+	s.BlockHelper = commontesting.NewBlockHelper(s.APIState)
+	s.AddCleanup(func(*gc.C) { s.BlockHelper.Close() })
+}
+
+func (s *logArchiveSuite) logArchiveURL(c *gc.C, id string) string {
+	uri := s.baseURL(c)
+	uri.Path = fmt.Sprintf("/environment/%s/logarchive", s.envUUID)
+	if id != "" {
+		uri.RawQuery = "id=" + id
+	}
+	return uri.String()
+}
+
+// makeArchive archives and prunes two old log records, returning the
+// resulting log archive.
+func (s *logArchiveSuite) makeArchive(c *gc.C) state.LogArchive {
+	return s.makeArchiveForState(c, s.State)
+}
+
+// makeArchiveForState archives and prunes two old log records of the
+// given environment, returning the resulting log archive.
+func (s *logArchiveSuite) makeArchiveForState(c *gc.C, st *state.State) state.LogArchive {
+	dbLogger := state.NewDbLogger(st, names.NewMachineTag("0"))
+	defer dbLogger.Close()
+	old := time.Now().Add(-2 * time.Hour)
+	for _, msg := range []string{"one", "two"} {
+		err := dbLogger.Log(old, "juju.worker", "worker.go:1", loggo.INFO, msg)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	policies := map[string]state.LogPrunePolicy{
+		st.EnvironUUID(): {Archive: true},
+	}
+	err := state.PruneLogs(st, time.Now().Add(-time.Hour), 100, policies)
+	c.Assert(err, jc.ErrorIsNil)
+	archives, err := state.LogArchives(st, time.Time{}, time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(archives, gc.HasLen, 1)
+	return archives[0]
+}
+
+func (s *logArchiveSuite) TestRequiresAuth(c *gc.C) {
+	resp := s.sendRequest(c, httpRequestParams{method: "GET", url: s.logArchiveURL(c, "")})
+	body := assertResponse(c, resp, http.StatusUnauthorized, params.ContentTypeJSON)
+	c.Assert(string(body), jc.Contains, "no credentials provided")
+}
+
+func (s *logArchiveSuite) TestRequiresGETOrPOST(c *gc.C) {
+	resp := s.authRequest(c, httpRequestParams{method: "PUT", url: s.logArchiveURL(c, "")})
+	body := assertResponse(c, resp, http.StatusMethodNotAllowed, params.ContentTypeJSON)
+	c.Assert(string(body), jc.Contains, `unsupported method: \"PUT\"`)
+}
+
+func (s *logArchiveSuite) TestRemove(c *gc.C) {
+	archive := s.makeArchive(c)
+
+	resp := s.authRequest(c, httpRequestParams{method: "DELETE", url: s.logArchiveURL(c, archive.Id)})
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	archives, err := state.LogArchives(s.State, time.Time{}, time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(archives, gc.HasLen, 0)
+
+	resp = s.authRequest(c, httpRequestParams{method: "DELETE", url: s.logArchiveURL(c, archive.Id)})
+	body := assertResponse(c, resp, http.StatusNotFound, params.ContentTypeJSON)
+	c.Assert(string(body), jc.Contains, fmt.Sprintf(`log archive \"%s\" not found`, archive.Id))
+}
+
+func (s *logArchiveSuite) TestRemoveRequiresEnvironAdmin(c *gc.C) {
+	// The user of another environment neither owns it nor
+	// administers the controller.
+	st := s.setupOtherEnvironment(c)
+	archive := s.makeArchiveForState(c, st)
+
+	// Users of the environment may download archives...
+	resp := s.authRequest(c, httpRequestParams{method: "GET", url: s.logArchiveURL(c, archive.Id)})
+	assertResponse(c, resp, http.StatusOK, params.ContentTypeGzip)
+
+	// ...but only administrators may remove them.
+	resp = s.authRequest(c, httpRequestParams{method: "DELETE", url: s.logArchiveURL(c, archive.Id)})
+	body := assertResponse(c, resp, http.StatusUnauthorized, params.ContentTypeJSON)
+	c.Assert(string(body), jc.Contains, "permission denied")
+	archives, err := state.LogArchives(st, time.Time{}, time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(archives, gc.HasLen, 1)
+}
+
+func (s *logArchiveSuite) TestRemoveBlocked(c *gc.C) {
+	archive := s.makeArchive(c)
+	s.BlockRemoveObject(c, "TestRemoveBlocked")
+
+	resp := s.authRequest(c, httpRequestParams{method: "DELETE", url: s.logArchiveURL(c, archive.Id)})
+	body := assertResponse(c, resp, http.StatusBadRequest, params.ContentTypeJSON)
+	var result params.ErrorResult
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AssertBlocked(c, result.Error, "TestRemoveBlocked")
+	archives, err := state.LogArchives(s.State, time.Time{}, time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(archives, gc.HasLen, 1)
+}
+
+func (s *logArchiveSuite) TestRemoveRequiresId(c *gc.C) {
+	resp := s.authRequest(c, httpRequestParams{method: "DELETE", url: s.logArchiveURL(c, "")})
+	body := assertResponse(c, resp, http.StatusBadRequest, params.ContentTypeJSON)
+	c.Assert(string(body), jc.Contains, "expected id query argument")
+}
+
+func (s *logArchiveSuite) TestDownloadRequiresId(c *gc.C) {
+	resp := s.authRequest(c, httpRequestParams{method: "GET", url: s.logArchiveURL(c, "")})
+	body := assertResponse(c, resp, http.StatusBadRequest, params.ContentTypeJSON)
+	c.Assert(string(body), jc.Contains, "expected id query argument")
+}
+
+func (s *logArchiveSuite) TestDownloadNotFound(c *gc.C) {
+	id := bson.NewObjectId().Hex()
+	resp := s.authRequest(c, httpRequestParams{method: "GET", url: s.logArchiveURL(c, id)})
+	body := assertResponse(c, resp, http.StatusNotFound, params.ContentTypeJSON)
+	c.Assert(string(body), jc.Contains, fmt.Sprintf(`log archive \"%s\" not found`, id))
+}
+
+func (s *logArchiveSuite) TestDownloadAndImport(c *gc.C) {
+	archive := s.makeArchive(c)
+
+	resp := s.authRequest(c, httpRequestParams{method: "GET", url: s.logArchiveURL(c, archive.Id)})
+	data := assertResponse(c, resp, http.StatusOK, params.ContentTypeGzip)
+	c.Assert(int64(len(data)), gc.Equals, archive.Size)
+
+	resp = s.authRequest(c, httpRequestParams{
+		method:      "POST",
+		url:         s.logArchiveURL(c, ""),
+		contentType: params.ContentTypeGzip,
+		body:        bytes.NewReader(data),
+	})
+	body := assertResponse(c, resp, http.StatusOK, params.ContentTypeJSON)
+	var result params.LogArchiveImportResult
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Count, gc.Equals, 2)
+
+	count, err := s.State.MongoSession().DB("logs").C("logs").Find(bson.M{
+		"e": s.State.EnvironUUID(),
+		"x": bson.M{"$in": []string{"one", "two"}},
+	}).Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 2)
+}
+
+func (s *logArchiveSuite) TestImportRequiresEnvironAdmin(c *gc.C) {
+	s.setupOtherEnvironment(c)
+	resp := s.authRequest(c, httpRequestParams{
+		method:      "POST",
+		url:         s.logArchiveURL(c, ""),
+		contentType: params.ContentTypeGzip,
+		body:        strings.NewReader("archive"),
+	})
+	body := assertResponse(c, resp, http.StatusUnauthorized, params.ContentTypeJSON)
+	c.Assert(string(body), jc.Contains, "permission denied")
+}
+
+func (s *logArchiveSuite) TestImportBlocked(c *gc.C) {
+	s.BlockAllChanges(c, "TestImportBlocked")
+	resp := s.authRequest(c, httpRequestParams{
+		method:      "POST",
+		url:         s.logArchiveURL(c, ""),
+		contentType: params.ContentTypeGzip,
+		body:        strings.NewReader("archive"),
+	})
+	body := assertResponse(c, resp, http.StatusBadRequest, params.ContentTypeJSON)
+	var result params.ErrorResult
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AssertBlocked(c, result.Error, "TestImportBlocked")
+}
+
+func (s *logArchiveSuite) TestImportRequiresGzip(c *gc.C) {
+	resp := s.authRequest(c, httpRequestParams{
+		method:      "POST",
+		url:         s.logArchiveURL(c, ""),
+		contentType: params.ContentTypeJSON,
+		body:        strings.NewReader("{}"),
+	})
+	body := assertResponse(c, resp, http.StatusBadRequest, params.ContentTypeJSON)
+	c.Assert(string(body), jc.Contains, `expected Content-Type \"application/gzip\", got \"application/json\"`)
+}
+
+func (s *logArchiveSuite) TestImportInvalidArchive(c *gc.C) {
+	resp := s.authRequest(c, httpRequestParams{
+		method:      "POST",
+		url:         s.logArchiveURL(c, ""),
+		contentType: params.ContentTypeGzip,
+		body:        strings.NewReader("not gzip"),
+	})
+	body := assertResponse(c, resp, http.StatusBadRequest, params.ContentTypeJSON)
+	c.Assert(string(body), jc.Contains, "invalid log archive")
+}
+
+func (s *logArchiveSuite) TestImportTooLarge(c *gc.C) {
+	s.PatchValue(apiserver.MaxLogArchiveUploadSize, int64(10))
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte(`{"level": "INFO", "message": "hello"}` + "\n"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zw.Close(), jc.ErrorIsNil)
+
+	resp := s.authRequest(c, httpRequestParams{
+		method:      "POST",
+		url:         s.logArchiveURL(c, ""),
+		contentType: params.ContentTypeGzip,
+		body:        bytes.NewReader(buf.Bytes()),
+	})
+	body := assertResponse(c, resp, http.StatusBadRequest, params.ContentTypeJSON)
+	c.Assert(string(body), jc.Contains, "request body too large")
+}
@@ -51,9 +51,10 @@ func NewLoggerAPI(
 }
 
 // WatchLoggingConfig starts a watcher to track changes to the logging config
-// for the agents specified..  Unfortunately the current infrastruture makes
-// watching parts of the config non-trivial, so currently any change to the
-// config will cause the watcher to notify the client.
+// for the agents specified. Unfortunately the current infrastruture makes
+// watching parts of the environment config non-trivial, so currently any
+// change to it will cause the watcher to notify the client; as will any
+// change to, or expiry of, the agent's logging override.
 func (api *LoggerAPI) WatchLoggingConfig(arg params.Entities) params.NotifyWatchResults {
 	result := make([]params.NotifyWatchResult, len(arg.Entities))
 	for i, entity := range arg.Entities {
@@ -64,7 +65,7 @@ func (api *LoggerAPI) WatchLoggingConfig(arg params.Entities) params.NotifyWatch
 		}
 		err = common.ErrPerm
 		if api.authorizer.AuthOwner(tag) {
-			watch := api.state.WatchForEnvironConfigChanges()
+			watch := api.state.WatchAgentLoggingConfig(tag)
 			// Consume the initial event. Technically, API calls to Watch
 			// 'transmit' the initial event in the Watch response. But
 			// NotifyWatchers have no state to transmit.
@@ -86,7 +87,6 @@ func (api *LoggerAPI) LoggingConfig(arg params.Entities) params.StringResults {
 		return params.StringResults{}
 	}
 	results := make([]params.StringResult, len(arg.Entities))
-	config, configErr := api.state.EnvironConfig()
 	for i, entity := range arg.Entities {
 		tag, err := names.ParseTag(entity.Tag)
 		if err != nil {
@@ -95,12 +95,7 @@ func (api *LoggerAPI) LoggingConfig(arg params.Entities) params.StringResults {
 		}
 		err = common.ErrPerm
 		if api.authorizer.AuthOwner(tag) {
-			if configErr == nil {
-				results[i].Result = config.LoggingConfig()
-				err = nil
-			} else {
-				err = configErr
-			}
+			results[i].Result, err = api.state.AgentLoggingConfig(tag)
 		}
 		results[i].Error = common.ServerError(err)
 	}
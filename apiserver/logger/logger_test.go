@@ -4,21 +4,27 @@
 package logger_test
 
 import (
+	"time"
+
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/common"
+	commontesting "github.com/juju/juju/apiserver/common/testing"
 	"github.com/juju/juju/apiserver/logger"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing/factory"
 )
 
 type loggerSuite struct {
 	jujutesting.JujuConnSuite
+	commontesting.BlockHelper
 
 	// These are raw State objects. Use them for setup and assertions, but
 	// should never be touched by the API calls themselves
@@ -34,6 +40,8 @@ func (s *loggerSuite) SetUpTest(c *gc.C) {
 	s.JujuConnSuite.SetUpTest(c)
 	s.resources = common.NewResources()
 	s.AddCleanup(func(_ *gc.C) { s.resources.StopAll() })
+	s.BlockHelper = commontesting.NewBlockHelper(s.APIState)
+	s.AddCleanup(func(*gc.C) { s.BlockHelper.Close() })
 
 	// Create a machine to work with
 	var err error
@@ -49,7 +57,15 @@ func (s *loggerSuite) SetUpTest(c *gc.C) {
 }
 
 func (s *loggerSuite) TestNewLoggerAPIRefusesNonAgent(c *gc.C) {
-	// We aren't even a machine agent
+	// We aren't even a machine agent, or a client
+	anAuthorizer := s.authorizer
+	anAuthorizer.Tag = names.NewServiceTag("germany")
+	endPoint, err := logger.NewLoggerAPI(s.State, s.resources, anAuthorizer)
+	c.Assert(endPoint, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *loggerSuite) TestNewLoggerAPIRefusesClient(c *gc.C) {
 	anAuthorizer := s.authorizer
 	anAuthorizer.Tag = s.AdminUserTag(c)
 	endPoint, err := logger.NewLoggerAPI(s.State, s.resources, anAuthorizer)
@@ -57,6 +73,28 @@ func (s *loggerSuite) TestNewLoggerAPIRefusesNonAgent(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 }
 
+func (s *loggerSuite) TestNewLoggerAPIV1AcceptsClient(c *gc.C) {
+	anAuthorizer := s.authorizer
+	anAuthorizer.Tag = s.AdminUserTag(c)
+	endPoint, err := logger.NewLoggerAPIV1(s.State, s.resources, anAuthorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(endPoint, gc.NotNil)
+}
+
+func (s *loggerSuite) TestNewLoggerAPIV1AcceptsAgent(c *gc.C) {
+	endPoint, err := logger.NewLoggerAPIV1(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(endPoint, gc.NotNil)
+}
+
+func (s *loggerSuite) TestNewLoggerAPIV1RefusesNonAgent(c *gc.C) {
+	anAuthorizer := s.authorizer
+	anAuthorizer.Tag = names.NewServiceTag("germany")
+	endPoint, err := logger.NewLoggerAPIV1(s.State, s.resources, anAuthorizer)
+	c.Assert(endPoint, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
 func (s *loggerSuite) TestNewLoggerAPIAcceptsUnitAgent(c *gc.C) {
 	// We aren't even a machine agent
 	anAuthorizer := s.authorizer
@@ -144,3 +182,167 @@ func (s *loggerSuite) TestLoggingConfigForAgent(c *gc.C) {
 	c.Assert(result.Error, gc.IsNil)
 	c.Assert(result.Result, gc.Equals, newLoggingConfig)
 }
+
+func (s *loggerSuite) clientAPI(c *gc.C) *logger.LoggerAPIV1 {
+	anAuthorizer := s.authorizer
+	anAuthorizer.Tag = s.AdminUserTag(c)
+	api, err := logger.NewLoggerAPIV1(s.State, s.resources, anAuthorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *loggerSuite) TestLoggingConfigWithOverride(c *gc.C) {
+	s.setLoggingConfig(c, "<root>=WARNING")
+	err := s.State.SetLoggingOverride(s.rawMachine.Tag(), "juju.worker=TRACE", time.Now().Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
+	}
+	results := s.logger.LoggingConfig(args)
+	c.Assert(results.Results, gc.HasLen, 1)
+	result := results.Results[0]
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.Result, gc.Equals, "<root>=WARNING;juju.worker=TRACE")
+}
+
+func (s *loggerSuite) TestWatchLoggingConfigOverride(c *gc.C) {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
+	}
+	results := s.logger.WatchLoggingConfig(args)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	w := s.resources.Get(results.Results[0].NotifyWatcherId).(state.NotifyWatcher)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertNoChange()
+
+	err := s.State.SetLoggingOverride(s.rawMachine.Tag(), "juju.worker=TRACE", time.Now().Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
+func (s *loggerSuite) TestSetLoggingOverrides(c *gc.C) {
+	expires := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+	results, err := s.clientAPI(c).SetLoggingOverrides(params.LoggingOverrides{
+		Overrides: []params.LoggingOverride{{
+			Tag:     s.rawMachine.Tag().String(),
+			Config:  "juju.worker=TRACE",
+			Expires: expires,
+		}, {
+			Tag:     "machine-42",
+			Config:  "juju.worker=TRACE",
+			Expires: expires,
+		}, {
+			Tag:     "bad",
+			Config:  "juju.worker=TRACE",
+			Expires: expires,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot set logging override for "machine-42": machine 42 not found`)
+	c.Assert(results.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `"bad" is not a valid tag`)
+
+	override, err := s.State.LoggingOverride(s.rawMachine.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(override.Config, gc.Equals, "juju.worker=TRACE")
+	c.Assert(override.Expires.Equal(expires), jc.IsTrue)
+}
+
+func (s *loggerSuite) TestLoggingOverrides(c *gc.C) {
+	expires := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+	err := s.State.SetLoggingOverride(s.rawMachine.Tag(), "juju.worker=TRACE", expires)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.clientAPI(c).LoggingOverrides()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Overrides, gc.HasLen, 1)
+	override := result.Overrides[0]
+	c.Assert(override.Tag, gc.Equals, s.rawMachine.Tag().String())
+	c.Assert(override.Config, gc.Equals, "juju.worker=TRACE")
+	c.Assert(override.Expires.Equal(expires), jc.IsTrue)
+}
+
+func (s *loggerSuite) TestRemoveLoggingOverrides(c *gc.C) {
+	err := s.State.SetLoggingOverride(s.rawMachine.Tag(), "juju.worker=TRACE", time.Now().Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.clientAPI(c).RemoveLoggingOverrides(params.Entities{
+		Entities: []params.Entity{
+			{Tag: s.rawMachine.Tag().String()},
+			{Tag: s.rawMachine.Tag().String()},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+	_, err = s.State.LoggingOverride(s.rawMachine.Tag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *loggerSuite) TestLoggingOverridesRefusesAgent(c *gc.C) {
+	api, err := logger.NewLoggerAPIV1(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.SetLoggingOverrides(params.LoggingOverrides{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = api.RemoveLoggingOverrides(params.Entities{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = api.LoggingOverrides()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *loggerSuite) TestLoggingOverridesRequiresEnvironAdmin(c *gc.C) {
+	// Users that are not users of the controller's environment do not
+	// administer it.
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoEnvUser: true})
+	anAuthorizer := s.authorizer
+	anAuthorizer.Tag = user.UserTag()
+	api, err := logger.NewLoggerAPIV1(s.State, s.resources, anAuthorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.SetLoggingOverrides(params.LoggingOverrides{
+		Overrides: []params.LoggingOverride{{
+			Tag:     s.rawMachine.Tag().String(),
+			Config:  "juju.worker=TRACE",
+			Expires: time.Now().Add(time.Hour),
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = api.RemoveLoggingOverrides(params.Entities{
+		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = s.State.LoggingOverride(s.rawMachine.Tag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *loggerSuite) TestSetLoggingOverridesBlocked(c *gc.C) {
+	s.BlockAllChanges(c, "TestSetLoggingOverridesBlocked")
+	_, err := s.clientAPI(c).SetLoggingOverrides(params.LoggingOverrides{
+		Overrides: []params.LoggingOverride{{
+			Tag:     s.rawMachine.Tag().String(),
+			Config:  "juju.worker=TRACE",
+			Expires: time.Now().Add(time.Hour),
+		}},
+	})
+	s.AssertBlocked(c, err, "TestSetLoggingOverridesBlocked")
+	_, err = s.State.LoggingOverride(s.rawMachine.Tag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *loggerSuite) TestRemoveLoggingOverridesBlocked(c *gc.C) {
+	err := s.State.SetLoggingOverride(s.rawMachine.Tag(), "juju.worker=TRACE", time.Now().Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	s.BlockRemoveObject(c, "TestRemoveLoggingOverridesBlocked")
+	_, err = s.clientAPI(c).RemoveLoggingOverrides(params.Entities{
+		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
+	})
+	s.AssertBlocked(c, err, "TestRemoveLoggingOverridesBlocked")
+	_, err = s.State.LoggingOverride(s.rawMachine.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+}
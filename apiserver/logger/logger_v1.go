@@ -0,0 +1,137 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logger
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Logger", 1, NewLoggerAPIV1)
+}
+
+// LoggerV1 defines the methods on version 1 of the logger API end
+// point, which adds the management of logging overrides by clients.
+type LoggerV1 interface {
+	Logger
+	SetLoggingOverrides(args params.LoggingOverrides) (params.ErrorResults, error)
+	RemoveLoggingOverrides(args params.Entities) (params.ErrorResults, error)
+	LoggingOverrides() (params.LoggingOverrides, error)
+}
+
+// LoggerAPIV1 implements version 1 of the logger API end point.
+type LoggerAPIV1 struct {
+	LoggerAPI
+	check *common.BlockChecker
+}
+
+var _ LoggerV1 = (*LoggerAPIV1)(nil)
+
+// NewLoggerAPIV1 creates a new server-side logger API end point. Agents
+// use it to track their logging config, and clients to manage the
+// logging overrides of individual agents.
+func NewLoggerAPIV1(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*LoggerAPIV1, error) {
+	if !authorizer.AuthMachineAgent() && !authorizer.AuthUnitAgent() && !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &LoggerAPIV1{
+		LoggerAPI: LoggerAPI{state: st, resources: resources, authorizer: authorizer},
+		check:     common.NewBlockChecker(st),
+	}, nil
+}
+
+// checkIsEnvironAdmin returns an error unless the API is being used by
+// a client which may administer the environment.
+func (api *LoggerAPIV1) checkIsEnvironAdmin() error {
+	if !api.authorizer.AuthClient() {
+		return common.ErrPerm
+	}
+	apiUser, ok := api.authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return common.ErrPerm
+	}
+	isAdmin, err := common.HasEnvironAdminAccess(api.state, apiUser)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !isAdmin {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// SetLoggingOverrides sets logging configuration for the agents of the
+// given machines and units, on top of the environment's logging-config,
+// until the given expiry times. Only administrators of the environment
+// may set overrides.
+func (api *LoggerAPIV1) SetLoggingOverrides(args params.LoggingOverrides) (params.ErrorResults, error) {
+	if err := api.checkIsEnvironAdmin(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := make([]params.ErrorResult, len(args.Overrides))
+	for i, override := range args.Overrides {
+		tag, err := names.ParseTag(override.Tag)
+		if err == nil {
+			err = api.state.SetLoggingOverride(tag, override.Config, override.Expires)
+		}
+		results[i].Error = common.ServerError(err)
+	}
+	return params.ErrorResults{Results: results}, nil
+}
+
+// RemoveLoggingOverrides removes the logging overrides of the given
+// agents before they expire. Only administrators of the environment may
+// remove overrides.
+func (api *LoggerAPIV1) RemoveLoggingOverrides(args params.Entities) (params.ErrorResults, error) {
+	if err := api.checkIsEnvironAdmin(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	if err := api.check.RemoveAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := make([]params.ErrorResult, len(args.Entities))
+	for i, entity := range args.Entities {
+		tag, err := names.ParseTag(entity.Tag)
+		if err == nil {
+			err = api.state.RemoveLoggingOverride(tag)
+		}
+		results[i].Error = common.ServerError(err)
+	}
+	return params.ErrorResults{Results: results}, nil
+}
+
+// LoggingOverrides returns the logging overrides in the environment
+// that have not yet expired. Only clients may list overrides.
+func (api *LoggerAPIV1) LoggingOverrides() (params.LoggingOverrides, error) {
+	if !api.authorizer.AuthClient() {
+		return params.LoggingOverrides{}, common.ErrPerm
+	}
+	overrides, err := api.state.LoggingOverrides()
+	if err != nil {
+		return params.LoggingOverrides{}, errors.Trace(err)
+	}
+	result := params.LoggingOverrides{
+		Overrides: make([]params.LoggingOverride, len(overrides)),
+	}
+	for i, override := range overrides {
+		result.Overrides[i] = params.LoggingOverride{
+			Tag:     override.Tag.String(),
+			Config:  override.Config,
+			Expires: override.Expires,
+		}
+	}
+	return result, nil
+}
@@ -91,7 +91,7 @@ func (h *logSinkHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 					logger.Errorf("logging to logsink.log failed: %v", fileErr)
 				}
 
-				dbErr := dbLogger.Log(m.Time, m.Module, m.Location, m.Level, m.Message)
+				dbErr := dbLogger.LogWithLabels(m.Time, m.Module, m.Location, m.Level, m.Message, m.Labels)
 				if dbErr != nil {
 					logger.Errorf("logging to DB failed: %v", err)
 				}
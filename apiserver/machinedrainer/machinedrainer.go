@@ -0,0 +1,52 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The machinedrainer package implements the API interface
+// used by the machinedrainer worker.
+
+package machinedrainer
+
+import (
+	"time"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("MachineDrainer", 1, NewMachineDrainerAPI)
+}
+
+// StateInterface defines the state methods used by the MachineDrainer
+// facade.
+type StateInterface interface {
+	AdvanceMachineDrains(replacementTimeout time.Duration) error
+}
+
+var getState = func(st *state.State) StateInterface {
+	return st
+}
+
+// MachineDrainerAPI implements the API used by the machinedrainer worker.
+type MachineDrainerAPI struct {
+	st StateInterface
+}
+
+// NewMachineDrainerAPI creates a new instance of the MachineDrainer API.
+func NewMachineDrainerAPI(
+	st *state.State,
+	_ *common.Resources,
+	authorizer common.Authorizer,
+) (*MachineDrainerAPI, error) {
+	if !authorizer.AuthEnvironManager() {
+		return nil, common.ErrPerm
+	}
+	return &MachineDrainerAPI{st: getState(st)}, nil
+}
+
+// AdvanceMachineDrains moves every machine drain in progress on by at
+// most one step.
+func (api *MachineDrainerAPI) AdvanceMachineDrains(args params.AdvanceMachineDrainsArgs) error {
+	return api.st.AdvanceMachineDrains(args.ReplacementTimeout)
+}
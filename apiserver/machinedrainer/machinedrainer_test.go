@@ -0,0 +1,74 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinedrainer_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/machinedrainer"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type MachineDrainerSuite struct {
+	coretesting.BaseSuite
+
+	st         *mockState
+	api        *machinedrainer.MachineDrainerAPI
+	authoriser apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&MachineDrainerSuite{})
+
+func (s *MachineDrainerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+
+	s.authoriser = apiservertesting.FakeAuthorizer{
+		EnvironManager: true,
+	}
+	s.st = &mockState{&testing.Stub{}}
+	machinedrainer.PatchState(s, s.st)
+	var err error
+	s.api, err = machinedrainer.NewMachineDrainerAPI(nil, nil, s.authoriser)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MachineDrainerSuite) TestNewMachineDrainerAPIRequiresEnvironManager(c *gc.C) {
+	anAuthoriser := s.authoriser
+	anAuthoriser.EnvironManager = false
+	api, err := machinedrainer.NewMachineDrainerAPI(nil, nil, anAuthoriser)
+	c.Assert(api, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(common.ServerError(err), jc.Satisfies, params.IsCodeUnauthorized)
+}
+
+func (s *MachineDrainerSuite) TestAdvanceMachineDrains(c *gc.C) {
+	err := s.api.AdvanceMachineDrains(params.AdvanceMachineDrainsArgs{
+		ReplacementTimeout: time.Minute,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.st.CheckCalls(c, []testing.StubCall{{"AdvanceMachineDrains", []interface{}{time.Minute}}})
+}
+
+func (s *MachineDrainerSuite) TestAdvanceMachineDrainsFailure(c *gc.C) {
+	s.st.SetErrors(errors.New("boom!"))
+	err := s.api.AdvanceMachineDrains(params.AdvanceMachineDrainsArgs{})
+	c.Assert(err, gc.ErrorMatches, "boom!")
+}
+
+type mockState struct {
+	*testing.Stub
+}
+
+func (st *mockState) AdvanceMachineDrains(replacementTimeout time.Duration) error {
+	st.MethodCall(st, "AdvanceMachineDrains", replacementTimeout)
+	return st.NextErr()
+}
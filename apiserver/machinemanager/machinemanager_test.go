@@ -5,6 +5,7 @@ package machinemanager_test
 
 import (
 	"errors"
+	"time"
 
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
@@ -30,6 +31,7 @@ type MachineManagerSuite struct {
 	authorizer *apiservertesting.FakeAuthorizer
 	st         *mockState
 	api        *machinemanager.MachineManagerAPI
+	apiV2      *machinemanager.MachineManagerAPIV2
 }
 
 func (s *MachineManagerSuite) SetUpTest(c *gc.C) {
@@ -43,6 +45,8 @@ func (s *MachineManagerSuite) SetUpTest(c *gc.C) {
 	var err error
 	s.api, err = machinemanager.NewMachineManagerAPI(nil, nil, s.authorizer)
 	c.Assert(err, jc.ErrorIsNil)
+	s.apiV2, err = machinemanager.NewMachineManagerAPIV2(nil, nil, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
 }
 
 func (s *MachineManagerSuite) TestAddMachines(c *gc.C) {
@@ -102,6 +106,13 @@ func (s *MachineManagerSuite) TestNewMachineManagerAPINonClient(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 }
 
+func (s *MachineManagerSuite) TestNewMachineManagerAPIV2NonClient(c *gc.C) {
+	tag := names.NewUnitTag("mysql/0")
+	s.authorizer = &apiservertesting.FakeAuthorizer{Tag: tag}
+	_, err := machinemanager.NewMachineManagerAPIV2(nil, nil, s.authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
 func (s *MachineManagerSuite) TestAddMachinesStateError(c *gc.C) {
 	s.st.err = errors.New("boom")
 	results, err := s.api.AddMachines(params.AddMachines{
@@ -118,12 +129,100 @@ func (s *MachineManagerSuite) TestAddMachinesStateError(c *gc.C) {
 	c.Assert(s.st.calls, gc.Equals, 1)
 }
 
+func (s *MachineManagerSuite) TestDrainMachines(c *gc.C) {
+	results, err := s.apiV2.DrainMachines(params.Entities{Entities: []params.Entity{
+		{Tag: "machine-1"}, {Tag: "unit-foo-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{Results: []params.ErrorResult{
+		{},
+		{Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized}},
+	}})
+	c.Assert(s.st.drained, jc.DeepEquals, []string{"1"})
+}
+
+func (s *MachineManagerSuite) TestUndrainMachines(c *gc.C) {
+	s.st.err = errors.New("boom")
+	results, err := s.apiV2.UndrainMachines(params.Entities{Entities: []params.Entity{
+		{Tag: "machine-2"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{Results: []params.ErrorResult{
+		{Error: &params.Error{Message: "boom"}},
+	}})
+	c.Assert(s.st.undrained, jc.DeepEquals, []string{"2"})
+}
+
+func (s *MachineManagerSuite) TestMachineDrains(c *gc.C) {
+	started := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.st.drain = &mockDrain{
+		machineId: "1",
+		status:    state.MachineDrainReplacing,
+		replacements: []state.UnitReplacement{
+			{Original: "mysql/0", Replacement: "mysql/1"},
+		},
+		started: started,
+		updated: started.Add(time.Minute),
+	}
+	results, err := s.apiV2.MachineDrains(params.Entities{Entities: []params.Entity{
+		{Tag: "machine-1"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.MachineDrainResults{Results: []params.MachineDrainResult{{
+		Result: &params.MachineDrain{
+			MachineId: "1",
+			Status:    "replacing",
+			Replacements: []params.UnitReplacement{
+				{Original: "mysql/0", Replacement: "mysql/1"},
+			},
+			Started: started,
+			Updated: started.Add(time.Minute),
+		},
+	}}})
+}
+
 type mockState struct {
-	calls    int
-	machines []state.MachineTemplate
-	err      error
+	calls     int
+	machines  []state.MachineTemplate
+	drained   []string
+	undrained []string
+	drain     *mockDrain
+	err       error
+}
+
+func (st *mockState) DrainMachine(id string) error {
+	st.drained = append(st.drained, id)
+	return st.err
 }
 
+func (st *mockState) UndrainMachine(id string) error {
+	st.undrained = append(st.undrained, id)
+	return st.err
+}
+
+func (st *mockState) MachineDrain(id string) (machinemanager.MachineDrain, error) {
+	if st.err != nil {
+		return nil, st.err
+	}
+	return st.drain, nil
+}
+
+type mockDrain struct {
+	machineId    string
+	status       state.MachineDrainStatus
+	message      string
+	replacements []state.UnitReplacement
+	started      time.Time
+	updated      time.Time
+}
+
+func (d *mockDrain) MachineId() string                     { return d.machineId }
+func (d *mockDrain) Status() state.MachineDrainStatus      { return d.status }
+func (d *mockDrain) Message() string                       { return d.message }
+func (d *mockDrain) Replacements() []state.UnitReplacement { return d.replacements }
+func (d *mockDrain) Started() time.Time                    { return d.started }
+func (d *mockDrain) Updated() time.Time                    { return d.updated }
+
 func (st *mockState) AddOneMachine(template state.MachineTemplate) (*state.Machine, error) {
 	st.calls++
 	st.machines = append(st.machines, template)
@@ -0,0 +1,114 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinemanager
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("MachineManager", 2, NewMachineManagerAPIV2)
+}
+
+// MachineManagerAPIV2 provides access to version 2 of the
+// MachineManager API facade, which adds machine drains.
+type MachineManagerAPIV2 struct {
+	MachineManagerAPI
+}
+
+// NewMachineManagerAPIV2 creates a new server-side MachineManager API
+// facade, version 2.
+func NewMachineManagerAPIV2(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*MachineManagerAPIV2, error) {
+	api, err := NewMachineManagerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &MachineManagerAPIV2{*api}, nil
+}
+
+// DrainMachines marks each of the given machines as unschedulable and
+// starts evacuating its units onto other machines.
+func (mm *MachineManagerAPIV2) DrainMachines(args params.Entities) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	if err := mm.check.RemoveAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		err := mm.forMachine(entity.Tag, mm.st.DrainMachine)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// UndrainMachines allows each of the given machines to accept new units
+// again, abandoning any drain in progress.
+func (mm *MachineManagerAPIV2) UndrainMachines(args params.Entities) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	if err := mm.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		err := mm.forMachine(entity.Tag, mm.st.UndrainMachine)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// MachineDrains returns the progress of the most recent drain of each
+// of the given machines.
+func (mm *MachineManagerAPIV2) MachineDrains(args params.Entities) (params.MachineDrainResults, error) {
+	results := params.MachineDrainResults{
+		Results: make([]params.MachineDrainResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		err := mm.forMachine(entity.Tag, func(id string) error {
+			drain, err := mm.st.MachineDrain(id)
+			if err != nil {
+				return err
+			}
+			results.Results[i].Result = machineDrainFromState(drain)
+			return nil
+		})
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (mm *MachineManagerAPIV2) forMachine(tag string, f func(id string) error) error {
+	machineTag, err := names.ParseMachineTag(tag)
+	if err != nil {
+		return common.ErrPerm
+	}
+	return f(machineTag.Id())
+}
+
+func machineDrainFromState(drain MachineDrain) *params.MachineDrain {
+	result := &params.MachineDrain{
+		MachineId: drain.MachineId(),
+		Status:    string(drain.Status()),
+		Message:   drain.Message(),
+		Started:   drain.Started(),
+		Updated:   drain.Updated(),
+	}
+	for _, r := range drain.Replacements() {
+		result.Replacements = append(result.Replacements, params.UnitReplacement{
+			Original:    r.Original,
+			Replacement: r.Replacement,
+		})
+	}
+	return result
+}
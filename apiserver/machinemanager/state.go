@@ -4,6 +4,10 @@
 package machinemanager
 
 import (
+	"time"
+
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
@@ -16,6 +20,20 @@ type stateInterface interface {
 	AddOneMachine(template state.MachineTemplate) (*state.Machine, error)
 	AddMachineInsideNewMachine(template, parentTemplate state.MachineTemplate, containerType instance.ContainerType) (*state.Machine, error)
 	AddMachineInsideMachine(template state.MachineTemplate, parentId string, containerType instance.ContainerType) (*state.Machine, error)
+	DrainMachine(id string) error
+	UndrainMachine(id string) error
+	MachineDrain(id string) (MachineDrain, error)
+}
+
+// MachineDrain describes the progress of evacuating the units from
+// a machine.
+type MachineDrain interface {
+	MachineId() string
+	Status() state.MachineDrainStatus
+	Message() string
+	Replacements() []state.UnitReplacement
+	Started() time.Time
+	Updated() time.Time
 }
 
 type stateShim struct {
@@ -45,3 +63,28 @@ func (s stateShim) AddMachineInsideNewMachine(template, parentTemplate state.Mac
 func (s stateShim) AddMachineInsideMachine(template state.MachineTemplate, parentId string, containerType instance.ContainerType) (*state.Machine, error) {
 	return s.State.AddMachineInsideMachine(template, parentId, containerType)
 }
+
+func (s stateShim) DrainMachine(id string) error {
+	m, err := s.State.Machine(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = m.Drain()
+	return errors.Trace(err)
+}
+
+func (s stateShim) UndrainMachine(id string) error {
+	m, err := s.State.Machine(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return m.Undrain()
+}
+
+func (s stateShim) MachineDrain(id string) (MachineDrain, error) {
+	drain, err := s.State.MachineDrain(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return drain, nil
+}
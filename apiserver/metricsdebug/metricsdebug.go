@@ -0,0 +1,198 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package metricsdebug contains the implementation of an api endpoint
+// for inspecting the metrics recorded by units.
+package metricsdebug
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("MetricsDebug", 1, NewMetricsDebugAPI)
+}
+
+// MetricsDebug defines the methods on the metricsdebug API end point.
+type MetricsDebug interface {
+	// GetMetrics returns the metrics recorded for units or services.
+	GetMetrics(args params.MetricsQueries) (params.MetricResults, error)
+
+	// AddMetricThresholds adds metric thresholds to the environment.
+	AddMetricThresholds(args params.MetricThresholds) (params.StringResults, error)
+
+	// RemoveMetricThresholds removes metric thresholds from the environment.
+	RemoveMetricThresholds(args params.MetricThresholdIds) (params.ErrorResults, error)
+
+	// MetricThresholds returns the metric thresholds defined in the environment.
+	MetricThresholds() (params.MetricThresholds, error)
+
+	// MetricAlerts returns the alerts raised by metric thresholds.
+	MetricAlerts(args params.MetricAlertsQuery) (params.MetricAlertsResult, error)
+}
+
+// MetricsDebugAPI implements the metricsdebug interface and is the concrete
+// implementation of the api end point.
+type MetricsDebugAPI struct {
+	state *state.State
+}
+
+var _ MetricsDebug = (*MetricsDebugAPI)(nil)
+
+// NewMetricsDebugAPI creates a new API endpoint for calling metrics debug functions.
+func NewMetricsDebugAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*MetricsDebugAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &MetricsDebugAPI{
+		state: st,
+	}, nil
+}
+
+// GetMetrics returns the metrics stored in state for the given units
+// or services. Only metrics that have not yet been removed by the
+// metric cleanup are returned.
+func (api *MetricsDebugAPI) GetMetrics(args params.MetricsQueries) (params.MetricResults, error) {
+	results := params.MetricResults{
+		Results: make([]params.EntityMetrics, len(args.Queries)),
+	}
+	for i, query := range args.Queries {
+		batches, err := api.metricBatches(query)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		var metrics []params.MetricResult
+		for _, batch := range batches {
+			for _, m := range batch.Metrics() {
+				metrics = append(metrics, params.MetricResult{
+					Key:   m.Key,
+					Value: m.Value,
+					Time:  m.Time,
+					Unit:  batch.Unit(),
+				})
+			}
+		}
+		results.Results[i].Metrics = metrics
+	}
+	return results, nil
+}
+
+func (api *MetricsDebugAPI) metricBatches(query params.MetricsQuery) ([]state.MetricBatch, error) {
+	tag, err := names.ParseTag(query.Tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch tag := tag.(type) {
+	case names.UnitTag:
+		return api.state.MetricBatchesForUnit(tag.Id(), query.Since)
+	case names.ServiceTag:
+		return api.state.MetricBatchesForService(tag.Id(), query.Since)
+	}
+	return nil, errors.NotValidf("metrics query for %s", names.ReadableString(tag))
+}
+
+// AddMetricThresholds adds metric thresholds to the environment,
+// returning the ID of each new threshold.
+func (api *MetricsDebugAPI) AddMetricThresholds(args params.MetricThresholds) (params.StringResults, error) {
+	results := params.StringResults{
+		Results: make([]params.StringResult, len(args.Thresholds)),
+	}
+	if err := common.NewBlockChecker(api.state).ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, arg := range args.Thresholds {
+		t, err := api.state.AddMetricThreshold(state.MetricThresholdParams{
+			Service:  arg.Service,
+			Key:      arg.Key,
+			Operator: arg.Operator,
+			Value:    arg.Value,
+			Duration: arg.Duration,
+			Status:   arg.Status,
+			Info:     arg.Info,
+		})
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = t.Id()
+	}
+	return results, nil
+}
+
+// RemoveMetricThresholds removes the metric thresholds with the given IDs.
+func (api *MetricsDebugAPI) RemoveMetricThresholds(args params.MetricThresholdIds) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Ids)),
+	}
+	if err := common.NewBlockChecker(api.state).ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, id := range args.Ids {
+		err := api.state.RemoveMetricThreshold(id)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// MetricThresholds returns the metric thresholds defined in the environment.
+func (api *MetricsDebugAPI) MetricThresholds() (params.MetricThresholds, error) {
+	thresholds, err := api.state.MetricThresholds()
+	if err != nil {
+		return params.MetricThresholds{}, errors.Trace(err)
+	}
+	result := params.MetricThresholds{
+		Thresholds: make([]params.MetricThreshold, len(thresholds)),
+	}
+	for i, t := range thresholds {
+		result.Thresholds[i] = params.MetricThreshold{
+			Id:       t.Id(),
+			Service:  t.Service(),
+			Key:      t.Key(),
+			Operator: t.Operator(),
+			Value:    t.Value(),
+			Duration: t.Duration(),
+			Status:   t.Status(),
+			Info:     t.Info(),
+		}
+	}
+	return result, nil
+}
+
+// MetricAlerts returns the alerts raised by metric thresholds since
+// the given time, along with any alerts that are still active.
+func (api *MetricsDebugAPI) MetricAlerts(args params.MetricAlertsQuery) (params.MetricAlertsResult, error) {
+	alerts, err := api.state.MetricAlerts(args.Since)
+	if err != nil {
+		return params.MetricAlertsResult{}, errors.Trace(err)
+	}
+	result := params.MetricAlertsResult{
+		Alerts: make([]params.MetricAlert, len(alerts)),
+	}
+	for i, a := range alerts {
+		alert := params.MetricAlert{
+			Id:        a.Id(),
+			Threshold: a.Threshold(),
+			UnitTag:   a.Unit().String(),
+			Key:       a.Key(),
+			Value:     a.Value(),
+			Status:    a.Status(),
+			Info:      a.Info(),
+			Raised:    a.Raised(),
+		}
+		if resolved, ok := a.Resolved(); ok {
+			alert.Resolved = &resolved
+		}
+		result.Alerts[i] = alert
+	}
+	return result, nil
+}
@@ -0,0 +1,187 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsdebug_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/metricsdebug"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type metricsDebugSuite struct {
+	jujutesting.JujuConnSuite
+
+	metricsdebug *metricsdebug.MetricsDebugAPI
+	authorizer   apiservertesting.FakeAuthorizer
+	service      *state.Service
+	unit         *state.Unit
+}
+
+var _ = gc.Suite(&metricsDebugSuite{})
+
+func (s *metricsDebugSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	debug, err := metricsdebug.NewMetricsDebugAPI(s.State, nil, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	s.metricsdebug = debug
+	meteredCharm := s.Factory.MakeCharm(c, &factory.CharmParams{Name: "metered", URL: "cs:quantal/metered"})
+	s.service = s.Factory.MakeService(c, &factory.ServiceParams{Charm: meteredCharm})
+	s.unit = s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service, SetCharmURL: true})
+}
+
+func (s *metricsDebugSuite) TestNewMetricsDebugAPIRefusesNonClient(c *gc.C) {
+	_, err := metricsdebug.NewMetricsDebugAPI(s.State, nil, apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *metricsDebugSuite) TestGetMetricsUnit(c *gc.C) {
+	now := time.Now().Round(time.Second).UTC()
+	metric := state.Metric{"pings", "5", now}
+	s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit, Time: &now, Metrics: []state.Metric{metric}})
+	unit2 := s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service, SetCharmURL: true})
+	s.Factory.MakeMetric(c, &factory.MetricParams{Unit: unit2, Time: &now})
+
+	result, err := s.metricsdebug.GetMetrics(params.MetricsQueries{Queries: []params.MetricsQuery{
+		{Tag: s.unit.Tag().String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Metrics, gc.HasLen, 1)
+	c.Assert(result.Results[0].Metrics[0].Key, gc.Equals, "pings")
+	c.Assert(result.Results[0].Metrics[0].Value, gc.Equals, "5")
+	c.Assert(result.Results[0].Metrics[0].Time.Equal(now), jc.IsTrue)
+	c.Assert(result.Results[0].Metrics[0].Unit, gc.Equals, "metered/0")
+}
+
+func (s *metricsDebugSuite) TestGetMetricsService(c *gc.C) {
+	now := time.Now().Round(time.Second).UTC()
+	old := now.Add(-time.Hour)
+	s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit, Time: &old})
+	s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit, Time: &now})
+	unit2 := s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service, SetCharmURL: true})
+	s.Factory.MakeMetric(c, &factory.MetricParams{Unit: unit2, Time: &now})
+
+	result, err := s.metricsdebug.GetMetrics(params.MetricsQueries{Queries: []params.MetricsQuery{
+		{Tag: s.service.Tag().String()},
+		{Tag: s.service.Tag().String(), Since: now.Add(-time.Minute)},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Metrics, gc.HasLen, 3)
+	c.Assert(result.Results[1].Error, gc.IsNil)
+	c.Assert(result.Results[1].Metrics, gc.HasLen, 2)
+	for _, m := range result.Results[1].Metrics {
+		c.Assert(m.Time.Equal(now), jc.IsTrue)
+	}
+}
+
+func (s *metricsDebugSuite) TestGetMetricsErrors(c *gc.C) {
+	result, err := s.metricsdebug.GetMetrics(params.MetricsQueries{Queries: []params.MetricsQuery{
+		{Tag: "invalid"},
+		{Tag: names.NewUnitTag("metered/42").String()},
+		{Tag: names.NewServiceTag("unknown").String()},
+		{Tag: names.NewMachineTag("0").String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 4)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, `"invalid" is not a valid tag`)
+	c.Assert(result.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+	c.Assert(result.Results[2].Error, jc.Satisfies, params.IsCodeNotFound)
+	c.Assert(result.Results[3].Error, gc.ErrorMatches, `metrics query for machine 0 not valid`)
+}
+
+func (s *metricsDebugSuite) TestAddAndListMetricThresholds(c *gc.C) {
+	result, err := s.metricsdebug.AddMetricThresholds(params.MetricThresholds{Thresholds: []params.MetricThreshold{{
+		Service:  "metered",
+		Key:      "pings",
+		Operator: ">",
+		Value:    500,
+		Duration: 5 * time.Minute,
+		Status:   "AMBER",
+		Info:     "too many pings",
+	}, {
+		Service:  "metered",
+		Key:      "pings",
+		Operator: "!",
+		Status:   "AMBER",
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `cannot add metric threshold: operator "!" not valid`)
+	id := result.Results[0].Result
+
+	thresholds, err := s.metricsdebug.MetricThresholds()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(thresholds, jc.DeepEquals, params.MetricThresholds{Thresholds: []params.MetricThreshold{{
+		Id:       id,
+		Service:  "metered",
+		Key:      "pings",
+		Operator: ">",
+		Value:    500,
+		Duration: 5 * time.Minute,
+		Status:   "AMBER",
+		Info:     "too many pings",
+	}}})
+
+	removed, err := s.metricsdebug.RemoveMetricThresholds(params.MetricThresholdIds{Ids: []string{id, id}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed.Results, gc.HasLen, 2)
+	c.Assert(removed.Results[0].Error, gc.IsNil)
+	c.Assert(removed.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func (s *metricsDebugSuite) TestAddMetricThresholdsBlocked(c *gc.C) {
+	err := s.State.SwitchBlockOn(state.ChangeBlock, "TestAddMetricThresholdsBlocked")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.metricsdebug.AddMetricThresholds(params.MetricThresholds{})
+	c.Assert(params.IsCodeOperationBlocked(err), jc.IsTrue)
+	_, err = s.metricsdebug.RemoveMetricThresholds(params.MetricThresholdIds{})
+	c.Assert(params.IsCodeOperationBlocked(err), jc.IsTrue)
+}
+
+func (s *metricsDebugSuite) TestMetricAlerts(c *gc.C) {
+	t, err := s.State.AddMetricThreshold(state.MetricThresholdParams{
+		Service:  "metered",
+		Key:      "pings",
+		Operator: ">",
+		Value:    4,
+		Status:   "RED",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	now := time.Now().Round(time.Second).UTC()
+	metric := state.Metric{"pings", "5", now}
+	s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit, Time: &now, Metrics: []state.Metric{metric}})
+	err = s.State.EvaluateMetricThresholds(now)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.metricsdebug.MetricAlerts(params.MetricAlertsQuery{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Alerts, gc.HasLen, 1)
+	alert := result.Alerts[0]
+	c.Assert(alert.Threshold, gc.Equals, t.Id())
+	c.Assert(alert.UnitTag, gc.Equals, "unit-metered-0")
+	c.Assert(alert.Key, gc.Equals, "pings")
+	c.Assert(alert.Value, gc.Equals, "5")
+	c.Assert(alert.Status, gc.Equals, "RED")
+	c.Assert(alert.Info, gc.Equals, "metered pings > 4")
+	c.Assert(alert.Raised.Equal(now), jc.IsTrue)
+	c.Assert(alert.Resolved, gc.IsNil)
+}
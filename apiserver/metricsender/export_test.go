@@ -4,6 +4,7 @@ package metricsender
 
 import (
 	"crypto/x509"
+	"time"
 
 	"github.com/juju/testing"
 )
@@ -16,3 +17,15 @@ func PatchHostAndCertPool(host string, certPool *x509.CertPool) func() {
 		restoreCertsPool()
 	}
 }
+
+func PatchFileSenderDir(dir string) func() {
+	return testing.PatchValue(&fileSenderDir, dir)
+}
+
+func PatchInfluxDBTimeout(timeout time.Duration) func() {
+	return testing.PatchValue(&influxDBTimeout, timeout)
+}
+
+func PatchGraphiteSendTimeout(timeout time.Duration) func() {
+	return testing.PatchValue(&graphiteSendTimeout, timeout)
+}
@@ -0,0 +1,67 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsender
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/series"
+
+	"github.com/juju/juju/apiserver/metricsender/wireformat"
+	"github.com/juju/juju/juju/paths"
+)
+
+// FileSenderName is the name under which the file sender
+// is registered.
+const FileSenderName = "file"
+
+func init() {
+	RegisterSender(FileSenderName, NewFileSender)
+}
+
+// fileSenderDir is the directory on the state server in which the
+// file sender writes metrics. The sender's target names a file in
+// this directory, so environment configuration cannot be used to
+// write to arbitrary paths.
+var fileSenderDir = filepath.Join(paths.MustSucceed(paths.DataDir(series.HostSeries())), "metrics")
+
+// FileSender appends metrics to a local file, one JSON-encoded
+// metric batch per line.
+type FileSender struct {
+	path string
+}
+
+// NewFileSender returns a sender that appends metrics to the file
+// with the given name in the state server's metrics directory.
+func NewFileSender(target string) (MetricSender, error) {
+	if target == "" || target == "." || target == ".." || filepath.Base(target) != target {
+		return nil, errors.NotValidf("metrics file name %q", target)
+	}
+	return &FileSender{path: filepath.Join(fileSenderDir, target)}, nil
+}
+
+// Send appends the given metrics to the file.
+func (s *FileSender) Send(batches []*wireformat.MetricBatch) (*wireformat.Response, error) {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return nil, errors.Trace(err)
+	}
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	for _, batch := range batches {
+		if err := enc.Encode(batch); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if err := f.Sync(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ackBatches(batches)
+}
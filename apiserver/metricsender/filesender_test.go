@@ -0,0 +1,74 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsender_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/metricsender"
+	"github.com/juju/juju/apiserver/metricsender/wireformat"
+	"github.com/juju/juju/testing"
+)
+
+type FileSenderSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&FileSenderSuite{})
+
+var _ metricsender.MetricSender = (*metricsender.FileSender)(nil)
+
+func (s *FileSenderSuite) TestSendAppends(c *gc.C) {
+	dir := c.MkDir()
+	defer metricsender.PatchFileSenderDir(dir)()
+	path := filepath.Join(dir, "metrics.log")
+	sender, err := metricsender.NewFileSender("metrics.log")
+	c.Assert(err, jc.ErrorIsNil)
+
+	batches := testBatches()
+	resp, err := sender.Send(batches[:1])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.EnvResponses["env-uuid"].AcknowledgedBatches, jc.DeepEquals, []string{"batch-0"})
+	resp, err = sender.Send(batches[1:])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.EnvResponses["env-uuid"].AcknowledgedBatches, jc.DeepEquals, []string{"batch-1"})
+
+	f, err := os.Open(path)
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+	var written []*wireformat.MetricBatch
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var batch wireformat.MetricBatch
+		err := json.Unmarshal(scanner.Bytes(), &batch)
+		c.Assert(err, jc.ErrorIsNil)
+		written = append(written, &batch)
+	}
+	c.Assert(scanner.Err(), jc.ErrorIsNil)
+	c.Assert(written, jc.DeepEquals, batches)
+}
+
+func (s *FileSenderSuite) TestSendCreatesDir(c *gc.C) {
+	dir := filepath.Join(c.MkDir(), "metrics")
+	defer metricsender.PatchFileSenderDir(dir)()
+	sender, err := metricsender.NewFileSender("metrics.log")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = sender.Send(testBatches())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = os.Stat(filepath.Join(dir, "metrics.log"))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *FileSenderSuite) TestInvalidFileName(c *gc.C) {
+	for _, name := range []string{"", ".", "..", "/etc/passwd", "../metrics.log", "a/b"} {
+		_, err := metricsender.NewFileSender(name)
+		c.Check(err, gc.ErrorMatches, `metrics file name ".*" not valid`)
+	}
+}
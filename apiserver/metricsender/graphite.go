@@ -0,0 +1,87 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsender
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/metricsender/wireformat"
+)
+
+// GraphiteSenderName is the name under which the Graphite sender
+// is registered.
+const GraphiteSenderName = "graphite"
+
+var (
+	graphiteDialTimeout = 10 * time.Second
+	graphiteSendTimeout = 30 * time.Second
+)
+
+func init() {
+	RegisterSender(GraphiteSenderName, NewGraphiteSender)
+}
+
+// GraphiteSender sends metrics to a Graphite (carbon) plaintext
+// protocol TCP endpoint.
+type GraphiteSender struct {
+	addr string
+}
+
+// NewGraphiteSender returns a sender that writes metrics to the
+// carbon endpoint at the given host:port address.
+func NewGraphiteSender(target string) (MetricSender, error) {
+	if _, _, err := net.SplitHostPort(target); err != nil {
+		return nil, errors.Annotate(err, "invalid Graphite address")
+	}
+	return &GraphiteSender{addr: target}, nil
+}
+
+// Send sends the given metrics to Graphite. Each metric is written
+// under the path juju.<env-uuid>.<service>.<unit-number>.<key>.
+func (s *GraphiteSender) Send(batches []*wireformat.MetricBatch) (*wireformat.Response, error) {
+	conn, err := net.DialTimeout("tcp", s.addr, graphiteDialTimeout)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer conn.Close()
+	// Carbon never replies on the plaintext protocol, so without a
+	// deadline a stalled endpoint would block the sender forever.
+	if err := conn.SetDeadline(time.Now().Add(graphiteSendTimeout)); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := bufio.NewWriter(conn)
+	for _, batch := range batches {
+		prefix := strings.Join([]string{
+			"juju",
+			graphitePathElement(batch.EnvUUID),
+			graphitePathElement(strings.Replace(batch.UnitName, "/", ".", 1)),
+		}, ".")
+		for _, m := range batch.Metrics {
+			_, err := fmt.Fprintf(w, "%s.%s %s %d\n",
+				prefix, graphitePathElement(m.Key), m.Value, m.Time.Unix(),
+			)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ackBatches(batches)
+}
+
+var graphiteEscaper = strings.NewReplacer(" ", "_", "\t", "_", "\n", "_")
+
+// graphitePathElement replaces characters that cannot appear in
+// a Graphite metric path.
+func graphitePathElement(s string) string {
+	return graphiteEscaper.Replace(s)
+}
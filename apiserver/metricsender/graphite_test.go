@@ -0,0 +1,108 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsender_test
+
+import (
+	"io/ioutil"
+	"net"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/metricsender"
+	"github.com/juju/juju/testing"
+)
+
+type GraphiteSenderSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&GraphiteSenderSuite{})
+
+var _ metricsender.MetricSender = (*metricsender.GraphiteSender)(nil)
+
+func (s *GraphiteSenderSuite) TestSend(c *gc.C) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	defer listener.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			received <- err.Error()
+			return
+		}
+		defer conn.Close()
+		data, err := ioutil.ReadAll(conn)
+		if err != nil {
+			received <- err.Error()
+			return
+		}
+		received <- string(data)
+	}()
+
+	sender, err := metricsender.NewGraphiteSender(listener.Addr().String())
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := sender.Send(testBatches())
+	c.Assert(err, jc.ErrorIsNil)
+	assertAcked(c, resp)
+
+	select {
+	case data := <-received:
+		c.Assert(data, gc.Equals, ""+
+			"juju.env-uuid.metered.0.pings 5 1444000000\n"+
+			"juju.env-uuid.metered.1.juju-units 1.5 1444000000\n",
+		)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for metrics")
+	}
+}
+
+func (s *GraphiteSenderSuite) TestSendConnectionRefused(c *gc.C) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	sender, err := metricsender.NewGraphiteSender(addr)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = sender.Send(testBatches())
+	c.Assert(err, gc.ErrorMatches, ".*connection refused")
+}
+
+func (s *GraphiteSenderSuite) TestSendTimeout(c *gc.C) {
+	defer metricsender.PatchGraphiteSendTimeout(50 * time.Millisecond)()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	defer listener.Close()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		// Accept the connection, but never read from it.
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		<-done
+	}()
+
+	// Send more than the socket buffers can hold, so that
+	// writing blocks until the deadline passes.
+	batches := testBatches()
+	metric := batches[0].Metrics[0]
+	for i := 0; i < 1000000; i++ {
+		batches[0].Metrics = append(batches[0].Metrics, metric)
+	}
+	sender, err := metricsender.NewGraphiteSender(listener.Addr().String())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = sender.Send(batches)
+	c.Assert(err, gc.ErrorMatches, ".*i/o timeout")
+}
+
+func (s *GraphiteSenderSuite) TestInvalidAddress(c *gc.C) {
+	_, err := metricsender.NewGraphiteSender("localhost")
+	c.Assert(err, gc.ErrorMatches, "invalid Graphite address: .*missing port in address.*")
+}
@@ -0,0 +1,97 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsender
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/metricsender/wireformat"
+)
+
+// InfluxDBSenderName is the name under which the InfluxDB sender
+// is registered.
+const InfluxDBSenderName = "influxdb"
+
+func init() {
+	RegisterSender(InfluxDBSenderName, NewInfluxDBSender)
+}
+
+// influxDBTimeout bounds the time taken to send metrics to InfluxDB.
+var influxDBTimeout = 30 * time.Second
+
+// InfluxDBSender sends metrics to an InfluxDB HTTP write endpoint
+// using the line protocol.
+type InfluxDBSender struct {
+	url    string
+	client *http.Client
+}
+
+// NewInfluxDBSender returns a sender that posts metrics to the given
+// InfluxDB write URL, e.g. http://localhost:8086/write?db=juju.
+func NewInfluxDBSender(target string) (MetricSender, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, errors.Annotate(err, "invalid InfluxDB URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.NotValidf("InfluxDB URL %q", target)
+	}
+	return &InfluxDBSender{
+		url:    target,
+		client: &http.Client{Timeout: influxDBTimeout},
+	}, nil
+}
+
+// Send sends the given metrics to InfluxDB.
+func (s *InfluxDBSender) Send(batches []*wireformat.MetricBatch) (*wireformat.Response, error) {
+	var buf bytes.Buffer
+	for _, batch := range batches {
+		for _, m := range batch.Metrics {
+			fmt.Fprintf(&buf, "%s,env=%s,unit=%s,charm=%s value=%s %d\n",
+				influxEscape(m.Key),
+				influxEscape(batch.EnvUUID),
+				influxEscape(batch.UnitName),
+				influxEscape(batch.CharmUrl),
+				influxFieldValue(m.Value),
+				m.Time.UnixNano(),
+			)
+		}
+	}
+	resp, err := s.client.Post(s.url, "text/plain", &buf)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	// InfluxDB responds with 204 No Content on success.
+	if resp.StatusCode/100 != 2 {
+		return nil, errors.Errorf("failed to send metrics http %v", resp.StatusCode)
+	}
+	return ackBatches(batches)
+}
+
+var influxEscaper = strings.NewReplacer(",", `\,`, " ", `\ `, "=", `\=`)
+
+// influxEscape escapes the characters that are significant in
+// line protocol measurement names and tags.
+func influxEscape(s string) string {
+	return influxEscaper.Replace(s)
+}
+
+// influxFieldValue formats a metric value as a line protocol field
+// value. Numeric values are written as floats, anything else as a
+// quoted string.
+func influxFieldValue(v string) string {
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return v
+	}
+	return strconv.Quote(v)
+}
@@ -0,0 +1,80 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsender_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/metricsender"
+	"github.com/juju/juju/testing"
+)
+
+type InfluxDBSenderSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&InfluxDBSenderSuite{})
+
+var _ metricsender.MetricSender = (*metricsender.InfluxDBSender)(nil)
+
+func (s *InfluxDBSenderSuite) TestSend(c *gc.C) {
+	var body string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, gc.Equals, "POST")
+		c.Check(r.URL.Query().Get("db"), gc.Equals, "juju")
+		data, err := ioutil.ReadAll(r.Body)
+		c.Check(err, jc.ErrorIsNil)
+		body = string(data)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	sender, err := metricsender.NewInfluxDBSender(ts.URL + "/write?db=juju")
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := sender.Send(testBatches())
+	c.Assert(err, jc.ErrorIsNil)
+	assertAcked(c, resp)
+	c.Assert(body, gc.Equals, ""+
+		"pings,env=env-uuid,unit=metered/0,charm=cs:quantal/metered value=5 1444000000000000000\n"+
+		"juju-units,env=env-uuid,unit=metered/1,charm=cs:quantal/metered value=1.5 1444000000000000000\n",
+	)
+}
+
+func (s *InfluxDBSenderSuite) TestSendError(c *gc.C) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	sender, err := metricsender.NewInfluxDBSender(ts.URL + "/write?db=juju")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = sender.Send(testBatches())
+	c.Assert(err, gc.ErrorMatches, "failed to send metrics http 400")
+}
+
+func (s *InfluxDBSenderSuite) TestSendTimeout(c *gc.C) {
+	defer metricsender.PatchInfluxDBTimeout(10 * time.Millisecond)()
+	unblock := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+	}))
+	defer ts.Close()
+	defer close(unblock)
+
+	sender, err := metricsender.NewInfluxDBSender(ts.URL + "/write?db=juju")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = sender.Send(testBatches())
+	c.Assert(err, gc.ErrorMatches, ".*(Client.Timeout exceeded|request canceled).*")
+}
+
+func (s *InfluxDBSenderSuite) TestInvalidURL(c *gc.C) {
+	_, err := metricsender.NewInfluxDBSender("localhost:8086")
+	c.Assert(err, gc.ErrorMatches, `InfluxDB URL "localhost:8086" not valid`)
+}
@@ -0,0 +1,90 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsender
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/apiserver/metricsender/wireformat"
+	"github.com/juju/juju/environs/config"
+)
+
+// SenderFactory creates a MetricSender that delivers metrics to
+// the given target. The meaning of the target depends on the sender;
+// it may be a URL, a network address or a file path.
+type SenderFactory func(target string) (MetricSender, error)
+
+var (
+	sendersMu sync.Mutex
+	senders   = make(map[string]SenderFactory)
+)
+
+// RegisterSender registers a new metric sender factory under the given
+// name. It panics if a sender with the same name is already registered.
+func RegisterSender(name string, factory SenderFactory) {
+	sendersMu.Lock()
+	defer sendersMu.Unlock()
+	if _, ok := senders[name]; ok {
+		panic(fmt.Errorf("juju: duplicate metric sender name %q", name))
+	}
+	senders[name] = factory
+}
+
+// RegisteredSenders returns the names of all registered metric senders.
+func RegisteredSenders() []string {
+	sendersMu.Lock()
+	defer sendersMu.Unlock()
+	names := make([]string, 0, len(senders))
+	for name := range senders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewSender returns a MetricSender created by the factory registered
+// under the given name, delivering metrics to the specified target.
+func NewSender(name, target string) (MetricSender, error) {
+	sendersMu.Lock()
+	factory, ok := senders[name]
+	sendersMu.Unlock()
+	if !ok {
+		return nil, errors.NotFoundf("metric sender %q", name)
+	}
+	sender, err := factory(target)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot create metric sender %q", name)
+	}
+	return sender, nil
+}
+
+// SenderForConfig returns the MetricSender selected by the given
+// environment configuration, or the default sender if none is selected.
+func SenderForConfig(cfg *config.Config) (MetricSender, error) {
+	name, ok := cfg.MetricsSender()
+	if !ok {
+		return DefaultMetricSender(), nil
+	}
+	return NewSender(name, cfg.MetricsSenderURL())
+}
+
+// ackBatches returns a response acknowledging all of the given batches.
+// It is used by senders that deliver metrics to systems that do not
+// report unit meter statuses back to Juju.
+func ackBatches(batches []*wireformat.MetricBatch) (*wireformat.Response, error) {
+	resp := make(wireformat.EnvironmentResponses)
+	for _, batch := range batches {
+		resp.Ack(batch.EnvUUID, batch.UUID)
+	}
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &wireformat.Response{UUID: uuid.String(), EnvResponses: resp}, nil
+}
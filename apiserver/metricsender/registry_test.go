@@ -0,0 +1,96 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsender_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/metricsender"
+	"github.com/juju/juju/apiserver/metricsender/wireformat"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/testing"
+)
+
+type RegistrySuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&RegistrySuite{})
+
+// testBatches returns two metric batches, each holding a single metric,
+// for exercising senders without a running state server.
+func testBatches() []*wireformat.MetricBatch {
+	t := time.Unix(1444000000, 0).UTC()
+	return []*wireformat.MetricBatch{{
+		UUID:     "batch-0",
+		EnvUUID:  "env-uuid",
+		UnitName: "metered/0",
+		CharmUrl: "cs:quantal/metered",
+		Created:  t,
+		Metrics:  []wireformat.Metric{{Key: "pings", Value: "5", Time: t}},
+	}, {
+		UUID:     "batch-1",
+		EnvUUID:  "env-uuid",
+		UnitName: "metered/1",
+		CharmUrl: "cs:quantal/metered",
+		Created:  t,
+		Metrics:  []wireformat.Metric{{Key: "juju-units", Value: "1.5", Time: t}},
+	}}
+}
+
+// assertAcked checks that the response acknowledges all of the test batches.
+func assertAcked(c *gc.C, resp *wireformat.Response) {
+	c.Assert(resp, gc.NotNil)
+	c.Assert(resp.EnvResponses["env-uuid"].AcknowledgedBatches, jc.SameContents, []string{"batch-0", "batch-1"})
+}
+
+func (s *RegistrySuite) TestRegisteredSenders(c *gc.C) {
+	c.Assert(metricsender.RegisteredSenders(), jc.DeepEquals, []string{"file", "graphite", "influxdb"})
+}
+
+func (s *RegistrySuite) TestRegisterSenderDuplicate(c *gc.C) {
+	c.Assert(func() {
+		metricsender.RegisterSender("file", metricsender.NewFileSender)
+	}, gc.PanicMatches, `juju: duplicate metric sender name "file"`)
+}
+
+func (s *RegistrySuite) TestNewSenderUnknown(c *gc.C) {
+	_, err := metricsender.NewSender("statsd", "localhost:8125")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `metric sender "statsd" not found`)
+}
+
+func (s *RegistrySuite) TestNewSenderInvalidTarget(c *gc.C) {
+	_, err := metricsender.NewSender("file", "relative/path")
+	c.Assert(err, gc.ErrorMatches, `cannot create metric sender "file": metrics file name "relative/path" not valid`)
+}
+
+func (s *RegistrySuite) TestSenderForConfigDefault(c *gc.C) {
+	cfg, err := config.New(config.UseDefaults, testing.FakeConfig())
+	c.Assert(err, jc.ErrorIsNil)
+	sender, err := metricsender.SenderForConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sender, gc.Equals, metricsender.DefaultMetricSender())
+}
+
+func (s *RegistrySuite) TestSenderForConfig(c *gc.C) {
+	cfg, err := config.New(config.UseDefaults, testing.FakeConfig().Merge(testing.Attrs{
+		"metrics-sender":     "graphite",
+		"metrics-sender-url": "localhost:2003",
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	sender, err := metricsender.SenderForConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sender, gc.FitsTypeOf, &metricsender.GraphiteSender{})
+}
+
+func (s *RegistrySuite) TestNopSenderAcksAll(c *gc.C) {
+	resp, err := metricsender.NopSender{}.Send(testBatches())
+	c.Assert(err, jc.ErrorIsNil)
+	assertAcked(c, resp)
+}
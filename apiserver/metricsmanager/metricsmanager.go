@@ -20,9 +20,19 @@ var (
 	logger            = loggo.GetLogger("juju.apiserver.metricsmanager")
 	maxBatchesPerSend = metricsender.DefaultMaxBatchesPerSend()
 
-	sender = metricsender.DefaultMetricSender()
+	newSender = senderForState
 )
 
+// senderForState returns the metric sender selected by
+// the environment configuration.
+func senderForState(st *state.State) (metricsender.MetricSender, error) {
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return metricsender.SenderForConfig(cfg)
+}
+
 func init() {
 	common.RegisterStandardFacade("MetricsManager", 0, NewMetricsManagerAPI)
 }
@@ -125,6 +135,13 @@ func (api *MetricsManagerAPI) SendMetrics(args params.Entities) (params.ErrorRes
 			result.Results[i].Error = common.ServerError(common.ErrPerm)
 			continue
 		}
+		sender, err := newSender(api.state)
+		if err != nil {
+			err = errors.Annotate(err, "failed to create metric sender")
+			logger.Warningf("%v", err)
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
 		err = metricsender.SendMetrics(api.state, sender, maxBatchesPerSend)
 		if err != nil {
 			err = errors.Annotate(err, "failed to send metrics")
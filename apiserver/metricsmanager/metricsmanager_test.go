@@ -4,6 +4,9 @@
 package metricsmanager_test
 
 import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"time"
 
 	"github.com/juju/errors"
@@ -24,9 +27,10 @@ import (
 type metricsManagerSuite struct {
 	jujutesting.JujuConnSuite
 
-	metricsmanager *metricsmanager.MetricsManagerAPI
-	authorizer     apiservertesting.FakeAuthorizer
-	unit           *state.Unit
+	metricsmanager   *metricsmanager.MetricsManagerAPI
+	metricsmanagerV1 *metricsmanager.MetricsManagerAPIV1
+	authorizer       apiservertesting.FakeAuthorizer
+	unit             *state.Unit
 }
 
 var _ = gc.Suite(&metricsManagerSuite{})
@@ -40,6 +44,7 @@ func (s *metricsManagerSuite) SetUpTest(c *gc.C) {
 	manager, err := metricsmanager.NewMetricsManagerAPI(s.State, nil, s.authorizer)
 	c.Assert(err, jc.ErrorIsNil)
 	s.metricsmanager = manager
+	s.metricsmanagerV1 = &metricsmanager.MetricsManagerAPIV1{MetricsManagerAPI: *manager}
 	meteredCharm := s.Factory.MakeCharm(c, &factory.CharmParams{Name: "metered", URL: "cs:quantal/metered"})
 	meteredService := s.Factory.MakeService(c, &factory.ServiceParams{Charm: meteredCharm})
 	s.unit = s.Factory.MakeUnit(c, &factory.UnitParams{Service: meteredService, SetCharmURL: true})
@@ -209,3 +214,76 @@ func (s *metricsManagerSuite) TestLastSuccessfulNotChangedIfNothingToSend(c *gc.
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(mm.LastSuccessfulSend().Equal(time.Time{}), jc.IsTrue)
 }
+
+func (s *metricsManagerSuite) TestSendMetricsConfiguredSender(c *gc.C) {
+	metricsmanager.ResetSender()
+	var body string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := ioutil.ReadAll(r.Body)
+		c.Check(err, jc.ErrorIsNil)
+		body = string(data)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"metrics-sender":     "influxdb",
+		"metrics-sender-url": ts.URL + "/write?db=juju",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	now := time.Now()
+	metric := state.Metric{"pings", "5", now}
+	unsent := s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit, Sent: false, Time: &now, Metrics: []state.Metric{metric}})
+	args := params.Entities{Entities: []params.Entity{
+		{s.State.EnvironTag().String()},
+	}}
+	result, err := s.metricsmanager.SendMetrics(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	m, err := s.State.MetricBatch(unsent.UUID())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Sent(), jc.IsTrue)
+	c.Assert(body, jc.HasPrefix, "pings,env="+s.State.EnvironUUID())
+}
+
+func (s *metricsManagerSuite) TestSendMetricsUnknownSender(c *gc.C) {
+	metricsmanager.ResetSender()
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"metrics-sender":     "statsd",
+		"metrics-sender-url": "localhost:8125",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	args := params.Entities{Entities: []params.Entity{
+		{s.State.EnvironTag().String()},
+	}}
+	result, err := s.metricsmanager.SendMetrics(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, `failed to create metric sender: metric sender "statsd" not found`)
+}
+
+func (s *metricsManagerSuite) TestEvaluateThresholds(c *gc.C) {
+	_, err := s.State.AddMetricThreshold(state.MetricThresholdParams{
+		Service:  s.unit.ServiceName(),
+		Key:      "pings",
+		Operator: ">",
+		Value:    4,
+		Status:   "AMBER",
+		Info:     "too many pings",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	now := time.Now()
+	metric := state.Metric{"pings", "5", now}
+	s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit, Time: &now, Metrics: []state.Metric{metric}})
+	args := params.Entities{Entities: []params.Entity{
+		{"invalid"},
+		{s.State.EnvironTag().String()},
+	}}
+	result, err := s.metricsmanagerV1.EvaluateThresholds(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, `"invalid" is not a valid tag`)
+	c.Assert(result.Results[1].Error, gc.IsNil)
+	status, err := s.unit.GetMeterStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Code, gc.Equals, state.MeterAmber)
+	c.Assert(status.Info, gc.Equals, "too many pings")
+}
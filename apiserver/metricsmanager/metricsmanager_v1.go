@@ -0,0 +1,82 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsmanager
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("MetricsManager", 1, NewMetricsManagerAPIV1)
+}
+
+// MetricsManagerV1 defines the methods on version 1 of the
+// metricsmanager API end point.
+type MetricsManagerV1 interface {
+	MetricsManager
+	EvaluateThresholds(args params.Entities) (params.ErrorResults, error)
+}
+
+// MetricsManagerAPIV1 implements version 1 of the metrics manager
+// interface. It adds EvaluateThresholds to version 0.
+type MetricsManagerAPIV1 struct {
+	MetricsManagerAPI
+}
+
+var _ MetricsManagerV1 = (*MetricsManagerAPIV1)(nil)
+
+// NewMetricsManagerAPIV1 creates a new API endpoint for calling
+// metrics manager functions, version 1.
+func NewMetricsManagerAPIV1(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*MetricsManagerAPIV1, error) {
+	api, err := NewMetricsManagerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &MetricsManagerAPIV1{*api}, nil
+}
+
+// EvaluateThresholds checks the metrics recorded by units against the
+// metric thresholds defined in the environment, updating unit meter
+// statuses and raising or resolving alerts as necessary.
+func (api *MetricsManagerAPIV1) EvaluateThresholds(args params.Entities) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	if len(args.Entities) == 0 {
+		return result, nil
+	}
+	canAccess, err := api.accessEnviron()
+	if err != nil {
+		return result, err
+	}
+	for i, arg := range args.Entities {
+		tag, err := names.ParseEnvironTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = api.state.EvaluateMetricThresholds(time.Now())
+		if err != nil {
+			err = errors.Annotate(err, "failed to evaluate metric thresholds")
+			logger.Warningf("%v", err)
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
@@ -38,11 +38,14 @@ type Actions struct {
 }
 
 // Action describes an Action that will be or has been queued up.
+// If Timeout is positive, the Action is stopped and marked failed if
+// it runs for longer than that.
 type Action struct {
 	Tag        string                 `json:"tag"`
 	Receiver   string                 `json:"receiver"`
 	Name       string                 `json:"name"`
 	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Timeout    time.Duration          `json:"timeout,omitempty"`
 }
 
 // ActionResults is a slice of ActionResult for bulk requests.
@@ -117,3 +120,114 @@ type ServiceCharmActionsResult struct {
 	Actions    *charm.Actions `json:"actions,omitempty"`
 	Error      *Error         `json:"error,omitempty"`
 }
+
+// ScheduleActions holds a slice of ScheduleAction for a bulk
+// Schedule API call.
+type ScheduleActions struct {
+	Actions []ScheduleAction `json:"actions,omitempty"`
+}
+
+// ScheduleAction describes an Action to be enqueued on a receiver
+// each time a cron schedule fires.
+type ScheduleAction struct {
+	Receiver   string                 `json:"receiver"`
+	Name       string                 `json:"name"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Schedule   string                 `json:"schedule"`
+}
+
+// ScheduledActionIds holds the ids of scheduled actions.
+type ScheduledActionIds struct {
+	Ids []string `json:"ids"`
+}
+
+// ScheduledActionResults holds a slice of ScheduledActionResult.
+type ScheduledActionResults struct {
+	Results []ScheduledActionResult `json:"results,omitempty"`
+}
+
+// ScheduledActionResult holds a scheduled action, or an error.
+type ScheduledActionResult struct {
+	Result *ScheduledAction `json:"result,omitempty"`
+	Error  *Error           `json:"error,omitempty"`
+}
+
+// ScheduledAction describes an Action that is enqueued repeatedly
+// according to a cron schedule, along with its recent runs.
+type ScheduledAction struct {
+	Id         string                 `json:"id"`
+	Receiver   string                 `json:"receiver"`
+	Name       string                 `json:"name"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Schedule   string                 `json:"schedule"`
+	Created    time.Time              `json:"created"`
+	NextRun    time.Time              `json:"nextrun"`
+	Runs       []ScheduledActionRun   `json:"runs,omitempty"`
+}
+
+// ScheduledActionRun describes a single run of a scheduled action.
+// ActionTag identifies the Action that was enqueued; if it could not
+// be enqueued, Error holds the reason.
+type ScheduledActionRun struct {
+	ActionTag string    `json:"actiontag,omitempty"`
+	Enqueued  time.Time `json:"enqueued"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// ServiceActions holds a slice of ServiceAction for a bulk
+// EnqueueOperations API call.
+type ServiceActions struct {
+	Actions []ServiceAction `json:"actions,omitempty"`
+}
+
+// ServiceAction describes an Action to be enqueued on many units of a
+// service at once. If Units is empty, the Action is enqueued on all of
+// the service's units. If BatchSize is positive, at most that many of
+// the Actions are pending or running at any one time.
+type ServiceAction struct {
+	Service    string                 `json:"service"`
+	Name       string                 `json:"name"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Units      []string               `json:"units,omitempty"`
+	BatchSize  int                    `json:"batchsize,omitempty"`
+}
+
+// ActionOperationIds holds the ids, or unique id prefixes, of action
+// operations.
+type ActionOperationIds struct {
+	Ids []string `json:"ids"`
+}
+
+// ActionOperationResults holds a slice of ActionOperationResult.
+type ActionOperationResults struct {
+	Results []ActionOperationResult `json:"results,omitempty"`
+}
+
+// ActionOperationResult holds an action operation, or an error.
+type ActionOperationResult struct {
+	Operation *ActionOperation `json:"operation,omitempty"`
+	Error     *Error           `json:"error,omitempty"`
+}
+
+// ActionOperation describes an Action invoked on many units of a
+// service, and the progress of the Action on each unit.
+type ActionOperation struct {
+	Id         string                 `json:"id"`
+	Service    string                 `json:"service"`
+	Name       string                 `json:"name"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	BatchSize  int                    `json:"batchsize,omitempty"`
+	Enqueued   time.Time              `json:"enqueued"`
+	Units      []ActionOperationUnit  `json:"units"`
+}
+
+// ActionOperationUnit describes the progress of an action operation on
+// a single unit. ActionTag is empty if the Action has not been
+// enqueued on the unit, either because the unit is waiting for its
+// turn, or because the Action could not be enqueued.
+type ActionOperationUnit struct {
+	Unit      string `json:"unit"`
+	ActionTag string `json:"actiontag,omitempty"`
+	Status    string `json:"status"`
+	Message   string `json:"message,omitempty"`
+}
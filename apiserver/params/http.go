@@ -23,4 +23,7 @@ const (
 	ContentTypeJSON = "application/json"
 	// ContentTypeRaw is the HTTP content-type value used for raw, unformattedcontent.
 	ContentTypeRaw = "application/octet-stream"
+	// ContentTypeGzip is the HTTP content-type value used for
+	// gzip-compressed content, such as log archives.
+	ContentTypeGzip = "application/gzip"
 )
@@ -620,6 +620,93 @@ type MetricBatchParams struct {
 	Batches []MetricBatchParam
 }
 
+// MetricsQuery holds the parameters for retrieving the metrics
+// recorded for a single unit or service.
+type MetricsQuery struct {
+	// Tag is the tag of the unit or service.
+	Tag string
+	// Since, if non-zero, restricts the results to metric
+	// batches created at or after the given time.
+	Since time.Time
+}
+
+// MetricsQueries holds the parameters for retrieving metrics
+// for multiple units or services.
+type MetricsQueries struct {
+	Queries []MetricsQuery
+}
+
+// MetricResult holds a single metric value recorded by a unit.
+type MetricResult struct {
+	Key   string
+	Value string
+	Time  time.Time
+	Unit  string
+}
+
+// EntityMetrics holds the metrics recorded for a unit or service,
+// or an error.
+type EntityMetrics struct {
+	Metrics []MetricResult
+	Error   *Error
+}
+
+// MetricResults holds the metrics results for multiple units
+// or services.
+type MetricResults struct {
+	Results []EntityMetrics
+}
+
+// MetricThreshold describes a rule that sets the meter status of
+// a service's units when their metrics breach a threshold.
+type MetricThreshold struct {
+	Id       string
+	Service  string
+	Key      string
+	Operator string
+	Value    float64
+	Duration time.Duration
+	Status   string
+	Info     string
+}
+
+// MetricThresholds holds multiple metric thresholds.
+type MetricThresholds struct {
+	Thresholds []MetricThreshold
+}
+
+// MetricThresholdIds holds the IDs of multiple metric thresholds.
+type MetricThresholdIds struct {
+	Ids []string
+}
+
+// MetricAlertsQuery holds the parameters for retrieving metric alerts.
+type MetricAlertsQuery struct {
+	// Since restricts the results to alerts raised at or after
+	// the given time. Active alerts are always returned.
+	Since time.Time
+}
+
+// MetricAlert records a unit breaching a metric threshold.
+type MetricAlert struct {
+	Id        string
+	Threshold string
+	UnitTag   string
+	Key       string
+	Value     string
+	Status    string
+	Info      string
+	Raised    time.Time
+	// Resolved is nil while the alert is active.
+	Resolved *time.Time
+}
+
+// MetricAlertsResult holds the result of an API call to
+// retrieve metric alerts.
+type MetricAlertsResult struct {
+	Alerts []MetricAlert
+}
+
 // MeterStatusResult holds unit meter status or error.
 type MeterStatusResult struct {
 	Code  string
@@ -3,6 +3,8 @@
 
 package params
 
+import "time"
+
 // ClaimLeadershipBulkParams is a collection of parameters for making
 // a bulk leadership claim.
 type ClaimLeadershipBulkParams struct {
@@ -84,3 +86,70 @@ type MergeLeadershipSettingsParam struct {
 	// Settings are the Leadership settings you wish to merge in.
 	Settings Settings
 }
+
+// LeadershipLease describes a unit's leadership of a service.
+type LeadershipLease struct {
+
+	// Namespace is the lease namespace in which leadership is recorded.
+	Namespace string
+
+	// ServiceTag is the service whose leadership is described.
+	ServiceTag string
+
+	// UnitTag is the unit that holds leadership of the service.
+	UnitTag string
+
+	// Expiry is the latest time at which the unit's leadership might
+	// still be valid.
+	Expiry time.Time
+}
+
+// LeadershipLeasesResult holds the current leadership of every service
+// with a leader.
+type LeadershipLeasesResult struct {
+	Leases []LeadershipLease
+}
+
+// LeadershipHistoryQuery requests the most recent leadership changes
+// of a service.
+type LeadershipHistoryQuery struct {
+	// ServiceTag is the service whose leadership history is wanted.
+	ServiceTag string
+
+	// Size is the maximum number of changes to return.
+	Size int
+}
+
+// LeadershipHistoryQueries holds a set of leadership history queries.
+type LeadershipHistoryQueries struct {
+	Queries []LeadershipHistoryQuery
+}
+
+// LeadershipChange describes a unit taking over leadership of a service.
+type LeadershipChange struct {
+	// ServiceTag is the service whose leadership changed.
+	ServiceTag string
+
+	// Previous is the unit that was last recorded as the service's
+	// leader. It is empty if no leader had been recorded.
+	Previous string
+
+	// Leader is the unit that took over leadership.
+	Leader string
+
+	// Time is when the change was recorded.
+	Time time.Time
+}
+
+// LeadershipHistoryResult holds the leadership changes of a service,
+// oldest first, or an error.
+type LeadershipHistoryResult struct {
+	Changes []LeadershipChange
+	Error   *Error
+}
+
+// LeadershipHistoryResults holds the results of a set of leadership
+// history queries.
+type LeadershipHistoryResults struct {
+	Results []LeadershipHistoryResult
+}
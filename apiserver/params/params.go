@@ -181,6 +181,44 @@ type AddMachinesResult struct {
 	Error   *Error `json:"Error"`
 }
 
+// MachineDrainResults holds the results of a MachineDrains call.
+type MachineDrainResults struct {
+	Results []MachineDrainResult `json:"Results"`
+}
+
+// MachineDrainResult holds the progress of a single machine drain,
+// or an error if it could not be retrieved.
+type MachineDrainResult struct {
+	Result *MachineDrain `json:"Result,omitempty"`
+	Error  *Error        `json:"Error,omitempty"`
+}
+
+// MachineDrain describes the progress of evacuating the units from
+// a machine.
+type MachineDrain struct {
+	MachineId    string            `json:"MachineId"`
+	Status       string            `json:"Status"`
+	Message      string            `json:"Message,omitempty"`
+	Replacements []UnitReplacement `json:"Replacements,omitempty"`
+	Started      time.Time         `json:"Started"`
+	Updated      time.Time         `json:"Updated"`
+}
+
+// UnitReplacement pairs a unit on a draining machine with the unit
+// added elsewhere to replace it.
+type UnitReplacement struct {
+	Original    string `json:"Original"`
+	Replacement string `json:"Replacement"`
+}
+
+// AdvanceMachineDrainsArgs holds the parameters for the
+// MachineDrainer.AdvanceMachineDrains call.
+type AdvanceMachineDrainsArgs struct {
+	// ReplacementTimeout is how long a replacement unit has to settle
+	// before its drain is abandoned.
+	ReplacementTimeout time.Duration `json:"ReplacementTimeout"`
+}
+
 // DestroyMachines holds parameters for the DestroyMachines call.
 type DestroyMachines struct {
 	MachineNames []string
@@ -339,6 +377,11 @@ type AddServiceUnits struct {
 	NumUnits      int
 	ToMachineSpec string
 	Placement     []*instance.Placement
+
+	// AttachStorage holds the tags of detached storage instances
+	// to attach to the new unit, in place of creating new storage.
+	// It is only used by AddServiceUnitsAttachingStorage.
+	AttachStorage []string
 }
 
 // DestroyServiceUnits holds parameters for the DestroyUnits call.
@@ -766,11 +809,68 @@ type RebootActionResult struct {
 // endpoint.  Single character field names are used for serialisation
 // to keep the size down. These messages are going to be sent a lot.
 type LogRecord struct {
-	Time     time.Time   `json:"t"`
-	Module   string      `json:"m"`
-	Location string      `json:"l"`
-	Level    loggo.Level `json:"v"`
-	Message  string      `json:"x"`
+	Time     time.Time         `json:"t"`
+	Module   string            `json:"m"`
+	Location string            `json:"l"`
+	Level    loggo.Level       `json:"v"`
+	Message  string            `json:"x"`
+	Labels   map[string]string `json:"f,omitempty"`
+}
+
+// DebugLogRecord holds a log message, as sent to debug-log clients
+// which request JSON output. Imported is set for messages imported
+// from a log archive.
+type DebugLogRecord struct {
+	Time     time.Time         `json:"time"`
+	Entity   string            `json:"entity"`
+	Module   string            `json:"module"`
+	Location string            `json:"location"`
+	Level    string            `json:"level"`
+	Message  string            `json:"message"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Imported bool              `json:"imported,omitempty"`
+}
+
+// LogArchivesArgs holds the time range of the log archives to list.
+// Either end of the range may be omitted.
+type LogArchivesArgs struct {
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+}
+
+// LogArchive describes an archive of pruned log records.
+type LogArchive struct {
+	Id    string    `json:"id"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Count int       `json:"count"`
+	Size  int64     `json:"size"`
+}
+
+// LogArchivesResult holds the log archives of an environment.
+type LogArchivesResult struct {
+	Archives []LogArchive `json:"archives"`
+}
+
+// LogArchiveImportResult holds the number of log records imported
+// from a log archive.
+type LogArchiveImportResult struct {
+	Count int `json:"count"`
+}
+
+// LoggingOverride holds logging configuration applied to the agent
+// of a machine or unit, on top of the environment's logging-config,
+// until it expires.
+type LoggingOverride struct {
+	Tag     string    `json:"tag"`
+	Config  string    `json:"config"`
+	Expires time.Time `json:"expires"`
+}
+
+// LoggingOverrides holds the logging overrides to set, or that are
+// set, on agents.
+type LoggingOverrides struct {
+	Overrides []LoggingOverride `json:"overrides"`
 }
 
 // GetBundleChangesParams holds parameters for making GetBundleChanges calls.
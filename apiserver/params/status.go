@@ -181,6 +181,38 @@ type UnitStatusHistory struct {
 	Statuses []AgentStatus
 }
 
+// EnvironStatusHistoryArgs holds the parameters to filter the status
+// history of an environment.
+type EnvironStatusHistoryArgs struct {
+	// Size is the maximum number of entries to return.
+	Size int
+
+	// From and To, if set, bound the times of the entries returned.
+	From *time.Time
+	To   *time.Time
+
+	// Statuses, if not empty, restricts the entries returned to
+	// those with one of the given statuses.
+	Statuses []Status
+}
+
+// EntityStatusHistory holds a status history entry of an entity in an
+// environment.
+type EntityStatusHistory struct {
+	Tag    string
+	Kind   HistoryKind
+	Status Status
+	Info   string
+	Data   map[string]interface{}
+	Since  *time.Time
+}
+
+// EnvironStatusHistoryResult holds the status history of an
+// environment, oldest first.
+type EnvironStatusHistoryResult struct {
+	Statuses []EntityStatusHistory
+}
+
 const (
 	// DefaultMaxLogsPerEntity is the default value for logs for each entity
 	// that should be kept at any given time.
@@ -237,6 +269,10 @@ const (
 	KindAgent HistoryKind = "agent"
 	// KindWorkload represents a charm workload status history entry.
 	KindWorkload HistoryKind = "workload"
+	// KindMachine represents a machine status history entry.
+	KindMachine HistoryKind = "machine"
+	// KindService represents a service status history entry.
+	KindService HistoryKind = "service"
 )
 
 // Life describes the lifecycle state of an entity ("alive", "dying" or "dead").
@@ -3,7 +3,11 @@
 
 package params
 
-import "github.com/juju/juju/storage"
+import (
+	"time"
+
+	"github.com/juju/juju/storage"
+)
 
 // MachineBlockDevices holds a machine tag and the block devices present
 // on that machine.
@@ -94,6 +98,9 @@ type StorageAttachment struct {
 	Kind     StorageKind
 	Location string
 	Life     Life
+
+	// Size is the size of the attached volume or filesystem in MiB.
+	Size uint64
 }
 
 // StorageAttachmentId identifies a storage attachment by the tags of the
@@ -210,6 +217,10 @@ type VolumeParams struct {
 	Attributes map[string]interface{}  `json:"attributes,omitempty"`
 	Tags       map[string]string       `json:"tags,omitempty"`
 	Attachment *VolumeAttachmentParams `json:"attachment,omitempty"`
+
+	// Snapshot is the provider-supplied ID of the snapshot from
+	// which the volume is to be created, if any.
+	Snapshot string `json:"snapshot,omitempty"`
 }
 
 // VolumeAttachmentParams holds the parameters for creating a volume
@@ -690,3 +701,183 @@ type StorageAddParams struct {
 type StoragesAddParams struct {
 	Storages []StorageAddParams `json:"storages"`
 }
+
+// VolumeSnapshotIds holds a set of volume snapshot IDs.
+type VolumeSnapshotIds struct {
+	Ids []string `json:"ids"`
+}
+
+// VolumeSnapshotFilter holds a filter for the volume snapshot list
+// API call.
+type VolumeSnapshotFilter struct {
+	// Storage are storage tags to filter on.
+	Storage []string `json:"storage,omitempty"`
+}
+
+// VolumeSnapshotDetails describes a volume snapshot for the purpose
+// of volume snapshot CLI commands.
+type VolumeSnapshotDetails struct {
+	// Id is the unique ID of the snapshot.
+	Id string `json:"id"`
+
+	// StorageTag is the tag of the storage instance whose volume
+	// was snapshotted.
+	StorageTag string `json:"storagetag"`
+
+	// VolumeTag is the tag of the snapshotted volume.
+	VolumeTag string `json:"volumetag"`
+
+	// Pool is the storage pool of the snapshotted volume.
+	Pool string `json:"pool"`
+
+	// Life is the lifecycle state of the snapshot.
+	Life Life `json:"life"`
+
+	// Created is the time at which the snapshot was requested.
+	Created time.Time `json:"created"`
+
+	// SnapshotId is the provider-supplied ID of the snapshot, which
+	// is empty until the snapshot has been taken.
+	SnapshotId string `json:"snapshotid,omitempty"`
+
+	// Size is the size of the snapshotted volume in MiB, which is
+	// zero until the snapshot has been taken.
+	Size uint64 `json:"size,omitempty"`
+
+	// Status is the status of the snapshot.
+	Status EntityStatus `json:"status"`
+}
+
+// VolumeSnapshotDetailsResult contains details about a volume snapshot,
+// or an error preventing retrieving those details.
+type VolumeSnapshotDetailsResult struct {
+	Result *VolumeSnapshotDetails `json:"result,omitempty"`
+	Error  *Error                 `json:"error,omitempty"`
+}
+
+// VolumeSnapshotDetailsResults holds volume snapshot details.
+type VolumeSnapshotDetailsResults struct {
+	Results []VolumeSnapshotDetailsResult `json:"results,omitempty"`
+}
+
+// VolumeSnapshotRestoreParams holds the parameters for restoring a
+// volume snapshot to a unit, as a new storage instance.
+type VolumeSnapshotRestoreParams struct {
+	// Id is the ID of the volume snapshot to restore.
+	Id string `json:"id"`
+
+	// UnitTag is the tag of the unit to add the storage to.
+	UnitTag string `json:"unit"`
+
+	// StorageName is the name of the storage as specified in the charm.
+	StorageName string `json:"name"`
+}
+
+// VolumeSnapshotsRestoreParams holds the parameters for restoring
+// multiple volume snapshots.
+type VolumeSnapshotsRestoreParams struct {
+	Snapshots []VolumeSnapshotRestoreParams `json:"snapshots"`
+}
+
+// VolumeSnapshotParams holds the parameters for taking or deleting
+// a volume snapshot.
+type VolumeSnapshotParams struct {
+	Id        string `json:"id"`
+	Life      Life   `json:"life"`
+	VolumeTag string `json:"volumetag"`
+	VolumeId  string `json:"volumeid"`
+	Provider  string `json:"provider"`
+	Size      uint64 `json:"size"`
+
+	// SnapshotId is the provider-supplied ID of the snapshot, which
+	// is empty until the snapshot has been taken.
+	SnapshotId string `json:"snapshotid,omitempty"`
+}
+
+// VolumeSnapshotParamsResult holds provisioning parameters for a
+// volume snapshot.
+type VolumeSnapshotParamsResult struct {
+	Result VolumeSnapshotParams `json:"result"`
+	Error  *Error               `json:"error,omitempty"`
+}
+
+// VolumeSnapshotParamsResults holds provisioning parameters for
+// multiple volume snapshots.
+type VolumeSnapshotParamsResults struct {
+	Results []VolumeSnapshotParamsResult `json:"results,omitempty"`
+}
+
+// VolumeSnapshotInfo holds the provider-supplied information about
+// a volume snapshot, once it has been taken.
+type VolumeSnapshotInfo struct {
+	Id         string `json:"id"`
+	SnapshotId string `json:"snapshotid"`
+	Size       uint64 `json:"size"`
+}
+
+// VolumeSnapshotInfos holds information about multiple volume
+// snapshots.
+type VolumeSnapshotInfos struct {
+	Snapshots []VolumeSnapshotInfo `json:"snapshots"`
+}
+
+// VolumeSnapshotError records why a volume snapshot cannot be taken.
+type VolumeSnapshotError struct {
+	Id    string `json:"id"`
+	Error string `json:"error"`
+}
+
+// VolumeSnapshotErrors holds errors for multiple volume snapshots.
+type VolumeSnapshotErrors struct {
+	Snapshots []VolumeSnapshotError `json:"snapshots"`
+}
+
+// StorageResizeArg holds the parameters for growing the volume or
+// filesystem assigned to a storage instance.
+type StorageResizeArg struct {
+	// StorageTag is the tag of the storage instance to resize.
+	StorageTag string `json:"storagetag"`
+
+	// Size is the new size of the storage in MiB.
+	Size uint64 `json:"size"`
+}
+
+// StorageResizeArgs holds the parameters for resizing multiple
+// storage instances.
+type StorageResizeArgs struct {
+	Storages []StorageResizeArg `json:"storages"`
+}
+
+// StorageResizeIds holds a set of storage resize request IDs.
+type StorageResizeIds struct {
+	Ids []string `json:"ids"`
+}
+
+// StorageResizeParams holds the parameters for carrying out a
+// storage resize request. VolumeTag is set if a volume is to be
+// grown, and FilesystemTag if the storage is a filesystem. The
+// provider-supplied IDs are empty if the volume or filesystem has
+// since been removed.
+type StorageResizeParams struct {
+	Id            string `json:"id"`
+	Life          Life   `json:"life"`
+	VolumeTag     string `json:"volumetag,omitempty"`
+	VolumeId      string `json:"volumeid,omitempty"`
+	FilesystemTag string `json:"filesystemtag,omitempty"`
+	FilesystemId  string `json:"filesystemid,omitempty"`
+	Provider      string `json:"provider"`
+	Size          uint64 `json:"size"`
+}
+
+// StorageResizeParamsResult holds the parameters for a storage
+// resize request.
+type StorageResizeParamsResult struct {
+	Result StorageResizeParams `json:"result"`
+	Error  *Error              `json:"error,omitempty"`
+}
+
+// StorageResizeParamsResults holds the parameters for multiple
+// storage resize requests.
+type StorageResizeParamsResults struct {
+	Results []StorageResizeParamsResult `json:"results,omitempty"`
+}
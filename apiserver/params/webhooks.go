@@ -0,0 +1,114 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import "time"
+
+// AddWebhookParams holds the parameters for creating a webhook.
+type AddWebhookParams struct {
+	// URL is the http or https URL that changes are POSTed to.
+	URL string `json:"url"`
+
+	// Kinds restricts delivery to changes to entities of the given
+	// kinds. If empty, all kinds are delivered.
+	Kinds []string `json:"kinds,omitempty"`
+
+	// Statuses restricts delivery to changes to entities with one of
+	// the given statuses. If empty, changes are delivered regardless
+	// of status.
+	Statuses []string `json:"statuses,omitempty"`
+
+	// Secret is the key used to sign each delivery.
+	Secret string `json:"secret"`
+}
+
+// AddWebhooks holds the parameters for creating webhooks in bulk.
+type AddWebhooks struct {
+	Webhooks []AddWebhookParams `json:"webhooks"`
+}
+
+// Webhook describes a webhook. Its secret is never returned.
+type Webhook struct {
+	Id       string    `json:"id"`
+	URL      string    `json:"url"`
+	Kinds    []string  `json:"kinds,omitempty"`
+	Statuses []string  `json:"statuses,omitempty"`
+	Owner    string    `json:"owner"`
+	Created  time.Time `json:"created"`
+}
+
+// WebhookResult holds a webhook or an error.
+type WebhookResult struct {
+	Webhook Webhook `json:"webhook"`
+	Error   *Error  `json:"error,omitempty"`
+}
+
+// WebhookResults holds the results of a bulk webhook call.
+type WebhookResults struct {
+	Results []WebhookResult `json:"results"`
+}
+
+// ListWebhooksResult holds all webhooks in an environment.
+type ListWebhooksResult struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+// WebhookIds identifies webhooks by id.
+type WebhookIds struct {
+	Ids []string `json:"ids"`
+}
+
+// WebhookDeadLetter describes a delivery that could not be made to a
+// webhook.
+type WebhookDeadLetter struct {
+	WebhookId string    `json:"webhook-id"`
+	Payload   string    `json:"payload"`
+	Attempts  int       `json:"attempts"`
+	Error     string    `json:"error"`
+	Time      time.Time `json:"time"`
+}
+
+// WebhookDeadLettersResult holds the dead letters of a webhook, or an
+// error.
+type WebhookDeadLettersResult struct {
+	DeadLetters []WebhookDeadLetter `json:"dead-letters"`
+	Error       *Error              `json:"error,omitempty"`
+}
+
+// WebhookDeadLettersResults holds the results of a bulk dead letter
+// query.
+type WebhookDeadLettersResults struct {
+	Results []WebhookDeadLettersResult `json:"results"`
+}
+
+// WebhookTarget holds what the webhooks worker needs to deliver to a
+// webhook, including its secret.
+type WebhookTarget struct {
+	Id       string   `json:"id"`
+	URL      string   `json:"url"`
+	Kinds    []string `json:"kinds,omitempty"`
+	Statuses []string `json:"statuses,omitempty"`
+	Secret   string   `json:"secret"`
+}
+
+// WebhookTargetsResult holds the webhooks to deliver to, and the
+// controller addresses that deliveries must never be made to.
+type WebhookTargetsResult struct {
+	Webhooks            []WebhookTarget `json:"webhooks"`
+	ControllerAddresses []string        `json:"controller-addresses"`
+}
+
+// AddWebhookDeadLetter holds a delivery that could not be made to a
+// webhook.
+type AddWebhookDeadLetter struct {
+	WebhookId string `json:"webhook-id"`
+	Payload   string `json:"payload"`
+	Attempts  int    `json:"attempts"`
+	Error     string `json:"error"`
+}
+
+// AddWebhookDeadLetters holds dead letters to record in bulk.
+type AddWebhookDeadLetters struct {
+	DeadLetters []AddWebhookDeadLetter `json:"dead-letters"`
+}
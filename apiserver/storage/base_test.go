@@ -25,6 +25,7 @@ type baseStorageSuite struct {
 	authorizer testing.FakeAuthorizer
 
 	api   *storage.API
+	apiV2 *storage.APIV2
 	state *mockState
 
 	storageTag      names.StorageTag
@@ -59,6 +60,7 @@ func (s *baseStorageSuite) SetUpTest(c *gc.C) {
 	var err error
 	s.api, err = storage.CreateAPI(s.state, s.poolManager, s.resources, s.authorizer)
 	c.Assert(err, jc.ErrorIsNil)
+	s.apiV2 = &storage.APIV2{API: *s.api}
 }
 
 func (s *baseStorageSuite) assertCalls(c *gc.C, expectedCalls []string) {
@@ -4,6 +4,8 @@
 package storage_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	"gopkg.in/juju/charm.v6-unstable"
@@ -47,6 +49,8 @@ type mockState struct {
 	watchStorageAttachment              func(names.StorageTag, names.UnitTag) state.NotifyWatcher
 	watchFilesystemAttachment           func(names.MachineTag, names.FilesystemTag) state.NotifyWatcher
 	watchVolumeAttachment               func(names.MachineTag, names.VolumeTag) state.NotifyWatcher
+	watchVolume                         func(names.VolumeTag) state.NotifyWatcher
+	watchFilesystem                     func(names.FilesystemTag) state.NotifyWatcher
 	watchBlockDevices                   func(names.MachineTag) state.NotifyWatcher
 	envName                             string
 	volume                              func(tag names.VolumeTag) (state.Volume, error)
@@ -60,6 +64,14 @@ type mockState struct {
 	addStorageForUnit                   func(u names.UnitTag, name string, cons state.StorageConstraints) error
 	getBlockForType                     func(t state.BlockType) (state.Block, bool, error)
 	blockDevices                        func(names.MachineTag) ([]state.BlockDeviceInfo, error)
+	addVolumeSnapshot                   func(names.StorageTag) (state.VolumeSnapshot, error)
+	allVolumeSnapshots                  func() ([]state.VolumeSnapshot, error)
+	storageInstanceVolumeSnapshots      func(names.StorageTag) ([]state.VolumeSnapshot, error)
+	destroyVolumeSnapshot               func(id string) error
+	restoreVolumeSnapshot               func(u names.UnitTag, name, id string) error
+	resizeStorageInstance               func(names.StorageTag, uint64) (state.StorageResize, error)
+	detachStorage                       func(names.StorageTag, names.UnitTag) error
+	attachStorage                       func(names.StorageTag, names.UnitTag) error
 }
 
 func (st *mockState) StorageInstance(s names.StorageTag) (state.StorageInstance, error) {
@@ -106,6 +118,14 @@ func (st *mockState) WatchVolumeAttachment(mtag names.MachineTag, v names.Volume
 	return st.watchVolumeAttachment(mtag, v)
 }
 
+func (st *mockState) WatchVolume(v names.VolumeTag) state.NotifyWatcher {
+	return st.watchVolume(v)
+}
+
+func (st *mockState) WatchFilesystem(f names.FilesystemTag) state.NotifyWatcher {
+	return st.watchFilesystem(f)
+}
+
 func (st *mockState) WatchBlockDevices(mtag names.MachineTag) state.NotifyWatcher {
 	return st.watchBlockDevices(mtag)
 }
@@ -161,6 +181,38 @@ func (st *mockState) BlockDevices(m names.MachineTag) ([]state.BlockDeviceInfo,
 	return []state.BlockDeviceInfo{}, nil
 }
 
+func (st *mockState) AddVolumeSnapshot(tag names.StorageTag) (state.VolumeSnapshot, error) {
+	return st.addVolumeSnapshot(tag)
+}
+
+func (st *mockState) AllVolumeSnapshots() ([]state.VolumeSnapshot, error) {
+	return st.allVolumeSnapshots()
+}
+
+func (st *mockState) StorageInstanceVolumeSnapshots(tag names.StorageTag) ([]state.VolumeSnapshot, error) {
+	return st.storageInstanceVolumeSnapshots(tag)
+}
+
+func (st *mockState) DestroyVolumeSnapshot(id string) error {
+	return st.destroyVolumeSnapshot(id)
+}
+
+func (st *mockState) RestoreVolumeSnapshot(u names.UnitTag, name, id string) error {
+	return st.restoreVolumeSnapshot(u, name, id)
+}
+
+func (st *mockState) ResizeStorageInstance(tag names.StorageTag, size uint64) (state.StorageResize, error) {
+	return st.resizeStorageInstance(tag, size)
+}
+
+func (st *mockState) DetachStorage(storage names.StorageTag, unit names.UnitTag) error {
+	return st.detachStorage(storage, unit)
+}
+
+func (st *mockState) AttachStorage(storage names.StorageTag, unit names.UnitTag) error {
+	return st.attachStorage(storage, unit)
+}
+
 type mockNotifyWatcher struct {
 	state.NotifyWatcher
 	changes chan struct{}
@@ -347,3 +399,54 @@ func (b mockBlock) Type() state.BlockType {
 func (b mockBlock) Message() string {
 	return b.msg
 }
+
+type mockVolumeSnapshot struct {
+	state.VolumeSnapshot
+	id      string
+	storage names.StorageTag
+	volume  names.VolumeTag
+	created time.Time
+	info    *state.VolumeSnapshotInfo
+	err     string
+}
+
+func (m *mockVolumeSnapshot) Id() string {
+	return m.id
+}
+
+func (m *mockVolumeSnapshot) StorageInstance() names.StorageTag {
+	return m.storage
+}
+
+func (m *mockVolumeSnapshot) Volume() names.VolumeTag {
+	return m.volume
+}
+
+func (m *mockVolumeSnapshot) Pool() string {
+	return "loop"
+}
+
+func (m *mockVolumeSnapshot) Life() state.Life {
+	return state.Alive
+}
+
+func (m *mockVolumeSnapshot) Created() time.Time {
+	return m.created
+}
+
+func (m *mockVolumeSnapshot) Info() (state.VolumeSnapshotInfo, error) {
+	if m.info != nil {
+		return *m.info, nil
+	}
+	return state.VolumeSnapshotInfo{}, errors.NotProvisionedf("volume snapshot %q", m.id)
+}
+
+func (m *mockVolumeSnapshot) Status() (state.Status, string) {
+	switch {
+	case m.info != nil:
+		return state.StatusActive, ""
+	case m.err != "":
+		return state.StatusError, m.err
+	}
+	return state.StatusPending, ""
+}
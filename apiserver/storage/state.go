@@ -44,6 +44,12 @@ type storageAccess interface {
 	// WatchVolumeAttachment is required for storage functionality.
 	WatchVolumeAttachment(names.MachineTag, names.VolumeTag) state.NotifyWatcher
 
+	// WatchVolume is required for storage functionality.
+	WatchVolume(names.VolumeTag) state.NotifyWatcher
+
+	// WatchFilesystem is required for storage functionality.
+	WatchFilesystem(names.FilesystemTag) state.NotifyWatcher
+
 	// WatchBlockDevices is required for storage functionality.
 	WatchBlockDevices(names.MachineTag) state.NotifyWatcher
 
@@ -82,6 +88,30 @@ type storageAccess interface {
 
 	// GetBlockForType is required to block operations.
 	GetBlockForType(t state.BlockType) (state.Block, bool, error)
+
+	// AddVolumeSnapshot is required for snapshot functionality.
+	AddVolumeSnapshot(names.StorageTag) (state.VolumeSnapshot, error)
+
+	// AllVolumeSnapshots is required for snapshot functionality.
+	AllVolumeSnapshots() ([]state.VolumeSnapshot, error)
+
+	// StorageInstanceVolumeSnapshots is required for snapshot functionality.
+	StorageInstanceVolumeSnapshots(names.StorageTag) ([]state.VolumeSnapshot, error)
+
+	// DestroyVolumeSnapshot is required for snapshot functionality.
+	DestroyVolumeSnapshot(id string) error
+
+	// RestoreVolumeSnapshot is required for snapshot functionality.
+	RestoreVolumeSnapshot(unit names.UnitTag, storageName, id string) error
+
+	// ResizeStorageInstance is required for storage resize functionality.
+	ResizeStorageInstance(names.StorageTag, uint64) (state.StorageResize, error)
+
+	// DetachStorage is required for storage detach functionality.
+	DetachStorage(names.StorageTag, names.UnitTag) error
+
+	// AttachStorage is required for storage attach functionality.
+	AttachStorage(names.StorageTag, names.UnitTag) error
 }
 
 var getState = func(st *state.State) storageAccess {
@@ -694,3 +694,72 @@ func (a *API) AddToUnit(args params.StoragesAddParams) (params.ErrorResults, err
 	}
 	return params.ErrorResults{Results: result}, nil
 }
+
+// ResizeStorage requests that the storage instances with the specified
+// tags be grown to the specified sizes, in MiB. The storage is resized
+// asynchronously by the storage provisioner, after which the unit that
+// owns the storage is notified with a "storage-resized" hook.
+// A "CHANGE" block can block this operation.
+func (a *API) ResizeStorage(args params.StorageResizeArgs) (params.ErrorResults, error) {
+	blockChecker := common.NewBlockChecker(a.storage)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := make([]params.ErrorResult, len(args.Storages))
+	for i, arg := range args.Storages {
+		storageTag, err := names.ParseStorageTag(arg.StorageTag)
+		if err == nil {
+			_, err = a.storage.ResizeStorageInstance(storageTag, arg.Size)
+		}
+		if errors.IsNotFound(err) {
+			err = common.ErrPerm
+		}
+		results[i].Error = common.ServerError(err)
+	}
+	return params.ErrorResults{Results: results}, nil
+}
+
+// DetachStorage detaches storage instances from the units that own
+// them, transferring ownership of the storage to the units' services.
+// Detached storage outlives the unit it was detached from, and may be
+// attached to another unit of the same service.
+// A "CHANGE" block can block this operation.
+func (a *API) DetachStorage(args params.StorageAttachmentIds) (params.ErrorResults, error) {
+	return a.storageAttachmentOp(args, a.storage.DetachStorage)
+}
+
+// AttachStorage attaches detached storage instances to units of the
+// services that own the storage.
+// A "CHANGE" block can block this operation.
+func (a *API) AttachStorage(args params.StorageAttachmentIds) (params.ErrorResults, error) {
+	return a.storageAttachmentOp(args, a.storage.AttachStorage)
+}
+
+func (a *API) storageAttachmentOp(
+	args params.StorageAttachmentIds,
+	op func(names.StorageTag, names.UnitTag) error,
+) (params.ErrorResults, error) {
+	blockChecker := common.NewBlockChecker(a.storage)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := make([]params.ErrorResult, len(args.Ids))
+	for i, id := range args.Ids {
+		err := func() error {
+			storageTag, err := names.ParseStorageTag(id.StorageTag)
+			if err != nil {
+				return err
+			}
+			unitTag, err := names.ParseUnitTag(id.UnitTag)
+			if err != nil {
+				return err
+			}
+			return op(storageTag, unitTag)
+		}()
+		if errors.IsNotFound(err) {
+			err = common.ErrPerm
+		}
+		results[i].Error = common.ServerError(err)
+	}
+	return params.ErrorResults{Results: results}, nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Storage", 2, NewAPIV2)
+}
+
+// APIV2 implements version 2 of the Storage API facade. It adds
+// volume snapshots to version 1.
+type APIV2 struct {
+	API
+}
+
+// NewAPIV2 returns a new storage API facade, version 2.
+func NewAPIV2(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*APIV2, error) {
+	api, err := NewAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &APIV2{*api}, nil
+}
+
+// CreateVolumeSnapshots requests snapshots of the volumes assigned to
+// the storage instances with the specified tags. The snapshots are
+// taken asynchronously by the storage provisioner.
+// A "CHANGE" block can block this operation.
+func (a *APIV2) CreateVolumeSnapshots(args params.Entities) (params.VolumeSnapshotDetailsResults, error) {
+	blockChecker := common.NewBlockChecker(a.storage)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.VolumeSnapshotDetailsResults{}, errors.Trace(err)
+	}
+	results := make([]params.VolumeSnapshotDetailsResult, len(args.Entities))
+	for i, arg := range args.Entities {
+		storageTag, err := names.ParseStorageTag(arg.Tag)
+		if err != nil {
+			results[i].Error = common.ServerError(err)
+			continue
+		}
+		snapshot, err := a.storage.AddVolumeSnapshot(storageTag)
+		if err != nil {
+			results[i].Error = common.ServerError(err)
+			continue
+		}
+		results[i].Result = createVolumeSnapshotDetails(snapshot)
+	}
+	return params.VolumeSnapshotDetailsResults{Results: results}, nil
+}
+
+// ListVolumeSnapshots returns a list of volume snapshots in the
+// environment, optionally filtered by storage instance.
+func (a *APIV2) ListVolumeSnapshots(filter params.VolumeSnapshotFilter) (params.VolumeSnapshotDetailsResults, error) {
+	var snapshots []state.VolumeSnapshot
+	if len(filter.Storage) == 0 {
+		all, err := a.storage.AllVolumeSnapshots()
+		if err != nil {
+			return params.VolumeSnapshotDetailsResults{}, common.ServerError(err)
+		}
+		snapshots = all
+	}
+	for _, tag := range filter.Storage {
+		storageTag, err := names.ParseStorageTag(tag)
+		if err != nil {
+			return params.VolumeSnapshotDetailsResults{}, common.ServerError(err)
+		}
+		storageSnapshots, err := a.storage.StorageInstanceVolumeSnapshots(storageTag)
+		if err != nil {
+			return params.VolumeSnapshotDetailsResults{}, common.ServerError(err)
+		}
+		snapshots = append(snapshots, storageSnapshots...)
+	}
+	results := make([]params.VolumeSnapshotDetailsResult, len(snapshots))
+	for i, snapshot := range snapshots {
+		results[i].Result = createVolumeSnapshotDetails(snapshot)
+	}
+	return params.VolumeSnapshotDetailsResults{Results: results}, nil
+}
+
+// DestroyVolumeSnapshots destroys the volume snapshots with the
+// specified IDs. The snapshots are deleted asynchronously by the
+// storage provisioner.
+// A "REMOVE" block can block this operation.
+func (a *APIV2) DestroyVolumeSnapshots(args params.VolumeSnapshotIds) (params.ErrorResults, error) {
+	blockChecker := common.NewBlockChecker(a.storage)
+	if err := blockChecker.RemoveAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := make([]params.ErrorResult, len(args.Ids))
+	for i, id := range args.Ids {
+		var err error
+		if !state.IsValidVolumeSnapshotId(id) {
+			err = errors.NotValidf("volume snapshot ID %q", id)
+		} else {
+			err = a.storage.DestroyVolumeSnapshot(id)
+		}
+		results[i].Error = common.ServerError(err)
+	}
+	return params.ErrorResults{Results: results}, nil
+}
+
+// RestoreVolumeSnapshots creates new storage instances for units,
+// with volumes created from the specified volume snapshots.
+// A "CHANGE" block can block this operation.
+func (a *APIV2) RestoreVolumeSnapshots(args params.VolumeSnapshotsRestoreParams) (params.ErrorResults, error) {
+	blockChecker := common.NewBlockChecker(a.storage)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := make([]params.ErrorResult, len(args.Snapshots))
+	for i, arg := range args.Snapshots {
+		unitTag, err := names.ParseUnitTag(arg.UnitTag)
+		if err != nil {
+			err = errors.Annotatef(err, "parsing unit tag %v", arg.UnitTag)
+		} else {
+			err = a.storage.RestoreVolumeSnapshot(unitTag, arg.StorageName, arg.Id)
+		}
+		if errors.IsNotFound(err) {
+			err = common.ErrPerm
+		}
+		results[i].Error = common.ServerError(err)
+	}
+	return params.ErrorResults{Results: results}, nil
+}
+
+func createVolumeSnapshotDetails(snapshot state.VolumeSnapshot) *params.VolumeSnapshotDetails {
+	details := &params.VolumeSnapshotDetails{
+		Id:         snapshot.Id(),
+		StorageTag: snapshot.StorageInstance().String(),
+		VolumeTag:  snapshot.Volume().String(),
+		Pool:       snapshot.Pool(),
+		Life:       params.Life(snapshot.Life().String()),
+		Created:    snapshot.Created(),
+	}
+	if info, err := snapshot.Info(); err == nil {
+		details.SnapshotId = info.SnapshotId
+		details.Size = info.Size
+	}
+	status, message := snapshot.Status()
+	details.Status = params.EntityStatus{
+		Status: params.Status(status),
+		Info:   message,
+	}
+	return details
+}
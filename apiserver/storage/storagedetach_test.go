@@ -0,0 +1,94 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+type storageDetachSuite struct {
+	baseStorageSuite
+	detached []names.StorageTag
+	attached []names.StorageTag
+}
+
+var _ = gc.Suite(&storageDetachSuite{})
+
+func (s *storageDetachSuite) SetUpTest(c *gc.C) {
+	s.baseStorageSuite.SetUpTest(c)
+	s.detached = nil
+	s.attached = nil
+	s.state.detachStorage = func(storage names.StorageTag, unit names.UnitTag) error {
+		s.calls = append(s.calls, "detachStorage")
+		if storage != s.storageTag || unit != s.unitTag {
+			return errors.NotFoundf("%s", names.ReadableString(storage))
+		}
+		s.detached = append(s.detached, storage)
+		return nil
+	}
+	s.state.attachStorage = func(storage names.StorageTag, unit names.UnitTag) error {
+		s.calls = append(s.calls, "attachStorage")
+		if storage != s.storageTag {
+			return errors.NotFoundf("%s", names.ReadableString(storage))
+		}
+		if unit != s.unitTag {
+			return errors.New("storage is not detached")
+		}
+		s.attached = append(s.attached, storage)
+		return nil
+	}
+}
+
+func (s *storageDetachSuite) storageAttachmentIds() params.StorageAttachmentIds {
+	return params.StorageAttachmentIds{Ids: []params.StorageAttachmentId{
+		{StorageTag: s.storageTag.String(), UnitTag: s.unitTag.String()},
+		{StorageTag: "storage-foo-1", UnitTag: s.unitTag.String()},
+		{StorageTag: "volume-0", UnitTag: s.unitTag.String()},
+		{StorageTag: s.storageTag.String(), UnitTag: "machine-0"},
+	}}
+}
+
+func (s *storageDetachSuite) TestDetachStorage(c *gc.C) {
+	results, err := s.api.DetachStorage(s.storageAttachmentIds())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, jc.Satisfies, params.IsCodeUnauthorized)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `"volume-0" is not a valid storage tag`)
+	c.Assert(results.Results[3].Error, gc.ErrorMatches, `"machine-0" is not a valid unit tag`)
+	c.Assert(s.detached, jc.DeepEquals, []names.StorageTag{s.storageTag})
+	s.assertCalls(c, []string{getBlockForTypeCall, "detachStorage", "detachStorage"})
+}
+
+func (s *storageDetachSuite) TestDetachStorageBlocked(c *gc.C) {
+	s.blockAllChanges(c, "TestDetachStorageBlocked")
+	_, err := s.api.DetachStorage(s.storageAttachmentIds())
+	s.assertBlocked(c, err, "TestDetachStorageBlocked")
+}
+
+func (s *storageDetachSuite) TestAttachStorage(c *gc.C) {
+	results, err := s.api.AttachStorage(params.StorageAttachmentIds{Ids: []params.StorageAttachmentId{
+		{StorageTag: s.storageTag.String(), UnitTag: s.unitTag.String()},
+		{StorageTag: s.storageTag.String(), UnitTag: "unit-mysql-1"},
+		{StorageTag: "storage-foo-1", UnitTag: s.unitTag.String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "storage is not detached")
+	c.Assert(results.Results[2].Error, jc.Satisfies, params.IsCodeUnauthorized)
+	c.Assert(s.attached, jc.DeepEquals, []names.StorageTag{s.storageTag})
+	s.assertCalls(c, []string{getBlockForTypeCall, "attachStorage", "attachStorage", "attachStorage"})
+}
+
+func (s *storageDetachSuite) TestAttachStorageBlocked(c *gc.C) {
+	s.blockAllChanges(c, "TestAttachStorageBlocked")
+	_, err := s.api.AttachStorage(params.StorageAttachmentIds{})
+	s.assertBlocked(c, err, "TestAttachStorageBlocked")
+}
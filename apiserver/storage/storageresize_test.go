@@ -0,0 +1,59 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type storageResizeSuite struct {
+	baseStorageSuite
+	sizes map[names.StorageTag]uint64
+}
+
+var _ = gc.Suite(&storageResizeSuite{})
+
+func (s *storageResizeSuite) SetUpTest(c *gc.C) {
+	s.baseStorageSuite.SetUpTest(c)
+	s.sizes = make(map[names.StorageTag]uint64)
+	s.state.resizeStorageInstance = func(tag names.StorageTag, size uint64) (state.StorageResize, error) {
+		s.calls = append(s.calls, "resizeStorageInstance")
+		if tag != s.storageTag {
+			return nil, errors.NotFoundf("%s", names.ReadableString(tag))
+		}
+		s.sizes[tag] = size
+		return nil, nil
+	}
+}
+
+func (s *storageResizeSuite) TestResizeStorage(c *gc.C) {
+	results, err := s.api.ResizeStorage(params.StorageResizeArgs{
+		Storages: []params.StorageResizeArg{
+			{StorageTag: s.storageTag.String(), Size: 2048},
+			{StorageTag: "storage-foo-1", Size: 2048},
+			{StorageTag: "volume-0", Size: 2048},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, jc.Satisfies, params.IsCodeUnauthorized)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `"volume-0" is not a valid storage tag`)
+	c.Assert(s.sizes, jc.DeepEquals, map[names.StorageTag]uint64{s.storageTag: 2048})
+	s.assertCalls(c, []string{getBlockForTypeCall, "resizeStorageInstance", "resizeStorageInstance"})
+}
+
+func (s *storageResizeSuite) TestResizeStorageBlocked(c *gc.C) {
+	s.blockAllChanges(c, "TestResizeStorageBlocked")
+	_, err := s.api.ResizeStorage(params.StorageResizeArgs{
+		Storages: []params.StorageResizeArg{{StorageTag: s.storageTag.String(), Size: 2048}},
+	})
+	s.assertBlocked(c, err, "TestResizeStorageBlocked")
+}
@@ -0,0 +1,162 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type volumeSnapshotSuite struct {
+	baseStorageSuite
+	snapshot *mockVolumeSnapshot
+}
+
+var _ = gc.Suite(&volumeSnapshotSuite{})
+
+func (s *volumeSnapshotSuite) SetUpTest(c *gc.C) {
+	s.baseStorageSuite.SetUpTest(c)
+	s.snapshot = &mockVolumeSnapshot{
+		id:      "66/0",
+		storage: s.storageTag,
+		volume:  s.volumeTag,
+		created: time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC),
+	}
+	s.state.addVolumeSnapshot = func(tag names.StorageTag) (state.VolumeSnapshot, error) {
+		s.calls = append(s.calls, "addVolumeSnapshot")
+		if tag != s.storageTag {
+			return nil, errors.NotFoundf("%s", names.ReadableString(tag))
+		}
+		return s.snapshot, nil
+	}
+	s.state.allVolumeSnapshots = func() ([]state.VolumeSnapshot, error) {
+		s.calls = append(s.calls, "allVolumeSnapshots")
+		return []state.VolumeSnapshot{s.snapshot}, nil
+	}
+	s.state.storageInstanceVolumeSnapshots = func(tag names.StorageTag) ([]state.VolumeSnapshot, error) {
+		s.calls = append(s.calls, "storageInstanceVolumeSnapshots")
+		if tag != s.storageTag {
+			return nil, nil
+		}
+		return []state.VolumeSnapshot{s.snapshot}, nil
+	}
+	s.state.destroyVolumeSnapshot = func(id string) error {
+		s.calls = append(s.calls, "destroyVolumeSnapshot")
+		return nil
+	}
+	s.state.restoreVolumeSnapshot = func(u names.UnitTag, name, id string) error {
+		s.calls = append(s.calls, "restoreVolumeSnapshot")
+		if id != s.snapshot.id {
+			return errors.NotFoundf("volume snapshot %q", id)
+		}
+		return nil
+	}
+}
+
+func (s *volumeSnapshotSuite) expectedDetails() *params.VolumeSnapshotDetails {
+	return &params.VolumeSnapshotDetails{
+		Id:         "66/0",
+		StorageTag: s.storageTag.String(),
+		VolumeTag:  s.volumeTag.String(),
+		Pool:       "loop",
+		Life:       params.Alive,
+		Created:    s.snapshot.created,
+		Status:     params.EntityStatus{Status: params.StatusPending},
+	}
+}
+
+func (s *volumeSnapshotSuite) TestCreateVolumeSnapshots(c *gc.C) {
+	results, err := s.apiV2.CreateVolumeSnapshots(params.Entities{
+		Entities: []params.Entity{{s.storageTag.String()}, {"storage-foo-1"}, {"volume-0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0], jc.DeepEquals, params.VolumeSnapshotDetailsResult{
+		Result: s.expectedDetails(),
+	})
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `storage foo/1 not found`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `"volume-0" is not a valid storage tag`)
+	s.assertCalls(c, []string{getBlockForTypeCall, "addVolumeSnapshot", "addVolumeSnapshot"})
+}
+
+func (s *volumeSnapshotSuite) TestCreateVolumeSnapshotsBlocked(c *gc.C) {
+	s.blockAllChanges(c, "TestCreateVolumeSnapshotsBlocked")
+	_, err := s.apiV2.CreateVolumeSnapshots(params.Entities{
+		Entities: []params.Entity{{s.storageTag.String()}},
+	})
+	s.assertBlocked(c, err, "TestCreateVolumeSnapshotsBlocked")
+}
+
+func (s *volumeSnapshotSuite) TestListVolumeSnapshots(c *gc.C) {
+	s.snapshot.info = &state.VolumeSnapshotInfo{SnapshotId: "snap-0", Size: 1024}
+	expected := s.expectedDetails()
+	expected.SnapshotId = "snap-0"
+	expected.Size = 1024
+	expected.Status.Status = params.StatusActive
+
+	results, err := s.apiV2.ListVolumeSnapshots(params.VolumeSnapshotFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.VolumeSnapshotDetailsResult{{Result: expected}})
+	s.assertCalls(c, []string{"allVolumeSnapshots"})
+}
+
+func (s *volumeSnapshotSuite) TestListVolumeSnapshotsError(c *gc.C) {
+	s.snapshot.err = "snapshots not supported"
+	expected := s.expectedDetails()
+	expected.Status = params.EntityStatus{
+		Status: params.StatusError,
+		Info:   "snapshots not supported",
+	}
+
+	results, err := s.apiV2.ListVolumeSnapshots(params.VolumeSnapshotFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.VolumeSnapshotDetailsResult{{Result: expected}})
+}
+
+func (s *volumeSnapshotSuite) TestListVolumeSnapshotsFilterStorage(c *gc.C) {
+	results, err := s.apiV2.ListVolumeSnapshots(params.VolumeSnapshotFilter{
+		Storage: []string{s.storageTag.String(), "storage-foo-1"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.VolumeSnapshotDetailsResult{{Result: s.expectedDetails()}})
+	s.assertCalls(c, []string{"storageInstanceVolumeSnapshots", "storageInstanceVolumeSnapshots"})
+}
+
+func (s *volumeSnapshotSuite) TestDestroyVolumeSnapshots(c *gc.C) {
+	results, err := s.apiV2.DestroyVolumeSnapshots(params.VolumeSnapshotIds{Ids: []string{"66/0", "foo"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `volume snapshot ID "foo" not valid`)
+	s.assertCalls(c, []string{getBlockForTypeCall, "destroyVolumeSnapshot"})
+}
+
+func (s *volumeSnapshotSuite) TestDestroyVolumeSnapshotsBlocked(c *gc.C) {
+	s.blockRemoveObject(c, "TestDestroyVolumeSnapshotsBlocked")
+	_, err := s.apiV2.DestroyVolumeSnapshots(params.VolumeSnapshotIds{Ids: []string{"66/0"}})
+	s.assertBlocked(c, err, "TestDestroyVolumeSnapshotsBlocked")
+}
+
+func (s *volumeSnapshotSuite) TestRestoreVolumeSnapshots(c *gc.C) {
+	results, err := s.apiV2.RestoreVolumeSnapshots(params.VolumeSnapshotsRestoreParams{
+		Snapshots: []params.VolumeSnapshotRestoreParams{
+			{Id: "66/0", UnitTag: s.unitTag.String(), StorageName: "data"},
+			{Id: "66/1", UnitTag: s.unitTag.String(), StorageName: "data"},
+			{Id: "66/0", UnitTag: "machine-0", StorageName: "data"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, jc.Satisfies, params.IsCodeUnauthorized)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `parsing unit tag machine-0: "machine-0" is not a valid unit tag`)
+	s.assertCalls(c, []string{getBlockForTypeCall, "restoreVolumeSnapshot", "restoreVolumeSnapshot"})
+}
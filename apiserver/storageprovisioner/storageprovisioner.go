@@ -40,6 +40,8 @@ type StorageProvisionerAPI struct {
 	getMachineAuthFunc       common.GetAuthFunc
 	getBlockDevicesAuthFunc  common.GetAuthFunc
 	getAttachmentAuthFunc    func() (func(names.MachineTag, names.Tag) bool, error)
+	getSnapshotAuthFunc      func() (func(string) bool, error)
+	getResizeAuthFunc        func() (func(string) bool, error)
 }
 
 var getState = func(st *state.State) provisionerState {
@@ -143,6 +145,33 @@ func NewStorageProvisionerAPI(st *state.State, resources *common.Resources, auth
 			return !hasMachineScope || machineScope == authorizer.GetAuthTag()
 		}, nil
 	}
+	canAccessMachineScopedId := func(
+		id string,
+		isValid func(string) bool,
+		machine func(string) (names.MachineTag, bool),
+	) bool {
+		if !isValid(id) {
+			return false
+		}
+		if machineTag, ok := machine(id); ok {
+			return canAccessStorageMachine(machineTag, false)
+		}
+		return authorizer.AuthEnvironManager()
+	}
+	getSnapshotAuthFunc := func() (func(string) bool, error) {
+		// Volume snapshots are accessible in the same way as
+		// the volumes they were taken from.
+		return func(id string) bool {
+			return canAccessMachineScopedId(id, state.IsValidVolumeSnapshotId, state.VolumeSnapshotMachine)
+		}, nil
+	}
+	getResizeAuthFunc := func() (func(string) bool, error) {
+		// Storage resize requests are accessible in the same
+		// way as the storage they resize.
+		return func(id string) bool {
+			return canAccessMachineScopedId(id, state.IsValidStorageResizeId, state.StorageResizeMachine)
+		}, nil
+	}
 	getMachineAuthFunc := func() (common.AuthFunc, error) {
 		return func(tag names.Tag) bool {
 			if tag, ok := tag.(names.MachineTag); ok {
@@ -175,6 +204,8 @@ func NewStorageProvisionerAPI(st *state.State, resources *common.Resources, auth
 		getScopeAuthFunc:         getScopeAuthFunc,
 		getStorageEntityAuthFunc: getStorageEntityAuthFunc,
 		getAttachmentAuthFunc:    getAttachmentAuthFunc,
+		getSnapshotAuthFunc:      getSnapshotAuthFunc,
+		getResizeAuthFunc:        getResizeAuthFunc,
 		getMachineAuthFunc:       getMachineAuthFunc,
 		getBlockDevicesAuthFunc:  getBlockDevicesAuthFunc,
 	}, nil
@@ -261,6 +292,12 @@ func (s *StorageProvisionerAPI) WatchVolumes(args params.Entities) (params.Strin
 	return s.watchStorageEntities(args, s.st.WatchEnvironVolumes, s.st.WatchMachineVolumes)
 }
 
+// WatchStorageResizes watches for changes to storage resize requests
+// scoped to the entity with the tag passed to NewState.
+func (s *StorageProvisionerAPI) WatchStorageResizes(args params.Entities) (params.StringsWatchResults, error) {
+	return s.watchStorageEntities(args, s.st.WatchEnvironStorageResizes, s.st.WatchMachineStorageResizes)
+}
+
 // WatchFilesystems watches for changes to filesystems scoped
 // to the entity with the tag passed to NewState.
 func (s *StorageProvisionerAPI) WatchFilesystems(args params.Entities) (params.StringsWatchResults, error) {
@@ -578,6 +615,17 @@ func (s *StorageProvisionerAPI) VolumeParams(args params.Entities) (params.Volum
 		if err != nil {
 			return params.VolumeParams{}, err
 		}
+		if stateVolumeParams, ok := volume.Params(); ok && stateVolumeParams.Snapshot != "" {
+			snapshot, err := s.st.VolumeSnapshot(stateVolumeParams.Snapshot)
+			if err != nil {
+				return params.VolumeParams{}, err
+			}
+			snapshotInfo, err := snapshot.Info()
+			if err != nil {
+				return params.VolumeParams{}, err
+			}
+			volumeParams.Snapshot = snapshotInfo.SnapshotId
+		}
 		if len(volumeAttachments) == 1 {
 			// There is exactly one attachment to be made, so make
 			// it immediately. Otherwise we will defer attachments
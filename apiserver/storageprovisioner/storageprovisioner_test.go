@@ -37,6 +37,7 @@ type provisionerSuite struct {
 	resources  *common.Resources
 	authorizer *apiservertesting.FakeAuthorizer
 	api        *storageprovisioner.StorageProvisionerAPI
+	apiV2      *storageprovisioner.StorageProvisionerAPIV2
 }
 
 func (s *provisionerSuite) SetUpSuite(c *gc.C) {
@@ -73,6 +74,8 @@ func (s *provisionerSuite) SetUpTest(c *gc.C) {
 	}
 	s.api, err = storageprovisioner.NewStorageProvisionerAPI(s.State, s.resources, s.authorizer)
 	c.Assert(err, jc.ErrorIsNil)
+	s.apiV2, err = storageprovisioner.NewStorageProvisionerAPIV2(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
 }
 
 func (s *provisionerSuite) TestNewStorageProvisionerAPINonMachine(c *gc.C) {
@@ -0,0 +1,28 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("StorageProvisioner", 2, NewStorageProvisionerAPIV2)
+}
+
+// StorageProvisionerAPIV2 implements version 2 of the StorageProvisioner
+// API facade. It adds volume snapshots to version 1.
+type StorageProvisionerAPIV2 struct {
+	*StorageProvisionerAPI
+}
+
+// NewStorageProvisionerAPIV2 creates a new server-side StorageProvisionerAPIV2 facade.
+func NewStorageProvisionerAPIV2(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*StorageProvisionerAPIV2, error) {
+	api, err := NewStorageProvisionerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &StorageProvisionerAPIV2{api}, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/common/storagecommon"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage/poolmanager"
+)
+
+// StorageResizeParams returns the parameters for carrying out the
+// storage resize requests with the specified IDs.
+func (s *StorageProvisionerAPI) StorageResizeParams(args params.StorageResizeIds) (params.StorageResizeParamsResults, error) {
+	canAccess, err := s.getResizeAuthFunc()
+	if err != nil {
+		return params.StorageResizeParamsResults{}, err
+	}
+	results := params.StorageResizeParamsResults{
+		Results: make([]params.StorageResizeParamsResult, len(args.Ids)),
+	}
+	poolManager := poolmanager.New(s.settings)
+	one := func(id string) (params.StorageResizeParams, error) {
+		if !canAccess(id) {
+			return params.StorageResizeParams{}, common.ErrPerm
+		}
+		// A request that has been completed is reported as not
+		// found, so the storage provisioner can ignore it.
+		r, err := s.st.StorageResize(id)
+		if err != nil {
+			return params.StorageResizeParams{}, err
+		}
+		result := params.StorageResizeParams{
+			Id:   id,
+			Life: params.Life(r.Life().String()),
+			Size: r.Size(),
+		}
+		// The volume or filesystem may have been removed since the
+		// request was made, in which case the provider IDs are left
+		// empty and there is nothing left to resize.
+		var pool string
+		if volumeTag, ok := r.Volume(); ok {
+			result.VolumeTag = volumeTag.String()
+			volume, err := s.st.Volume(volumeTag)
+			if err == nil {
+				if info, err := volume.Info(); err == nil {
+					result.VolumeId = info.VolumeId
+					pool = info.Pool
+				}
+			} else if !errors.IsNotFound(err) {
+				return params.StorageResizeParams{}, err
+			}
+		}
+		if filesystemTag, ok := r.Filesystem(); ok {
+			result.FilesystemTag = filesystemTag.String()
+			filesystem, err := s.st.Filesystem(filesystemTag)
+			if err == nil {
+				if info, err := filesystem.Info(); err == nil {
+					result.FilesystemId = info.FilesystemId
+					if pool == "" {
+						pool = info.Pool
+					}
+				}
+			} else if !errors.IsNotFound(err) {
+				return params.StorageResizeParams{}, err
+			}
+		}
+		if pool != "" {
+			providerType, _, err := storagecommon.StoragePoolConfig(pool, poolManager)
+			if err != nil {
+				return params.StorageResizeParams{}, err
+			}
+			result.Provider = string(providerType)
+		}
+		return result, nil
+	}
+	for i, id := range args.Ids {
+		var result params.StorageResizeParamsResult
+		resizeParams, err := one(id)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Result = resizeParams
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+// CompleteStorageResizes records the new sizes of resized storage,
+// and removes the storage resize requests with the specified IDs.
+func (s *StorageProvisionerAPI) CompleteStorageResizes(args params.StorageResizeIds) (params.ErrorResults, error) {
+	canAccess, err := s.getResizeAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Ids)),
+	}
+	one := func(id string) error {
+		if !canAccess(id) {
+			return common.ErrPerm
+		}
+		return s.st.CompleteStorageResize(id)
+	}
+	for i, id := range args.Ids {
+		err := one(id)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
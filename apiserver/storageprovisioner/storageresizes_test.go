@@ -0,0 +1,110 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+// setupStorageResize adds a unit with a provisioned loop volume on
+// machine 0, and requests that it be grown.
+func (s *provisionerSuite) setupStorageResize(c *gc.C) state.StorageResize {
+	ch := s.AddTestingCharm(c, "storage-block")
+	service := s.AddTestingServiceWithStorage(c, "storage-block", ch, map[string]state.StorageConstraints{
+		"data": {Pool: "loop", Size: 1024, Count: 1},
+	})
+	unit, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	storageTag := names.NewStorageTag("data/0")
+	volume, err := s.State.StorageInstanceVolume(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetVolumeInfo(volume.VolumeTag(), state.VolumeInfo{VolumeId: "volume-0-0", Size: 1024})
+	c.Assert(err, jc.ErrorIsNil)
+	r, err := s.State.ResizeStorageInstance(storageTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	return r
+}
+
+func (s *provisionerSuite) TestStorageResizeParams(c *gc.C) {
+	r := s.setupStorageResize(c)
+	c.Assert(r.Id(), gc.Equals, "0/0")
+
+	results, err := s.api.StorageResizeParams(params.StorageResizeIds{
+		Ids: []string{"0/0", "42", "invalid"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.StorageResizeParamsResults{
+		Results: []params.StorageResizeParamsResult{
+			{Result: params.StorageResizeParams{
+				Id:        "0/0",
+				Life:      params.Alive,
+				VolumeTag: "volume-0-0",
+				VolumeId:  "volume-0-0",
+				Provider:  "loop",
+				Size:      2048,
+			}},
+			{Error: &params.Error{Code: params.CodeNotFound, Message: `storage resize "42" not found`}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *provisionerSuite) TestCompleteStorageResizes(c *gc.C) {
+	s.setupStorageResize(c)
+
+	results, err := s.api.CompleteStorageResizes(params.StorageResizeIds{Ids: []string{"0/0", "1/0"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+	_, err = s.State.StorageResize("0/0")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	volume, err := s.State.Volume(names.NewVolumeTag("0/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	info, err := volume.Info()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Size, gc.Equals, uint64(2048))
+}
+
+func (s *provisionerSuite) TestWatchStorageResizes(c *gc.C) {
+	s.setupStorageResize(c)
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{"machine-0"},
+		{s.State.EnvironTag().String()},
+		{"machine-1"},
+	}}
+	result, err := s.api.WatchStorageResizes(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0], jc.DeepEquals, params.StringsWatchResult{
+		StringsWatcherId: "1", Changes: []string{"0/0"},
+	})
+	c.Assert(result.Results[1].StringsWatcherId, gc.Equals, "2")
+	c.Assert(result.Results[1].Changes, gc.HasLen, 0)
+	c.Assert(result.Results[2].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+
+	c.Assert(s.resources.Count(), gc.Equals, 2)
+	v0Watcher := s.resources.Get("1")
+	defer statetesting.AssertStop(c, v0Watcher)
+	v1Watcher := s.resources.Get("2")
+	defer statetesting.AssertStop(c, v1Watcher)
+
+	wc := statetesting.NewStringsWatcherC(c, s.State, v0Watcher.(state.StringsWatcher))
+	wc.AssertNoChange()
+}
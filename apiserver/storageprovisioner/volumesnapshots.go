@@ -0,0 +1,161 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/common/storagecommon"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/storage/poolmanager"
+)
+
+// WatchVolumeSnapshots watches for changes to volume snapshots scoped
+// to the entity with the tag passed to NewState.
+func (s *StorageProvisionerAPIV2) WatchVolumeSnapshots(args params.Entities) (params.StringsWatchResults, error) {
+	return s.watchStorageEntities(args, s.st.WatchEnvironVolumeSnapshots, s.st.WatchMachineVolumeSnapshots)
+}
+
+// VolumeSnapshotParams returns the parameters for taking or deleting
+// the volume snapshots with the specified IDs.
+func (s *StorageProvisionerAPIV2) VolumeSnapshotParams(args params.VolumeSnapshotIds) (params.VolumeSnapshotParamsResults, error) {
+	canAccess, err := s.getSnapshotAuthFunc()
+	if err != nil {
+		return params.VolumeSnapshotParamsResults{}, err
+	}
+	results := params.VolumeSnapshotParamsResults{
+		Results: make([]params.VolumeSnapshotParamsResult, len(args.Ids)),
+	}
+	poolManager := poolmanager.New(s.settings)
+	one := func(id string) (params.VolumeSnapshotParams, error) {
+		if !canAccess(id) {
+			return params.VolumeSnapshotParams{}, common.ErrPerm
+		}
+		// A snapshot that has been removed is reported as not
+		// found, so the storage provisioner can ignore it.
+		snapshot, err := s.st.VolumeSnapshot(id)
+		if err != nil {
+			return params.VolumeSnapshotParams{}, err
+		}
+		providerType, _, err := storagecommon.StoragePoolConfig(snapshot.Pool(), poolManager)
+		if err != nil {
+			return params.VolumeSnapshotParams{}, err
+		}
+		result := params.VolumeSnapshotParams{
+			Id:        id,
+			Life:      params.Life(snapshot.Life().String()),
+			VolumeTag: snapshot.Volume().String(),
+			Provider:  string(providerType),
+		}
+		// The volume may have been removed since the snapshot was
+		// taken, in which case only deletion of the snapshot is
+		// possible, and that does not require the volume ID.
+		volume, err := s.st.Volume(snapshot.Volume())
+		if err == nil {
+			if volumeInfo, err := volume.Info(); err == nil {
+				result.VolumeId = volumeInfo.VolumeId
+				result.Size = volumeInfo.Size
+			}
+		} else if !errors.IsNotFound(err) {
+			return params.VolumeSnapshotParams{}, err
+		}
+		if snapshotInfo, err := snapshot.Info(); err == nil {
+			result.SnapshotId = snapshotInfo.SnapshotId
+			result.Size = snapshotInfo.Size
+		}
+		return result, nil
+	}
+	for i, id := range args.Ids {
+		var result params.VolumeSnapshotParamsResult
+		snapshotParams, err := one(id)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Result = snapshotParams
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+// SetVolumeSnapshotInfo records the details of newly taken volume
+// snapshots.
+func (s *StorageProvisionerAPIV2) SetVolumeSnapshotInfo(args params.VolumeSnapshotInfos) (params.ErrorResults, error) {
+	canAccess, err := s.getSnapshotAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Snapshots)),
+	}
+	one := func(arg params.VolumeSnapshotInfo) error {
+		if !canAccess(arg.Id) {
+			return common.ErrPerm
+		}
+		err := s.st.SetVolumeSnapshotInfo(arg.Id, state.VolumeSnapshotInfo{
+			SnapshotId: arg.SnapshotId,
+			Size:       arg.Size,
+		})
+		if errors.IsNotFound(err) {
+			return common.ErrPerm
+		}
+		return errors.Trace(err)
+	}
+	for i, arg := range args.Snapshots {
+		err := one(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// SetVolumeSnapshotErrors records that volume snapshots cannot be
+// taken, and why.
+func (s *StorageProvisionerAPIV2) SetVolumeSnapshotErrors(args params.VolumeSnapshotErrors) (params.ErrorResults, error) {
+	canAccess, err := s.getSnapshotAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Snapshots)),
+	}
+	one := func(arg params.VolumeSnapshotError) error {
+		if !canAccess(arg.Id) {
+			return common.ErrPerm
+		}
+		err := s.st.SetVolumeSnapshotError(arg.Id, arg.Error)
+		if errors.IsNotFound(err) {
+			return common.ErrPerm
+		}
+		return errors.Trace(err)
+	}
+	for i, arg := range args.Snapshots {
+		err := one(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// RemoveVolumeSnapshots removes volume snapshots from state.
+func (s *StorageProvisionerAPIV2) RemoveVolumeSnapshots(args params.VolumeSnapshotIds) (params.ErrorResults, error) {
+	canAccess, err := s.getSnapshotAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Ids)),
+	}
+	one := func(id string) error {
+		if !canAccess(id) {
+			return common.ErrPerm
+		}
+		return s.st.RemoveVolumeSnapshot(id)
+	}
+	for i, id := range args.Ids {
+		err := one(id)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+// setupVolumeSnapshot adds a unit with a provisioned loop volume on
+// machine 0, and requests a snapshot of it.
+func (s *provisionerSuite) setupVolumeSnapshot(c *gc.C) state.VolumeSnapshot {
+	ch := s.AddTestingCharm(c, "storage-block")
+	service := s.AddTestingServiceWithStorage(c, "storage-block", ch, map[string]state.StorageConstraints{
+		"data": {Pool: "loop", Size: 1024, Count: 1},
+	})
+	unit, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	storageTag := names.NewStorageTag("data/0")
+	volume, err := s.State.StorageInstanceVolume(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetVolumeInfo(volume.VolumeTag(), state.VolumeInfo{VolumeId: "volume-0-0", Size: 1024})
+	c.Assert(err, jc.ErrorIsNil)
+	snapshot, err := s.State.AddVolumeSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	return snapshot
+}
+
+func (s *provisionerSuite) TestVolumeSnapshotParams(c *gc.C) {
+	snapshot := s.setupVolumeSnapshot(c)
+	c.Assert(snapshot.Id(), gc.Equals, "0/0")
+
+	results, err := s.apiV2.VolumeSnapshotParams(params.VolumeSnapshotIds{
+		Ids: []string{"0/0", "42", "invalid"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.VolumeSnapshotParamsResults{
+		Results: []params.VolumeSnapshotParamsResult{
+			{Result: params.VolumeSnapshotParams{
+				Id:        "0/0",
+				Life:      params.Alive,
+				VolumeTag: "volume-0-0",
+				VolumeId:  "volume-0-0",
+				Provider:  "loop",
+				Size:      1024,
+			}},
+			{Error: &params.Error{Code: params.CodeNotFound, Message: `volume snapshot "42" not found`}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = s.State.SetVolumeSnapshotInfo("0/0", state.VolumeSnapshotInfo{SnapshotId: "snapshot-0-0", Size: 1024})
+	c.Assert(err, jc.ErrorIsNil)
+	results, err = s.apiV2.VolumeSnapshotParams(params.VolumeSnapshotIds{Ids: []string{"0/0"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Result.SnapshotId, gc.Equals, "snapshot-0-0")
+}
+
+func (s *provisionerSuite) TestSetVolumeSnapshotInfo(c *gc.C) {
+	s.setupVolumeSnapshot(c)
+
+	results, err := s.apiV2.SetVolumeSnapshotInfo(params.VolumeSnapshotInfos{
+		Snapshots: []params.VolumeSnapshotInfo{
+			{Id: "0/0", SnapshotId: "snapshot-0-0", Size: 1024},
+			{Id: "42", SnapshotId: "snapshot-42", Size: 1024},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+	snapshot, err := s.State.VolumeSnapshot("0/0")
+	c.Assert(err, jc.ErrorIsNil)
+	info, err := snapshot.Info()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, state.VolumeSnapshotInfo{SnapshotId: "snapshot-0-0", Size: 1024})
+}
+
+func (s *provisionerSuite) TestSetVolumeSnapshotErrors(c *gc.C) {
+	s.setupVolumeSnapshot(c)
+
+	results, err := s.apiV2.SetVolumeSnapshotErrors(params.VolumeSnapshotErrors{
+		Snapshots: []params.VolumeSnapshotError{
+			{Id: "0/0", Error: "snapshots not supported"},
+			{Id: "42", Error: "snapshots not supported"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+	snapshot, err := s.State.VolumeSnapshot("0/0")
+	c.Assert(err, jc.ErrorIsNil)
+	status, message := snapshot.Status()
+	c.Assert(status, gc.Equals, state.StatusError)
+	c.Assert(message, gc.Equals, "snapshots not supported")
+}
+
+func (s *provisionerSuite) TestRemoveVolumeSnapshots(c *gc.C) {
+	s.setupVolumeSnapshot(c)
+
+	results, err := s.apiV2.RemoveVolumeSnapshots(params.VolumeSnapshotIds{Ids: []string{"0/0"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: &params.Error{Message: "removing volume snapshot 0/0: volume snapshot is alive"}},
+		},
+	})
+
+	err = s.State.DestroyVolumeSnapshot("0/0")
+	c.Assert(err, jc.ErrorIsNil)
+	results, err = s.apiV2.RemoveVolumeSnapshots(params.VolumeSnapshotIds{Ids: []string{"0/0"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{Results: []params.ErrorResult{{}}})
+}
+
+func (s *provisionerSuite) TestWatchVolumeSnapshots(c *gc.C) {
+	s.setupVolumeSnapshot(c)
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{"machine-0"},
+		{s.State.EnvironTag().String()},
+		{"environ-adb650da-b77b-4ee8-9cbb-d57a9a592847"},
+		{"machine-1"},
+	}}
+	result, err := s.apiV2.WatchVolumeSnapshots(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 4)
+	c.Assert(result.Results[0], jc.DeepEquals, params.StringsWatchResult{
+		StringsWatcherId: "1", Changes: []string{"0/0"},
+	})
+	// Machine-scoped snapshots are not reported to the environment.
+	c.Assert(result.Results[1].StringsWatcherId, gc.Equals, "2")
+	c.Assert(result.Results[1].Changes, gc.HasLen, 0)
+	c.Assert(result.Results[2].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+	c.Assert(result.Results[3].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+
+	// Verify the resources were registered and stop them when done.
+	c.Assert(s.resources.Count(), gc.Equals, 2)
+	v0Watcher := s.resources.Get("1")
+	defer statetesting.AssertStop(c, v0Watcher)
+	v1Watcher := s.resources.Get("2")
+	defer statetesting.AssertStop(c, v1Watcher)
+
+	// Check that the Watch has consumed the initial events ("returned"
+	// in the Watch call)
+	wc := statetesting.NewStringsWatcherC(c, s.State, v0Watcher.(state.StringsWatcher))
+	wc.AssertNoChange()
+}
@@ -55,8 +55,10 @@ func init() {
 
 // NewAllEnvWatcher returns a new API server endpoint for interacting
 // with a watcher created by the WatchAll and WatchAllEnvs API calls.
+// Environment managers may also use it, for the watchers created by
+// WebhookDispatcher.WatchAll.
 func NewAllWatcher(st *state.State, resources *common.Resources, auth common.Authorizer, id string) (interface{}, error) {
-	if !auth.AuthClient() {
+	if !auth.AuthClient() && !auth.AuthEnvironManager() {
 		return nil, common.ErrPerm
 	}
 
@@ -0,0 +1,149 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The webhookdispatcher package implements the API interface
+// used by the webhooks worker.
+
+package webhookdispatcher
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("WebhookDispatcher", 1, NewWebhookDispatcherAPI)
+}
+
+// Webhook describes the webhook methods used by the WebhookDispatcher
+// facade.
+type Webhook interface {
+	Id() string
+	URL() string
+	Kinds() []string
+	Statuses() []string
+	Secret() string
+	AddDeadLetter(payload string, attempts int, deliveryErr string) error
+}
+
+// StateInterface defines the state methods used by the
+// WebhookDispatcher facade.
+type StateInterface interface {
+	Watch() *state.Multiwatcher
+	Webhook(id string) (Webhook, error)
+	Webhooks() ([]Webhook, error)
+	APIHostPorts() ([][]network.HostPort, error)
+}
+
+type stateShim struct {
+	*state.State
+}
+
+func (s stateShim) Webhook(id string) (Webhook, error) {
+	hook, err := s.State.Webhook(id)
+	if err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+func (s stateShim) Webhooks() ([]Webhook, error) {
+	hooks, err := s.State.Webhooks()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Webhook, len(hooks))
+	for i, hook := range hooks {
+		result[i] = hook
+	}
+	return result, nil
+}
+
+var getState = func(st *state.State) StateInterface {
+	return stateShim{st}
+}
+
+// WebhookDispatcherAPI implements the API used by the webhooks worker.
+type WebhookDispatcherAPI struct {
+	st        StateInterface
+	resources *common.Resources
+}
+
+// NewWebhookDispatcherAPI creates a new instance of the
+// WebhookDispatcher API.
+func NewWebhookDispatcherAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*WebhookDispatcherAPI, error) {
+	if !authorizer.AuthEnvironManager() {
+		return nil, common.ErrPerm
+	}
+	return &WebhookDispatcherAPI{
+		st:        getState(st),
+		resources: resources,
+	}, nil
+}
+
+// WatchAll starts a watcher for changes to the environment, whose
+// deltas are read through the AllWatcher facade.
+func (api *WebhookDispatcherAPI) WatchAll() (params.AllWatcherId, error) {
+	w := api.st.Watch()
+	return params.AllWatcherId{
+		AllWatcherId: api.resources.Register(w),
+	}, nil
+}
+
+// Webhooks returns every webhook in the environment, with its secret,
+// along with the controller's addresses.
+func (api *WebhookDispatcherAPI) Webhooks() (params.WebhookTargetsResult, error) {
+	hooks, err := api.st.Webhooks()
+	if err != nil {
+		return params.WebhookTargetsResult{}, common.ServerError(err)
+	}
+	hostPorts, err := api.st.APIHostPorts()
+	if err != nil {
+		return params.WebhookTargetsResult{}, common.ServerError(err)
+	}
+	var result params.WebhookTargetsResult
+	for _, hook := range hooks {
+		result.Webhooks = append(result.Webhooks, params.WebhookTarget{
+			Id:       hook.Id(),
+			URL:      hook.URL(),
+			Kinds:    hook.Kinds(),
+			Statuses: hook.Statuses(),
+			Secret:   hook.Secret(),
+		})
+	}
+	for _, server := range hostPorts {
+		for _, hp := range server {
+			result.ControllerAddresses = append(result.ControllerAddresses, hp.Value)
+		}
+	}
+	return result, nil
+}
+
+// AddDeadLetters records deliveries that could not be made to
+// webhooks.
+func (api *WebhookDispatcherAPI) AddDeadLetters(args params.AddWebhookDeadLetters) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.DeadLetters)),
+	}
+	for i, letter := range args.DeadLetters {
+		err := api.addDeadLetter(letter)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *WebhookDispatcherAPI) addDeadLetter(letter params.AddWebhookDeadLetter) error {
+	hook, err := api.st.Webhook(letter.WebhookId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return hook.AddDeadLetter(letter.Payload, letter.Attempts, letter.Error)
+}
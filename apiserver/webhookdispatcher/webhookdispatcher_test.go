@@ -0,0 +1,175 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhookdispatcher_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/apiserver/webhookdispatcher"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type WebhookDispatcherSuite struct {
+	coretesting.BaseSuite
+
+	st         *mockState
+	resources  *common.Resources
+	api        *webhookdispatcher.WebhookDispatcherAPI
+	authoriser apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&WebhookDispatcherSuite{})
+
+func (s *WebhookDispatcherSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+
+	s.authoriser = apiservertesting.FakeAuthorizer{
+		EnvironManager: true,
+	}
+	s.st = &mockState{
+		Stub: &testing.Stub{},
+		hooks: []*mockWebhook{{
+			id:       "hook-1",
+			url:      "https://example.com/hook",
+			kinds:    []string{"unit"},
+			statuses: []string{"error"},
+			secret:   "s3cret",
+		}},
+		hostPorts: [][]network.HostPort{network.NewHostPorts(17070, "10.0.0.1", "10.0.0.2")},
+	}
+	webhookdispatcher.PatchState(s, s.st)
+	s.resources = common.NewResources()
+	var err error
+	s.api, err = webhookdispatcher.NewWebhookDispatcherAPI(nil, s.resources, s.authoriser)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *WebhookDispatcherSuite) TestNewWebhookDispatcherAPIRequiresEnvironManager(c *gc.C) {
+	anAuthoriser := s.authoriser
+	anAuthoriser.EnvironManager = false
+	api, err := webhookdispatcher.NewWebhookDispatcherAPI(nil, s.resources, anAuthoriser)
+	c.Assert(api, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(common.ServerError(err), jc.Satisfies, params.IsCodeUnauthorized)
+}
+
+func (s *WebhookDispatcherSuite) TestWatchAll(c *gc.C) {
+	result, err := s.api.WatchAll()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.AllWatcherId, gc.Equals, "1")
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	s.st.CheckCallNames(c, "Watch")
+}
+
+func (s *WebhookDispatcherSuite) TestWebhooks(c *gc.C) {
+	result, err := s.api.Webhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.WebhookTargetsResult{
+		Webhooks: []params.WebhookTarget{{
+			Id:       "hook-1",
+			URL:      "https://example.com/hook",
+			Kinds:    []string{"unit"},
+			Statuses: []string{"error"},
+			Secret:   "s3cret",
+		}},
+		ControllerAddresses: []string{"10.0.0.1", "10.0.0.2"},
+	})
+	s.st.CheckCallNames(c, "Webhooks", "APIHostPorts")
+}
+
+func (s *WebhookDispatcherSuite) TestWebhooksFailure(c *gc.C) {
+	s.st.SetErrors(errors.New("boom!"))
+	_, err := s.api.Webhooks()
+	c.Assert(err, gc.ErrorMatches, "boom!")
+}
+
+func (s *WebhookDispatcherSuite) TestAddDeadLetters(c *gc.C) {
+	results, err := s.api.AddDeadLetters(params.AddWebhookDeadLetters{
+		DeadLetters: []params.AddWebhookDeadLetter{{
+			WebhookId: "hook-1",
+			Payload:   "{}",
+			Attempts:  5,
+			Error:     "503 Service Unavailable",
+		}, {
+			WebhookId: "hook-9",
+			Payload:   "{}",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}, {
+			Error: &params.Error{Message: `webhook "hook-9" not found`, Code: params.CodeNotFound},
+		}},
+	})
+	s.st.hooks[0].CheckCall(c, 0, "AddDeadLetter", "{}", 5, "503 Service Unavailable")
+}
+
+type mockState struct {
+	*testing.Stub
+	hooks     []*mockWebhook
+	hostPorts [][]network.HostPort
+}
+
+func (st *mockState) Watch() *state.Multiwatcher {
+	st.MethodCall(st, "Watch")
+	return nil
+}
+
+func (st *mockState) Webhook(id string) (webhookdispatcher.Webhook, error) {
+	st.MethodCall(st, "Webhook", id)
+	if err := st.NextErr(); err != nil {
+		return nil, err
+	}
+	for _, hook := range st.hooks {
+		if hook.id == id {
+			return hook, nil
+		}
+	}
+	return nil, errors.NotFoundf("webhook %q", id)
+}
+
+func (st *mockState) Webhooks() ([]webhookdispatcher.Webhook, error) {
+	st.MethodCall(st, "Webhooks")
+	if err := st.NextErr(); err != nil {
+		return nil, err
+	}
+	result := make([]webhookdispatcher.Webhook, len(st.hooks))
+	for i, hook := range st.hooks {
+		result[i] = hook
+	}
+	return result, nil
+}
+
+func (st *mockState) APIHostPorts() ([][]network.HostPort, error) {
+	st.MethodCall(st, "APIHostPorts")
+	return st.hostPorts, st.NextErr()
+}
+
+type mockWebhook struct {
+	testing.Stub
+	id       string
+	url      string
+	kinds    []string
+	statuses []string
+	secret   string
+}
+
+func (w *mockWebhook) Id() string         { return w.id }
+func (w *mockWebhook) URL() string        { return w.url }
+func (w *mockWebhook) Kinds() []string    { return w.kinds }
+func (w *mockWebhook) Statuses() []string { return w.statuses }
+func (w *mockWebhook) Secret() string     { return w.secret }
+
+func (w *mockWebhook) AddDeadLetter(payload string, attempts int, deliveryErr string) error {
+	w.MethodCall(w, "AddDeadLetter", payload, attempts, deliveryErr)
+	return w.NextErr()
+}
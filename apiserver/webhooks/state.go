@@ -0,0 +1,85 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhooks
+
+import (
+	"time"
+
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+// Webhook describes the webhook methods that the facade uses.
+type Webhook interface {
+	Id() string
+	URL() string
+	Kinds() []string
+	Statuses() []string
+	Owner() string
+	Created() time.Time
+	Remove() error
+	DeadLetters() ([]state.WebhookDeadLetter, error)
+}
+
+// webhookState defines the state methods that the webhooks facade
+// uses.
+type webhookState interface {
+	common.BlockGetter
+
+	// AddWebhook creates a webhook.
+	AddWebhook(args state.AddWebhookArgs) (Webhook, error)
+
+	// Webhook returns the webhook with the given id.
+	Webhook(id string) (Webhook, error)
+
+	// Webhooks returns all webhooks in the environment.
+	Webhooks() ([]Webhook, error)
+
+	// IsControllerAdministrator returns whether the given user
+	// administers the controller.
+	IsControllerAdministrator(user names.UserTag) (bool, error)
+
+	// APIHostPorts returns the addresses of the controller's API
+	// servers.
+	APIHostPorts() ([][]network.HostPort, error)
+}
+
+type stateShim struct {
+	*state.State
+}
+
+func (s stateShim) AddWebhook(args state.AddWebhookArgs) (Webhook, error) {
+	hook, err := s.State.AddWebhook(args)
+	if err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+func (s stateShim) Webhook(id string) (Webhook, error) {
+	hook, err := s.State.Webhook(id)
+	if err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+func (s stateShim) Webhooks() ([]Webhook, error) {
+	hooks, err := s.State.Webhooks()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Webhook, len(hooks))
+	for i, hook := range hooks {
+		result[i] = hook
+	}
+	return result, nil
+}
+
+var getState = func(st *state.State) webhookState {
+	return stateShim{st}
+}
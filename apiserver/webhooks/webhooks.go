@@ -0,0 +1,285 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package webhooks provides an API server facade for managing the
+// webhooks that environment changes are delivered to.
+package webhooks
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.webhooks")
+
+// lookupIP resolves webhook URL hosts; it is a variable so that tests
+// need not rely on DNS.
+var lookupIP = net.LookupIP
+
+func init() {
+	common.RegisterStandardFacade("Webhooks", 1, NewAPI)
+}
+
+// Webhooks defines the methods on the webhooks API end point.
+type Webhooks interface {
+	// AddWebhooks creates webhooks that environment changes are
+	// delivered to.
+	AddWebhooks(args params.AddWebhooks) (params.WebhookResults, error)
+
+	// ListWebhooks returns all webhooks in the environment.
+	ListWebhooks() (params.ListWebhooksResult, error)
+
+	// RemoveWebhooks removes the given webhooks.
+	RemoveWebhooks(args params.WebhookIds) (params.ErrorResults, error)
+
+	// WebhookDeadLetters returns the deliveries that could not be
+	// made to the given webhooks.
+	WebhookDeadLetters(args params.WebhookIds) (params.WebhookDeadLettersResults, error)
+}
+
+// API implements the Webhooks interface and is the concrete
+// implementation of the api end point.
+type API struct {
+	state      webhookState
+	authorizer common.Authorizer
+}
+
+var _ Webhooks = (*API)(nil)
+
+// createAPI returns a new webhooks API facade.
+func createAPI(
+	st webhookState,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		state:      st,
+		authorizer: authorizer,
+	}, nil
+}
+
+// NewAPI returns a new webhooks API facade.
+func NewAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*API, error) {
+	return createAPI(getState(st), resources, authorizer)
+}
+
+// AddWebhooks creates webhooks that environment changes are delivered
+// to. Each webhook is owned by the authenticated user, who must
+// administer the controller. Webhooks may not be delivered to loopback,
+// link-local or unspecified addresses, nor to the controller itself.
+func (api *API) AddWebhooks(args params.AddWebhooks) (params.WebhookResults, error) {
+	if err := api.checkIsAdmin(); err != nil {
+		return params.WebhookResults{}, errors.Trace(err)
+	}
+	if err := common.NewBlockChecker(api.state).ChangeAllowed(); err != nil {
+		return params.WebhookResults{}, errors.Trace(err)
+	}
+	owner := api.authorizer.GetAuthTag()
+	results := params.WebhookResults{
+		Results: make([]params.WebhookResult, len(args.Webhooks)),
+	}
+	for i, arg := range args.Webhooks {
+		if err := api.checkURL(arg.URL); err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		hook, err := api.state.AddWebhook(state.AddWebhookArgs{
+			URL:      arg.URL,
+			Kinds:    arg.Kinds,
+			Statuses: arg.Statuses,
+			Secret:   arg.Secret,
+			Owner:    owner.Id(),
+		})
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		logger.Infof("added webhook %q for %s", hook.Id(), names.ReadableString(owner))
+		results.Results[i].Webhook = webhookParams(hook)
+	}
+	return results, nil
+}
+
+// checkURL returns an error if the URL's host is, or resolves to, a
+// loopback, link-local or unspecified address, or an address of the
+// controller, so that webhooks cannot be used to reach services only
+// reachable from the controller.
+func (api *API) checkURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return errors.NotValidf("URL %q", rawURL)
+	}
+	host := u.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	controller, err := api.controllerAddresses()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if controller[host] {
+		return errors.NotValidf("URL %q (controller address)", rawURL)
+	}
+	ips, err := lookupIP(host)
+	if err != nil {
+		return errors.Annotatef(err, "cannot resolve %q", host)
+	}
+	for _, ip := range ips {
+		if network.IsLocalIP(ip) {
+			return errors.NotValidf("URL %q (local address %s)", rawURL, ip)
+		}
+		if controller[ip.String()] {
+			return errors.NotValidf("URL %q (controller address %s)", rawURL, ip)
+		}
+	}
+	return nil
+}
+
+// controllerAddresses returns the set of addresses the controller's
+// API servers are reachable on.
+func (api *API) controllerAddresses() (map[string]bool, error) {
+	hostPorts, err := api.state.APIHostPorts()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	addrs := make(map[string]bool)
+	for _, server := range hostPorts {
+		for _, hp := range server {
+			addrs[hp.Value] = true
+		}
+	}
+	return addrs, nil
+}
+
+// ListWebhooks returns all webhooks in the environment, oldest first.
+func (api *API) ListWebhooks() (params.ListWebhooksResult, error) {
+	hooks, err := api.state.Webhooks()
+	if err != nil {
+		return params.ListWebhooksResult{}, common.ServerError(err)
+	}
+	result := params.ListWebhooksResult{
+		Webhooks: make([]params.Webhook, len(hooks)),
+	}
+	for i, hook := range hooks {
+		result.Webhooks[i] = webhookParams(hook)
+	}
+	return result, nil
+}
+
+// RemoveWebhooks removes the given webhooks, along with their dead
+// letters. Only a webhook's owner, or a controller administrator, may
+// remove it.
+func (api *API) RemoveWebhooks(args params.WebhookIds) (params.ErrorResults, error) {
+	if err := common.NewBlockChecker(api.state).RemoveAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Ids)),
+	}
+	for i, id := range args.Ids {
+		err := api.removeWebhook(id)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *API) removeWebhook(id string) error {
+	hook, err := api.state.Webhook(id)
+	if err != nil {
+		return err
+	}
+	if err := api.checkCanModify(hook); err != nil {
+		return err
+	}
+	return hook.Remove()
+}
+
+// checkCanModify returns an error if the authenticated user neither
+// owns the webhook nor administers the controller.
+func (api *API) checkCanModify(hook Webhook) error {
+	// AuthClient was checked at creation time, so this is a user tag.
+	apiUser, _ := api.authorizer.GetAuthTag().(names.UserTag)
+	if names.IsValidUser(hook.Owner()) && names.NewUserTag(hook.Owner()).Canonical() == apiUser.Canonical() {
+		return nil
+	}
+	return api.checkIsAdmin()
+}
+
+// checkIsAdmin returns an error if the authenticated user does not
+// administer the controller.
+func (api *API) checkIsAdmin() error {
+	apiUser, _ := api.authorizer.GetAuthTag().(names.UserTag)
+	isAdmin, err := api.state.IsControllerAdministrator(apiUser)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !isAdmin {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// WebhookDeadLetters returns the deliveries that could not be made to
+// each of the given webhooks, oldest first.
+func (api *API) WebhookDeadLetters(args params.WebhookIds) (params.WebhookDeadLettersResults, error) {
+	results := params.WebhookDeadLettersResults{
+		Results: make([]params.WebhookDeadLettersResult, len(args.Ids)),
+	}
+	for i, id := range args.Ids {
+		letters, err := api.deadLetters(id)
+		results.Results[i].DeadLetters = letters
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *API) deadLetters(id string) ([]params.WebhookDeadLetter, error) {
+	hook, err := api.state.Webhook(id)
+	if err != nil {
+		return nil, err
+	}
+	letters, err := hook.DeadLetters()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]params.WebhookDeadLetter, len(letters))
+	for i, letter := range letters {
+		result[i] = params.WebhookDeadLetter{
+			WebhookId: letter.WebhookId,
+			Payload:   letter.Payload,
+			Attempts:  letter.Attempts,
+			Error:     letter.Error,
+			Time:      letter.Time,
+		}
+	}
+	return result, nil
+}
+
+func webhookParams(hook Webhook) params.Webhook {
+	return params.Webhook{
+		Id:       hook.Id(),
+		URL:      hook.URL(),
+		Kinds:    hook.Kinds(),
+		Statuses: hook.Statuses(),
+		Owner:    hook.Owner(),
+		Created:  hook.Created(),
+	}
+}
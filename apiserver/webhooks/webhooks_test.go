@@ -0,0 +1,343 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhooks_test
+
+import (
+	"net"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/apiserver/webhooks"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type webhooksSuite struct {
+	coretesting.BaseSuite
+
+	authorizer apiservertesting.FakeAuthorizer
+	state      *mockState
+	api        *webhooks.API
+}
+
+var _ = gc.Suite(&webhooksSuite{})
+
+func (s *webhooksSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("admin")}
+	s.PatchValue(webhooks.LookupIP, func(host string) ([]net.IP, error) {
+		switch host {
+		case "example.com":
+			return []net.IP{net.ParseIP("93.184.216.34")}, nil
+		case "localhost":
+			return []net.IP{net.ParseIP("127.0.0.1")}, nil
+		case "controller.example.com":
+			return []net.IP{net.ParseIP("10.0.0.1")}, nil
+		}
+		if ip := net.ParseIP(host); ip != nil {
+			return []net.IP{ip}, nil
+		}
+		return nil, errors.Errorf("no such host %q", host)
+	})
+	s.state = &mockState{
+		admins:      []string{"admin@local"},
+		apiHostPort: [][]network.HostPort{network.NewHostPorts(17070, "10.0.0.1", "controller.internal")},
+		hooks: []*mockWebhook{{
+			id:       "hook-1",
+			url:      "https://example.com/hook",
+			kinds:    []string{"unit"},
+			statuses: []string{"error"},
+			owner:    "admin",
+			created:  time.Unix(100, 0),
+			deadLetters: []state.WebhookDeadLetter{{
+				WebhookId: "hook-1",
+				Payload:   "[]",
+				Attempts:  5,
+				Error:     "503 Service Unavailable",
+				Time:      time.Unix(200, 0),
+			}},
+		}},
+	}
+	var err error
+	s.api, err = webhooks.CreateAPI(s.state, common.NewResources(), s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *webhooksSuite) TestNewAPIRequiresClient(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{Tag: names.NewUnitTag("mysql/0")}
+	_, err := webhooks.CreateAPI(s.state, common.NewResources(), authorizer)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *webhooksSuite) TestAddWebhooks(c *gc.C) {
+	results, err := s.api.AddWebhooks(params.AddWebhooks{
+		Webhooks: []params.AddWebhookParams{{
+			URL:      "https://example.com/other",
+			Statuses: []string{"blocked"},
+			Secret:   "s3cret",
+		}, {
+			URL: "https://example.com/nosecret",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.WebhookResults{
+		Results: []params.WebhookResult{{
+			Webhook: params.Webhook{
+				Id:       "hook-2",
+				URL:      "https://example.com/other",
+				Statuses: []string{"blocked"},
+				Owner:    "admin",
+				Created:  time.Unix(300, 0),
+			},
+		}, {
+			Error: &params.Error{Message: "cannot add webhook: no secret given"},
+		}},
+	})
+	c.Assert(s.state.added, jc.DeepEquals, []state.AddWebhookArgs{{
+		URL:      "https://example.com/other",
+		Statuses: []string{"blocked"},
+		Secret:   "s3cret",
+		Owner:    "admin",
+	}, {
+		URL:   "https://example.com/nosecret",
+		Owner: "admin",
+	}})
+}
+
+func (s *webhooksSuite) TestAddWebhooksRequiresControllerAdministrator(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("bob")
+	api, err := webhooks.CreateAPI(s.state, common.NewResources(), s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.AddWebhooks(params.AddWebhooks{
+		Webhooks: []params.AddWebhookParams{{URL: "https://example.com", Secret: "x"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(s.state.added, gc.HasLen, 0)
+}
+
+func (s *webhooksSuite) TestAddWebhooksRejectsLocalAndControllerAddresses(c *gc.C) {
+	urls := []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+		"http://[fe80::1]:80/hook",
+		"http://0.0.0.0/hook",
+		"https://10.0.0.1:17070/hook",
+		"https://controller.internal/hook",
+		"https://controller.example.com/hook",
+		"https://unresolvable.invalid/hook",
+	}
+	args := params.AddWebhooks{}
+	for _, url := range urls {
+		args.Webhooks = append(args.Webhooks, params.AddWebhookParams{URL: url, Secret: "x"})
+	}
+	results, err := s.api.AddWebhooks(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, len(urls))
+	for i, result := range results.Results {
+		c.Logf("test %d: %s", i, urls[i])
+		c.Check(result.Error, gc.NotNil)
+	}
+	c.Check(results.Results[0].Error, gc.ErrorMatches, `URL "http://127.0.0.1:8080/hook" \(local address 127.0.0.1\) not valid`)
+	c.Check(results.Results[6].Error, gc.ErrorMatches, `URL "https://10.0.0.1:17070/hook" \(controller address\) not valid`)
+	c.Check(results.Results[8].Error, gc.ErrorMatches, `URL "https://controller.example.com/hook" \(controller address 10.0.0.1\) not valid`)
+	c.Assert(s.state.added, gc.HasLen, 0)
+}
+
+func (s *webhooksSuite) TestAddWebhooksBlocked(c *gc.C) {
+	s.state.blocks = map[state.BlockType]bool{state.ChangeBlock: true}
+	_, err := s.api.AddWebhooks(params.AddWebhooks{
+		Webhooks: []params.AddWebhookParams{{URL: "https://example.com", Secret: "x"}},
+	})
+	c.Assert(params.IsCodeOperationBlocked(err), jc.IsTrue)
+	c.Assert(s.state.added, gc.HasLen, 0)
+}
+
+func (s *webhooksSuite) TestListWebhooks(c *gc.C) {
+	result, err := s.api.ListWebhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ListWebhooksResult{
+		Webhooks: []params.Webhook{{
+			Id:       "hook-1",
+			URL:      "https://example.com/hook",
+			Kinds:    []string{"unit"},
+			Statuses: []string{"error"},
+			Owner:    "admin",
+			Created:  time.Unix(100, 0),
+		}},
+	})
+}
+
+func (s *webhooksSuite) TestRemoveWebhooks(c *gc.C) {
+	results, err := s.api.RemoveWebhooks(params.WebhookIds{Ids: []string{"hook-1", "hook-9"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}, {
+			Error: &params.Error{Message: `webhook "hook-9" not found`, Code: params.CodeNotFound},
+		}},
+	})
+	c.Assert(s.state.hooks[0].removed, jc.IsTrue)
+}
+
+func (s *webhooksSuite) TestRemoveWebhooksNotOwner(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("bob")
+	api, err := webhooks.CreateAPI(s.state, common.NewResources(), s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := api.RemoveWebhooks(params.WebhookIds{Ids: []string{"hook-1"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{
+			Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized},
+		}},
+	})
+	c.Assert(s.state.hooks[0].removed, jc.IsFalse)
+}
+
+func (s *webhooksSuite) TestRemoveWebhooksControllerAdministrator(c *gc.C) {
+	s.state.admins = []string{"bob@local"}
+	s.authorizer.Tag = names.NewUserTag("bob")
+	api, err := webhooks.CreateAPI(s.state, common.NewResources(), s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := api.RemoveWebhooks(params.WebhookIds{Ids: []string{"hook-1"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+	c.Assert(s.state.hooks[0].removed, jc.IsTrue)
+}
+
+func (s *webhooksSuite) TestRemoveWebhooksBlocked(c *gc.C) {
+	s.state.blocks = map[state.BlockType]bool{state.RemoveBlock: true}
+	_, err := s.api.RemoveWebhooks(params.WebhookIds{Ids: []string{"hook-1"}})
+	c.Assert(params.IsCodeOperationBlocked(err), jc.IsTrue)
+	c.Assert(s.state.hooks[0].removed, jc.IsFalse)
+}
+
+func (s *webhooksSuite) TestWebhookDeadLetters(c *gc.C) {
+	results, err := s.api.WebhookDeadLetters(params.WebhookIds{Ids: []string{"hook-1", "hook-9"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.WebhookDeadLettersResults{
+		Results: []params.WebhookDeadLettersResult{{
+			DeadLetters: []params.WebhookDeadLetter{{
+				WebhookId: "hook-1",
+				Payload:   "[]",
+				Attempts:  5,
+				Error:     "503 Service Unavailable",
+				Time:      time.Unix(200, 0),
+			}},
+		}, {
+			Error: &params.Error{Message: `webhook "hook-9" not found`, Code: params.CodeNotFound},
+		}},
+	})
+}
+
+type mockState struct {
+	hooks       []*mockWebhook
+	added       []state.AddWebhookArgs
+	blocks      map[state.BlockType]bool
+	admins      []string
+	apiHostPort [][]network.HostPort
+}
+
+func (st *mockState) APIHostPorts() ([][]network.HostPort, error) {
+	return st.apiHostPort, nil
+}
+
+func (st *mockState) IsControllerAdministrator(user names.UserTag) (bool, error) {
+	for _, admin := range st.admins {
+		if admin == user.Canonical() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (st *mockState) GetBlockForType(t state.BlockType) (state.Block, bool, error) {
+	if st.blocks[t] {
+		return mockBlock{t, "blocked"}, true, nil
+	}
+	return nil, false, nil
+}
+
+func (st *mockState) AddWebhook(args state.AddWebhookArgs) (webhooks.Webhook, error) {
+	st.added = append(st.added, args)
+	if args.Secret == "" {
+		return nil, errors.New("cannot add webhook: no secret given")
+	}
+	hook := &mockWebhook{
+		id:       "hook-2",
+		url:      args.URL,
+		kinds:    args.Kinds,
+		statuses: args.Statuses,
+		owner:    args.Owner,
+		created:  time.Unix(300, 0),
+	}
+	st.hooks = append(st.hooks, hook)
+	return hook, nil
+}
+
+func (st *mockState) Webhook(id string) (webhooks.Webhook, error) {
+	for _, hook := range st.hooks {
+		if hook.id == id {
+			return hook, nil
+		}
+	}
+	return nil, errors.NotFoundf("webhook %q", id)
+}
+
+func (st *mockState) Webhooks() ([]webhooks.Webhook, error) {
+	result := make([]webhooks.Webhook, len(st.hooks))
+	for i, hook := range st.hooks {
+		result[i] = hook
+	}
+	return result, nil
+}
+
+type mockWebhook struct {
+	id          string
+	url         string
+	kinds       []string
+	statuses    []string
+	owner       string
+	created     time.Time
+	deadLetters []state.WebhookDeadLetter
+	removed     bool
+}
+
+func (w *mockWebhook) Id() string         { return w.id }
+func (w *mockWebhook) URL() string        { return w.url }
+func (w *mockWebhook) Kinds() []string    { return w.kinds }
+func (w *mockWebhook) Statuses() []string { return w.statuses }
+func (w *mockWebhook) Owner() string      { return w.owner }
+func (w *mockWebhook) Created() time.Time { return w.created }
+
+func (w *mockWebhook) Remove() error {
+	w.removed = true
+	return nil
+}
+
+func (w *mockWebhook) DeadLetters() ([]state.WebhookDeadLetter, error) {
+	return w.deadLetters, nil
+}
+
+type mockBlock struct {
+	state.Block
+	t   state.BlockType
+	msg string
+}
+
+func (b mockBlock) Type() state.BlockType {
+	return b.t
+}
+
+func (b mockBlock) Message() string {
+	return b.msg
+}
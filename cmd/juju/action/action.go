@@ -34,8 +34,13 @@ func NewSuperCommand() cmd.Command {
 		})
 	actionCmd.Register(newDefinedCommand())
 	actionCmd.Register(newDoCommand())
+	actionCmd.Register(newDoServiceCommand())
 	actionCmd.Register(newFetchCommand())
 	actionCmd.Register(newStatusCommand())
+	actionCmd.Register(newCancelCommand())
+	actionCmd.Register(newScheduleCommand())
+	actionCmd.Register(newListSchedulesCommand())
+	actionCmd.Register(newUnscheduleCommand())
 	return actionCmd
 }
 
@@ -64,8 +69,9 @@ type APIClient interface {
 	// Entities.
 	ListCompleted(params.Entities) (params.ActionsByReceivers, error)
 
-	// Cancel attempts to cancel a queued up Action from running.
-	Cancel(params.Actions) (params.ActionResults, error)
+	// Cancel takes a list of Entities representing Actions, and cancels
+	// each of them.
+	Cancel(params.Entities) (params.ActionResults, error)
 
 	// ServiceCharmActions is a single query which uses ServicesCharmActions to
 	// get the charm.Actions for a single Service by tag.
@@ -78,6 +84,25 @@ type APIClient interface {
 	// FindActionTagsByPrefix takes a list of string prefixes and finds
 	// corresponding ActionTags that match that prefix.
 	FindActionTagsByPrefix(params.FindTags) (params.FindTagsResults, error)
+
+	// Schedule takes a list of Actions with cron schedules, and arranges
+	// for each to be queued on its receiver every time the schedule fires.
+	Schedule(params.ScheduleActions) (params.ScheduledActionResults, error)
+
+	// ListSchedules returns the scheduled actions for each of the given
+	// units, or for every unit if none are given.
+	ListSchedules(params.Entities) (params.ScheduledActionResults, error)
+
+	// Unschedule removes the scheduled actions with the given ids.
+	Unschedule(params.ScheduledActionIds) (params.ErrorResults, error)
+
+	// EnqueueOperations takes a list of Actions, each to be queued on
+	// many units of a service, and starts an operation for each.
+	EnqueueOperations(params.ServiceActions) (params.ActionOperationResults, error)
+
+	// ActionOperations returns the operations with the given ids, or
+	// unique id prefixes.
+	ActionOperations(params.ActionOperationIds) (params.ActionOperationResults, error)
 }
 
 // ActionCommandBase is the base type for action sub-commands.
@@ -44,11 +44,16 @@ func (s *ActionCommandSuite) TestHelp(c *gc.C) {
 
 func (s *ActionCommandSuite) checkHelpSubCommands(c *gc.C, ctx *cmd.Context) {
 	var expectedSubCommmands = [][]string{
+		{"cancel", "cancel pending or running actions"},
 		{"defined", "show actions defined for a service"},
 		{"do", "queue an action for execution"},
+		{"do-service", "queue an action for execution on many units of a service"},
 		{"fetch", "show results of an action by ID"},
 		{"help", "show help on a command or other topic"},
+		{"list-schedules", "show scheduled actions"},
+		{"schedule", "queue an action for execution on a recurring schedule"},
 		{"status", "show results of all actions filtered by optional ID prefix"},
+		{"unschedule", "remove scheduled actions"},
 	}
 
 	// Check that we have registered all the sub commands by
@@ -0,0 +1,97 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+func newCancelCommand() cmd.Command {
+	return envcmd.Wrap(&cancelCommand{})
+}
+
+// cancelCommand cancels pending or running Actions by ID.
+type cancelCommand struct {
+	ActionCommandBase
+	out          cmd.Output
+	requestedIds []string
+}
+
+const cancelDoc = `
+Cancel the Actions matching the given IDs or partial ID prefixes.
+
+A pending Action is cancelled at once, and will not run. A running Action is
+shown as "cancelling" until its unit has stopped it: the Action's process is
+asked to exit, and is killed if it has not done so after a grace period.
+
+Examples:
+
+$ juju action cancel <ID>
+actions:
+- id: <ID>
+  status: cancelled
+  unit: mysql/0
+`
+
+// Set up the output.
+func (c *cancelCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+}
+
+func (c *cancelCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "cancel",
+		Args:    "<action ID> [<action ID>...]",
+		Purpose: "cancel pending or running actions",
+		Doc:     cancelDoc,
+	}
+}
+
+func (c *cancelCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no action IDs specified")
+	}
+	c.requestedIds = args
+	return nil
+}
+
+func (c *cancelCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	entities := []params.Entity{}
+	for _, requestedId := range c.requestedIds {
+		tag, err := getActionTagByPrefix(api, requestedId)
+		if err != nil {
+			return err
+		}
+		entities = append(entities, params.Entity{tag.String()})
+	}
+
+	results, err := api.Cancel(params.Entities{Entities: entities})
+	if err != nil {
+		return err
+	}
+	if len(results.Results) != len(entities) {
+		return errors.New("illegal number of results returned")
+	}
+
+	items := []map[string]interface{}{}
+	for _, result := range results.Results {
+		item := resultToMap(result)
+		if result.Status == params.ActionRunning {
+			item["status"] = "cancelling"
+		}
+		items = append(items, item)
+	}
+	return c.out.Write(ctx, map[string]interface{}{"actions": items})
+}
@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/action"
+	"github.com/juju/juju/testing"
+)
+
+type CancelSuite struct {
+	BaseActionSuite
+}
+
+var _ = gc.Suite(&CancelSuite{})
+
+func (s *CancelSuite) TestHelp(c *gc.C) {
+	s.checkHelp(c, action.NewCancelCommand())
+}
+
+func (s *CancelSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(action.NewCancelCommand(), []string{"-e", "dummyenv"})
+	c.Assert(err, gc.ErrorMatches, "no action IDs specified")
+}
+
+func (s *CancelSuite) TestRun(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		actionTagMatches: tagsForIdPrefix(validActionId[:8], validActionTagString),
+		actionResults: []params.ActionResult{{
+			Action: &params.Action{
+				Tag:      validActionTagString,
+				Receiver: "unit-mysql-0",
+			},
+			Status: params.ActionRunning,
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, action.NewCancelCommand(), "-e", "dummyenv", validActionId[:8])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fakeClient.cancelled, jc.DeepEquals, params.Entities{
+		Entities: []params.Entity{{Tag: validActionTagString}},
+	})
+	c.Check(testing.Stdout(ctx), gc.Equals, `
+actions:
+- id: `+validActionId+`
+  status: cancelling
+  unit: mysql/0
+`[1:])
+}
+
+func (s *CancelSuite) TestRunNoMatch(c *gc.C) {
+	fakeClient := &fakeAPIClient{actionTagMatches: tagsForIdPrefix("deadbeef")}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	_, err := testing.RunCommand(c, action.NewCancelCommand(), "-e", "dummyenv", "deadbeef")
+	c.Assert(err, gc.ErrorMatches, `actions for identifier "deadbeef" not found`)
+	c.Check(fakeClient.cancelled.Entities, gc.HasLen, 0)
+}
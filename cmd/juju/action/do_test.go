@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"errors"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/juju/names"
@@ -69,6 +70,7 @@ func (s *DoSuite) TestInit(c *gc.C) {
 		expectParamsYamlPath string
 		expectParseStrings   bool
 		expectKVArgs         [][]string
+		expectTimeout        time.Duration
 		expectOutput         string
 		expectError          string
 	}{{
@@ -123,6 +125,16 @@ func (s *DoSuite) TestInit(c *gc.C) {
 		args:         []string{validUnitId, "valid-action-name"},
 		expectUnit:   names.NewUnitTag(validUnitId),
 		expectAction: "valid-action-name",
+	}, {
+		should:        "handle --timeout",
+		args:          []string{validUnitId, "valid-action-name", "--timeout", "90s"},
+		expectUnit:    names.NewUnitTag(validUnitId),
+		expectAction:  "valid-action-name",
+		expectTimeout: 90 * time.Second,
+	}, {
+		should:      "fail with negative --timeout",
+		args:        []string{validUnitId, "valid-action-name", "--timeout", "-1s"},
+		expectError: "invalid timeout -1s",
 	}, {
 		should:               "handle --params properly",
 		args:                 []string{validUnitId, "valid-action-name", "--params=foo.yml"},
@@ -177,6 +189,7 @@ func (s *DoSuite) TestInit(c *gc.C) {
 			c.Check(command.ParamsYAML().Path, gc.Equals, t.expectParamsYamlPath)
 			c.Check(command.Args(), jc.DeepEquals, t.expectKVArgs)
 			c.Check(command.ParseStrings(), gc.Equals, t.expectParseStrings)
+			c.Check(command.Timeout(), gc.Equals, t.expectTimeout)
 		} else {
 			c.Check(err, gc.ErrorMatches, t.expectError)
 		}
@@ -0,0 +1,166 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+func newDoServiceCommand() cmd.Command {
+	return envcmd.Wrap(&doServiceCommand{})
+}
+
+// doServiceCommand enqueues an Action on many units of a service at
+// once, as a single operation.
+type doServiceCommand struct {
+	ActionCommandBase
+	serviceTag   names.ServiceTag
+	actionName   string
+	units        string
+	unitNames    []string
+	batchSize    int
+	paramsYAML   cmd.FileVar
+	parseStrings bool
+	out          cmd.Output
+	args         [][]string
+}
+
+const doServiceDoc = `
+Queue an Action for execution on the units of a service, with a given set of
+params. By default the Action is queued on every unit of the service; use
+--units to give a comma-separated list of the units to target instead.
+
+With --batch-size, the Action is queued on at most that many units at a time;
+as each unit's Action completes or fails, the Action is queued on the next
+unit, until every unit has been visited.
+
+Params are given in the same way as for "juju action do". Displays the ID of
+the operation, for use with "juju action fetch", which summarises the
+progress of the Action across all of the units.
+
+Examples:
+
+$ juju action do-service mysql backup
+Operation queued with id: <ID>
+
+$ juju action do-service mysql pause --units mysql/0,mysql/2
+
+$ juju action do-service mysql upgrade --batch-size 2 --params p.yml
+
+$ juju action fetch <ID>
+action: upgrade
+operation: <ID>
+service: mysql
+status: running
+summary:
+  completed: 2
+  pending: 2
+  waiting: 3
+units:
+...
+`
+
+// SetFlags offers an option for YAML output.
+func (c *doServiceCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	f.Var(&c.paramsYAML, "params", "path to yaml-formatted params file")
+	f.BoolVar(&c.parseStrings, "string-args", false, "use raw string values of CLI args")
+	f.StringVar(&c.units, "units", "", "comma-separated list of the units to target")
+	f.IntVar(&c.batchSize, "batch-size", 0, "maximum number of units running the action at once")
+}
+
+func (c *doServiceCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "do-service",
+		Args:    "<service> <action name> [key.key.key...=value]",
+		Purpose: "queue an action for execution on many units of a service",
+		Doc:     doServiceDoc,
+	}
+}
+
+// Init gets the service tag, and checks for other correct args.
+func (c *doServiceCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no service specified")
+	case 1:
+		return errors.New("no action specified")
+	}
+	serviceName := args[0]
+	if !names.IsValidService(serviceName) {
+		return errors.Errorf("invalid service name %q", serviceName)
+	}
+	actionName := args[1]
+	if valid := ActionNameRule.MatchString(actionName); !valid {
+		return fmt.Errorf("invalid action name %q", actionName)
+	}
+	if c.batchSize < 0 {
+		return errors.Errorf("invalid batch size %d", c.batchSize)
+	}
+	if c.units != "" {
+		for _, unitName := range strings.Split(c.units, ",") {
+			unitName = strings.TrimSpace(unitName)
+			if !names.IsValidUnit(unitName) {
+				return errors.Errorf("invalid unit name %q", unitName)
+			}
+			c.unitNames = append(c.unitNames, unitName)
+		}
+	}
+	c.serviceTag = names.NewServiceTag(serviceName)
+	c.actionName = actionName
+	parsed, err := parseKeyValueArgs(args[2:])
+	if err != nil {
+		return err
+	}
+	c.args = parsed
+	return nil
+}
+
+func (c *doServiceCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	actionParams, err := buildActionParams(ctx, c.paramsYAML, c.args, c.parseStrings)
+	if err != nil {
+		return err
+	}
+
+	results, err := api.EnqueueOperations(params.ServiceActions{
+		Actions: []params.ServiceAction{{
+			Service:    c.serviceTag.String(),
+			Name:       c.actionName,
+			Parameters: actionParams,
+			Units:      c.unitNames,
+			BatchSize:  c.batchSize,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if len(results.Results) != 1 {
+		return errors.New("illegal number of results returned")
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.Operation == nil {
+		return errors.New("action failed to enqueue")
+	}
+
+	output := map[string]string{"Operation queued with id": result.Operation.Id}
+	return c.out.Write(ctx, output)
+}
@@ -0,0 +1,179 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"strings"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/action"
+	"github.com/juju/juju/testing"
+)
+
+type DoServiceSuite struct {
+	BaseActionSuite
+}
+
+var _ = gc.Suite(&DoServiceSuite{})
+
+func (s *DoServiceSuite) TestHelp(c *gc.C) {
+	cmd, _ := action.NewDoServiceCommand()
+	s.checkHelp(c, cmd)
+}
+
+func (s *DoServiceSuite) TestInit(c *gc.C) {
+	tests := []struct {
+		should          string
+		args            []string
+		expectService   names.ServiceTag
+		expectAction    string
+		expectUnits     []string
+		expectBatchSize int
+		expectKeyValues [][]string
+		expectError     string
+	}{{
+		should:      "fail with missing args",
+		args:        []string{},
+		expectError: "no service specified",
+	}, {
+		should:      "fail with no action specified",
+		args:        []string{validServiceId},
+		expectError: "no action specified",
+	}, {
+		should:      "fail with invalid service name",
+		args:        []string{invalidServiceId, "backup"},
+		expectError: "invalid service name \"" + invalidServiceId + "\"",
+	}, {
+		should:      "fail with invalid unit name",
+		args:        []string{validServiceId, "backup", "--units", "mysql/0,mysql"},
+		expectError: `invalid unit name "mysql"`,
+	}, {
+		should:      "fail with negative batch size",
+		args:        []string{validServiceId, "backup", "--batch-size", "-1"},
+		expectError: "invalid batch size -1",
+	}, {
+		should:        "init properly with no flags",
+		args:          []string{validServiceId, "backup"},
+		expectService: names.NewServiceTag(validServiceId),
+		expectAction:  "backup",
+	}, {
+		should:          "init properly with units, batch size and key-value args",
+		args:            []string{validServiceId, "backup", "--units", "mysql/0, mysql/2", "--batch-size", "1", "out=name"},
+		expectService:   names.NewServiceTag(validServiceId),
+		expectAction:    "backup",
+		expectUnits:     []string{"mysql/0", "mysql/2"},
+		expectBatchSize: 1,
+		expectKeyValues: [][]string{{"out", "name"}},
+	}}
+
+	for i, t := range tests {
+		c.Logf("test %d: should %s:\n$ juju action do-service %s\n", i,
+			t.should, strings.Join(t.args, " "))
+		wrappedCommand, command := action.NewDoServiceCommand()
+		args := append([]string{"-e", "dummyenv"}, t.args...)
+		err := testing.InitCommand(wrappedCommand, args)
+		if t.expectError != "" {
+			c.Check(err, gc.ErrorMatches, t.expectError)
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(command.ServiceTag(), gc.Equals, t.expectService)
+		c.Check(command.ActionName(), gc.Equals, t.expectAction)
+		c.Check(command.UnitNames(), jc.DeepEquals, t.expectUnits)
+		c.Check(command.BatchSize(), gc.Equals, t.expectBatchSize)
+		c.Check(command.Args(), jc.DeepEquals, t.expectKeyValues)
+	}
+}
+
+func (s *DoServiceSuite) TestRun(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		operationResults: []params.ActionOperationResult{{
+			Operation: &params.ActionOperation{Id: "some-id"},
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	wrappedCommand, _ := action.NewDoServiceCommand()
+	ctx, err := testing.RunCommand(c, wrappedCommand,
+		"-e", "dummyenv", validServiceId, "backup", "--batch-size", "2", "out=name")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, "Operation queued with id: some-id\n")
+	c.Check(fakeClient.serviceActions, jc.DeepEquals, params.ServiceActions{
+		Actions: []params.ServiceAction{{
+			Service:    names.NewServiceTag(validServiceId).String(),
+			Name:       "backup",
+			Parameters: map[string]interface{}{"out": "name"},
+			BatchSize:  2,
+		}},
+	})
+}
+
+func (s *DoServiceSuite) TestRunError(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		operationResults: []params.ActionOperationResult{{
+			Error: &params.Error{Message: `action "backup" not defined on service "mysql"`},
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	wrappedCommand, _ := action.NewDoServiceCommand()
+	_, err := testing.RunCommand(c, wrappedCommand, "-e", "dummyenv", validServiceId, "backup")
+	c.Assert(err, gc.ErrorMatches, `action "backup" not defined on service "mysql"`)
+}
+
+func (s *DoServiceSuite) TestFetchOperation(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		actionTagMatches: tagsForIdPrefix("some-id"),
+		operationResults: []params.ActionOperationResult{{
+			Operation: &params.ActionOperation{
+				Id:      "some-id",
+				Service: names.NewServiceTag(validServiceId).String(),
+				Name:    "backup",
+				Units: []params.ActionOperationUnit{{
+					Unit:      "mysql/0",
+					ActionTag: validActionTagString,
+					Status:    params.ActionCompleted,
+				}, {
+					Unit:    "mysql/1",
+					Status:  params.ActionFailed,
+					Message: `unit "mysql/1" not found`,
+				}, {
+					Unit:   "mysql/2",
+					Status: "waiting",
+				}},
+			},
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, action.NewFetchCommand(), "-e", "dummyenv", "some-id")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fakeClient.operationIds, jc.DeepEquals, []string{"some-id"})
+	c.Check(testing.Stdout(ctx), gc.Equals, `
+action: backup
+operation: some-id
+service: mysql
+status: running
+summary:
+  completed: 1
+  failed: 1
+  waiting: 1
+units:
+  mysql/0:
+    id: `+validActionId+`
+    status: completed
+  mysql/1:
+    message: unit "mysql/1" not found
+    status: failed
+  mysql/2:
+    status: waiting
+`[1:])
+}
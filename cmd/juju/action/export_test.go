@@ -4,6 +4,8 @@
 package action
 
 import (
+	"time"
+
 	"github.com/juju/cmd"
 	"github.com/juju/names"
 
@@ -16,6 +18,10 @@ var (
 	AddValueToMap      = addValueToMap
 	NewFetchCommand    = newFetchCommand
 	NewStatusCommand   = newStatusCommand
+	NewCancelCommand   = newCancelCommand
+
+	NewListSchedulesCommand = newListSchedulesCommand
+	NewUnscheduleCommand    = newUnscheduleCommand
 )
 
 type DoCommand struct {
@@ -38,6 +44,10 @@ func (c *DoCommand) ParamsYAML() cmd.FileVar {
 	return c.paramsYAML
 }
 
+func (c *DoCommand) Timeout() time.Duration {
+	return c.timeout
+}
+
 func (c *DoCommand) Args() [][]string {
 	return c.args
 }
@@ -63,6 +73,61 @@ func NewDoCommand() (cmd.Command, *DoCommand) {
 	c := &doCommand{}
 	return envcmd.Wrap(c, envcmd.EnvSkipDefault), &DoCommand{c}
 }
+
+type ScheduleCommand struct {
+	*scheduleCommand
+}
+
+func (c *ScheduleCommand) UnitTag() names.UnitTag {
+	return c.unitTag
+}
+
+func (c *ScheduleCommand) ActionName() string {
+	return c.actionName
+}
+
+func (c *ScheduleCommand) Schedule() string {
+	return c.schedule
+}
+
+func (c *ScheduleCommand) Args() [][]string {
+	return c.args
+}
+
+func NewScheduleCommand() (cmd.Command, *ScheduleCommand) {
+	c := &scheduleCommand{}
+	return envcmd.Wrap(c, envcmd.EnvSkipDefault), &ScheduleCommand{c}
+}
+
+type DoServiceCommand struct {
+	*doServiceCommand
+}
+
+func (c *DoServiceCommand) ServiceTag() names.ServiceTag {
+	return c.serviceTag
+}
+
+func (c *DoServiceCommand) ActionName() string {
+	return c.actionName
+}
+
+func (c *DoServiceCommand) UnitNames() []string {
+	return c.unitNames
+}
+
+func (c *DoServiceCommand) BatchSize() int {
+	return c.batchSize
+}
+
+func (c *DoServiceCommand) Args() [][]string {
+	return c.args
+}
+
+func NewDoServiceCommand() (cmd.Command, *DoServiceCommand) {
+	c := &doServiceCommand{}
+	return envcmd.Wrap(c, envcmd.EnvSkipDefault), &DoServiceCommand{c}
+}
+
 func ActionResultsToMap(results []params.ActionResult) map[string]interface{} {
 	return resultsToMap(results)
 }
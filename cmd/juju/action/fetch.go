@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/cmd"
 	errors "github.com/juju/errors"
+	"github.com/juju/names"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
@@ -30,7 +31,9 @@ type fetchCommand struct {
 
 const fetchDoc = `
 Show the results returned by an action with the given ID.  A partial ID may
-also be used.  To block until the result is known completed or failed, use
+also be used.  If the ID is that of an operation queued by
+"juju action do-service", a summary of the operation's progress on each unit
+is shown instead, and --wait is ignored.  To block until the result is known completed or failed, use
 the --wait flag with a duration, as in --wait 5s or --wait 1h.  Use --wait 0
 to wait indefinitely.  If units are left off, seconds are assumed.
 
@@ -88,6 +91,22 @@ func (c *fetchCommand) Run(ctx *cmd.Context) error {
 	}
 	defer api.Close()
 
+	// If the ID does not identify an action, it may identify an
+	// operation started by "juju action do-service".
+	actionTags, err := getActionTagsByPrefix(api, c.requestedId)
+	if err != nil {
+		return err
+	}
+	if len(actionTags) == 0 {
+		operation, err := fetchOperation(api, c.requestedId)
+		if err != nil {
+			return err
+		}
+		if operation != nil {
+			return c.out.Write(ctx, formatActionOperation(*operation))
+		}
+	}
+
 	// tick every two seconds, to delay the loop timer.
 	tick := time.NewTimer(2 * time.Second)
 	wait := time.NewTimer(0 * time.Second)
@@ -211,3 +230,71 @@ func formatActionResult(result params.ActionResult) map[string]interface{} {
 
 	return response
 }
+
+// fetchOperation queries the given API for the operation with the given
+// ID prefix. It returns nil if there is no such operation.
+func fetchOperation(api APIClient, requestedId string) (*params.ActionOperation, error) {
+	results, err := api.ActionOperations(params.ActionOperationIds{Ids: []string{requestedId}})
+	if errors.IsNotImplemented(err) {
+		// Older API servers have no operations.
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, nil
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		if params.IsCodeNotFound(result.Error) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return result.Operation, nil
+}
+
+// formatActionOperation summarises the progress of an operation across
+// its units in a map[string]interface{} for cmd.Output to write. The
+// operation is running until every unit's action has finished, and has
+// failed if any of them failed.
+func formatActionOperation(op params.ActionOperation) map[string]interface{} {
+	summary := make(map[string]int)
+	units := make(map[string]interface{})
+	for _, unit := range op.Units {
+		summary[unit.Status]++
+		formatted := map[string]interface{}{"status": unit.Status}
+		if tag, err := names.ParseActionTag(unit.ActionTag); err == nil {
+			formatted["id"] = tag.Id()
+		}
+		if unit.Message != "" {
+			formatted["message"] = unit.Message
+		}
+		units[unit.Unit] = formatted
+	}
+
+	status := params.ActionCompleted
+	switch {
+	case summary[params.ActionPending] > 0, summary[params.ActionRunning] > 0, summary["waiting"] > 0:
+		status = params.ActionRunning
+	case summary[params.ActionFailed] > 0, summary[params.ActionCancelled] > 0:
+		status = params.ActionFailed
+	}
+
+	service := op.Service
+	if tag, err := names.ParseServiceTag(op.Service); err == nil {
+		service = tag.Id()
+	}
+	response := map[string]interface{}{
+		"operation": op.Id,
+		"service":   service,
+		"action":    op.Name,
+		"status":    status,
+		"summary":   summary,
+		"units":     units,
+	}
+	if len(op.Parameters) > 0 {
+		response["parameters"] = op.Parameters
+	}
+	return response
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	jujuerrors "github.com/juju/errors"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/common"
@@ -289,6 +290,13 @@ timing:
 	}
 }
 
+func (s *FetchSuite) TestRunOperationsNotSupported(c *gc.C) {
+	client := makeFakeClient(0, 10*time.Second, tagsForIdPrefix(validActionId), nil, "")
+	client.operationsErr = jujuerrors.NotImplementedf("ActionOperations")
+	testRunHelper(c, s, client, `actions for identifier "`+validActionId+`" not found`, "", "", validActionId)
+	c.Check(client.operationIds, gc.DeepEquals, []string{validActionId})
+}
+
 func testRunHelper(c *gc.C, s *FetchSuite, client *fakeAPIClient, expectedErr, expectedOutput, wait, query string) {
 	unpatch := s.BaseActionSuite.patchAPIClient(client)
 	defer unpatch()
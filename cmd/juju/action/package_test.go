@@ -135,6 +135,16 @@ type fakeAPIClient struct {
 	actionsByReceivers []params.ActionsByReceiver
 	actionTagMatches   params.FindTagsResults
 	charmActions       *charm.Actions
+	scheduleArgs       params.ScheduleActions
+	scheduledResults   []params.ScheduledActionResult
+	listSchedulesArgs  params.Entities
+	unscheduledIds     []string
+	unscheduleResults  []params.ErrorResult
+	serviceActions     params.ServiceActions
+	operationIds       []string
+	operationResults   []params.ActionOperationResult
+	operationsErr      error
+	cancelled          params.Entities
 	apiErr             error
 }
 
@@ -173,7 +183,8 @@ func (c *fakeAPIClient) ListCompleted(args params.Entities) (params.ActionsByRec
 	}, c.apiErr
 }
 
-func (c *fakeAPIClient) Cancel(args params.Actions) (params.ActionResults, error) {
+func (c *fakeAPIClient) Cancel(args params.Entities) (params.ActionResults, error) {
+	c.cancelled = args
 	return params.ActionResults{
 		Results: c.actionResults,
 	}, c.apiErr
@@ -213,3 +224,31 @@ func (c *fakeAPIClient) Actions(args params.Entities) (params.ActionResults, err
 func (c *fakeAPIClient) FindActionTagsByPrefix(arg params.FindTags) (params.FindTagsResults, error) {
 	return c.actionTagMatches, c.apiErr
 }
+
+func (c *fakeAPIClient) Schedule(args params.ScheduleActions) (params.ScheduledActionResults, error) {
+	c.scheduleArgs = args
+	return params.ScheduledActionResults{Results: c.scheduledResults}, c.apiErr
+}
+
+func (c *fakeAPIClient) ListSchedules(args params.Entities) (params.ScheduledActionResults, error) {
+	c.listSchedulesArgs = args
+	return params.ScheduledActionResults{Results: c.scheduledResults}, c.apiErr
+}
+
+func (c *fakeAPIClient) Unschedule(args params.ScheduledActionIds) (params.ErrorResults, error) {
+	c.unscheduledIds = args.Ids
+	return params.ErrorResults{Results: c.unscheduleResults}, c.apiErr
+}
+
+func (c *fakeAPIClient) EnqueueOperations(args params.ServiceActions) (params.ActionOperationResults, error) {
+	c.serviceActions = args
+	return params.ActionOperationResults{Results: c.operationResults}, c.apiErr
+}
+
+func (c *fakeAPIClient) ActionOperations(args params.ActionOperationIds) (params.ActionOperationResults, error) {
+	c.operationIds = args.Ids
+	if c.operationsErr != nil {
+		return params.ActionOperationResults{}, c.operationsErr
+	}
+	return params.ActionOperationResults{Results: c.operationResults}, c.apiErr
+}
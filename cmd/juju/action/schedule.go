@@ -0,0 +1,313 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/utils/cron"
+)
+
+func newScheduleCommand() cmd.Command {
+	return envcmd.Wrap(&scheduleCommand{})
+}
+
+// scheduleCommand arranges for an Action to be enqueued on a unit
+// repeatedly, according to a cron schedule.
+type scheduleCommand struct {
+	ActionCommandBase
+	unitTag      names.UnitTag
+	actionName   string
+	schedule     string
+	paramsYAML   cmd.FileVar
+	parseStrings bool
+	out          cmd.Output
+	args         [][]string
+}
+
+const scheduleDoc = `
+Schedule an Action to be queued for execution on a given unit each time a
+cron schedule fires. The schedule is given in the standard five-field cron
+format, "minute hour day-of-month month day-of-week", and must be quoted so
+that it forms a single argument. The aliases @hourly, @daily, @weekly,
+@monthly and @yearly may also be used. Schedules are evaluated in UTC.
+
+Params are validated and may be given in the same way as for
+"juju action do". Each time the schedule fires, a new Action is queued;
+"juju action list-schedules" shows the IDs of the Actions queued by recent
+runs, for use with "juju action fetch".
+
+Examples:
+
+$ juju action schedule mysql/3 backup "30 2 * * *"
+Action scheduled with id: <ID>
+
+$ juju action schedule mysql/3 backup @weekly --params parameters.yml
+...
+`
+
+// SetFlags offers an option for YAML output.
+func (c *scheduleCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	f.Var(&c.paramsYAML, "params", "path to yaml-formatted params file")
+	f.BoolVar(&c.parseStrings, "string-args", false, "use raw string values of CLI args")
+}
+
+func (c *scheduleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "schedule",
+		Args:    "<unit> <action name> <schedule> [key.key.key...=value]",
+		Purpose: "queue an action for execution on a recurring schedule",
+		Doc:     scheduleDoc,
+	}
+}
+
+// Init gets the unit tag, action name and schedule, and checks for
+// other correct args.
+func (c *scheduleCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no unit specified")
+	case 1:
+		return errors.New("no action specified")
+	case 2:
+		return errors.New("no schedule specified")
+	}
+	unitName := args[0]
+	if !names.IsValidUnit(unitName) {
+		return errors.Errorf("invalid unit name %q", unitName)
+	}
+	actionName := args[1]
+	if valid := ActionNameRule.MatchString(actionName); !valid {
+		return fmt.Errorf("invalid action name %q", actionName)
+	}
+	if _, err := cron.Parse(args[2]); err != nil {
+		return err
+	}
+	c.unitTag = names.NewUnitTag(unitName)
+	c.actionName = actionName
+	c.schedule = args[2]
+	parsed, err := parseKeyValueArgs(args[3:])
+	if err != nil {
+		return err
+	}
+	c.args = parsed
+	return nil
+}
+
+func (c *scheduleCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	actionParams, err := buildActionParams(ctx, c.paramsYAML, c.args, c.parseStrings)
+	if err != nil {
+		return err
+	}
+
+	results, err := api.Schedule(params.ScheduleActions{
+		Actions: []params.ScheduleAction{{
+			Receiver:   c.unitTag.String(),
+			Name:       c.actionName,
+			Parameters: actionParams,
+			Schedule:   c.schedule,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if len(results.Results) != 1 {
+		return errors.New("illegal number of results returned")
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.Result == nil {
+		return errors.New("action failed to schedule")
+	}
+
+	output := map[string]string{
+		"Action scheduled with id": result.Result.Id,
+		"Next run":                 result.Result.NextRun.String(),
+	}
+	return c.out.Write(ctx, output)
+}
+
+func newListSchedulesCommand() cmd.Command {
+	return envcmd.Wrap(&listSchedulesCommand{})
+}
+
+// listSchedulesCommand shows scheduled actions and their recent runs.
+type listSchedulesCommand struct {
+	ActionCommandBase
+	unitTags []names.UnitTag
+	out      cmd.Output
+}
+
+const listSchedulesDoc = `
+Show the Actions scheduled on the given units, or on all units if none are
+given, along with the time each will next be queued and the IDs of the
+Actions queued by its most recent runs.
+`
+
+// SetFlags offers an option for YAML output.
+func (c *listSchedulesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+}
+
+func (c *listSchedulesCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list-schedules",
+		Args:    "[<unit> ...]",
+		Purpose: "show scheduled actions",
+		Doc:     listSchedulesDoc,
+	}
+}
+
+// Init validates the unit names, if any.
+func (c *listSchedulesCommand) Init(args []string) error {
+	for _, unitName := range args {
+		if !names.IsValidUnit(unitName) {
+			return errors.Errorf("invalid unit name %q", unitName)
+		}
+		c.unitTags = append(c.unitTags, names.NewUnitTag(unitName))
+	}
+	return nil
+}
+
+func (c *listSchedulesCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	entities := params.Entities{Entities: make([]params.Entity, len(c.unitTags))}
+	for i, tag := range c.unitTags {
+		entities.Entities[i] = params.Entity{Tag: tag.String()}
+	}
+	results, err := api.ListSchedules(entities)
+	if err != nil {
+		return err
+	}
+	output := make(map[string]interface{})
+	for _, result := range results.Results {
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.Result == nil {
+			continue
+		}
+		output[result.Result.Id] = formatScheduledAction(*result.Result)
+	}
+	if len(output) == 0 {
+		ctx.Infof("no scheduled actions found")
+		return nil
+	}
+	return c.out.Write(ctx, output)
+}
+
+// formatScheduledAction converts a scheduled action into a map for
+// display.
+func formatScheduledAction(sa params.ScheduledAction) map[string]interface{} {
+	unit := sa.Receiver
+	if tag, err := names.ParseUnitTag(sa.Receiver); err == nil {
+		unit = tag.Id()
+	}
+	formatted := map[string]interface{}{
+		"unit":     unit,
+		"action":   sa.Name,
+		"schedule": sa.Schedule,
+		"next-run": sa.NextRun.String(),
+	}
+	if len(sa.Parameters) > 0 {
+		formatted["parameters"] = sa.Parameters
+	}
+	if len(sa.Runs) == 0 {
+		return formatted
+	}
+	runs := make([]map[string]interface{}, len(sa.Runs))
+	for i, run := range sa.Runs {
+		formattedRun := map[string]interface{}{
+			"enqueued": run.Enqueued.String(),
+		}
+		if tag, err := names.ParseActionTag(run.ActionTag); err == nil {
+			formattedRun["id"] = tag.Id()
+		}
+		if run.Error != "" {
+			formattedRun["error"] = run.Error
+		}
+		runs[i] = formattedRun
+	}
+	formatted["runs"] = runs
+	return formatted
+}
+
+func newUnscheduleCommand() cmd.Command {
+	return envcmd.Wrap(&unscheduleCommand{})
+}
+
+// unscheduleCommand removes scheduled actions.
+type unscheduleCommand struct {
+	ActionCommandBase
+	ids []string
+}
+
+const unscheduleDoc = `
+Remove the scheduled actions with the given IDs, as shown by
+"juju action list-schedules". Actions that have already been queued by the
+schedules are not affected.
+`
+
+func (c *unscheduleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "unschedule",
+		Args:    "<schedule ID> ...",
+		Purpose: "remove scheduled actions",
+		Doc:     unscheduleDoc,
+	}
+}
+
+// Init checks that at least one schedule ID was given.
+func (c *unscheduleCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no schedule ID specified")
+	}
+	c.ids = args
+	return nil
+}
+
+func (c *unscheduleCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.Unschedule(params.ScheduledActionIds{Ids: c.ids})
+	if err != nil {
+		return err
+	}
+	var failed bool
+	for i, result := range results.Results {
+		if result.Error != nil {
+			fmt.Fprintf(ctx.Stderr, "schedule %s: %v\n", c.ids[i], result.Error)
+			failed = true
+		}
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
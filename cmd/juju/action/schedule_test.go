@@ -0,0 +1,199 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/action"
+	"github.com/juju/juju/testing"
+)
+
+type ScheduleSuite struct {
+	BaseActionSuite
+}
+
+var _ = gc.Suite(&ScheduleSuite{})
+
+func (s *ScheduleSuite) TestHelp(c *gc.C) {
+	cmd, _ := action.NewScheduleCommand()
+	s.checkHelp(c, cmd)
+	s.checkHelp(c, action.NewListSchedulesCommand())
+	s.checkHelp(c, action.NewUnscheduleCommand())
+}
+
+func (s *ScheduleSuite) TestInit(c *gc.C) {
+	tests := []struct {
+		should          string
+		args            []string
+		expectUnit      names.UnitTag
+		expectAction    string
+		expectSchedule  string
+		expectKeyValues [][]string
+		expectError     string
+	}{{
+		should:      "fail with missing args",
+		args:        []string{},
+		expectError: "no unit specified",
+	}, {
+		should:      "fail with no action specified",
+		args:        []string{validUnitId},
+		expectError: "no action specified",
+	}, {
+		should:      "fail with no schedule specified",
+		args:        []string{validUnitId, "backup"},
+		expectError: "no schedule specified",
+	}, {
+		should:      "fail with invalid unit tag",
+		args:        []string{invalidUnitId, "backup", "@daily"},
+		expectError: "invalid unit name \"" + invalidUnitId + "\"",
+	}, {
+		should:      "fail with invalid schedule",
+		args:        []string{validUnitId, "backup", "0 2 * *"},
+		expectError: `cron spec "0 2 \* \*": expected 5 fields, got 4`,
+	}, {
+		should:      "fail with invalid key-value arg",
+		args:        []string{validUnitId, "backup", "@daily", "out"},
+		expectError: `argument "out" must be of the form key...=value`,
+	}, {
+		should:          "init properly with key-value args",
+		args:            []string{validUnitId, "backup", "30 2 * * *", "out=name", "file.kind=xz"},
+		expectUnit:      names.NewUnitTag(validUnitId),
+		expectAction:    "backup",
+		expectSchedule:  "30 2 * * *",
+		expectKeyValues: [][]string{{"out", "name"}, {"file", "kind", "xz"}},
+	}}
+
+	for i, t := range tests {
+		c.Logf("test %d: should %s:\n$ juju action schedule %s\n", i,
+			t.should, strings.Join(t.args, " "))
+		wrappedCommand, command := action.NewScheduleCommand()
+		args := append([]string{"-e", "dummyenv"}, t.args...)
+		err := testing.InitCommand(wrappedCommand, args)
+		if t.expectError != "" {
+			c.Check(err, gc.ErrorMatches, t.expectError)
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(command.UnitTag(), gc.Equals, t.expectUnit)
+		c.Check(command.ActionName(), gc.Equals, t.expectAction)
+		c.Check(command.Schedule(), gc.Equals, t.expectSchedule)
+		c.Check(command.Args(), jc.DeepEquals, t.expectKeyValues)
+	}
+}
+
+func (s *ScheduleSuite) TestRunSchedule(c *gc.C) {
+	nextRun := time.Date(2015, time.October, 2, 2, 30, 0, 0, time.UTC)
+	fakeClient := &fakeAPIClient{
+		scheduledResults: []params.ScheduledActionResult{{
+			Result: &params.ScheduledAction{Id: "some-id", NextRun: nextRun},
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	wrappedCommand, _ := action.NewScheduleCommand()
+	ctx, err := testing.RunCommand(c, wrappedCommand,
+		"-e", "dummyenv", validUnitId, "backup", "30 2 * * *", "out=name")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, `
+Action scheduled with id: some-id
+Next run: 2015-10-02 02:30:00 +0000 UTC
+`[1:])
+	c.Check(fakeClient.scheduleArgs, jc.DeepEquals, params.ScheduleActions{
+		Actions: []params.ScheduleAction{{
+			Receiver:   names.NewUnitTag(validUnitId).String(),
+			Name:       "backup",
+			Parameters: map[string]interface{}{"out": "name"},
+			Schedule:   "30 2 * * *",
+		}},
+	})
+}
+
+func (s *ScheduleSuite) TestRunScheduleError(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		scheduledResults: []params.ScheduledActionResult{{
+			Error: &params.Error{Message: `action "backup" not defined on unit "mysql/0"`},
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	wrappedCommand, _ := action.NewScheduleCommand()
+	_, err := testing.RunCommand(c, wrappedCommand, "-e", "dummyenv", validUnitId, "backup", "@daily")
+	c.Assert(err, gc.ErrorMatches, `action "backup" not defined on unit "mysql/0"`)
+}
+
+func (s *ScheduleSuite) TestRunListSchedules(c *gc.C) {
+	created := time.Date(2015, time.October, 1, 12, 0, 0, 0, time.UTC)
+	fakeClient := &fakeAPIClient{
+		scheduledResults: []params.ScheduledActionResult{{
+			Result: &params.ScheduledAction{
+				Id:         "some-id",
+				Receiver:   names.NewUnitTag(validUnitId).String(),
+				Name:       "backup",
+				Parameters: map[string]interface{}{"out": "name"},
+				Schedule:   "30 2 * * *",
+				Created:    created,
+				NextRun:    time.Date(2015, time.October, 3, 2, 30, 0, 0, time.UTC),
+				Runs: []params.ScheduledActionRun{{
+					ActionTag: validActionTagString,
+					Enqueued:  time.Date(2015, time.October, 2, 2, 30, 0, 0, time.UTC),
+				}},
+			},
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, action.NewListSchedulesCommand(), "-e", "dummyenv", validUnitId)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fakeClient.listSchedulesArgs, jc.DeepEquals, params.Entities{
+		Entities: []params.Entity{{Tag: names.NewUnitTag(validUnitId).String()}},
+	})
+	c.Check(testing.Stdout(ctx), gc.Equals, `
+some-id:
+  action: backup
+  next-run: 2015-10-03 02:30:00 +0000 UTC
+  parameters:
+    out: name
+  runs:
+  - enqueued: 2015-10-02 02:30:00 +0000 UTC
+    id: `+validActionId+`
+  schedule: 30 2 * * *
+  unit: mysql/0
+`[1:])
+}
+
+func (s *ScheduleSuite) TestRunListSchedulesEmpty(c *gc.C) {
+	restore := s.patchAPIClient(&fakeAPIClient{})
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, action.NewListSchedulesCommand(), "-e", "dummyenv")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, "")
+	c.Check(testing.Stderr(ctx), gc.Equals, "no scheduled actions found\n")
+}
+
+func (s *ScheduleSuite) TestRunUnschedule(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		unscheduleResults: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Message: `scheduled action "bad-id" not found`}},
+		},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, action.NewUnscheduleCommand(), "-e", "dummyenv", "some-id", "bad-id")
+	c.Assert(err, gc.ErrorMatches, "cmd: error out silently")
+	c.Check(fakeClient.unscheduledIds, jc.DeepEquals, []string{"some-id", "bad-id"})
+	c.Check(testing.Stderr(ctx), gc.Equals, "schedule bad-id: scheduled action \"bad-id\" not found\n")
+}
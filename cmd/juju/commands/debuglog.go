@@ -6,13 +6,19 @@ package commands
 import (
 	"fmt"
 	"io"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/loglabels"
 )
 
 func newDebugLogCommand() cmd.Command {
@@ -23,6 +29,10 @@ type debugLogCommand struct {
 	envcmd.EnvCommandBase
 
 	level  string
+	since  string
+	until  string
+	format string
+	labels []string
 	params api.DebugLogParams
 }
 
@@ -35,6 +45,35 @@ const defaultLineCount = 10
 const debuglogDoc = `
 Stream the consolidated debug log file. This file contains the log messages
 from all nodes in the environment.
+
+The --since and --until options restrict the log to messages logged within
+a time range. Each takes either an RFC3339 timestamp such as
+2015-10-21T16:00:00Z, or a duration such as 2h meaning that long ago. Either
+implies --replay, so that all matching messages in the range are shown;
+with --until, the command exits once that time has passed and they have
+been shown.
+
+The --message option only shows messages matching a regular expression in
+Go's regexp syntax, which is evaluated by the server.
+
+The --label option only shows messages carrying a label with the given
+value, and may be repeated to require several labels. Unit agents label
+the output and juju-log messages of a hook or action with "hook", "action"
+and, for relation hooks, "relation"; the API server labels its request
+and reply messages with "request".
+
+With --format json, each message is printed on its own line as a JSON
+object holding its time, entity, module, location, level, message and
+labels, so that the log can be processed by other tools. Messages
+imported from a log archive are marked as imported in either format.
+
+The time range, --message, --label and --format json options require the
+log to be stored in the database, as with the db-log feature.
+
+Examples:
+    juju debug-log --since 2h --until 1h --message "hook failed"
+    juju debug-log --replay --format json -i unit-mysql-0
+    juju debug-log --replay -i unit-mysql-0 --label hook=db-relation-joined --label relation=1
 `
 
 func (c *debugLogCommand) Info() *cmd.Info {
@@ -60,6 +99,11 @@ func (c *debugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.UintVar(&c.params.Backlog, "lines", defaultLineCount, "")
 	f.UintVar(&c.params.Limit, "limit", 0, "show at most this many lines")
 	f.BoolVar(&c.params.Replay, "replay", false, "start filtering from the start")
+	f.StringVar(&c.since, "since", "", "only show log messages logged since this time or duration ago")
+	f.StringVar(&c.until, "until", "", "only show log messages logged until this time or duration ago, then exit")
+	f.StringVar(&c.params.MessageRegex, "message", "", "only show log messages matching this regular expression")
+	f.Var(cmd.NewAppendStringsValue(&c.labels), "label", "only show log messages carrying this label, as name=value")
+	f.StringVar(&c.format, "format", "text", "output format, one of [text, json]")
 }
 
 func (c *debugLogCommand) Init(args []string) error {
@@ -71,6 +115,51 @@ func (c *debugLogCommand) Init(args []string) error {
 		}
 		c.params.Level = level
 	}
+	now := time.Now()
+	if c.since != "" {
+		t, err := common.ParseTime(c.since, now)
+		if err != nil {
+			return errors.Annotate(err, "invalid --since")
+		}
+		c.params.StartTime = t
+		c.params.Replay = true
+	}
+	if c.until != "" {
+		t, err := common.ParseTime(c.until, now)
+		if err != nil {
+			return errors.Annotate(err, "invalid --until")
+		}
+		c.params.EndTime = t
+		c.params.Replay = true
+	}
+	if !c.params.StartTime.IsZero() && !c.params.EndTime.IsZero() && c.params.EndTime.Before(c.params.StartTime) {
+		return errors.Errorf("--until must not be before --since")
+	}
+	if c.params.MessageRegex != "" {
+		if _, err := regexp.Compile(c.params.MessageRegex); err != nil {
+			return errors.Annotate(err, "invalid --message")
+		}
+	}
+	for _, label := range c.labels {
+		parts := strings.SplitN(label, "=", 2)
+		if len(parts) != 2 {
+			return errors.Errorf("invalid --label %q: expected name=value", label)
+		}
+		if err := loglabels.ValidateName(parts[0]); err != nil {
+			return errors.Annotatef(err, "invalid --label %q", label)
+		}
+		if c.params.Labels == nil {
+			c.params.Labels = make(map[string]string)
+		}
+		c.params.Labels[parts[0]] = parts[1]
+	}
+	switch c.format {
+	case "text":
+	case "json":
+		c.params.JSON = true
+	default:
+		return errors.Errorf("format value %q is not one of %q, %q", c.format, "text", "json")
+	}
 	return cmd.CheckEmpty(args)
 }
 
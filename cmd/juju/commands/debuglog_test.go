@@ -7,6 +7,7 @@ import (
 	"io"
 	"io/ioutil"
 	"strings"
+	"time"
 
 	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
@@ -85,6 +86,50 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 				Backlog: 10,
 				Limit:   100,
 			},
+		}, {
+			args: []string{"--since", "2015-10-21T16:00:00Z", "--until", "2015-10-21T17:00:00Z"},
+			expected: api.DebugLogParams{
+				Backlog:   10,
+				Replay:    true,
+				StartTime: time.Date(2015, 10, 21, 16, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(2015, 10, 21, 17, 0, 0, 0, time.UTC),
+			},
+		}, {
+			args:     []string{"--since", "yesterday"},
+			errMatch: `invalid --since: invalid time "yesterday": expected an RFC3339 timestamp or a duration such as 2h`,
+		}, {
+			args:     []string{"--since", "1h", "--until", "2h"},
+			errMatch: "--until must not be before --since",
+		}, {
+			args: []string{"--message", "hook fail(ed|ure)"},
+			expected: api.DebugLogParams{
+				Backlog:      10,
+				MessageRegex: "hook fail(ed|ure)",
+			},
+		}, {
+			args:     []string{"--message", "("},
+			errMatch: "invalid --message: error parsing regexp: .*",
+		}, {
+			args: []string{"--label", "hook=db-relation-joined", "--label", "relation=1"},
+			expected: api.DebugLogParams{
+				Backlog: 10,
+				Labels:  map[string]string{"hook": "db-relation-joined", "relation": "1"},
+			},
+		}, {
+			args:     []string{"--label", "hook"},
+			errMatch: `invalid --label "hook": expected name=value`,
+		}, {
+			args:     []string{"--label", "Hook=install"},
+			errMatch: `invalid --label "Hook=install": label name "Hook" not valid`,
+		}, {
+			args: []string{"--format", "json"},
+			expected: api.DebugLogParams{
+				Backlog: 10,
+				JSON:    true,
+			},
+		}, {
+			args:     []string{"--format", "yaml"},
+			errMatch: `format value "yaml" is not one of "text", "json"`,
 		},
 	} {
 		c.Logf("test %v", i)
@@ -120,6 +165,19 @@ func (s *DebugLogSuite) TestParamsPassed(c *gc.C) {
 	})
 }
 
+func (s *DebugLogSuite) TestRelativeTimeRange(c *gc.C) {
+	before := time.Now()
+	command := &debugLogCommand{}
+	err := testing.InitCommand(envcmd.Wrap(command), []string{"--since", "2h", "--until", "1h"})
+	c.Assert(err, jc.ErrorIsNil)
+	after := time.Now()
+	c.Assert(command.params.Replay, jc.IsTrue)
+	c.Assert(command.params.StartTime.Before(before.Add(-2*time.Hour)), jc.IsFalse)
+	c.Assert(command.params.StartTime.After(after.Add(-2*time.Hour)), jc.IsFalse)
+	c.Assert(command.params.EndTime.Before(before.Add(-time.Hour)), jc.IsFalse)
+	c.Assert(command.params.EndTime.After(after.Add(-time.Hour)), jc.IsFalse)
+}
+
 func (s *DebugLogSuite) TestLogOutput(c *gc.C) {
 	s.PatchValue(&getDebugLogAPI, func(_ *debugLogCommand) (DebugLogAPI, error) {
 		return &fakeDebugLogAPI{log: "this is the log output"}, nil
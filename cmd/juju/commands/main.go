@@ -19,7 +19,11 @@ import (
 	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/juju/environment"
 	"github.com/juju/juju/cmd/juju/helptopics"
+	"github.com/juju/juju/cmd/juju/leadership"
+	"github.com/juju/juju/cmd/juju/logarchive"
+	"github.com/juju/juju/cmd/juju/loggingconfig"
 	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/cmd/juju/metricsdebug"
 	"github.com/juju/juju/cmd/juju/service"
 	"github.com/juju/juju/cmd/juju/space"
 	"github.com/juju/juju/cmd/juju/status"
@@ -27,6 +31,7 @@ import (
 	"github.com/juju/juju/cmd/juju/subnet"
 	"github.com/juju/juju/cmd/juju/system"
 	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/cmd/juju/webhook"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/juju"
 	"github.com/juju/juju/juju/osenv"
@@ -139,6 +144,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(newEndpointCommand())
 	r.Register(newAPIInfoCommand())
 	r.Register(status.NewStatusHistoryCommand())
+	r.Register(metricsdebug.NewMetricsCommand())
 
 	// Error resolution and debugging commands.
 	r.Register(newRunCommand())
@@ -215,6 +221,18 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	// Manage storage
 	r.Register(storage.NewSuperCommand())
 
+	// Manage service leadership
+	r.Register(leadership.NewSuperCommand())
+
+	// Manage log archives
+	r.Register(logarchive.NewSuperCommand())
+
+	// Manage the logging config of individual agents
+	r.Register(loggingconfig.NewSuperCommand())
+
+	// Manage webhooks
+	r.Register(webhook.NewSuperCommand())
+
 	// Manage spaces
 	r.Register(space.NewSuperCommand())
 
@@ -229,7 +229,11 @@ var commandNames = []string{
 	"help",
 	"help-tool",
 	"init",
+	"leadership",
+	"log-archives",
+	"logging-config",
 	"machine",
+	"metrics",
 	"publish",
 	"remove-machine",  // alias for destroy-machine
 	"remove-relation", // alias for destroy-relation
@@ -263,6 +267,7 @@ var commandNames = []string{
 	"upgrade-juju",
 	"user",
 	"version",
+	"webhook",
 }
 
 func (s *MainSuite) TestHelpCommands(c *gc.C) {
@@ -26,6 +26,20 @@ func FormatTime(t *time.Time, formatISO bool) string {
 	return t.Local().Format("02 Jan 2006 15:04:05Z07:00")
 }
 
+// ParseTime parses a time given on the command line, either as an
+// RFC3339 timestamp or as a duration such as 2h, meaning that long
+// before now.
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.Errorf("invalid time %q: expected an RFC3339 timestamp or a duration such as 2h", value)
+	}
+	return t, nil
+}
+
 // ConformYAML ensures all keys of any nested maps are strings.  This is
 // necessary because YAML unmarshals map[interface{}]interface{} in nested
 // maps, which cannot be serialized by bson. Also, handle []interface{}.
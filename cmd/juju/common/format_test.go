@@ -4,6 +4,8 @@
 package common_test
 
 import (
+	"time"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -114,3 +116,34 @@ func (s *ConformSuite) TestConformYAML(c *gc.C) {
 		}
 	}
 }
+
+type ParseTimeSuite struct{}
+
+var _ = gc.Suite(&ParseTimeSuite{})
+
+func (s *ParseTimeSuite) TestParseTime(c *gc.C) {
+	now := time.Date(2015, 10, 21, 16, 0, 0, 0, time.UTC)
+	for i, test := range []struct {
+		value    string
+		expected time.Time
+		err      string
+	}{{
+		value:    "2015-10-21T09:30:00Z",
+		expected: time.Date(2015, 10, 21, 9, 30, 0, 0, time.UTC),
+	}, {
+		value:    "90m",
+		expected: time.Date(2015, 10, 21, 14, 30, 0, 0, time.UTC),
+	}, {
+		value: "yesterday",
+		err:   `invalid time "yesterday": expected an RFC3339 timestamp or a duration such as 2h`,
+	}} {
+		c.Logf("test %d: %q", i, test.value)
+		t, err := common.ParseTime(test.value, now)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(t.Equal(test.expected), jc.IsTrue)
+	}
+}
@@ -101,6 +101,24 @@ instance-type
    conflict with other constraints depending on the provider (since the instance
    type my determine things like memory size etc.)
 
+placement
+   Placement defines the policy used to choose machines for the units of a
+   service when no explicit placement directive is given:
+      spread - (default) place each unit on a clean machine, provisioning a
+               new machine if none is available
+      pack   - reuse machines that already host units of other services,
+               filling the busiest machines first, so that units occupy as
+               few machines as possible
+
+anti-affinity
+   Anti-affinity is a comma separated list of service names whose units must
+   never share a machine with units of the constrained service.  The rule
+   applies in both directions, so setting anti-affinity=cassandra on mysql
+   also keeps cassandra units off machines hosting mysql.  Explicit placement
+   directives (--to) that would break the rule are refused.
+
+   Example: juju service set-constraints mysql placement=pack anti-affinity=cassandra
+
 Example:
 
    juju add-machine --constraints "arch=amd64 mem=8G tags=foo,^bar"
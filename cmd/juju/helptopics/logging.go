@@ -115,6 +115,11 @@ Example:
 
   juju set-environment logging-config "juju=WARNING; unit=INFO"
 
+The logging config of a single machine or unit agent can also be
+changed for a limited time, without affecting the other agents:
+
+  juju logging-config set --unit mysql/0 --for 30m juju.worker.uniter=TRACE
+
 Valid logging levels:
   CRITICAL
   ERROR
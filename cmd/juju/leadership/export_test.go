@@ -0,0 +1,31 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leadership
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+func NewListCommandWithAPI(api LeasesAPI) cmd.Command {
+	c := &listCommand{newAPIFunc: func() (LeasesAPI, error) {
+		return api, nil
+	}}
+	return envcmd.Wrap(c)
+}
+
+func NewRevokeCommandWithAPI(api RevokeAPI) cmd.Command {
+	c := &revokeCommand{newAPIFunc: func() (RevokeAPI, error) {
+		return api, nil
+	}}
+	return envcmd.Wrap(c)
+}
+
+func NewHistoryCommandWithAPI(api HistoryAPI) cmd.Command {
+	c := &historyCommand{newAPIFunc: func() (HistoryAPI, error) {
+		return api, nil
+	}}
+	return envcmd.Wrap(c)
+}
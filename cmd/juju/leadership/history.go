@@ -0,0 +1,180 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leadership
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// HistoryAPI defines the API methods that the leadership history
+// command uses.
+type HistoryAPI interface {
+	Close() error
+	LeadershipHistory(serviceName string, size int) ([]params.LeadershipChange, error)
+}
+
+const historyCommandDoc = `
+Show the most recent leadership changes of a service, oldest first.
+Each change records the unit that took over leadership, the unit that
+was previously recorded as leader, and when the change happened.
+Frequent changes indicate that leadership of the service is flapping.
+
+Example:
+    Show the last 5 leadership changes of the mysql service:
+
+      juju leadership history mysql -n 5
+`
+
+func newHistoryCommand() cmd.Command {
+	cmd := &historyCommand{}
+	cmd.newAPIFunc = func() (HistoryAPI, error) {
+		return cmd.NewLeadershipAdminAPI()
+	}
+	return envcmd.Wrap(cmd)
+}
+
+// historyCommand shows the leadership changes of a service.
+type historyCommand struct {
+	LeadershipCommandBase
+	out         cmd.Output
+	serviceName string
+	size        int
+	newAPIFunc  func() (HistoryAPI, error)
+}
+
+// Info implements Command.Info.
+func (c *historyCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "history",
+		Args:    "<service>",
+		Purpose: "show leadership changes of a service",
+		Doc:     historyCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *historyCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.IntVar(&c.size, "n", 20, "size of the history to show")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatHistoryTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *historyCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no service specified")
+	case 1:
+		c.serviceName = args[0]
+	default:
+		return cmd.CheckEmpty(args[1:])
+	}
+	if !names.IsValidService(c.serviceName) {
+		return errors.NotValidf("service name %q", c.serviceName)
+	}
+	if c.size < 1 {
+		return errors.Errorf("invalid history size %d", c.size)
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *historyCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	changes, err := api.LeadershipHistory(c.serviceName, c.size)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(changes) == 0 {
+		ctx.Infof("no leadership changes recorded for service %s", c.serviceName)
+		return nil
+	}
+	output, err := formatHistory(changes)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return c.out.Write(ctx, output)
+}
+
+// LeadershipChangeInfo holds the details of a leadership change.
+type LeadershipChangeInfo struct {
+	Time     time.Time `yaml:"time" json:"time"`
+	Previous string    `yaml:"previous,omitempty" json:"previous,omitempty"`
+	Leader   string    `yaml:"leader" json:"leader"`
+}
+
+// formatHistory converts the API results into leadership change
+// details, preserving their order.
+func formatHistory(changes []params.LeadershipChange) ([]LeadershipChangeInfo, error) {
+	output := make([]LeadershipChangeInfo, len(changes))
+	for i, change := range changes {
+		leaderTag, err := names.ParseUnitTag(change.Leader)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		output[i] = LeadershipChangeInfo{
+			Time:   change.Time.UTC(),
+			Leader: leaderTag.Id(),
+		}
+		if change.Previous != "" {
+			previousTag, err := names.ParseUnitTag(change.Previous)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			output[i].Previous = previousTag.Id()
+		}
+	}
+	return output, nil
+}
+
+// formatHistoryTabular returns a tabular summary of leadership changes.
+func formatHistoryTabular(value interface{}) ([]byte, error) {
+	changes, ok := value.([]LeadershipChangeInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", changes, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	print := func(values ...string) {
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	print("TIME", "PREVIOUS", "LEADER")
+	for _, change := range changes {
+		previous := change.Previous
+		if previous == "" {
+			previous = "-"
+		}
+		print(change.Time.Format(time.RFC3339), previous, change.Leader)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leadership_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/leadership"
+	"github.com/juju/juju/testing"
+)
+
+type HistorySuite struct {
+	testing.FakeJujuHomeSuite
+	api *mockHistoryAPI
+}
+
+var _ = gc.Suite(&HistorySuite{})
+
+func (s *HistorySuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	t0 := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.api = &mockHistoryAPI{
+		changes: []params.LeadershipChange{{
+			ServiceTag: "service-mysql",
+			Leader:     "unit-mysql-0",
+			Time:       t0,
+		}, {
+			ServiceTag: "service-mysql",
+			Previous:   "unit-mysql-0",
+			Leader:     "unit-mysql-1",
+			Time:       t0.Add(time.Minute),
+		}},
+	}
+}
+
+func (s *HistorySuite) run(c *gc.C, args ...string) (string, error) {
+	ctx, err := testing.RunCommand(c, leadership.NewHistoryCommandWithAPI(s.api), args...)
+	if err != nil {
+		return "", err
+	}
+	return testing.Stdout(ctx), nil
+}
+
+func (s *HistorySuite) TestHistoryTabular(c *gc.C) {
+	out, err := s.run(c, "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"TIME                  PREVIOUS  LEADER\n"+
+		"2015-10-01T12:00:00Z  -         mysql/0\n"+
+		"2015-10-01T12:01:00Z  mysql/0   mysql/1\n",
+	)
+	c.Assert(s.api.serviceName, gc.Equals, "mysql")
+	c.Assert(s.api.size, gc.Equals, 20)
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *HistorySuite) TestHistoryYAML(c *gc.C) {
+	out, err := s.run(c, "mysql", "-n", "5", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"- time: 2015-10-01T12:00:00Z\n"+
+		"  leader: mysql/0\n"+
+		"- time: 2015-10-01T12:01:00Z\n"+
+		"  previous: mysql/0\n"+
+		"  leader: mysql/1\n",
+	)
+	c.Assert(s.api.size, gc.Equals, 5)
+}
+
+func (s *HistorySuite) TestHistoryNoChanges(c *gc.C) {
+	s.api.changes = nil
+	ctx, err := testing.RunCommand(c, leadership.NewHistoryCommandWithAPI(s.api), "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "no leadership changes recorded for service mysql\n")
+}
+
+func (s *HistorySuite) TestHistoryError(c *gc.C) {
+	s.api.err = errors.New("boom")
+	_, err := s.run(c, "mysql")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *HistorySuite) TestHistoryInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no service specified",
+	}, {
+		args: []string{"mysql/0"},
+		err:  `service name "mysql/0" not valid`,
+	}, {
+		args: []string{"mysql", "wordpress"},
+		err:  `unrecognized args: \["wordpress"\]`,
+	}, {
+		args: []string{"mysql", "-n", "0"},
+		err:  "invalid history size 0",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := s.run(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+type mockHistoryAPI struct {
+	changes     []params.LeadershipChange
+	serviceName string
+	size        int
+	err         error
+	closed      bool
+}
+
+func (m *mockHistoryAPI) Close() error {
+	m.closed = true
+	return nil
+}
+
+func (m *mockHistoryAPI) LeadershipHistory(serviceName string, size int) ([]params.LeadershipChange, error) {
+	m.serviceName = serviceName
+	m.size = size
+	return m.changes, m.err
+}
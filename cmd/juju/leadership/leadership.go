@@ -0,0 +1,54 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package leadership contains the commands used to inspect service
+// leadership, and to hand it over when a leader is stuck.
+package leadership
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/leadershipadmin"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const leadershipCmdDoc = `
+"juju leadership" is used to inspect which unit leads each service
+in the environment, to show how leadership has changed over time,
+and to force a stuck leader to hand over leadership to another unit.
+`
+
+const leadershipCmdPurpose = "inspect and manage service leadership"
+
+// NewSuperCommand creates the leadership supercommand and registers
+// the subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	leadershipcmd := cmd.NewSuperCommand(
+		cmd.SuperCommandParams{
+			Name:        "leadership",
+			Doc:         leadershipCmdDoc,
+			UsagePrefix: "juju",
+			Purpose:     leadershipCmdPurpose,
+		})
+	leadershipcmd.Register(newListCommand())
+	leadershipcmd.Register(newRevokeCommand())
+	leadershipcmd.Register(newHistoryCommand())
+	return leadershipcmd
+}
+
+// LeadershipCommandBase is a helper base structure that has a method
+// to get the leadership admin client.
+type LeadershipCommandBase struct {
+	envcmd.EnvCommandBase
+}
+
+// NewLeadershipAdminAPI returns a leadership admin api for the root
+// api endpoint that the environment command returns.
+func (c *LeadershipCommandBase) NewLeadershipAdminAPI() (*leadershipadmin.Client, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return leadershipadmin.NewClient(root), nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leadership
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// LeasesAPI defines the API methods that the leadership list command
+// uses.
+type LeasesAPI interface {
+	Close() error
+	Leases() ([]params.LeadershipLease, error)
+}
+
+const listCommandDoc = `
+List the unit that currently leads each service in the environment,
+along with the lease namespace in which leadership is recorded and the
+latest time at which the leader's current lease might expire. Leaders
+extend their leases regularly; a lease that stays close to expiry may
+indicate a stuck leader.
+
+Services without a leader are not shown.
+`
+
+func newListCommand() cmd.Command {
+	cmd := &listCommand{}
+	cmd.newAPIFunc = func() (LeasesAPI, error) {
+		return cmd.NewLeadershipAdminAPI()
+	}
+	return envcmd.Wrap(cmd)
+}
+
+// listCommand lists the leadership leases in the environment.
+type listCommand struct {
+	LeadershipCommandBase
+	out        cmd.Output
+	newAPIFunc func() (LeasesAPI, error)
+}
+
+// Info implements Command.Info.
+func (c *listCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list service leaders",
+		Doc:     listCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatLeasesTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *listCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *listCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	leases, err := api.Leases()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(leases) == 0 {
+		ctx.Infof("no service leaders to display")
+		return nil
+	}
+	output, err := formatLeases(leases)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return c.out.Write(ctx, output)
+}
+
+// LeaseInfo holds the details of a service's leadership.
+type LeaseInfo struct {
+	Leader    string    `yaml:"leader" json:"leader"`
+	Expiry    time.Time `yaml:"expiry" json:"expiry"`
+	Namespace string    `yaml:"namespace" json:"namespace"`
+}
+
+// formatLeases converts the API results into a mapping from service
+// name to leadership details.
+func formatLeases(leases []params.LeadershipLease) (map[string]LeaseInfo, error) {
+	output := make(map[string]LeaseInfo)
+	for _, lease := range leases {
+		serviceTag, err := names.ParseServiceTag(lease.ServiceTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		unitTag, err := names.ParseUnitTag(lease.UnitTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		output[serviceTag.Id()] = LeaseInfo{
+			Leader:    unitTag.Id(),
+			Expiry:    lease.Expiry.UTC(),
+			Namespace: lease.Namespace,
+		}
+	}
+	return output, nil
+}
+
+// formatLeasesTabular returns a tabular summary of leadership leases,
+// ordered by service name.
+func formatLeasesTabular(value interface{}) ([]byte, error) {
+	leases, ok := value.(map[string]LeaseInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", leases, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	print := func(values ...string) {
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	serviceNames := make([]string, 0, len(leases))
+	for serviceName := range leases {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+
+	print("SERVICE", "LEADER", "EXPIRY", "NAMESPACE")
+	for _, serviceName := range serviceNames {
+		lease := leases[serviceName]
+		print(serviceName, lease.Leader, lease.Expiry.Format(time.RFC3339), lease.Namespace)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
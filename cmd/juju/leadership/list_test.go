@@ -0,0 +1,109 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leadership_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/leadership"
+	"github.com/juju/juju/testing"
+)
+
+type ListSuite struct {
+	testing.FakeJujuHomeSuite
+	api *mockLeasesAPI
+}
+
+var _ = gc.Suite(&ListSuite{})
+
+func (s *ListSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	expiry := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.api = &mockLeasesAPI{
+		leases: []params.LeadershipLease{{
+			Namespace:  "service-leadership",
+			ServiceTag: "service-wordpress",
+			UnitTag:    "unit-wordpress-1",
+			Expiry:     expiry.Add(time.Minute),
+		}, {
+			Namespace:  "service-leadership",
+			ServiceTag: "service-mysql",
+			UnitTag:    "unit-mysql-0",
+			Expiry:     expiry,
+		}},
+	}
+}
+
+func (s *ListSuite) run(c *gc.C, args ...string) (string, error) {
+	ctx, err := testing.RunCommand(c, leadership.NewListCommandWithAPI(s.api), args...)
+	if err != nil {
+		return "", err
+	}
+	return testing.Stdout(ctx), nil
+}
+
+func (s *ListSuite) TestListTabular(c *gc.C) {
+	out, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"SERVICE    LEADER       EXPIRY                NAMESPACE\n"+
+		"mysql      mysql/0      2015-10-01T12:00:00Z  service-leadership\n"+
+		"wordpress  wordpress/1  2015-10-01T12:01:00Z  service-leadership\n",
+	)
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *ListSuite) TestListYAML(c *gc.C) {
+	out, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"mysql:\n"+
+		"  leader: mysql/0\n"+
+		"  expiry: 2015-10-01T12:00:00Z\n"+
+		"  namespace: service-leadership\n"+
+		"wordpress:\n"+
+		"  leader: wordpress/1\n"+
+		"  expiry: 2015-10-01T12:01:00Z\n"+
+		"  namespace: service-leadership\n",
+	)
+}
+
+func (s *ListSuite) TestListNoLeases(c *gc.C) {
+	s.api.leases = nil
+	ctx, err := testing.RunCommand(c, leadership.NewListCommandWithAPI(s.api))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "no service leaders to display\n")
+}
+
+func (s *ListSuite) TestListError(c *gc.C) {
+	s.api.err = errors.New("boom")
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ListSuite) TestListUnexpectedArgs(c *gc.C) {
+	_, err := s.run(c, "mysql")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["mysql"\]`)
+}
+
+type mockLeasesAPI struct {
+	leases []params.LeadershipLease
+	err    error
+	closed bool
+}
+
+func (m *mockLeasesAPI) Close() error {
+	m.closed = true
+	return nil
+}
+
+func (m *mockLeasesAPI) Leases() ([]params.LeadershipLease, error) {
+	return m.leases, m.err
+}
@@ -0,0 +1,108 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leadership
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+// RevokeAPI defines the API methods that the leadership revoke command
+// uses.
+type RevokeAPI interface {
+	Close() error
+	RevokeLeadership(serviceNames []string) ([]params.ErrorResult, error)
+}
+
+const revokeCommandDoc = `
+Revoke the leadership of one or more services, so that another unit
+of each service can take over without the current leader's agent being
+stopped. This is intended for use when a leader unit is stuck, and is
+only available to administrators.
+
+The service has no leader until the revoked lease would have expired,
+so that the revoked unit cannot still be acting as leader when another
+unit takes over; after that, any unit of the service may become leader.
+The revoked unit learns that it is no longer leader when it next tries
+to extend its lease, and runs its "leader-settings-changed" hook as
+usual.
+
+Example:
+    Hand over leadership of the mysql service to another unit:
+
+      juju leadership revoke mysql
+`
+
+func newRevokeCommand() cmd.Command {
+	cmd := &revokeCommand{}
+	cmd.newAPIFunc = func() (RevokeAPI, error) {
+		return cmd.NewLeadershipAdminAPI()
+	}
+	return envcmd.Wrap(cmd)
+}
+
+// revokeCommand revokes the leadership of services.
+type revokeCommand struct {
+	LeadershipCommandBase
+	serviceNames []string
+	newAPIFunc   func() (RevokeAPI, error)
+}
+
+// Info implements Command.Info.
+func (c *revokeCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "revoke",
+		Args:    "<service> [...]",
+		Purpose: "force services to hand over leadership",
+		Doc:     revokeCommandDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *revokeCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service specified")
+	}
+	for _, arg := range args {
+		if !names.IsValidService(arg) {
+			return errors.NotValidf("service name %q", arg)
+		}
+	}
+	c.serviceNames = args
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *revokeCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	results, err := api.RevokeLeadership(c.serviceNames)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	var failed bool
+	for i, result := range results {
+		if result.Error != nil {
+			fmt.Fprintf(ctx.Stderr, "service %s: %v\n", c.serviceNames[i], result.Error)
+			failed = true
+			continue
+		}
+		ctx.Infof("revoked leadership of service %s", c.serviceNames[i])
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leadership_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/leadership"
+	"github.com/juju/juju/testing"
+)
+
+type RevokeSuite struct {
+	testing.FakeJujuHomeSuite
+	api *mockRevokeAPI
+}
+
+var _ = gc.Suite(&RevokeSuite{})
+
+func (s *RevokeSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &mockRevokeAPI{}
+}
+
+func (s *RevokeSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "no service specified",
+	}, {
+		args: []string{"mysql/0"},
+		err:  `service name "mysql/0" not valid`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := testing.RunCommand(c, leadership.NewRevokeCommandWithAPI(s.api), test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *RevokeSuite) TestRevoke(c *gc.C) {
+	ctx, err := testing.RunCommand(c, leadership.NewRevokeCommandWithAPI(s.api), "mysql", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.revoked, jc.DeepEquals, []string{"mysql", "wordpress"})
+	c.Assert(testing.Stderr(ctx), gc.Equals, ""+
+		"revoked leadership of service mysql\n"+
+		"revoked leadership of service wordpress\n",
+	)
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *RevokeSuite) TestRevokeErrors(c *gc.C) {
+	s.api.errors = []*params.Error{nil, common.ServerError(common.ErrPerm)}
+	ctx, err := testing.RunCommand(c, leadership.NewRevokeCommandWithAPI(s.api), "mysql", "wordpress")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(testing.Stderr(ctx), gc.Equals, ""+
+		"revoked leadership of service mysql\n"+
+		"service wordpress: permission denied\n",
+	)
+}
+
+func (s *RevokeSuite) TestRevokeBlocked(c *gc.C) {
+	s.api.err = common.OperationBlockedError("TestRevokeBlocked")
+	_, err := testing.RunCommand(c, leadership.NewRevokeCommandWithAPI(s.api), "mysql")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Check(c.GetTestLog(), jc.Contains, "TestRevokeBlocked")
+}
+
+type mockRevokeAPI struct {
+	revoked []string
+	errors  []*params.Error
+	err     error
+	closed  bool
+}
+
+func (m *mockRevokeAPI) Close() error {
+	m.closed = true
+	return nil
+}
+
+func (m *mockRevokeAPI) RevokeLeadership(serviceNames []string) ([]params.ErrorResult, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.revoked = serviceNames
+	results := make([]params.ErrorResult, len(serviceNames))
+	for i := range results {
+		if i < len(m.errors) {
+			results[i].Error = m.errors[i]
+		}
+	}
+	return results, nil
+}
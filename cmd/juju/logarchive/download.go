@@ -0,0 +1,107 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logarchive
+
+import (
+	"io"
+	"os"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// DownloadAPI defines the API methods that the log-archives download
+// command uses.
+type DownloadAPI interface {
+	Close() error
+	DownloadLogArchive(id string) (io.ReadCloser, error)
+}
+
+const downloadCommandDoc = `
+Download a log archive to a local file. The archive is a gzip-compressed
+file holding one JSON-encoded log record per line.
+
+By default the archive is written to juju-logs-<id>.json.gz in the
+current directory; use --output to choose another file.
+
+Example:
+
+    juju log-archives download 5620b6d5bdbd3d1bbc000001
+`
+
+func newDownloadCommand() cmd.Command {
+	cmd := &downloadCommand{}
+	cmd.newAPIFunc = func() (DownloadAPI, error) {
+		return cmd.NewAPIClient()
+	}
+	return envcmd.Wrap(cmd)
+}
+
+// downloadCommand downloads a log archive.
+type downloadCommand struct {
+	envcmd.EnvCommandBase
+	id         string
+	filename   string
+	newAPIFunc func() (DownloadAPI, error)
+}
+
+// Info implements Command.Info.
+func (c *downloadCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "download",
+		Args:    "<id>",
+		Purpose: "download a log archive",
+		Doc:     downloadCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *downloadCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.filename, "o", "", "the file to write the archive to")
+	f.StringVar(&c.filename, "output", "", "")
+}
+
+// Init implements Command.Init.
+func (c *downloadCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no log archive id specified")
+	}
+	c.id, args = args[0], args[1:]
+	if c.filename == "" {
+		c.filename = "juju-logs-" + c.id + ".json.gz"
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *downloadCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	r, err := api.DownloadLogArchive(c.id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer r.Close()
+
+	f, err := os.Create(ctx.AbsPath(c.filename))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, r); err != nil {
+		return errors.Annotate(err, "cannot write log archive")
+	}
+	if err := f.Close(); err != nil {
+		return errors.Annotate(err, "cannot write log archive")
+	}
+	ctx.Infof("downloaded log archive to %s", c.filename)
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logarchive_test
+
+import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/logarchive"
+	"github.com/juju/juju/testing"
+)
+
+type DownloadSuite struct {
+	testing.FakeJujuHomeSuite
+	api *mockDownloadAPI
+}
+
+var _ = gc.Suite(&DownloadSuite{})
+
+func (s *DownloadSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &mockDownloadAPI{data: "archived logs"}
+}
+
+func (s *DownloadSuite) TestDownload(c *gc.C) {
+	ctx, err := testing.RunCommand(c, logarchive.NewDownloadCommandWithAPI(s.api), "abc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.id, gc.Equals, "abc")
+	c.Assert(s.api.closed, jc.IsTrue)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "downloaded log archive to juju-logs-abc.json.gz\n")
+	data, err := ioutil.ReadFile(ctx.AbsPath("juju-logs-abc.json.gz"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "archived logs")
+}
+
+func (s *DownloadSuite) TestDownloadOutput(c *gc.C) {
+	filename := filepath.Join(c.MkDir(), "logs.json.gz")
+	_, err := testing.RunCommand(c, logarchive.NewDownloadCommandWithAPI(s.api), "abc", "-o", filename)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadFile(filename)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "archived logs")
+}
+
+func (s *DownloadSuite) TestDownloadError(c *gc.C) {
+	s.api.err = errors.NotFoundf(`log archive "abc"`)
+	_, err := testing.RunCommand(c, logarchive.NewDownloadCommandWithAPI(s.api), "abc")
+	c.Assert(err, gc.ErrorMatches, `log archive "abc" not found`)
+}
+
+func (s *DownloadSuite) TestDownloadInit(c *gc.C) {
+	_, err := testing.RunCommand(c, logarchive.NewDownloadCommandWithAPI(s.api))
+	c.Assert(err, gc.ErrorMatches, "no log archive id specified")
+	_, err = testing.RunCommand(c, logarchive.NewDownloadCommandWithAPI(s.api), "abc", "def")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["def"\]`)
+}
+
+type mockDownloadAPI struct {
+	data   string
+	id     string
+	err    error
+	closed bool
+}
+
+func (m *mockDownloadAPI) Close() error {
+	m.closed = true
+	return nil
+}
+
+func (m *mockDownloadAPI) DownloadLogArchive(id string) (io.ReadCloser, error) {
+	m.id = id
+	if m.err != nil {
+		return nil, m.err
+	}
+	return ioutil.NopCloser(strings.NewReader(m.data)), nil
+}
@@ -0,0 +1,38 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logarchive
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+func NewListCommandWithAPI(api ListAPI) cmd.Command {
+	c := &listCommand{newAPIFunc: func() (ListAPI, error) {
+		return api, nil
+	}}
+	return envcmd.Wrap(c)
+}
+
+func NewDownloadCommandWithAPI(api DownloadAPI) cmd.Command {
+	c := &downloadCommand{newAPIFunc: func() (DownloadAPI, error) {
+		return api, nil
+	}}
+	return envcmd.Wrap(c)
+}
+
+func NewImportCommandWithAPI(api ImportAPI) cmd.Command {
+	c := &importCommand{newAPIFunc: func() (ImportAPI, error) {
+		return api, nil
+	}}
+	return envcmd.Wrap(c)
+}
+
+func NewRemoveCommandWithAPI(api RemoveAPI) cmd.Command {
+	c := &removeCommand{newAPIFunc: func() (RemoveAPI, error) {
+		return api, nil
+	}}
+	return envcmd.Wrap(c)
+}
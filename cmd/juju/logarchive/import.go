@@ -0,0 +1,90 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logarchive
+
+import (
+	"io"
+	"os"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// ImportAPI defines the API methods that the log-archives import
+// command uses.
+type ImportAPI interface {
+	Close() error
+	ImportLogArchive(r io.ReadSeeker) (int, error)
+}
+
+const importCommandDoc = `
+Import a log archive, as written by "juju log-archives download", back
+into the environment's logs so that its records can be viewed with
+"juju debug-log", which marks them as imported. Imported records are
+kept for a day regardless of their age, and are then pruned as usual,
+but are not archived again. Only administrators of the environment may
+import archives.
+
+Example:
+
+    juju log-archives import juju-logs-5620b6d5bdbd3d1bbc000001.json.gz
+`
+
+func newImportCommand() cmd.Command {
+	cmd := &importCommand{}
+	cmd.newAPIFunc = func() (ImportAPI, error) {
+		return cmd.NewAPIClient()
+	}
+	return envcmd.Wrap(cmd)
+}
+
+// importCommand imports a log archive.
+type importCommand struct {
+	envcmd.EnvCommandBase
+	filename   string
+	newAPIFunc func() (ImportAPI, error)
+}
+
+// Info implements Command.Info.
+func (c *importCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "import",
+		Args:    "<file>",
+		Purpose: "import a log archive",
+		Doc:     importCommandDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *importCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no log archive file specified")
+	}
+	c.filename, args = args[0], args[1:]
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *importCommand) Run(ctx *cmd.Context) error {
+	f, err := os.Open(ctx.AbsPath(c.filename))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer f.Close()
+
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	count, err := api.ImportLogArchive(f)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("imported %d log records", count)
+	return nil
+}
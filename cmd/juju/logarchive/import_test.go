@@ -0,0 +1,80 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logarchive_test
+
+import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/logarchive"
+	"github.com/juju/juju/testing"
+)
+
+type ImportSuite struct {
+	testing.FakeJujuHomeSuite
+	api      *mockImportAPI
+	filename string
+}
+
+var _ = gc.Suite(&ImportSuite{})
+
+func (s *ImportSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &mockImportAPI{count: 42}
+	s.filename = filepath.Join(c.MkDir(), "logs.json.gz")
+	err := ioutil.WriteFile(s.filename, []byte("archived logs"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ImportSuite) TestImport(c *gc.C) {
+	ctx, err := testing.RunCommand(c, logarchive.NewImportCommandWithAPI(s.api), s.filename)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.data, gc.Equals, "archived logs")
+	c.Assert(s.api.closed, jc.IsTrue)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "imported 42 log records\n")
+}
+
+func (s *ImportSuite) TestImportMissingFile(c *gc.C) {
+	_, err := testing.RunCommand(c, logarchive.NewImportCommandWithAPI(s.api), "missing.json.gz")
+	c.Assert(err, gc.ErrorMatches, "open .*missing.json.gz: no such file or directory")
+}
+
+func (s *ImportSuite) TestImportError(c *gc.C) {
+	s.api.err = errors.New("invalid log archive: boom")
+	_, err := testing.RunCommand(c, logarchive.NewImportCommandWithAPI(s.api), s.filename)
+	c.Assert(err, gc.ErrorMatches, "invalid log archive: boom")
+}
+
+func (s *ImportSuite) TestImportInit(c *gc.C) {
+	_, err := testing.RunCommand(c, logarchive.NewImportCommandWithAPI(s.api))
+	c.Assert(err, gc.ErrorMatches, "no log archive file specified")
+	_, err = testing.RunCommand(c, logarchive.NewImportCommandWithAPI(s.api), "a", "b")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["b"\]`)
+}
+
+type mockImportAPI struct {
+	count  int
+	data   string
+	err    error
+	closed bool
+}
+
+func (m *mockImportAPI) Close() error {
+	m.closed = true
+	return nil
+}
+
+func (m *mockImportAPI) ImportLogArchive(r io.ReadSeeker) (int, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	m.data = string(data)
+	return m.count, m.err
+}
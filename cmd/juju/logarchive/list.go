@@ -0,0 +1,181 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logarchive
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/common"
+)
+
+// ListAPI defines the API methods that the log-archives list command
+// uses.
+type ListAPI interface {
+	Close() error
+	LogArchives(from, to *time.Time) ([]params.LogArchive, error)
+}
+
+const listCommandDoc = `
+List the log archives of the environment, oldest first, showing the
+time range of the records in each archive.
+
+The --from and --to options limit the archives listed to those holding
+records from that time range. Each takes an RFC3339 timestamp or a
+duration such as 2h, meaning that long ago.
+
+Examples:
+
+    juju log-archives list
+    juju log-archives list --from 48h --to 24h
+`
+
+func newListCommand() cmd.Command {
+	cmd := &listCommand{}
+	cmd.newAPIFunc = func() (ListAPI, error) {
+		return cmd.NewAPIClient()
+	}
+	return envcmd.Wrap(cmd)
+}
+
+// listCommand lists the log archives of the environment.
+type listCommand struct {
+	envcmd.EnvCommandBase
+	out        cmd.Output
+	from       string
+	to         string
+	fromTime   *time.Time
+	toTime     *time.Time
+	newAPIFunc func() (ListAPI, error)
+}
+
+// Info implements Command.Info.
+func (c *listCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list log archives",
+		Doc:     listCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.from, "from", "", "only list archives holding records from after this time")
+	f.StringVar(&c.to, "to", "", "only list archives holding records from before this time")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatArchivesTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *listCommand) Init(args []string) error {
+	now := time.Now()
+	var err error
+	if c.fromTime, err = parseArchiveTime(c.from, now); err != nil {
+		return errors.Trace(err)
+	}
+	if c.toTime, err = parseArchiveTime(c.to, now); err != nil {
+		return errors.Trace(err)
+	}
+	if c.fromTime != nil && c.toTime != nil && c.toTime.Before(*c.fromTime) {
+		return errors.Errorf("--to must not be before --from")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// parseArchiveTime parses a --from or --to value, returning nil if
+// none was given.
+func parseArchiveTime(value string, now time.Time) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := common.ParseTime(value, now)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &t, nil
+}
+
+// Run implements Command.Run.
+func (c *listCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	archives, err := api.LogArchives(c.fromTime, c.toTime)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(archives) == 0 {
+		ctx.Infof("no log archives to display")
+		return nil
+	}
+	output := make([]ArchiveInfo, len(archives))
+	for i, archive := range archives {
+		output[i] = ArchiveInfo{
+			Id:    archive.Id,
+			Start: archive.Start.UTC(),
+			End:   archive.End.UTC(),
+			Count: archive.Count,
+			Size:  archive.Size,
+		}
+	}
+	return c.out.Write(ctx, output)
+}
+
+// ArchiveInfo holds the details of a log archive.
+type ArchiveInfo struct {
+	Id    string    `yaml:"id" json:"id"`
+	Start time.Time `yaml:"start" json:"start"`
+	End   time.Time `yaml:"end" json:"end"`
+	Count int       `yaml:"records" json:"records"`
+	Size  int64     `yaml:"size" json:"size"`
+}
+
+// formatArchivesTabular returns a tabular summary of log archives.
+func formatArchivesTabular(value interface{}) ([]byte, error) {
+	archives, ok := value.([]ArchiveInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", archives, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	print := func(values ...string) {
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	print("ID", "START", "END", "RECORDS", "SIZE")
+	for _, archive := range archives {
+		print(
+			archive.Id,
+			archive.Start.Format(time.RFC3339),
+			archive.End.Format(time.RFC3339),
+			fmt.Sprint(archive.Count),
+			humanize.IBytes(uint64(archive.Size)),
+		)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logarchive_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/logarchive"
+	"github.com/juju/juju/testing"
+)
+
+type ListSuite struct {
+	testing.FakeJujuHomeSuite
+	api *mockListAPI
+}
+
+var _ = gc.Suite(&ListSuite{})
+
+func (s *ListSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	t0 := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.api = &mockListAPI{
+		archives: []params.LogArchive{{
+			Id:    "5620b6d5bdbd3d1bbc000001",
+			Start: t0,
+			End:   t0.Add(time.Hour),
+			Count: 1200,
+			Size:  20480,
+		}},
+	}
+}
+
+func (s *ListSuite) run(c *gc.C, args ...string) (string, error) {
+	ctx, err := testing.RunCommand(c, logarchive.NewListCommandWithAPI(s.api), args...)
+	if err != nil {
+		return "", err
+	}
+	return testing.Stdout(ctx), nil
+}
+
+func (s *ListSuite) TestListTabular(c *gc.C) {
+	out, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"ID                        START                 END                   RECORDS  SIZE\n"+
+		"5620b6d5bdbd3d1bbc000001  2015-10-01T12:00:00Z  2015-10-01T13:00:00Z  1200     20 KiB\n",
+	)
+	c.Assert(s.api.from, gc.IsNil)
+	c.Assert(s.api.to, gc.IsNil)
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *ListSuite) TestListYAML(c *gc.C) {
+	out, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"- id: 5620b6d5bdbd3d1bbc000001\n"+
+		"  start: 2015-10-01T12:00:00Z\n"+
+		"  end: 2015-10-01T13:00:00Z\n"+
+		"  records: 1200\n"+
+		"  size: 20480\n",
+	)
+}
+
+func (s *ListSuite) TestListTimeRange(c *gc.C) {
+	_, err := s.run(c, "--from", "2015-10-01T00:00:00Z", "--to", "1h")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.from, gc.NotNil)
+	c.Assert(s.api.from.Equal(time.Date(2015, 10, 1, 0, 0, 0, 0, time.UTC)), jc.IsTrue)
+	c.Assert(s.api.to, gc.NotNil)
+	c.Assert(s.api.to.Before(time.Now().Add(-59*time.Minute)), jc.IsTrue)
+}
+
+func (s *ListSuite) TestListNoArchives(c *gc.C) {
+	s.api.archives = nil
+	ctx, err := testing.RunCommand(c, logarchive.NewListCommandWithAPI(s.api))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "no log archives to display\n")
+}
+
+func (s *ListSuite) TestListError(c *gc.C) {
+	s.api.err = errors.New("boom")
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ListSuite) TestListInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"foo"},
+		err:  `unrecognized args: \["foo"\]`,
+	}, {
+		args: []string{"--from", "yesterday"},
+		err:  `invalid time "yesterday": .*`,
+	}, {
+		args: []string{"--from", "1h", "--to", "2h"},
+		err:  "--to must not be before --from",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := s.run(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+type mockListAPI struct {
+	archives []params.LogArchive
+	from, to *time.Time
+	err      error
+	closed   bool
+}
+
+func (m *mockListAPI) Close() error {
+	m.closed = true
+	return nil
+}
+
+func (m *mockListAPI) LogArchives(from, to *time.Time) ([]params.LogArchive, error) {
+	m.from, m.to = from, to
+	return m.archives, m.err
+}
@@ -0,0 +1,41 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package logarchive contains the commands used to list, download and
+// re-import archives of logs pruned from the database.
+package logarchive
+
+import (
+	"github.com/juju/cmd"
+)
+
+const logArchiveCmdDoc = `
+"juju log-archives" is used to work with the logs that were archived
+before being pruned from the database. Logs are only archived when the
+environment's log-archive setting is true.
+
+Archives are gzip-compressed files holding one JSON-encoded log record
+per line, kept in the environment's storage until the environment's
+log-archive-retention has passed. They can be downloaded for inspection,
+removed, and imported back into the environment's logs so that they can
+be viewed with debug-log.
+`
+
+const logArchiveCmdPurpose = "list, download, remove and import archived logs"
+
+// NewSuperCommand creates the log-archives supercommand and registers
+// the subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	logarchivecmd := cmd.NewSuperCommand(
+		cmd.SuperCommandParams{
+			Name:        "log-archives",
+			Doc:         logArchiveCmdDoc,
+			UsagePrefix: "juju",
+			Purpose:     logArchiveCmdPurpose,
+		})
+	logarchivecmd.Register(newListCommand())
+	logarchivecmd.Register(newDownloadCommand())
+	logarchivecmd.Register(newRemoveCommand())
+	logarchivecmd.Register(newImportCommand())
+	return logarchivecmd
+}
@@ -0,0 +1,80 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logarchive
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// RemoveAPI defines the API methods that the log-archives remove
+// command uses.
+type RemoveAPI interface {
+	Close() error
+	RemoveLogArchive(id string) error
+}
+
+const removeCommandDoc = `
+Remove one or more log archives, as listed by "juju log-archives list".
+Archives are otherwise removed once their newest record is older than
+the environment's log-archive-retention setting. Only administrators of
+the environment may remove archives.
+
+Example:
+
+    juju log-archives remove 5620b6d5bdbd3d1bbc000001
+`
+
+func newRemoveCommand() cmd.Command {
+	cmd := &removeCommand{}
+	cmd.newAPIFunc = func() (RemoveAPI, error) {
+		return cmd.NewAPIClient()
+	}
+	return envcmd.Wrap(cmd)
+}
+
+// removeCommand removes log archives.
+type removeCommand struct {
+	envcmd.EnvCommandBase
+	ids        []string
+	newAPIFunc func() (RemoveAPI, error)
+}
+
+// Info implements Command.Info.
+func (c *removeCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove",
+		Args:    "<id> ...",
+		Purpose: "remove log archives",
+		Doc:     removeCommandDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *removeCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no log archive id specified")
+	}
+	c.ids = args
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *removeCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	for _, id := range c.ids {
+		if err := api.RemoveLogArchive(id); err != nil {
+			return errors.Annotatef(err, "cannot remove log archive %q", id)
+		}
+		ctx.Infof("removed log archive %s", id)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logarchive_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/logarchive"
+	"github.com/juju/juju/testing"
+)
+
+type RemoveSuite struct {
+	testing.FakeJujuHomeSuite
+	api *mockRemoveAPI
+}
+
+var _ = gc.Suite(&RemoveSuite{})
+
+func (s *RemoveSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &mockRemoveAPI{}
+}
+
+func (s *RemoveSuite) TestRemove(c *gc.C) {
+	ctx, err := testing.RunCommand(c, logarchive.NewRemoveCommandWithAPI(s.api), "a1", "b2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.removed, jc.DeepEquals, []string{"a1", "b2"})
+	c.Assert(s.api.closed, jc.IsTrue)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "removed log archive a1\nremoved log archive b2\n")
+}
+
+func (s *RemoveSuite) TestRemoveError(c *gc.C) {
+	s.api.err = errors.NotFoundf(`log archive "a1"`)
+	_, err := testing.RunCommand(c, logarchive.NewRemoveCommandWithAPI(s.api), "a1", "b2")
+	c.Assert(err, gc.ErrorMatches, `cannot remove log archive "a1": log archive "a1" not found`)
+	c.Assert(s.api.removed, gc.HasLen, 0)
+}
+
+func (s *RemoveSuite) TestRemoveInit(c *gc.C) {
+	_, err := testing.RunCommand(c, logarchive.NewRemoveCommandWithAPI(s.api))
+	c.Assert(err, gc.ErrorMatches, "no log archive id specified")
+}
+
+type mockRemoveAPI struct {
+	removed []string
+	err     error
+	closed  bool
+}
+
+func (m *mockRemoveAPI) Close() error {
+	m.closed = true
+	return nil
+}
+
+func (m *mockRemoveAPI) RemoveLogArchive(id string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.removed = append(m.removed, id)
+	return nil
+}
@@ -0,0 +1,31 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package loggingconfig
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+func NewSetCommandWithAPI(api SetAPI) cmd.Command {
+	c := &setCommand{newAPIFunc: func() (SetAPI, error) {
+		return api, nil
+	}}
+	return envcmd.Wrap(c)
+}
+
+func NewRemoveCommandWithAPI(api RemoveAPI) cmd.Command {
+	c := &removeCommand{newAPIFunc: func() (RemoveAPI, error) {
+		return api, nil
+	}}
+	return envcmd.Wrap(c)
+}
+
+func NewListCommandWithAPI(api ListAPI) cmd.Command {
+	c := &listCommand{newAPIFunc: func() (ListAPI, error) {
+		return api, nil
+	}}
+	return envcmd.Wrap(c)
+}
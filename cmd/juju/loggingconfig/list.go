@@ -0,0 +1,136 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package loggingconfig
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// ListAPI defines the API methods that the logging-config list command
+// uses.
+type ListAPI interface {
+	Close() error
+	LoggingOverrides() ([]params.LoggingOverride, error)
+}
+
+const listCommandDoc = `
+List the logging config set for individual agents that has not yet
+expired.
+`
+
+func newListCommand() cmd.Command {
+	cmd := &listCommand{}
+	cmd.newAPIFunc = func() (ListAPI, error) {
+		return cmd.NewLoggerAPI()
+	}
+	return envcmd.Wrap(cmd)
+}
+
+// listCommand lists the logging overrides of agents.
+type listCommand struct {
+	LoggingConfigCommandBase
+	out        cmd.Output
+	newAPIFunc func() (ListAPI, error)
+}
+
+// Info implements Command.Info.
+func (c *listCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list the logging config set for agents",
+		Doc:     listCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatOverridesTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *listCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *listCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	overrides, err := api.LoggingOverrides()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(overrides) == 0 {
+		ctx.Infof("no agent logging config set")
+		return nil
+	}
+	output := make([]OverrideInfo, len(overrides))
+	for i, override := range overrides {
+		tag, err := names.ParseTag(override.Tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		output[i] = OverrideInfo{
+			Agent:   agentName(tag),
+			Config:  override.Config,
+			Expires: override.Expires.UTC(),
+		}
+	}
+	return c.out.Write(ctx, output)
+}
+
+// OverrideInfo holds the logging config set for an agent.
+type OverrideInfo struct {
+	Agent   string    `yaml:"agent" json:"agent"`
+	Config  string    `yaml:"config" json:"config"`
+	Expires time.Time `yaml:"expires" json:"expires"`
+}
+
+// formatOverridesTabular returns a tabular summary of logging
+// overrides.
+func formatOverridesTabular(value interface{}) ([]byte, error) {
+	overrides, ok := value.([]OverrideInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", overrides, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	print := func(values ...string) {
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	print("AGENT", "EXPIRES", "CONFIG")
+	for _, override := range overrides {
+		print(override.Agent, override.Expires.Format(time.RFC3339), override.Config)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package loggingconfig_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/loggingconfig"
+	"github.com/juju/juju/testing"
+)
+
+type ListSuite struct {
+	testing.FakeJujuHomeSuite
+	api *mockListAPI
+}
+
+var _ = gc.Suite(&ListSuite{})
+
+func (s *ListSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	expires := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.api = &mockListAPI{
+		overrides: []params.LoggingOverride{{
+			Tag:     "machine-3",
+			Config:  "juju=DEBUG",
+			Expires: expires,
+		}, {
+			Tag:     "unit-mysql-0",
+			Config:  "juju.worker.uniter=TRACE",
+			Expires: expires.Add(30 * time.Minute),
+		}},
+	}
+}
+
+func (s *ListSuite) run(c *gc.C, args ...string) (string, error) {
+	ctx, err := testing.RunCommand(c, loggingconfig.NewListCommandWithAPI(s.api), args...)
+	if err != nil {
+		return "", err
+	}
+	return testing.Stdout(ctx), nil
+}
+
+func (s *ListSuite) TestListTabular(c *gc.C) {
+	out, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"AGENT         EXPIRES               CONFIG\n"+
+		"machine 3     2015-10-01T12:00:00Z  juju=DEBUG\n"+
+		"unit mysql/0  2015-10-01T12:30:00Z  juju.worker.uniter=TRACE\n",
+	)
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *ListSuite) TestListYAML(c *gc.C) {
+	out, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"- agent: machine 3\n"+
+		"  config: juju=DEBUG\n"+
+		"  expires: 2015-10-01T12:00:00Z\n"+
+		"- agent: unit mysql/0\n"+
+		"  config: juju.worker.uniter=TRACE\n"+
+		"  expires: 2015-10-01T12:30:00Z\n",
+	)
+}
+
+func (s *ListSuite) TestListNone(c *gc.C) {
+	s.api.overrides = nil
+	ctx, err := testing.RunCommand(c, loggingconfig.NewListCommandWithAPI(s.api))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "no agent logging config set\n")
+}
+
+func (s *ListSuite) TestListError(c *gc.C) {
+	s.api.err = errors.New("boom")
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type mockListAPI struct {
+	overrides []params.LoggingOverride
+	err       error
+	closed    bool
+}
+
+func (m *mockListAPI) Close() error {
+	m.closed = true
+	return nil
+}
+
+func (m *mockListAPI) LoggingOverrides() ([]params.LoggingOverride, error) {
+	return m.overrides, m.err
+}
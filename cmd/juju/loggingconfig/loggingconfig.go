@@ -0,0 +1,97 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package loggingconfig contains the commands used to change the
+// logging config of individual agents for a limited time.
+package loggingconfig
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/logger"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const loggingConfigCmdDoc = `
+"juju logging-config" is used to change the logging config of a single
+machine or unit agent, without affecting the rest of the environment.
+
+The environment's logging-config setting applies to every agent. An
+override set with "juju logging-config set" is applied on top of it,
+for one agent, until the override expires or is removed; the agent
+then returns to the environment's logging config.
+`
+
+const loggingConfigCmdPurpose = "change the logging config of individual agents"
+
+// NewSuperCommand creates the logging-config supercommand and registers
+// the subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	loggingconfigcmd := cmd.NewSuperCommand(
+		cmd.SuperCommandParams{
+			Name:        "logging-config",
+			Doc:         loggingConfigCmdDoc,
+			UsagePrefix: "juju",
+			Purpose:     loggingConfigCmdPurpose,
+		})
+	loggingconfigcmd.Register(newSetCommand())
+	loggingconfigcmd.Register(newRemoveCommand())
+	loggingconfigcmd.Register(newListCommand())
+	return loggingconfigcmd
+}
+
+// LoggingConfigCommandBase is a helper base structure that has a
+// method to get the logger client.
+type LoggingConfigCommandBase struct {
+	envcmd.EnvCommandBase
+}
+
+// NewLoggerAPI returns a logger client for the root api endpoint that
+// the environment command returns.
+func (c *LoggingConfigCommandBase) NewLoggerAPI() (*logger.Client, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return logger.NewClient(root), nil
+}
+
+// agentFlags holds the flags used to choose the agent whose logging
+// config is changed.
+type agentFlags struct {
+	machine string
+	unit    string
+}
+
+func (f *agentFlags) setFlags(fs *gnuflag.FlagSet) {
+	fs.StringVar(&f.machine, "machine", "", "the machine whose agent is affected")
+	fs.StringVar(&f.unit, "unit", "", "the unit whose agent is affected")
+}
+
+// tag returns the tag of the agent chosen by the flags.
+func (f *agentFlags) tag() (names.Tag, error) {
+	switch {
+	case f.machine != "" && f.unit != "":
+		return nil, errors.New("only one of --machine and --unit may be specified")
+	case f.machine != "":
+		if !names.IsValidMachine(f.machine) {
+			return nil, errors.NotValidf("machine id %q", f.machine)
+		}
+		return names.NewMachineTag(f.machine), nil
+	case f.unit != "":
+		if !names.IsValidUnit(f.unit) {
+			return nil, errors.NotValidf("unit name %q", f.unit)
+		}
+		return names.NewUnitTag(f.unit), nil
+	}
+	return nil, errors.New("one of --machine or --unit must be specified")
+}
+
+// agentName returns a description of the agent with the given tag,
+// such as "unit mysql/0".
+func agentName(tag names.Tag) string {
+	return tag.Kind() + " " + tag.Id()
+}
@@ -0,0 +1,86 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package loggingconfig
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+// RemoveAPI defines the API methods that the logging-config remove
+// command uses.
+type RemoveAPI interface {
+	Close() error
+	RemoveLoggingOverride(tag names.Tag) error
+}
+
+const removeCommandDoc = `
+Remove the logging config set for a single machine or unit agent before
+it expires, returning the agent to the environment's logging config.
+Only environment administrators may remove logging config, and not
+while operations that remove are blocked.
+
+Example:
+
+    juju logging-config remove --unit mysql/0
+`
+
+func newRemoveCommand() cmd.Command {
+	cmd := &removeCommand{}
+	cmd.newAPIFunc = func() (RemoveAPI, error) {
+		return cmd.NewLoggerAPI()
+	}
+	return envcmd.Wrap(cmd)
+}
+
+// removeCommand removes the logging override of an agent.
+type removeCommand struct {
+	LoggingConfigCommandBase
+	agent      agentFlags
+	tag        names.Tag
+	newAPIFunc func() (RemoveAPI, error)
+}
+
+// Info implements Command.Info.
+func (c *removeCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove",
+		Purpose: "remove the logging config set for an agent",
+		Doc:     removeCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *removeCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.agent.setFlags(f)
+}
+
+// Init implements Command.Init.
+func (c *removeCommand) Init(args []string) error {
+	var err error
+	if c.tag, err = c.agent.tag(); err != nil {
+		return errors.Trace(err)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *removeCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	if err := api.RemoveLoggingOverride(c.tag); err != nil {
+		return block.ProcessBlockedError(err, block.BlockRemove)
+	}
+	ctx.Infof("logging config of %s removed", agentName(c.tag))
+	return nil
+}
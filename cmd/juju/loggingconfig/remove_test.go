@@ -0,0 +1,75 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package loggingconfig_test
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/cmd/juju/loggingconfig"
+	"github.com/juju/juju/testing"
+)
+
+type RemoveSuite struct {
+	testing.FakeJujuHomeSuite
+	api *mockRemoveAPI
+}
+
+var _ = gc.Suite(&RemoveSuite{})
+
+func (s *RemoveSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &mockRemoveAPI{}
+}
+
+func (s *RemoveSuite) TestRemove(c *gc.C) {
+	ctx, err := testing.RunCommand(c, loggingconfig.NewRemoveCommandWithAPI(s.api), "--unit", "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "logging config of unit mysql/0 removed\n")
+	c.Assert(s.api.tag, gc.Equals, names.NewUnitTag("mysql/0"))
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *RemoveSuite) TestRemoveError(c *gc.C) {
+	s.api.err = errors.NotFoundf(`logging override for "machine-0"`)
+	_, err := testing.RunCommand(c, loggingconfig.NewRemoveCommandWithAPI(s.api), "--machine", "0")
+	c.Assert(err, gc.ErrorMatches, `logging override for "machine-0" not found`)
+}
+
+func (s *RemoveSuite) TestRemoveBlocked(c *gc.C) {
+	s.api.err = common.OperationBlockedError("TestRemoveBlocked")
+	_, err := testing.RunCommand(c, loggingconfig.NewRemoveCommandWithAPI(s.api), "--machine", "0")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Check(stripped, gc.Matches, ".*TestRemoveBlocked.*")
+}
+
+func (s *RemoveSuite) TestRemoveInit(c *gc.C) {
+	_, err := testing.RunCommand(c, loggingconfig.NewRemoveCommandWithAPI(s.api))
+	c.Assert(err, gc.ErrorMatches, "one of --machine or --unit must be specified")
+	_, err = testing.RunCommand(c, loggingconfig.NewRemoveCommandWithAPI(s.api), "--machine", "0", "foo")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["foo"\]`)
+}
+
+type mockRemoveAPI struct {
+	tag    names.Tag
+	err    error
+	closed bool
+}
+
+func (m *mockRemoveAPI) Close() error {
+	m.closed = true
+	return nil
+}
+
+func (m *mockRemoveAPI) RemoveLoggingOverride(tag names.Tag) error {
+	m.tag = tag
+	return m.err
+}
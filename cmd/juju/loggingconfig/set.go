@@ -0,0 +1,120 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package loggingconfig
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+// SetAPI defines the API methods that the logging-config set command
+// uses.
+type SetAPI interface {
+	Close() error
+	SetLoggingOverride(tag names.Tag, config string, expires time.Time) error
+}
+
+// defaultOverrideDuration is how long a logging override lasts if no
+// --for value is given.
+const defaultOverrideDuration = time.Hour
+
+const setCommandDoc = `
+Set the logging config of a single machine or unit agent, on top of the
+environment's logging-config, for a limited time. Any override already
+set for the agent is replaced.
+
+The logging config is given as one or more <module>=<level> entries,
+in the same form as the environment's logging-config setting. The
+override expires after the time given with --for, which defaults to
+one hour; the agent then returns to the environment's logging config.
+Only environment administrators may set logging config, and not while
+changes are blocked.
+
+Examples:
+    Trace the uniter of mysql/0 for the next 30 minutes:
+
+      juju logging-config set --unit mysql/0 --for 30m juju.worker.uniter=TRACE
+
+    Debug everything on machine 3 for the next hour:
+
+      juju logging-config set --machine 3 juju=DEBUG
+`
+
+func newSetCommand() cmd.Command {
+	cmd := &setCommand{}
+	cmd.newAPIFunc = func() (SetAPI, error) {
+		return cmd.NewLoggerAPI()
+	}
+	return envcmd.Wrap(cmd)
+}
+
+// setCommand sets the logging override of an agent.
+type setCommand struct {
+	LoggingConfigCommandBase
+	agent      agentFlags
+	duration   time.Duration
+	tag        names.Tag
+	config     string
+	newAPIFunc func() (SetAPI, error)
+}
+
+// Info implements Command.Info.
+func (c *setCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set",
+		Args:    "<module>=<level> [...]",
+		Purpose: "set the logging config of an agent for a limited time",
+		Doc:     setCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *setCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.agent.setFlags(f)
+	f.DurationVar(&c.duration, "for", defaultOverrideDuration, "how long the logging config applies for")
+}
+
+// Init implements Command.Init.
+func (c *setCommand) Init(args []string) error {
+	var err error
+	if c.tag, err = c.agent.tag(); err != nil {
+		return errors.Trace(err)
+	}
+	if c.duration <= 0 {
+		return errors.Errorf("invalid duration %v: expected a positive duration such as 30m", c.duration)
+	}
+	if len(args) == 0 {
+		return errors.New("no logging config specified")
+	}
+	c.config = strings.Join(args, ";")
+	if _, err := loggo.ParseConfigurationString(c.config); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *setCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	expires := time.Now().Add(c.duration)
+	if err := api.SetLoggingOverride(c.tag, c.config, expires); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("logging config of %s set until %s", agentName(c.tag), expires.Format(time.RFC3339))
+	return nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package loggingconfig_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/cmd/juju/loggingconfig"
+	"github.com/juju/juju/testing"
+)
+
+type SetSuite struct {
+	testing.FakeJujuHomeSuite
+	api *mockSetAPI
+}
+
+var _ = gc.Suite(&SetSuite{})
+
+func (s *SetSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &mockSetAPI{}
+}
+
+func (s *SetSuite) run(c *gc.C, args ...string) (string, error) {
+	ctx, err := testing.RunCommand(c, loggingconfig.NewSetCommandWithAPI(s.api), args...)
+	if err != nil {
+		return "", err
+	}
+	return testing.Stderr(ctx), nil
+}
+
+func (s *SetSuite) TestSetUnit(c *gc.C) {
+	before := time.Now()
+	out, err := s.run(c, "--unit", "mysql/0", "--for", "30m", "juju.worker.uniter=TRACE")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Matches, "logging config of unit mysql/0 set until .*\n")
+	c.Assert(s.api.tag, gc.Equals, names.NewUnitTag("mysql/0"))
+	c.Assert(s.api.config, gc.Equals, "juju.worker.uniter=TRACE")
+	c.Assert(s.api.expires.Before(before.Add(30*time.Minute)), jc.IsFalse)
+	c.Assert(s.api.expires.After(time.Now().Add(30*time.Minute)), jc.IsFalse)
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *SetSuite) TestSetMachineDefaultDuration(c *gc.C) {
+	before := time.Now()
+	_, err := s.run(c, "--machine", "3", "juju=DEBUG", "juju.provisioner=TRACE")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.tag, gc.Equals, names.NewMachineTag("3"))
+	c.Assert(s.api.config, gc.Equals, "juju=DEBUG;juju.provisioner=TRACE")
+	c.Assert(s.api.expires.Before(before.Add(time.Hour)), jc.IsFalse)
+}
+
+func (s *SetSuite) TestSetError(c *gc.C) {
+	s.api.err = errors.NotFoundf("unit mysql/0")
+	_, err := s.run(c, "--unit", "mysql/0", "juju=DEBUG")
+	c.Assert(err, gc.ErrorMatches, "unit mysql/0 not found")
+}
+
+func (s *SetSuite) TestSetBlocked(c *gc.C) {
+	s.api.err = common.OperationBlockedError("TestSetBlocked")
+	_, err := s.run(c, "--unit", "mysql/0", "juju=DEBUG")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Check(stripped, gc.Matches, ".*TestSetBlocked.*")
+}
+
+func (s *SetSuite) TestSetInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"juju=DEBUG"},
+		err:  "one of --machine or --unit must be specified",
+	}, {
+		args: []string{"--machine", "0", "--unit", "mysql/0", "juju=DEBUG"},
+		err:  "only one of --machine and --unit may be specified",
+	}, {
+		args: []string{"--unit", "mysql", "juju=DEBUG"},
+		err:  `unit name "mysql" not valid`,
+	}, {
+		args: []string{"--machine", "a", "juju=DEBUG"},
+		err:  `machine id "a" not valid`,
+	}, {
+		args: []string{"--unit", "mysql/0"},
+		err:  "no logging config specified",
+	}, {
+		args: []string{"--unit", "mysql/0", "--for", "0s", "juju=DEBUG"},
+		err:  "invalid duration 0s: expected a positive duration such as 30m",
+	}, {
+		args: []string{"--unit", "mysql/0", "juju=LOUD"},
+		err:  `.*LOUD.*`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := s.run(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+type mockSetAPI struct {
+	tag     names.Tag
+	config  string
+	expires time.Time
+	err     error
+	closed  bool
+}
+
+func (m *mockSetAPI) Close() error {
+	m.closed = true
+	return nil
+}
+
+func (m *mockSetAPI) SetLoggingOverride(tag names.Tag, config string, expires time.Time) error {
+	m.tag, m.config, m.expires = tag, config, expires
+	return m.err
+}
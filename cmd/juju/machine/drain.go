@@ -0,0 +1,241 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+// DrainMachineAPI defines the methods on the machine manager API that
+// the drain, undrain and show-drain commands call.
+type DrainMachineAPI interface {
+	DrainMachines(machines ...string) ([]params.ErrorResult, error)
+	UndrainMachines(machines ...string) ([]params.ErrorResult, error)
+	MachineDrains(machines ...string) ([]params.MachineDrainResult, error)
+	Close() error
+}
+
+// drainCommandBase is the common base for commands that operate on
+// machine drains.
+type drainCommandBase struct {
+	envcmd.EnvCommandBase
+	api        DrainMachineAPI
+	MachineIds []string
+}
+
+func (c *drainCommandBase) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no machines specified")
+	}
+	for _, id := range args {
+		if !names.IsValidMachine(id) {
+			return fmt.Errorf("invalid machine id %q", id)
+		}
+	}
+	c.MachineIds = args
+	return nil
+}
+
+func (c *drainCommandBase) getAPI() (DrainMachineAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+// reportErrors writes any per-machine errors to stderr, returning
+// cmd.ErrSilent if there were any.
+func (c *drainCommandBase) reportErrors(ctx *cmd.Context, results []params.ErrorResult) error {
+	var failed bool
+	for i, result := range results {
+		if result.Error != nil {
+			fmt.Fprintf(ctx.Stderr, "machine %s: %v\n", c.MachineIds[i], result.Error)
+			failed = true
+		}
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
+
+func newDrainCommand() cmd.Command {
+	return envcmd.Wrap(&drainCommand{})
+}
+
+// drainCommand evacuates the units from machines.
+type drainCommand struct {
+	drainCommandBase
+}
+
+const drainMachineDoc = `
+Draining a machine prepares it for maintenance. The machine is marked as
+unschedulable, so that no new units will be placed on it, and each unit
+on the machine (or in its containers) is replaced by a new unit of the same
+service on another machine. Once every replacement unit has settled (its
+hooks have run and its workload reports no error, blocked, maintenance or
+waiting status), the original units are removed.
+
+The drain proceeds in the background; use "juju machine show-drain" to
+follow its progress. The machine remains unschedulable after the drain
+completes, until "juju machine undrain" is run.
+
+Examples:
+	# Evacuate the units from machine 5
+	$ juju machine drain 5
+
+	# Check on the progress of the drain
+	$ juju machine show-drain 5
+`
+
+func (c *drainCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "drain",
+		Args:    "<machine> ...",
+		Purpose: "evacuate units from machines before maintenance",
+		Doc:     drainMachineDoc,
+	}
+}
+
+func (c *drainCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	results, err := client.DrainMachines(c.MachineIds...)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockRemove)
+	}
+	return c.reportErrors(ctx, results)
+}
+
+func newUndrainCommand() cmd.Command {
+	return envcmd.Wrap(&undrainCommand{})
+}
+
+// undrainCommand allows machines to accept new units again.
+type undrainCommand struct {
+	drainCommandBase
+}
+
+const undrainMachineDoc = `
+Undraining a machine allows new units to be placed on it again. If a drain
+of the machine is still in progress it is abandoned: replacement units that
+have already been added are kept, and original units that have not yet been
+removed stay on the machine.
+`
+
+func (c *undrainCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "undrain",
+		Args:    "<machine> ...",
+		Purpose: "allow drained machines to accept units again",
+		Doc:     undrainMachineDoc,
+	}
+}
+
+func (c *undrainCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	results, err := client.UndrainMachines(c.MachineIds...)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	return c.reportErrors(ctx, results)
+}
+
+func newShowDrainCommand() cmd.Command {
+	return envcmd.Wrap(&showDrainCommand{})
+}
+
+// showDrainCommand reports the progress of machine drains.
+type showDrainCommand struct {
+	drainCommandBase
+	out cmd.Output
+}
+
+const showDrainDoc = `
+Show the progress of the most recent drain of each of the given machines,
+including the units that have been added to replace those on the machine.
+`
+
+func (c *showDrainCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show-drain",
+		Args:    "<machine> ...",
+		Purpose: "show the progress of machine drains",
+		Doc:     showDrainDoc,
+	}
+}
+
+func (c *showDrainCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+// DrainInfo holds the progress of a machine drain, formatted for output.
+type DrainInfo struct {
+	Status       string            `yaml:"status" json:"status"`
+	Message      string            `yaml:"message,omitempty" json:"message,omitempty"`
+	Started      string            `yaml:"started" json:"started"`
+	Updated      string            `yaml:"updated" json:"updated"`
+	Replacements map[string]string `yaml:"replacements,omitempty" json:"replacements,omitempty"`
+}
+
+func (c *showDrainCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	results, err := client.MachineDrains(c.MachineIds...)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	output := make(map[string]DrainInfo)
+	errorResults := make([]params.ErrorResult, len(results))
+	for i, result := range results {
+		if result.Error != nil {
+			errorResults[i].Error = result.Error
+			continue
+		}
+		info := DrainInfo{
+			Status:  result.Result.Status,
+			Message: result.Result.Message,
+			Started: result.Result.Started.Format(time.RFC3339),
+			Updated: result.Result.Updated.Format(time.RFC3339),
+		}
+		for _, r := range result.Result.Replacements {
+			if info.Replacements == nil {
+				info.Replacements = make(map[string]string)
+			}
+			info.Replacements[r.Original] = r.Replacement
+		}
+		output[c.MachineIds[i]] = info
+	}
+	if len(output) > 0 {
+		if err := c.out.Write(ctx, output); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return c.reportErrors(ctx, errorResults)
+}
@@ -0,0 +1,158 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/testing"
+)
+
+type DrainMachineSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeDrainMachineAPI
+}
+
+var _ = gc.Suite(&DrainMachineSuite{})
+
+func (s *DrainMachineSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeDrainMachineAPI{}
+}
+
+func (s *DrainMachineSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		errorString string
+	}{
+		{
+			errorString: "no machines specified",
+		}, {
+			args: []string{"1", "2/lxc/0"},
+		}, {
+			args:        []string{"lxc"},
+			errorString: `invalid machine id "lxc"`,
+		},
+	} {
+		c.Logf("test %d", i)
+		for _, command := range []cmd.Command{
+			machine.NewDrainCommand(s.fake),
+			machine.NewUndrainCommand(s.fake),
+			machine.NewShowDrainCommand(s.fake),
+		} {
+			err := testing.InitCommand(command, test.args)
+			if test.errorString == "" {
+				c.Check(err, jc.ErrorIsNil)
+			} else {
+				c.Check(err, gc.ErrorMatches, test.errorString)
+			}
+		}
+	}
+}
+
+func (s *DrainMachineSuite) TestDrain(c *gc.C) {
+	_, err := testing.RunCommand(c, machine.NewDrainCommand(s.fake), "1", "2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.calls, jc.DeepEquals, []string{"DrainMachines"})
+	c.Assert(s.fake.machines, jc.DeepEquals, []string{"1", "2"})
+}
+
+func (s *DrainMachineSuite) TestDrainMachineError(c *gc.C) {
+	s.fake.results = []params.ErrorResult{
+		{}, {Error: &params.Error{Message: "machine 2 not found"}},
+	}
+	ctx, err := testing.RunCommand(c, machine.NewDrainCommand(s.fake), "1", "2")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "machine 2: machine 2 not found\n")
+}
+
+func (s *DrainMachineSuite) TestDrainBlocked(c *gc.C) {
+	s.fake.err = common.OperationBlockedError("TestDrainBlocked")
+	_, err := testing.RunCommand(c, machine.NewDrainCommand(s.fake), "1")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Assert(stripped, gc.Matches, ".*TestDrainBlocked.*")
+}
+
+func (s *DrainMachineSuite) TestUndrain(c *gc.C) {
+	_, err := testing.RunCommand(c, machine.NewUndrainCommand(s.fake), "3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.calls, jc.DeepEquals, []string{"UndrainMachines"})
+	c.Assert(s.fake.machines, jc.DeepEquals, []string{"3"})
+}
+
+func (s *DrainMachineSuite) TestShowDrain(c *gc.C) {
+	started := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.fake.drains = []params.MachineDrainResult{{
+		Result: &params.MachineDrain{
+			MachineId: "1",
+			Status:    "replacing",
+			Replacements: []params.UnitReplacement{
+				{Original: "mysql/0", Replacement: "mysql/3"},
+			},
+			Started: started,
+			Updated: started.Add(time.Minute),
+		},
+	}, {
+		Error: &params.Error{Message: `drain for machine "2" not found`},
+	}}
+	ctx, err := testing.RunCommand(c, machine.NewShowDrainCommand(s.fake), "1", "2")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(s.fake.calls, jc.DeepEquals, []string{"MachineDrains"})
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+"1":
+  status: replacing
+  started: 2015-10-01T12:00:00Z
+  updated: 2015-10-01T12:01:00Z
+  replacements:
+    mysql/0: mysql/3
+`[1:])
+	c.Assert(testing.Stderr(ctx), gc.Equals, "machine 2: drain for machine \"2\" not found\n")
+}
+
+type fakeDrainMachineAPI struct {
+	calls    []string
+	machines []string
+	results  []params.ErrorResult
+	drains   []params.MachineDrainResult
+	err      error
+}
+
+func (f *fakeDrainMachineAPI) Close() error {
+	return nil
+}
+
+func (f *fakeDrainMachineAPI) errorResults(method string, machines []string) ([]params.ErrorResult, error) {
+	f.calls = append(f.calls, method)
+	f.machines = machines
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.results != nil {
+		return f.results, nil
+	}
+	return make([]params.ErrorResult, len(machines)), nil
+}
+
+func (f *fakeDrainMachineAPI) DrainMachines(machines ...string) ([]params.ErrorResult, error) {
+	return f.errorResults("DrainMachines", machines)
+}
+
+func (f *fakeDrainMachineAPI) UndrainMachines(machines ...string) ([]params.ErrorResult, error) {
+	return f.errorResults("UndrainMachines", machines)
+}
+
+func (f *fakeDrainMachineAPI) MachineDrains(machines ...string) ([]params.MachineDrainResult, error) {
+	f.calls = append(f.calls, "MachineDrains")
+	f.machines = machines
+	return f.drains, f.err
+}
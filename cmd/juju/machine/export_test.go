@@ -39,6 +39,21 @@ func NewRemoveCommand(api RemoveMachineAPI) (cmd.Command, *RemoveCommand) {
 	return envcmd.Wrap(cmd), &RemoveCommand{cmd}
 }
 
+// NewDrainCommand returns a drain command with the api provided as specified.
+func NewDrainCommand(api DrainMachineAPI) cmd.Command {
+	return envcmd.Wrap(&drainCommand{drainCommandBase{api: api}})
+}
+
+// NewUndrainCommand returns an undrain command with the api provided as specified.
+func NewUndrainCommand(api DrainMachineAPI) cmd.Command {
+	return envcmd.Wrap(&undrainCommand{drainCommandBase{api: api}})
+}
+
+// NewShowDrainCommand returns a show-drain command with the api provided as specified.
+func NewShowDrainCommand(api DrainMachineAPI) cmd.Command {
+	return envcmd.Wrap(&showDrainCommand{drainCommandBase: drainCommandBase{api: api}})
+}
+
 func NewDisksFlag(disks *[]storage.Constraints) *disksFlag {
 	return &disksFlag{disks}
 }
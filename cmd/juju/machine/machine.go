@@ -11,7 +11,7 @@ import (
 var logger = loggo.GetLogger("juju.cmd.juju.machine")
 
 const machineCommandDoc = `
-"juju machine" provides commands to add and remove machines in the Juju environment.
+"juju machine" provides commands to add, remove and drain machines in the Juju environment.
 `
 
 const machineCommandPurpose = "manage machines"
@@ -27,5 +27,8 @@ func NewSuperCommand() cmd.Command {
 	})
 	machineCmd.Register(newAddCommand())
 	machineCmd.Register(newRemoveCommand())
+	machineCmd.Register(newDrainCommand())
+	machineCmd.Register(newUndrainCommand())
+	machineCmd.Register(newShowDrainCommand())
 	return machineCmd
 }
@@ -21,8 +21,11 @@ var _ = gc.Suite(&MachineCommandSuite{})
 
 var expectedCommmandNames = []string{
 	"add",
+	"drain",
 	"help",
 	"remove",
+	"show-drain",
+	"undrain",
 }
 
 func (s *MachineCommandSuite) TestHelp(c *gc.C) {
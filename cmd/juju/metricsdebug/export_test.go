@@ -0,0 +1,17 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsdebug
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+func NewMetricsCommandWithAPI(api MetricsAPI) cmd.Command {
+	c := &metricsCommand{newAPIFunc: func() (MetricsAPI, error) {
+		return api, nil
+	}}
+	return envcmd.Wrap(c)
+}
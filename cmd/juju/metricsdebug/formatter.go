@@ -0,0 +1,51 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsdebug
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// formatMetricsTabular returns a tabular summary of metric values
+// or aggregates.
+func formatMetricsTabular(value interface{}) ([]byte, error) {
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	print := func(values ...string) {
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+
+	switch value := value.(type) {
+	case []MetricValue:
+		print("UNIT", "TIMESTAMP", "METRIC", "VALUE")
+		for _, v := range value {
+			print(v.Unit, v.Timestamp.Format(time.RFC3339), v.Metric, v.Value)
+		}
+	case []MetricAggregate:
+		print("METRIC", "AGGREGATE", "VALUE", "COUNT")
+		for _, v := range value {
+			print(v.Metric, v.Aggregate, v.Value, strconv.Itoa(v.Count))
+		}
+	default:
+		return nil, errors.Errorf("unexpected value of type %T", value)
+	}
+	tw.Flush()
+
+	return out.Bytes(), nil
+}
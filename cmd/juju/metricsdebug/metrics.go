@@ -0,0 +1,246 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package metricsdebug contains the command used to inspect the
+// metrics recorded by units.
+package metricsdebug
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/metricsdebug"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const metricsDoc = `
+Display the metrics recorded by a unit, or by all units of a service.
+
+Only metrics still held by the state server are shown; metrics that
+have been sent to the metrics collector are removed after a day.
+
+The --since option restricts the output to metrics recorded within
+the given duration, e.g. 30m or 2h.
+
+The --aggregate option combines the values recorded for each metric
+key across all matching units:
+    sum:  the sum of all values
+    avg:  the mean of all values
+    last: the most recently recorded value
+
+Examples:
+    juju metrics mysql
+    juju metrics mysql/0 --since 1h
+    juju metrics mysql --aggregate avg
+`
+
+// Aggregation functions supported by the metrics command.
+const (
+	aggregateSum  = "sum"
+	aggregateAvg  = "avg"
+	aggregateLast = "last"
+)
+
+// MetricsAPI defines the API methods used by the metrics command.
+type MetricsAPI interface {
+	GetMetrics(tag names.Tag, since time.Time) ([]params.MetricResult, error)
+	Close() error
+}
+
+// NewMetricsCommand returns a command that displays the metrics
+// recorded by a unit or service.
+func NewMetricsCommand() cmd.Command {
+	c := &metricsCommand{}
+	c.newAPIFunc = func() (MetricsAPI, error) {
+		root, err := c.NewAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return metricsdebug.NewClient(root), nil
+	}
+	return envcmd.Wrap(c)
+}
+
+type metricsCommand struct {
+	envcmd.EnvCommandBase
+	out        cmd.Output
+	newAPIFunc func() (MetricsAPI, error)
+
+	tag       names.Tag
+	since     time.Duration
+	aggregate string
+}
+
+// Info implements Command.Info.
+func (c *metricsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "metrics",
+		Args:    "<service|unit>",
+		Purpose: "display metrics recorded by units",
+		Doc:     metricsDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *metricsCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.DurationVar(&c.since, "since", 0, "only show metrics recorded within this duration")
+	f.StringVar(&c.aggregate, "aggregate", "", "aggregate values per metric key [sum|avg|last]")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatMetricsTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *metricsCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service or unit specified")
+	}
+	entity, args := args[0], args[1:]
+	switch {
+	case names.IsValidUnit(entity):
+		c.tag = names.NewUnitTag(entity)
+	case names.IsValidService(entity):
+		c.tag = names.NewServiceTag(entity)
+	default:
+		return errors.Errorf("%q is not a valid service or unit name", entity)
+	}
+	if c.since < 0 {
+		return errors.Errorf("invalid --since duration %v", c.since)
+	}
+	switch c.aggregate {
+	case "", aggregateSum, aggregateAvg, aggregateLast:
+	default:
+		return errors.Errorf("unknown aggregation %q, expected sum, avg or last", c.aggregate)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *metricsCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	var since time.Time
+	if c.since > 0 {
+		since = time.Now().Add(-c.since)
+	}
+	metrics, err := api.GetMetrics(c.tag, since)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(metrics) == 0 {
+		ctx.Infof("no metrics to display")
+		return nil
+	}
+	if c.aggregate == "" {
+		return c.out.Write(ctx, metricValues(metrics))
+	}
+	aggregates, err := aggregateMetrics(metrics, c.aggregate)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return c.out.Write(ctx, aggregates)
+}
+
+// MetricValue holds a single metric value recorded by a unit.
+type MetricValue struct {
+	Unit      string    `yaml:"unit" json:"unit"`
+	Timestamp time.Time `yaml:"timestamp" json:"timestamp"`
+	Metric    string    `yaml:"metric" json:"metric"`
+	Value     string    `yaml:"value" json:"value"`
+}
+
+// MetricAggregate holds the aggregated values recorded for a
+// single metric key.
+type MetricAggregate struct {
+	Metric    string `yaml:"metric" json:"metric"`
+	Aggregate string `yaml:"aggregate" json:"aggregate"`
+	Value     string `yaml:"value" json:"value"`
+	Count     int    `yaml:"count" json:"count"`
+}
+
+// metricValues converts the API results into values for display,
+// ordered by time and then by unit.
+func metricValues(metrics []params.MetricResult) []MetricValue {
+	values := make([]MetricValue, len(metrics))
+	for i, m := range metrics {
+		values[i] = MetricValue{
+			Unit:      m.Unit,
+			Timestamp: m.Time.UTC(),
+			Metric:    m.Key,
+			Value:     m.Value,
+		}
+	}
+	sort.Sort(byTimeAndUnit(values))
+	return values
+}
+
+type byTimeAndUnit []MetricValue
+
+func (v byTimeAndUnit) Len() int      { return len(v) }
+func (v byTimeAndUnit) Swap(i, j int) { v[i], v[j] = v[j], v[i] }
+func (v byTimeAndUnit) Less(i, j int) bool {
+	if !v[i].Timestamp.Equal(v[j].Timestamp) {
+		return v[i].Timestamp.Before(v[j].Timestamp)
+	}
+	if v[i].Unit != v[j].Unit {
+		return v[i].Unit < v[j].Unit
+	}
+	return v[i].Metric < v[j].Metric
+}
+
+// aggregateMetrics combines the values of the given metrics per key
+// using the named aggregation function. The results are ordered by key.
+func aggregateMetrics(metrics []params.MetricResult, aggregate string) ([]MetricAggregate, error) {
+	byKey := make(map[string][]MetricValue)
+	for _, v := range metricValues(metrics) {
+		byKey[v.Metric] = append(byKey[v.Metric], v)
+	}
+	keys := make([]string, 0, len(byKey))
+	for key := range byKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	results := make([]MetricAggregate, len(keys))
+	for i, key := range keys {
+		values := byKey[key]
+		result := MetricAggregate{
+			Metric:    key,
+			Aggregate: aggregate,
+			Count:     len(values),
+		}
+		if aggregate == aggregateLast {
+			// Values are ordered by time, so the last is the most recent.
+			result.Value = values[len(values)-1].Value
+			results[i] = result
+			continue
+		}
+		var sum float64
+		for _, v := range values {
+			f, err := strconv.ParseFloat(v.Value, 64)
+			if err != nil {
+				return nil, errors.Errorf("cannot aggregate metric %q: value %q is not a number", key, v.Value)
+			}
+			sum += f
+		}
+		if aggregate == aggregateAvg {
+			sum /= float64(len(values))
+		}
+		result.Value = strconv.FormatFloat(sum, 'f', -1, 64)
+		results[i] = result
+	}
+	return results, nil
+}
@@ -0,0 +1,177 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsdebug_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/metricsdebug"
+	"github.com/juju/juju/testing"
+)
+
+type MetricsSuite struct {
+	testing.FakeJujuHomeSuite
+	api *mockMetricsAPI
+}
+
+var _ = gc.Suite(&MetricsSuite{})
+
+func (s *MetricsSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	t0 := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Minute)
+	s.api = &mockMetricsAPI{
+		metrics: []params.MetricResult{
+			{Key: "pings", Value: "5", Time: t1, Unit: "metered/1"},
+			{Key: "pings", Value: "2", Time: t0, Unit: "metered/0"},
+			{Key: "juju-units", Value: "1", Time: t0, Unit: "metered/0"},
+			{Key: "pings", Value: "3.5", Time: t1, Unit: "metered/0"},
+		},
+	}
+}
+
+func (s *MetricsSuite) run(c *gc.C, args ...string) (string, error) {
+	ctx, err := testing.RunCommand(c, metricsdebug.NewMetricsCommandWithAPI(s.api), args...)
+	if err != nil {
+		return "", err
+	}
+	return testing.Stdout(ctx), nil
+}
+
+func (s *MetricsSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "no service or unit specified",
+	}, {
+		args: []string{"metered/x"},
+		err:  `"metered/x" is not a valid service or unit name`,
+	}, {
+		args: []string{"metered", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"metered", "--aggregate", "max"},
+		err:  `unknown aggregation "max", expected sum, avg or last`,
+	}, {
+		args: []string{"metered", "--since", "-1h"},
+		err:  `invalid --since duration -1h0m0s`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := s.run(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *MetricsSuite) TestMetricsService(c *gc.C) {
+	out, err := s.run(c, "metered")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `
+UNIT       TIMESTAMP             METRIC      VALUE
+metered/0  2015-10-01T12:00:00Z  juju-units  1
+metered/0  2015-10-01T12:00:00Z  pings       2
+metered/0  2015-10-01T12:01:00Z  pings       3.5
+metered/1  2015-10-01T12:01:00Z  pings       5
+`[1:])
+	c.Assert(s.api.tag, gc.Equals, names.NewServiceTag("metered"))
+	c.Assert(s.api.since.IsZero(), jc.IsTrue)
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *MetricsSuite) TestMetricsUnitSince(c *gc.C) {
+	before := time.Now()
+	_, err := s.run(c, "metered/0", "--since", "1h")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.tag, gc.Equals, names.NewUnitTag("metered/0"))
+	c.Assert(s.api.since.Before(before.Add(-time.Hour)), jc.IsFalse)
+	c.Assert(s.api.since.After(time.Now().Add(-time.Hour)), jc.IsFalse)
+}
+
+func (s *MetricsSuite) TestMetricsAggregate(c *gc.C) {
+	for _, test := range []struct {
+		aggregate string
+		expected  string
+	}{{
+		aggregate: "sum",
+		expected: `
+METRIC      AGGREGATE  VALUE  COUNT
+juju-units  sum        1      1
+pings       sum        10.5   3
+`[1:],
+	}, {
+		aggregate: "avg",
+		expected: `
+METRIC      AGGREGATE  VALUE  COUNT
+juju-units  avg        1      1
+pings       avg        3.5    3
+`[1:],
+	}, {
+		aggregate: "last",
+		expected: `
+METRIC      AGGREGATE  VALUE  COUNT
+juju-units  last       1      1
+pings       last       5      3
+`[1:],
+	}} {
+		c.Logf("aggregate %s", test.aggregate)
+		out, err := s.run(c, "metered", "--aggregate", test.aggregate)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(out, gc.Equals, test.expected)
+	}
+}
+
+func (s *MetricsSuite) TestMetricsAggregateNotNumeric(c *gc.C) {
+	s.api.metrics = append(s.api.metrics, params.MetricResult{
+		Key: "pings", Value: "many", Time: time.Now(), Unit: "metered/0",
+	})
+	_, err := s.run(c, "metered", "--aggregate", "sum")
+	c.Assert(err, gc.ErrorMatches, `cannot aggregate metric "pings": value "many" is not a number`)
+}
+
+func (s *MetricsSuite) TestMetricsYAML(c *gc.C) {
+	out, err := s.run(c, "metered", "--aggregate", "avg", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `
+- metric: juju-units
+  aggregate: avg
+  value: "1"
+  count: 1
+- metric: pings
+  aggregate: avg
+  value: "3.5"
+  count: 3
+`[1:])
+}
+
+func (s *MetricsSuite) TestNoMetrics(c *gc.C) {
+	s.api.metrics = nil
+	ctx, err := testing.RunCommand(c, metricsdebug.NewMetricsCommandWithAPI(s.api), "metered")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "no metrics to display\n")
+}
+
+type mockMetricsAPI struct {
+	metrics []params.MetricResult
+	tag     names.Tag
+	since   time.Time
+	closed  bool
+}
+
+func (m *mockMetricsAPI) GetMetrics(tag names.Tag, since time.Time) ([]params.MetricResult, error) {
+	m.tag = tag
+	m.since = since
+	return m.metrics, nil
+}
+
+func (m *mockMetricsAPI) Close() error {
+	m.closed = true
+	return nil
+}
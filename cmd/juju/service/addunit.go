@@ -111,6 +111,10 @@ type addUnitCommand struct {
 	UnitCommandBase
 	ServiceName string
 	api         ServiceAddUnitAPI
+
+	// AttachStorage holds the IDs of detached storage instances
+	// to attach to the new unit.
+	AttachStorage []string
 }
 
 const addUnitDoc = `
@@ -122,11 +126,19 @@ By default, services are deployed to newly provisioned machines.  Alternatively,
 service units can be added to a specific existing machine using the --to
 argument.
 
+Storage that was detached from another unit of the service with
+"juju storage detach" may be attached to a new unit using the
+--attach-storage argument, in place of creating new storage for the unit.
+The storage's data is preserved. Only one unit may be added when attaching
+storage.
+
 Examples:
  juju service add-unit mysql -n 5          (Add 5 mysql units on 5 new machines)
  juju service add-unit mysql --to 23       (Add a mysql unit to machine 23)
  juju service add-unit mysql --to 24/lxc/3 (Add unit to lxc container 3 on host machine 24)
  juju service add-unit mysql --to lxc:25   (Add unit to a new lxc container on host machine 25)
+ juju service add-unit mysql --attach-storage data/0
+                                           (Add a mysql unit with detached storage data/0)
 `
 
 func (c *addUnitCommand) Info() *cmd.Info {
@@ -141,6 +153,7 @@ func (c *addUnitCommand) Info() *cmd.Info {
 func (c *addUnitCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.UnitCommandBase.SetFlags(f)
 	f.IntVar(&c.NumUnits, "n", 1, "number of service units to add")
+	f.Var(cmd.NewStringsValue(nil, &c.AttachStorage), "attach-storage", "comma-separated IDs of detached storage to attach to the new unit")
 }
 
 func (c *addUnitCommand) Init(args []string) error {
@@ -153,6 +166,16 @@ func (c *addUnitCommand) Init(args []string) error {
 	if err := cmd.CheckEmpty(args[1:]); err != nil {
 		return err
 	}
+	if len(c.AttachStorage) > 0 {
+		if c.NumUnits != 1 {
+			return errors.New("--attach-storage cannot be used with -n or --num-units")
+		}
+		for _, id := range c.AttachStorage {
+			if !names.IsValidStorage(id) {
+				return errors.NotValidf("storage ID %q", id)
+			}
+		}
+	}
 	return c.UnitCommandBase.Init(args)
 }
 
@@ -163,6 +186,7 @@ type ServiceAddUnitAPI interface {
 	EnvironmentUUID() string
 	AddServiceUnits(service string, numUnits int, machineSpec string) ([]string, error)
 	AddServiceUnitsWithPlacement(service string, numUnits int, placement []*instance.Placement) ([]string, error)
+	AddServiceUnitsAttachingStorage(service string, placement []*instance.Placement, storage []string) ([]string, error)
 	EnvironmentGet() (map[string]interface{}, error)
 }
 
@@ -191,12 +215,30 @@ func (c *addUnitCommand) Run(_ *cmd.Context) error {
 		return err
 	}
 
+	if len(c.AttachStorage) > 0 && len(c.Placement) == 0 && c.PlacementSpec != "" {
+		placement, err := parsePlacement(c.PlacementSpec)
+		if err != nil {
+			return err
+		}
+		c.Placement = []*instance.Placement{placement}
+	}
 	for i, p := range c.Placement {
 		if p.Scope == "env-uuid" {
 			p.Scope = apiclient.EnvironmentUUID()
 		}
 		c.Placement[i] = p
 	}
+	if len(c.AttachStorage) > 0 {
+		storageTags := make([]string, len(c.AttachStorage))
+		for i, id := range c.AttachStorage {
+			storageTags[i] = names.NewStorageTag(id).String()
+		}
+		_, err = apiclient.AddServiceUnitsAttachingStorage(c.ServiceName, c.Placement, storageTags)
+		if errors.IsNotImplemented(err) {
+			return errors.New("cannot attach storage: not supported by the API server")
+		}
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
 	if len(c.Placement) > 0 {
 		_, err = apiclient.AddServiceUnitsWithPlacement(c.ServiceName, c.NumUnits, c.Placement)
 		if err == nil {
@@ -29,6 +29,7 @@ type fakeServiceAddUnitAPI struct {
 	numUnits    int
 	machineSpec string
 	placement   []*instance.Placement
+	storage     []string
 	err         error
 	newAPI      bool
 }
@@ -70,6 +71,20 @@ func (f *fakeServiceAddUnitAPI) AddServiceUnitsWithPlacement(service string, num
 	return nil, nil
 }
 
+func (f *fakeServiceAddUnitAPI) AddServiceUnitsAttachingStorage(service string, placement []*instance.Placement, storage []string) ([]string, error) {
+	if !f.newAPI {
+		return nil, errors.NotImplementedf("AddServiceUnitsAttachingStorage")
+	}
+	if service != f.service {
+		return nil, errors.NotFoundf("service %q", service)
+	}
+
+	f.numUnits++
+	f.placement = placement
+	f.storage = storage
+	return nil, nil
+}
+
 func (f *fakeServiceAddUnitAPI) EnvironmentGet() (map[string]interface{}, error) {
 	cfg, err := config.New(config.UseDefaults, map[string]interface{}{
 		"type": f.envType,
@@ -102,6 +117,12 @@ var initAddUnitErrorTests = []struct {
 	}, {
 		args: []string{"some-service-name", "--to", "1,#:foo"},
 		err:  `invalid --to parameter "#:foo"`,
+	}, {
+		args: []string{"some-service-name", "-n", "2", "--attach-storage", "data/0"},
+		err:  `--attach-storage cannot be used with -n or --num-units`,
+	}, {
+		args: []string{"some-service-name", "--attach-storage", "data"},
+		err:  `storage ID "data" not valid`,
 	},
 }
 
@@ -163,6 +184,25 @@ func (s *AddUnitSuite) TestAddUnitWithPlacement(c *gc.C) {
 	})
 }
 
+func (s *AddUnitSuite) TestAddUnitAttachingStorage(c *gc.C) {
+	s.fake.newAPI = true
+	err := s.runAddUnit(c, "some-service-name", "--attach-storage", "data/0,logs/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.numUnits, gc.Equals, 2)
+	c.Assert(s.fake.storage, jc.DeepEquals, []string{"storage-data-0", "storage-logs-1"})
+	c.Assert(s.fake.placement, gc.HasLen, 0)
+
+	err = s.runAddUnit(c, "some-service-name", "--attach-storage", "data/0", "--to", "lxc:1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.numUnits, gc.Equals, 3)
+	c.Assert(s.fake.placement, jc.DeepEquals, []*instance.Placement{{"lxc", "1"}})
+}
+
+func (s *AddUnitSuite) TestAddUnitAttachingStorageOlderServer(c *gc.C) {
+	err := s.runAddUnit(c, "some-service-name", "--attach-storage", "data/0")
+	c.Assert(err, gc.ErrorMatches, "cannot attach storage: not supported by the API server")
+}
+
 func (s *AddUnitSuite) TestBlockAddUnit(c *gc.C) {
 	// Block operation
 	s.fake.err = common.OperationBlockedError("TestBlockAddUnit")
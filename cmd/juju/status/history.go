@@ -7,9 +7,13 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
@@ -19,7 +23,8 @@ import (
 )
 
 // NewStatusHistoryCommand returns a command that reports the history
-// of status changes for the specified unit.
+// of status changes for the specified unit, or for the whole
+// environment.
 func NewStatusHistoryCommand() cmd.Command {
 	return envcmd.Wrap(&statusHistoryCommand{})
 }
@@ -31,6 +36,14 @@ type statusHistoryCommand struct {
 	backlogSize   int
 	isoTime       bool
 	unitName      string
+	all           bool
+	from          string
+	to            string
+	statuses      string
+
+	fromTime   *time.Time
+	toTime     *time.Time
+	statusList []params.Status
 }
 
 var statusHistoryDoc = `
@@ -42,13 +55,28 @@ The statuses for the unit workload and/or agent are available.
     workload: will show statuses for the unit's workload
     combined: will show agent and workload statuses combined
  and sorted by time of occurrence.
+
+With --all, the status changes of every machine, service, unit
+and unit agent in the environment are merged into a single
+timeline, oldest first, showing the order in which things
+happened. The timeline may be restricted with:
+    --from, --to: only show status changes recorded in the given
+        window. Each bound is either an RFC3339 timestamp such as
+        2015-10-21T16:00:00Z, or a duration such as 2h meaning
+        that long ago.
+    --status: only show changes to the given statuses, separated
+        by commas.
+
+Examples:
+    juju status-history mysql/0
+    juju status-history --all --from 2h --status error,blocked
 `
 
 func (c *statusHistoryCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "status-history",
-		Args:    "[-n N] <unit>",
-		Purpose: "output past statuses for a unit",
+		Args:    "[-n N] <unit> | --all",
+		Purpose: "output past statuses for a unit or the environment",
 		Doc:     statusHistoryDoc,
 	}
 }
@@ -57,10 +85,20 @@ func (c *statusHistoryCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.outputContent, "type", "combined", "type of statuses to be displayed [agent|workload|combined].")
 	f.IntVar(&c.backlogSize, "n", 20, "size of logs backlog.")
 	f.BoolVar(&c.isoTime, "utc", false, "display time as UTC in RFC3339 format")
+	f.BoolVar(&c.all, "all", false, "show the status history of the whole environment")
+	f.StringVar(&c.from, "from", "", "with --all, only show statuses recorded since this time or duration ago")
+	f.StringVar(&c.to, "to", "", "with --all, only show statuses recorded until this time or duration ago")
+	f.StringVar(&c.statuses, "status", "", "with --all, only show these statuses (comma separated)")
 }
 
 func (c *statusHistoryCommand) Init(args []string) error {
 	switch {
+	case c.all:
+		if err := c.initAll(args); err != nil {
+			return errors.Trace(err)
+		}
+	case c.from != "" || c.to != "" || c.statuses != "":
+		return errors.Errorf("--from, --to and --status can only be used with --all")
 	case len(args) > 1:
 		return errors.Errorf("unexpected arguments after unit name.")
 	case len(args) == 0:
@@ -88,7 +126,66 @@ func (c *statusHistoryCommand) Init(args []string) error {
 	return errors.Errorf("unexpected status type %q", c.outputContent)
 }
 
+// initAll validates the arguments used to show the status history of
+// the whole environment.
+func (c *statusHistoryCommand) initAll(args []string) error {
+	if len(args) > 0 {
+		return errors.Errorf("unexpected arguments with --all")
+	}
+	if c.outputContent != string(params.KindCombined) {
+		return errors.Errorf("-type cannot be used with --all")
+	}
+	now := time.Now()
+	var err error
+	if c.fromTime, err = parseHistoryTime(c.from, now); err != nil {
+		return errors.Trace(err)
+	}
+	if c.toTime, err = parseHistoryTime(c.to, now); err != nil {
+		return errors.Trace(err)
+	}
+	if c.fromTime != nil && c.toTime != nil && c.toTime.Before(*c.fromTime) {
+		return errors.Errorf("--to must not be before --from")
+	}
+	for _, status := range strings.Split(c.statuses, ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			c.statusList = append(c.statusList, params.Status(status))
+		}
+	}
+	return nil
+}
+
+// parseHistoryTime parses a --from or --to value, returning nil if
+// none was given.
+func parseHistoryTime(value string, now time.Time) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := common.ParseTime(value, now)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &t, nil
+}
+
+// environStatusHistoryAPI defines the API methods used to show the
+// status history of an environment.
+type environStatusHistoryAPI interface {
+	EnvironStatusHistory(params.EnvironStatusHistoryArgs) ([]params.EntityStatusHistory, error)
+	Close() error
+}
+
+var newEnvironStatusHistoryAPI = func(c *statusHistoryCommand) (environStatusHistoryAPI, error) {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
 func (c *statusHistoryCommand) Run(ctx *cmd.Context) error {
+	if c.all {
+		return c.runAll(ctx)
+	}
 	apiclient, err := c.NewAPIClient()
 	if err != nil {
 		return fmt.Errorf(connectionError, c.ConnectionName(), err)
@@ -123,3 +220,34 @@ func (c *statusHistoryCommand) Run(ctx *cmd.Context) error {
 	}
 	return nil
 }
+
+// runAll shows the status history of the whole environment.
+func (c *statusHistoryCommand) runAll(ctx *cmd.Context) error {
+	apiclient, err := newEnvironStatusHistoryAPI(c)
+	if err != nil {
+		return fmt.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	defer apiclient.Close()
+	statuses, err := apiclient.EnvironStatusHistory(params.EnvironStatusHistoryArgs{
+		Size:     c.backlogSize,
+		From:     c.fromTime,
+		To:       c.toTime,
+		Statuses: c.statusList,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(statuses) == 0 {
+		return errors.Errorf("no status history available")
+	}
+	tw := tabwriter.NewWriter(ctx.Stdout, 0, 1, 1, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tENTITY\tTYPE\tSTATUS\tMESSAGE")
+	for _, v := range statuses {
+		entity := v.Tag
+		if tag, err := names.ParseTag(v.Tag); err == nil {
+			entity = tag.Id()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", common.FormatTime(v.Since, c.isoTime), entity, v.Kind, v.Status, v.Info)
+	}
+	return tw.Flush()
+}
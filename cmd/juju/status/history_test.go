@@ -0,0 +1,135 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type HistorySuite struct {
+	coretesting.FakeJujuHomeSuite
+}
+
+var _ = gc.Suite(&HistorySuite{})
+
+func initStatusHistoryCommand(args ...string) (*statusHistoryCommand, error) {
+	com := &statusHistoryCommand{}
+	return com, coretesting.InitCommand(envcmd.Wrap(com), args)
+}
+
+func (s *HistorySuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "unit name is missing.",
+	}, {
+		args: []string{"mysql/0", "--status", "error"},
+		err:  "--from, --to and --status can only be used with --all",
+	}, {
+		args: []string{"--all", "mysql/0"},
+		err:  "unexpected arguments with --all",
+	}, {
+		args: []string{"--all", "--type", "agent"},
+		err:  "-type cannot be used with --all",
+	}, {
+		args: []string{"--all", "--from", "yesterday"},
+		err:  `invalid time "yesterday": expected an RFC3339 timestamp or a duration such as 2h`,
+	}, {
+		args: []string{"--all", "--from", "1h", "--to", "2h"},
+		err:  "--to must not be before --from",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := initStatusHistoryCommand(test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *HistorySuite) TestInitAll(c *gc.C) {
+	before := time.Now()
+	com, err := initStatusHistoryCommand(
+		"--all", "--from", "2015-10-21T16:00:00Z", "--to", "2h", "--status", "error, blocked",
+	)
+	after := time.Now()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(com.fromTime.Equal(time.Date(2015, 10, 21, 16, 0, 0, 0, time.UTC)), jc.IsTrue)
+	c.Assert(com.toTime.Before(before.Add(-2*time.Hour)), jc.IsFalse)
+	c.Assert(com.toTime.After(after.Add(-2*time.Hour)), jc.IsFalse)
+	c.Assert(com.statusList, jc.DeepEquals, []params.Status{params.StatusError, params.StatusBlocked})
+}
+
+func (s *HistorySuite) TestRunAll(c *gc.C) {
+	t0 := time.Date(2015, 10, 21, 16, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Minute)
+	t2 := t1.Add(time.Minute)
+	api := &fakeEnvironStatusHistoryAPI{
+		statuses: []params.EntityStatusHistory{{
+			Tag:    "machine-0",
+			Kind:   params.KindMachine,
+			Status: params.StatusError,
+			Info:   "disk full",
+			Since:  &t0,
+		}, {
+			Tag:    "unit-mysql-0",
+			Kind:   params.KindWorkload,
+			Status: params.StatusBlocked,
+			Info:   "waiting for storage",
+			Since:  &t1,
+		}, {
+			Tag:    "service-mysql",
+			Kind:   params.KindService,
+			Status: params.StatusBlocked,
+			Since:  &t2,
+		}},
+	}
+	s.PatchValue(&newEnvironStatusHistoryAPI, func(*statusHistoryCommand) (environStatusHistoryAPI, error) {
+		return api, nil
+	})
+
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&statusHistoryCommand{}),
+		"--all", "--utc", "-n", "5", "--status", "error,blocked")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(api.closed, jc.IsTrue)
+	c.Assert(api.args, jc.DeepEquals, params.EnvironStatusHistoryArgs{
+		Size:     5,
+		Statuses: []params.Status{params.StatusError, params.StatusBlocked},
+	})
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, ""+
+		"TIME                 ENTITY  TYPE     STATUS  MESSAGE\n"+
+		"2015-10-21 16:00:00Z 0       machine  error   disk full\n"+
+		"2015-10-21 16:01:00Z mysql/0 workload blocked waiting for storage\n"+
+		"2015-10-21 16:02:00Z mysql   service  blocked \n")
+}
+
+func (s *HistorySuite) TestRunAllNoHistory(c *gc.C) {
+	s.PatchValue(&newEnvironStatusHistoryAPI, func(*statusHistoryCommand) (environStatusHistoryAPI, error) {
+		return &fakeEnvironStatusHistoryAPI{}, nil
+	})
+	_, err := coretesting.RunCommand(c, envcmd.Wrap(&statusHistoryCommand{}), "--all")
+	c.Assert(err, gc.ErrorMatches, "no status history available")
+}
+
+type fakeEnvironStatusHistoryAPI struct {
+	statuses []params.EntityStatusHistory
+	args     params.EnvironStatusHistoryArgs
+	closed   bool
+}
+
+func (api *fakeEnvironStatusHistoryAPI) EnvironStatusHistory(args params.EnvironStatusHistoryArgs) ([]params.EntityStatusHistory, error) {
+	api.args = args
+	return api.statuses, nil
+}
+
+func (api *fakeEnvironStatusHistoryAPI) Close() error {
+	api.closed = true
+	return nil
+}
@@ -36,7 +36,9 @@ type statusCommand struct {
 	out      cmd.Output
 	patterns []string
 	isoTime  bool
+	watch    bool
 	api      statusAPI
+	flagSet  *gnuflag.FlagSet
 }
 
 var statusDoc = `
@@ -70,6 +72,37 @@ will be displayed.
 Wildcards ('*') may be specified in service/unit names to match any sequence
 of characters. For example, 'nova-*' will match any service whose name begins
 with 'nova-': 'nova-compute', 'nova-volume', etc.
+
+The status may also be filtered with terms of the form <key><op><value>,
+which are evaluated by the server and apply to every output format:
+
+    status=<status>[,...]          unit agent or workload status, or
+                                   machine status
+    workload=<status>[,...]        unit workload status
+    agent=<status>[,...]           unit agent status, or machine status
+    machine-series=<series>[,...]  series of the machine
+    service-charm=<charm>[,...]    charm name or URL of the service
+    since<op><duration>            time since the status last changed,
+                                   where <op> is one of <, <=, > or >=
+
+All keys but since may also be compared with != to exclude matches. An
+entity must match every filter term given, as well as any name pattern.
+For units, since is measured from the status named by the other terms:
+the workload status for workload terms, the agent status for agent
+terms, and whichever status matched for status terms. Otherwise it is
+measured from the workload status. Filter terms require a server that
+supports version 1 of the Client API.
+For example, to show the units that have been in error or blocked for
+more than ten minutes:
+
+    juju status 'status=error,blocked' 'since>10m'
+
+With --watch, the tabular status is redrawn whenever the environment
+changes, with changed rows highlighted, until the command is
+interrupted. The tabular format is used unless another is given with
+--format, which --watch does not support. A single watcher is used to follow the changes, which is
+far cheaper for the server than running status repeatedly. Patterns
+and filters cannot be used with --watch.
 `
 
 func (c *statusCommand) Info() *cmd.Info {
@@ -83,7 +116,9 @@ func (c *statusCommand) Info() *cmd.Info {
 }
 
 func (c *statusCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.flagSet = f
 	f.BoolVar(&c.isoTime, "utc", false, "display time as UTC in RFC3339 format")
+	f.BoolVar(&c.watch, "watch", false, "redraw the tabular status whenever it changes")
 
 	oneLineFormatter := FormatOneline
 	defaultFormat := "yaml"
@@ -105,6 +140,22 @@ func (c *statusCommand) SetFlags(f *gnuflag.FlagSet) {
 
 func (c *statusCommand) Init(args []string) error {
 	c.patterns = args
+	if c.watch {
+		// --watch always draws the tabular format, so any other
+		// format must only be rejected if it was asked for.
+		formatSet := false
+		c.flagSet.Visit(func(flag *gnuflag.Flag) {
+			if flag.Name == "format" {
+				formatSet = true
+			}
+		})
+		if formatSet && c.out.Name() != "tabular" {
+			return errors.New("--watch is only supported with the tabular format")
+		}
+		if len(c.patterns) > 0 {
+			return errors.New("patterns cannot be used with --watch")
+		}
+	}
 	// If use of ISO time not specified on command line,
 	// check env var.
 	if !c.isoTime {
@@ -131,6 +182,9 @@ var newApiClientForStatus = func(c *statusCommand) (statusAPI, error) {
 }
 
 func (c *statusCommand) Run(ctx *cmd.Context) error {
+	if c.watch {
+		return c.runWatch(ctx.Stdout)
+	}
 	apiclient, err := newApiClientForStatus(c)
 	if err != nil {
 		return errors.Errorf(connectionError, c.ConnectionName(), err)
@@ -3620,6 +3620,68 @@ func (s *StatusSuite) TestFilterMultipleHeterogenousPatterns(c *gc.C) {
 	c.Assert(string(stdout), gc.Equals, expected[1:])
 }
 
+// Scenario: User filters to units in error for a short while
+func (s *StatusSuite) TestFilterOnStatusAndSince(c *gc.C) {
+	ctx := s.FilteringTestSetup(c)
+	defer s.resetContext(c, ctx)
+
+	// Given unit 1 of the "logging" service has an error
+	setAgentStatus{"logging/1", state.StatusError, "mock error", nil}.step(c, ctx)
+	// When I run juju status --format oneline status=error,blocked since<1h
+	_, stdout, stderr := runStatus(c, "--format", "oneline", "status=error,blocked", "since<1h")
+	c.Assert(stderr, gc.IsNil)
+	// Then I should receive output prefixed with:
+	const expected = `
+
+- mysql/0: dummyenv-2.dns (started)
+  - logging/1: dummyenv-2.dns (error)
+`
+	c.Assert(string(stdout), gc.Equals, expected[1:])
+}
+
+// Scenario: User filters on a service's charm and a unit's workload status
+func (s *StatusSuite) TestFilterOnServiceCharmAndWorkload(c *gc.C) {
+	ctx := s.FilteringTestSetup(c)
+	defer s.resetContext(c, ctx)
+
+	// When I run juju status --format oneline service-charm=wordpress workload=active
+	_, stdout, stderr := runStatus(c, "--format", "oneline", "service-charm=wordpress", "workload=active")
+	c.Assert(stderr, gc.IsNil)
+	// Then I should receive output prefixed with:
+	const expected = `
+
+- wordpress/0: dummyenv-1.dns (started)
+  - logging/0: dummyenv-1.dns (started)
+`
+	c.Assert(string(stdout), gc.Equals, expected[1:])
+}
+
+// Scenario: User combines a name pattern with a filter on machine series
+func (s *StatusSuite) TestFilterOnNameAndMachineSeries(c *gc.C) {
+	ctx := s.FilteringTestSetup(c)
+	defer s.resetContext(c, ctx)
+
+	// When I run juju status --format oneline mysql machine-series=quantal
+	_, stdout, stderr := runStatus(c, "--format", "oneline", "mysql", "machine-series=quantal")
+	c.Assert(stderr, gc.IsNil)
+	// Then I should receive output prefixed with:
+	const expected = `
+
+- mysql/0: dummyenv-2.dns (started)
+  - logging/1: dummyenv-2.dns (started)
+`
+	c.Assert(string(stdout), gc.Equals, expected[1:])
+}
+
+func (s *StatusSuite) TestFilterInvalid(c *gc.C) {
+	ctx := s.FilteringTestSetup(c)
+	defer s.resetContext(c, ctx)
+
+	code, _, stderr := runStatus(c, "since=10m")
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(string(stderr), jc.Contains, `invalid status filter "since=10m": since must be compared with <, <=, > or >=`)
+}
+
 // TestSummaryStatusWithUnresolvableDns is result of bug# 1410320.
 func (s *StatusSuite) TestSummaryStatusWithUnresolvableDns(c *gc.C) {
 	formatter := &summaryFormatter{}
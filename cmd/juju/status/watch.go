@@ -0,0 +1,388 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+const (
+	// clearScreen moves the cursor to the top left of the terminal
+	// and clears it.
+	clearScreen = "\x1b[H\x1b[2J"
+
+	// highlightStart and highlightEnd surround rows that changed
+	// since the previous redraw.
+	highlightStart = "\x1b[1m"
+	highlightEnd   = "\x1b[0m"
+)
+
+// allWatcher is the part of api.AllWatcher used to watch status.
+type allWatcher interface {
+	Next() ([]multiwatcher.Delta, error)
+	Stop() error
+}
+
+// watchAPI defines the API methods used by status --watch.
+type watchAPI interface {
+	WatchAll() (allWatcher, error)
+	Status(patterns []string) (*params.FullStatus, error)
+	Close() error
+}
+
+// watchAPIClient adapts an api.Client to the watchAPI interface.
+type watchAPIClient struct {
+	*api.Client
+}
+
+// WatchAll implements watchAPI.
+func (c watchAPIClient) WatchAll() (allWatcher, error) {
+	w, err := c.Client.WatchAll()
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+var newWatchAPIForStatus = func(c *statusCommand) (watchAPI, error) {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return watchAPIClient{client}, nil
+}
+
+// runWatch follows changes to the environment with a single
+// allwatcher, redrawing the tabular status whenever it changes.
+func (c *statusCommand) runWatch(out io.Writer) error {
+	apiclient, err := newWatchAPIForStatus(c)
+	if err != nil {
+		return errors.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	defer apiclient.Close()
+
+	watcher, err := apiclient.WatchAll()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer watcher.Stop()
+
+	model := newStatusModel(c.ConnectionName())
+	var previous []byte
+	for {
+		deltas, err := watcher.Next()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !model.apply(deltas) {
+			continue
+		}
+		status := model.fullStatus()
+		if model.missingPrincipals() {
+			// Older servers do not report the principals of
+			// subordinate units, so the status cannot be built
+			// from the deltas; fetch it instead.
+			fullStatus, err := apiclient.Status(nil)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			status = *fullStatus
+		}
+		formatter := newStatusFormatter(&status, c.CompatVersion(), c.isoTime)
+		current, err := FormatTabular(formatter.format())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if bytes.Equal(current, previous) {
+			continue
+		}
+		if _, err := io.WriteString(out, clearScreen+highlightChanges(previous, current)); err != nil {
+			return errors.Trace(err)
+		}
+		previous = current
+	}
+}
+
+// highlightChanges returns the current output with any rows that did
+// not appear in the previous output highlighted. Nothing is
+// highlighted when there is no previous output.
+func highlightChanges(previous, current []byte) string {
+	lines := strings.SplitAfter(string(current), "\n")
+	if len(previous) == 0 {
+		return strings.Join(lines, "")
+	}
+	seen := make(map[string]bool)
+	for _, line := range strings.SplitAfter(string(previous), "\n") {
+		seen[strings.TrimRight(line, " \n")] = true
+	}
+	var buf bytes.Buffer
+	for _, line := range lines {
+		row := strings.TrimRight(line, " \n")
+		if row == "" || seen[row] {
+			buf.WriteString(line)
+			continue
+		}
+		buf.WriteString(highlightStart + row + highlightEnd)
+		if strings.HasSuffix(line, "\n") {
+			buf.WriteString("\n")
+		}
+	}
+	return buf.String()
+}
+
+// statusModel holds the entities reported by an allwatcher that are
+// needed to construct the status of an environment.
+type statusModel struct {
+	environmentName string
+	machines        map[string]*multiwatcher.MachineInfo
+	services        map[string]*multiwatcher.ServiceInfo
+	units           map[string]*multiwatcher.UnitInfo
+	relations       map[string]*multiwatcher.RelationInfo
+}
+
+func newStatusModel(environmentName string) *statusModel {
+	return &statusModel{
+		environmentName: environmentName,
+		machines:        make(map[string]*multiwatcher.MachineInfo),
+		services:        make(map[string]*multiwatcher.ServiceInfo),
+		units:           make(map[string]*multiwatcher.UnitInfo),
+		relations:       make(map[string]*multiwatcher.RelationInfo),
+	}
+}
+
+// apply updates the model with the given deltas, reporting whether
+// any entity shown in the status was affected.
+func (m *statusModel) apply(deltas []multiwatcher.Delta) bool {
+	changed := false
+	for _, delta := range deltas {
+		switch info := delta.Entity.(type) {
+		case *multiwatcher.MachineInfo:
+			if delta.Removed {
+				delete(m.machines, info.Id)
+			} else {
+				m.machines[info.Id] = info
+			}
+		case *multiwatcher.ServiceInfo:
+			if delta.Removed {
+				delete(m.services, info.Name)
+			} else {
+				m.services[info.Name] = info
+			}
+		case *multiwatcher.UnitInfo:
+			if delta.Removed {
+				delete(m.units, info.Name)
+			} else {
+				m.units[info.Name] = info
+			}
+		case *multiwatcher.RelationInfo:
+			if delta.Removed {
+				delete(m.relations, info.Key)
+			} else {
+				m.relations[info.Key] = info
+			}
+		default:
+			continue
+		}
+		changed = true
+	}
+	return changed
+}
+
+// missingPrincipals reports whether any subordinate unit in the model
+// lacks its principal, as happens with servers that predate its
+// addition to the allwatcher.
+func (m *statusModel) missingPrincipals() bool {
+	for _, unit := range m.units {
+		if unit.Subordinate && unit.Principal == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// fullStatus returns the status of the environment described by the
+// model.
+func (m *statusModel) fullStatus() params.FullStatus {
+	status := params.FullStatus{
+		EnvironmentName: m.environmentName,
+		Machines:        make(map[string]params.MachineStatus),
+		Services:        make(map[string]params.ServiceStatus),
+	}
+	for id := range m.machines {
+		if !strings.Contains(id, "/") {
+			status.Machines[id] = m.machineStatus(id)
+		}
+	}
+	for name, service := range m.services {
+		status.Services[name] = m.serviceStatus(service)
+	}
+	var keys []string
+	for key := range m.relations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		status.Relations = append(status.Relations, relationStatus(m.relations[key]))
+	}
+	return status
+}
+
+// machineStatus returns the status of the machine with the given id,
+// including its containers.
+func (m *statusModel) machineStatus(id string) params.MachineStatus {
+	info := m.machines[id]
+	agent := params.AgentStatus{
+		Status: params.Status(info.Status),
+		Info:   info.StatusInfo,
+		Data:   info.StatusData,
+		Life:   string(info.Life),
+	}
+	status := params.MachineStatus{
+		Agent:          agent,
+		AgentState:     agent.Status,
+		AgentStateInfo: agent.Info,
+		Life:           agent.Life,
+		InstanceId:     instance.Id(info.InstanceId),
+		Series:         info.Series,
+		Id:             info.Id,
+		Containers:     make(map[string]params.MachineStatus),
+		Jobs:           info.Jobs,
+		HasVote:        info.HasVote,
+		WantsVote:      info.WantsVote,
+	}
+	if info.InstanceId != "" {
+		addr, _ := network.SelectPublicAddress(info.Addresses)
+		status.DNSName = addr.Value
+	} else {
+		// As for the full status, unprovisioned machines are
+		// shown as pending with no agent state.
+		status.InstanceId = "pending"
+		status.AgentState = ""
+	}
+	if info.HardwareCharacteristics != nil {
+		status.Hardware = info.HardwareCharacteristics.String()
+	}
+	for containerId := range m.machines {
+		if parentId(containerId) == id {
+			status.Containers[containerId] = m.machineStatus(containerId)
+		}
+	}
+	return status
+}
+
+func (m *statusModel) serviceStatus(info *multiwatcher.ServiceInfo) params.ServiceStatus {
+	status := params.ServiceStatus{
+		Err:     info.Status.Err,
+		Charm:   info.CharmURL,
+		Exposed: info.Exposed,
+		Life:    string(info.Life),
+		Status:  agentStatus(info.Status),
+		Units:   make(map[string]params.UnitStatus),
+	}
+	if info.Subordinate {
+		for _, rel := range m.relations {
+			for _, ep := range rel.Endpoints {
+				if ep.ServiceName != info.Name && ep.Relation.Scope == charm.ScopeContainer {
+					status.SubordinateTo = append(status.SubordinateTo, ep.ServiceName)
+				}
+			}
+		}
+		sort.Strings(status.SubordinateTo)
+	}
+	for name, unit := range m.units {
+		if unit.Service == info.Name && !unit.Subordinate {
+			status.Units[name] = m.unitStatus(unit, info.CharmURL)
+		}
+	}
+	return status
+}
+
+func (m *statusModel) unitStatus(info *multiwatcher.UnitInfo, serviceCharm string) params.UnitStatus {
+	status := params.UnitStatus{
+		UnitAgent:      agentStatus(info.AgentStatus),
+		Workload:       agentStatus(info.WorkloadStatus),
+		AgentState:     params.Status(info.Status),
+		AgentStateInfo: info.StatusInfo,
+		AgentVersion:   info.AgentStatus.Version,
+		Machine:        info.MachineId,
+		PublicAddress:  info.PublicAddress,
+	}
+	if info.CharmURL != serviceCharm {
+		status.Charm = info.CharmURL
+	}
+	for _, portRange := range info.PortRanges {
+		status.OpenedPorts = append(status.OpenedPorts, portRange.String())
+	}
+	for name, sub := range m.units {
+		if sub.Principal == info.Name {
+			if status.Subordinates == nil {
+				status.Subordinates = make(map[string]params.UnitStatus)
+			}
+			subStatus := m.unitStatus(sub, m.serviceCharm(sub.Service))
+			if subStatus.Machine == "" {
+				subStatus.Machine = info.MachineId
+			}
+			status.Subordinates[name] = subStatus
+		}
+	}
+	return status
+}
+
+func (m *statusModel) serviceCharm(name string) string {
+	if service, ok := m.services[name]; ok {
+		return service.CharmURL
+	}
+	return ""
+}
+
+func agentStatus(info multiwatcher.StatusInfo) params.AgentStatus {
+	return params.AgentStatus{
+		Status:  params.Status(info.Current),
+		Info:    info.Message,
+		Data:    info.Data,
+		Since:   info.Since,
+		Version: info.Version,
+		Err:     info.Err,
+	}
+}
+
+func relationStatus(info *multiwatcher.RelationInfo) params.RelationStatus {
+	status := params.RelationStatus{
+		Id:  info.Id,
+		Key: info.Key,
+	}
+	for _, ep := range info.Endpoints {
+		status.Interface = ep.Relation.Interface
+		status.Scope = ep.Relation.Scope
+		status.Endpoints = append(status.Endpoints, params.EndpointStatus{
+			ServiceName: ep.ServiceName,
+			Name:        ep.Relation.Name,
+			Role:        ep.Relation.Role,
+			Subordinate: ep.Relation.Scope == charm.ScopeContainer,
+		})
+	}
+	return status
+}
+
+// parentId returns the id of the machine hosting the container with
+// the given id, or "" if the id is not that of a container.
+func parentId(id string) string {
+	parts := strings.Split(id, "/")
+	if len(parts) < 3 {
+		return ""
+	}
+	return strings.Join(parts[:len(parts)-2], "/")
+}
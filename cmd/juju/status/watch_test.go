@@ -0,0 +1,231 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state/multiwatcher"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type WatchSuite struct {
+	coretesting.FakeJujuHomeSuite
+}
+
+var _ = gc.Suite(&WatchSuite{})
+
+func (s *WatchSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"--watch", "--format", "yaml"},
+		err:  "--watch is only supported with the tabular format",
+	}, {
+		args: []string{"--watch", "--format", "tabular", "mysql"},
+		err:  "patterns cannot be used with --watch",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := initStatusCommand(test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *WatchSuite) TestInitDefaultsToTabular(c *gc.C) {
+	// Whatever the default format, --watch draws the tabular
+	// format unless another is asked for.
+	com, err := initStatusCommand("--watch")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(com.watch, jc.IsTrue)
+}
+
+func (s *WatchSuite) TestWatchRedrawsOnChange(c *gc.C) {
+	machine := &multiwatcher.MachineInfo{
+		Id:         "0",
+		InstanceId: "i-0",
+		Status:     multiwatcher.Status("started"),
+		Series:     "trusty",
+		Addresses:  network.NewAddresses("10.0.0.1"),
+	}
+	service := &multiwatcher.ServiceInfo{
+		Name:     "mysql",
+		CharmURL: "cs:trusty/mysql-1",
+		Status:   multiwatcher.StatusInfo{Current: "active"},
+	}
+	unit := &multiwatcher.UnitInfo{
+		Name:           "mysql/0",
+		Service:        "mysql",
+		CharmURL:       "cs:trusty/mysql-1",
+		MachineId:      "0",
+		WorkloadStatus: multiwatcher.StatusInfo{Current: "active"},
+		AgentStatus:    multiwatcher.StatusInfo{Current: "idle"},
+	}
+	blocked := *unit
+	blocked.WorkloadStatus = multiwatcher.StatusInfo{Current: "blocked", Message: "need a relation"}
+	watcher := &fakeAllWatcher{
+		deltas: [][]multiwatcher.Delta{{
+			{Entity: machine},
+			{Entity: service},
+			{Entity: unit},
+		}, {
+			// Annotations are not shown, so cause no redraw.
+			{Entity: &multiwatcher.AnnotationInfo{Tag: "service-mysql"}},
+		}, {
+			{Entity: &blocked},
+		}},
+	}
+	api := &fakeWatchAPI{watcher: watcher}
+	s.PatchValue(&newWatchAPIForStatus, func(*statusCommand) (watchAPI, error) {
+		return api, nil
+	})
+
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&statusCommand{}), "--watch")
+	c.Assert(err, gc.ErrorMatches, "watcher stopped")
+	c.Assert(api.closed, jc.IsTrue)
+	c.Assert(watcher.stopped, jc.IsTrue)
+
+	frames := strings.Split(coretesting.Stdout(ctx), clearScreen)
+	c.Assert(frames, gc.HasLen, 3)
+	c.Assert(frames[0], gc.Equals, "")
+
+	// Nothing is highlighted in the first frame.
+	c.Assert(frames[1], gc.Not(jc.Contains), highlightStart)
+	c.Assert(frames[1], jc.Contains, "mysql/0 active")
+
+	// Only the changed unit is highlighted in the second.
+	c.Assert(strings.Count(frames[2], highlightStart), gc.Equals, 1)
+	c.Assert(frames[2], jc.Contains, highlightStart+"mysql/0 blocked")
+	c.Assert(frames[2], jc.Contains, "need a relation"+highlightEnd)
+}
+
+func (s *WatchSuite) TestWatchWithoutPrincipalsFetchesStatus(c *gc.C) {
+	// Older servers do not report the principal of a subordinate
+	// unit, so the status is fetched rather than built.
+	watcher := &fakeAllWatcher{
+		deltas: [][]multiwatcher.Delta{{
+			{Entity: &multiwatcher.ServiceInfo{Name: "logging", CharmURL: "cs:logging-1", Subordinate: true}},
+			{Entity: &multiwatcher.UnitInfo{Name: "logging/0", Service: "logging", CharmURL: "cs:logging-1", Subordinate: true}},
+		}},
+	}
+	api := &fakeWatchAPI{
+		watcher: watcher,
+		status: &params.FullStatus{
+			EnvironmentName: "dummyenv",
+			Services: map[string]params.ServiceStatus{
+				"wordpress": {
+					Charm: "cs:wordpress-1",
+					Units: map[string]params.UnitStatus{
+						"wordpress/0": {
+							Workload: params.AgentStatus{Status: "active"},
+							Subordinates: map[string]params.UnitStatus{
+								"logging/0": {Workload: params.AgentStatus{Status: "active"}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	s.PatchValue(&newWatchAPIForStatus, func(*statusCommand) (watchAPI, error) {
+		return api, nil
+	})
+
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&statusCommand{}), "--watch")
+	c.Assert(err, gc.ErrorMatches, "watcher stopped")
+	c.Assert(api.statusCalls, gc.Equals, 1)
+	c.Assert(coretesting.Stdout(ctx), jc.Contains, "logging/0")
+	c.Assert(coretesting.Stdout(ctx), jc.Contains, "wordpress/0")
+}
+
+func (s *WatchSuite) TestModelFullStatus(c *gc.C) {
+	model := newStatusModel("dummyenv")
+	changed := model.apply([]multiwatcher.Delta{
+		{Entity: &multiwatcher.MachineInfo{Id: "0", InstanceId: "i-0"}},
+		{Entity: &multiwatcher.MachineInfo{Id: "0/lxc/0"}},
+		{Entity: &multiwatcher.ServiceInfo{Name: "wordpress", CharmURL: "cs:wordpress-1"}},
+		{Entity: &multiwatcher.ServiceInfo{Name: "logging", CharmURL: "cs:logging-1", Subordinate: true}},
+		{Entity: &multiwatcher.UnitInfo{Name: "wordpress/0", Service: "wordpress", CharmURL: "cs:wordpress-1", MachineId: "0"}},
+		{Entity: &multiwatcher.UnitInfo{Name: "logging/0", Service: "logging", CharmURL: "cs:logging-1", Subordinate: true, Principal: "wordpress/0"}},
+		{Entity: &multiwatcher.RelationInfo{Key: "logging:info wordpress:juju-info", Id: 1, Endpoints: []multiwatcher.Endpoint{{
+			ServiceName: "logging",
+			Relation:    charm.Relation{Name: "info", Role: charm.RoleRequirer, Interface: "juju-info", Scope: charm.ScopeContainer},
+		}, {
+			ServiceName: "wordpress",
+			Relation:    charm.Relation{Name: "juju-info", Role: charm.RoleProvider, Interface: "juju-info", Scope: charm.ScopeContainer},
+		}}}},
+	})
+	c.Assert(changed, jc.IsTrue)
+
+	status := model.fullStatus()
+	c.Assert(status.Machines, gc.HasLen, 1)
+	c.Assert(string(status.Machines["0"].InstanceId), gc.Equals, "i-0")
+	c.Assert(status.Machines["0"].Containers, gc.HasLen, 1)
+	c.Assert(string(status.Machines["0"].Containers["0/lxc/0"].InstanceId), gc.Equals, "pending")
+
+	c.Assert(status.Services["wordpress"].Units, gc.HasLen, 1)
+	c.Assert(status.Services["logging"].Units, gc.HasLen, 0)
+	c.Assert(status.Services["logging"].SubordinateTo, jc.DeepEquals, []string{"wordpress"})
+	sub := status.Services["wordpress"].Units["wordpress/0"].Subordinates["logging/0"]
+	c.Assert(sub.Machine, gc.Equals, "0")
+	c.Assert(sub.Charm, gc.Equals, "")
+	c.Assert(status.Relations, gc.HasLen, 1)
+
+	changed = model.apply([]multiwatcher.Delta{
+		{Removed: true, Entity: &multiwatcher.UnitInfo{Name: "logging/0"}},
+	})
+	c.Assert(changed, jc.IsTrue)
+	status = model.fullStatus()
+	c.Assert(status.Services["wordpress"].Units["wordpress/0"].Subordinates, gc.HasLen, 0)
+}
+
+type fakeWatchAPI struct {
+	watcher     *fakeAllWatcher
+	status      *params.FullStatus
+	statusCalls int
+	closed      bool
+}
+
+func (api *fakeWatchAPI) WatchAll() (allWatcher, error) {
+	return api.watcher, nil
+}
+
+func (api *fakeWatchAPI) Status(patterns []string) (*params.FullStatus, error) {
+	api.statusCalls++
+	return api.status, nil
+}
+
+func (api *fakeWatchAPI) Close() error {
+	api.closed = true
+	return nil
+}
+
+// fakeAllWatcher returns each of its batches of deltas in turn, then
+// fails.
+type fakeAllWatcher struct {
+	deltas  [][]multiwatcher.Delta
+	stopped bool
+}
+
+func (w *fakeAllWatcher) Next() ([]multiwatcher.Delta, error) {
+	if len(w.deltas) == 0 {
+		return nil, errors.New("watcher stopped")
+	}
+	deltas := w.deltas[0]
+	w.deltas = w.deltas[1:]
+	return deltas, nil
+}
+
+func (w *fakeAllWatcher) Stop() error {
+	w.stopped = true
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// StorageAttachAPI defines the API methods that the storage attach
+// command uses.
+type StorageAttachAPI interface {
+	Close() error
+	AttachStorage(ids []params.StorageAttachmentId) ([]params.ErrorResult, error)
+}
+
+const attachCommandDoc = `
+Attach a storage instance that was detached with "juju storage detach"
+to a unit of the same service. The unit takes ownership of the storage,
+and the storage's existing volume or filesystem is attached to the
+unit's machine.
+
+The unit must not already have the maximum number of storage instances
+supported by the charm for the storage's store. To replace a unit,
+attach the storage when adding the new unit:
+
+      juju service add-unit postgresql --attach-storage data/0
+
+Example:
+    Attach storage instance data/0 to unit postgresql/1:
+
+      juju storage attach data/0 postgresql/1
+`
+
+func newAttachCommand() cmd.Command {
+	cmd := &attachCommand{}
+	cmd.newAPIFunc = func() (StorageAttachAPI, error) {
+		return cmd.NewStorageAPI()
+	}
+	return envcmd.Wrap(cmd)
+}
+
+// attachCommand attaches detached storage to a unit.
+type attachCommand struct {
+	StorageCommandBase
+	storageTag names.StorageTag
+	unitTag    names.UnitTag
+	newAPIFunc func() (StorageAttachAPI, error)
+}
+
+// Init implements Command.Init.
+func (c *attachCommand) Init(args []string) (err error) {
+	c.storageTag, c.unitTag, err = parseStorageAttachmentArgs("attach", args)
+	return err
+}
+
+// Info implements Command.Info.
+func (c *attachCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "attach",
+		Args:    "<storage ID> <unit name>",
+		Purpose: "attach detached storage to a unit",
+		Doc:     attachCommandDoc,
+	}
+}
+
+// Run implements Command.Run.
+func (c *attachCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.AttachStorage([]params.StorageAttachmentId{{
+		StorageTag: c.storageTag.String(),
+		UnitTag:    c.unitTag.String(),
+	}})
+	if err != nil {
+		return err
+	}
+	if results[0].Error != nil {
+		return results[0].Error
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/storage"
+	"github.com/juju/juju/testing"
+)
+
+type attachSuite struct {
+	SubStorageSuite
+	mockAPI *mockAttachAPI
+}
+
+var _ = gc.Suite(&attachSuite{})
+
+func (s *attachSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+	s.mockAPI = &mockAttachAPI{}
+}
+
+func (s *attachSuite) TestAttach(c *gc.C) {
+	_, err := testing.RunCommand(c, storage.NewAttachCommand(s.mockAPI), "data/0", "postgresql/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.attached, jc.DeepEquals, []params.StorageAttachmentId{{
+		StorageTag: "storage-data-0",
+		UnitTag:    "unit-postgresql-1",
+	}})
+}
+
+func (s *attachSuite) TestAttachError(c *gc.C) {
+	s.mockAPI.err = &params.Error{Message: `storage is not detached from service "postgresql"`}
+	_, err := testing.RunCommand(c, storage.NewAttachCommand(s.mockAPI), "data/0", "postgresql/1")
+	c.Assert(err, gc.ErrorMatches, `storage is not detached from service "postgresql"`)
+}
+
+func (s *attachSuite) TestAttachInvalidArgs(c *gc.C) {
+	_, err := testing.RunCommand(c, storage.NewAttachCommand(s.mockAPI))
+	c.Assert(err, gc.ErrorMatches, "storage attach requires a storage ID and a unit name")
+}
+
+type mockAttachAPI struct {
+	attached []params.StorageAttachmentId
+	err      *params.Error
+}
+
+func (s *mockAttachAPI) Close() error {
+	return nil
+}
+
+func (s *mockAttachAPI) AttachStorage(ids []params.StorageAttachmentId) ([]params.ErrorResult, error) {
+	s.attached = ids
+	return []params.ErrorResult{{Error: s.err}}, nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// StorageDetachAPI defines the API methods that the storage detach
+// command uses.
+type StorageDetachAPI interface {
+	Close() error
+	DetachStorage(ids []params.StorageAttachmentId) ([]params.ErrorResult, error)
+}
+
+const detachCommandDoc = `
+Detach a storage instance from the unit that owns it, so that the
+storage outlives the unit. The unit runs its "storage-detaching" hook,
+after which the storage's volume or filesystem is detached from the
+unit's machine with its data intact.
+
+Detached storage belongs to the unit's service, and may be attached to
+a new unit of the service with "juju service add-unit --attach-storage",
+or to an existing unit with "juju storage attach". Detached storage is
+destroyed along with its service.
+
+Storage whose volume or filesystem is bound to a machine, such as loop
+devices, cannot be detached.
+
+Example:
+    Detach storage instance data/0 from unit postgresql/0:
+
+      juju storage detach data/0 postgresql/0
+`
+
+func newDetachCommand() cmd.Command {
+	cmd := &detachCommand{}
+	cmd.newAPIFunc = func() (StorageDetachAPI, error) {
+		return cmd.NewStorageAPI()
+	}
+	return envcmd.Wrap(cmd)
+}
+
+// detachCommand detaches a storage instance from its owning unit.
+type detachCommand struct {
+	StorageCommandBase
+	storageTag names.StorageTag
+	unitTag    names.UnitTag
+	newAPIFunc func() (StorageDetachAPI, error)
+}
+
+// Init implements Command.Init.
+func (c *detachCommand) Init(args []string) (err error) {
+	c.storageTag, c.unitTag, err = parseStorageAttachmentArgs("detach", args)
+	return err
+}
+
+// parseStorageAttachmentArgs parses the storage ID and unit name
+// arguments of the storage attach and detach commands.
+func parseStorageAttachmentArgs(command string, args []string) (names.StorageTag, names.UnitTag, error) {
+	if len(args) != 2 {
+		return names.StorageTag{}, names.UnitTag{}, errors.Errorf(
+			"storage %s requires a storage ID and a unit name", command,
+		)
+	}
+	if !names.IsValidStorage(args[0]) {
+		return names.StorageTag{}, names.UnitTag{}, errors.NotValidf("storage ID %q", args[0])
+	}
+	if !names.IsValidUnit(args[1]) {
+		return names.StorageTag{}, names.UnitTag{}, errors.NotValidf("unit name %q", args[1])
+	}
+	return names.NewStorageTag(args[0]), names.NewUnitTag(args[1]), nil
+}
+
+// Info implements Command.Info.
+func (c *detachCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "detach",
+		Args:    "<storage ID> <unit name>",
+		Purpose: "detach storage from a unit, preserving its data",
+		Doc:     detachCommandDoc,
+	}
+}
+
+// Run implements Command.Run.
+func (c *detachCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.DetachStorage([]params.StorageAttachmentId{{
+		StorageTag: c.storageTag.String(),
+		UnitTag:    c.unitTag.String(),
+	}})
+	if err != nil {
+		return err
+	}
+	if results[0].Error != nil {
+		return results[0].Error
+	}
+	return nil
+}
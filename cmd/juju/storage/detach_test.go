@@ -0,0 +1,63 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/storage"
+	"github.com/juju/juju/testing"
+)
+
+type detachSuite struct {
+	SubStorageSuite
+	mockAPI *mockDetachAPI
+}
+
+var _ = gc.Suite(&detachSuite{})
+
+func (s *detachSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+	s.mockAPI = &mockDetachAPI{}
+}
+
+func (s *detachSuite) TestDetach(c *gc.C) {
+	_, err := testing.RunCommand(c, storage.NewDetachCommand(s.mockAPI), "data/0", "postgresql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.detached, jc.DeepEquals, []params.StorageAttachmentId{{
+		StorageTag: "storage-data-0",
+		UnitTag:    "unit-postgresql-0",
+	}})
+}
+
+func (s *detachSuite) TestDetachError(c *gc.C) {
+	s.mockAPI.err = &params.Error{Message: "volume 0/0 is machine-scoped, storage cannot be detached"}
+	_, err := testing.RunCommand(c, storage.NewDetachCommand(s.mockAPI), "data/0", "postgresql/0")
+	c.Assert(err, gc.ErrorMatches, "volume 0/0 is machine-scoped, storage cannot be detached")
+}
+
+func (s *detachSuite) TestDetachInvalidArgs(c *gc.C) {
+	_, err := testing.RunCommand(c, storage.NewDetachCommand(s.mockAPI), "data/0")
+	c.Assert(err, gc.ErrorMatches, "storage detach requires a storage ID and a unit name")
+	_, err = testing.RunCommand(c, storage.NewDetachCommand(s.mockAPI), "data", "postgresql/0")
+	c.Assert(err, gc.ErrorMatches, `storage ID "data" not valid`)
+	_, err = testing.RunCommand(c, storage.NewDetachCommand(s.mockAPI), "data/0", "postgresql")
+	c.Assert(err, gc.ErrorMatches, `unit name "postgresql" not valid`)
+}
+
+type mockDetachAPI struct {
+	detached []params.StorageAttachmentId
+	err      *params.Error
+}
+
+func (s *mockDetachAPI) Close() error {
+	return nil
+}
+
+func (s *mockDetachAPI) DetachStorage(ids []params.StorageAttachmentId) ([]params.ErrorResult, error) {
+	s.detached = ids
+	return []params.ErrorResult{{Error: s.err}}, nil
+}
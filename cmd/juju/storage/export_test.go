@@ -12,9 +12,11 @@ import (
 var (
 	ConvertToVolumeInfo     = convertToVolumeInfo
 	ConvertToFilesystemInfo = convertToFilesystemInfo
+	ConvertToSnapshotInfo   = convertToSnapshotInfo
 
-	NewPoolSuperCommand   = newPoolSuperCommand
-	NewVolumeSuperCommand = newVolumeSuperCommand
+	NewPoolSuperCommand     = newPoolSuperCommand
+	NewVolumeSuperCommand   = newVolumeSuperCommand
+	NewSnapshotSuperCommand = newSnapshotSuperCommand
 )
 
 func NewPoolListCommand(api PoolListAPI) cmd.Command {
@@ -65,3 +67,52 @@ func NewFilesystemListCommand(api FilesystemListAPI) cmd.Command {
 	}}
 	return envcmd.Wrap(cmd)
 }
+
+func NewSnapshotCreateCommand(api SnapshotCreateAPI) cmd.Command {
+	cmd := &snapshotCreateCommand{newAPIFunc: func() (SnapshotCreateAPI, error) {
+		return api, nil
+	}}
+	return envcmd.Wrap(cmd)
+}
+
+func NewSnapshotListCommand(api SnapshotListAPI) cmd.Command {
+	cmd := &snapshotListCommand{newAPIFunc: func() (SnapshotListAPI, error) {
+		return api, nil
+	}}
+	return envcmd.Wrap(cmd)
+}
+
+func NewSnapshotRemoveCommand(api SnapshotRemoveAPI) cmd.Command {
+	cmd := &snapshotRemoveCommand{newAPIFunc: func() (SnapshotRemoveAPI, error) {
+		return api, nil
+	}}
+	return envcmd.Wrap(cmd)
+}
+
+func NewSnapshotRestoreCommand(api SnapshotRestoreAPI) cmd.Command {
+	cmd := &snapshotRestoreCommand{newAPIFunc: func() (SnapshotRestoreAPI, error) {
+		return api, nil
+	}}
+	return envcmd.Wrap(cmd)
+}
+
+func NewResizeCommand(api StorageResizeAPI) cmd.Command {
+	cmd := &resizeCommand{newAPIFunc: func() (StorageResizeAPI, error) {
+		return api, nil
+	}}
+	return envcmd.Wrap(cmd)
+}
+
+func NewDetachCommand(api StorageDetachAPI) cmd.Command {
+	cmd := &detachCommand{newAPIFunc: func() (StorageDetachAPI, error) {
+		return api, nil
+	}}
+	return envcmd.Wrap(cmd)
+}
+
+func NewAttachCommand(api StorageAttachAPI) cmd.Command {
+	cmd := &attachCommand{newAPIFunc: func() (StorageAttachAPI, error) {
+		return api, nil
+	}}
+	return envcmd.Wrap(cmd)
+}
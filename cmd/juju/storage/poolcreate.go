@@ -38,6 +38,11 @@ into named resources that can be used during deployment.
 
 Pools defined at the environment level are easily reused across services.
 
+For example, to create volumes as thinly provisioned LVM logical volumes,
+in the thin pool "pool0" of each machine's volume group "vg0":
+
+    juju storage pool create thin lvm volume-group=vg0 thin-pool=pool0
+
 options:
     -e, --environment (= "")
         juju environment to operate in
@@ -0,0 +1,113 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// StorageResizeAPI defines the API methods that the storage resize
+// command uses.
+type StorageResizeAPI interface {
+	Close() error
+	ResizeStorage(storages []params.StorageResizeArg) ([]params.ErrorResult, error)
+}
+
+const resizeCommandDoc = `
+Grow the volume or filesystem assigned to a storage instance.
+Storage can only be grown, and must already be provisioned.
+
+The storage is resized by the storage provisioner, after which the
+unit that owns the storage runs its "storage-resized" hook, so that
+the charm may grow its filesystem to make use of the new space.
+
+The size may be given with an optional unit suffix of M, G, T or P;
+sizes without a suffix are in MiB.
+
+Example:
+    Grow storage instance data/0 to 20GiB:
+
+      juju storage resize data/0 --size 20G
+`
+
+func newResizeCommand() cmd.Command {
+	cmd := &resizeCommand{}
+	cmd.newAPIFunc = func() (StorageResizeAPI, error) {
+		return cmd.NewStorageAPI()
+	}
+	return envcmd.Wrap(cmd)
+}
+
+// resizeCommand grows the storage assigned to a storage instance.
+type resizeCommand struct {
+	StorageCommandBase
+	storageTag names.StorageTag
+	sizeString string
+	size       uint64
+	newAPIFunc func() (StorageResizeAPI, error)
+}
+
+// Init implements Command.Init.
+func (c *resizeCommand) Init(args []string) error {
+	if len(args) != 1 {
+		return errors.New("storage resize requires a storage ID")
+	}
+	if !names.IsValidStorage(args[0]) {
+		return errors.NotValidf("storage ID %q", args[0])
+	}
+	c.storageTag = names.NewStorageTag(args[0])
+	if c.sizeString == "" {
+		return errors.New("--size must be specified")
+	}
+	size, err := utils.ParseSize(c.sizeString)
+	if err != nil {
+		return errors.Annotate(err, "cannot parse size")
+	}
+	c.size = size
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *resizeCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "resize",
+		Args:    "<storage ID> --size <size>",
+		Purpose: "grow the storage assigned to a storage instance",
+		Doc:     resizeCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *resizeCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.StorageCommandBase.SetFlags(f)
+	f.StringVar(&c.sizeString, "size", "", "the new size of the storage")
+}
+
+// Run implements Command.Run.
+func (c *resizeCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.ResizeStorage([]params.StorageResizeArg{{
+		StorageTag: c.storageTag.String(),
+		Size:       c.size,
+	}})
+	if err != nil {
+		return err
+	}
+	if results[0].Error != nil {
+		return results[0].Error
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/storage"
+	"github.com/juju/juju/testing"
+)
+
+type resizeSuite struct {
+	SubStorageSuite
+	mockAPI *mockResizeAPI
+}
+
+var _ = gc.Suite(&resizeSuite{})
+
+func (s *resizeSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+	s.mockAPI = &mockResizeAPI{}
+}
+
+func (s *resizeSuite) TestResize(c *gc.C) {
+	_, err := testing.RunCommand(c, storage.NewResizeCommand(s.mockAPI), "data/0", "--size", "20G")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.resized, jc.DeepEquals, []params.StorageResizeArg{{
+		StorageTag: "storage-data-0",
+		Size:       20 * 1024,
+	}})
+}
+
+func (s *resizeSuite) TestResizeError(c *gc.C) {
+	s.mockAPI.err = &params.Error{Message: "storage can only be grown"}
+	_, err := testing.RunCommand(c, storage.NewResizeCommand(s.mockAPI), "data/0", "--size", "1024")
+	c.Assert(err, gc.ErrorMatches, "storage can only be grown")
+}
+
+func (s *resizeSuite) TestResizeInvalidArgs(c *gc.C) {
+	_, err := testing.RunCommand(c, storage.NewResizeCommand(s.mockAPI), "--size", "20G")
+	c.Assert(err, gc.ErrorMatches, "storage resize requires a storage ID")
+	_, err = testing.RunCommand(c, storage.NewResizeCommand(s.mockAPI), "data", "--size", "20G")
+	c.Assert(err, gc.ErrorMatches, `storage ID "data" not valid`)
+	_, err = testing.RunCommand(c, storage.NewResizeCommand(s.mockAPI), "data/0")
+	c.Assert(err, gc.ErrorMatches, "--size must be specified")
+	_, err = testing.RunCommand(c, storage.NewResizeCommand(s.mockAPI), "data/0", "--size", "lots")
+	c.Assert(err, gc.ErrorMatches, "cannot parse size: .*")
+}
+
+type mockResizeAPI struct {
+	resized []params.StorageResizeArg
+	err     *params.Error
+}
+
+func (s *mockResizeAPI) Close() error {
+	return nil
+}
+
+func (s *mockResizeAPI) ResizeStorage(storages []params.StorageResizeArg) ([]params.ErrorResult, error) {
+	s.resized = storages
+	return []params.ErrorResult{{Error: s.err}}, nil
+}
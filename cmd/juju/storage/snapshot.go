@@ -0,0 +1,103 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/common"
+)
+
+const snapshotCmdDoc = `
+"juju storage snapshot" is used to manage snapshots of storage
+ volumes in the Juju environment.
+`
+
+const snapshotCmdPurpose = "manage storage volume snapshots"
+
+// newSnapshotSuperCommand creates the storage snapshot super subcommand
+// and registers the subcommands that it supports.
+func newSnapshotSuperCommand() cmd.Command {
+	supercmd := jujucmd.NewSubSuperCommand(cmd.SuperCommandParams{
+		Name:        "snapshot",
+		Doc:         snapshotCmdDoc,
+		UsagePrefix: "juju storage",
+		Purpose:     snapshotCmdPurpose,
+	})
+	supercmd.Register(newSnapshotCreateCommand())
+	supercmd.Register(newSnapshotListCommand())
+	supercmd.Register(newSnapshotRemoveCommand())
+	supercmd.Register(newSnapshotRestoreCommand())
+	return supercmd
+}
+
+// SnapshotInfo defines the serialization behaviour for volume snapshots.
+type SnapshotInfo struct {
+	// Storage is the ID of the storage instance whose volume was
+	// snapshotted.
+	Storage string `yaml:"storage" json:"storage"`
+
+	// Volume is the ID of the snapshotted volume.
+	Volume string `yaml:"volume" json:"volume"`
+
+	// Pool is the storage pool of the snapshotted volume.
+	Pool string `yaml:"pool" json:"pool"`
+
+	// ProviderSnapshotId is the provider-supplied unique snapshot ID,
+	// which is empty until the snapshot has been taken.
+	ProviderSnapshotId string `yaml:"provider-id,omitempty" json:"provider-id,omitempty"`
+
+	// Size is the size of the snapshotted volume in MiB.
+	Size uint64 `yaml:"size,omitempty" json:"size,omitempty"`
+
+	// Life is the lifecycle state of the snapshot.
+	Life string `yaml:"life" json:"life"`
+
+	// Created is the time at which the snapshot was requested.
+	Created string `yaml:"created" json:"created"`
+
+	// Status is the status of the snapshot.
+	Status EntityStatus `yaml:"status,omitempty" json:"status,omitempty"`
+}
+
+// convertToSnapshotInfo returns a map of snapshot IDs to snapshot info.
+func convertToSnapshotInfo(all []params.VolumeSnapshotDetailsResult) (map[string]SnapshotInfo, error) {
+	result := make(map[string]SnapshotInfo)
+	for _, one := range all {
+		info, err := createSnapshotInfo(*one.Result)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result[one.Result.Id] = info
+	}
+	return result, nil
+}
+
+func createSnapshotInfo(details params.VolumeSnapshotDetails) (SnapshotInfo, error) {
+	storageTag, err := names.ParseStorageTag(details.StorageTag)
+	if err != nil {
+		return SnapshotInfo{}, errors.Trace(err)
+	}
+	volumeTag, err := names.ParseVolumeTag(details.VolumeTag)
+	if err != nil {
+		return SnapshotInfo{}, errors.Trace(err)
+	}
+	return SnapshotInfo{
+		Storage:            storageTag.Id(),
+		Volume:             volumeTag.Id(),
+		Pool:               details.Pool,
+		ProviderSnapshotId: details.SnapshotId,
+		Size:               details.Size,
+		Life:               string(details.Life),
+		Created:            common.FormatTime(&details.Created, false),
+		Status: EntityStatus{
+			Current: details.Status.Status,
+			Message: details.Status.Info,
+		},
+	}, nil
+}
@@ -0,0 +1,202 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	goyaml "gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/juju/storage"
+	"github.com/juju/juju/testing"
+)
+
+var expectedSnapshotCommmandNames = []string{
+	"create",
+	"help",
+	"list",
+	"remove",
+	"restore",
+}
+
+type snapshotSuite struct {
+	HelpStorageSuite
+}
+
+var _ = gc.Suite(&snapshotSuite{})
+
+func (s *snapshotSuite) TestSnapshotHelp(c *gc.C) {
+	s.command = storage.NewSnapshotSuperCommand()
+	s.assertHelp(c, expectedSnapshotCommmandNames)
+}
+
+type snapshotCommandsSuite struct {
+	SubStorageSuite
+	mockAPI *mockSnapshotAPI
+}
+
+var _ = gc.Suite(&snapshotCommandsSuite{})
+
+func (s *snapshotCommandsSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+	s.mockAPI = &mockSnapshotAPI{}
+}
+
+func (s *snapshotCommandsSuite) TestCreate(c *gc.C) {
+	context, err := testing.RunCommand(c, storage.NewSnapshotCreateCommand(s.mockAPI), "data/0", "data/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.created, jc.DeepEquals, []names.StorageTag{
+		names.NewStorageTag("data/0"),
+		names.NewStorageTag("data/1"),
+	})
+	c.Assert(testing.Stdout(context), gc.Equals, "0/0\n")
+	c.Assert(testing.Stderr(context), gc.Equals, "cannot snapshot storage data/1: volume not provisioned\n")
+}
+
+func (s *snapshotCommandsSuite) TestCreateInvalidArgs(c *gc.C) {
+	_, err := testing.RunCommand(c, storage.NewSnapshotCreateCommand(s.mockAPI))
+	c.Assert(err, gc.ErrorMatches, "storage snapshot create requires at least one storage ID")
+	_, err = testing.RunCommand(c, storage.NewSnapshotCreateCommand(s.mockAPI), "foo")
+	c.Assert(err, gc.ErrorMatches, `storage ID "foo" not valid`)
+}
+
+func (s *snapshotCommandsSuite) TestListYaml(c *gc.C) {
+	context, err := testing.RunCommand(c, storage.NewSnapshotListCommand(s.mockAPI), "--format", "yaml", "data/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.listed, jc.DeepEquals, []names.StorageTag{names.NewStorageTag("data/0")})
+
+	var result struct {
+		Snapshots map[string]storage.SnapshotInfo
+	}
+	err = goyaml.Unmarshal([]byte(testing.Stdout(context)), &result)
+	c.Assert(err, jc.ErrorIsNil)
+	created := snapshotCreated
+	c.Assert(result.Snapshots, jc.DeepEquals, map[string]storage.SnapshotInfo{
+		"0/0": {
+			Storage:            "data/0",
+			Volume:             "0/0",
+			Pool:               "loop",
+			ProviderSnapshotId: "snapshot-0-0",
+			Size:               1024,
+			Life:               "alive",
+			Created:            common.FormatTime(&created, false),
+			Status: storage.EntityStatus{
+				Current: params.StatusActive,
+			},
+		},
+	})
+	c.Assert(testing.Stderr(context), gc.Equals, "boom\n")
+}
+
+func (s *snapshotCommandsSuite) TestListTabular(c *gc.C) {
+	context, err := testing.RunCommand(c, storage.NewSnapshotListCommand(s.mockAPI))
+	c.Assert(err, jc.ErrorIsNil)
+	created := snapshotCreated
+	createdPad := strings.Repeat(" ", len(common.FormatTime(&created, false))-len("CREATED"))
+	c.Assert(testing.Stdout(context), gc.Equals, ""+
+		"ID   STORAGE  VOLUME  PROVIDER-ID   SIZE    LIFE   CREATED"+createdPad+"  STATE   MESSAGE\n"+
+		"0/0  data/0   0/0     snapshot-0-0  1.0GiB  alive  "+common.FormatTime(&created, false)+"  active  \n",
+	)
+}
+
+func (s *snapshotCommandsSuite) TestRemove(c *gc.C) {
+	context, err := testing.RunCommand(c, storage.NewSnapshotRemoveCommand(s.mockAPI), "0/0", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.removed, jc.DeepEquals, []string{"0/0", "1"})
+	c.Assert(testing.Stderr(context), gc.Equals, "cannot remove snapshot 1: volume snapshot \"1\" not found\n")
+
+	_, err = testing.RunCommand(c, storage.NewSnapshotRemoveCommand(s.mockAPI))
+	c.Assert(err, gc.ErrorMatches, "storage snapshot remove requires at least one snapshot ID")
+}
+
+func (s *snapshotCommandsSuite) TestRestore(c *gc.C) {
+	_, err := testing.RunCommand(c, storage.NewSnapshotRestoreCommand(s.mockAPI), "0/0", "u/0", "data")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.restored, jc.DeepEquals, []params.VolumeSnapshotRestoreParams{{
+		Id:          "0/0",
+		UnitTag:     "unit-u-0",
+		StorageName: "data",
+	}})
+
+	_, err = testing.RunCommand(c, storage.NewSnapshotRestoreCommand(s.mockAPI), "1", "u/0", "data")
+	c.Assert(err, gc.ErrorMatches, `volume snapshot "1" not found`)
+}
+
+func (s *snapshotCommandsSuite) TestRestoreInvalidArgs(c *gc.C) {
+	_, err := testing.RunCommand(c, storage.NewSnapshotRestoreCommand(s.mockAPI), "0/0", "u/0")
+	c.Assert(err, gc.ErrorMatches, "storage snapshot restore requires a snapshot ID, a unit and a storage name")
+	_, err = testing.RunCommand(c, storage.NewSnapshotRestoreCommand(s.mockAPI), "0/0", "u", "data")
+	c.Assert(err, gc.ErrorMatches, `unit name "u" not valid`)
+}
+
+var snapshotCreated = time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+
+type mockSnapshotAPI struct {
+	created  []names.StorageTag
+	listed   []names.StorageTag
+	removed  []string
+	restored []params.VolumeSnapshotRestoreParams
+}
+
+func (s *mockSnapshotAPI) Close() error {
+	return nil
+}
+
+func (s *mockSnapshotAPI) CreateVolumeSnapshots(tags []names.StorageTag) ([]params.VolumeSnapshotDetailsResult, error) {
+	s.created = tags
+	return []params.VolumeSnapshotDetailsResult{
+		{Result: &params.VolumeSnapshotDetails{Id: "0/0"}},
+		{Error: &params.Error{Message: "volume not provisioned"}},
+	}, nil
+}
+
+func (s *mockSnapshotAPI) ListVolumeSnapshots(tags []names.StorageTag) ([]params.VolumeSnapshotDetailsResult, error) {
+	s.listed = tags
+	results := []params.VolumeSnapshotDetailsResult{{
+		Result: &params.VolumeSnapshotDetails{
+			Id:         "0/0",
+			StorageTag: "storage-data-0",
+			VolumeTag:  "volume-0-0",
+			Pool:       "loop",
+			Life:       params.Alive,
+			Created:    snapshotCreated,
+			SnapshotId: "snapshot-0-0",
+			Size:       1024,
+			Status:     params.EntityStatus{Status: params.StatusActive},
+		},
+	}}
+	if len(tags) > 0 {
+		results = append(results, params.VolumeSnapshotDetailsResult{
+			Error: &params.Error{Message: "boom"},
+		})
+	}
+	return results, nil
+}
+
+func (s *mockSnapshotAPI) DestroyVolumeSnapshots(ids []string) ([]params.ErrorResult, error) {
+	s.removed = ids
+	results := make([]params.ErrorResult, len(ids))
+	for i, id := range ids {
+		if id != "0/0" {
+			results[i].Error = &params.Error{Message: `volume snapshot "` + id + `" not found`}
+		}
+	}
+	return results, nil
+}
+
+func (s *mockSnapshotAPI) RestoreVolumeSnapshots(snapshots []params.VolumeSnapshotRestoreParams) ([]params.ErrorResult, error) {
+	s.restored = snapshots
+	if snapshots[0].Id != "0/0" {
+		return []params.ErrorResult{{
+			Error: &params.Error{Message: `volume snapshot "1" not found`},
+		}}, nil
+	}
+	return []params.ErrorResult{{}}, nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// SnapshotCreateAPI defines the API methods that the snapshot create
+// command uses.
+type SnapshotCreateAPI interface {
+	Close() error
+	CreateVolumeSnapshots(tags []names.StorageTag) ([]params.VolumeSnapshotDetailsResult, error)
+}
+
+const snapshotCreateCommandDoc = `
+Request snapshots of the volumes assigned to the specified storage
+instances. Snapshots are taken asynchronously; use
+"juju storage snapshot list" to see when they are complete.
+
+The ID of each requested snapshot is printed, one per line.
+
+Example:
+    juju storage snapshot create data/0 data/1
+`
+
+func newSnapshotCreateCommand() cmd.Command {
+	cmd := &snapshotCreateCommand{}
+	cmd.newAPIFunc = func() (SnapshotCreateAPI, error) {
+		return cmd.NewStorageAPI()
+	}
+	return envcmd.Wrap(cmd)
+}
+
+// snapshotCreateCommand requests volume snapshots.
+type snapshotCreateCommand struct {
+	StorageCommandBase
+	storageTags []names.StorageTag
+	newAPIFunc  func() (SnapshotCreateAPI, error)
+}
+
+// Init implements Command.Init.
+func (c *snapshotCreateCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("storage snapshot create requires at least one storage ID")
+	}
+	c.storageTags = make([]names.StorageTag, len(args))
+	for i, arg := range args {
+		if !names.IsValidStorage(arg) {
+			return errors.NotValidf("storage ID %q", arg)
+		}
+		c.storageTags[i] = names.NewStorageTag(arg)
+	}
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *snapshotCreateCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "create",
+		Args:    "<storage ID> ...",
+		Purpose: "request snapshots of storage volumes",
+		Doc:     snapshotCreateCommandDoc,
+	}
+}
+
+// Run implements Command.Run.
+func (c *snapshotCreateCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.CreateVolumeSnapshots(c.storageTags)
+	if err != nil {
+		return err
+	}
+	for i, result := range results {
+		if result.Error != nil {
+			fmt.Fprintf(ctx.Stderr, "cannot snapshot storage %s: %v\n", c.storageTags[i].Id(), result.Error)
+			continue
+		}
+		fmt.Fprintln(ctx.Stdout, result.Result.Id)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// SnapshotListAPI defines the API methods that the snapshot list
+// command uses.
+type SnapshotListAPI interface {
+	Close() error
+	ListVolumeSnapshots(tags []names.StorageTag) ([]params.VolumeSnapshotDetailsResult, error)
+}
+
+const snapshotListCommandDoc = `
+List volume snapshots in the environment.
+
+options:
+-e, --environment (= "")
+    juju environment to operate in
+-o, --output (= "")
+    specify an output file
+[storage]
+    storage ids for filtering the list
+
+`
+
+func newSnapshotListCommand() cmd.Command {
+	cmd := &snapshotListCommand{}
+	cmd.newAPIFunc = func() (SnapshotListAPI, error) {
+		return cmd.NewStorageAPI()
+	}
+	return envcmd.Wrap(cmd)
+}
+
+// snapshotListCommand lists volume snapshots.
+type snapshotListCommand struct {
+	StorageCommandBase
+	storageTags []names.StorageTag
+	out         cmd.Output
+	newAPIFunc  func() (SnapshotListAPI, error)
+}
+
+// Init implements Command.Init.
+func (c *snapshotListCommand) Init(args []string) error {
+	for _, arg := range args {
+		if !names.IsValidStorage(arg) {
+			return errors.NotValidf("storage ID %q", arg)
+		}
+		c.storageTags = append(c.storageTags, names.NewStorageTag(arg))
+	}
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *snapshotListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list storage volume snapshots",
+		Doc:     snapshotListCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *snapshotListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.StorageCommandBase.SetFlags(f)
+
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatSnapshotListTabular,
+	})
+}
+
+// Run implements Command.Run.
+func (c *snapshotListCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	found, err := api.ListVolumeSnapshots(c.storageTags)
+	if err != nil {
+		return err
+	}
+	// filter out valid output, if any
+	var valid []params.VolumeSnapshotDetailsResult
+	for _, one := range found {
+		if one.Error == nil {
+			valid = append(valid, one)
+			continue
+		}
+		// display individual error
+		fmt.Fprintf(ctx.Stderr, "%v\n", one.Error)
+	}
+	if len(valid) == 0 {
+		return nil
+	}
+
+	info, err := convertToSnapshotInfo(valid)
+	if err != nil {
+		return err
+	}
+
+	var output interface{}
+	switch c.out.Name() {
+	case "json", "yaml":
+		output = map[string]map[string]SnapshotInfo{"snapshots": info}
+	default:
+		output = info
+	}
+	return c.out.Write(ctx, output)
+}
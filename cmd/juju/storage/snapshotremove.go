@@ -0,0 +1,83 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// SnapshotRemoveAPI defines the API methods that the snapshot remove
+// command uses.
+type SnapshotRemoveAPI interface {
+	Close() error
+	DestroyVolumeSnapshots(ids []string) ([]params.ErrorResult, error)
+}
+
+const snapshotRemoveCommandDoc = `
+Remove the volume snapshots with the specified IDs. Snapshots are
+deleted from the storage provider asynchronously.
+
+Example:
+    juju storage snapshot remove 0/0 1
+`
+
+func newSnapshotRemoveCommand() cmd.Command {
+	cmd := &snapshotRemoveCommand{}
+	cmd.newAPIFunc = func() (SnapshotRemoveAPI, error) {
+		return cmd.NewStorageAPI()
+	}
+	return envcmd.Wrap(cmd)
+}
+
+// snapshotRemoveCommand removes volume snapshots.
+type snapshotRemoveCommand struct {
+	StorageCommandBase
+	ids        []string
+	newAPIFunc func() (SnapshotRemoveAPI, error)
+}
+
+// Init implements Command.Init.
+func (c *snapshotRemoveCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("storage snapshot remove requires at least one snapshot ID")
+	}
+	c.ids = args
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *snapshotRemoveCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove",
+		Args:    "<snapshot ID> ...",
+		Purpose: "remove storage volume snapshots",
+		Doc:     snapshotRemoveCommandDoc,
+	}
+}
+
+// Run implements Command.Run.
+func (c *snapshotRemoveCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.DestroyVolumeSnapshots(c.ids)
+	if err != nil {
+		return err
+	}
+	for i, result := range results {
+		if result.Error != nil {
+			fmt.Fprintf(ctx.Stderr, "cannot remove snapshot %s: %v\n", c.ids[i], result.Error)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// SnapshotRestoreAPI defines the API methods that the snapshot restore
+// command uses.
+type SnapshotRestoreAPI interface {
+	Close() error
+	RestoreVolumeSnapshots(snapshots []params.VolumeSnapshotRestoreParams) ([]params.ErrorResult, error)
+}
+
+const snapshotRestoreCommandDoc = `
+Restore a volume snapshot by adding a new storage instance to a unit,
+with a volume created from the snapshot. The storage instance is
+created in the same pool as the snapshotted volume, and must be for
+block storage defined by the unit's charm.
+
+Snapshots of machine-scoped volumes, such as loop devices, can only
+be restored to units assigned to the same machine.
+
+Example:
+    Restore snapshot 0/0 as new "data" storage for unit u/0:
+
+      juju storage snapshot restore 0/0 u/0 data
+`
+
+func newSnapshotRestoreCommand() cmd.Command {
+	cmd := &snapshotRestoreCommand{}
+	cmd.newAPIFunc = func() (SnapshotRestoreAPI, error) {
+		return cmd.NewStorageAPI()
+	}
+	return envcmd.Wrap(cmd)
+}
+
+// snapshotRestoreCommand restores a volume snapshot as new unit storage.
+type snapshotRestoreCommand struct {
+	StorageCommandBase
+	id          string
+	unitTag     names.UnitTag
+	storageName string
+	newAPIFunc  func() (SnapshotRestoreAPI, error)
+}
+
+// Init implements Command.Init.
+func (c *snapshotRestoreCommand) Init(args []string) error {
+	if len(args) != 3 {
+		return errors.New("storage snapshot restore requires a snapshot ID, a unit and a storage name")
+	}
+	if !names.IsValidUnit(args[1]) {
+		return errors.NotValidf("unit name %q", args[1])
+	}
+	c.id = args[0]
+	c.unitTag = names.NewUnitTag(args[1])
+	c.storageName = args[2]
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *snapshotRestoreCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "restore",
+		Args:    "<snapshot ID> <unit name> <storage name>",
+		Purpose: "restore a storage volume snapshot",
+		Doc:     snapshotRestoreCommandDoc,
+	}
+}
+
+// Run implements Command.Run.
+func (c *snapshotRestoreCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.RestoreVolumeSnapshots([]params.VolumeSnapshotRestoreParams{{
+		Id:          c.id,
+		UnitTag:     c.unitTag.String(),
+		StorageName: c.storageName,
+	}})
+	if err != nil {
+		return err
+	}
+	if results[0].Error != nil {
+		return results[0].Error
+	}
+	return nil
+}
@@ -45,6 +45,10 @@ func NewSuperCommand() cmd.Command {
 	storagecmd.Register(newPoolSuperCommand())
 	storagecmd.Register(newVolumeSuperCommand())
 	storagecmd.Register(NewFilesystemSuperCommand())
+	storagecmd.Register(newSnapshotSuperCommand())
+	storagecmd.Register(newResizeCommand())
+	storagecmd.Register(newDetachCommand())
+	storagecmd.Register(newAttachCommand())
 	return storagecmd
 }
 
@@ -11,11 +11,15 @@ import (
 
 var expectedSubCommmandNames = []string{
 	"add",
+	"attach",
+	"detach",
 	"filesystem",
 	"help",
 	"list",
 	"pool",
+	"resize",
 	"show",
+	"snapshot",
 	"volume",
 }
 
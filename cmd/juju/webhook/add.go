@@ -0,0 +1,138 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhook
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+// AddAPI defines the API methods that the webhook add command uses.
+type AddAPI interface {
+	Close() error
+	AddWebhook(url string, kinds, statuses []string, secret string) (params.Webhook, error)
+}
+
+const addCommandDoc = `
+Add a webhook that receives changes to the environment as signed JSON
+POST requests. Each request body holds the environment UUID, the id of
+the webhook, and a list of deltas in the same form as the allwatcher:
+
+    {"env-uuid": "...", "webhook-id": "...",
+     "deltas": [["unit", "change", {...}], ...]}
+
+Each request carries an X-Juju-Timestamp header, holding the time it
+was sent in seconds since the Unix epoch, and an X-Juju-Signature
+header, holding "sha256=" followed by the hex-encoded HMAC-SHA256 of
+the timestamp, a ".", and the request body, keyed with the webhook's
+secret. Receivers should check the signature, and reject requests
+whose timestamp is more than a few minutes old so that captured
+requests cannot be replayed.
+
+By default, every change is delivered. Use --kinds to deliver only
+changes to entities of the given kinds (machine, service, unit,
+relation, action, annotation, block, payload), and --statuses to
+deliver only changes to entities with one of the given statuses. Unit
+changes match either their workload or their agent status.
+
+The id of the new webhook is displayed. If no secret is given, a
+random one is generated and displayed along with the id; it cannot be
+retrieved later.
+
+Only changes made while the webhook exists are delivered. Only a
+controller administrator may add a webhook, and its URL may not refer
+to a loopback or link-local address, nor to the controller itself.
+
+Examples:
+    Deliver all unit and machine errors to a ticketing system:
+
+      juju webhook add https://tickets.example.com/juju \
+          --kinds unit,machine --statuses error --secret s3cret
+`
+
+func newAddCommand() cmd.Command {
+	cmd := &addCommand{}
+	cmd.newAPIFunc = func() (AddAPI, error) {
+		return cmd.NewWebhooksAPI()
+	}
+	return envcmd.Wrap(cmd)
+}
+
+// addCommand adds a webhook.
+type addCommand struct {
+	WebhookCommandBase
+	url        string
+	kinds      string
+	statuses   string
+	secret     string
+	newAPIFunc func() (AddAPI, error)
+}
+
+// Info implements Command.Info.
+func (c *addCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add",
+		Args:    "<url>",
+		Purpose: "add a webhook",
+		Doc:     addCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *addCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.kinds, "kinds", "", "comma-separated entity kinds to deliver changes for")
+	f.StringVar(&c.statuses, "statuses", "", "comma-separated statuses to deliver changes for")
+	f.StringVar(&c.secret, "secret", "", "key used to sign deliveries")
+}
+
+// Init implements Command.Init.
+func (c *addCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no URL specified")
+	}
+	u, err := url.Parse(args[0])
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.NotValidf("URL %q", args[0])
+	}
+	c.url = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+var randomSecret = utils.RandomPassword
+
+// Run implements Command.Run.
+func (c *addCommand) Run(ctx *cmd.Context) error {
+	secret := c.secret
+	if secret == "" {
+		var err error
+		if secret, err = randomSecret(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	hook, err := api.AddWebhook(c.url, splitList(c.kinds), splitList(c.statuses), secret)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	fmt.Fprintf(ctx.Stdout, "id: %s\n", hook.Id)
+	if c.secret == "" {
+		fmt.Fprintf(ctx.Stdout, "secret: %s\n", secret)
+	}
+	return nil
+}
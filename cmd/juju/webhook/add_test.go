@@ -0,0 +1,104 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhook_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/webhook"
+	"github.com/juju/juju/testing"
+)
+
+type AddSuite struct {
+	testing.FakeJujuHomeSuite
+	api *mockAddAPI
+}
+
+var _ = gc.Suite(&AddSuite{})
+
+func (s *AddSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &mockAddAPI{}
+	s.PatchValue(webhook.RandomSecret, func() (string, error) {
+		return "generated", nil
+	})
+}
+
+func (s *AddSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "no URL specified",
+	}, {
+		args: []string{"ftp://example.com"},
+		err:  `URL "ftp://example.com" not valid`,
+	}, {
+		args: []string{"example.com"},
+		err:  `URL "example.com" not valid`,
+	}, {
+		args: []string{"https://example.com", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := testing.RunCommand(c, webhook.NewAddCommandWithAPI(s.api), test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *AddSuite) TestAdd(c *gc.C) {
+	ctx, err := testing.RunCommand(c, webhook.NewAddCommandWithAPI(s.api),
+		"https://example.com/hook", "--kinds", "unit, machine", "--statuses", "error", "--secret", "s3cret",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.url, gc.Equals, "https://example.com/hook")
+	c.Assert(s.api.kinds, jc.DeepEquals, []string{"unit", "machine"})
+	c.Assert(s.api.statuses, jc.DeepEquals, []string{"error"})
+	c.Assert(s.api.secret, gc.Equals, "s3cret")
+	c.Assert(testing.Stdout(ctx), gc.Equals, "id: hook-1\n")
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *AddSuite) TestAddGeneratesSecret(c *gc.C) {
+	ctx, err := testing.RunCommand(c, webhook.NewAddCommandWithAPI(s.api), "https://example.com/hook")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.kinds, gc.IsNil)
+	c.Assert(s.api.statuses, gc.IsNil)
+	c.Assert(s.api.secret, gc.Equals, "generated")
+	c.Assert(testing.Stdout(ctx), gc.Equals, "id: hook-1\nsecret: generated\n")
+}
+
+func (s *AddSuite) TestAddBlocked(c *gc.C) {
+	s.api.err = common.OperationBlockedError("TestAddBlocked")
+	_, err := testing.RunCommand(c, webhook.NewAddCommandWithAPI(s.api), "https://example.com/hook")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Check(c.GetTestLog(), jc.Contains, "TestAddBlocked")
+}
+
+type mockAddAPI struct {
+	url      string
+	kinds    []string
+	statuses []string
+	secret   string
+	err      error
+	closed   bool
+}
+
+func (api *mockAddAPI) Close() error {
+	api.closed = true
+	return nil
+}
+
+func (api *mockAddAPI) AddWebhook(url string, kinds, statuses []string, secret string) (params.Webhook, error) {
+	api.url, api.kinds, api.statuses, api.secret = url, kinds, statuses, secret
+	if api.err != nil {
+		return params.Webhook{}, api.err
+	}
+	return params.Webhook{Id: "hook-1", URL: url}, nil
+}
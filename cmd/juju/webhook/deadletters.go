@@ -0,0 +1,141 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// DeadLettersAPI defines the API methods that the webhook dead-letters
+// command uses.
+type DeadLettersAPI interface {
+	Close() error
+	DeadLetters(id string) ([]params.WebhookDeadLetter, error)
+}
+
+const deadLettersCommandDoc = `
+Show the deliveries that could not be made to a webhook, oldest first,
+along with the number of attempts made and the failure of the last
+attempt. Use --format yaml or --format json to see the undelivered
+request bodies.
+`
+
+func newDeadLettersCommand() cmd.Command {
+	cmd := &deadLettersCommand{}
+	cmd.newAPIFunc = func() (DeadLettersAPI, error) {
+		return cmd.NewWebhooksAPI()
+	}
+	return envcmd.Wrap(cmd)
+}
+
+// deadLettersCommand shows the dead letters of a webhook.
+type deadLettersCommand struct {
+	WebhookCommandBase
+	out        cmd.Output
+	id         string
+	newAPIFunc func() (DeadLettersAPI, error)
+}
+
+// Info implements Command.Info.
+func (c *deadLettersCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "dead-letters",
+		Args:    "<id>",
+		Purpose: "show deliveries that could not be made to a webhook",
+		Doc:     deadLettersCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *deadLettersCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatDeadLettersTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *deadLettersCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no webhook specified")
+	}
+	c.id = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *deadLettersCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	letters, err := api.DeadLetters(c.id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(letters) == 0 {
+		ctx.Infof("no dead letters for webhook %s", c.id)
+		return nil
+	}
+	output := make([]DeadLetterInfo, len(letters))
+	for i, letter := range letters {
+		output[i] = DeadLetterInfo{
+			Time:     letter.Time.UTC(),
+			Attempts: letter.Attempts,
+			Error:    letter.Error,
+			Payload:  letter.Payload,
+		}
+	}
+	return c.out.Write(ctx, output)
+}
+
+// DeadLetterInfo holds the details of an undelivered request.
+type DeadLetterInfo struct {
+	Time     time.Time `yaml:"time" json:"time"`
+	Attempts int       `yaml:"attempts" json:"attempts"`
+	Error    string    `yaml:"error" json:"error"`
+	Payload  string    `yaml:"payload" json:"payload"`
+}
+
+// formatDeadLettersTabular returns a tabular summary of dead letters,
+// without their payloads.
+func formatDeadLettersTabular(value interface{}) ([]byte, error) {
+	letters, ok := value.([]DeadLetterInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", letters, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	print := func(values ...string) {
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	print("TIME", "ATTEMPTS", "ERROR")
+	for _, letter := range letters {
+		print(letter.Time.Format(time.RFC3339), fmt.Sprint(letter.Attempts), letter.Error)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhook_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/webhook"
+	"github.com/juju/juju/testing"
+)
+
+type DeadLettersSuite struct {
+	testing.FakeJujuHomeSuite
+	api *mockDeadLettersAPI
+}
+
+var _ = gc.Suite(&DeadLettersSuite{})
+
+func (s *DeadLettersSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &mockDeadLettersAPI{
+		letters: []params.WebhookDeadLetter{{
+			WebhookId: "hook-1",
+			Payload:   `{"deltas":[]}`,
+			Attempts:  5,
+			Error:     "503 Service Unavailable",
+			Time:      time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC),
+		}},
+	}
+}
+
+func (s *DeadLettersSuite) run(c *gc.C, args ...string) (string, error) {
+	ctx, err := testing.RunCommand(c, webhook.NewDeadLettersCommandWithAPI(s.api), args...)
+	if err != nil {
+		return "", err
+	}
+	return testing.Stdout(ctx), nil
+}
+
+func (s *DeadLettersSuite) TestInitErrors(c *gc.C) {
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "no webhook specified")
+	_, err = s.run(c, "hook-1", "hook-2")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["hook-2"\]`)
+}
+
+func (s *DeadLettersSuite) TestDeadLettersTabular(c *gc.C) {
+	out, err := s.run(c, "hook-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.id, gc.Equals, "hook-1")
+	c.Assert(out, gc.Equals, ""+
+		"TIME                  ATTEMPTS  ERROR\n"+
+		"2015-10-01T12:00:00Z  5         503 Service Unavailable\n",
+	)
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *DeadLettersSuite) TestDeadLettersYAML(c *gc.C) {
+	out, err := s.run(c, "hook-1", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"- time: 2015-10-01T12:00:00Z\n"+
+		"  attempts: 5\n"+
+		"  error: 503 Service Unavailable\n"+
+		"  payload: '{\"deltas\":[]}'\n",
+	)
+}
+
+func (s *DeadLettersSuite) TestNoDeadLetters(c *gc.C) {
+	s.api.letters = nil
+	ctx, err := testing.RunCommand(c, webhook.NewDeadLettersCommandWithAPI(s.api), "hook-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "no dead letters for webhook hook-1\n")
+}
+
+type mockDeadLettersAPI struct {
+	letters []params.WebhookDeadLetter
+	id      string
+	closed  bool
+}
+
+func (api *mockDeadLettersAPI) Close() error {
+	api.closed = true
+	return nil
+}
+
+func (api *mockDeadLettersAPI) DeadLetters(id string) ([]params.WebhookDeadLetter, error) {
+	api.id = id
+	return api.letters, nil
+}
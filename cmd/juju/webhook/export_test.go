@@ -0,0 +1,40 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhook
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+var RandomSecret = &randomSecret
+
+func NewAddCommandWithAPI(api AddAPI) cmd.Command {
+	c := &addCommand{newAPIFunc: func() (AddAPI, error) {
+		return api, nil
+	}}
+	return envcmd.Wrap(c)
+}
+
+func NewListCommandWithAPI(api ListAPI) cmd.Command {
+	c := &listCommand{newAPIFunc: func() (ListAPI, error) {
+		return api, nil
+	}}
+	return envcmd.Wrap(c)
+}
+
+func NewRemoveCommandWithAPI(api RemoveAPI) cmd.Command {
+	c := &removeCommand{newAPIFunc: func() (RemoveAPI, error) {
+		return api, nil
+	}}
+	return envcmd.Wrap(c)
+}
+
+func NewDeadLettersCommandWithAPI(api DeadLettersAPI) cmd.Command {
+	c := &deadLettersCommand{newAPIFunc: func() (DeadLettersAPI, error) {
+		return api, nil
+	}}
+	return envcmd.Wrap(c)
+}
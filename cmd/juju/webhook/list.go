@@ -0,0 +1,141 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// ListAPI defines the API methods that the webhook list command uses.
+type ListAPI interface {
+	Close() error
+	ListWebhooks() ([]params.Webhook, error)
+}
+
+const listCommandDoc = `
+List the webhooks in the environment, oldest first, along with the
+entity kinds and statuses each is restricted to. Secrets are not shown.
+`
+
+func newListCommand() cmd.Command {
+	cmd := &listCommand{}
+	cmd.newAPIFunc = func() (ListAPI, error) {
+		return cmd.NewWebhooksAPI()
+	}
+	return envcmd.Wrap(cmd)
+}
+
+// listCommand lists the webhooks in the environment.
+type listCommand struct {
+	WebhookCommandBase
+	out        cmd.Output
+	newAPIFunc func() (ListAPI, error)
+}
+
+// Info implements Command.Info.
+func (c *listCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list webhooks",
+		Doc:     listCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatWebhooksTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *listCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *listCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	hooks, err := api.ListWebhooks()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(hooks) == 0 {
+		ctx.Infof("no webhooks to display")
+		return nil
+	}
+	output := make([]WebhookInfo, len(hooks))
+	for i, hook := range hooks {
+		output[i] = WebhookInfo{
+			Id:       hook.Id,
+			URL:      hook.URL,
+			Kinds:    hook.Kinds,
+			Statuses: hook.Statuses,
+			Owner:    hook.Owner,
+			Created:  hook.Created.UTC(),
+		}
+	}
+	return c.out.Write(ctx, output)
+}
+
+// WebhookInfo holds the details of a webhook.
+type WebhookInfo struct {
+	Id       string    `yaml:"id" json:"id"`
+	URL      string    `yaml:"url" json:"url"`
+	Kinds    []string  `yaml:"kinds,omitempty" json:"kinds,omitempty"`
+	Statuses []string  `yaml:"statuses,omitempty" json:"statuses,omitempty"`
+	Owner    string    `yaml:"owner" json:"owner"`
+	Created  time.Time `yaml:"created" json:"created"`
+}
+
+// formatWebhooksTabular returns a tabular summary of webhooks.
+func formatWebhooksTabular(value interface{}) ([]byte, error) {
+	hooks, ok := value.([]WebhookInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", hooks, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	print := func(values ...string) {
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	orAll := func(values []string) string {
+		if len(values) == 0 {
+			return "*"
+		}
+		return strings.Join(values, ",")
+	}
+	print("ID", "URL", "KINDS", "STATUSES", "OWNER", "CREATED")
+	for _, hook := range hooks {
+		print(hook.Id, hook.URL, orAll(hook.Kinds), orAll(hook.Statuses), hook.Owner, hook.Created.Format(time.RFC3339))
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
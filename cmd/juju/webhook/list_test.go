@@ -0,0 +1,103 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhook_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/webhook"
+	"github.com/juju/juju/testing"
+)
+
+type ListSuite struct {
+	testing.FakeJujuHomeSuite
+	api *mockListAPI
+}
+
+var _ = gc.Suite(&ListSuite{})
+
+func (s *ListSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	created := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.api = &mockListAPI{
+		hooks: []params.Webhook{{
+			Id:       "hook-1",
+			URL:      "https://example.com/tickets",
+			Kinds:    []string{"unit", "machine"},
+			Statuses: []string{"error"},
+			Owner:    "admin",
+			Created:  created,
+		}, {
+			Id:      "hook-2",
+			URL:     "https://example.com/chat",
+			Owner:   "bob",
+			Created: created.Add(time.Hour),
+		}},
+	}
+}
+
+func (s *ListSuite) run(c *gc.C, args ...string) (string, error) {
+	ctx, err := testing.RunCommand(c, webhook.NewListCommandWithAPI(s.api), args...)
+	if err != nil {
+		return "", err
+	}
+	return testing.Stdout(ctx), nil
+}
+
+func (s *ListSuite) TestListTabular(c *gc.C) {
+	out, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"ID      URL                          KINDS         STATUSES  OWNER  CREATED\n"+
+		"hook-1  https://example.com/tickets  unit,machine  error     admin  2015-10-01T12:00:00Z\n"+
+		"hook-2  https://example.com/chat     *             *         bob    2015-10-01T13:00:00Z\n",
+	)
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *ListSuite) TestListYAML(c *gc.C) {
+	out, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"- id: hook-1\n"+
+		"  url: https://example.com/tickets\n"+
+		"  kinds:\n"+
+		"  - unit\n"+
+		"  - machine\n"+
+		"  statuses:\n"+
+		"  - error\n"+
+		"  owner: admin\n"+
+		"  created: 2015-10-01T12:00:00Z\n"+
+		"- id: hook-2\n"+
+		"  url: https://example.com/chat\n"+
+		"  owner: bob\n"+
+		"  created: 2015-10-01T13:00:00Z\n",
+	)
+}
+
+func (s *ListSuite) TestListNoWebhooks(c *gc.C) {
+	s.api.hooks = nil
+	ctx, err := testing.RunCommand(c, webhook.NewListCommandWithAPI(s.api))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "no webhooks to display\n")
+}
+
+type mockListAPI struct {
+	hooks  []params.Webhook
+	closed bool
+}
+
+func (api *mockListAPI) Close() error {
+	api.closed = true
+	return nil
+}
+
+func (api *mockListAPI) ListWebhooks() ([]params.Webhook, error) {
+	return api.hooks, nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhook
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+// RemoveAPI defines the API methods that the webhook remove command
+// uses.
+type RemoveAPI interface {
+	Close() error
+	RemoveWebhooks(ids []string) ([]params.ErrorResult, error)
+}
+
+const removeCommandDoc = `
+Remove one or more webhooks, along with their dead letters. Only the
+owner of a webhook, or a controller administrator, may remove it.
+Deliveries already in progress may still be made.
+`
+
+func newRemoveCommand() cmd.Command {
+	cmd := &removeCommand{}
+	cmd.newAPIFunc = func() (RemoveAPI, error) {
+		return cmd.NewWebhooksAPI()
+	}
+	return envcmd.Wrap(cmd)
+}
+
+// removeCommand removes webhooks.
+type removeCommand struct {
+	WebhookCommandBase
+	ids        []string
+	newAPIFunc func() (RemoveAPI, error)
+}
+
+// Info implements Command.Info.
+func (c *removeCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove",
+		Args:    "<id> [...]",
+		Purpose: "remove webhooks",
+		Doc:     removeCommandDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *removeCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no webhook specified")
+	}
+	c.ids = args
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *removeCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	results, err := api.RemoveWebhooks(c.ids)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockRemove)
+	}
+	var failed bool
+	for i, result := range results {
+		if result.Error != nil {
+			fmt.Fprintf(ctx.Stderr, "webhook %s: %v\n", c.ids[i], result.Error)
+			failed = true
+			continue
+		}
+		ctx.Infof("removed webhook %s", c.ids[i])
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhook_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/webhook"
+	"github.com/juju/juju/testing"
+)
+
+type RemoveSuite struct {
+	testing.FakeJujuHomeSuite
+	api *mockRemoveAPI
+}
+
+var _ = gc.Suite(&RemoveSuite{})
+
+func (s *RemoveSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &mockRemoveAPI{}
+}
+
+func (s *RemoveSuite) TestInitErrors(c *gc.C) {
+	_, err := testing.RunCommand(c, webhook.NewRemoveCommandWithAPI(s.api))
+	c.Assert(err, gc.ErrorMatches, "no webhook specified")
+}
+
+func (s *RemoveSuite) TestRemove(c *gc.C) {
+	ctx, err := testing.RunCommand(c, webhook.NewRemoveCommandWithAPI(s.api), "hook-1", "hook-2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.removed, jc.DeepEquals, []string{"hook-1", "hook-2"})
+	c.Assert(testing.Stderr(ctx), gc.Equals, ""+
+		"removed webhook hook-1\n"+
+		"removed webhook hook-2\n",
+	)
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *RemoveSuite) TestRemoveErrors(c *gc.C) {
+	s.api.errors = []*params.Error{nil, common.ServerError(errors.NotFoundf(`webhook "hook-2"`))}
+	ctx, err := testing.RunCommand(c, webhook.NewRemoveCommandWithAPI(s.api), "hook-1", "hook-2")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(testing.Stderr(ctx), gc.Equals, ""+
+		"removed webhook hook-1\n"+
+		"webhook hook-2: webhook \"hook-2\" not found\n",
+	)
+}
+
+func (s *RemoveSuite) TestRemoveBlocked(c *gc.C) {
+	s.api.err = common.OperationBlockedError("TestRemoveBlocked")
+	_, err := testing.RunCommand(c, webhook.NewRemoveCommandWithAPI(s.api), "hook-1")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Check(c.GetTestLog(), jc.Contains, "TestRemoveBlocked")
+}
+
+type mockRemoveAPI struct {
+	removed []string
+	errors  []*params.Error
+	err     error
+	closed  bool
+}
+
+func (api *mockRemoveAPI) Close() error {
+	api.closed = true
+	return nil
+}
+
+func (api *mockRemoveAPI) RemoveWebhooks(ids []string) ([]params.ErrorResult, error) {
+	if api.err != nil {
+		return nil, api.err
+	}
+	api.removed = append(api.removed, ids...)
+	results := make([]params.ErrorResult, len(ids))
+	for i := range ids {
+		if i < len(api.errors) {
+			results[i].Error = api.errors[i]
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package webhook contains the commands used to manage the webhooks
+// that environment changes are delivered to.
+package webhook
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/webhooks"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const webhookCmdDoc = `
+"juju webhook" is used to manage webhooks. A webhook receives changes
+to the environment -- the same changes reported by the allwatcher -- as
+JSON POST requests, without having to hold a connection open to the
+API server.
+
+Each request is signed with the webhook's secret: the X-Juju-Signature
+header holds "sha256=" followed by the hex-encoded HMAC-SHA256 of the
+request body. Failed deliveries are retried with increasing delays;
+deliveries that still fail are recorded as dead letters, which can be
+inspected with "juju webhook dead-letters".
+`
+
+const webhookCmdPurpose = "manage webhooks that receive environment changes"
+
+// NewSuperCommand creates the webhook supercommand and registers the
+// subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	webhookcmd := cmd.NewSuperCommand(
+		cmd.SuperCommandParams{
+			Name:        "webhook",
+			Doc:         webhookCmdDoc,
+			UsagePrefix: "juju",
+			Purpose:     webhookCmdPurpose,
+		})
+	webhookcmd.Register(newAddCommand())
+	webhookcmd.Register(newListCommand())
+	webhookcmd.Register(newRemoveCommand())
+	webhookcmd.Register(newDeadLettersCommand())
+	return webhookcmd
+}
+
+// WebhookCommandBase is a helper base structure that has a method to
+// get the webhooks client.
+type WebhookCommandBase struct {
+	envcmd.EnvCommandBase
+}
+
+// NewWebhooksAPI returns a webhooks api for the root api endpoint that
+// the environment command returns.
+func (c *WebhookCommandBase) NewWebhooksAPI() (*webhooks.Client, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return webhooks.NewClient(root), nil
+}
+
+// splitList splits a comma-separated list, ignoring empty elements.
+func splitList(value string) []string {
+	var result []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
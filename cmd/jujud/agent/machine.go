@@ -36,11 +36,14 @@ import (
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/agent/tools"
 	"github.com/juju/juju/api"
+	apiactionscheduler "github.com/juju/juju/api/actionscheduler"
 	apideployer "github.com/juju/juju/api/deployer"
 	apilogsender "github.com/juju/juju/api/logsender"
+	apimachinedrainer "github.com/juju/juju/api/machinedrainer"
 	"github.com/juju/juju/api/metricsmanager"
 	"github.com/juju/juju/api/statushistory"
 	apiupgrader "github.com/juju/juju/api/upgrader"
+	apiwebhookdispatcher "github.com/juju/juju/api/webhookdispatcher"
 	"github.com/juju/juju/apiserver"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cert"
@@ -67,6 +70,7 @@ import (
 	"github.com/juju/juju/storage/looputil"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/actionscheduler"
 	"github.com/juju/juju/worker/addresser"
 	"github.com/juju/juju/worker/apiaddressupdater"
 	"github.com/juju/juju/worker/apicaller"
@@ -85,6 +89,7 @@ import (
 	"github.com/juju/juju/worker/localstorage"
 	workerlogger "github.com/juju/juju/worker/logger"
 	"github.com/juju/juju/worker/logsender"
+	"github.com/juju/juju/worker/machinedrainer"
 	"github.com/juju/juju/worker/machiner"
 	"github.com/juju/juju/worker/metricworker"
 	"github.com/juju/juju/worker/minunitsworker"
@@ -104,6 +109,7 @@ import (
 	"github.com/juju/juju/worker/unitassigner"
 	"github.com/juju/juju/worker/upgrader"
 	"github.com/juju/juju/worker/upgradesteps"
+	"github.com/juju/juju/worker/webhooks"
 )
 
 const bootstrapMachineId = "0"
@@ -1279,6 +1285,27 @@ func (a *MachineAgent) startEnvWorkers(
 	singularRunner.StartWorker("cleaner", func() (worker.Worker, error) {
 		return newCleaner(apiSt.Cleaner()), nil
 	})
+	singularRunner.StartWorker("machinedrainer", func() (worker.Worker, error) {
+		return machinedrainer.New(machinedrainer.Config{
+			Facade:             apimachinedrainer.NewFacade(apiSt),
+			Interval:           machinedrainer.DefaultDrainInterval,
+			ReplacementTimeout: machinedrainer.DefaultReplacementTimeout,
+			NewTimer:           worker.NewTimer,
+		})
+	})
+	singularRunner.StartWorker("webhooks", func() (worker.Worker, error) {
+		config := webhooks.DefaultConfig
+		config.Facade = apiwebhookdispatcher.NewFacade(apiSt)
+		config.EnvUUID = st.EnvironUUID()
+		return webhooks.New(config)
+	})
+	singularRunner.StartWorker("actionscheduler", func() (worker.Worker, error) {
+		return actionscheduler.New(actionscheduler.Config{
+			Facade:   apiactionscheduler.NewFacade(apiSt),
+			Interval: actionscheduler.DefaultInterval,
+			NewTimer: worker.NewTimer,
+		})
+	})
 	singularRunner.StartWorker("addresserworker", func() (worker.Worker, error) {
 		return newAddresser(apiSt.Addresser())
 	})
@@ -255,6 +255,9 @@ type MachineSuite struct {
 var perEnvSingularWorkers = []string{
 	"cleaner",
 	"minunitsworker",
+	"machinedrainer",
+	"actionscheduler",
+	"webhooks",
 	"addresserworker",
 	"environ-provisioner",
 	"charm-revision-updater",
@@ -2474,6 +2477,10 @@ func (m *mockMetricAPI) SendMetrics() error {
 	return nil
 }
 
+func (m *mockMetricAPI) EvaluateThresholds() error {
+	return nil
+}
+
 func (m *mockMetricAPI) SendCalled() <-chan struct{} {
 	return m.sendCalled
 }
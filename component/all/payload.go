@@ -8,7 +8,9 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6-unstable"
 
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/cmd/envcmd"
@@ -50,7 +52,56 @@ func (payloads) newPublicFacade(st *state.State, resources *common.Resources, au
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return server.NewPublicAPI(up), nil
+	return server.NewPublicAPI(&publicFacadeState{up, st}), nil
+}
+
+// publicFacadeState exposes the state functionality needed by the
+// public payloads facade.
+type publicFacadeState struct {
+	state.EnvPayloads
+	st *state.State
+}
+
+// StatusHistory implements server.EnvPayloads.
+func (fs *publicFacadeState) StatusHistory(unit, class, rawID string, size int) ([]payload.StatusEntry, error) {
+	history, err := fs.st.PayloadStatusHistory(unit, class, rawID, size)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	entries := make([]payload.StatusEntry, len(history))
+	for i, info := range history {
+		entries[i].Status = string(info.Status)
+		if info.Since != nil {
+			entries[i].Since = *info.Since
+		}
+	}
+	return entries, nil
+}
+
+// ActionSpecs implements server.EnvPayloads.
+func (fs *publicFacadeState) ActionSpecs(unitName string) (map[string]charm.ActionSpec, error) {
+	unit, err := fs.st.Unit(unitName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	specs, err := unit.ActionSpecs()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return specs, nil
+}
+
+// EnqueueAction implements server.EnvPayloads.
+func (fs *publicFacadeState) EnqueueAction(unitName, name string, params map[string]interface{}) (string, error) {
+	unit, err := fs.st.Unit(unitName)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	action, err := unit.AddAction(name, params)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return action.Id(), nil
 }
 
 func (c payloads) registerPublicFacade() {
@@ -70,18 +121,30 @@ func (c facadeCaller) Close() error {
 	return c.closeFunc()
 }
 
-func (payloads) newListAPIClient(cmd *status.ListCommand) (status.ListAPI, error) {
-	apiCaller, err := cmd.NewAPIRoot()
+func (payloads) newPublicClient(newAPIRoot func() (api.Connection, error)) (client.PublicClient, error) {
+	apiCaller, err := newAPIRoot()
 	if err != nil {
-		return nil, errors.Trace(err)
+		return client.PublicClient{}, errors.Trace(err)
 	}
 	caller := base.NewFacadeCallerForVersion(apiCaller, payload.ComponentName, 0)
 
-	listAPI := client.NewPublicClient(&facadeCaller{
+	publicClient := client.NewPublicClient(&facadeCaller{
 		FacadeCaller: caller,
 		closeFunc:    apiCaller.Close,
 	})
-	return listAPI, nil
+	return publicClient, nil
+}
+
+func (c payloads) newListAPIClient(cmd *status.ListCommand) (status.ListAPI, error) {
+	return c.newPublicClient(cmd.NewAPIRoot)
+}
+
+func (c payloads) newActAPIClient(cmd *status.ActCommand) (status.ActAPI, error) {
+	return c.newPublicClient(cmd.NewAPIRoot)
+}
+
+func (c payloads) newStatusHistoryAPIClient(cmd *status.StatusHistoryCommand) (status.StatusHistoryAPI, error) {
+	return c.newPublicClient(cmd.NewAPIRoot)
 }
 
 func (c payloads) registerPublicCommands() {
@@ -92,6 +155,15 @@ func (c payloads) registerPublicCommands() {
 	commands.RegisterEnvCommand(func() envcmd.EnvironCommand {
 		return status.NewListCommand(c.newListAPIClient)
 	})
+	for _, operation := range []string{payload.OperationStop, payload.OperationRestart} {
+		operation := operation
+		commands.RegisterEnvCommand(func() envcmd.EnvironCommand {
+			return status.NewActCommand(operation, c.newActAPIClient)
+		})
+	}
+	commands.RegisterEnvCommand(func() envcmd.EnvironCommand {
+		return status.NewStatusHistoryCommand(c.newStatusHistoryAPIClient)
+	})
 }
 
 func (c payloads) registerHookContext() {
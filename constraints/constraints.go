@@ -30,6 +30,21 @@ const (
 	InstanceType = "instance-type"
 	Networks     = "networks"
 	Spaces       = "spaces"
+	Placement    = "placement"
+	AntiAffinity = "anti-affinity"
+)
+
+// The following constants list the supported values for the placement
+// constraint attribute.
+const (
+	// PlacementSpread indicates that units of a service should be
+	// spread across as many machines as possible. This is the default.
+	PlacementSpread = "spread"
+
+	// PlacementPack indicates that units of a service should be packed
+	// onto as few machines as possible, reusing machines that already
+	// host other units.
+	PlacementPack = "pack"
 )
 
 // Value describes a user's requirements of the hardware on which units
@@ -88,6 +103,14 @@ type Value struct {
 	// TODO(dimitern): Drop this as soon as spaces can be used for
 	// deployments instead.
 	Networks *[]string `json:"networks,omitempty" yaml:"networks,omitempty"`
+
+	// Placement, if not nil or empty, indicates the policy used to choose
+	// machines for units of a service: either "spread" or "pack".
+	Placement *string `json:"placement,omitempty" yaml:"placement,omitempty"`
+
+	// AntiAffinity, if not nil, holds a list of services whose units must
+	// never share a machine with units of the constrained service.
+	AntiAffinity *[]string `json:"anti-affinity,omitempty" yaml:"anti-affinity,omitempty"`
 }
 
 // fieldNames records a mapping from the constraint tag to struct field name.
@@ -194,6 +217,12 @@ func (v *Value) HaveNetworks() bool {
 	return v.Networks != nil && len(*v.Networks) > 0
 }
 
+// HaveAntiAffinity returns whether any anti-affinity constraints
+// were specified.
+func (v *Value) HaveAntiAffinity() bool {
+	return v.AntiAffinity != nil && len(*v.AntiAffinity) > 0
+}
+
 // String expresses a constraints.Value in the language in which it was specified.
 func (v Value) String() string {
 	var strs []string
@@ -238,6 +267,13 @@ func (v Value) String() string {
 		s := strings.Join(*v.Networks, ",")
 		strs = append(strs, "networks="+s)
 	}
+	if v.Placement != nil {
+		strs = append(strs, "placement="+*v.Placement)
+	}
+	if v.AntiAffinity != nil {
+		s := strings.Join(*v.AntiAffinity, ",")
+		strs = append(strs, "anti-affinity="+s)
+	}
 	return strings.Join(strs, " ")
 }
 
@@ -281,6 +317,14 @@ func (v Value) GoString() string {
 	} else if v.Networks != nil {
 		values = append(values, "Networks: (*[]string)(nil)")
 	}
+	if v.Placement != nil {
+		values = append(values, fmt.Sprintf("Placement: %q", *v.Placement))
+	}
+	if v.AntiAffinity != nil && *v.AntiAffinity != nil {
+		values = append(values, fmt.Sprintf("AntiAffinity: %q", *v.AntiAffinity))
+	} else if v.AntiAffinity != nil {
+		values = append(values, "AntiAffinity: (*[]string)(nil)")
+	}
 	return fmt.Sprintf("{%s}", strings.Join(values, ", "))
 }
 
@@ -422,6 +466,10 @@ func (v *Value) setRaw(raw string) error {
 		err = v.setSpaces(str)
 	case Networks:
 		err = v.setNetworks(str)
+	case Placement:
+		err = v.setPlacement(str)
+	case AntiAffinity:
+		err = v.setAntiAffinity(str)
 	default:
 		return errors.Errorf("unknown constraint %q", name)
 	}
@@ -482,6 +530,21 @@ func (v *Value) UnmarshalYAML(unmarshal func(interface{}) error) error {
 			if err == nil {
 				v.Networks = networks
 			}
+		case Placement:
+			err = v.validatePlacement(vstr)
+			if err == nil {
+				v.Placement = &vstr
+			}
+		case AntiAffinity:
+			var services *[]string
+			services, err = parseYamlStrings("anti-affinity", val)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			err = v.validateAntiAffinity(services)
+			if err == nil {
+				v.AntiAffinity = services
+			}
 		default:
 			return errors.Errorf("unknown constraint value: %v", k)
 		}
@@ -623,6 +686,49 @@ func (v *Value) validateNetworks(networks *[]string) error {
 	return nil
 }
 
+func (v *Value) setPlacement(str string) error {
+	if v.Placement != nil {
+		return errors.Errorf("already set")
+	}
+	if err := v.validatePlacement(str); err != nil {
+		return err
+	}
+	v.Placement = &str
+	return nil
+}
+
+func (v *Value) validatePlacement(str string) error {
+	switch str {
+	case "", PlacementSpread, PlacementPack:
+		return nil
+	}
+	return errors.Errorf("%q not recognized", str)
+}
+
+func (v *Value) setAntiAffinity(str string) error {
+	if v.AntiAffinity != nil {
+		return errors.Errorf("already set")
+	}
+	services := parseCommaDelimited(str)
+	if err := v.validateAntiAffinity(services); err != nil {
+		return err
+	}
+	v.AntiAffinity = services
+	return nil
+}
+
+func (v *Value) validateAntiAffinity(services *[]string) error {
+	if services == nil {
+		return nil
+	}
+	for _, name := range *services {
+		if !names.IsValidService(name) {
+			return errors.Errorf("%q is not a valid service name", name)
+		}
+	}
+	return nil
+}
+
 func parseUint64(str string) (*uint64, error) {
 	var value uint64
 	if str != "" {
@@ -301,6 +301,42 @@ var parseConstraintsTests = []struct {
 		args:    []string{"networks="},
 	},
 
+	// "placement" in detail.
+	{
+		summary: "set placement empty",
+		args:    []string{"placement="},
+	}, {
+		summary: "set placement spread",
+		args:    []string{"placement=spread"},
+	}, {
+		summary: "set placement pack",
+		args:    []string{"placement=pack"},
+	}, {
+		summary: "set nonsense placement",
+		args:    []string{"placement=cheese"},
+		err:     `bad "placement" constraint: "cheese" not recognized`,
+	}, {
+		summary: "double set placement together",
+		args:    []string{"placement=pack placement=pack"},
+		err:     `bad "placement" constraint: already set`,
+	},
+
+	// anti-affinity
+	{
+		summary: "single anti-affinity service",
+		args:    []string{"anti-affinity=mysql"},
+	}, {
+		summary: "multiple anti-affinity services",
+		args:    []string{"anti-affinity=mysql,cassandra"},
+	}, {
+		summary: "no anti-affinity services",
+		args:    []string{"anti-affinity="},
+	}, {
+		summary: "invalid anti-affinity service",
+		args:    []string{"anti-affinity=mysql,Cassandra"},
+		err:     `bad "anti-affinity" constraint: "Cassandra" is not a valid service name`,
+	},
+
 	// instance type
 	{
 		summary: "set instance type",
@@ -536,6 +572,11 @@ var constraintsRoundtripTests = []roundTrip{
 	{"Networks3", constraints.Value{Networks: &[]string{"net1", "^net2"}}},
 	{"InstanceType1", constraints.Value{InstanceType: strp("")}},
 	{"InstanceType2", constraints.Value{InstanceType: strp("foo")}},
+	{"Placement1", constraints.Value{Placement: strp("")}},
+	{"Placement2", constraints.Value{Placement: strp("pack")}},
+	{"AntiAffinity1", constraints.Value{AntiAffinity: nil}},
+	{"AntiAffinity2", constraints.Value{AntiAffinity: &[]string{}}},
+	{"AntiAffinity3", constraints.Value{AntiAffinity: &[]string{"mysql", "cassandra"}}},
 	{"All", constraints.Value{
 		Arch:         strp("i386"),
 		Container:    ctypep("lxc"),
@@ -547,6 +588,8 @@ var constraintsRoundtripTests = []roundTrip{
 		Spaces:       &[]string{"space1", "^space2"},
 		Networks:     &[]string{"net1", "^net2"},
 		InstanceType: strp("foo"),
+		Placement:    strp("spread"),
+		AntiAffinity: &[]string{"mysql"},
 	}},
 }
 
@@ -97,6 +97,10 @@ const (
 	// config setting. Only non-zero, positive integer values will
 	// have effect.
 	DefaultLXCDefaultMTU = 0
+
+	// DefaultLogArchiveRetention is how long log archives are kept
+	// when "log-archive-retention" is not set.
+	DefaultLogArchiveRetention = 30 * 24 * time.Hour
 )
 
 // TODO(katco-): Please grow this over time.
@@ -184,6 +188,31 @@ const (
 	// IdentityPublicKey sets the public key of the identity manager.
 	IdentityPublicKey = "identity-public-key"
 
+	// MetricsSenderKey names the metric sender used to deliver
+	// unit metrics collected by the state server.
+	MetricsSenderKey = "metrics-sender"
+
+	// MetricsSenderURLKey is the target the metric sender delivers
+	// metrics to. Its form depends on the sender in use.
+	MetricsSenderURLKey = "metrics-sender-url"
+
+	// LogRetentionKey holds rules overriding how long the logs of
+	// particular modules and levels are kept in the database.
+	LogRetentionKey = "log-retention"
+
+	// LogQuotaMBKey, when set to a positive integer, limits the size
+	// in megabytes of the logs stored for the environment. The size
+	// of the stored logs is estimated from a sample of them.
+	LogQuotaMBKey = "log-quota-mb"
+
+	// LogArchiveKey specifies whether logs are archived before they
+	// are pruned from the database.
+	LogArchiveKey = "log-archive"
+
+	// LogArchiveRetentionKey specifies how long log archives are
+	// kept, judged by their newest records.
+	LogArchiveRetentionKey = "log-archive-retention"
+
 	//
 	// Deprecated Settings Attributes
 	//
@@ -612,6 +641,30 @@ func Validate(cfg, old *Config) error {
 
 	}
 
+	if _, ok := cfg.MetricsSender(); ok && cfg.MetricsSenderURL() == "" {
+		return fmt.Errorf("%s must be set when %s is set", MetricsSenderURLKey, MetricsSenderKey)
+	}
+
+	if v, ok := cfg.defined[LogRetentionKey].(string); ok {
+		if _, err := ParseLogRetention(v); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	if quota, ok := cfg.LogQuotaMB(); ok && quota <= 0 {
+		return fmt.Errorf("%s: expected positive integer, got %v", LogQuotaMBKey, quota)
+	}
+
+	if v, ok := cfg.defined[LogArchiveRetentionKey].(string); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("%s: %v", LogArchiveRetentionKey, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("%s: expected positive duration, got %v", LogArchiveRetentionKey, v)
+		}
+	}
+
 	if v, ok := cfg.defined[IdentityPublicKey].(string); ok {
 		var key bakery.PublicKey
 		if err := key.UnmarshalText([]byte(v)); err != nil {
@@ -1201,6 +1254,53 @@ func (c *Config) CloudImageBaseURL() string {
 	return c.asString(CloudImageBaseURL)
 }
 
+// MetricsSender returns the name of the metric sender used
+// to deliver unit metrics, and whether it has been set.
+func (c *Config) MetricsSender() (string, bool) {
+	v := c.asString(MetricsSenderKey)
+	return v, v != ""
+}
+
+// MetricsSenderURL returns the target the metric sender delivers
+// metrics to.
+func (c *Config) MetricsSenderURL() string {
+	return c.asString(MetricsSenderURLKey)
+}
+
+// LogRetention returns the rules overriding how long the logs of
+// particular modules and levels are kept. When several rules match a
+// log, the one naming the longest module wins, with rules naming a
+// level preferred over those that do not.
+func (c *Config) LogRetention() []LogRetentionRule {
+	// The rules were checked when the config was validated.
+	rules, _ := ParseLogRetention(c.asString(LogRetentionKey))
+	return rules
+}
+
+// LogQuotaMB returns the maximum size in megabytes of the logs stored
+// for the environment, and whether it has been set.
+func (c *Config) LogQuotaMB() (int, bool) {
+	v, ok := c.defined[LogQuotaMBKey].(int)
+	return v, ok
+}
+
+// LogArchive reports whether logs are archived before they are pruned
+// from the database.
+func (c *Config) LogArchive() bool {
+	v, _ := c.defined[LogArchiveKey].(bool)
+	return v
+}
+
+// LogArchiveRetention returns how long log archives are kept, judged
+// by their newest records.
+func (c *Config) LogArchiveRetention() time.Duration {
+	// The value has been validated as a positive duration when set.
+	if d, err := time.ParseDuration(c.asString(LogArchiveRetentionKey)); err == nil {
+		return d
+	}
+	return DefaultLogArchiveRetention
+}
+
 // ResourceTags returns a set of tags to set on environment resources
 // that Juju creates and manages, if the provider supports them. These
 // tags have no special meaning to Juju, but may be used for existing
@@ -1336,6 +1436,12 @@ var alwaysOptional = schema.Defaults{
 	AllowLXCLoopMounts:           false,
 	ResourceTagsKey:              schema.Omit,
 	CloudImageBaseURL:            schema.Omit,
+	MetricsSenderKey:             schema.Omit,
+	MetricsSenderURLKey:          schema.Omit,
+	LogRetentionKey:              schema.Omit,
+	LogQuotaMBKey:                schema.Omit,
+	LogArchiveKey:                schema.Omit,
+	LogArchiveRetentionKey:       schema.Omit,
 
 	// Storage related config.
 	// Environ providers will specify their own defaults.
@@ -1779,6 +1885,36 @@ global or per instance security groups.`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	MetricsSenderKey: {
+		Description: "The name of the sender used to deliver unit metrics, e.g. influxdb, graphite or file",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	MetricsSenderURLKey: {
+		Description: "The target of the metric sender: a URL for influxdb, a host:port address for graphite or, for file, the name of a file in the state server's metrics directory",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogRetentionKey: {
+		Description: "Comma separated rules overriding how long logs are kept, e.g. ERROR=720h,DEBUG=24h,juju.worker.uniter:DEBUG=2h",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogQuotaMBKey: {
+		Description: "The maximum size in megabytes of the logs stored for the environment, as estimated from the size of its recent logs",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	LogArchiveKey: {
+		Description: "Whether logs are archived to compressed files in environment storage before they are pruned",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
+	LogArchiveRetentionKey: {
+		Description: "How long log archives are kept after their newest record, e.g. 720h (the default)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	"logging-config": {
 		Description: `The configuration string to use when configuring Juju agent logging (see http://godoc.org/github.com/juju/loggo#ParseConfigurationString for details)`,
 		Type:        environschema.Tstring,
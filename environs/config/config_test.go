@@ -1763,6 +1763,90 @@ func (s *ConfigSuite) TestCloudImageBaseURLSet(c *gc.C) {
 	c.Assert(config.CloudImageBaseURL(), gc.Equals, "http://local.foo/query")
 }
 
+func (s *ConfigSuite) TestMetricsSender(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{})
+	_, ok := cfg.MetricsSender()
+	c.Assert(ok, jc.IsFalse)
+	c.Assert(cfg.MetricsSenderURL(), gc.Equals, "")
+}
+
+func (s *ConfigSuite) TestMetricsSenderSet(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{
+		"metrics-sender":     "graphite",
+		"metrics-sender-url": "localhost:2003",
+	})
+	sender, ok := cfg.MetricsSender()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(sender, gc.Equals, "graphite")
+	c.Assert(cfg.MetricsSenderURL(), gc.Equals, "localhost:2003")
+}
+
+func (s *ConfigSuite) TestMetricsSenderWithoutURL(c *gc.C) {
+	s.addJujuFiles(c)
+	_, err := config.New(config.UseDefaults, testing.Attrs{
+		"type":           "my-type",
+		"name":           "my-name",
+		"metrics-sender": "graphite",
+	})
+	c.Assert(err, gc.ErrorMatches, "metrics-sender-url must be set when metrics-sender is set")
+}
+
+func (s *ConfigSuite) TestLogRetention(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.LogRetention(), gc.HasLen, 0)
+	_, ok := cfg.LogQuotaMB()
+	c.Assert(ok, jc.IsFalse)
+	c.Assert(cfg.LogArchive(), jc.IsFalse)
+	c.Assert(cfg.LogArchiveRetention(), gc.Equals, config.DefaultLogArchiveRetention)
+}
+
+func (s *ConfigSuite) TestLogRetentionSet(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{
+		"log-retention":         "ERROR=720h, juju.worker:DEBUG=2h",
+		"log-quota-mb":          100,
+		"log-archive":           true,
+		"log-archive-retention": "168h",
+	})
+	c.Assert(cfg.LogRetention(), jc.DeepEquals, []config.LogRetentionRule{
+		{Level: loggo.ERROR, MaxAge: 720 * time.Hour},
+		{Module: "juju.worker", Level: loggo.DEBUG, MaxAge: 2 * time.Hour},
+	})
+	quota, ok := cfg.LogQuotaMB()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(quota, gc.Equals, 100)
+	c.Assert(cfg.LogArchive(), jc.IsTrue)
+	c.Assert(cfg.LogArchiveRetention(), gc.Equals, 168*time.Hour)
+}
+
+func (s *ConfigSuite) TestLogRetentionInvalid(c *gc.C) {
+	s.addJujuFiles(c)
+	for i, test := range []struct {
+		attrs testing.Attrs
+		err   string
+	}{{
+		attrs: testing.Attrs{"log-retention": "ERROR"},
+		err:   `invalid log retention rule "ERROR": expected <selector>=<duration>`,
+	}, {
+		attrs: testing.Attrs{"log-quota-mb": -1},
+		err:   "log-quota-mb: expected positive integer, got -1",
+	}, {
+		attrs: testing.Attrs{"log-archive-retention": "forever"},
+		err:   `log-archive-retention: time: invalid duration "?forever"?`,
+	}, {
+		attrs: testing.Attrs{"log-archive-retention": "-1h"},
+		err:   "log-archive-retention: expected positive duration, got -1h",
+	}} {
+		c.Logf("test %d: %v", i, test.attrs)
+		attrs := testing.Attrs{"type": "my-type", "name": "my-name"}.Merge(test.attrs)
+		_, err := config.New(config.UseDefaults, attrs)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
 func (s *ConfigSuite) TestProxyValuesWithFallback(c *gc.C) {
 	s.addJujuFiles(c)
 
@@ -0,0 +1,91 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+)
+
+// LogRetentionRule specifies how long the logs of a module and/or
+// level are kept before they are pruned.
+type LogRetentionRule struct {
+	// Module, when set, restricts the rule to logs from the module
+	// and its submodules.
+	Module string
+
+	// Level, when set, restricts the rule to logs of that level.
+	Level loggo.Level
+
+	// MaxAge is how long matching logs are kept.
+	MaxAge time.Duration
+}
+
+// ParseLogRetention parses a comma separated list of log retention
+// rules of the form <selector>=<duration>, where the selector is a
+// level (e.g. ERROR), a module (e.g. juju.worker.uniter) or both
+// (e.g. juju.worker.uniter:DEBUG), and the duration is a Go duration
+// such as 720h. For example:
+//
+//	ERROR=720h,DEBUG=24h,juju.worker.uniter:DEBUG=2h
+func ParseLogRetention(value string) ([]LogRetentionRule, error) {
+	var rules []LogRetentionRule
+	seen := make(map[string]bool)
+	for _, spec := range strings.Split(value, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		parts := strings.SplitN(spec, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid log retention rule %q: expected <selector>=<duration>", spec)
+		}
+		selector := strings.TrimSpace(parts[0])
+		rule, err := parseLogRetentionSelector(selector)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid log retention rule %q", spec)
+		}
+		rule.MaxAge, err = time.ParseDuration(strings.TrimSpace(parts[1]))
+		if err != nil || rule.MaxAge <= 0 {
+			return nil, errors.Errorf("invalid log retention rule %q: expected a positive duration such as 24h", spec)
+		}
+		key := rule.Module + ":" + rule.Level.String()
+		if seen[key] {
+			return nil, errors.Errorf("duplicate log retention rule for %q", selector)
+		}
+		seen[key] = true
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// parseLogRetentionSelector returns a rule holding the module and
+// level named by the given selector.
+func parseLogRetentionSelector(selector string) (LogRetentionRule, error) {
+	var rule LogRetentionRule
+	module, levelName := selector, ""
+	if i := strings.LastIndex(selector, ":"); i >= 0 {
+		module, levelName = selector[:i], selector[i+1:]
+		if module == "" {
+			return rule, errors.New("missing module")
+		}
+	} else if _, ok := loggo.ParseLevel(selector); ok {
+		module, levelName = "", selector
+	}
+	if levelName != "" {
+		level, ok := loggo.ParseLevel(levelName)
+		if !ok || level == loggo.UNSPECIFIED {
+			return rule, errors.Errorf("unknown level %q", levelName)
+		}
+		rule.Level = level
+	}
+	if module == "" && rule.Level == loggo.UNSPECIFIED {
+		return rule, errors.New("missing module or level")
+	}
+	rule.Module = module
+	return rule, nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config_test
+
+import (
+	"time"
+
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/testing"
+)
+
+type LogRetentionSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&LogRetentionSuite{})
+
+func (s *LogRetentionSuite) TestParseLogRetention(c *gc.C) {
+	rules, err := config.ParseLogRetention(" ERROR=720h, debug=24h,juju.worker.uniter=48h,,juju.provisioner:WARNING=1h30m")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []config.LogRetentionRule{
+		{Level: loggo.ERROR, MaxAge: 720 * time.Hour},
+		{Level: loggo.DEBUG, MaxAge: 24 * time.Hour},
+		{Module: "juju.worker.uniter", MaxAge: 48 * time.Hour},
+		{Module: "juju.provisioner", Level: loggo.WARNING, MaxAge: 90 * time.Minute},
+	})
+}
+
+func (s *LogRetentionSuite) TestParseLogRetentionEmpty(c *gc.C) {
+	rules, err := config.ParseLogRetention("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+}
+
+func (s *LogRetentionSuite) TestParseLogRetentionErrors(c *gc.C) {
+	for i, test := range []struct {
+		value string
+		err   string
+	}{{
+		value: "ERROR",
+		err:   `invalid log retention rule "ERROR": expected <selector>=<duration>`,
+	}, {
+		value: "=24h",
+		err:   `invalid log retention rule "=24h": missing module or level`,
+	}, {
+		value: ":ERROR=24h",
+		err:   `invalid log retention rule ":ERROR=24h": missing module`,
+	}, {
+		value: "juju:LOUD=24h",
+		err:   `invalid log retention rule "juju:LOUD=24h": unknown level "LOUD"`,
+	}, {
+		value: "ERROR=forever",
+		err:   `invalid log retention rule "ERROR=forever": expected a positive duration such as 24h`,
+	}, {
+		value: "ERROR=-1h",
+		err:   `invalid log retention rule "ERROR=-1h": expected a positive duration such as 24h`,
+	}, {
+		value: "ERROR=24h,error=48h",
+		err:   `duplicate log retention rule for "error"`,
+	}} {
+		c.Logf("test %d: %q", i, test.value)
+		_, err := config.ParseLogRetention(test.value)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
@@ -179,6 +179,34 @@ func AddUnitsWithPlacement(st *state.State, svc *state.Service, n int, placement
 	return units, nil
 }
 
+// AddUnitAttachingStorage starts a unit of the given service, attaching
+// the specified storage instances that were detached from other units of
+// the service, and allocates a machine to it using the specified placement
+// directive, if any.
+func AddUnitAttachingStorage(
+	st *state.State, svc *state.Service,
+	placement *instance.Placement, storageTags []names.StorageTag,
+) (*state.Unit, error) {
+	networks, err := svc.Networks()
+	if err != nil {
+		return nil, errors.Errorf("cannot get service %q networks", svc.Name())
+	}
+	unit, err := svc.AddUnitAttachingStorage(storageTags)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if placement == nil {
+		if err := st.AssignUnit(unit, state.AssignCleanEmpty); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return unit, nil
+	}
+	if err := st.AssignUnitWithPlacement(unit, placement, networks); err != nil {
+		return nil, errors.Annotatef(err, "adding new machine to host unit %q", unit.Name())
+	}
+	return unit, nil
+}
+
 func stateStorageConstraints(cons map[string]storage.Constraints) map[string]state.StorageConstraints {
 	result := make(map[string]state.StorageConstraints)
 	for name, cons := range cons {
@@ -58,3 +58,34 @@ type Checker interface {
 	// verify the unit's continued leadership as part of another txn.
 	LeadershipCheck(serviceName, unitName string) Token
 }
+
+// Lease describes a unit's leadership of a service.
+type Lease struct {
+
+	// Holder is the name of the unit that holds leadership.
+	Holder string
+
+	// Expiry is the latest time at which the unit's leadership might
+	// still be valid.
+	Expiry time.Time
+}
+
+// Inspector exposes leadership inspection capabilities.
+type Inspector interface {
+
+	// Leases returns the current leadership of every service that has a
+	// leader, keyed on service name.
+	Leases() (map[string]Lease, error)
+}
+
+// Revoker exposes leadership revocation capabilities.
+type Revoker interface {
+
+	// RevokeLeadership forcibly ends the current leadership of the named
+	// service, so that another unit can take over. No unit, including the
+	// revoked one, will be able to claim leadership until the revoked
+	// lease would have expired, so that two units never believe
+	// themselves to be leader at once. If the service has no leader, an
+	// error satisfying errors.IsNotFound is returned.
+	RevokeLeadership(serviceId string) error
+}
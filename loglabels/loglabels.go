@@ -0,0 +1,185 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package loglabels attaches structured labels, such as the name of the
+// hook being run, to the log records that agents send to the API server,
+// so that logs can be filtered by label rather than by matching text.
+//
+// Labels are attached by logging through a Logger created with them.
+// They are kept apart from the message, so they never appear in local
+// log files and cannot be forged by text that is logged; writers that
+// record labels, such as the agents' log sender, call Attached to find
+// the labels of the record they are writing.
+package loglabels
+
+import (
+	"fmt"
+	"regexp"
+	"runtime"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+)
+
+// The names of the labels set by Juju itself.
+const (
+	// Hook holds the name of the hook being run by a unit agent.
+	Hook = "hook"
+
+	// Action holds the id of the action being run by a unit agent.
+	Action = "action"
+
+	// Relation holds the id of the relation whose hook is being run.
+	Relation = "relation"
+
+	// Request holds the id of the API request being served, as
+	// "<connection>:<request>".
+	Request = "request"
+)
+
+var validName = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// ValidateName returns an error if the given label name is not valid.
+// Label names are made of lower case letters, digits and hyphens, and
+// start with a letter.
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return errors.NotValidf("label name %q", name)
+	}
+	return nil
+}
+
+// Logger logs messages through a loggo.Logger with a fixed set of
+// labels attached to each record.
+type Logger struct {
+	logger loggo.Logger
+	labels map[string]string
+}
+
+// NewLogger returns a Logger that logs through the given logger,
+// attaching the given labels to every record. Labels with invalid
+// names are ignored.
+func NewLogger(logger loggo.Logger, labels map[string]string) Logger {
+	valid := make(map[string]string)
+	for name, value := range labels {
+		if ValidateName(name) == nil {
+			valid[name] = value
+		}
+	}
+	return Logger{
+		logger: logger,
+		labels: valid,
+	}
+}
+
+// Logf logs a message at the given level, as Logf on the underlying
+// logger would, with the Logger's labels attached.
+func (l Logger) Logf(level loggo.Level, message string, args ...interface{}) {
+	l.logf(level, message, args)
+}
+
+// Errorf logs a message at the ERROR level.
+func (l Logger) Errorf(message string, args ...interface{}) {
+	l.logf(loggo.ERROR, message, args)
+}
+
+// Warningf logs a message at the WARNING level.
+func (l Logger) Warningf(message string, args ...interface{}) {
+	l.logf(loggo.WARNING, message, args)
+}
+
+// Infof logs a message at the INFO level.
+func (l Logger) Infof(message string, args ...interface{}) {
+	l.logf(loggo.INFO, message, args)
+}
+
+// Debugf logs a message at the DEBUG level.
+func (l Logger) Debugf(message string, args ...interface{}) {
+	l.logf(loggo.DEBUG, message, args)
+}
+
+// Tracef logs a message at the TRACE level.
+func (l Logger) Tracef(message string, args ...interface{}) {
+	l.logf(loggo.TRACE, message, args)
+}
+
+func (l Logger) logf(level loggo.Level, message string, args []interface{}) {
+	if !l.logger.IsLevelEnabled(level) {
+		return
+	}
+	message = fmt.Sprintf(message, args...)
+	// The caller of the exported method is two frames above logf,
+	// and three frames above LogCallf, so that is the location
+	// recorded.
+	_, filename, line, ok := runtime.Caller(2)
+	if !ok || len(l.labels) == 0 {
+		l.logger.LogCallf(3, level, "%s", message)
+		return
+	}
+
+	// Loggo calls its writers before LogCallf returns, so the
+	// labels need only be available while the record is written.
+	writing.Lock()
+	defer writing.Unlock()
+	setCurrent(&record{
+		module:   l.logger.Name(),
+		filename: filename,
+		line:     line,
+		message:  message,
+		labels:   l.labels,
+	})
+	defer setCurrent(nil)
+	l.logger.LogCallf(3, level, "%s", message)
+}
+
+// record identifies a record being written by a Logger, along with
+// the labels attached to it.
+type record struct {
+	module   string
+	filename string
+	line     int
+	message  string
+	labels   map[string]string
+}
+
+var (
+	// writing is held while a Logger writes a labelled record, so
+	// that only one is being written at a time.
+	writing sync.Mutex
+
+	// currentMutex guards current, which holds the labelled record
+	// being written, if any.
+	currentMutex sync.Mutex
+	current      *record
+)
+
+func setCurrent(r *record) {
+	currentMutex.Lock()
+	current = r
+	currentMutex.Unlock()
+}
+
+// Attached returns the labels attached by a Logger to the log record
+// with the given fields. It is intended to be called by a loggo.Writer
+// from its Write method; if the record being written was not logged
+// by a Logger with labels, Attached returns nil.
+func Attached(module, filename string, line int, message string) map[string]string {
+	currentMutex.Lock()
+	defer currentMutex.Unlock()
+	// Unlabelled records may be written by other goroutines while
+	// a labelled record is being written, so the record must match
+	// exactly; it cannot, as it comes from a different call site.
+	if current == nil ||
+		current.module != module ||
+		current.filename != filename ||
+		current.line != line ||
+		current.message != message {
+		return nil
+	}
+	labels := make(map[string]string, len(current.labels))
+	for name, value := range current.labels {
+		labels[name] = value
+	}
+	return labels
+}
@@ -0,0 +1,101 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package loglabels_test
+
+import (
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/loglabels"
+)
+
+type labelsSuite struct {
+	writer *loglabels.TestWriter
+}
+
+var _ = gc.Suite(&labelsSuite{})
+
+func (s *labelsSuite) SetUpTest(c *gc.C) {
+	loggo.ResetLoggers()
+	loggo.ResetWriters()
+	err := loggo.ConfigureLoggers(`<root>=ERROR; test=DEBUG`)
+	c.Assert(err, jc.ErrorIsNil)
+	s.writer = &loglabels.TestWriter{}
+	err = loggo.RegisterWriter("test", s.writer, loggo.TRACE)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (*labelsSuite) TearDownTest(c *gc.C) {
+	loggo.ResetLoggers()
+	loggo.ResetWriters()
+}
+
+func (s *labelsSuite) TestLogger(c *gc.C) {
+	logger := loglabels.NewLogger(loggo.GetLogger("test.module"), map[string]string{
+		"relation": "db:1",
+		"hook":     "install",
+	})
+	logger.Logf(loggo.INFO, "hello %s", "world")
+	logger.Warningf("goodbye")
+
+	labels := map[string]string{"hook": "install", "relation": "db:1"}
+	log := s.writer.Log()
+	c.Assert(log, gc.HasLen, 2)
+	c.Assert(log[0].Module, gc.Equals, "test.module")
+	c.Assert(log[0].Level, gc.Equals, loggo.INFO)
+	c.Assert(log[0].Message, gc.Equals, "hello world")
+	c.Assert(log[0].Filename, gc.Matches, ".*loglabels_test.go")
+	c.Assert(s.writer.Labels()[0], jc.DeepEquals, labels)
+	c.Assert(log[1].Level, gc.Equals, loggo.WARNING)
+	c.Assert(log[1].Message, gc.Equals, "goodbye")
+	c.Assert(s.writer.Labels()[1], jc.DeepEquals, labels)
+}
+
+func (s *labelsSuite) TestLoggerNoLabels(c *gc.C) {
+	logger := loglabels.NewLogger(loggo.GetLogger("test.module"), nil)
+	logger.Infof("hello")
+	c.Assert(s.writer.Log(), gc.HasLen, 1)
+	c.Assert(s.writer.Log()[0].Message, gc.Equals, "hello")
+	c.Assert(s.writer.Labels()[0], gc.IsNil)
+}
+
+func (s *labelsSuite) TestLoggerIgnoresInvalidNames(c *gc.C) {
+	logger := loglabels.NewLogger(loggo.GetLogger("test.module"), map[string]string{
+		"Hook": "install",
+		"f.x":  "y",
+		"hook": "install",
+	})
+	logger.Infof("hello")
+	c.Assert(s.writer.Log()[0].Message, gc.Equals, "hello")
+	c.Assert(s.writer.Labels()[0], jc.DeepEquals, map[string]string{"hook": "install"})
+}
+
+func (s *labelsSuite) TestLoggerLevelDisabled(c *gc.C) {
+	logger := loglabels.NewLogger(loggo.GetLogger("test.module"), map[string]string{"hook": "install"})
+	logger.Tracef("hello")
+	c.Assert(s.writer.Log(), gc.HasLen, 0)
+}
+
+func (s *labelsSuite) TestUnlabelledLoggerNotAttached(c *gc.C) {
+	// Text that looks like labels is logged as is, and is not
+	// taken to be labels.
+	loggo.GetLogger("test.module").Infof(`[hook="install"] hello`)
+	c.Assert(s.writer.Log()[0].Message, gc.Equals, `[hook="install"] hello`)
+	c.Assert(s.writer.Labels()[0], gc.IsNil)
+}
+
+func (*labelsSuite) TestAttachedOutsideWrite(c *gc.C) {
+	labels := loglabels.Attached("test.module", "loglabels_test.go", 1, "hello")
+	c.Assert(labels, gc.IsNil)
+}
+
+func (*labelsSuite) TestValidateName(c *gc.C) {
+	for _, name := range []string{"hook", "request-id", "a1"} {
+		c.Check(loglabels.ValidateName(name), jc.ErrorIsNil)
+	}
+	for _, name := range []string{"", "Hook", "1a", "f.hook", "$where", "-a"} {
+		c.Check(loglabels.ValidateName(name), gc.ErrorMatches, `label name ".*" not valid`)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package loglabels
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/loggo"
+)
+
+// TestWriter is a loggo.Writer that records the labels attached to
+// each record it writes, along with the record itself. It is used in
+// tests of code that logs through a Logger.
+type TestWriter struct {
+	loggo.TestWriter
+
+	mu     sync.Mutex
+	labels []map[string]string
+}
+
+// Write records the labels attached to the record, then writes the
+// record with the embedded loggo.TestWriter.
+func (w *TestWriter) Write(level loggo.Level, module, filename string, line int, timestamp time.Time, message string) {
+	w.mu.Lock()
+	w.labels = append(w.labels, Attached(module, filename, line, message))
+	w.mu.Unlock()
+	w.TestWriter.Write(level, module, filename, line, timestamp, message)
+}
+
+// Labels returns the labels attached to each record written, in the
+// same order as the records returned by Log.
+func (w *TestWriter) Labels() []map[string]string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]map[string]string(nil), w.labels...)
+}
@@ -160,6 +160,17 @@ func isIPv6UniqueLocalAddress(addrType AddressType, ip net.IP) bool {
 	return ipv6UniqueLocal.Contains(ip)
 }
 
+// IsLocalIP reports whether the IP address is a loopback, link-local
+// or unspecified address, and so refers to the host itself or its
+// local link rather than to a routable host.
+func IsLocalIP(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsUnspecified()
+}
+
 // deriveScope attempts to derive the network scope from an address's
 // type and value, returning the original network scope if no
 // deduction can be made.
@@ -116,6 +116,28 @@ func (s *AddressSuite) TestNewScopedAddressIPv6(c *gc.C) {
 	}
 }
 
+func (s *AddressSuite) TestIsLocalIP(c *gc.C) {
+	for i, test := range []struct {
+		value string
+		local bool
+	}{
+		{"127.0.0.1", true},
+		{"127.1.2.3", true},
+		{"169.254.169.254", true},
+		{"0.0.0.0", true},
+		{"::1", true},
+		{"fe80::1", true},
+		{"ff02::1", true},
+		{"::", true},
+		{"10.0.0.1", false},
+		{"8.8.8.8", false},
+		{"2001:db8::1", false},
+	} {
+		c.Logf("test %d: %s", i, test.value)
+		c.Check(network.IsLocalIP(net.ParseIP(test.value)), gc.Equals, test.local)
+	}
+}
+
 func (s *AddressSuite) TestNewAddressIPv4(c *gc.C) {
 	value := "0.1.2.3"
 	addr1 := network.NewScopedAddress(value, network.ScopeUnknown)
@@ -0,0 +1,84 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package payload
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/charm.v6-unstable"
+)
+
+// The operations that may be requested of a payload. Juju does not
+// act on payloads directly; each operation is mapped onto an action
+// defined by the charm that registered the payload.
+const (
+	OperationStop    = "stop"
+	OperationRestart = "restart"
+)
+
+var okayOperations = set.NewStrings(
+	OperationStop,
+	OperationRestart,
+)
+
+// ValidateOperation verifies that the operation may be requested of
+// a payload.
+func ValidateOperation(operation string) error {
+	if !okayOperations.Contains(operation) {
+		supported := okayOperations.Values()
+		sort.Strings(supported)
+		operations := strings.Join(supported, `", "`)
+		msg := fmt.Sprintf(`operation %q not supported; expected one of ["%s"]`, operation, operations)
+		return errors.NewNotValid(nil, msg)
+	}
+	return nil
+}
+
+// ActionName returns the name of the charm action that performs the
+// operation on payloads of the given class. A charm maps an operation
+// onto one of its actions by defining an action named
+// "<class>-<operation>" (e.g. "webapp-restart"), or, for all of its
+// payload classes, "payload-<operation>". The former takes precedence.
+func ActionName(actions map[string]charm.ActionSpec, class, operation string) (string, error) {
+	if err := ValidateOperation(operation); err != nil {
+		return "", errors.Trace(err)
+	}
+	candidates := []string{
+		class + "-" + operation,
+		"payload-" + operation,
+	}
+	for _, name := range candidates {
+		if _, ok := actions[name]; ok {
+			return name, nil
+		}
+	}
+	return "", errors.NotSupportedf("%s of %q payloads (charm defines neither %q nor %q action)",
+		operation, class, candidates[0], candidates[1])
+}
+
+// ActionParams returns the parameters passed to the charm action that
+// acts on the payload. They identify the payload, so the action must
+// accept "class" and "id" parameters.
+func ActionParams(p Payload) map[string]interface{} {
+	return map[string]interface{}{
+		"class": p.Name,
+		"id":    p.ID,
+	}
+}
+
+// ActionResult ties the result of requesting an operation to the
+// payload on which it was requested.
+type ActionResult struct {
+	// Payload holds the info about the payload.
+	Payload FullPayloadInfo
+	// ActionID identifies the charm action that was enqueued to
+	// perform the operation, if any.
+	ActionID string
+	// Error is the error associated with this result (if any).
+	Error error
+}
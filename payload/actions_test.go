@@ -0,0 +1,90 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package payload_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/payload"
+	"github.com/juju/juju/testing"
+)
+
+type actionsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&actionsSuite{})
+
+func (s *actionsSuite) TestValidateOperationOkay(c *gc.C) {
+	for _, operation := range []string{payload.OperationStop, payload.OperationRestart} {
+		c.Logf("checking %q", operation)
+		err := payload.ValidateOperation(operation)
+
+		c.Check(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *actionsSuite) TestValidateOperationBad(c *gc.C) {
+	err := payload.ValidateOperation("explode")
+
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, `operation "explode" not supported; expected one of \["restart", "stop"\]`)
+}
+
+func (s *actionsSuite) TestActionNameClassSpecific(c *gc.C) {
+	actions := map[string]charm.ActionSpec{
+		"spam-stop":    {},
+		"payload-stop": {},
+	}
+	name, err := payload.ActionName(actions, "spam", payload.OperationStop)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(name, gc.Equals, "spam-stop")
+}
+
+func (s *actionsSuite) TestActionNameGeneric(c *gc.C) {
+	actions := map[string]charm.ActionSpec{
+		"eggs-stop":       {},
+		"payload-restart": {},
+	}
+	name, err := payload.ActionName(actions, "spam", payload.OperationRestart)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(name, gc.Equals, "payload-restart")
+}
+
+func (s *actionsSuite) TestActionNameNotDefined(c *gc.C) {
+	actions := map[string]charm.ActionSpec{
+		"eggs-stop": {},
+	}
+	_, err := payload.ActionName(actions, "spam", payload.OperationStop)
+
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	c.Check(err, gc.ErrorMatches, `stop of "spam" payloads \(charm defines neither "spam-stop" nor "payload-stop" action\) not supported`)
+}
+
+func (s *actionsSuite) TestActionNameBadOperation(c *gc.C) {
+	_, err := payload.ActionName(nil, "spam", "explode")
+
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *actionsSuite) TestActionParams(c *gc.C) {
+	pl := payload.Payload{
+		PayloadClass: charm.PayloadClass{
+			Name: "spam",
+			Type: "docker",
+		},
+		ID: "idspam",
+	}
+	params := payload.ActionParams(pl)
+
+	c.Check(params, jc.DeepEquals, map[string]interface{}{
+		"class": "spam",
+		"id":    "idspam",
+	})
+}
@@ -7,7 +7,9 @@ import (
 	"io"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
 
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/payload"
 	"github.com/juju/juju/payload/api"
 )
@@ -56,3 +58,72 @@ func (c PublicClient) ListFull(patterns ...string) ([]payload.FullPayloadInfo, e
 	}
 	return payloads, nil
 }
+
+// Act calls the Act API server method, which requests the operation
+// of every payload matching any of the patterns.
+func (c PublicClient) Act(operation string, patterns ...string) ([]payload.ActionResult, error) {
+	var result api.ActResults
+
+	args := api.ActArgs{
+		Operation: operation,
+		Patterns:  patterns,
+	}
+	if err := c.FacadeCall("Act", &args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	results := make([]payload.ActionResult, len(result.Results))
+	for i, apiResult := range result.Results {
+		pl, err := api.API2Payload(apiResult.Payload)
+		if err != nil {
+			// We should never see this happen; we control the input safely.
+			return nil, errors.Trace(err)
+		}
+		results[i].Payload = pl
+		if apiResult.Error != nil {
+			results[i].Error, _ = common.RestoreError(apiResult.Error)
+			continue
+		}
+		tag, err := names.ParseActionTag(apiResult.Action)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		results[i].ActionID = tag.Id()
+	}
+	return results, nil
+}
+
+// StatusHistory calls the StatusHistory API server method, which
+// returns at most size of the most recent statuses of every payload
+// matching any of the patterns.
+func (c PublicClient) StatusHistory(size int, patterns ...string) ([]payload.StatusHistory, error) {
+	var result api.StatusHistoryResults
+
+	args := api.StatusHistoryArgs{
+		Patterns: patterns,
+		Size:     size,
+	}
+	if err := c.FacadeCall("StatusHistory", &args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	histories := make([]payload.StatusHistory, len(result.Results))
+	for i, apiResult := range result.Results {
+		pl, err := api.API2Payload(apiResult.Payload)
+		if err != nil {
+			// We should never see this happen; we control the input safely.
+			return nil, errors.Trace(err)
+		}
+		histories[i].Payload = pl
+		if apiResult.Error != nil {
+			histories[i].Error, _ = common.RestoreError(apiResult.Error)
+		}
+		for _, entry := range apiResult.Entries {
+			histories[i].Entries = append(histories[i].Entries, payload.StatusEntry{
+				Status: entry.Status,
+				Since:  entry.Since,
+			})
+		}
+	}
+	return histories, nil
+}
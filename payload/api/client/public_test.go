@@ -1,12 +1,15 @@
 package client_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/payload"
 	"github.com/juju/juju/payload/api"
 	"github.com/juju/juju/payload/api/client"
@@ -77,6 +80,90 @@ func (s *publicSuite) TestListAPI(c *gc.C) {
 	}})
 }
 
+func (s *publicSuite) TestActOkay(c *gc.C) {
+	s.facade.FacadeCallFn = func(_ string, _, response interface{}) error {
+		typedResponse, ok := response.(*api.ActResults)
+		c.Assert(ok, gc.Equals, true)
+		typedResponse.Results = append(typedResponse.Results, api.ActResult{
+			Payload: s.payload,
+			Action:  "action-f47ac10b-58cc-4372-a567-0e02b2c3d479",
+		}, api.ActResult{
+			Payload: s.payload,
+			Error:   common.ServerError(errors.NotSupportedf("stop")),
+		})
+		return nil
+	}
+
+	pclient := client.NewPublicClient(s.facade)
+
+	results, err := pclient.Act(payload.OperationStop, "spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	expected, _ := api.API2Payload(s.payload)
+	c.Assert(results, gc.HasLen, 2)
+	c.Check(results[0], jc.DeepEquals, payload.ActionResult{
+		Payload:  expected,
+		ActionID: "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+	})
+	c.Check(results[1].Payload, jc.DeepEquals, expected)
+	c.Check(results[1].ActionID, gc.Equals, "")
+	c.Check(results[1].Error, gc.ErrorMatches, "stop not supported")
+	s.stub.CheckCalls(c, []testing.StubCall{{
+		FuncName: "FacadeCall",
+		Args: []interface{}{
+			"Act",
+			&api.ActArgs{
+				Operation: payload.OperationStop,
+				Patterns:  []string{"spam"},
+			},
+			&api.ActResults{
+				Results: []api.ActResult{{
+					Payload: s.payload,
+					Action:  "action-f47ac10b-58cc-4372-a567-0e02b2c3d479",
+				}, {
+					Payload: s.payload,
+					Error:   common.ServerError(errors.NotSupportedf("stop")),
+				}},
+			},
+		},
+	}})
+}
+
+func (s *publicSuite) TestStatusHistoryOkay(c *gc.C) {
+	since := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.facade.FacadeCallFn = func(_ string, _, response interface{}) error {
+		typedResponse, ok := response.(*api.StatusHistoryResults)
+		c.Assert(ok, gc.Equals, true)
+		typedResponse.Results = append(typedResponse.Results, api.StatusHistoryResult{
+			Payload: s.payload,
+			Entries: []api.StatusEntry{{
+				Status: payload.StateRunning,
+				Since:  since,
+			}},
+		})
+		return nil
+	}
+
+	pclient := client.NewPublicClient(s.facade)
+
+	histories, err := pclient.StatusHistory(5, "spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	expected, _ := api.API2Payload(s.payload)
+	c.Check(histories, jc.DeepEquals, []payload.StatusHistory{{
+		Payload: expected,
+		Entries: []payload.StatusEntry{{
+			Status: payload.StateRunning,
+			Since:  since,
+		}},
+	}})
+	s.stub.CheckCallNames(c, "FacadeCall")
+	c.Check(s.stub.Calls()[0].Args[1], jc.DeepEquals, &api.StatusHistoryArgs{
+		Patterns: []string{"spam"},
+		Size:     5,
+	})
+}
+
 type stubFacade struct {
 	stub         *testing.Stub
 	FacadeCallFn func(name string, params, response interface{}) error
@@ -5,6 +5,12 @@ package api
 
 // TODO(ericsnow) Move this file to the top-level "payload" package?
 
+import (
+	"time"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
 // EnvListArgs are the arguments for the env-based List endpoint.
 type EnvListArgs struct {
 	// Patterns is the list of patterns against which to filter.
@@ -36,3 +42,61 @@ type Payload struct {
 	// Machine identifies the machine tag associated with the payload.
 	Machine string
 }
+
+// ActArgs are the arguments for the Act endpoint.
+type ActArgs struct {
+	// Operation is the operation to request of each payload.
+	Operation string
+	// Patterns is the list of patterns against which to filter.
+	Patterns []string
+}
+
+// ActResults are the results of a call to the Act endpoint.
+type ActResults struct {
+	// Results is the list of results, one per matched payload.
+	Results []ActResult
+}
+
+// ActResult is the result of requesting an operation of a payload.
+type ActResult struct {
+	// Payload is the payload on which the operation was requested.
+	Payload Payload
+	// Action is the tag of the charm action enqueued to perform
+	// the operation.
+	Action string
+	// Error is the error (if any) for this result.
+	Error *params.Error
+}
+
+// StatusHistoryArgs are the arguments for the StatusHistory endpoint.
+type StatusHistoryArgs struct {
+	// Patterns is the list of patterns against which to filter.
+	Patterns []string
+	// Size is the maximum number of statuses to return per payload.
+	Size int
+}
+
+// StatusHistoryResults are the results of a call to the
+// StatusHistory endpoint.
+type StatusHistoryResults struct {
+	// Results is the list of results, one per matched payload.
+	Results []StatusHistoryResult
+}
+
+// StatusHistoryResult holds the status history of a payload.
+type StatusHistoryResult struct {
+	// Payload is the payload whose history is described.
+	Payload Payload
+	// Entries holds the payload's most recent statuses, newest first.
+	Entries []StatusEntry
+	// Error is the error (if any) for this result.
+	Error *params.Error
+}
+
+// StatusEntry is a status that a payload had.
+type StatusEntry struct {
+	// Status is the Juju-level status of the payload.
+	Status string
+	// Since is when the payload entered the status.
+	Since time.Time
+}
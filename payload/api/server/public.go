@@ -5,7 +5,10 @@ package server
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/juju/charm.v6-unstable"
 
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/payload"
 	"github.com/juju/juju/payload/api"
 )
@@ -14,6 +17,17 @@ import (
 type EnvPayloads interface {
 	// ListAll returns information on the payload with the id on the unit.
 	ListAll() ([]payload.FullPayloadInfo, error)
+
+	// StatusHistory returns at most size of the most recent statuses
+	// of the identified payload of the unit, newest first.
+	StatusHistory(unit, class, rawID string, size int) ([]payload.StatusEntry, error)
+
+	// ActionSpecs returns the actions defined by the unit's charm.
+	ActionSpecs(unit string) (map[string]charm.ActionSpec, error)
+
+	// EnqueueAction enqueues the named action, with the given
+	// parameters, on the unit. It returns the ID of the new action.
+	EnqueueAction(unit, name string, params map[string]interface{}) (string, error)
 }
 
 // PublicAPI serves payload-specific API methods.
@@ -33,20 +47,119 @@ func NewPublicAPI(st EnvPayloads) *PublicAPI {
 func (a PublicAPI) List(args api.EnvListArgs) (api.EnvListResults, error) {
 	var r api.EnvListResults
 
-	payloads, err := a.State.ListAll()
+	payloads, err := a.filter(args.Patterns)
 	if err != nil {
 		return r, errors.Trace(err)
 	}
 
-	filters, err := payload.BuildPredicatesFor(args.Patterns)
+	for _, payload := range payloads {
+		apiInfo := api.Payload2api(payload)
+		r.Results = append(r.Results, apiInfo)
+	}
+	return r, nil
+}
+
+// Act requests the operation of every payload that matches any of
+// the given patterns, by enqueueing the charm action to which the
+// payload's charm maps the operation. At least one pattern must be
+// given.
+func (a PublicAPI) Act(args api.ActArgs) (api.ActResults, error) {
+	var r api.ActResults
+
+	if err := payload.ValidateOperation(args.Operation); err != nil {
+		return r, errors.Trace(err)
+	}
+	if len(args.Patterns) == 0 {
+		return r, errors.New("no payloads specified")
+	}
+	payloads, err := a.filter(args.Patterns)
 	if err != nil {
 		return r, errors.Trace(err)
 	}
-	payloads = payload.Filter(payloads, filters...)
 
-	for _, payload := range payloads {
-		apiInfo := api.Payload2api(payload)
-		r.Results = append(r.Results, apiInfo)
+	specs := make(map[string]map[string]charm.ActionSpec)
+	for _, pl := range payloads {
+		result := api.ActResult{
+			Payload: api.Payload2api(pl),
+		}
+		actionID, err := a.act(specs, pl, args.Operation)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Action = names.NewActionTag(actionID).String()
+		}
+		r.Results = append(r.Results, result)
 	}
 	return r, nil
 }
+
+// act enqueues the charm action that performs the operation on the
+// payload. The action specs of each unit are cached in specs.
+func (a PublicAPI) act(specs map[string]map[string]charm.ActionSpec, pl payload.FullPayloadInfo, operation string) (string, error) {
+	unitSpecs, ok := specs[pl.Unit]
+	if !ok {
+		var err error
+		unitSpecs, err = a.State.ActionSpecs(pl.Unit)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		specs[pl.Unit] = unitSpecs
+	}
+	name, err := payload.ActionName(unitSpecs, pl.Name, operation)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	logger.Debugf("enqueueing action %q on %s to %s payload %s", name, pl.Unit, operation, pl.FullID())
+	actionID, err := a.State.EnqueueAction(pl.Unit, name, payload.ActionParams(pl.Payload))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return actionID, nil
+}
+
+// StatusHistory returns the most recent statuses of every payload
+// that matches any of the given patterns. If no patterns are given
+// then the history of every payload is returned.
+func (a PublicAPI) StatusHistory(args api.StatusHistoryArgs) (api.StatusHistoryResults, error) {
+	var r api.StatusHistoryResults
+
+	if args.Size < 1 {
+		return r, errors.NotValidf("history size %d", args.Size)
+	}
+	payloads, err := a.filter(args.Patterns)
+	if err != nil {
+		return r, errors.Trace(err)
+	}
+
+	for _, pl := range payloads {
+		result := api.StatusHistoryResult{
+			Payload: api.Payload2api(pl),
+		}
+		entries, err := a.State.StatusHistory(pl.Unit, pl.Name, pl.ID, args.Size)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		}
+		for _, entry := range entries {
+			result.Entries = append(result.Entries, api.StatusEntry{
+				Status: entry.Status,
+				Since:  entry.Since,
+			})
+		}
+		r.Results = append(r.Results, result)
+	}
+	return r, nil
+}
+
+// filter returns the payloads that match any of the given patterns.
+func (a PublicAPI) filter(patterns []string) ([]payload.FullPayloadInfo, error) {
+	payloads, err := a.State.ListAll()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	filters, err := payload.BuildPredicatesFor(patterns)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return payload.Filter(payloads, filters...), nil
+}
@@ -4,6 +4,9 @@
 package server
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	"github.com/juju/testing"
@@ -247,10 +250,142 @@ func (s *publicSuite) TestListAllFilters(c *gc.C) {
 	}
 }
 
+func (s *publicSuite) TestActOkay(c *gc.C) {
+	payloadA, apiPayloadA := s.newPayload("spam")
+	payloadB, apiPayloadB := s.newPayload("eggs")
+	payloadC, _ := s.newPayload("ham")
+	s.state.payloads = append(s.state.payloads, payloadA, payloadB, payloadC)
+	s.state.actions = map[string]charm.ActionSpec{
+		"spam-stop":    {},
+		"payload-stop": {},
+	}
+
+	facade := PublicAPI{s.state}
+	args := api.ActArgs{
+		Operation: payload.OperationStop,
+		Patterns:  []string{"spam", "eggs"},
+	}
+	results, err := facade.Act(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(results, jc.DeepEquals, api.ActResults{
+		Results: []api.ActResult{{
+			Payload: apiPayloadA,
+			Action:  "action-f47ac10b-58cc-4372-a567-0e02b2c3d470",
+		}, {
+			Payload: apiPayloadB,
+			Action:  "action-f47ac10b-58cc-4372-a567-0e02b2c3d471",
+		}},
+	})
+	// The action specs of each unit are only fetched once.
+	s.stub.CheckCallNames(c, "ListAll", "ActionSpecs", "EnqueueAction", "EnqueueAction")
+	s.stub.CheckCall(c, 2, "EnqueueAction", "a-service/0", "spam-stop", map[string]interface{}{
+		"class": "spam",
+		"id":    "idspam",
+	})
+	s.stub.CheckCall(c, 3, "EnqueueAction", "a-service/0", "payload-stop", map[string]interface{}{
+		"class": "eggs",
+		"id":    "ideggs",
+	})
+}
+
+func (s *publicSuite) TestActNotMapped(c *gc.C) {
+	payloadA, apiPayloadA := s.newPayload("spam")
+	s.state.payloads = append(s.state.payloads, payloadA)
+
+	facade := PublicAPI{s.state}
+	args := api.ActArgs{
+		Operation: payload.OperationRestart,
+		Patterns:  []string{"spam"},
+	}
+	results, err := facade.Act(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Check(results.Results[0].Payload, jc.DeepEquals, apiPayloadA)
+	c.Check(results.Results[0].Action, gc.Equals, "")
+	c.Check(results.Results[0].Error, gc.ErrorMatches, `restart of "spam" payloads .* not supported`)
+	s.stub.CheckCallNames(c, "ListAll", "ActionSpecs")
+}
+
+func (s *publicSuite) TestActBadOperation(c *gc.C) {
+	facade := PublicAPI{s.state}
+	args := api.ActArgs{
+		Operation: "explode",
+		Patterns:  []string{"spam"},
+	}
+	_, err := facade.Act(args)
+
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	s.stub.CheckNoCalls(c)
+}
+
+func (s *publicSuite) TestActNoPatterns(c *gc.C) {
+	facade := PublicAPI{s.state}
+	args := api.ActArgs{
+		Operation: payload.OperationStop,
+	}
+	_, err := facade.Act(args)
+
+	c.Check(err, gc.ErrorMatches, "no payloads specified")
+	s.stub.CheckNoCalls(c)
+}
+
+func (s *publicSuite) TestStatusHistory(c *gc.C) {
+	payloadA, apiPayloadA := s.newPayload("spam")
+	payloadB, _ := s.newPayload("eggs")
+	s.state.payloads = append(s.state.payloads, payloadA, payloadB)
+	since := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.state.history = []payload.StatusEntry{{
+		Status: payload.StateRunning,
+		Since:  since,
+	}, {
+		Status: payload.StateStarting,
+		Since:  since.Add(-time.Minute),
+	}}
+
+	facade := PublicAPI{s.state}
+	args := api.StatusHistoryArgs{
+		Patterns: []string{"spam"},
+		Size:     5,
+	}
+	results, err := facade.StatusHistory(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(results, jc.DeepEquals, api.StatusHistoryResults{
+		Results: []api.StatusHistoryResult{{
+			Payload: apiPayloadA,
+			Entries: []api.StatusEntry{{
+				Status: payload.StateRunning,
+				Since:  since,
+			}, {
+				Status: payload.StateStarting,
+				Since:  since.Add(-time.Minute),
+			}},
+		}},
+	})
+	s.stub.CheckCallNames(c, "ListAll", "StatusHistory")
+	s.stub.CheckCall(c, 1, "StatusHistory", "a-service/0", "spam", "idspam", 5)
+}
+
+func (s *publicSuite) TestStatusHistoryBadSize(c *gc.C) {
+	facade := PublicAPI{s.state}
+	args := api.StatusHistoryArgs{
+		Size: 0,
+	}
+	_, err := facade.StatusHistory(args)
+
+	c.Check(err, gc.ErrorMatches, "history size 0 not valid")
+	s.stub.CheckNoCalls(c)
+}
+
 type stubState struct {
 	stub *testing.Stub
 
 	payloads []payload.FullPayloadInfo
+	history  []payload.StatusEntry
+	actions  map[string]charm.ActionSpec
+	enqueued int
 }
 
 func (s *stubState) ListAll() ([]payload.FullPayloadInfo, error) {
@@ -261,3 +396,32 @@ func (s *stubState) ListAll() ([]payload.FullPayloadInfo, error) {
 
 	return s.payloads, nil
 }
+
+func (s *stubState) StatusHistory(unit, class, rawID string, size int) ([]payload.StatusEntry, error) {
+	s.stub.AddCall("StatusHistory", unit, class, rawID, size)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return s.history, nil
+}
+
+func (s *stubState) ActionSpecs(unit string) (map[string]charm.ActionSpec, error) {
+	s.stub.AddCall("ActionSpecs", unit)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return s.actions, nil
+}
+
+func (s *stubState) EnqueueAction(unit, name string, params map[string]interface{}) (string, error) {
+	s.stub.AddCall("EnqueueAction", unit, name, params)
+	if err := s.stub.NextErr(); err != nil {
+		return "", errors.Trace(err)
+	}
+
+	id := fmt.Sprintf("f47ac10b-58cc-4372-a567-0e02b2c3d47%d", s.enqueued)
+	s.enqueued++
+	return id, nil
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/set"
@@ -41,3 +42,21 @@ func ValidateState(state string) error {
 	}
 	return nil
 }
+
+// StatusEntry records a status that a payload had.
+type StatusEntry struct {
+	// Status is the Juju-level status of the payload.
+	Status string
+	// Since is when the payload entered the status.
+	Since time.Time
+}
+
+// StatusHistory holds the recent status history of a payload.
+type StatusHistory struct {
+	// Payload holds the info about the payload.
+	Payload FullPayloadInfo
+	// Entries holds the payload's most recent statuses, newest first.
+	Entries []StatusEntry
+	// Error is the error associated with this result (if any).
+	Error error
+}
@@ -0,0 +1,101 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/payload"
+)
+
+// ActAPI has the API methods needed by ActCommand.
+type ActAPI interface {
+	Act(operation string, patterns ...string) ([]payload.ActionResult, error)
+	io.Closer
+}
+
+// ActCommand implements the commands that request an operation (e.g.
+// stop-payloads) of payloads.
+type ActCommand struct {
+	envcmd.EnvCommandBase
+	operation string
+	patterns  []string
+
+	newAPIClient func(c *ActCommand) (ActAPI, error)
+}
+
+// NewActCommand returns a new command that requests the operation of
+// charm payloads in the current environment.
+func NewActCommand(operation string, newAPIClient func(c *ActCommand) (ActAPI, error)) *ActCommand {
+	cmd := &ActCommand{
+		operation:    operation,
+		newAPIClient: newAPIClient,
+	}
+	return cmd
+}
+
+var actDoc = `
+This command will %[1]s the payloads which match *any* of the provided
+patterns. The patterns are checked as for list-payloads.
+
+Juju does not %[1]s payloads itself. Instead, it enqueues a charm action
+on the unit that registered each payload. The charm maps the operation
+onto an action named "<payload-class>-%[1]s" or, for all of its payload
+classes, "payload-%[1]s"; the action is passed the payload's "class"
+and "id" as parameters. Use "juju action fetch" to follow the progress
+of the enqueued actions.
+`
+
+func (c *ActCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    c.operation + "-payloads",
+		Args:    "<pattern> [...]",
+		Purpose: fmt.Sprintf("%s payloads using their charms' actions", c.operation),
+		Doc:     fmt.Sprintf(actDoc, c.operation),
+	}
+}
+
+func (c *ActCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no payloads specified")
+	}
+	c.patterns = args
+	return nil
+}
+
+func (c *ActCommand) Run(ctx *cmd.Context) error {
+	apiclient, err := c.newAPIClient(c)
+	if err != nil {
+		return fmt.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	defer apiclient.Close()
+
+	results, err := apiclient.Act(c.operation, c.patterns...)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(results) == 0 {
+		return errors.New("no payloads matched")
+	}
+
+	var failed bool
+	for _, result := range results {
+		pl := result.Payload
+		if result.Error != nil {
+			fmt.Fprintf(ctx.Stderr, "%s %s: %v\n", pl.Unit, pl.FullID(), result.Error)
+			failed = true
+			continue
+		}
+		fmt.Fprintf(ctx.Stdout, "%s %s: action %s\n", pl.Unit, pl.FullID(), result.ActionID)
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
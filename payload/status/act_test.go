@@ -0,0 +1,146 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status_test
+
+import (
+	"bytes"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/payload"
+	"github.com/juju/juju/payload/status"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&actSuite{})
+
+type actSuite struct {
+	testing.IsolationSuite
+
+	stub   *testing.Stub
+	client *stubActClient
+}
+
+func (s *actSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.stub = &testing.Stub{}
+	s.client = &stubActClient{stub: s.stub}
+}
+
+func (s *actSuite) newAPIClient(c *status.ActCommand) (status.ActAPI, error) {
+	s.stub.AddCall("newAPIClient", c)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return s.client, nil
+}
+
+func (s *actSuite) TestInfo(c *gc.C) {
+	command := status.NewActCommand(payload.OperationRestart, s.newAPIClient)
+	info := command.Info()
+
+	c.Check(info.Name, gc.Equals, "restart-payloads")
+	c.Check(info.Args, gc.Equals, "<pattern> [...]")
+	c.Check(info.Purpose, gc.Equals, "restart payloads using their charms' actions")
+	c.Check(info.Doc, jc.Contains, `"<payload-class>-restart"`)
+}
+
+func (s *actSuite) TestOkay(c *gc.C) {
+	s.client.results = []payload.ActionResult{{
+		Payload:  status.NewPayload("spam", "a-service", 1, 0),
+		ActionID: "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+	}}
+
+	command := status.NewActCommand(payload.OperationStop, s.newAPIClient)
+	code, stdout, stderr := runAct(c, command, "spam")
+	c.Assert(code, gc.Equals, 0)
+
+	c.Check(stdout, gc.Equals, "a-service/0 spam/idspam: action f47ac10b-58cc-4372-a567-0e02b2c3d479\n")
+	c.Check(stderr, gc.Equals, "")
+	s.stub.CheckCalls(c, []testing.StubCall{{
+		FuncName: "newAPIClient",
+		Args: []interface{}{
+			command,
+		},
+	}, {
+		FuncName: "Act",
+		Args: []interface{}{
+			payload.OperationStop,
+			[]string{"spam"},
+		},
+	}, {
+		FuncName: "Close",
+	}})
+}
+
+func (s *actSuite) TestPartialFailure(c *gc.C) {
+	s.client.results = []payload.ActionResult{{
+		Payload:  status.NewPayload("spam", "a-service", 1, 0),
+		ActionID: "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+	}, {
+		Payload: status.NewPayload("eggs", "another-service", 2, 1),
+		Error:   errors.New("boom"),
+	}}
+
+	command := status.NewActCommand(payload.OperationStop, s.newAPIClient)
+	code, stdout, stderr := runAct(c, command, "a-tag")
+	c.Assert(code, gc.Equals, 1)
+
+	c.Check(stdout, gc.Equals, "a-service/0 spam/idspam: action f47ac10b-58cc-4372-a567-0e02b2c3d479\n")
+	c.Check(stderr, gc.Equals, "another-service/1 eggs/ideggs: boom\n")
+}
+
+func (s *actSuite) TestNoMatch(c *gc.C) {
+	command := status.NewActCommand(payload.OperationStop, s.newAPIClient)
+	code, _, stderr := runAct(c, command, "spam")
+	c.Assert(code, gc.Equals, 1)
+
+	c.Check(stderr, gc.Equals, "error: no payloads matched\n")
+}
+
+func (s *actSuite) TestNoPatterns(c *gc.C) {
+	command := status.NewActCommand(payload.OperationStop, s.newAPIClient)
+	code, _, stderr := runAct(c, command)
+	c.Assert(code, gc.Equals, 2)
+
+	c.Check(stderr, gc.Equals, "error: no payloads specified\n")
+	s.stub.CheckNoCalls(c)
+}
+
+func runAct(c *gc.C, command *status.ActCommand, args ...string) (int, string, string) {
+	ctx := coretesting.Context(c)
+	code := cmd.Main(command, ctx, args)
+	stdout := ctx.Stdout.(*bytes.Buffer).Bytes()
+	stderr := ctx.Stderr.(*bytes.Buffer).Bytes()
+	return code, string(stdout), string(stderr)
+}
+
+type stubActClient struct {
+	stub    *testing.Stub
+	results []payload.ActionResult
+}
+
+func (s *stubActClient) Act(operation string, patterns ...string) ([]payload.ActionResult, error) {
+	s.stub.AddCall("Act", operation, patterns)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return s.results, nil
+}
+
+func (s *stubActClient) Close() error {
+	s.stub.AddCall("Close")
+	if err := s.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/payload"
+)
+
+// StatusHistoryAPI has the API methods needed by StatusHistoryCommand.
+type StatusHistoryAPI interface {
+	StatusHistory(size int, patterns ...string) ([]payload.StatusHistory, error)
+	io.Closer
+}
+
+// StatusHistoryCommand implements the payload-status-history command.
+type StatusHistoryCommand struct {
+	envcmd.EnvCommandBase
+	out      cmd.Output
+	size     int
+	patterns []string
+
+	newAPIClient func(c *StatusHistoryCommand) (StatusHistoryAPI, error)
+}
+
+// NewStatusHistoryCommand returns a new command that shows the status
+// history of charm payloads in the current environment.
+func NewStatusHistoryCommand(newAPIClient func(c *StatusHistoryCommand) (StatusHistoryAPI, error)) *StatusHistoryCommand {
+	cmd := &StatusHistoryCommand{
+		newAPIClient: newAPIClient,
+	}
+	return cmd
+}
+
+var statusHistoryDoc = `
+This command will report the most recent statuses of defined payloads,
+newest first.
+
+When one or more pattern is given, Juju will limit the results to only
+those payloads which match *any* of the provided patterns. The patterns
+are checked as for list-payloads.
+`
+
+func (c *StatusHistoryCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "payload-status-history",
+		Args:    "[pattern ...]",
+		Purpose: "display the status history of known payloads",
+		Doc:     statusHistoryDoc,
+	}
+}
+
+func (c *StatusHistoryCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.IntVar(&c.size, "n", 20, "size of the history to show per payload")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"tabular": FormatStatusHistoryTabular,
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+	})
+}
+
+func (c *StatusHistoryCommand) Init(args []string) error {
+	if c.size < 1 {
+		return errors.Errorf("invalid history size %d", c.size)
+	}
+	c.patterns = args
+	return nil
+}
+
+func (c *StatusHistoryCommand) Run(ctx *cmd.Context) error {
+	apiclient, err := c.newAPIClient(c)
+	if err != nil {
+		return fmt.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	defer apiclient.Close()
+
+	histories, err := apiclient.StatusHistory(c.size, c.patterns...)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	formatted := make([]FormattedStatusHistory, 0, len(histories))
+	for _, history := range histories {
+		if history.Error != nil {
+			// Display any error, but continue to print the others.
+			fmt.Fprintf(ctx.Stderr, "%s %s: %v\n", history.Payload.Unit, history.Payload.FullID(), history.Error)
+			continue
+		}
+		formatted = append(formatted, formatStatusHistory(history))
+	}
+	return c.out.Write(ctx, formatted)
+}
+
+// FormattedStatusHistory holds the formatted representation of a
+// payload's status history.
+type FormattedStatusHistory struct {
+	// These fields are exported for the sake of serialization.
+	Unit     string                 `json:"unit" yaml:"unit"`
+	Class    string                 `json:"payload-class" yaml:"payload-class"`
+	ID       string                 `json:"id" yaml:"id"`
+	Statuses []FormattedStatusEntry `json:"statuses" yaml:"statuses"`
+}
+
+// FormattedStatusEntry holds the formatted representation of a status
+// that a payload had.
+type FormattedStatusEntry struct {
+	Status string    `json:"status" yaml:"status"`
+	Since  time.Time `json:"since" yaml:"since"`
+}
+
+func formatStatusHistory(history payload.StatusHistory) FormattedStatusHistory {
+	formatted := FormattedStatusHistory{
+		Unit:     history.Payload.Unit,
+		Class:    history.Payload.Name,
+		ID:       history.Payload.ID,
+		Statuses: make([]FormattedStatusEntry, len(history.Entries)),
+	}
+	for i, entry := range history.Entries {
+		formatted.Statuses[i] = FormattedStatusEntry{
+			Status: entry.Status,
+			Since:  entry.Since.UTC(),
+		}
+	}
+	return formatted
+}
+
+// FormatStatusHistoryTabular returns a tabular summary of payload
+// status histories, with one row per status.
+func FormatStatusHistoryTabular(value interface{}) ([]byte, error) {
+	histories, valueConverted := value.([]FormattedStatusHistory)
+	if !valueConverted {
+		return nil, errors.Errorf("expected value of type %T, got %T", histories, value)
+	}
+
+	var out bytes.Buffer
+	// To format things into columns.
+	tw := tabwriter.NewWriter(&out, 0, 1, 1, ' ', 0)
+
+	fmt.Fprintln(tw, strings.Join([]string{"UNIT", "PAYLOAD-CLASS", "ID", "TIME", "STATUS"}, "\t")+"\t")
+	for _, history := range histories {
+		for _, entry := range history.Statuses {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t\n",
+				history.Unit,
+				history.Class,
+				history.ID,
+				entry.Since.Format(time.RFC3339),
+				entry.Status,
+			)
+		}
+	}
+	tw.Flush()
+
+	return out.Bytes(), nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status_test
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/payload"
+	"github.com/juju/juju/payload/status"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&statusHistorySuite{})
+
+type statusHistorySuite struct {
+	testing.IsolationSuite
+
+	stub   *testing.Stub
+	client *stubStatusHistoryClient
+}
+
+func (s *statusHistorySuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.stub = &testing.Stub{}
+	s.client = &stubStatusHistoryClient{stub: s.stub}
+}
+
+func (s *statusHistorySuite) newAPIClient(c *status.StatusHistoryCommand) (status.StatusHistoryAPI, error) {
+	s.stub.AddCall("newAPIClient", c)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return s.client, nil
+}
+
+func (s *statusHistorySuite) addHistory() {
+	since := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.client.histories = append(s.client.histories, payload.StatusHistory{
+		Payload: status.NewPayload("spam", "a-service", 1, 0),
+		Entries: []payload.StatusEntry{{
+			Status: payload.StateRunning,
+			Since:  since.Add(time.Minute),
+		}, {
+			Status: payload.StateStarting,
+			Since:  since,
+		}},
+	})
+}
+
+func (s *statusHistorySuite) TestOkay(c *gc.C) {
+	s.addHistory()
+
+	command := status.NewStatusHistoryCommand(s.newAPIClient)
+	code, stdout, stderr := runStatusHistory(c, command, "spam")
+	c.Assert(code, gc.Equals, 0)
+
+	c.Check(stdout, gc.Equals, `
+UNIT        PAYLOAD-CLASS ID     TIME                 STATUS   
+a-service/0 spam          idspam 2015-10-01T12:01:00Z running  
+a-service/0 spam          idspam 2015-10-01T12:00:00Z starting 
+`[1:])
+	c.Check(stderr, gc.Equals, "")
+	s.stub.CheckCalls(c, []testing.StubCall{{
+		FuncName: "newAPIClient",
+		Args: []interface{}{
+			command,
+		},
+	}, {
+		FuncName: "StatusHistory",
+		Args: []interface{}{
+			20,
+			[]string{"spam"},
+		},
+	}, {
+		FuncName: "Close",
+	}})
+}
+
+func (s *statusHistorySuite) TestYAML(c *gc.C) {
+	s.addHistory()
+
+	command := status.NewStatusHistoryCommand(s.newAPIClient)
+	code, stdout, stderr := runStatusHistory(c, command, "-n", "2", "--format", "yaml")
+	c.Assert(code, gc.Equals, 0)
+
+	c.Check(stdout, gc.Equals, `
+- unit: a-service/0
+  payload-class: spam
+  id: idspam
+  statuses:
+  - status: running
+    since: 2015-10-01T12:01:00Z
+  - status: starting
+    since: 2015-10-01T12:00:00Z
+`[1:])
+	c.Check(stderr, gc.Equals, "")
+	s.stub.CheckCallNames(c, "newAPIClient", "StatusHistory", "Close")
+	c.Check(s.stub.Calls()[1].Args[0], gc.Equals, 2)
+}
+
+func (s *statusHistorySuite) TestPayloadError(c *gc.C) {
+	s.addHistory()
+	s.client.histories = append(s.client.histories, payload.StatusHistory{
+		Payload: status.NewPayload("eggs", "another-service", 2, 1),
+		Error:   errors.New("boom"),
+	})
+
+	command := status.NewStatusHistoryCommand(s.newAPIClient)
+	code, stdout, stderr := runStatusHistory(c, command)
+	c.Assert(code, gc.Equals, 0)
+
+	c.Check(stdout, gc.Matches, "(?s)UNIT .*a-service/0 .*")
+	c.Check(stderr, gc.Equals, "another-service/1 eggs/ideggs: boom\n")
+}
+
+func (s *statusHistorySuite) TestBadSize(c *gc.C) {
+	command := status.NewStatusHistoryCommand(s.newAPIClient)
+	code, _, stderr := runStatusHistory(c, command, "-n", "0")
+	c.Assert(code, gc.Equals, 2)
+
+	c.Check(stderr, gc.Equals, "error: invalid history size 0\n")
+	s.stub.CheckNoCalls(c)
+}
+
+func runStatusHistory(c *gc.C, command *status.StatusHistoryCommand, args ...string) (int, string, string) {
+	ctx := coretesting.Context(c)
+	code := cmd.Main(command, ctx, args)
+	stdout := ctx.Stdout.(*bytes.Buffer).Bytes()
+	stderr := ctx.Stderr.(*bytes.Buffer).Bytes()
+	return code, string(stdout), string(stderr)
+}
+
+type stubStatusHistoryClient struct {
+	stub      *testing.Stub
+	histories []payload.StatusHistory
+}
+
+func (s *stubStatusHistoryClient) StatusHistory(size int, patterns ...string) ([]payload.StatusHistory, error) {
+	s.stub.AddCall("StatusHistory", size, patterns)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return s.histories, nil
+}
+
+func (s *stubStatusHistoryClient) Close() error {
+	s.stub.AddCall("Close")
+	if err := s.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	return nil
+}
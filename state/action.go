@@ -9,6 +9,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
 	"github.com/juju/utils"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
@@ -99,6 +100,29 @@ type actionDoc struct {
 
 	// Results are the structured results from the action.
 	Results map[string]interface{} `bson:"results"`
+
+	// Operation is the id of the ActionOperation that enqueued this
+	// action, if any.
+	Operation string `bson:"operation,omitempty"`
+
+	// Timeout is the longest the action may run before it is stopped
+	// and marked failed; zero means no limit.
+	Timeout time.Duration `bson:"timeout,omitempty"`
+
+	// CancelRequested is set when a running action is cancelled; the
+	// unit running it is expected to stop it.
+	CancelRequested bool `bson:"cancelrequested,omitempty"`
+}
+
+// actionOptions holds the optional attributes of a newly enqueued
+// action.
+type actionOptions struct {
+	// operation is the id of the ActionOperation enqueueing the
+	// action, if any.
+	operation string
+
+	// timeout is the longest the action may run; zero means no limit.
+	timeout time.Duration
 }
 
 // Action represents an instruction to do some "action" and is expected
@@ -119,6 +143,24 @@ func (a *Action) Receiver() string {
 	return a.doc.Receiver
 }
 
+// Operation returns the id of the ActionOperation that enqueued this
+// action, or "" if it was enqueued individually.
+func (a *Action) Operation() string {
+	return a.doc.Operation
+}
+
+// Timeout returns the longest the action may run before it is stopped
+// and marked failed, or zero if there is no limit.
+func (a *Action) Timeout() time.Duration {
+	return a.doc.Timeout
+}
+
+// CancelRequested returns whether the action has been cancelled while
+// running, and should be stopped by the unit running it.
+func (a *Action) CancelRequested() bool {
+	return a.doc.CancelRequested
+}
+
 // Name returns the name of the action, as defined in the charm.
 func (a *Action) Name() string {
 	return a.doc.Name
@@ -202,6 +244,43 @@ func (a *Action) Begin() (*Action, error) {
 	return a.st.Action(a.Id())
 }
 
+// Cancel cancels the action. A pending action is removed from the queue
+// and marked cancelled at once; a running action is flagged so that the
+// unit running it stops it, and is marked cancelled when it has.
+func (a *Action) Cancel() (*Action, error) {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			fresh, err := a.st.Action(a.Id())
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			a.doc = fresh.doc
+		}
+		switch a.doc.Status {
+		case ActionPending:
+			return a.finishOps(ActionPending, ActionCancelled, nil, "action cancelled"), nil
+		case ActionRunning:
+			if a.doc.CancelRequested {
+				return nil, jujutxn.ErrNoOperations
+			}
+			return []txn.Op{{
+				C:      actionsC,
+				Id:     a.doc.DocId,
+				Assert: bson.D{{"status", ActionRunning}},
+				Update: bson.D{{"$set", bson.D{{"cancelrequested", true}}}},
+			}}, nil
+		}
+		return nil, errors.Errorf("action %q already %s", a.Id(), a.doc.Status)
+	}
+	if err := a.st.run(buildTxn); err != nil {
+		return nil, errors.Annotatef(err, "cannot cancel action %q", a.Id())
+	}
+	if a.doc.Status == ActionPending {
+		a.advanceOperation()
+	}
+	return a.st.Action(a.Id())
+}
+
 // Finish removes action from the pending queue and captures the output
 // and end state of the action.
 func (a *Action) Finish(results ActionResults) (*Action, error) {
@@ -212,33 +291,54 @@ func (a *Action) Finish(results ActionResults) (*Action, error) {
 // an actionresult to capture the outcome of the action. It asserts that
 // the action is not already completed.
 func (a *Action) removeAndLog(finalStatus ActionStatus, results map[string]interface{}, message string) (*Action, error) {
-	err := a.st.runTransaction([]txn.Op{
-		{
-			C:  actionsC,
-			Id: a.doc.DocId,
-			Assert: bson.D{{"status", bson.D{
-				{"$nin", []interface{}{
-					ActionCompleted,
-					ActionCancelled,
-					ActionFailed,
-				}}}}},
-			Update: bson.D{{"$set", bson.D{
-				{"status", finalStatus},
-				{"message", message},
-				{"results", results},
-				{"completed", nowToTheSecond()},
-			}}},
-		}, {
-			C:      actionNotificationsC,
-			Id:     a.st.docID(ensureActionMarker(a.Receiver()) + a.Id()),
-			Remove: true,
-		}})
+	notFinished := bson.D{{"$nin", []interface{}{
+		ActionCompleted,
+		ActionCancelled,
+		ActionFailed,
+	}}}
+	err := a.st.runTransaction(a.finishOps(notFinished, finalStatus, results, message))
 	if err != nil {
 		return nil, err
 	}
+	a.advanceOperation()
 	return a.st.Action(a.Id())
 }
 
+// finishOps returns the operations that record the end state of the
+// action and remove its notification, asserting that its status
+// matches currentStatus.
+func (a *Action) finishOps(currentStatus interface{}, finalStatus ActionStatus, results map[string]interface{}, message string) []txn.Op {
+	return []txn.Op{{
+		C:      actionsC,
+		Id:     a.doc.DocId,
+		Assert: bson.D{{"status", currentStatus}},
+		Update: bson.D{{"$set", bson.D{
+			{"status", finalStatus},
+			{"message", message},
+			{"results", results},
+			{"completed", nowToTheSecond()},
+		}}},
+	}, {
+		C:      actionNotificationsC,
+		Id:     a.st.docID(ensureActionMarker(a.Receiver()) + a.Id()),
+		Remove: true,
+	}}
+}
+
+// advanceOperation advances the operation that enqueued the action, if
+// any, now that the action has finished.
+func (a *Action) advanceOperation() {
+	if a.doc.Operation == "" {
+		return
+	}
+	// The action's completion may free a slot for the next batch of a
+	// rolling operation. The action itself has finished regardless,
+	// so a failure here is only logged.
+	if err := a.st.advanceActionOperation(a.doc.Operation); err != nil {
+		actionLogger.Warningf("cannot advance operation %q: %v", a.doc.Operation, err)
+	}
+}
+
 // newActionTagFromNotification converts an actionNotificationDoc into
 // an names.ActionTag
 func newActionTagFromNotification(doc actionNotificationDoc) names.ActionTag {
@@ -255,7 +355,7 @@ func newAction(st *State, adoc actionDoc) *Action {
 }
 
 // newActionDoc builds the actionDoc with the given name and parameters.
-func newActionDoc(st *State, receiverTag names.Tag, actionName string, parameters map[string]interface{}) (actionDoc, actionNotificationDoc, error) {
+func newActionDoc(st *State, receiverTag names.Tag, actionName string, parameters map[string]interface{}, opts actionOptions) (actionDoc, actionNotificationDoc, error) {
 	prefix := ensureActionMarker(receiverTag.Id())
 	actionId, err := NewUUID()
 	if err != nil {
@@ -271,6 +371,8 @@ func newActionDoc(st *State, receiverTag names.Tag, actionName string, parameter
 			Parameters: parameters,
 			Enqueued:   nowToTheSecond(),
 			Status:     ActionPending,
+			Operation:  opts.operation,
+			Timeout:    opts.timeout,
 		}, actionNotificationDoc{
 			DocId:    st.docID(prefix + actionId.String()),
 			EnvUUID:  envuuid,
@@ -331,6 +433,12 @@ func (st *State) FindActionTagsByPrefix(prefix string) []names.ActionTag {
 
 // EnqueueAction
 func (st *State) EnqueueAction(receiver names.Tag, actionName string, payload map[string]interface{}) (*Action, error) {
+	return st.enqueueAction(receiver, actionName, payload, actionOptions{})
+}
+
+// enqueueAction adds an action for the receiver, with the given
+// optional attributes.
+func (st *State) enqueueAction(receiver names.Tag, actionName string, payload map[string]interface{}, opts actionOptions) (*Action, error) {
 	if len(actionName) == 0 {
 		return nil, errors.New("action name required")
 	}
@@ -340,7 +448,7 @@ func (st *State) EnqueueAction(receiver names.Tag, actionName string, payload ma
 		return nil, errors.Trace(err)
 	}
 
-	doc, ndoc, err := newActionDoc(st, receiver, actionName, payload)
+	doc, ndoc, err := newActionDoc(st, receiver, actionName, payload, opts)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -463,6 +464,67 @@ func (s *ActionSuite) TestComplete(c *gc.C) {
 	c.Assert(len(actions), gc.Equals, 0)
 }
 
+func (s *ActionSuite) TestCancelPending(c *gc.C) {
+	action, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cancelled, err := action.Cancel()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cancelled.Status(), gc.Equals, state.ActionCancelled)
+	_, message := cancelled.Results()
+	c.Assert(message, gc.Equals, "action cancelled")
+
+	pending, err := s.unit.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.HasLen, 0)
+
+	_, err = action.Cancel()
+	c.Assert(err, gc.ErrorMatches, `cannot cancel action ".*": action ".*" already cancelled`)
+}
+
+func (s *ActionSuite) TestCancelRunning(c *gc.C) {
+	action, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	action, err = action.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.CancelRequested(), jc.IsFalse)
+
+	// A running action is only flagged; the unit stops it.
+	cancelling, err := s.unit.CancelAction(action)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cancelling.Status(), gc.Equals, state.ActionRunning)
+	c.Assert(cancelling.CancelRequested(), jc.IsTrue)
+
+	// Cancelling again is harmless.
+	cancelling, err = cancelling.Cancel()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cancelling.CancelRequested(), jc.IsTrue)
+
+	cancelled, err := cancelling.Finish(state.ActionResults{Status: state.ActionCancelled})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cancelled.Status(), gc.Equals, state.ActionCancelled)
+
+	_, err = cancelled.Cancel()
+	c.Assert(err, gc.ErrorMatches, `cannot cancel action ".*": action ".*" already cancelled`)
+}
+
+func (s *ActionSuite) TestAddActionWithTimeout(c *gc.C) {
+	action, err := s.unit.AddActionWithTimeout("snapshot", nil, 10*time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Timeout(), gc.Equals, 10*time.Minute)
+
+	action, err = s.State.Action(action.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Timeout(), gc.Equals, 10*time.Minute)
+
+	action, err = s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Timeout(), gc.Equals, time.Duration(0))
+
+	_, err = s.unit.AddActionWithTimeout("snapshot", nil, -time.Second)
+	c.Assert(err, gc.ErrorMatches, `invalid timeout -1s`)
+}
+
 func (s *ActionSuite) TestFindActionTagsByPrefix(c *gc.C) {
 	prefix := "feedbeef"
 	uuidMock := uuidMockHelper{}
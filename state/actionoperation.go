@@ -0,0 +1,427 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// actionOperationDoc groups the actions enqueued on the units of a
+// service by a single invocation.
+type actionOperationDoc struct {
+	DocId      string                 `bson:"_id"`
+	EnvUUID    string                 `bson:"env-uuid"`
+	Service    string                 `bson:"service"`
+	Name       string                 `bson:"name"`
+	Parameters map[string]interface{} `bson:"parameters"`
+	Enqueued   time.Time              `bson:"enqueued"`
+
+	// Units holds the names of the target units, in the order in
+	// which actions are enqueued on them.
+	Units []string `bson:"units"`
+
+	// BatchSize is the maximum number of the operation's actions that
+	// may be pending or running at once; zero means no limit.
+	BatchSize int `bson:"batchsize"`
+
+	// Next is the index in Units of the next unit on which to enqueue
+	// the action.
+	Next int `bson:"next"`
+
+	// Failures records the units on which the action could not be
+	// enqueued.
+	Failures []operationFailureDoc `bson:"failures,omitempty"`
+}
+
+type operationFailureDoc struct {
+	Unit  string `bson:"unit"`
+	Error string `bson:"error"`
+}
+
+// ActionOperation represents an action invoked on many units of a
+// service at once, either all together or a batch at a time.
+type ActionOperation struct {
+	st  *State
+	doc actionOperationDoc
+}
+
+// Id returns the id of the operation.
+func (op *ActionOperation) Id() string {
+	return op.st.localID(op.doc.DocId)
+}
+
+// Service returns the name of the service whose units are targeted.
+func (op *ActionOperation) Service() string {
+	return op.doc.Service
+}
+
+// Name returns the name of the action, as defined in the charm.
+func (op *ActionOperation) Name() string {
+	return op.doc.Name
+}
+
+// Parameters returns the parameters passed to each action.
+func (op *ActionOperation) Parameters() map[string]interface{} {
+	return op.doc.Parameters
+}
+
+// Enqueued returns the time the operation was started.
+func (op *ActionOperation) Enqueued() time.Time {
+	return op.doc.Enqueued
+}
+
+// Units returns the names of the units targeted by the operation.
+func (op *ActionOperation) Units() []string {
+	return op.doc.Units
+}
+
+// BatchSize returns the maximum number of the operation's actions
+// that may be pending or running at once; zero means no limit.
+func (op *ActionOperation) BatchSize() int {
+	return op.doc.BatchSize
+}
+
+// Waiting returns the names of the units on which the action has not
+// yet been enqueued.
+func (op *ActionOperation) Waiting() []string {
+	return op.doc.Units[op.doc.Next:]
+}
+
+// Failures returns the error for each unit on which the action could
+// not be enqueued.
+func (op *ActionOperation) Failures() map[string]string {
+	failures := make(map[string]string)
+	for _, f := range op.doc.Failures {
+		failures[f.Unit] = f.Error
+	}
+	return failures
+}
+
+// Actions returns the actions enqueued by the operation.
+func (op *ActionOperation) Actions() ([]*Action, error) {
+	return op.st.matchingActionsByOperation(op.Id(), nil)
+}
+
+// Refresh refreshes the contents of the ActionOperation from the
+// underlying state.
+func (op *ActionOperation) Refresh() error {
+	fresh, err := op.st.ActionOperation(op.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	op.doc = fresh.doc
+	return nil
+}
+
+// EnqueueActionOperation invokes the named action on the units of the
+// service with the given names, or on all of its alive units if none
+// are given. If batchSize is positive, at most that many of the actions
+// are pending or running at once; the next units' actions are enqueued
+// as earlier ones finish.
+func (s *Service) EnqueueActionOperation(name string, payload map[string]interface{}, unitNames []string, batchSize int) (*ActionOperation, error) {
+	if len(name) == 0 {
+		return nil, errors.New("no action name given")
+	}
+	if batchSize < 0 {
+		return nil, errors.Errorf("invalid batch size %d", batchSize)
+	}
+	ch, _, err := s.Charm()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var spec ActionSpecsByName
+	if actions := ch.Actions(); actions != nil {
+		spec = actions.ActionSpecs
+	}
+	actionSpec, ok := spec[name]
+	if !ok {
+		return nil, errors.Errorf("action %q not defined on service %q", name, s.Name())
+	}
+	if err := actionSpec.ValidateParams(payload); err != nil {
+		return nil, err
+	}
+	units, err := s.operationUnits(unitNames)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	id, err := NewUUID()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	doc := actionOperationDoc{
+		DocId:      s.st.docID(id.String()),
+		EnvUUID:    s.st.EnvironUUID(),
+		Service:    s.Name(),
+		Name:       name,
+		Parameters: payload,
+		Enqueued:   nowToTheSecond(),
+		Units:      units,
+		BatchSize:  batchSize,
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: isAliveDoc,
+	}, {
+		C:      actionOperationsC,
+		Id:     doc.DocId,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := s.st.runTransaction(ops); err != nil {
+		return nil, errors.Annotatef(
+			onAbort(err, errors.New("service is not alive")),
+			"cannot enqueue action %q on service %q", name, s.Name(),
+		)
+	}
+	if err := s.st.advanceActionOperation(id.String()); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return s.st.ActionOperation(id.String())
+}
+
+// operationUnits returns the names of the units an operation should
+// target: the given units, which must belong to the service, or all
+// alive units of the service, in order of unit number.
+func (s *Service) operationUnits(unitNames []string) ([]string, error) {
+	if len(unitNames) > 0 {
+		seen := make(map[string]bool)
+		for _, unitName := range unitNames {
+			if !names.IsValidUnit(unitName) {
+				return nil, errors.NotValidf("unit name %q", unitName)
+			}
+			if serviceName, _ := names.UnitService(unitName); serviceName != s.Name() {
+				return nil, errors.Errorf("unit %q does not belong to service %q", unitName, s.Name())
+			}
+			if seen[unitName] {
+				return nil, errors.Errorf("unit %q specified more than once", unitName)
+			}
+			seen[unitName] = true
+		}
+		return unitNames, nil
+	}
+	units, err := s.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var alive []*Unit
+	for _, u := range units {
+		if u.Life() == Alive {
+			alive = append(alive, u)
+		}
+	}
+	if len(alive) == 0 {
+		return nil, errors.Errorf("service %q has no alive units", s.Name())
+	}
+	sort.Sort(byUnitNumber(alive))
+	result := make([]string, len(alive))
+	for i, u := range alive {
+		result[i] = u.Name()
+	}
+	return result, nil
+}
+
+// byUnitNumber sorts the units of a single service by unit number.
+// Since the names share the service prefix, shorter names have lower
+// numbers.
+type byUnitNumber []*Unit
+
+func (u byUnitNumber) Len() int      { return len(u) }
+func (u byUnitNumber) Swap(i, j int) { u[i], u[j] = u[j], u[i] }
+func (u byUnitNumber) Less(i, j int) bool {
+	a, b := u[i].doc.Name, u[j].doc.Name
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+// advanceActionOperation enqueues the operation's action on as many of
+// its remaining units as its batch size allows.
+func (st *State) advanceActionOperation(id string) error {
+	for {
+		op, err := st.ActionOperation(id)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := op.failOrphanedActions(); err != nil {
+			return errors.Annotatef(err, "cannot advance operation %q", id)
+		}
+		claimed, err := op.claimUnits()
+		if err != nil {
+			return errors.Annotatef(err, "cannot advance operation %q", id)
+		}
+		if len(claimed) == 0 {
+			return nil
+		}
+		failures, err := op.enqueueOn(claimed)
+		if err != nil {
+			return errors.Annotatef(err, "cannot advance operation %q", id)
+		}
+		if failures == 0 {
+			return nil
+		}
+		// Units on which the action could not be enqueued leave
+		// their slots in the batch free; fill them.
+	}
+}
+
+// failOrphanedActions fails the operation's pending or running actions
+// whose units are dead or have been removed, since they will never be
+// run, so that they no longer hold slots in the batch.
+func (op *ActionOperation) failOrphanedActions() error {
+	active, err := op.st.matchingActionsByOperation(op.Id(), activeActionStatus)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, action := range active {
+		unit, err := op.st.Unit(action.Receiver())
+		if err == nil && unit.Life() != Dead {
+			continue
+		} else if err != nil && !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+		isActive := bson.D{{"$in", []ActionStatus{ActionPending, ActionRunning}}}
+		ops := action.finishOps(isActive, ActionFailed, nil, "unit removed")
+		if err := op.st.runTransaction(ops); err != nil && err != txn.ErrAborted {
+			// An aborted transaction means the action finished
+			// in the meantime.
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// claimUnits reserves the next units on which to enqueue the action,
+// so that concurrent advances never enqueue it twice on a unit.
+func (op *ActionOperation) claimUnits() ([]string, error) {
+	var claimed []string
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		claimed = nil
+		if attempt > 0 {
+			if err := op.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		count := len(op.doc.Units) - op.doc.Next
+		if op.doc.BatchSize > 0 {
+			active, err := op.st.matchingActionsByOperation(op.Id(), activeActionStatus)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if slots := op.doc.BatchSize - len(active); slots < count {
+				count = slots
+			}
+		}
+		if count <= 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		claimed = op.doc.Units[op.doc.Next : op.doc.Next+count]
+		return []txn.Op{{
+			C:      actionOperationsC,
+			Id:     op.doc.DocId,
+			Assert: bson.D{{"next", op.doc.Next}},
+			Update: bson.D{{"$set", bson.D{{"next", op.doc.Next + count}}}},
+		}}, nil
+	}
+	if err := op.st.run(buildTxn); err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// enqueueOn enqueues the operation's action on each of the named
+// units, recording any failures. It returns the number of failures.
+func (op *ActionOperation) enqueueOn(unitNames []string) (int, error) {
+	var failures []operationFailureDoc
+	for _, unitName := range unitNames {
+		unit, err := op.st.Unit(unitName)
+		if err == nil {
+			payload := make(map[string]interface{})
+			for k, v := range op.doc.Parameters {
+				payload[k] = v
+			}
+			_, err = unit.addAction(op.doc.Name, payload, actionOptions{operation: op.Id()})
+		}
+		if err != nil {
+			failures = append(failures, operationFailureDoc{unitName, err.Error()})
+		}
+	}
+	if len(failures) == 0 {
+		return 0, nil
+	}
+	ops := []txn.Op{{
+		C:      actionOperationsC,
+		Id:     op.doc.DocId,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$push", bson.D{{"failures", bson.D{{"$each", failures}}}}}},
+	}}
+	if err := op.st.runTransaction(ops); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return len(failures), nil
+}
+
+var activeActionStatus = bson.D{{"status", bson.D{{"$in", []ActionStatus{
+	ActionPending, ActionRunning,
+}}}}}
+
+// matchingActionsByOperation finds the actions enqueued by the
+// operation that satisfy the optional status condition.
+func (st *State) matchingActionsByOperation(id string, statusCondition bson.D) ([]*Action, error) {
+	var doc actionDoc
+	var actions []*Action
+
+	actionsCollection, closer := st.getCollection(actionsC)
+	defer closer()
+
+	sel := append(bson.D{{"operation", id}}, statusCondition...)
+	iter := actionsCollection.Find(sel).Iter()
+	for iter.Next(&doc) {
+		actions = append(actions, newAction(st, doc))
+	}
+	return actions, errors.Trace(iter.Close())
+}
+
+// ActionOperation returns the operation with the given id.
+func (st *State) ActionOperation(id string) (*ActionOperation, error) {
+	operations, closer := st.getCollection(actionOperationsC)
+	defer closer()
+
+	var doc actionOperationDoc
+	err := operations.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("operation %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get operation %q", id)
+	}
+	return &ActionOperation{st, doc}, nil
+}
+
+// FindActionOperationsByPrefix returns the operations whose ids start
+// with the given prefix.
+func (st *State) FindActionOperationsByPrefix(prefix string) ([]*ActionOperation, error) {
+	operations, closer := st.getCollection(actionOperationsC)
+	defer closer()
+
+	var docs []actionOperationDoc
+	sel := bson.D{{"_id", bson.D{{"$regex", "^" + st.docID(prefix)}}}}
+	if err := operations.Find(sel).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get operations")
+	}
+	result := make([]*ActionOperation, len(docs))
+	for i, doc := range docs {
+		result[i] = &ActionOperation{st, doc}
+	}
+	return result, nil
+}
@@ -0,0 +1,183 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type ActionOperationSuite struct {
+	ConnSuite
+	service *state.Service
+}
+
+var _ = gc.Suite(&ActionOperationSuite{})
+
+func (s *ActionOperationSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	for i := 0; i < 3; i++ {
+		_, err := s.service.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *ActionOperationSuite) operationActions(c *gc.C, op *state.ActionOperation) map[string]*state.Action {
+	actions, err := op.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	byUnit := make(map[string]*state.Action)
+	for _, action := range actions {
+		c.Assert(action.Operation(), gc.Equals, op.Id())
+		byUnit[action.Receiver()] = action
+	}
+	return byUnit
+}
+
+func (s *ActionOperationSuite) TestEnqueueOnAllUnits(c *gc.C) {
+	params := map[string]interface{}{"outfile": "out.bz2"}
+	op, err := s.service.EnqueueActionOperation("snapshot", params, nil, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.Service(), gc.Equals, "dummy")
+	c.Assert(op.Name(), gc.Equals, "snapshot")
+	c.Assert(op.Parameters(), jc.DeepEquals, params)
+	c.Assert(op.Units(), jc.DeepEquals, []string{"dummy/0", "dummy/1", "dummy/2"})
+	c.Assert(op.Waiting(), gc.HasLen, 0)
+	c.Assert(op.Failures(), gc.HasLen, 0)
+
+	actions := s.operationActions(c, op)
+	c.Assert(actions, gc.HasLen, 3)
+	for _, unitName := range op.Units() {
+		action, ok := actions[unitName]
+		c.Assert(ok, jc.IsTrue)
+		c.Check(action.Name(), gc.Equals, "snapshot")
+		c.Check(action.Parameters(), jc.DeepEquals, params)
+		c.Check(action.Status(), gc.Equals, state.ActionPending)
+	}
+
+	fetched, err := s.State.FindActionOperationsByPrefix(op.Id()[:8])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fetched, gc.HasLen, 1)
+	c.Assert(fetched[0].Id(), gc.Equals, op.Id())
+}
+
+func (s *ActionOperationSuite) TestEnqueueOnSubset(c *gc.C) {
+	op, err := s.service.EnqueueActionOperation("snapshot", nil, []string{"dummy/2", "dummy/0"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.Units(), jc.DeepEquals, []string{"dummy/2", "dummy/0"})
+
+	actions := s.operationActions(c, op)
+	c.Assert(actions, gc.HasLen, 2)
+	_, ok := actions["dummy/1"]
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *ActionOperationSuite) TestEnqueueInBatches(c *gc.C) {
+	op, err := s.service.EnqueueActionOperation("snapshot", nil, nil, 2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.BatchSize(), gc.Equals, 2)
+	c.Assert(op.Waiting(), jc.DeepEquals, []string{"dummy/2"})
+
+	actions := s.operationActions(c, op)
+	c.Assert(actions, gc.HasLen, 2)
+
+	// Starting an action does not free its slot.
+	_, err = actions["dummy/0"].Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.Refresh(), jc.ErrorIsNil)
+	c.Assert(op.Waiting(), jc.DeepEquals, []string{"dummy/2"})
+
+	// Finishing it, successfully or not, does.
+	_, err = actions["dummy/0"].Finish(state.ActionResults{Status: state.ActionFailed})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.Refresh(), jc.ErrorIsNil)
+	c.Assert(op.Waiting(), gc.HasLen, 0)
+	c.Assert(s.operationActions(c, op), gc.HasLen, 3)
+}
+
+func (s *ActionOperationSuite) TestEnqueueRecordsFailures(c *gc.C) {
+	op, err := s.service.EnqueueActionOperation("snapshot", nil, []string{"dummy/9", "dummy/0", "dummy/1"}, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The missing unit's slot is refilled at once.
+	c.Assert(op.Failures(), jc.DeepEquals, map[string]string{
+		"dummy/9": `unit "dummy/9" not found`,
+	})
+	c.Assert(op.Waiting(), jc.DeepEquals, []string{"dummy/1"})
+	c.Assert(s.operationActions(c, op), gc.HasLen, 1)
+}
+
+func (s *ActionOperationSuite) TestRemovedUnitFreesSlot(c *gc.C) {
+	unit, err := s.State.Unit("dummy/0")
+	c.Assert(err, jc.ErrorIsNil)
+	// An already finished action must not block the cleanup.
+	earlier, err := unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = earlier.Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+
+	op, err := s.service.EnqueueActionOperation("snapshot", nil, []string{"dummy/0", "dummy/1"}, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.Waiting(), jc.DeepEquals, []string{"dummy/1"})
+
+	c.Assert(unit.EnsureDead(), jc.ErrorIsNil)
+	c.Assert(unit.Remove(), jc.ErrorIsNil)
+	c.Assert(s.State.Cleanup(), jc.ErrorIsNil)
+
+	c.Assert(op.Refresh(), jc.ErrorIsNil)
+	c.Assert(op.Waiting(), gc.HasLen, 0)
+	actions := s.operationActions(c, op)
+	c.Assert(actions, gc.HasLen, 2)
+	c.Assert(actions["dummy/0"].Status(), gc.Equals, state.ActionCancelled)
+	c.Assert(actions["dummy/1"].Status(), gc.Equals, state.ActionPending)
+}
+
+func (s *ActionOperationSuite) TestDeadUnitActionFailed(c *gc.C) {
+	_, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	op, err := s.service.EnqueueActionOperation("snapshot", nil, nil, 2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.Waiting(), jc.DeepEquals, []string{"dummy/2", "dummy/3"})
+
+	unit, err := s.State.Unit("dummy/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit.EnsureDead(), jc.ErrorIsNil)
+
+	// Advancing the operation fails the dead unit's action as well,
+	// freeing both slots.
+	actions := s.operationActions(c, op)
+	_, err = actions["dummy/1"].Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(op.Refresh(), jc.ErrorIsNil)
+	c.Assert(op.Waiting(), gc.HasLen, 0)
+	actions = s.operationActions(c, op)
+	c.Assert(actions, gc.HasLen, 4)
+	c.Assert(actions["dummy/0"].Status(), gc.Equals, state.ActionFailed)
+	_, message := actions["dummy/0"].Results()
+	c.Assert(message, gc.Equals, "unit removed")
+}
+
+func (s *ActionOperationSuite) TestEnqueueErrors(c *gc.C) {
+	_, err := s.service.EnqueueActionOperation("missing", nil, nil, 0)
+	c.Assert(err, gc.ErrorMatches, `action "missing" not defined on service "dummy"`)
+
+	_, err = s.service.EnqueueActionOperation("snapshot", map[string]interface{}{"outfile": 5.0}, nil, 0)
+	c.Assert(err, gc.ErrorMatches, `validation failed: .*`)
+
+	_, err = s.service.EnqueueActionOperation("snapshot", nil, nil, -1)
+	c.Assert(err, gc.ErrorMatches, `invalid batch size -1`)
+
+	_, err = s.service.EnqueueActionOperation("snapshot", nil, []string{"wordpress/0"}, 0)
+	c.Assert(err, gc.ErrorMatches, `unit "wordpress/0" does not belong to service "dummy"`)
+
+	_, err = s.service.EnqueueActionOperation("snapshot", nil, []string{"dummy/0", "dummy/0"}, 0)
+	c.Assert(err, gc.ErrorMatches, `unit "dummy/0" specified more than once`)
+
+	_, err = s.State.ActionOperation("nonsense")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
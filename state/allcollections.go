@@ -115,7 +115,12 @@ func allCollections() collectionSchema {
 
 		// This collection holds workload metrics reported by certain charms
 		// for passing onward to other tools.
-		metricsC: {global: true},
+		metricsC: {
+			global: true,
+			indexes: []mgo.Index{{
+				Key: []string{"env-uuid", "unit", "created"},
+			}},
+		},
 
 		// This collection holds persistent state for the metrics manager.
 		metricsManagerC: {global: true},
@@ -211,6 +216,29 @@ func allCollections() collectionSchema {
 		machinesC:      {},
 		rebootC:        {},
 
+		// This collection holds the progress of evacuating units from
+		// machines that have been drained.
+		machineDrainsC: {},
+
+		// -----
+
+		// This collection holds logging configuration set for
+		// individual agents, which expires after a time.
+		loggingOverridesC: {},
+
+		// -----
+
+		// These collections hold locally defined metric thresholds,
+		// and the alerts raised when units breach them.
+		metricThresholdsC: {},
+		metricAlertsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"env-uuid", "active"},
+			}, {
+				Key: []string{"env-uuid", "raised"},
+			}},
+		},
+
 		// -----
 
 		// These collections hold information associated with storage.
@@ -243,6 +271,16 @@ func allCollections() collectionSchema {
 			}},
 		},
 		volumeAttachmentsC: {},
+		volumeSnapshotsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"env-uuid", "storageid"},
+			}},
+		},
+		storageResizesC: {
+			indexes: []mgo.Index{{
+				Key: []string{"env-uuid", "storageid"},
+			}},
+		},
 
 		// -----
 
@@ -291,8 +329,19 @@ func allCollections() collectionSchema {
 		// -----
 
 		// These collections hold information associated with actions.
-		actionsC:             {},
+		actionsC: {
+			indexes: []mgo.Index{{
+				Key:    []string{"env-uuid", "operation"},
+				Sparse: true,
+			}},
+		},
 		actionNotificationsC: {},
+		actionOperationsC:    {},
+		scheduledActionsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"env-uuid", "nextrun"},
+			}},
+		},
 
 		// -----
 
@@ -300,7 +349,18 @@ func allCollections() collectionSchema {
 
 		// This collection holds information associated with charm payloads.
 		// See payload/persistence/mongo.go.
-		"payloads": {},
+		payloadsC: {},
+
+		// -----
+
+		// These collections hold outbound webhook subscriptions, and
+		// the deliveries that could not be made to them.
+		webhooksC: {},
+		webhookDeadLettersC: {
+			indexes: []mgo.Index{{
+				Key: []string{"env-uuid", "webhookid"},
+			}},
+		},
 
 		// -----
 
@@ -347,6 +407,7 @@ func allCollections() collectionSchema {
 // inspection.
 const (
 	actionNotificationsC   = "actionnotifications"
+	actionOperationsC      = "actionoperations"
 	actionresultsC         = "actionresults"
 	actionsC               = "actions"
 	annotationsC           = "annotations"
@@ -366,8 +427,12 @@ const (
 	ipaddressesC           = "ipaddresses"
 	leaseC                 = "lease"
 	leasesC                = "leases"
+	loggingOverridesC      = "loggingoverrides"
+	machineDrainsC         = "machinedrains"
 	machinesC              = "machines"
 	meterStatusC           = "meterStatus"
+	metricAlertsC          = "metricalerts"
+	metricThresholdsC      = "metricthresholds"
 	metricsC               = "metrics"
 	metricsManagerC        = "metricsmanager"
 	minUnitsC              = "minunits"
@@ -379,6 +444,7 @@ const (
 	relationsC             = "relations"
 	requestedNetworksC     = "requestednetworks"
 	restoreInfoC           = "restoreInfo"
+	scheduledActionsC      = "scheduledactions"
 	sequenceC              = "sequence"
 	servicesC              = "services"
 	settingsC              = "settings"
@@ -389,6 +455,7 @@ const (
 	storageAttachmentsC    = "storageattachments"
 	storageConstraintsC    = "storageconstraints"
 	storageInstancesC      = "storageinstances"
+	storageResizesC        = "storageresizes"
 	subnetsC               = "subnets"
 	spacesC                = "spaces"
 	toolsmetadataC         = "toolsmetadata"
@@ -402,5 +469,8 @@ const (
 	envUserLastConnectionC = "envUserLastConnection"
 	volumeAttachmentsC     = "volumeattachments"
 	volumesC               = "volumes"
-	// "payloads" (see payload/persistence/mongo.go)
+	volumeSnapshotsC       = "volumesnapshots"
+	webhookDeadLettersC    = "webhookdeadletters"
+	webhooksC              = "webhooks"
+	payloadsC              = "payloads" // see payload/persistence/mongo.go
 )
@@ -74,6 +74,8 @@ func makeAllWatcherCollectionInfo(collNames ...string) map[string]allWatcherStat
 			collection.docType = reflect.TypeOf(backingAnnotation{})
 		case blocksC:
 			collection.docType = reflect.TypeOf(backingBlock{})
+		case payloadsC:
+			collection.docType = reflect.TypeOf(backingPayload{})
 		case statusesC:
 			collection.docType = reflect.TypeOf(backingStatus{})
 			collection.subsidiary = true
@@ -260,6 +262,7 @@ func (u *backingUnit) updated(st *State, store *multiwatcherStore, id string) er
 		Series:      u.Series,
 		MachineId:   u.MachineId,
 		Subordinate: u.Principal != "",
+		Principal:   u.Principal,
 		StatusData:  make(map[string]interface{}),
 	}
 	if u.CharmURL != nil {
@@ -605,6 +608,48 @@ func (a *backingBlock) mongoId() string {
 	return a.DocID
 }
 
+// backingPayload mirrors the payload documents written by
+// payload/persistence, which owns the payloads collection.
+type backingPayload struct {
+	DocID   string   `bson:"_id"`
+	EnvUUID string   `bson:"env-uuid"`
+	UnitID  string   `bson:"unitid"`
+	Name    string   `bson:"name"`
+	Type    string   `bson:"type"`
+	State   string   `bson:"state"`
+	Labels  []string `bson:"labels"`
+	RawID   string   `bson:"rawid"`
+}
+
+func (p *backingPayload) updated(st *State, store *multiwatcherStore, id string) error {
+	// The id is env-prefixed when all entities are loaded.
+	info := &multiwatcher.PayloadInfo{
+		EnvUUID: st.EnvironUUID(),
+		Id:      st.localID(id),
+		Unit:    p.UnitID,
+		Class:   p.Name,
+		Type:    p.Type,
+		RawId:   p.RawID,
+		Status:  p.State,
+		Labels:  p.Labels,
+	}
+	store.Update(info)
+	return nil
+}
+
+func (p *backingPayload) removed(store *multiwatcherStore, envUUID, id string, _ *State) error {
+	store.Remove(multiwatcher.EntityId{
+		Kind:    "payload",
+		EnvUUID: envUUID,
+		Id:      id,
+	})
+	return nil
+}
+
+func (p *backingPayload) mongoId() string {
+	return p.DocID
+}
+
 type backingStatus statusDoc
 
 func (s *backingStatus) updated(st *State, store *multiwatcherStore, id string) error {
@@ -1004,6 +1049,7 @@ func newAllWatcherStateBacking(st *State) Backing {
 		openedPortsC,
 		actionsC,
 		blocksC,
+		payloadsC,
 	)
 	return &allWatcherStateBacking{
 		st:               st,
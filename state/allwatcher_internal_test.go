@@ -15,6 +15,7 @@ import (
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/mgo.v2/txn"
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/instance"
@@ -36,6 +37,7 @@ var (
 	_ backingEntityDoc = (*backingOpenedPorts)(nil)
 	_ backingEntityDoc = (*backingAction)(nil)
 	_ backingEntityDoc = (*backingBlock)(nil)
+	_ backingEntityDoc = (*backingPayload)(nil)
 )
 
 var dottedConfig = `
@@ -248,6 +250,7 @@ func (s *allWatcherBaseSuite) setUpScenario(c *gc.C, st *State, units int) (enti
 			Status:      multiwatcher.Status("pending"),
 			StatusData:  map[string]interface{}{},
 			Subordinate: true,
+			Principal:   fmt.Sprintf("wordpress/%d", i),
 			WorkloadStatus: multiwatcher.StatusInfo{
 				Current: "unknown",
 				Message: "Waiting for agent initialization to finish",
@@ -511,6 +514,87 @@ func (s *allWatcherStateSuite) TestChangeBlocks(c *gc.C) {
 	s.performChangeTestCases(c, changeTestFuncs)
 }
 
+func (s *allWatcherStateSuite) TestChangePayloads(c *gc.C) {
+	insertPayload := func(c *gc.C, st *State, localID string) {
+		err := st.runTransaction([]txn.Op{{
+			C:      payloadsC,
+			Id:     st.docID(localID),
+			Assert: txn.DocMissing,
+			Insert: &backingPayload{
+				DocID:  st.docID(localID),
+				UnitID: "wordpress/0",
+				Name:   "spam",
+				Type:   "docker",
+				State:  "running",
+				Labels: []string{"a-tag"},
+				RawID:  "idspam",
+			},
+		}})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	payloadInfo := func(st *State, localID, status string) *multiwatcher.PayloadInfo {
+		return &multiwatcher.PayloadInfo{
+			EnvUUID: st.EnvironUUID(),
+			Id:      localID,
+			Unit:    "wordpress/0",
+			Class:   "spam",
+			Type:    "docker",
+			RawId:   "idspam",
+			Status:  status,
+			Labels:  []string{"a-tag"},
+		}
+	}
+	changeTestFuncs := []changeTestFunc{
+		func(c *gc.C, st *State) changeTestCase {
+			return changeTestCase{
+				about: "no payload in state, no payload in store -> do nothing",
+				change: watcher.Change{
+					C:  payloadsC,
+					Id: st.docID("payload#wordpress/0#1"),
+				}}
+		},
+		func(c *gc.C, st *State) changeTestCase {
+			insertPayload(c, st, "payload#wordpress/0#1")
+			return changeTestCase{
+				about: "payload is added if it's in backing but not in store",
+				change: watcher.Change{
+					C:  payloadsC,
+					Id: st.docID("payload#wordpress/0#1"),
+				},
+				expectContents: []multiwatcher.EntityInfo{
+					payloadInfo(st, "payload#wordpress/0#1", "running"),
+				}}
+		},
+		func(c *gc.C, st *State) changeTestCase {
+			insertPayload(c, st, "payload#wordpress/0#1")
+			return changeTestCase{
+				about: "payload is updated if it's in backing and in store",
+				initialContents: []multiwatcher.EntityInfo{
+					payloadInfo(st, "payload#wordpress/0#1", "starting"),
+				},
+				change: watcher.Change{
+					C:  payloadsC,
+					Id: st.docID("payload#wordpress/0#1"),
+				},
+				expectContents: []multiwatcher.EntityInfo{
+					payloadInfo(st, "payload#wordpress/0#1", "running"),
+				}}
+		},
+		func(c *gc.C, st *State) changeTestCase {
+			return changeTestCase{
+				about: "payload is removed if it's not in backing but is in store",
+				initialContents: []multiwatcher.EntityInfo{
+					payloadInfo(st, "payload#wordpress/0#1", "running"),
+				},
+				change: watcher.Change{
+					C:  payloadsC,
+					Id: st.docID("payload#wordpress/0#1"),
+				}}
+		},
+	}
+	s.performChangeTestCases(c, changeTestFuncs)
+}
+
 func (s *allWatcherStateSuite) TestClosingPorts(c *gc.C) {
 	defer s.Reset(c)
 	// Init the test environment.
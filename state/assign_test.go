@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/txn"
@@ -671,6 +672,160 @@ func (s *AssignSuite) TestAssignUnitWithSubordinate(c *gc.C) {
 	}
 }
 
+func (s *AssignSuite) addMySQLUnitToMachine(c *gc.C, cons constraints.Value) *state.Machine {
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	err := mysql.SetConstraints(cons)
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	return machine
+}
+
+func (s *AssignSuite) TestAssignUnitPackPolicy(c *gc.C) {
+	machine := s.addMySQLUnitToMachine(c, constraints.Value{})
+	err := s.wordpress.SetConstraints(constraints.MustParse("placement=pack"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	for _, policy := range []state.AssignmentPolicy{state.AssignClean, state.AssignCleanEmpty} {
+		unit, err := s.wordpress.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		err = s.State.AssignUnit(unit, policy)
+		c.Assert(err, jc.ErrorIsNil)
+		id, err := unit.AssignedMachineId()
+		c.Assert(err, jc.ErrorIsNil)
+		if policy == state.AssignClean {
+			// The first unit is packed alongside mysql.
+			c.Assert(id, gc.Equals, machine.Id())
+			assertMachineCount(c, s.State, 1)
+		} else {
+			// The only in-use machine already hosts a wordpress
+			// unit, so a new machine is needed.
+			c.Assert(id, gc.Not(gc.Equals), machine.Id())
+			assertMachineCount(c, s.State, 2)
+		}
+	}
+}
+
+func (s *AssignSuite) TestAssignUnitSpreadPolicyIgnoresInUseMachines(c *gc.C) {
+	machine := s.addMySQLUnitToMachine(c, constraints.Value{})
+	err := s.wordpress.SetConstraints(constraints.MustParse("placement=spread"))
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Not(gc.Equals), machine.Id())
+	assertMachineCount(c, s.State, 2)
+}
+
+func (s *AssignSuite) TestAssignUnitPackPolicyHonoursAntiAffinity(c *gc.C) {
+	// The anti-affinity is declared on mysql only, but applies in
+	// both directions.
+	machine := s.addMySQLUnitToMachine(c, constraints.MustParse("anti-affinity=wordpress"))
+	err := s.wordpress.SetConstraints(constraints.MustParse("placement=pack"))
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Not(gc.Equals), machine.Id())
+	assertMachineCount(c, s.State, 2)
+}
+
+func (s *AssignSuite) TestAssignUnitWithPlacementHonoursAntiAffinity(c *gc.C) {
+	machine := s.addMySQLUnitToMachine(c, constraints.Value{})
+	err := s.wordpress.SetConstraints(constraints.MustParse("anti-affinity=mysql"))
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	placement := &instance.Placement{Scope: instance.MachineScope, Directive: machine.Id()}
+	err = s.State.AssignUnitWithPlacement(unit, placement, nil)
+	c.Assert(err, gc.ErrorMatches, `unit "wordpress/0" cannot share machine "0" with service "mysql" \(anti-affinity\)`)
+	_, err = unit.AssignedMachineId()
+	c.Assert(err, jc.Satisfies, errors.IsNotAssigned)
+
+	placement = &instance.Placement{Scope: "lxc", Directive: machine.Id()}
+	err = s.State.AssignUnitWithPlacement(unit, placement, nil)
+	c.Assert(err, gc.ErrorMatches, `unit "wordpress/0" cannot share machine "0" with service "mysql" \(anti-affinity\)`)
+	assertMachineCount(c, s.State, 1)
+}
+
+func (s *AssignSuite) TestAssignToMachineHonoursAntiAffinity(c *gc.C) {
+	machine := s.addMySQLUnitToMachine(c, constraints.Value{})
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, machine.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpress.SetConstraints(constraints.MustParse("anti-affinity=mysql"))
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/0" to machine 0: unit "wordpress/0" cannot share machine "0" with service "mysql" \(anti-affinity\)`)
+	err = unit.AssignToMachine(container)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/0" to machine 0/lxc/0: unit "wordpress/0" cannot share machine "0" with service "mysql" \(anti-affinity\)`)
+	_, err = unit.AssignedMachineId()
+	c.Assert(err, jc.Satisfies, errors.IsNotAssigned)
+}
+
+func (s *AssignSuite) TestAssignToCleanMachineHonoursAntiAffinity(c *gc.C) {
+	// The only clean machine is a container on a machine hosting a
+	// mysql unit.
+	machine := s.addMySQLUnitToMachine(c, constraints.Value{})
+	_, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, machine.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpress.SetConstraints(constraints.MustParse("anti-affinity=mysql"))
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = unit.AssignToCleanMachine()
+	c.Assert(err, gc.ErrorMatches, eligibleMachinesInUse)
+	_, err = unit.AssignedMachineId()
+	c.Assert(err, jc.Satisfies, errors.IsNotAssigned)
+}
+
+func (s *AssignSuite) TestAssignToMachineAntiAffinityConcurrentAssignment(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, machine.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	mysqlUnit, err := mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpress.SetConstraints(constraints.MustParse("anti-affinity=mysql"))
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		err := mysqlUnit.AssignToMachine(container)
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/0" to machine 0: unit "wordpress/0" cannot share machine "0" with service "mysql" \(anti-affinity\)`)
+	_, err = unit.AssignedMachineId()
+	c.Assert(err, jc.Satisfies, errors.IsNotAssigned)
+}
+
 func assertMachineCount(c *gc.C, st *state.State, expect int) {
 	ms, err := st.AllMachines()
 	c.Assert(err, jc.ErrorIsNil)
@@ -28,6 +28,7 @@ const (
 	cleanupAttachmentsForDyingStorage    cleanupKind = "storageAttachments"
 	cleanupAttachmentsForDyingVolume     cleanupKind = "volumeAttachments"
 	cleanupAttachmentsForDyingFilesystem cleanupKind = "filesystemAttachments"
+	cleanupStorageForRemovedService      cleanupKind = "serviceStorage"
 )
 
 // cleanupDoc represents a potentially large set of documents that should be
@@ -99,6 +100,8 @@ func (st *State) Cleanup() (err error) {
 			err = st.cleanupAttachmentsForDyingVolume(doc.Prefix)
 		case cleanupAttachmentsForDyingFilesystem:
 			err = st.cleanupAttachmentsForDyingFilesystem(doc.Prefix)
+		case cleanupStorageForRemovedService:
+			err = st.cleanupStorageForRemovedService(doc.Prefix)
 		default:
 			err = fmt.Errorf("unknown cleanup kind %q", doc.Kind)
 		}
@@ -242,7 +245,24 @@ func (st *State) cleanupRemovedUnit(unitId string) error {
 
 	cancelled := ActionResults{Status: ActionCancelled, Message: "unit removed"}
 	for _, action := range actions {
-		if _, err = action.Finish(cancelled); err != nil {
+		switch action.Status() {
+		case ActionCompleted, ActionCancelled, ActionFailed:
+			continue
+		}
+		if _, err = action.Finish(cancelled); err == txn.ErrAborted {
+			// The action finished in the meantime.
+			continue
+		} else if err != nil {
+			return err
+		}
+	}
+
+	scheduled, err := st.ScheduledActionsForUnit(unitId)
+	if err != nil {
+		return err
+	}
+	for _, sa := range scheduled {
+		if err := sa.Remove(); err != nil {
 			return err
 		}
 	}
@@ -428,6 +448,13 @@ func (st *State) cleanupAttachmentsForDyingStorage(storageId string) (err error)
 	return nil
 }
 
+// cleanupStorageForRemovedService destroys all storage instances owned
+// by the specified service. It's expected to be used when a service is
+// removed, to destroy storage detached from the service's units.
+func (st *State) cleanupStorageForRemovedService(serviceName string) error {
+	return st.destroyServiceStorage(names.NewServiceTag(serviceName))
+}
+
 // cleanupAttachmentsForDyingVolume sets all volume attachments related
 // to the specified volume to Dying, if they are not already Dying or
 // Dead. It's expected to be used when a volume is destroyed.
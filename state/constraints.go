@@ -29,7 +29,9 @@ type constraintsDoc struct {
 	Spaces       *[]string
 	// TODO(dimitern): Drop this once it's not possible to specify
 	// networks= in constraints.
-	Networks *[]string
+	Networks     *[]string
+	Placement    *string
+	AntiAffinity *[]string
 }
 
 func (doc constraintsDoc) value() constraints.Value {
@@ -44,6 +46,8 @@ func (doc constraintsDoc) value() constraints.Value {
 		Tags:         doc.Tags,
 		Spaces:       doc.Spaces,
 		Networks:     doc.Networks,
+		Placement:    doc.Placement,
+		AntiAffinity: doc.AntiAffinity,
 	}
 }
 
@@ -60,6 +64,8 @@ func newConstraintsDoc(st *State, cons constraints.Value) constraintsDoc {
 		Tags:         cons.Tags,
 		Spaces:       cons.Spaces,
 		Networks:     cons.Networks,
+		Placement:    cons.Placement,
+		AntiAffinity: cons.AntiAffinity,
 	}
 }
 
@@ -5,9 +5,15 @@ package state
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/instance"
 )
 
@@ -75,3 +81,183 @@ func ServiceInstances(st *State, service string) ([]instance.Id, error) {
 	}
 	return instanceIds, nil
 }
+
+// antiAffinityServices returns the names of the services with which the
+// given unit has an anti-affinity: those named in the unit's
+// anti-affinity constraint, and those whose own anti-affinity constraint
+// names the unit's service.
+func antiAffinityServices(u *Unit, cons *constraints.Value) (set.Strings, error) {
+	excluded := set.NewStrings()
+	if cons.AntiAffinity != nil {
+		for _, service := range *cons.AntiAffinity {
+			if service != u.doc.Service {
+				excluded.Add(service)
+			}
+		}
+	}
+	services, err := u.st.AllServices()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, svc := range services {
+		if svc.Name() == u.doc.Service || excluded.Contains(svc.Name()) {
+			continue
+		}
+		svcCons, err := svc.Constraints()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !svcCons.HaveAntiAffinity() {
+			continue
+		}
+		for _, service := range *svcCons.AntiAffinity {
+			if service == u.doc.Service {
+				excluded.Add(svc.Name())
+				break
+			}
+		}
+	}
+	return excluded, nil
+}
+
+// antiAffinityMachines returns the ids of the top-level machines that must
+// not host the given unit, mapped to the name of the service responsible.
+// A machine is excluded if it, or any container within it, hosts a unit
+// of a service with which the unit has an anti-affinity.
+func antiAffinityMachines(u *Unit, cons *constraints.Value) (map[string]string, error) {
+	excluded, err := antiAffinityServices(u, cons)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return serviceMachines(u.st, excluded)
+}
+
+// serviceMachines returns the ids of the top-level machines hosting units
+// of the given services, mapped to the name of one such service.
+func serviceMachines(st *State, services set.Strings) (map[string]string, error) {
+	machines := make(map[string]string)
+	for _, service := range services.SortedValues() {
+		units, err := allUnits(st, service)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, unit := range units {
+			machineId, err := unit.AssignedMachineId()
+			if errors.IsNotAssigned(err) {
+				continue
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			machines[TopParentId(machineId)] = service
+		}
+	}
+	return machines, nil
+}
+
+// antiAffinityError is returned when assigning a unit to a machine would
+// co-locate it with a unit of a service with which it has an
+// anti-affinity.
+type antiAffinityError struct {
+	unit      string
+	machineId string
+	service   string
+}
+
+func (e *antiAffinityError) Error() string {
+	return fmt.Sprintf(
+		"unit %q cannot share machine %q with service %q (anti-affinity)",
+		e.unit, e.machineId, e.service,
+	)
+}
+
+func isAntiAffinityError(err error) bool {
+	_, ok := err.(*antiAffinityError)
+	return ok
+}
+
+// checkAntiAffinity returns an error if assigning the unit to the
+// machine with the given id would co-locate it with a unit of a service
+// with which it has an anti-affinity.
+func checkAntiAffinity(u *Unit, machineId string) error {
+	cons, err := u.Constraints()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	machines, err := antiAffinityMachines(u, cons)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	topId := TopParentId(machineId)
+	if service, ok := machines[topId]; ok {
+		return &antiAffinityError{u.Name(), topId, service}
+	}
+	return nil
+}
+
+// antiAffinityOps returns the operations needed to ensure, as part of
+// the transaction assigning the unit to the given machine, that no
+// machine under the same top-level machine hosts a unit of a service
+// with which the unit has an anti-affinity. If one already does, an
+// *antiAffinityError is returned.
+func antiAffinityOps(u *Unit, m *Machine) ([]txn.Op, error) {
+	cons, err := u.Constraints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	excluded, err := antiAffinityServices(u, cons)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if excluded.IsEmpty() {
+		return nil, nil
+	}
+	topId := TopParentId(m.Id())
+	machines, err := serviceMachines(u.st, excluded)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if service, ok := machines[topId]; ok {
+		return nil, &antiAffinityError{u.Name(), topId, service}
+	}
+	services := excluded.SortedValues()
+	for i, service := range services {
+		services[i] = regexp.QuoteMeta(service)
+	}
+	hostsExcluded := bson.RegEx{Pattern: "^(" + strings.Join(services, "|") + ")/"}
+
+	// Assert against every machine sharing the top-level machine, and
+	// that no containers are added to the top-level machine meanwhile.
+	machinesCollection, closer := u.st.getCollection(machinesC)
+	defer closer()
+	var mdocs []machineDoc
+	sel := bson.D{{"$or", []bson.D{
+		{{"machineid", topId}},
+		{{"machineid", bson.RegEx{Pattern: "^" + regexp.QuoteMeta(topId) + "/"}}},
+	}}}
+	if err := machinesCollection.Find(sel).Select(bson.D{{"_id", 1}}).All(&mdocs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	containerRefsCollection, closer := u.st.getCollection(containerRefsC)
+	defer closer()
+	var refs machineContainers
+	if err := containerRefsCollection.FindId(topId).One(&refs); err != nil {
+		return nil, errors.Annotatef(err, "reading containers of machine %q", topId)
+	}
+	childrenAssert := bson.D{{"children", bson.D{{"$size", len(refs.Children)}}}}
+	if len(refs.Children) == 0 {
+		childrenAssert = bson.D{hasNoContainersTerm}
+	}
+	ops := []txn.Op{{
+		C:      containerRefsC,
+		Id:     refs.DocID,
+		Assert: childrenAssert,
+	}}
+	for _, mdoc := range mdocs {
+		ops = append(ops, txn.Op{
+			C:      machinesC,
+			Id:     mdoc.DocID,
+			Assert: bson.D{{"principals", bson.D{{"$not", hostsExcluded}}}},
+		})
+	}
+	return ops, nil
+}
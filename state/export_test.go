@@ -429,3 +429,15 @@ func SpaceDoc(s *Space) spaceDoc {
 }
 
 var ActionNotificationIdToActionId = actionNotificationIdToActionId
+
+var MaxImportedLogArchiveSize = &maxImportedLogArchiveSize
+
+var LogArchivePath = logArchivePath
+
+func AdvanceMachineDrains(st *State, replacementTimeout time.Duration, now time.Time) error {
+	return st.advanceMachineDrains(replacementTimeout, now)
+}
+
+func RunDueScheduledActions(st *State, now time.Time) error {
+	return st.runDueScheduledActions(now)
+}
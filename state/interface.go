@@ -4,6 +4,8 @@
 package state
 
 import (
+	"time"
+
 	"github.com/juju/names"
 
 	"github.com/juju/juju/environs/config"
@@ -138,8 +140,13 @@ type ActionReceiver interface {
 	// ActionReceiver.
 	AddAction(name string, payload map[string]interface{}) (*Action, error)
 
+	// AddActionWithTimeout queues an action as AddAction does, which
+	// is stopped and marked failed if it runs for longer than timeout.
+	AddActionWithTimeout(name string, payload map[string]interface{}, timeout time.Duration) (*Action, error)
+
 	// CancelAction removes a pending Action from the queue for this
-	// ActionReceiver and marks it as cancelled.
+	// ActionReceiver and marks it as cancelled, or requests that a
+	// running Action be stopped.
 	CancelAction(action *Action) (*Action, error)
 
 	// WatchActionNotifications returns a StringsWatcher that will notify
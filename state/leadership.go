@@ -23,9 +23,14 @@ func leadershipSettingsKey(serviceId string) string {
 }
 
 // LeadershipClaimer returns a leadership.Claimer for units and services in the
-// state's environment.
+// state's environment. Successful claims that change a service's leader are
+// recorded in status history.
 func (st *State) LeadershipClaimer() leadership.Claimer {
-	return st.leadershipManager
+	return leadershipClaimer{
+		Claimer: st.leadershipManager,
+		checker: st.leadershipManager,
+		st:      st,
+	}
 }
 
 // LeadershipChecker returns a leadership.Checker for units and services in the
@@ -34,6 +39,24 @@ func (st *State) LeadershipChecker() leadership.Checker {
 	return st.leadershipManager
 }
 
+// LeadershipInspector returns a leadership.Inspector for services in the
+// state's environment.
+func (st *State) LeadershipInspector() leadership.Inspector {
+	return st.leadershipManager
+}
+
+// LeadershipRevoker returns a leadership.Revoker for services in the state's
+// environment.
+func (st *State) LeadershipRevoker() leadership.Revoker {
+	return st.leadershipManager
+}
+
+// LeadershipNamespace returns the name of the lease namespace in which
+// service leadership is recorded.
+func (st *State) LeadershipNamespace() string {
+	return serviceLeadershipNamespace
+}
+
 // HackLeadership stops the state's internal leadership manager to prevent it
 // from interfering with apiserver shutdown.
 func (st *State) HackLeadership() {
@@ -0,0 +1,60 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leadership
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/leadership"
+)
+
+// inspect is used to deliver leadership-inspection requests to a manager's
+// loop goroutine on behalf of Leases.
+type inspect struct {
+	response chan inspectResult
+	abort    <-chan struct{}
+}
+
+// inspectResult holds the outcome of an inspect request.
+type inspectResult struct {
+	leases map[string]leadership.Lease
+	err    error
+}
+
+// validate returns an error if any fields are missing.
+func (i inspect) validate() error {
+	if i.response == nil {
+		return errors.New("missing response channel")
+	}
+	if i.abort == nil {
+		return errors.New("missing abort channel")
+	}
+	return nil
+}
+
+// invoke sends the inspect request on the supplied channel and waits for a
+// response.
+func (i inspect) invoke(ch chan<- inspect) (map[string]leadership.Lease, error) {
+	if err := i.validate(); err != nil {
+		return nil, errors.Annotatef(err, "cannot inspect leadership")
+	}
+	for {
+		select {
+		case <-i.abort:
+			return nil, errStopped
+		case ch <- i:
+			ch = nil
+		case result := <-i.response:
+			return result.leases, result.err
+		}
+	}
+}
+
+// respond causes the supplied result to be sent back to invoke.
+func (i inspect) respond(leases map[string]leadership.Lease, err error) {
+	select {
+	case <-i.abort:
+	case i.response <- inspectResult{leases, err}:
+	}
+}
@@ -16,6 +16,8 @@ import (
 type ManagerWorker interface {
 	leadership.Checker
 	leadership.Claimer
+	leadership.Inspector
+	leadership.Revoker
 	Kill()
 	Wait() error
 }
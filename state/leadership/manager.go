@@ -27,10 +27,12 @@ func NewManager(config ManagerConfig) (ManagerWorker, error) {
 		return nil, errors.Trace(err)
 	}
 	manager := &manager{
-		config: config,
-		claims: make(chan claim),
-		checks: make(chan check),
-		blocks: make(chan block),
+		config:      config,
+		claims:      make(chan claim),
+		checks:      make(chan check),
+		blocks:      make(chan block),
+		inspections: make(chan inspect),
+		revokes:     make(chan revoke),
 	}
 	go func() {
 		defer manager.tomb.Done()
@@ -56,6 +58,12 @@ type manager struct {
 
 	// blocks is used to deliver leaderlessness block requests to the loop.
 	blocks chan block
+
+	// inspections is used to deliver leadership inspection requests to the loop.
+	inspections chan inspect
+
+	// revokes is used to deliver leadership revocation requests to the loop.
+	revokes chan revoke
 }
 
 // Kill is part of the worker.Worker interface.
@@ -110,6 +118,10 @@ func (manager *manager) choose(blocks blocks) error {
 	case block := <-manager.blocks:
 		blocks.add(block)
 		return nil
+	case inspect := <-manager.inspections:
+		return manager.handleInspect(inspect)
+	case revoke := <-manager.revokes:
+		return manager.handleRevoke(revoke)
 	}
 }
 
@@ -126,7 +138,8 @@ func (manager *manager) ClaimLeadership(serviceName, unitName string, duration t
 
 // handleClaim processes and responds to the supplied claim. It will only return
 // unrecoverable errors; mere failure to claim just indicates a bad request, and
-// is communicated back to the claim's originator.
+// is communicated back to the claim's originator. Nobody may claim a revoked
+// lease until it has expired.
 func (manager *manager) handleClaim(claim claim) error {
 	client := manager.config.Client
 	request := lease.Request{claim.unitName, claim.duration}
@@ -138,6 +151,9 @@ func (manager *manager) handleClaim(claim claim) error {
 		default:
 			info, found := client.Leases()[claim.serviceName]
 			switch {
+			case found && info.Revoked:
+				claim.respond(false)
+				return nil
 			case !found:
 				err = client.ClaimLease(claim.serviceName, request)
 			case info.Holder == claim.unitName:
@@ -181,7 +197,7 @@ func (manager *manager) handleCheck(check check) error {
 		}
 		info, found = client.Leases()[check.serviceName]
 	}
-	if found && info.Holder == check.unitName {
+	if found && info.Holder == check.unitName && !info.Revoked {
 		check.succeed(info.AssertOp)
 	} else {
 		check.fail()
@@ -198,6 +214,74 @@ func (manager *manager) BlockUntilLeadershipReleased(serviceName string) error {
 	}.invoke(manager.blocks)
 }
 
+// Leases is part of the leadership.Inspector interface.
+func (manager *manager) Leases() (map[string]leadership.Lease, error) {
+	return inspect{
+		response: make(chan inspectResult),
+		abort:    manager.tomb.Dying(),
+	}.invoke(manager.inspections)
+}
+
+// handleInspect refreshes the client's view of lease state, and responds to
+// the supplied inspection with the result. Revoked leases are held by nobody,
+// and are not included. It will only return unrecoverable errors.
+func (manager *manager) handleInspect(inspect inspect) error {
+	client := manager.config.Client
+	if err := client.Refresh(); err != nil {
+		return errors.Trace(err)
+	}
+	leases := make(map[string]leadership.Lease)
+	for serviceName, info := range client.Leases() {
+		if info.Revoked {
+			continue
+		}
+		leases[serviceName] = leadership.Lease{
+			Holder: info.Holder,
+			Expiry: info.Expiry,
+		}
+	}
+	inspect.respond(leases, nil)
+	return nil
+}
+
+// RevokeLeadership is part of the leadership.Revoker interface.
+func (manager *manager) RevokeLeadership(serviceName string) error {
+	return revoke{
+		serviceName: serviceName,
+		response:    make(chan error),
+		abort:       manager.tomb.Dying(),
+	}.invoke(manager.revokes)
+}
+
+// handleRevoke processes and responds to the supplied revocation. The revoked
+// lease is kept, held by nobody, until its original expiry time, so that no
+// unit can become leader while the revoked unit might still believe itself to
+// be leader. It will only return unrecoverable errors; the absence of a leader
+// is communicated back to the revocation's originator.
+func (manager *manager) handleRevoke(revoke revoke) error {
+	client := manager.config.Client
+	err := lease.ErrInvalid
+	for err == lease.ErrInvalid {
+		select {
+		case <-manager.tomb.Dying():
+			return tomb.ErrDying
+		default:
+			info, found := client.Leases()[revoke.serviceName]
+			if !found || info.Revoked {
+				revoke.respond(errors.NotFoundf("leader of service %q", revoke.serviceName))
+				return nil
+			}
+			err = client.RevokeLease(revoke.serviceName)
+		}
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	logger.Infof("revoked leadership of service %q", revoke.serviceName)
+	revoke.respond(nil)
+	return nil
+}
+
 // nextExpiry returns a channel that will send a value at some point when we
 // expect at least one lease to be ready to expire. If no leases are known,
 // it will return nil.
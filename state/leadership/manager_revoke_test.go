@@ -0,0 +1,201 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leadership_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coreleadership "github.com/juju/juju/leadership"
+	"github.com/juju/juju/state/leadership"
+	"github.com/juju/juju/state/lease"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type RevokeLeadershipSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&RevokeLeadershipSuite{})
+
+func (s *RevokeLeadershipSuite) TestLeases(c *gc.C) {
+	fix := &Fixture{
+		leases: map[string]lease.Info{
+			"redis": lease.Info{
+				Holder: "redis/0",
+				Expiry: offset(time.Minute),
+			},
+		},
+		expectCalls: []call{{
+			method: "Refresh",
+			callback: func(leases map[string]lease.Info) {
+				leases["postgresql"] = lease.Info{
+					Holder: "postgresql/1",
+					Expiry: offset(time.Second),
+				}
+			},
+		}},
+	}
+	fix.RunTest(c, func(manager leadership.ManagerWorker, _ *coretesting.Clock) {
+		leases, err := manager.Leases()
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(leases, jc.DeepEquals, map[string]coreleadership.Lease{
+			"redis":      {Holder: "redis/0", Expiry: offset(time.Minute)},
+			"postgresql": {Holder: "postgresql/1", Expiry: offset(time.Second)},
+		})
+	})
+}
+
+func (s *RevokeLeadershipSuite) TestLeases_RefreshError(c *gc.C) {
+	fix := &Fixture{
+		expectCalls: []call{{
+			method: "Refresh",
+			err:    errors.New("crunch squish"),
+		}},
+		expectDirty: true,
+	}
+	fix.RunTest(c, func(manager leadership.ManagerWorker, _ *coretesting.Clock) {
+		_, err := manager.Leases()
+		c.Check(err, gc.ErrorMatches, "leadership manager stopped")
+		err = manager.Wait()
+		c.Check(err, gc.ErrorMatches, "crunch squish")
+	})
+}
+
+func (s *RevokeLeadershipSuite) TestRevokeLeadership_Success(c *gc.C) {
+	fix := &Fixture{
+		leases: map[string]lease.Info{
+			"redis": lease.Info{
+				Holder: "redis/0",
+				Expiry: offset(time.Minute),
+			},
+		},
+		expectCalls: []call{{
+			method: "RevokeLease",
+			args:   []interface{}{"redis"},
+			callback: func(leases map[string]lease.Info) {
+				leases["redis"] = lease.Info{
+					Holder:  "redis/0",
+					Expiry:  offset(time.Minute),
+					Revoked: true,
+				}
+			},
+		}},
+	}
+	fix.RunTest(c, func(manager leadership.ManagerWorker, _ *coretesting.Clock) {
+		err := manager.RevokeLeadership("redis")
+		c.Check(err, jc.ErrorIsNil)
+
+		// Neither the revoked unit nor any other can claim
+		// leadership before the revoked lease expires.
+		err = manager.ClaimLeadership("redis", "redis/0", time.Minute)
+		c.Check(err, gc.Equals, coreleadership.ErrClaimDenied)
+		err = manager.ClaimLeadership("redis", "redis/1", time.Minute)
+		c.Check(err, gc.Equals, coreleadership.ErrClaimDenied)
+
+		// The service has no leader in the meantime.
+		leases, err := manager.Leases()
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(leases, gc.HasLen, 0)
+	})
+}
+
+func (s *RevokeLeadershipSuite) TestRevokeLeadership_CheckFails(c *gc.C) {
+	fix := &Fixture{
+		leases: map[string]lease.Info{
+			"redis": lease.Info{
+				Holder:  "redis/0",
+				Expiry:  offset(time.Minute),
+				Revoked: true,
+			},
+		},
+	}
+	fix.RunTest(c, func(manager leadership.ManagerWorker, _ *coretesting.Clock) {
+		token := manager.LeadershipCheck("redis", "redis/0")
+		c.Check(token.Check(nil), gc.ErrorMatches, `"redis/0" is not leader of "redis"`)
+	})
+}
+
+func (s *RevokeLeadershipSuite) TestRevokeLeadership_ExpiresAsUsual(c *gc.C) {
+	fix := &Fixture{
+		leases: map[string]lease.Info{
+			"redis": lease.Info{
+				Holder:  "redis/0",
+				Expiry:  offset(time.Minute),
+				Revoked: true,
+			},
+		},
+		expectCalls: []call{{
+			method: "ExpireLease",
+			args:   []interface{}{"redis"},
+			callback: func(leases map[string]lease.Info) {
+				delete(leases, "redis")
+			},
+		}},
+	}
+	fix.RunTest(c, func(_ leadership.ManagerWorker, clock *coretesting.Clock) {
+		clock.Advance(time.Minute)
+	})
+}
+
+func (s *RevokeLeadershipSuite) TestRevokeLeadership_AlreadyRevoked(c *gc.C) {
+	fix := &Fixture{
+		leases: map[string]lease.Info{
+			"redis": lease.Info{
+				Holder:  "redis/0",
+				Expiry:  offset(time.Minute),
+				Revoked: true,
+			},
+		},
+	}
+	fix.RunTest(c, func(manager leadership.ManagerWorker, _ *coretesting.Clock) {
+		err := manager.RevokeLeadership("redis")
+		c.Check(err, gc.ErrorMatches, `leader of service "redis" not found`)
+		c.Check(err, jc.Satisfies, errors.IsNotFound)
+	})
+}
+
+func (s *RevokeLeadershipSuite) TestRevokeLeadership_NotFound(c *gc.C) {
+	fix := &Fixture{}
+	fix.RunTest(c, func(manager leadership.ManagerWorker, _ *coretesting.Clock) {
+		err := manager.RevokeLeadership("redis")
+		c.Check(err, gc.ErrorMatches, `leader of service "redis" not found`)
+		c.Check(err, jc.Satisfies, errors.IsNotFound)
+	})
+}
+
+func (s *RevokeLeadershipSuite) TestRevokeLeadership_Invalid(c *gc.C) {
+	fix := &Fixture{}
+	fix.RunTest(c, func(manager leadership.ManagerWorker, _ *coretesting.Clock) {
+		err := manager.RevokeLeadership("$redis")
+		c.Check(err, gc.ErrorMatches, `cannot revoke leadership: invalid service name "\$redis"`)
+	})
+}
+
+func (s *RevokeLeadershipSuite) TestRevokeLeadership_Error(c *gc.C) {
+	fix := &Fixture{
+		leases: map[string]lease.Info{
+			"redis": lease.Info{
+				Holder: "redis/0",
+				Expiry: offset(time.Minute),
+			},
+		},
+		expectCalls: []call{{
+			method: "RevokeLease",
+			args:   []interface{}{"redis"},
+			err:    errors.New("snarfblat hobalob"),
+		}},
+		expectDirty: true,
+	}
+	fix.RunTest(c, func(manager leadership.ManagerWorker, _ *coretesting.Clock) {
+		err := manager.RevokeLeadership("redis")
+		c.Check(err, gc.ErrorMatches, "leadership manager stopped")
+		err = manager.Wait()
+		c.Check(err, gc.ErrorMatches, "snarfblat hobalob")
+	})
+}
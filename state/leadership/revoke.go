@@ -0,0 +1,57 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leadership
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+)
+
+// revoke is used to deliver leadership-revocation requests to a manager's
+// loop goroutine on behalf of RevokeLeadership.
+type revoke struct {
+	serviceName string
+	response    chan error
+	abort       <-chan struct{}
+}
+
+// validate returns an error if any fields are invalid or missing.
+func (r revoke) validate() error {
+	if !names.IsValidService(r.serviceName) {
+		return errors.Errorf("invalid service name %q", r.serviceName)
+	}
+	if r.response == nil {
+		return errors.New("missing response channel")
+	}
+	if r.abort == nil {
+		return errors.New("missing abort channel")
+	}
+	return nil
+}
+
+// invoke sends the revoke request on the supplied channel and waits for a
+// response.
+func (r revoke) invoke(ch chan<- revoke) error {
+	if err := r.validate(); err != nil {
+		return errors.Annotatef(err, "cannot revoke leadership")
+	}
+	for {
+		select {
+		case <-r.abort:
+			return errStopped
+		case ch <- r:
+			ch = nil
+		case err := <-r.response:
+			return err
+		}
+	}
+}
+
+// respond causes the supplied error to be sent back to invoke.
+func (r revoke) respond(err error) {
+	select {
+	case <-r.abort:
+	case r.response <- err:
+	}
+}
@@ -107,6 +107,11 @@ func (client *Client) ExpireLease(name string) error {
 	return client.call("ExpireLease", []interface{}{name})
 }
 
+// RevokeLease is part of the lease.Client interface.
+func (client *Client) RevokeLease(name string) error {
+	return client.call("RevokeLease", []interface{}{name})
+}
+
 // Refresh is part of the lease.Client interface.
 func (client *Client) Refresh() error {
 	return client.call("Refresh", nil)
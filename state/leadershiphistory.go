@@ -0,0 +1,185 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/leadership"
+)
+
+// StatusLeaderElected is the status recorded in status history when a
+// unit takes over leadership of a service.
+const StatusLeaderElected Status = "elected"
+
+// LeadershipChange records a unit taking over leadership of a service.
+type LeadershipChange struct {
+	// ServiceName is the name of the service whose leadership changed.
+	ServiceName string
+
+	// Previous is the name of the unit that was last recorded as
+	// leader of the service. It is empty if no leader was recorded.
+	Previous string
+
+	// Leader is the name of the unit that took over leadership.
+	Leader string
+
+	// Time is the time at which the change was recorded.
+	Time time.Time
+}
+
+// leadershipGlobalKey returns the global key under which leadership
+// changes of the named service are recorded in status history.
+func leadershipGlobalKey(serviceName string) string {
+	return serviceGlobalKey(serviceName) + "#leader"
+}
+
+// leadershipHistoryIdRE matches the local ids of leadership history
+// documents, capturing the service name.
+var leadershipHistoryIdRE = regexp.MustCompile(`^s#(` + names.ServiceSnippet + `)#leader#[0-9]+$`)
+
+// leadershipHistoryDoc is a status history document recording a
+// leadership change. It has the same fields as historicalStatusDoc,
+// plus a well-known id so that leadership changes can be watched for.
+type leadershipHistoryDoc struct {
+	DocId      string                 `bson:"_id"`
+	EnvUUID    string                 `bson:"env-uuid"`
+	GlobalKey  string                 `bson:"globalkey"`
+	Status     Status                 `bson:"status"`
+	StatusInfo string                 `bson:"statusinfo"`
+	StatusData map[string]interface{} `bson:"statusdata"`
+	Updated    int64                  `bson:"updated"`
+}
+
+// change returns the LeadershipChange recorded by the document.
+func (doc *leadershipHistoryDoc) change(serviceName string) LeadershipChange {
+	previous, _ := doc.StatusData["previous"].(string)
+	leader, _ := doc.StatusData["leader"].(string)
+	return LeadershipChange{
+		ServiceName: serviceName,
+		Previous:    previous,
+		Leader:      leader,
+		Time:        time.Unix(0, doc.Updated).UTC(),
+	}
+}
+
+// leadershipClaimer wraps the state's leadership manager so that
+// leadership changes are recorded in status history as they happen.
+type leadershipClaimer struct {
+	leadership.Claimer
+	checker leadership.Checker
+	st      *State
+}
+
+// ClaimLeadership is part of the leadership.Claimer interface.
+func (c leadershipClaimer) ClaimLeadership(serviceName, unitName string, duration time.Duration) error {
+	// Most claims are made by the current leader to extend its
+	// leadership, and do not change it. Those are answered from the
+	// manager's view of the leases, and never touch status history.
+	extending := c.checker.LeadershipCheck(serviceName, unitName).Check(nil) == nil
+	if err := c.Claimer.ClaimLeadership(serviceName, unitName, duration); err != nil {
+		return err
+	}
+	if !extending {
+		probablyRecordLeadershipChange(c.st, serviceName, unitName)
+	}
+	return nil
+}
+
+// probablyRecordLeadershipChange records the named unit's leadership of
+// the named service in status history, unless it was already the last
+// recorded leader. Like status history, failures are logged rather than
+// returned, so that they never cause a leadership claim to fail.
+func probablyRecordLeadershipChange(st *State, serviceName, unitName string) {
+	if err := recordLeadershipChange(st, serviceName, unitName); err != nil {
+		logger.Errorf("failed to record leadership of %q by %q: %v", serviceName, unitName, err)
+	}
+}
+
+func recordLeadershipChange(st *State, serviceName, unitName string) error {
+	history, closer := st.getCollection(statusesHistoryC)
+	defer closer()
+
+	globalKey := leadershipGlobalKey(serviceName)
+	var last leadershipHistoryDoc
+	err := history.Find(bson.D{{"globalkey", globalKey}}).Sort("-updated").One(&last)
+	if err != nil && err != mgo.ErrNotFound {
+		return errors.Trace(err)
+	}
+	previous, _ := last.StatusData["leader"].(string)
+	if previous == unitName {
+		return nil
+	}
+
+	seq, err := st.sequence("leadershipchange")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	info := fmt.Sprintf("%s elected leader", unitName)
+	if previous != "" {
+		info = fmt.Sprintf("%s elected leader, replacing %s", unitName, previous)
+	}
+	doc := &leadershipHistoryDoc{
+		DocId:      st.docID(fmt.Sprintf("%s#%d", globalKey, seq)),
+		EnvUUID:    st.EnvironUUID(),
+		GlobalKey:  globalKey,
+		Status:     StatusLeaderElected,
+		StatusInfo: info,
+		StatusData: map[string]interface{}{
+			"previous": previous,
+			"leader":   unitName,
+		},
+		Updated: time.Now().UnixNano(),
+	}
+	// The change is written in a transaction, rather than inserted
+	// directly like other status history, so that it can be watched.
+	logger.Infof("recording leadership change of %q: %s", serviceName, info)
+	ops := []txn.Op{{
+		C:      statusesHistoryC,
+		Id:     doc.DocId,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}}
+	return errors.Trace(st.runTransaction(ops))
+}
+
+// LeadershipHistory returns at most size of the most recent leadership
+// changes of the named service, oldest first.
+func (st *State) LeadershipHistory(serviceName string, size int) ([]LeadershipChange, error) {
+	if !names.IsValidService(serviceName) {
+		return nil, errors.NotValidf("service name %q", serviceName)
+	}
+	if size < 1 {
+		return nil, errors.NotValidf("history size %d", size)
+	}
+	history, closer := st.getCollection(statusesHistoryC)
+	defer closer()
+
+	var docs []leadershipHistoryDoc
+	query := history.Find(bson.D{{"globalkey", leadershipGlobalKey(serviceName)}})
+	if err := query.Sort("-updated").Limit(size).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get leadership history for service %q", serviceName)
+	}
+	changes := make([]LeadershipChange, len(docs))
+	for i, doc := range docs {
+		changes[i] = doc.change(serviceName)
+	}
+	sort.Sort(leadershipChangesByTime(changes))
+	return changes, nil
+}
+
+type leadershipChangesByTime []LeadershipChange
+
+func (c leadershipChangesByTime) Len() int           { return len(c) }
+func (c leadershipChangesByTime) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c leadershipChangesByTime) Less(i, j int) bool { return c[i].Time.Before(c[j].Time) }
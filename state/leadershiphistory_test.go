@@ -0,0 +1,136 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type LeadershipHistorySuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&LeadershipHistorySuite{})
+
+func (s *LeadershipHistorySuite) claim(c *gc.C, serviceName, unitName string) {
+	err := s.State.LeadershipClaimer().ClaimLeadership(serviceName, unitName, time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *LeadershipHistorySuite) assertHistory(c *gc.C, serviceName string, size int, expect ...[2]string) {
+	history, err := s.State.LeadershipHistory(serviceName, size)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, len(expect))
+	for i, change := range history {
+		c.Check(change.ServiceName, gc.Equals, serviceName)
+		c.Check(change.Previous, gc.Equals, expect[i][0])
+		c.Check(change.Leader, gc.Equals, expect[i][1])
+		c.Check(change.Time.IsZero(), jc.IsFalse)
+		if i > 0 {
+			c.Check(change.Time.Before(history[i-1].Time), jc.IsFalse)
+		}
+	}
+}
+
+func (s *LeadershipHistorySuite) TestNoHistory(c *gc.C) {
+	s.assertHistory(c, "blah", 10)
+}
+
+func (s *LeadershipHistorySuite) TestClaimsRecorded(c *gc.C) {
+	s.claim(c, "blah", "blah/0")
+	s.assertHistory(c, "blah", 10, [2]string{"", "blah/0"})
+
+	// Extending leadership does not record a change.
+	s.claim(c, "blah", "blah/0")
+	s.assertHistory(c, "blah", 10, [2]string{"", "blah/0"})
+
+	err := s.State.LeadershipRevoker().RevokeLeadership("blah")
+	c.Assert(err, jc.ErrorIsNil)
+	s.claim(c, "blah", "blah/1")
+	s.assertHistory(c, "blah", 10,
+		[2]string{"", "blah/0"},
+		[2]string{"blah/0", "blah/1"},
+	)
+
+	// Only the most recent changes are returned.
+	s.assertHistory(c, "blah", 1, [2]string{"blah/0", "blah/1"})
+
+	// Other services are unaffected.
+	s.assertHistory(c, "other", 10)
+}
+
+func (s *LeadershipHistorySuite) TestHistoryPerService(c *gc.C) {
+	s.claim(c, "blah", "blah/0")
+	s.claim(c, "other", "other/3")
+	s.assertHistory(c, "blah", 10, [2]string{"", "blah/0"})
+	s.assertHistory(c, "other", 10, [2]string{"", "other/3"})
+}
+
+func (s *LeadershipHistorySuite) TestHistoryRecordedAsStatus(c *gc.C) {
+	s.claim(c, "blah", "blah/0")
+
+	history, closer := state.GetCollection(s.State, state.StatusesHistoryC)
+	defer closer()
+	var doc struct {
+		Status     state.Status           `bson:"status"`
+		StatusInfo string                 `bson:"statusinfo"`
+		StatusData map[string]interface{} `bson:"statusdata"`
+	}
+	err := history.Find(nil).One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(doc.Status, gc.Equals, state.StatusLeaderElected)
+	c.Assert(doc.StatusInfo, gc.Equals, "blah/0 elected leader")
+	c.Assert(doc.StatusData, jc.DeepEquals, map[string]interface{}{
+		"previous": "",
+		"leader":   "blah/0",
+	})
+}
+
+func (s *LeadershipHistorySuite) TestHistoryInvalidArgs(c *gc.C) {
+	_, err := s.State.LeadershipHistory("blah/0", 10)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	_, err = s.State.LeadershipHistory("blah", 0)
+	c.Assert(err, gc.ErrorMatches, "history size 0 not valid")
+}
+
+func (s *LeadershipHistorySuite) TestWatchLeadershipChanges(c *gc.C) {
+	w := s.State.WatchLeadershipChanges()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewStringsWatcherC(c, s.State, w)
+
+	// The initial event is empty, even if changes have been recorded.
+	wc.AssertChange()
+	wc.AssertNoChange()
+
+	s.claim(c, "blah", "blah/0")
+	wc.AssertChange("blah")
+	wc.AssertNoChange()
+
+	// Extending leadership is not a change.
+	s.claim(c, "blah", "blah/0")
+	wc.AssertNoChange()
+
+	err := s.State.LeadershipRevoker().RevokeLeadership("blah")
+	c.Assert(err, jc.ErrorIsNil)
+	s.claim(c, "blah", "blah/1")
+	s.claim(c, "other", "other/0")
+	wc.AssertChange("blah", "other")
+	wc.AssertNoChange()
+
+	// Pruning history is not a change.
+	err = state.PruneStatusHistory(s.State, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+	s.assertHistory(c, "blah", 10, [2]string{"blah/0", "blah/1"})
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
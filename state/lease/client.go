@@ -69,6 +69,7 @@ func (client *client) Leases() map[string]Info {
 		leases[name] = Info{
 			Holder:   entry.holder,
 			Expiry:   skew.Latest(entry.expiry),
+			Revoked:  entry.revoked,
 			AssertOp: client.assertOp(name, entry.holder),
 		}
 	}
@@ -174,6 +175,46 @@ func (client *client) ExpireLease(name string) error {
 	return nil
 }
 
+// RevokeLease is part of the Client interface.
+func (client *client) RevokeLease(name string) error {
+	if err := validateString(name); err != nil {
+		return errors.Annotatef(err, "invalid name")
+	}
+
+	// Close over cacheEntry to record in case of success.
+	var cacheEntry entry
+	err := client.config.Mongo.RunTransaction(func(attempt int) ([]txn.Op, error) {
+		client.logger.Tracef("revoking lease %q (attempt %d)", name, attempt)
+
+		// On the first attempt, assume cache is good.
+		if attempt > 0 {
+			if err := client.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+
+		// No special error handling here.
+		ops, nextEntry, err := client.revokeLeaseOps(name)
+		cacheEntry = nextEntry
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return ops, nil
+	})
+
+	// Unwrap ErrInvalid if necessary.
+	if errors.Cause(err) == ErrInvalid {
+		return ErrInvalid
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// Update the cache for this lease only.
+	client.entries[name] = cacheEntry
+	return nil
+}
+
 // Refresh is part of the Client interface.
 func (client *client) Refresh() error {
 	client.logger.Tracef("refreshing")
@@ -349,12 +390,13 @@ func (client *client) claimLeaseOps(name string, request Request) ([]txn.Op, ent
 // return ErrInvalid.
 func (client *client) extendLeaseOps(name string, request Request) ([]txn.Op, entry, error) {
 
-	// Reject extensions when there's no lease, or the holder doesn't match.
+	// Reject extensions when there's no lease, the holder doesn't match, or
+	// the lease has been revoked.
 	lastEntry, found := client.entries[name]
 	if !found {
 		return nil, entry{}, ErrInvalid
 	}
-	if lastEntry.holder != request.Holder {
+	if lastEntry.holder != request.Holder || lastEntry.revoked {
 		return nil, entry{}, ErrInvalid
 	}
 
@@ -396,9 +438,10 @@ func (client *client) extendLeaseOps(name string, request Request) ([]txn.Op, en
 		C:  client.config.Collection,
 		Id: client.leaseDocId(name),
 		Assert: bson.M{
-			fieldLeaseHolder: lastEntry.holder,
-			fieldLeaseExpiry: toInt64(lastEntry.expiry),
-			fieldLeaseWriter: lastEntry.writer,
+			fieldLeaseHolder:  lastEntry.holder,
+			fieldLeaseExpiry:  toInt64(lastEntry.expiry),
+			fieldLeaseWriter:  lastEntry.writer,
+			fieldLeaseRevoked: bson.M{"$ne": true},
 		},
 		Update: bson.M{"$set": bson.M{
 			fieldLeaseExpiry: toInt64(expiry),
@@ -451,6 +494,44 @@ func (client *client) expireLeaseOps(name string) ([]txn.Op, error) {
 	return ops, nil
 }
 
+// revokeLeaseOps returns the []txn.Op necessary to mark the supplied lease as
+// revoked, and a cache entry corresponding to the values that will be written
+// if the transaction succeeds. A revoked lease is held by nobody, but cannot be
+// claimed until it expires as usual. If the revocation would conflict with
+// cached state, it will return ErrInvalid.
+func (client *client) revokeLeaseOps(name string) ([]txn.Op, entry, error) {
+
+	// We can't revoke a lease that doesn't exist, or that's already revoked;
+	// but, unlike expiry, we don't care when the lease will expire.
+	lastEntry, found := client.entries[name]
+	if !found || lastEntry.revoked {
+		return nil, entry{}, ErrInvalid
+	}
+
+	// The expiry time and writer are left untouched, so that the lease will
+	// be expired exactly when it would have been had it not been revoked.
+	nextEntry := lastEntry
+	nextEntry.revoked = true
+	revokeLeaseOp := txn.Op{
+		C:  client.config.Collection,
+		Id: client.leaseDocId(name),
+		Assert: bson.M{
+			fieldLeaseHolder:  lastEntry.holder,
+			fieldLeaseExpiry:  toInt64(lastEntry.expiry),
+			fieldLeaseWriter:  lastEntry.writer,
+			fieldLeaseRevoked: bson.M{"$ne": true},
+		},
+		Update: bson.M{"$set": bson.M{
+			fieldLeaseRevoked: true,
+		}},
+	}
+
+	// We always write a clock-update operation *before* writing lease info.
+	writeClockOp := client.writeClockOp(client.config.Clock.Now())
+	ops := []txn.Op{writeClockOp, revokeLeaseOp}
+	return ops, nextEntry, nil
+}
+
 // writeClockOp returns a txn.Op which writes the supplied time to the writer's
 // field in the skew doc, and aborts if a more recent time has been recorded for
 // that writer.
@@ -480,7 +561,8 @@ func (client *client) assertOp(name, holder string) txn.Op {
 		C:  client.config.Collection,
 		Id: client.leaseDocId(name),
 		Assert: bson.M{
-			fieldLeaseHolder: holder,
+			fieldLeaseHolder:  holder,
+			fieldLeaseRevoked: bson.M{"$ne": true},
 		},
 	}
 }
@@ -506,6 +588,10 @@ type entry struct {
 
 	// writer identifies the client that wrote the lease.
 	writer string
+
+	// revoked is true if the lease has been revoked; nobody holds it, and
+	// nobody can claim it until it expires.
+	revoked bool
 }
 
 // errNoExtension is used internally to avoid running unnecessary transactions.
@@ -73,3 +73,14 @@ func (s *ClientAssertSuite) TestAbortsWhenLeaseVacant(c *gc.C) {
 	err = s.fix.Runner.RunTransaction(ops)
 	c.Check(err, gc.Equals, txn.ErrAborted)
 }
+
+func (s *ClientAssertSuite) TestAbortsWhenLeaseRevoked(c *gc.C) {
+	info := s.fix.Client.Leases()["name"]
+
+	err := s.fix.Client.RevokeLease("name")
+	c.Assert(err, jc.ErrorIsNil)
+
+	ops := []txn.Op{info.AssertOp}
+	err = s.fix.Runner.RunTransaction(ops)
+	c.Check(err, gc.Equals, txn.ErrAborted)
+}
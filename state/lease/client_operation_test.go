@@ -164,3 +164,39 @@ func (s *ClientOperationSuite) TestCannotExpireUnheldLease(c *gc.C) {
 	err := fix.Client.ExpireLease("name")
 	c.Assert(err, gc.Equals, lease.ErrInvalid)
 }
+
+func (s *ClientOperationSuite) TestRevokeLeaseBeforeExpiry(c *gc.C) {
+	fix := s.EasyFixture(c)
+	leaseDuration := time.Minute
+	err := fix.Client.ClaimLease("name", lease.Request{"holder", leaseDuration})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// It can be revoked at any time...
+	err = fix.Client.RevokeLease("name")
+	c.Assert(err, jc.ErrorIsNil)
+	info := fix.Client.Leases()["name"]
+	c.Check(info.Revoked, jc.IsTrue)
+	c.Check("name", fix.Expiry(), fix.Zero.Add(leaseDuration))
+
+	// ...after which nobody can claim or extend it...
+	err = fix.Client.ClaimLease("name", lease.Request{"other-holder", time.Minute})
+	c.Assert(err, gc.Equals, lease.ErrInvalid)
+	err = fix.Client.ExtendLease("name", lease.Request{"holder", time.Minute})
+	c.Assert(err, gc.Equals, lease.ErrInvalid)
+	err = fix.Client.RevokeLease("name")
+	c.Assert(err, gc.Equals, lease.ErrInvalid)
+
+	// ...until it has been expired as usual.
+	fix.Clock.Advance(leaseDuration + time.Nanosecond)
+	err = fix.Client.ExpireLease("name")
+	c.Assert(err, jc.ErrorIsNil)
+	err = fix.Client.ClaimLease("name", lease.Request{"other-holder", time.Minute})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check("name", fix.Holder(), "other-holder")
+}
+
+func (s *ClientOperationSuite) TestCannotRevokeUnheldLease(c *gc.C) {
+	fix := s.EasyFixture(c)
+	err := fix.Client.RevokeLease("name")
+	c.Assert(err, gc.Equals, lease.ErrInvalid)
+}
@@ -110,9 +110,27 @@ func (s *ClientPersistenceSuite) TestExpireLease(c *gc.C) {
 	err = fix1.Client.ExpireLease("name")
 	c.Assert(err, jc.ErrorIsNil)
 
-	// Same client id, same clock, new instance: sees no lease.
+	// Same client id, same clock, new instance: sees the revoked lease,
+	// and cannot claim it.
 	fix2 := s.EasyFixture(c)
-	c.Check("name", fix2.Holder(), "")
+	c.Check(fix2.Client.Leases()["name"].Revoked, jc.IsTrue)
+	err = fix2.Client.ClaimLease("name", lease.Request{"other-holder", time.Minute})
+	c.Check(err, gc.Equals, lease.ErrInvalid)
+}
+
+func (s *ClientPersistenceSuite) TestRevokeLease(c *gc.C) {
+	fix1 := s.EasyFixture(c)
+	err := fix1.Client.ClaimLease("name", lease.Request{"holder", time.Minute})
+	c.Assert(err, jc.ErrorIsNil)
+	err = fix1.Client.RevokeLease("name")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Same client id, same clock, new instance: sees the revoked lease,
+	// and cannot claim it.
+	fix2 := s.EasyFixture(c)
+	c.Check(fix2.Client.Leases()["name"].Revoked, jc.IsTrue)
+	err = fix2.Client.ClaimLease("name", lease.Request{"other-holder", time.Minute})
+	c.Check(err, gc.Equals, lease.ErrInvalid)
 }
 
 func (s *ClientPersistenceSuite) TestNamespaceIsolation(c *gc.C) {
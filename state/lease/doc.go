@@ -37,6 +37,10 @@ So long as the above holds true, the following statements will too:
   * ExpireLease will only succeed when the most recent writer of the lease is
     known to believe the time is after the expiry time it wrote.
 
+  * RevokeLease ends the holder's ownership immediately, but leaves the lease
+    in place, unclaimable, until it can be expired as usual. (A revoked holder
+    cannot extend the lease, and nobody else can claim it early.)
+
 
 Remarks on clock skew
 ---------------------
@@ -66,8 +70,9 @@ Schema design
 
 For each namespace, we store a single clock document; and one additional
 document per lease. The lease document holds the name, holder, expiry, and
-writer of the lease; the clock document contains the most recent time
-acknowledged by each client that has written to the namespace.
+writer of the lease, and whether it has been revoked; the clock document
+contains the most recent time acknowledged by each client that has written to
+the namespace.
 
 Every transaction that the lease package makes is gated on a write to the
 clock document (which *must* precede any lease operations) which acks a
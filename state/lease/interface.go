@@ -31,6 +31,14 @@ type Client interface {
 	// have passed. If it returns ErrInvalid, check Leases() for updated state.
 	ExpireLease(lease string) error
 
+	// RevokeLease records that the supplied lease's holder no longer holds
+	// it, regardless of its expiry time. It's intended for forcibly removing
+	// a holder that is no longer able to do its job. The revoked lease cannot
+	// be claimed or extended by anyone, and must be expired as usual once its
+	// expiry time has passed. If it returns ErrInvalid, check Leases() for
+	// updated state.
+	RevokeLease(lease string) error
+
 	// Leases returns a recent snapshot of lease state. Expiry times are
 	// expressed according to the Clock the client was configured with.
 	Leases() map[string]Info
@@ -51,6 +59,10 @@ type Info struct {
 	// be valid. Attempting to expire the lease before this time will fail.
 	Expiry time.Time
 
+	// Revoked is true if the lease has been revoked. Holder no longer holds a
+	// revoked lease, and nobody can claim it before it expires.
+	Revoked bool
+
 	// AssertOp, if included in a mgo/txn transaction, will gate the transaction
 	// on the lease remaining held by Holder. If we didn't need this, we could
 	// easily implement Clients backed by other substrates.
@@ -25,10 +25,11 @@ const (
 	typeClock = "clock"
 
 	// fieldLease* identify the fields in a leaseDoc.
-	fieldLeaseName   = "name"
-	fieldLeaseHolder = "holder"
-	fieldLeaseExpiry = "expiry"
-	fieldLeaseWriter = "writer"
+	fieldLeaseName    = "name"
+	fieldLeaseHolder  = "holder"
+	fieldLeaseExpiry  = "expiry"
+	fieldLeaseWriter  = "writer"
+	fieldLeaseRevoked = "revoked"
 
 	// fieldClock* identify the fields in a clockDoc.
 	fieldClockWriters = "writers"
@@ -89,10 +90,11 @@ type leaseDoc struct {
 	// in this package, though.
 	EnvUUID string `bson:"env-uuid"`
 
-	// Holder, Expiry, and Writer map directly to entry.
-	Holder string `bson:"holder"`
-	Expiry int64  `bson:"expiry"`
-	Writer string `bson:"writer"`
+	// Holder, Expiry, Writer and Revoked map directly to entry.
+	Holder  string `bson:"holder"`
+	Expiry  int64  `bson:"expiry"`
+	Writer  string `bson:"writer"`
+	Revoked bool   `bson:"revoked,omitempty"`
 }
 
 // validate returns an error if any fields are invalid or inconsistent.
@@ -124,9 +126,10 @@ func (doc leaseDoc) entry() (string, entry, error) {
 		return "", entry{}, errors.Trace(err)
 	}
 	entry := entry{
-		holder: doc.Holder,
-		expiry: toTime(doc.Expiry),
-		writer: doc.Writer,
+		holder:  doc.Holder,
+		expiry:  toTime(doc.Expiry),
+		writer:  doc.Writer,
+		revoked: doc.Revoked,
 	}
 	return doc.Name, entry, nil
 }
@@ -142,6 +145,7 @@ func newLeaseDoc(namespace, name string, entry entry) (*leaseDoc, error) {
 		Holder:    entry.holder,
 		Expiry:    toInt64(entry.expiry),
 		Writer:    entry.writer,
+		Revoked:   entry.revoked,
 	}
 	if err := doc.validate(); err != nil {
 		return nil, errors.Trace(err)
@@ -0,0 +1,395 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	statestorage "github.com/juju/juju/state/storage"
+)
+
+const logArchivesC = "logarchives"
+
+// maxArchivedLogs is the maximum number of log records written to a
+// single archive.
+const maxArchivedLogs = 50000
+
+// importLogsBatchSize is the number of log records inserted at a time
+// when importing an archive.
+const importLogsBatchSize = 1000
+
+// maxImportedLogArchiveSize is the maximum uncompressed size of a log
+// archive which may be imported.
+var maxImportedLogArchiveSize int64 = 512 * humanize.MiByte
+
+// LogArchive describes an archive of log records which were removed
+// from the logs collection when it was pruned. The contents of each
+// archive, gzip-compressed and holding one JSON-encoded
+// ArchivedLogRecord per line, oldest first, are stored in the
+// environment's blob storage, which is shared by all state servers,
+// and the archive is recorded in the database.
+type LogArchive struct {
+	Id string
+
+	// Start and End are the times of the oldest and newest records
+	// in the archive.
+	Start time.Time
+	End   time.Time
+
+	// Count is the number of records in the archive.
+	Count int
+
+	// Size is the size of the compressed archive in bytes.
+	Size int64
+}
+
+// ArchivedLogRecord holds a single log record in a log archive.
+type ArchivedLogRecord struct {
+	Time     time.Time         `json:"time"`
+	Entity   string            `json:"entity"`
+	Module   string            `json:"module"`
+	Location string            `json:"location"`
+	Level    string            `json:"level"`
+	Message  string            `json:"message"`
+	Labels   map[string]string `json:"labels,omitempty"`
+}
+
+// logArchiveDoc records a log archive.
+type logArchiveDoc struct {
+	Id      bson.ObjectId `bson:"_id"`
+	EnvUUID string        `bson:"e"`
+	Start   time.Time     `bson:"start"`
+	End     time.Time     `bson:"end"`
+	Count   int           `bson:"count"`
+	Size    int64         `bson:"size"`
+
+	// StoragePath is the path of the archive's contents in the
+	// environment's blob storage.
+	StoragePath string `bson:"storagepath"`
+}
+
+func (doc *logArchiveDoc) archive() LogArchive {
+	return LogArchive{
+		Id:    doc.Id.Hex(),
+		Start: doc.Start,
+		End:   doc.End,
+		Count: doc.Count,
+		Size:  doc.Size,
+	}
+}
+
+// logArchiveStorage returns the blob storage holding the contents of
+// an environment's log archives.
+func logArchiveStorage(session *mgo.Session, envUUID string) statestorage.Storage {
+	return statestorage.NewStorage(envUUID, session)
+}
+
+// logArchivePath returns the storage path of the contents of the log
+// archive with the given id.
+func logArchivePath(id string) string {
+	return fmt.Sprintf("logarchives/%s.json.gz", id)
+}
+
+// archiveLogs removes the logs of an environment matching the given
+// selector, first writing them to one or more archives. Logs which
+// were imported from an archive are removed without being archived
+// again. Only logs which have been archived are removed, so any
+// matching logs added meanwhile are kept.
+func archiveLogs(coll *mgo.Collection, envUUID string, sel bson.M) (int, error) {
+	var removed int
+	var ids []bson.ObjectId
+	var batch []logDoc
+	flush := func() error {
+		if err := writeLogArchive(coll.Database, envUUID, batch); err != nil {
+			return errors.Trace(err)
+		}
+		removeInfo, err := coll.RemoveAll(bson.M{"_id": bson.M{"$in": ids}})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		removed += removeInfo.Removed
+		ids = ids[:0]
+		batch = batch[:0]
+		return nil
+	}
+
+	iter := coll.Find(sel).Sort("t").Iter()
+	for {
+		var doc logDoc
+		if !iter.Next(&doc) {
+			break
+		}
+		ids = append(ids, doc.Id)
+		if doc.ImportedAt.IsZero() {
+			batch = append(batch, doc)
+		}
+		if len(ids) == maxArchivedLogs {
+			if err := flush(); err != nil {
+				iter.Close()
+				return removed, errors.Trace(err)
+			}
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return removed, errors.Trace(err)
+	}
+	if len(ids) == 0 {
+		return removed, nil
+	}
+	return removed, errors.Trace(flush())
+}
+
+// writeLogArchive writes the given logs, which must be in time order,
+// to a single archive.
+func writeLogArchive(db *mgo.Database, envUUID string, logs []logDoc) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(zw)
+	for _, doc := range logs {
+		err := enc.Encode(&ArchivedLogRecord{
+			Time:     doc.Time,
+			Entity:   doc.Entity,
+			Module:   doc.Module,
+			Location: doc.Location,
+			Level:    doc.Level.String(),
+			Message:  doc.Message,
+			Labels:   doc.Labels,
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return errors.Trace(err)
+	}
+
+	id := bson.NewObjectId()
+	path := logArchivePath(id.Hex())
+	stor := logArchiveStorage(db.Session, envUUID)
+	size := int64(buf.Len())
+	if err := stor.Put(path, &buf, size); err != nil {
+		return errors.Annotate(err, "cannot store log archive")
+	}
+	err := db.C(logArchivesC).Insert(&logArchiveDoc{
+		Id:          id,
+		EnvUUID:     envUUID,
+		Start:       logs[0].Time,
+		End:         logs[len(logs)-1].Time,
+		Count:       len(logs),
+		Size:        size,
+		StoragePath: path,
+	})
+	if err != nil {
+		if err := stor.Remove(path); err != nil {
+			logger.Warningf("cannot remove unrecorded log archive %q: %v", path, err)
+		}
+		return errors.Annotate(err, "cannot record log archive")
+	}
+	return nil
+}
+
+// pruneLogArchives removes the archives of an environment whose newest
+// records are older than minTime, returning the number of archives
+// removed.
+func pruneLogArchives(db *mgo.Database, envUUID string, minTime time.Time) (int, error) {
+	var docs []logArchiveDoc
+	err := db.C(logArchivesC).Find(bson.M{
+		"e":   envUUID,
+		"end": bson.M{"$lt": minTime},
+	}).All(&docs)
+	if err != nil {
+		return 0, errors.Annotate(err, "cannot get log archives")
+	}
+	for i, doc := range docs {
+		if err := removeLogArchive(db, &doc); err != nil {
+			return i, errors.Trace(err)
+		}
+	}
+	return len(docs), nil
+}
+
+// removeLogArchive removes the contents of the given log archive from
+// storage, and then its record. If the contents are missing, the
+// record is still removed, as the archive cannot be read, but an error
+// satisfying errors.IsNotFound is returned.
+func removeLogArchive(db *mgo.Database, doc *logArchiveDoc) error {
+	id := doc.Id.Hex()
+	err := logArchiveStorage(db.Session, doc.EnvUUID).Remove(doc.StoragePath)
+	contentsMissing := errors.IsNotFound(err)
+	if err != nil && !contentsMissing {
+		return errors.Annotatef(err, "cannot remove log archive %q", id)
+	}
+	err = db.C(logArchivesC).RemoveId(doc.Id)
+	if err == mgo.ErrNotFound {
+		// The archive was removed meanwhile.
+		return errors.NotFoundf("log archive %q", id)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot remove log archive %q", id)
+	}
+	if contentsMissing {
+		return errors.NotFoundf("contents of log archive %q", id)
+	}
+	return nil
+}
+
+// getLogArchiveDoc returns the record of the environment's log archive
+// with the given id.
+func getLogArchiveDoc(db *mgo.Database, envUUID, id string) (*logArchiveDoc, error) {
+	if !bson.IsObjectIdHex(id) {
+		return nil, errors.NotFoundf("log archive %q", id)
+	}
+	var doc logArchiveDoc
+	err := db.C(logArchivesC).Find(bson.M{
+		"_id": bson.ObjectIdHex(id),
+		"e":   envUUID,
+	}).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("log archive %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get log archive %q", id)
+	}
+	return &doc, nil
+}
+
+// LogArchives returns the log archives of the environment which hold
+// records from the given time range, ordered by their start time. A
+// zero from or to time leaves that end of the range open.
+func LogArchives(st LoggingState, from, to time.Time) ([]LogArchive, error) {
+	session := st.MongoSession().Copy()
+	defer session.Close()
+
+	sel := bson.M{"e": st.EnvironUUID()}
+	if !from.IsZero() {
+		sel["end"] = bson.M{"$gte": from}
+	}
+	if !to.IsZero() {
+		sel["start"] = bson.M{"$lte": to}
+	}
+	var docs []logArchiveDoc
+	err := session.DB(logsDB).C(logArchivesC).Find(sel).Sort("start").All(&docs)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get log archives")
+	}
+	archives := make([]LogArchive, len(docs))
+	for i, doc := range docs {
+		archives[i] = doc.archive()
+	}
+	return archives, nil
+}
+
+// OpenLogArchive returns the log archive of the environment with the
+// given id, and a reader for its compressed contents. The reader must
+// be closed after use.
+func OpenLogArchive(st LoggingState, id string) (LogArchive, io.ReadCloser, error) {
+	session := st.MongoSession().Copy()
+	defer session.Close()
+
+	doc, err := getLogArchiveDoc(session.DB(logsDB), st.EnvironUUID(), id)
+	if err != nil {
+		return LogArchive{}, nil, errors.Trace(err)
+	}
+	r, _, err := logArchiveStorage(st.MongoSession(), doc.EnvUUID).Get(doc.StoragePath)
+	if errors.IsNotFound(err) {
+		return LogArchive{}, nil, errors.NotFoundf("contents of log archive %q", id)
+	} else if err != nil {
+		return LogArchive{}, nil, errors.Annotatef(err, "cannot read log archive %q", id)
+	}
+	return doc.archive(), r, nil
+}
+
+// RemoveLogArchive removes the log archive of the environment with the
+// given id, and its contents.
+func RemoveLogArchive(st LoggingState, id string) error {
+	session := st.MongoSession().Copy()
+	defer session.Close()
+
+	db := session.DB(logsDB)
+	doc, err := getLogArchiveDoc(db, st.EnvironUUID(), id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(removeLogArchive(db, doc))
+}
+
+// ImportLogArchive adds the records in the given log archive to the
+// logs of the environment, returning the number of records added.
+// Imported records are marked with the time of their import, which
+// exempts them from removal by age for a time (see LogPrunePolicy),
+// and they are not archived again.
+func ImportLogArchive(st LoggingState, r io.Reader) (int, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, errors.NewNotValid(err, "invalid log archive")
+	}
+	defer zr.Close()
+	// Guard against archives which expand to excessive sizes.
+	lr := &io.LimitedReader{R: zr, N: maxImportedLogArchiveSize + 1}
+
+	session, logsColl := initLogsSession(st)
+	defer session.Close()
+
+	var count int
+	var batch []interface{}
+	insert := func() error {
+		if err := logsColl.Insert(batch...); err != nil {
+			return errors.Annotate(err, "cannot import logs")
+		}
+		count += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+	importedAt := time.Now()
+	dec := json.NewDecoder(lr)
+	for {
+		var record ArchivedLogRecord
+		if err := dec.Decode(&record); err == io.EOF && lr.N > 0 {
+			break
+		} else if lr.N <= 0 {
+			return count, errors.NotValidf("log archive larger than %d bytes", maxImportedLogArchiveSize)
+		} else if err != nil {
+			return count, errors.NewNotValid(err, "invalid log archive")
+		}
+		level, ok := loggo.ParseLevel(record.Level)
+		if !ok {
+			return count, errors.NotValidf("log level %q", record.Level)
+		}
+		batch = append(batch, &logDoc{
+			Id:         bson.NewObjectId(),
+			Time:       record.Time,
+			EnvUUID:    st.EnvironUUID(),
+			Entity:     record.Entity,
+			Module:     record.Module,
+			Location:   record.Location,
+			Level:      level,
+			Message:    record.Message,
+			Labels:     record.Labels,
+			ImportedAt: importedAt,
+		})
+		if len(batch) == importLogsBatchSize {
+			if err := insert(); err != nil {
+				return count, errors.Trace(err)
+			}
+		}
+	}
+	if len(batch) > 0 {
+		if err := insert(); err != nil {
+			return count, errors.Trace(err)
+		}
+	}
+	return count, nil
+}
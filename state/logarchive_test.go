@@ -0,0 +1,380 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/state"
+	statestorage "github.com/juju/juju/state/storage"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type LogArchiveSuite struct {
+	ConnSuite
+	logsColl *mgo.Collection
+	now      time.Time
+}
+
+var _ = gc.Suite(&LogArchiveSuite{})
+
+func (s *LogArchiveSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.logsColl = s.State.MongoSession().DB("logs").C("logs")
+	s.now = time.Now().Truncate(time.Millisecond)
+}
+
+func (s *LogArchiveSuite) log(c *gc.C, age time.Duration, msg string) {
+	dbLogger := state.NewDbLogger(s.State, names.NewMachineTag("0"))
+	defer dbLogger.Close()
+	err := dbLogger.Log(s.now.Add(-age), "juju.worker", "worker.go:42", loggo.WARNING, msg)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *LogArchiveSuite) prune(c *gc.C, maxAge time.Duration) {
+	s.pruneWithPolicy(c, maxAge, state.LogPrunePolicy{Archive: true})
+}
+
+func (s *LogArchiveSuite) pruneWithPolicy(c *gc.C, maxAge time.Duration, policy state.LogPrunePolicy) {
+	policies := map[string]state.LogPrunePolicy{
+		s.State.EnvironUUID(): policy,
+	}
+	err := state.PruneLogs(s.State, s.now.Add(-maxAge), 100, policies)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *LogArchiveSuite) archives(c *gc.C) []state.LogArchive {
+	archives, err := state.LogArchives(s.State, time.Time{}, time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	return archives
+}
+
+// storage returns the blob storage holding the contents of the
+// environment's log archives.
+func (s *LogArchiveSuite) storage() statestorage.Storage {
+	return statestorage.NewStorage(s.State.EnvironUUID(), s.State.MongoSession())
+}
+
+func (s *LogArchiveSuite) assertArchiveStored(c *gc.C, id string, stored bool) {
+	r, _, err := s.storage().Get(state.LogArchivePath(id))
+	if !stored {
+		c.Assert(err, jc.Satisfies, errors.IsNotFound)
+		return
+	}
+	c.Assert(err, jc.ErrorIsNil)
+	r.Close()
+}
+
+func readArchive(c *gc.C, r io.ReadCloser) []state.ArchivedLogRecord {
+	defer r.Close()
+	zr, err := gzip.NewReader(r)
+	c.Assert(err, jc.ErrorIsNil)
+	dec := json.NewDecoder(zr)
+	var records []state.ArchivedLogRecord
+	for {
+		var record state.ArchivedLogRecord
+		err := dec.Decode(&record)
+		if err == io.EOF {
+			return records
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		records = append(records, record)
+	}
+}
+
+func (s *LogArchiveSuite) TestPruneLogsArchives(c *gc.C) {
+	s.log(c, 3*time.Hour, "oldest")
+	s.log(c, 2*time.Hour, "older")
+	s.log(c, time.Minute, "recent")
+	s.prune(c, time.Hour)
+
+	archives, err := state.LogArchives(s.State, time.Time{}, time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(archives, gc.HasLen, 1)
+	archive := archives[0]
+	c.Assert(archive.Count, gc.Equals, 2)
+	c.Assert(archive.Start.Equal(s.now.Add(-3*time.Hour)), jc.IsTrue)
+	c.Assert(archive.End.Equal(s.now.Add(-2*time.Hour)), jc.IsTrue)
+	c.Assert(archive.Size, jc.GreaterThan, int64(0))
+
+	s.assertArchiveStored(c, archive.Id, true)
+	opened, r, err := state.OpenLogArchive(s.State, archive.Id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(opened, jc.DeepEquals, archive)
+	records := readArchive(c, r)
+	c.Assert(records, gc.HasLen, 2)
+	c.Assert(records[0].Time.Equal(s.now.Add(-3*time.Hour)), jc.IsTrue)
+	records[0].Time = time.Time{}
+	c.Assert(records[0], jc.DeepEquals, state.ArchivedLogRecord{
+		Entity:   "machine-0",
+		Module:   "juju.worker",
+		Location: "worker.go:42",
+		Level:    "WARNING",
+		Message:  "oldest",
+	})
+	c.Assert(records[1].Message, gc.Equals, "older")
+
+	count, err := s.logsColl.Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 1)
+}
+
+func (s *LogArchiveSuite) TestPruneLogsWithoutArchive(c *gc.C) {
+	s.log(c, 2*time.Hour, "old")
+	s.pruneWithPolicy(c, time.Hour, state.LogPrunePolicy{})
+	c.Assert(s.archives(c), gc.HasLen, 0)
+
+	count, err := s.logsColl.Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 0)
+}
+
+func (s *LogArchiveSuite) TestPruneLogArchives(c *gc.C) {
+	s.log(c, 10*time.Hour, "first")
+	s.prune(c, 5*time.Hour)
+	s.log(c, 2*time.Hour, "second")
+	s.prune(c, time.Hour)
+	archives := s.archives(c)
+	c.Assert(archives, gc.HasLen, 2)
+
+	// Archives are removed once their newest record is older than
+	// MinArchiveTime, even when logs are no longer archived.
+	s.pruneWithPolicy(c, time.Hour, state.LogPrunePolicy{
+		MinArchiveTime: s.now.Add(-5 * time.Hour),
+	})
+	c.Assert(s.archives(c), jc.DeepEquals, archives[1:])
+	s.assertArchiveStored(c, archives[0].Id, false)
+	s.assertArchiveStored(c, archives[1].Id, true)
+	_, _, err := state.OpenLogArchive(s.State, archives[0].Id)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *LogArchiveSuite) TestPruneLogArchivesMissingContents(c *gc.C) {
+	s.log(c, 10*time.Hour, "first")
+	s.prune(c, 5*time.Hour)
+	archives := s.archives(c)
+	c.Assert(archives, gc.HasLen, 1)
+	err := s.storage().Remove(state.LogArchivePath(archives[0].Id))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Removing an archive whose contents were lost is not treated
+	// as success.
+	policies := map[string]state.LogPrunePolicy{
+		s.State.EnvironUUID(): {MinArchiveTime: s.now},
+	}
+	err = state.PruneLogs(s.State, s.now.Add(-time.Hour), 100, policies)
+	c.Assert(err, gc.ErrorMatches, `failed to prune log archives: contents of log archive "`+archives[0].Id+`" not found`)
+	c.Assert(s.archives(c), gc.HasLen, 0)
+}
+
+func (s *LogArchiveSuite) TestRemoveLogArchive(c *gc.C) {
+	s.log(c, 2*time.Hour, "old")
+	s.prune(c, time.Hour)
+	archives := s.archives(c)
+	c.Assert(archives, gc.HasLen, 1)
+
+	err := state.RemoveLogArchive(s.State, archives[0].Id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.archives(c), gc.HasLen, 0)
+	s.assertArchiveStored(c, archives[0].Id, false)
+
+	err = state.RemoveLogArchive(s.State, archives[0].Id)
+	c.Assert(err, gc.ErrorMatches, `log archive "`+archives[0].Id+`" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *LogArchiveSuite) TestRemoveLogArchiveMissingContents(c *gc.C) {
+	s.log(c, 2*time.Hour, "old")
+	s.prune(c, time.Hour)
+	archives := s.archives(c)
+	c.Assert(archives, gc.HasLen, 1)
+
+	// The archive is still recorded after its contents are lost.
+	err := s.storage().Remove(state.LogArchivePath(archives[0].Id))
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = state.OpenLogArchive(s.State, archives[0].Id)
+	c.Assert(err, gc.ErrorMatches, `contents of log archive "`+archives[0].Id+`" not found`)
+
+	// Its record is removed, but the lost contents are reported.
+	err = state.RemoveLogArchive(s.State, archives[0].Id)
+	c.Assert(err, gc.ErrorMatches, `contents of log archive "`+archives[0].Id+`" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(s.archives(c), gc.HasLen, 0)
+}
+
+func (s *LogArchiveSuite) TestLogArchivesTimeRange(c *gc.C) {
+	s.log(c, 10*time.Hour, "first")
+	s.prune(c, 5*time.Hour)
+	s.log(c, 2*time.Hour, "second")
+	s.prune(c, time.Hour)
+
+	archives, err := state.LogArchives(s.State, time.Time{}, time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(archives, gc.HasLen, 2)
+	first, second := archives[0], archives[1]
+	c.Assert(first.Start.Before(second.Start), jc.IsTrue)
+
+	archives, err = state.LogArchives(s.State, s.now.Add(-3*time.Hour), time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(archives, jc.DeepEquals, []state.LogArchive{second})
+
+	archives, err = state.LogArchives(s.State, time.Time{}, s.now.Add(-5*time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(archives, jc.DeepEquals, []state.LogArchive{first})
+
+	// Archives of other environments are not seen.
+	st := s.Factory.MakeEnvironment(c, nil)
+	defer st.Close()
+	archives, err = state.LogArchives(st, time.Time{}, time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(archives, gc.HasLen, 0)
+	_, _, err = state.OpenLogArchive(st, first.Id)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = state.RemoveLogArchive(st, first.Id)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *LogArchiveSuite) TestOpenLogArchiveNotFound(c *gc.C) {
+	_, _, err := state.OpenLogArchive(s.State, "bad")
+	c.Assert(err, gc.ErrorMatches, `log archive "bad" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	id := bson.NewObjectId().Hex()
+	_, _, err = state.OpenLogArchive(s.State, id)
+	c.Assert(err, gc.ErrorMatches, `log archive "`+id+`" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *LogArchiveSuite) TestImportLogArchive(c *gc.C) {
+	s.log(c, 3*time.Hour, "one")
+	s.log(c, 2*time.Hour, "two")
+	s.prune(c, time.Hour)
+	archives, err := state.LogArchives(s.State, time.Time{}, time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(archives, gc.HasLen, 1)
+
+	_, r, err := state.OpenLogArchive(s.State, archives[0].Id)
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	count, err := state.ImportLogArchive(s.State, r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 2)
+
+	var docs []bson.M
+	err = s.logsColl.Find(nil).Sort("t").All(&docs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(docs, gc.HasLen, 2)
+	c.Assert(docs[0]["e"], gc.Equals, s.State.EnvironUUID())
+	c.Assert(docs[0]["n"], gc.Equals, "machine-0")
+	c.Assert(docs[0]["v"], gc.Equals, int(loggo.WARNING))
+	c.Assert(docs[0]["x"], gc.Equals, "one")
+	importedAt, ok := docs[0]["i"].(time.Time)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(importedAt.IsZero(), jc.IsFalse)
+	c.Assert(docs[1]["x"], gc.Equals, "two")
+
+	// Imported logs are marked as such when they are read.
+	tailer := state.NewLogTailer(s.State, &state.LogTailerParams{NoTail: true})
+	defer tailer.Stop()
+	for i := 0; i < 2; i++ {
+		select {
+		case record := <-tailer.Logs():
+			c.Assert(record.Imported, jc.IsTrue)
+		case <-time.After(coretesting.LongWait):
+			c.Fatal("timed out waiting for log")
+		}
+	}
+
+	// Recently imported logs are not pruned by age.
+	s.pruneWithPolicy(c, time.Hour, state.LogPrunePolicy{
+		Archive:       true,
+		MinImportTime: s.now.Add(-time.Hour),
+	})
+	count, err = s.logsColl.Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 2)
+
+	// Once the exemption has passed, they are pruned but not
+	// archived again.
+	s.pruneWithPolicy(c, time.Hour, state.LogPrunePolicy{
+		Archive:       true,
+		MinImportTime: s.now.Add(time.Hour),
+	})
+	count, err = s.logsColl.Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 0)
+	archives, err = state.LogArchives(s.State, time.Time{}, time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(archives, gc.HasLen, 1)
+}
+
+func (s *LogArchiveSuite) TestLogArchiveLabels(c *gc.C) {
+	dbLogger := state.NewDbLogger(s.State, names.NewUnitTag("mysql/0"))
+	defer dbLogger.Close()
+	err := dbLogger.LogWithLabels(s.now.Add(-2*time.Hour), "unit.mysql/0.install", "install:1", loggo.INFO, "installing",
+		map[string]string{"hook": "install"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.prune(c, time.Hour)
+	archives, err := state.LogArchives(s.State, time.Time{}, time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(archives, gc.HasLen, 1)
+
+	_, r, err := state.OpenLogArchive(s.State, archives[0].Id)
+	c.Assert(err, jc.ErrorIsNil)
+	records := readArchive(c, r)
+	c.Assert(records, gc.HasLen, 1)
+	c.Assert(records[0].Labels, jc.DeepEquals, map[string]string{"hook": "install"})
+
+	_, r, err = state.OpenLogArchive(s.State, archives[0].Id)
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	_, err = state.ImportLogArchive(s.State, r)
+	c.Assert(err, jc.ErrorIsNil)
+	var doc bson.M
+	err = s.logsColl.Find(nil).One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(doc["f"], gc.DeepEquals, bson.M{"hook": "install"})
+}
+
+func (s *LogArchiveSuite) TestImportLogArchiveInvalid(c *gc.C) {
+	_, err := state.ImportLogArchive(s.State, strings.NewReader("not gzip"))
+	c.Assert(err, gc.ErrorMatches, "invalid log archive: .*")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err = zw.Write([]byte(`{"level": "LOUD", "message": "hello"}` + "\n"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zw.Close(), jc.ErrorIsNil)
+	_, err = state.ImportLogArchive(s.State, &buf)
+	c.Assert(err, gc.ErrorMatches, `log level "LOUD" not valid`)
+}
+
+func (s *LogArchiveSuite) TestImportLogArchiveTooLarge(c *gc.C) {
+	s.PatchValue(state.MaxImportedLogArchiveSize, int64(100))
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(zw)
+	for i := 0; i < 10; i++ {
+		err := enc.Encode(&state.ArchivedLogRecord{Level: "INFO", Message: "hello"})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(zw.Close(), jc.ErrorIsNil)
+	_, err := state.ImportLogArchive(s.State, &buf)
+	c.Assert(err, gc.ErrorMatches, "log archive larger than 100 bytes not valid")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
@@ -0,0 +1,261 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// LoggingOverride holds logging configuration that applies to a single
+// agent, on top of the environment's logging-config, until it expires.
+type LoggingOverride struct {
+	// Tag identifies the machine or unit whose agent is affected.
+	Tag names.Tag
+
+	// Config is a loggo configuration string, such as
+	// "juju.worker.uniter=TRACE".
+	Config string
+
+	// Expires is the time after which the override no longer applies.
+	Expires time.Time
+}
+
+// loggingOverrideDoc records the logging override of an agent. There is
+// at most one document per agent, which is removed with the agent.
+// Expired documents are ignored, and removed whenever an override is
+// set in the environment.
+type loggingOverrideDoc struct {
+	DocID   string    `bson:"_id"`
+	EnvUUID string    `bson:"env-uuid"`
+	Tag     string    `bson:"tag"`
+	Config  string    `bson:"config"`
+	Expires time.Time `bson:"expires"`
+}
+
+func (doc *loggingOverrideDoc) override() (LoggingOverride, error) {
+	tag, err := names.ParseTag(doc.Tag)
+	if err != nil {
+		return LoggingOverride{}, errors.Trace(err)
+	}
+	return LoggingOverride{
+		Tag:     tag,
+		Config:  doc.Config,
+		Expires: doc.Expires,
+	}, nil
+}
+
+// agentEntityKey returns the collection and document id of the machine
+// or unit with the given tag.
+func (st *State) agentEntityKey(tag names.Tag) (docKey, error) {
+	switch tag := tag.(type) {
+	case names.MachineTag:
+		if _, err := st.Machine(tag.Id()); err != nil {
+			return docKey{}, errors.Trace(err)
+		}
+		return docKey{machinesC, st.docID(tag.Id())}, nil
+	case names.UnitTag:
+		if _, err := st.Unit(tag.Id()); err != nil {
+			return docKey{}, errors.Trace(err)
+		}
+		return docKey{unitsC, st.docID(tag.Id())}, nil
+	}
+	return docKey{}, errors.NotValidf("agent tag %q", tag)
+}
+
+// SetLoggingOverride sets logging configuration for the agent of the
+// machine or unit with the given tag, replacing any existing override.
+// The configuration is applied on top of the environment's
+// logging-config until the given expiry time.
+func (st *State) SetLoggingOverride(tag names.Tag, config string, expires time.Time) error {
+	if config == "" {
+		return errors.Errorf("cannot set logging override for %q: empty logging config", tag)
+	}
+	if _, err := loggo.ParseConfigurationString(config); err != nil {
+		return errors.Annotatef(err, "cannot set logging override for %q", tag)
+	}
+	now := GetClock().Now()
+	if !expires.After(now) {
+		return errors.Errorf("cannot set logging override for %q: expiry time is in the past", tag)
+	}
+	doc := loggingOverrideDoc{
+		DocID:   st.docID(tag.String()),
+		EnvUUID: st.EnvironUUID(),
+		Tag:     tag.String(),
+		Config:  config,
+		Expires: expires,
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		entity, err := st.agentEntityKey(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:      entity.coll,
+			Id:     entity.docId,
+			Assert: txn.DocExists,
+		}}
+		pruneOps, err := st.pruneLoggingOverridesOps(now, doc.DocID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, pruneOps...)
+		_, err = st.loggingOverrideDoc(tag)
+		if errors.IsNotFound(err) {
+			return append(ops, txn.Op{
+				C:      loggingOverridesC,
+				Id:     doc.DocID,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			}), nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, txn.Op{
+			C:      loggingOverridesC,
+			Id:     doc.DocID,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{
+				{"config", doc.Config},
+				{"expires", doc.Expires},
+			}}},
+		}), nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot set logging override for %q", tag)
+	}
+	return nil
+}
+
+// pruneLoggingOverridesOps returns the operations which remove the
+// logging overrides in the environment that had expired by the given
+// time, other than the one with the given document id.
+func (st *State) pruneLoggingOverridesOps(now time.Time, exceptDocID string) ([]txn.Op, error) {
+	overrides, closer := st.getCollection(loggingOverridesC)
+	defer closer()
+
+	var docs []loggingOverrideDoc
+	sel := bson.D{
+		{"_id", bson.D{{"$ne", exceptDocID}}},
+		{"expires", bson.D{{"$lte", now}}},
+	}
+	if err := overrides.Find(sel).Select(bson.D{{"_id", 1}}).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get expired logging overrides")
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      loggingOverridesC,
+			Id:     doc.DocID,
+			Assert: bson.D{{"expires", bson.D{{"$lte", now}}}},
+			Remove: true,
+		}
+	}
+	return ops, nil
+}
+
+// removeLoggingOverrideOp returns the operation which removes the
+// logging override of the agent with the given tag, if it has one.
+func removeLoggingOverrideOp(st *State, tag names.Tag) txn.Op {
+	return txn.Op{
+		C:      loggingOverridesC,
+		Id:     st.docID(tag.String()),
+		Remove: true,
+	}
+}
+
+// RemoveLoggingOverride removes the logging override of the agent with
+// the given tag, returning to the environment's logging-config before
+// the override expires.
+func (st *State) RemoveLoggingOverride(tag names.Tag) error {
+	ops := []txn.Op{{
+		C:      loggingOverridesC,
+		Id:     st.docID(tag.String()),
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	if err := st.runTransaction(ops); err != nil {
+		return onAbort(err, errors.NotFoundf("logging override for %q", tag))
+	}
+	return nil
+}
+
+// LoggingOverride returns the logging override of the agent with the
+// given tag. It returns a NotFound error if there is no override, or
+// if it has expired.
+func (st *State) LoggingOverride(tag names.Tag) (LoggingOverride, error) {
+	doc, err := st.loggingOverrideDoc(tag)
+	if err != nil {
+		return LoggingOverride{}, errors.Trace(err)
+	}
+	if !doc.Expires.After(GetClock().Now()) {
+		return LoggingOverride{}, errors.NotFoundf("logging override for %q", tag)
+	}
+	return doc.override()
+}
+
+// LoggingOverrides returns the logging overrides in the environment
+// that have not yet expired, ordered by tag.
+func (st *State) LoggingOverrides() ([]LoggingOverride, error) {
+	overrides, closer := st.getCollection(loggingOverridesC)
+	defer closer()
+
+	var docs []loggingOverrideDoc
+	sel := bson.D{{"expires", bson.D{{"$gt", GetClock().Now()}}}}
+	if err := overrides.Find(sel).Sort("tag").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get logging overrides")
+	}
+	result := make([]LoggingOverride, len(docs))
+	for i, doc := range docs {
+		override, err := doc.override()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result[i] = override
+	}
+	return result, nil
+}
+
+func (st *State) loggingOverrideDoc(tag names.Tag) (*loggingOverrideDoc, error) {
+	overrides, closer := st.getCollection(loggingOverridesC)
+	defer closer()
+
+	var doc loggingOverrideDoc
+	err := overrides.FindId(tag.String()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("logging override for %q", tag)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get logging override for %q", tag)
+	}
+	return &doc, nil
+}
+
+// AgentLoggingConfig returns the logging configuration for the agent
+// with the given tag: the environment's logging-config, followed by
+// the agent's logging override if it has one that has not expired.
+func (st *State) AgentLoggingConfig(tag names.Tag) (string, error) {
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	loggingConfig := cfg.LoggingConfig()
+	override, err := st.LoggingOverride(tag)
+	if errors.IsNotFound(err) {
+		return loggingConfig, nil
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	if loggingConfig == "" {
+		return override.Config, nil
+	}
+	// Later entries take precedence, so the override's levels win
+	// over the environment's for any module named in both.
+	return loggingConfig + ";" + override.Config, nil
+}
@@ -0,0 +1,242 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type LoggingOverridesSuite struct {
+	ConnSuite
+	clock   *coretesting.Clock
+	machine *state.Machine
+	unit    *state.Unit
+}
+
+var _ = gc.Suite(&LoggingOverridesSuite{})
+
+func (s *LoggingOverridesSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.clock = coretesting.NewClock(time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC))
+	s.PatchValue(&state.GetClock, func() clock.Clock {
+		return s.clock
+	})
+	var err error
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.unit, err = wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *LoggingOverridesSuite) envLoggingConfig(c *gc.C) string {
+	cfg, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	return cfg.LoggingConfig()
+}
+
+func (s *LoggingOverridesSuite) TestSetLoggingOverride(c *gc.C) {
+	expires := s.clock.Now().Add(30 * time.Minute)
+	err := s.State.SetLoggingOverride(s.unit.Tag(), "juju.worker.uniter=TRACE", expires)
+	c.Assert(err, jc.ErrorIsNil)
+
+	override, err := s.State.LoggingOverride(s.unit.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(override.Tag, gc.Equals, s.unit.Tag())
+	c.Assert(override.Config, gc.Equals, "juju.worker.uniter=TRACE")
+	c.Assert(override.Expires.Equal(expires), jc.IsTrue)
+
+	loggingConfig, err := s.State.AgentLoggingConfig(s.unit.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(loggingConfig, gc.Equals, s.envLoggingConfig(c)+";juju.worker.uniter=TRACE")
+
+	// Other agents are not affected.
+	loggingConfig, err = s.State.AgentLoggingConfig(s.machine.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(loggingConfig, gc.Equals, s.envLoggingConfig(c))
+}
+
+func (s *LoggingOverridesSuite) TestSetLoggingOverrideReplaces(c *gc.C) {
+	expires := s.clock.Now().Add(time.Hour)
+	err := s.State.SetLoggingOverride(s.machine.Tag(), "juju=DEBUG", expires)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetLoggingOverride(s.machine.Tag(), "juju.provisioner=TRACE", expires.Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+
+	override, err := s.State.LoggingOverride(s.machine.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(override.Config, gc.Equals, "juju.provisioner=TRACE")
+	c.Assert(override.Expires.Equal(expires.Add(time.Hour)), jc.IsTrue)
+}
+
+func (s *LoggingOverridesSuite) TestSetLoggingOverrideErrors(c *gc.C) {
+	expires := s.clock.Now().Add(time.Hour)
+	err := s.State.SetLoggingOverride(s.machine.Tag(), "", expires)
+	c.Assert(err, gc.ErrorMatches, `cannot set logging override for "machine-0": empty logging config`)
+
+	err = s.State.SetLoggingOverride(s.machine.Tag(), "juju=LOUD", expires)
+	c.Assert(err, gc.ErrorMatches, `cannot set logging override for "machine-0": .*LOUD.*`)
+
+	err = s.State.SetLoggingOverride(s.machine.Tag(), "juju=DEBUG", s.clock.Now())
+	c.Assert(err, gc.ErrorMatches, `cannot set logging override for "machine-0": expiry time is in the past`)
+
+	err = s.State.SetLoggingOverride(names.NewMachineTag("42"), "juju=DEBUG", expires)
+	c.Assert(err, gc.ErrorMatches, `cannot set logging override for "machine-42": machine 42 not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.SetLoggingOverride(names.NewServiceTag("wordpress"), "juju=DEBUG", expires)
+	c.Assert(err, gc.ErrorMatches, `cannot set logging override for "service-wordpress": agent tag "service-wordpress" not valid`)
+}
+
+func (s *LoggingOverridesSuite) TestLoggingOverrideExpires(c *gc.C) {
+	err := s.State.SetLoggingOverride(s.unit.Tag(), "juju=TRACE", s.clock.Now().Add(time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	overrides, err := s.State.LoggingOverrides()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(overrides, gc.HasLen, 1)
+
+	s.clock.Advance(time.Minute)
+	_, err = s.State.LoggingOverride(s.unit.Tag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	overrides, err = s.State.LoggingOverrides()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(overrides, gc.HasLen, 0)
+	loggingConfig, err := s.State.AgentLoggingConfig(s.unit.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(loggingConfig, gc.Equals, s.envLoggingConfig(c))
+}
+
+func (s *LoggingOverridesSuite) TestLoggingOverrides(c *gc.C) {
+	expires := s.clock.Now().Add(time.Hour)
+	err := s.State.SetLoggingOverride(s.unit.Tag(), "juju.worker.uniter=TRACE", expires)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetLoggingOverride(s.machine.Tag(), "juju=DEBUG", expires)
+	c.Assert(err, jc.ErrorIsNil)
+
+	overrides, err := s.State.LoggingOverrides()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(overrides, gc.HasLen, 2)
+	c.Assert(overrides[0].Tag, gc.Equals, s.machine.Tag())
+	c.Assert(overrides[0].Config, gc.Equals, "juju=DEBUG")
+	c.Assert(overrides[1].Tag, gc.Equals, s.unit.Tag())
+	c.Assert(overrides[1].Config, gc.Equals, "juju.worker.uniter=TRACE")
+}
+
+func (s *LoggingOverridesSuite) TestRemoveLoggingOverride(c *gc.C) {
+	err := s.State.SetLoggingOverride(s.unit.Tag(), "juju=TRACE", s.clock.Now().Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveLoggingOverride(s.unit.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.LoggingOverride(s.unit.Tag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.RemoveLoggingOverride(s.unit.Tag())
+	c.Assert(err, gc.ErrorMatches, `logging override for "unit-wordpress-0" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+// overrideDocCount returns the number of logging override documents
+// stored, including expired ones.
+func (s *LoggingOverridesSuite) overrideDocCount(c *gc.C) int {
+	count, err := s.State.MongoSession().DB("juju").C("loggingoverrides").Count()
+	c.Assert(err, jc.ErrorIsNil)
+	return count
+}
+
+func (s *LoggingOverridesSuite) TestSetLoggingOverridePrunesExpired(c *gc.C) {
+	err := s.State.SetLoggingOverride(s.unit.Tag(), "juju=DEBUG", s.clock.Now().Add(10*time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetLoggingOverride(s.machine.Tag(), "juju=DEBUG", s.clock.Now().Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.overrideDocCount(c), gc.Equals, 2)
+
+	// The unit's override has expired by the time another is set.
+	s.clock.Advance(20 * time.Minute)
+	err = s.State.SetLoggingOverride(s.machine.Tag(), "juju=TRACE", s.clock.Now().Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.overrideDocCount(c), gc.Equals, 1)
+	_, err = s.State.LoggingOverride(s.machine.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *LoggingOverridesSuite) TestSetLoggingOverrideReplacesExpired(c *gc.C) {
+	err := s.State.SetLoggingOverride(s.unit.Tag(), "juju=DEBUG", s.clock.Now().Add(10*time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	s.clock.Advance(20 * time.Minute)
+	err = s.State.SetLoggingOverride(s.unit.Tag(), "juju=TRACE", s.clock.Now().Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+
+	override, err := s.State.LoggingOverride(s.unit.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(override.Config, gc.Equals, "juju=TRACE")
+	c.Assert(s.overrideDocCount(c), gc.Equals, 1)
+}
+
+func (s *LoggingOverridesSuite) TestRemoveUnitRemovesLoggingOverride(c *gc.C) {
+	err := s.State.SetLoggingOverride(s.unit.Tag(), "juju=DEBUG", s.clock.Now().Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.overrideDocCount(c), gc.Equals, 0)
+}
+
+func (s *LoggingOverridesSuite) TestRemoveMachineRemovesLoggingOverride(c *gc.C) {
+	err := s.State.SetLoggingOverride(s.machine.Tag(), "juju=DEBUG", s.clock.Now().Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.overrideDocCount(c), gc.Equals, 0)
+}
+
+func (s *LoggingOverridesSuite) TestWatchAgentLoggingConfig(c *gc.C) {
+	w := s.State.WatchAgentLoggingConfig(s.unit.Tag())
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Changing the environment's logging-config triggers the watcher.
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"logging-config": "<root>=ERROR"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// So does setting an override for the agent...
+	err = s.State.SetLoggingOverride(s.unit.Tag(), "juju=TRACE", s.clock.Now().Add(time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// ...but not for another agent.
+	err = s.State.SetLoggingOverride(s.machine.Tag(), "juju=TRACE", s.clock.Now().Add(time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// The override expiring triggers the watcher.
+	s.clock.Advance(time.Minute)
+	wc.AssertOneChange()
+
+	// As does removing an override.
+	err = s.State.SetLoggingOverride(s.unit.Tag(), "juju=TRACE", s.clock.Now().Add(time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	err = s.State.RemoveLoggingOverride(s.unit.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// The removed override's expiry does not trigger the watcher.
+	s.clock.Advance(time.Minute)
+	wc.AssertNoChange()
+}
@@ -8,6 +8,7 @@ package state
 
 import (
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -34,8 +35,9 @@ type LoggingState interface {
 	MongoSession() *mgo.Session
 }
 
-// InitDbLogs sets up the indexes for the logs collection. It should
-// be called as state is opened. It is idempotent.
+// InitDbLogs sets up the indexes for the logs and log archives
+// collections. It should be called as state is opened. It is
+// idempotent.
 func InitDbLogs(session *mgo.Session) error {
 	logsColl := session.DB(logsDB).C(logsC)
 	for _, key := range [][]string{{"e", "t"}, {"e", "n"}} {
@@ -44,6 +46,13 @@ func InitDbLogs(session *mgo.Session) error {
 			return errors.Annotate(err, "cannot create index for logs collection")
 		}
 	}
+	archivesColl := session.DB(logsDB).C(logArchivesC)
+	for _, key := range [][]string{{"e", "start"}, {"e", "end"}} {
+		err := archivesColl.EnsureIndex(mgo.Index{Key: key})
+		if err != nil {
+			return errors.Annotate(err, "cannot create index for log archives collection")
+		}
+	}
 	return nil
 }
 
@@ -61,6 +70,14 @@ type logDoc struct {
 	Location string        `bson:"l"` // "filename:lineno"
 	Level    loggo.Level   `bson:"v"`
 	Message  string        `bson:"x"`
+
+	// Labels holds structured key/value fields describing the
+	// record, such as the hook being run.
+	Labels map[string]string `bson:"f,omitempty"`
+
+	// ImportedAt is the time at which logs imported from an archive
+	// were imported. Such logs are not archived again when pruned.
+	ImportedAt time.Time `bson:"i,omitempty"`
 }
 
 type DbLogger struct {
@@ -82,6 +99,12 @@ func NewDbLogger(st LoggingState, entity names.Tag) *DbLogger {
 
 // Log writes a log message to the database.
 func (logger *DbLogger) Log(t time.Time, module string, location string, level loggo.Level, msg string) error {
+	return logger.LogWithLabels(t, module, location, level, msg, nil)
+}
+
+// LogWithLabels writes a log message to the database along with the
+// given labels.
+func (logger *DbLogger) LogWithLabels(t time.Time, module string, location string, level loggo.Level, msg string, labels map[string]string) error {
 	return logger.logsColl.Insert(&logDoc{
 		Id:       bson.NewObjectId(),
 		Time:     t,
@@ -91,6 +114,7 @@ func (logger *DbLogger) Log(t time.Time, module string, location string, level l
 		Location: location,
 		Level:    level,
 		Message:  msg,
+		Labels:   labels,
 	})
 }
 
@@ -132,12 +156,28 @@ type LogRecord struct {
 	Location string
 	Level    loggo.Level
 	Message  string
+	Labels   map[string]string
+
+	// Imported records whether the record was imported from a log
+	// archive rather than logged by an agent.
+	Imported bool
 }
 
 // LogTailerParams specifies the filtering a LogTailer should apply to
 // logs in order to decide which to return.
+//
+// If EndTime is set, only logs recorded at or before that time are
+// returned, and the LogTailer stops once EndTime has passed and the
+// matching logs have been returned. If MessageRegex is set, only logs
+// whose messages match the regular expression are returned; it uses Go
+// regexp syntax, and is evaluated by the LogTailer rather than by
+// MongoDB.
+// If Labels is set, only logs carrying all of the given labels with the
+// given values are returned.
 type LogTailerParams struct {
 	StartTime     time.Time
+	EndTime       time.Time
+	MessageRegex  string
 	MinLevel      loggo.Level
 	InitialLines  int
 	NoTail        bool
@@ -145,6 +185,7 @@ type LogTailerParams struct {
 	ExcludeEntity []string
 	IncludeModule []string
 	ExcludeModule []string
+	Labels        map[string]string
 	Oplog         *mgo.Collection // For testing only
 }
 
@@ -188,14 +229,15 @@ func NewLogTailer(st LoggingState, params *LogTailerParams) LogTailer {
 }
 
 type logTailer struct {
-	tomb      tomb.Tomb
-	envUUID   string
-	session   *mgo.Session
-	logsColl  *mgo.Collection
-	params    *LogTailerParams
-	logCh     chan *LogRecord
-	lastTime  time.Time
-	recentIds *recentIdTracker
+	tomb         tomb.Tomb
+	envUUID      string
+	session      *mgo.Session
+	logsColl     *mgo.Collection
+	params       *LogTailerParams
+	messageRegex *regexp.Regexp
+	logCh        chan *LogRecord
+	lastTime     time.Time
+	recentIds    *recentIdTracker
 }
 
 // Logs implements the LogTailer interface.
@@ -220,12 +262,20 @@ func (t *logTailer) Err() error {
 }
 
 func (t *logTailer) loop() error {
+	if t.params.MessageRegex != "" {
+		var err error
+		t.messageRegex, err = regexp.Compile(t.params.MessageRegex)
+		if err != nil {
+			return errors.Annotate(err, "invalid message regex")
+		}
+	}
+
 	err := t.processCollection()
 	if err != nil {
 		return errors.Trace(err)
 	}
 
-	if t.params.NoTail {
+	if t.params.NoTail || t.endTimePassed() {
 		return nil
 	}
 
@@ -233,11 +283,28 @@ func (t *logTailer) loop() error {
 	return errors.Trace(err)
 }
 
+// endTimePassed reports whether the tailer has an end time which has
+// already passed, so that no further matching logs are expected.
+func (t *logTailer) endTimePassed() bool {
+	return !t.params.EndTime.IsZero() && !t.params.EndTime.After(time.Now())
+}
+
+// matches reports whether the log document passes the filters that
+// are applied by the tailer rather than by the selector.
+func (t *logTailer) matches(doc *logDoc) bool {
+	return t.messageRegex == nil || t.messageRegex.MatchString(doc.Message)
+}
+
 func (t *logTailer) processCollection() error {
 	// Create a selector from the params.
 	sel := t.paramsToSelector(t.params, "")
 	query := t.logsColl.Find(sel)
 
+	if t.params.InitialLines > 0 && t.messageRegex != nil {
+		// The documents matching the message regex cannot be
+		// counted by the database, so find the last of them.
+		return t.processCollectionBacklog(query)
+	}
 	if t.params.InitialLines > 0 {
 		// This is a little racy but it's good enough.
 		count, err := query.Count()
@@ -252,17 +319,52 @@ func (t *logTailer) processCollection() error {
 	iter := query.Sort("t", "_id").Iter()
 	doc := new(logDoc)
 	for iter.Next(doc) {
-		select {
-		case <-t.tomb.Dying():
-			return errors.Trace(tomb.ErrDying)
-		case t.logCh <- logDocToRecord(doc):
-			t.lastTime = doc.Time
-			t.recentIds.Add(doc.Id)
+		if !t.matches(doc) {
+			continue
+		}
+		if err := t.send(doc); err != nil {
+			return errors.Trace(err)
 		}
 	}
 	return errors.Trace(iter.Close())
 }
 
+// processCollectionBacklog sends the last InitialLines documents
+// returned by the query that match the tailer's filters, oldest first.
+func (t *logTailer) processCollectionBacklog(query *mgo.Query) error {
+	var docs []*logDoc
+	iter := query.Sort("-t", "-_id").Iter()
+	doc := new(logDoc)
+	for len(docs) < t.params.InitialLines && iter.Next(doc) {
+		if t.matches(doc) {
+			docs = append(docs, doc)
+			doc = new(logDoc)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	for i := len(docs) - 1; i >= 0; i-- {
+		if err := t.send(docs[i]); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// send sends the log document from the logs collection to the tailer's
+// client, recording it so that it is not sent again from the oplog.
+func (t *logTailer) send(doc *logDoc) error {
+	select {
+	case <-t.tomb.Dying():
+		return errors.Trace(tomb.ErrDying)
+	case t.logCh <- logDocToRecord(doc):
+		t.lastTime = doc.Time
+		t.recentIds.Add(doc.Id)
+	}
+	return nil
+}
+
 func (t *logTailer) tailOplog() error {
 	recentIds := t.recentIds.AsSet()
 
@@ -284,11 +386,19 @@ func (t *logTailer) tailOplog() error {
 	logger.Tracef("LogTailer starting oplog tailing: recent id count=%d, lastTime=%s, minOplogTs=%s",
 		recentIds.Length(), t.lastTime, minOplogTs)
 
+	// Stop tailing once the end time, if any, has passed.
+	var endTime <-chan time.Time
+	if !t.params.EndTime.IsZero() {
+		endTime = time.After(t.params.EndTime.Sub(time.Now()))
+	}
+
 	skipCount := 0
 	for {
 		select {
 		case <-t.tomb.Dying():
 			return errors.Trace(tomb.ErrDying)
+		case <-endTime:
+			return nil
 		case oplogDoc, ok := <-oplogTailer.Out():
 			if !ok {
 				return errors.Annotate(oplogTailer.Err(), "oplog tailer died")
@@ -308,6 +418,9 @@ func (t *logTailer) tailOplog() error {
 				}
 				continue
 			}
+			if !t.matches(doc) {
+				continue
+			}
 
 			select {
 			case <-t.tomb.Dying():
@@ -319,9 +432,13 @@ func (t *logTailer) tailOplog() error {
 }
 
 func (t *logTailer) paramsToSelector(params *LogTailerParams, prefix string) bson.D {
+	timeSel := bson.M{"$gte": params.StartTime}
+	if !params.EndTime.IsZero() {
+		timeSel["$lte"] = params.EndTime
+	}
 	sel := bson.D{
 		{"e", t.envUUID},
-		{"t", bson.M{"$gte": params.StartTime}},
+		{"t", timeSel},
 	}
 	if params.MinLevel > loggo.UNSPECIFIED {
 		sel = append(sel, bson.DocElem{"v", bson.M{"$gte": params.MinLevel}})
@@ -342,6 +459,17 @@ func (t *logTailer) paramsToSelector(params *LogTailerParams, prefix string) bso
 		sel = append(sel,
 			bson.DocElem{"m", bson.M{"$not": bson.RegEx{Pattern: makeModulePattern(params.ExcludeModule)}}})
 	}
+	if len(params.Labels) > 0 {
+		// Sort the label names so the selector is deterministic.
+		labelNames := make([]string, 0, len(params.Labels))
+		for name := range params.Labels {
+			labelNames = append(labelNames, name)
+		}
+		sort.Strings(labelNames)
+		for _, name := range labelNames {
+			sel = append(sel, bson.DocElem{"f." + name, params.Labels[name]})
+		}
+	}
 
 	if prefix != "" {
 		for i, elem := range sel {
@@ -425,14 +553,62 @@ func logDocToRecord(doc *logDoc) *LogRecord {
 		Location: doc.Location,
 		Level:    doc.Level,
 		Message:  doc.Message,
+		Labels:   doc.Labels,
+		Imported: !doc.ImportedAt.IsZero(),
 	}
 }
 
+// LogPruneRule overrides the minimum log time passed to PruneLogs for
+// the logs of a module and/or level.
+type LogPruneRule struct {
+	// Module, when set, restricts the rule to logs from the module
+	// and its submodules.
+	Module string
+
+	// Level, when set, restricts the rule to logs of that level.
+	Level loggo.Level
+
+	// MinLogTime is the time before which matching logs are removed.
+	MinLogTime time.Time
+}
+
+// LogPrunePolicy specifies how the logs of a single environment are
+// pruned.
+type LogPrunePolicy struct {
+	// Rules override the minimum log time for matching logs. When
+	// several rules match a log, the one naming the longest module
+	// wins, with rules naming a level preferred over those that do
+	// not.
+	Rules []LogPruneRule
+
+	// MaxLogsMB, when positive, limits the size of the logs stored
+	// for the environment.
+	MaxLogsMB int
+
+	// Archive specifies whether logs are archived before they are
+	// removed. See LogArchives.
+	Archive bool
+
+	// MinArchiveTime, when set, is the time before which the newest
+	// record of an archive must be for the archive to be removed.
+	MinArchiveTime time.Time
+
+	// MinImportTime, when set, is the time since which logs imported
+	// from an archive are exempt from removal by age.
+	MinImportTime time.Time
+}
+
 // PruneLogs removes old log documents in order to control the size of
-// logs collection. All logs older than minLogTime are
-// removed. Further removal is also performed if the logs collection
-// size is greater than maxLogsMB.
-func PruneLogs(st LoggingState, minLogTime time.Time, maxLogsMB int) error {
+// logs collection. All logs older than minLogTime are removed, except
+// where the policy for their environment has a rule matching them or
+// exempts recently imported logs. The oldest logs of each environment
+// with a size limit in its policy are then removed until the
+// environment is within the limit. Further removal is also performed
+// if the logs collection size is greater than maxLogsMB.
+//
+// Logs are archived before removal where their environment's policy
+// requires it, and archives older than the policy allows are removed.
+func PruneLogs(st LoggingState, minLogTime time.Time, maxLogsMB int, policies map[string]LogPrunePolicy) error {
 	session, logsColl := initLogsSession(st)
 	defer session.Close()
 
@@ -446,14 +622,25 @@ func PruneLogs(st LoggingState, minLogTime time.Time, maxLogsMB int) error {
 	// Remove old log entries (per environment UUID to take advantage
 	// of indexes on the logs collection).
 	for _, envUUID := range envUUIDs {
-		removeInfo, err := logsColl.RemoveAll(bson.M{
-			"e": envUUID,
-			"t": bson.M{"$lt": minLogTime},
-		})
+		policy := policies[envUUID]
+		removed, err := pruneLogsByTime(logsColl, envUUID, minLogTime, policy.MinImportTime, policy.Rules, policy.Archive)
 		if err != nil {
 			return errors.Annotate(err, "failed to prune logs by time")
 		}
-		pruneCounts[envUUID] = removeInfo.Removed
+		pruneCounts[envUUID] = removed
+	}
+
+	// Keep each environment within its own size limit, if it has one.
+	for _, envUUID := range envUUIDs {
+		policy := policies[envUUID]
+		if policy.MaxLogsMB <= 0 {
+			continue
+		}
+		removed, err := pruneLogsBySize(logsColl, envUUID, policy.MaxLogsMB, policy.Archive)
+		if err != nil {
+			return errors.Annotate(err, "failed to prune logs by environment size")
+		}
+		pruneCounts[envUUID] += removed
 	}
 
 	// Do further pruning if the logs collection is over the maximum size.
@@ -474,40 +661,192 @@ func PruneLogs(st LoggingState, minLogTime time.Time, maxLogsMB int) error {
 			break // Pruning is not worthwhile
 		}
 
-		// Remove the oldest 1% of log records for the environment.
-		toRemove := int(float64(count) * 0.01)
+		removed, err := removeOldestLogs(logsColl, envUUID, count, policies[envUUID].Archive)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		pruneCounts[envUUID] += removed
+	}
+
+	for envUUID, count := range pruneCounts {
+		if count > 0 {
+			logger.Debugf("pruned %d logs for environment %s", count, envUUID)
+		}
+	}
 
-		// Find the threshold timestammp to start removing from.
-		// NOTE: this assumes that there are no more logs being added
-		// for the time range being pruned (which should be true for
-		// any realistic minimum log collection size).
-		tsQuery := logsColl.Find(bson.M{"e": envUUID}).Sort("t")
-		tsQuery = tsQuery.Skip(toRemove)
-		tsQuery = tsQuery.Select(bson.M{"t": 1})
-		var doc bson.M
-		err = tsQuery.One(&doc)
+	// Remove expired archives, whether or not logs are still being
+	// archived.
+	for envUUID, policy := range policies {
+		if policy.MinArchiveTime.IsZero() {
+			continue
+		}
+		removed, err := pruneLogArchives(logsColl.Database, envUUID, policy.MinArchiveTime)
 		if err != nil {
-			return errors.Annotate(err, "log pruning timestamp query failed")
+			return errors.Annotate(err, "failed to prune log archives")
 		}
-		thresholdTs := doc["t"].(time.Time)
+		if removed > 0 {
+			logger.Debugf("removed %d log archives for environment %s", removed, envUUID)
+		}
+	}
+	return nil
+}
 
-		// Remove old records.
-		removeInfo, err := logsColl.RemoveAll(bson.M{
+// pruneLogsByTime removes the logs of an environment which are older
+// than the minimum log time of the most specific rule matching them,
+// or than minLogTime when no rule matches. Logs imported since
+// minImportTime, if set, are kept.
+func pruneLogsByTime(coll *mgo.Collection, envUUID string, minLogTime, minImportTime time.Time, rules []LogPruneRule, archive bool) (int, error) {
+	sorted := make([]LogPruneRule, len(rules), len(rules)+1)
+	copy(sorted, rules)
+	sort.Stable(logPruneRulesBySpecificity(sorted))
+	sorted = append(sorted, LogPruneRule{MinLogTime: minLogTime})
+
+	// Each rule only removes logs not matched by a more specific
+	// rule, which have already been dealt with.
+	var removed int
+	var moreSpecific []bson.M
+	for _, rule := range sorted {
+		ruleSel := rule.selector()
+		sel := bson.M{
 			"e": envUUID,
-			"t": bson.M{"$lt": thresholdTs},
-		})
+			"t": bson.M{"$lt": rule.MinLogTime},
+		}
+		for name, value := range ruleSel {
+			sel[name] = value
+		}
+		if len(moreSpecific) > 0 {
+			sel["$nor"] = moreSpecific
+		}
+		if !minImportTime.IsZero() {
+			sel["i"] = bson.M{"$not": bson.M{"$gte": minImportTime}}
+		}
+		n, err := removeLogs(coll, envUUID, sel, archive)
 		if err != nil {
-			return errors.Annotate(err, "log pruning failed")
+			return removed, errors.Trace(err)
+		}
+		removed += n
+		if len(ruleSel) == 0 {
+			// The rule matches all logs, so no others apply.
+			break
 		}
-		pruneCounts[envUUID] += removeInfo.Removed
+		moreSpecific = append(moreSpecific, ruleSel)
 	}
+	return removed, nil
+}
 
-	for envUUID, count := range pruneCounts {
-		if count > 0 {
-			logger.Debugf("pruned %d logs for environment %s", count, envUUID)
+// selector returns the selector matching the logs covered by the
+// rule, ignoring its time.
+func (r LogPruneRule) selector() bson.M {
+	sel := bson.M{}
+	if r.Module != "" {
+		sel["m"] = bson.RegEx{Pattern: makeModulePattern([]string{r.Module})}
+	}
+	if r.Level != loggo.UNSPECIFIED {
+		sel["v"] = r.Level
+	}
+	return sel
+}
+
+// logPruneRulesBySpecificity sorts rules so that those naming longer
+// modules come first, followed by those naming a level.
+type logPruneRulesBySpecificity []LogPruneRule
+
+func (r logPruneRulesBySpecificity) Len() int      { return len(r) }
+func (r logPruneRulesBySpecificity) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r logPruneRulesBySpecificity) Less(i, j int) bool {
+	if len(r[i].Module) != len(r[j].Module) {
+		return len(r[i].Module) > len(r[j].Module)
+	}
+	return r[i].Level != loggo.UNSPECIFIED && r[j].Level == loggo.UNSPECIFIED
+}
+
+// pruneLogsBySize removes the oldest logs of an environment until their
+// size is no more than maxLogsMB. The size is an estimate: the number
+// of log records multiplied by the average size of a sample of the
+// environment's most recent records, which excludes the space taken by
+// indexes.
+func pruneLogsBySize(coll *mgo.Collection, envUUID string, maxLogsMB int, archive bool) (int, error) {
+	logSize, err := getAverageLogSize(coll, envUUID)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	maxSize := float64(maxLogsMB) * humanize.MiByte
+	var removed int
+	for {
+		count, err := getLogCountForEnv(coll, envUUID)
+		if err != nil {
+			return removed, errors.Trace(err)
+		}
+		if float64(count)*logSize <= maxSize {
+			return removed, nil
+		}
+		n, err := removeOldestLogs(coll, envUUID, count, archive)
+		if err != nil {
+			return removed, errors.Trace(err)
+		}
+		if n == 0 {
+			// The oldest logs all share a timestamp.
+			return removed, nil
 		}
+		removed += n
 	}
-	return nil
+}
+
+// removeOldestLogs removes the oldest 1% (and at least one) of the
+// given number of log records stored for an environment.
+func removeOldestLogs(coll *mgo.Collection, envUUID string, count int, archive bool) (int, error) {
+	toRemove := int(float64(count) * 0.01)
+	if toRemove < 1 {
+		toRemove = 1
+	}
+
+	// Find the threshold timestammp to start removing from.
+	// NOTE: this assumes that there are no more logs being added
+	// for the time range being pruned (which should be true for
+	// any realistic minimum log collection size).
+	tsQuery := coll.Find(bson.M{"e": envUUID}).Sort("t")
+	tsQuery = tsQuery.Skip(toRemove)
+	tsQuery = tsQuery.Select(bson.M{"t": 1})
+	var doc bson.M
+	err := tsQuery.One(&doc)
+	if err == mgo.ErrNotFound {
+		// Fewer logs than expected remain, so remove them all.
+		removed, err := removeLogs(coll, envUUID, bson.M{"e": envUUID}, archive)
+		if err != nil {
+			return 0, errors.Annotate(err, "log pruning failed")
+		}
+		return removed, nil
+	} else if err != nil {
+		return 0, errors.Annotate(err, "log pruning timestamp query failed")
+	}
+	thresholdTs := doc["t"].(time.Time)
+
+	// Remove old records.
+	removed, err := removeLogs(coll, envUUID, bson.M{
+		"e": envUUID,
+		"t": bson.M{"$lt": thresholdTs},
+	}, archive)
+	if err != nil {
+		return 0, errors.Annotate(err, "log pruning failed")
+	}
+	return removed, nil
+}
+
+// removeLogs removes the logs of an environment matching the given
+// selector, first archiving them if archive is set.
+func removeLogs(coll *mgo.Collection, envUUID string, sel bson.M, archive bool) (int, error) {
+	if archive {
+		removed, err := archiveLogs(coll, envUUID, sel)
+		if err != nil {
+			return removed, errors.Annotate(err, "cannot archive logs")
+		}
+		return removed, nil
+	}
+	removeInfo, err := coll.RemoveAll(sel)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return removeInfo.Removed, nil
 }
 
 // initLogsSession creates a new session suitable for logging updates,
@@ -539,6 +878,29 @@ func getCollectionMB(coll *mgo.Collection) (int, error) {
 	return result["size"].(int), nil
 }
 
+// logSizeSampleCount is the number of log records sampled to estimate
+// the average size of an environment's log records.
+const logSizeSampleCount = 1000
+
+// getAverageLogSize returns the average size (in bytes) of the most
+// recent log records of an environment, or zero if it has none.
+func getAverageLogSize(coll *mgo.Collection, envUUID string) (float64, error) {
+	iter := coll.Find(bson.M{"e": envUUID}).Sort("-t").Limit(logSizeSampleCount).Iter()
+	var doc bson.Raw
+	var count, total int
+	for iter.Next(&doc) {
+		count++
+		total += len(doc.Data)
+	}
+	if err := iter.Close(); err != nil {
+		return 0, errors.Trace(err)
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	return float64(total) / float64(count), nil
+}
+
 // getEnvsInLogs returns the unique environment UUIDs that exist in
 // the logs collection. This uses the one of the indexes on the
 // collection and should be fast.
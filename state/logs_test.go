@@ -79,6 +79,26 @@ func (s *LogsSuite) TestDbLogger(c *gc.C) {
 	c.Assert(docs[1]["x"], gc.Equals, "oh noes")
 }
 
+func (s *LogsSuite) TestDbLoggerLabels(c *gc.C) {
+	logger := state.NewDbLogger(s.State, names.NewUnitTag("mysql/0"))
+	defer logger.Close()
+	t0 := time.Now().Truncate(time.Millisecond)
+	err := logger.LogWithLabels(t0, "unit.mysql/0.install", "foo.go:99", loggo.INFO, "installing",
+		map[string]string{"hook": "install"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = logger.Log(t0, "juju.worker.uniter", "bar.go:42", loggo.INFO, "unlabelled")
+	c.Assert(err, jc.ErrorIsNil)
+
+	var docs []bson.M
+	err = s.logsColl.Find(nil).Sort("m").All(&docs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(docs, gc.HasLen, 2)
+	c.Assert(docs[0]["m"], gc.Equals, "juju.worker.uniter")
+	_, ok := docs[0]["f"]
+	c.Assert(ok, jc.IsFalse)
+	c.Assert(docs[1]["f"], gc.DeepEquals, bson.M{"hook": "install"})
+}
+
 func (s *LogsSuite) TestPruneLogsByTime(c *gc.C) {
 	dbLogger := state.NewDbLogger(s.State, names.NewMachineTag("22"))
 	defer dbLogger.Close()
@@ -96,7 +116,7 @@ func (s *LogsSuite) TestPruneLogsByTime(c *gc.C) {
 	log(maxLogTime.Add(-(2 * time.Second)), "prune")
 
 	noPruneMB := 100
-	err := state.PruneLogs(s.State, maxLogTime, noPruneMB)
+	err := state.PruneLogs(s.State, maxLogTime, noPruneMB, nil)
 	c.Assert(err, jc.ErrorIsNil)
 
 	// After pruning there should just be 3 "keep" messages left.
@@ -130,7 +150,7 @@ func (s *LogsSuite) TestPruneLogsBySize(c *gc.C) {
 
 	// Prune logs collection back to 1 MiB.
 	tsNoPrune := time.Now().Add(-3 * 24 * time.Hour)
-	err := state.PruneLogs(s.State, tsNoPrune, 1)
+	err := state.PruneLogs(s.State, tsNoPrune, 1, nil)
 	c.Assert(err, jc.ErrorIsNil)
 
 	// Logs for first env should not be touched.
@@ -155,6 +175,81 @@ func (s *LogsSuite) TestPruneLogsBySize(c *gc.C) {
 	assertLatestTs(s2)
 }
 
+func (s *LogsSuite) TestPruneLogsByRule(c *gc.C) {
+	dbLogger := state.NewDbLogger(s.State, names.NewMachineTag("22"))
+	defer dbLogger.Close()
+	now := time.Now()
+	log := func(age time.Duration, module string, level loggo.Level, msg string) {
+		err := dbLogger.Log(now.Add(-age), module, "loc", level, msg)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	// By default logs are kept for an hour, but errors for a day,
+	// debug logs for a minute, and any logs from juju.worker.uniter
+	// (which is more specific) for ten minutes.
+	log(2*time.Hour, "juju.apiserver", loggo.INFO, "prune")
+	log(30*time.Minute, "juju.apiserver", loggo.INFO, "keep")
+	log(2*time.Hour, "juju.apiserver", loggo.ERROR, "keep")
+	log(2*24*time.Hour, "juju.apiserver", loggo.ERROR, "prune")
+	log(2*time.Minute, "juju.apiserver", loggo.DEBUG, "prune")
+	log(20*time.Minute, "juju.worker.uniter.operation", loggo.ERROR, "prune")
+	log(5*time.Minute, "juju.worker.uniter", loggo.DEBUG, "keep")
+	log(2*time.Hour, "juju.worker.uniterx", loggo.ERROR, "keep")
+
+	policies := map[string]state.LogPrunePolicy{
+		s.State.EnvironUUID(): {
+			Rules: []state.LogPruneRule{
+				{Level: loggo.ERROR, MinLogTime: now.Add(-24 * time.Hour)},
+				{Module: "juju.worker.uniter", MinLogTime: now.Add(-10 * time.Minute)},
+				{Level: loggo.DEBUG, MinLogTime: now.Add(-time.Minute)},
+			},
+		},
+	}
+	err := state.PruneLogs(s.State, now.Add(-time.Hour), 100, policies)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var docs []bson.M
+	err = s.logsColl.Find(nil).All(&docs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(docs, gc.HasLen, 4)
+	for _, doc := range docs {
+		c.Check(doc["x"], gc.Equals, "keep", gc.Commentf("%v", doc))
+	}
+}
+
+func (s *LogsSuite) TestPruneLogsByEnvironmentSize(c *gc.C) {
+	now := time.Now().Truncate(time.Millisecond)
+
+	s0 := s.State
+	startingLogsS0 := 10000
+	s.generateLogs(c, s0, now, startingLogsS0)
+
+	s1 := s.Factory.MakeEnvironment(c, nil)
+	defer s1.Close()
+	startingLogsS1 := 10000
+	s.generateLogs(c, s1, now, startingLogsS1)
+
+	// Only the second environment is limited, to well below the size
+	// of its logs.
+	policies := map[string]state.LogPrunePolicy{
+		s1.EnvironUUID(): {MaxLogsMB: 1},
+	}
+	tsNoPrune := now.Add(-3 * 24 * time.Hour)
+	err := state.PruneLogs(s.State, tsNoPrune, 100, policies)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.countLogs(c, s0), gc.Equals, startingLogsS0)
+	remaining := s.countLogs(c, s1)
+	c.Assert(remaining, jc.LessThan, startingLogsS1)
+	c.Assert(remaining, jc.GreaterThan, 0)
+
+	// The latest log records are kept.
+	var doc bson.M
+	err = s.logsColl.Find(bson.M{"e": s1.EnvironUUID()}).Sort("-t").One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(doc["t"].(time.Time), gc.Equals, now)
+}
+
 func (s *LogsSuite) generateLogs(c *gc.C, st *state.State, endTime time.Time, count int) {
 	dbLogger := state.NewDbLogger(st, names.NewMachineTag("0"))
 	defer dbLogger.Close()
@@ -220,6 +315,63 @@ func (s *LogTailerSuite) TestTimeFiltering(c *gc.C) {
 
 }
 
+func (s *LogTailerSuite) TestEndTimeFiltering(c *gc.C) {
+	threshT := time.Now()
+	want := logTemplate{Message: "want"}
+	s.writeLogsT(c, threshT.Add(-5*time.Second), threshT, 5, want)
+	s.writeLogsT(c, threshT.Add(time.Second), threshT.Add(5*time.Second), 5,
+		logTemplate{Message: "dont want"},
+	)
+
+	tailer := state.NewLogTailer(s.State, &state.LogTailerParams{
+		EndTime: threshT,
+		Oplog:   s.oplogColl,
+	})
+	defer tailer.Stop()
+	s.assertTailer(c, tailer, 5, want)
+
+	// The tailer stops once the logs up to the end time have been
+	// returned, rather than tailing the oplog.
+	select {
+	case _, ok := <-tailer.Logs():
+		if ok {
+			c.Fatal("shouldn't be any further logs")
+		}
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for logs channel to close")
+	}
+}
+
+func (s *LogTailerSuite) TestFutureEndTime(c *gc.C) {
+	want := logTemplate{Message: "want"}
+	s.writeLogs(c, 2, want)
+
+	endTime := time.Now().Add(2 * time.Second)
+	tailer := state.NewLogTailer(s.State, &state.LogTailerParams{
+		EndTime: endTime,
+		Oplog:   s.oplogColl,
+	})
+	defer tailer.Stop()
+	s.assertTailer(c, tailer, 2, want)
+
+	// The end time hasn't passed, so logs written now are read from
+	// the oplog.
+	want2 := logTemplate{Message: "want 2"}
+	s.writeLogs(c, 3, want2)
+	s.assertTailer(c, tailer, 3, want2)
+
+	// Once the end time has passed, the tailer stops itself.
+	select {
+	case _, ok := <-tailer.Logs():
+		if ok {
+			c.Fatal("shouldn't be any further logs")
+		}
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for logs channel to close")
+	}
+	c.Assert(time.Now().Before(endTime), jc.IsFalse)
+}
+
 func (s *LogTailerSuite) TestOplogTransition(c *gc.C) {
 	// Ensure that logs aren't repeated as the log tailer moves from
 	// reading from the logs collection to tailing the oplog.
@@ -509,6 +661,90 @@ func (s *LogTailerSuite) TestIncludeExcludeModule(c *gc.C) {
 	s.checkLogTailerFiltering(params, writeLogs, assert)
 }
 
+func (s *LogTailerSuite) TestMessageRegex(c *gc.C) {
+	failed := logTemplate{Message: "hook failed: install"}
+	writeLogs := func() {
+		s.writeLogs(c, 1, logTemplate{Message: "hook succeeded: install"})
+		s.writeLogs(c, 2, failed)
+		s.writeLogs(c, 1, logTemplate{Message: "nothing failed"})
+	}
+	params := &state.LogTailerParams{
+		MessageRegex: "^hook fail",
+	}
+	assert := func(tailer state.LogTailer) {
+		s.assertTailer(c, tailer, 2, failed)
+	}
+	s.checkLogTailerFiltering(params, writeLogs, assert)
+}
+
+func (s *LogTailerSuite) TestMessageRegexGoSyntax(c *gc.C) {
+	// The regex is evaluated with Go's regexp package rather than by
+	// MongoDB, so $ only matches at the very end of the message, not
+	// before a trailing newline as it would with PCRE.
+	want := logTemplate{Message: "hook failed"}
+	writeLogs := func() {
+		s.writeLogs(c, 1, logTemplate{Message: "hook failed\n"})
+		s.writeLogs(c, 2, want)
+	}
+	params := &state.LogTailerParams{
+		MessageRegex: "^hook failed$",
+	}
+	assert := func(tailer state.LogTailer) {
+		s.assertTailer(c, tailer, 2, want)
+	}
+	s.checkLogTailerFiltering(params, writeLogs, assert)
+}
+
+func (s *LogTailerSuite) TestMessageRegexInitialLines(c *gc.C) {
+	s.writeLogs(c, 3, logTemplate{Message: "hook failed: install"})
+	s.writeLogs(c, 3, logTemplate{Message: "nothing failed"})
+	want := logTemplate{Message: "hook failed: start"}
+	s.writeLogs(c, 2, want)
+	s.writeLogs(c, 3, logTemplate{Message: "nothing failed"})
+
+	tailer := state.NewLogTailer(s.State, &state.LogTailerParams{
+		MessageRegex: "^hook fail",
+		InitialLines: 2,
+	})
+	defer tailer.Stop()
+
+	// Should see just the last 2 matching lines, even though later
+	// lines don't match.
+	s.assertTailer(c, tailer, 2, want)
+}
+
+func (s *LogTailerSuite) TestInvalidMessageRegex(c *gc.C) {
+	tailer := state.NewLogTailer(s.State, &state.LogTailerParams{
+		MessageRegex: "(",
+	})
+	defer tailer.Stop()
+
+	select {
+	case <-tailer.Dying():
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("tailer didn't stop itself")
+	}
+	c.Assert(tailer.Stop(), gc.ErrorMatches, "invalid message regex: .*")
+}
+
+func (s *LogTailerSuite) TestLabels(c *gc.C) {
+	install := logTemplate{Labels: map[string]string{"hook": "install"}}
+	relation := logTemplate{Labels: map[string]string{"hook": "db-relation-joined", "relation": "1"}}
+	writeLogs := func() {
+		s.writeLogs(c, 1, logTemplate{})
+		s.writeLogs(c, 2, install)
+		s.writeLogs(c, 1, logTemplate{Labels: map[string]string{"hook": "db-relation-joined", "relation": "2"}})
+		s.writeLogs(c, 3, relation)
+	}
+	params := &state.LogTailerParams{
+		Labels: map[string]string{"hook": "db-relation-joined", "relation": "1"},
+	}
+	assert := func(tailer state.LogTailer) {
+		s.assertTailer(c, tailer, 3, relation)
+	}
+	s.checkLogTailerFiltering(params, writeLogs, assert)
+}
+
 func (s *LogTailerSuite) checkLogTailerFiltering(
 	params *state.LogTailerParams,
 	writeLogs func(),
@@ -535,6 +771,7 @@ type logTemplate struct {
 	Location string
 	Level    loggo.Level
 	Message  string
+	Labels   map[string]string
 }
 
 // writeLogs creates count log messages at the current time using
@@ -596,7 +833,7 @@ func (s *LogTailerSuite) normaliseLogTemplate(lt *logTemplate) {
 
 func (s *LogTailerSuite) logTemplateToDoc(lt logTemplate, t time.Time) interface{} {
 	s.normaliseLogTemplate(&lt)
-	return state.MakeLogDoc(
+	doc := state.MakeLogDoc(
 		lt.EnvUUID,
 		lt.Entity,
 		t,
@@ -605,6 +842,8 @@ func (s *LogTailerSuite) logTemplateToDoc(lt logTemplate, t time.Time) interface
 		lt.Level,
 		lt.Message,
 	)
+	doc.Labels = lt.Labels
+	return doc
 }
 
 func (s *LogTailerSuite) assertTailer(c *gc.C, tailer state.LogTailer, expectedCount int, lt logTemplate) {
@@ -623,6 +862,7 @@ func (s *LogTailerSuite) assertTailer(c *gc.C, tailer state.LogTailer, expectedC
 			c.Assert(log.Location, gc.Equals, lt.Location)
 			c.Assert(log.Level, gc.Equals, lt.Level)
 			c.Assert(log.Message, gc.Equals, lt.Message)
+			c.Assert(log.Labels, jc.DeepEquals, lt.Labels)
 			count++
 			if count == expectedCount {
 				return
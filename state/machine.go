@@ -142,6 +142,10 @@ type machineDoc struct {
 	// Placement is the placement directive that should be used when provisioning
 	// an instance for the machine.
 	Placement string `bson:",omitempty"`
+
+	// Unschedulable is true if the machine must not be assigned any new
+	// units, because it is being (or has been) drained.
+	Unschedulable bool `bson:"unschedulable,omitempty"`
 }
 
 func newMachine(st *State, doc *machineDoc) *Machine {
@@ -844,6 +848,8 @@ func (m *Machine) Remove() (err error) {
 		annotationRemoveOp(m.st, m.globalKey()),
 		removeRebootDocOp(m.st, m.globalKey()),
 		removeMachineBlockDevicesOp(m.Id()),
+		removeMachineDrainOp(m.st, m.Id()),
+		removeLoggingOverrideOp(m.st, m.Tag()),
 	}
 	ifacesOps, err := m.removeNetworkInterfacesOps()
 	if err != nil {
@@ -861,11 +867,16 @@ func (m *Machine) Remove() (err error) {
 	if err != nil {
 		return err
 	}
+	snapshotOps, err := m.st.removeMachineVolumeSnapshotsOps(m.MachineTag())
+	if err != nil {
+		return err
+	}
 	ops = append(ops, ifacesOps...)
 	ops = append(ops, portsOps...)
 	ops = append(ops, removeContainerRefOps(m.st, m.Id())...)
 	ops = append(ops, filesystemOps...)
 	ops = append(ops, volumeOps...)
+	ops = append(ops, snapshotOps...)
 	ipAddresses, err := m.st.AllocatedIPAddresses(m.Id())
 	if err != nil {
 		return errors.Trace(err)
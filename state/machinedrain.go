@@ -0,0 +1,634 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// MachineDrainStatus describes the progress of a machine drain.
+type MachineDrainStatus string
+
+const (
+	// MachineDrainPending indicates that the machine has been marked
+	// unschedulable, but no replacement units have been added yet.
+	MachineDrainPending MachineDrainStatus = "pending"
+
+	// MachineDrainReplacing indicates that replacement units have been
+	// added on other machines, and the drain is waiting for them to
+	// settle.
+	MachineDrainReplacing MachineDrainStatus = "replacing"
+
+	// MachineDrainRemoving indicates that all replacement units have
+	// settled, and the original units are being removed.
+	MachineDrainRemoving MachineDrainStatus = "removing"
+
+	// MachineDrainCompleted indicates that all units have been
+	// evacuated from the machine.
+	MachineDrainCompleted MachineDrainStatus = "completed"
+
+	// MachineDrainFailed indicates that the drain was abandoned, either
+	// because of an error or because it was cancelled.
+	MachineDrainFailed MachineDrainStatus = "failed"
+)
+
+// Done reports whether the status is terminal.
+func (s MachineDrainStatus) Done() bool {
+	return s == MachineDrainCompleted || s == MachineDrainFailed
+}
+
+var drainDoneDoc = bson.D{{"status", bson.D{{"$in", []MachineDrainStatus{
+	MachineDrainCompleted, MachineDrainFailed,
+}}}}}
+
+var drainNotDoneDoc = bson.D{{"status", bson.D{{"$nin", []MachineDrainStatus{
+	MachineDrainCompleted, MachineDrainFailed,
+}}}}}
+
+// machineDrainDoc records the progress of evacuating the units from
+// a machine. There is at most one document per machine; starting a new
+// drain overwrites the record of a finished one.
+type machineDrainDoc struct {
+	DocID        string               `bson:"_id"`
+	EnvUUID      string               `bson:"env-uuid"`
+	MachineId    string               `bson:"machineid"`
+	Status       MachineDrainStatus   `bson:"status"`
+	Message      string               `bson:"message,omitempty"`
+	Replacements []unitReplacementDoc `bson:"replacements,omitempty"`
+	Started      time.Time            `bson:"started"`
+	Updated      time.Time            `bson:"updated"`
+}
+
+type unitReplacementDoc struct {
+	Original    string    `bson:"original"`
+	Replacement string    `bson:"replacement"`
+	Added       time.Time `bson:"added"`
+}
+
+// UnitReplacement pairs a unit on a draining machine with the unit
+// added elsewhere to replace it.
+type UnitReplacement struct {
+	// Original is the name of the unit being evacuated.
+	Original string
+
+	// Replacement is the name of the unit added to replace it.
+	Replacement string
+
+	// Added is the time at which the replacement unit was added.
+	Added time.Time
+}
+
+// MachineDrain represents the evacuation of units from a machine.
+type MachineDrain struct {
+	st  *State
+	doc machineDrainDoc
+}
+
+// MachineId returns the id of the machine being drained.
+func (d *MachineDrain) MachineId() string {
+	return d.doc.MachineId
+}
+
+// Status returns the progress of the drain.
+func (d *MachineDrain) Status() MachineDrainStatus {
+	return d.doc.Status
+}
+
+// Message returns any message recorded with the drain's status.
+func (d *MachineDrain) Message() string {
+	return d.doc.Message
+}
+
+// Started returns the time at which the drain was started.
+func (d *MachineDrain) Started() time.Time {
+	return d.doc.Started
+}
+
+// Updated returns the time at which the drain's status last changed.
+func (d *MachineDrain) Updated() time.Time {
+	return d.doc.Updated
+}
+
+// Replacements returns the units that have been added to replace
+// the units on the machine.
+func (d *MachineDrain) Replacements() []UnitReplacement {
+	result := make([]UnitReplacement, len(d.doc.Replacements))
+	for i, r := range d.doc.Replacements {
+		result[i] = UnitReplacement{r.Original, r.Replacement, r.Added}
+	}
+	return result
+}
+
+// Refresh refreshes the contents of the MachineDrain from the
+// underlying state.
+func (d *MachineDrain) Refresh() error {
+	drain, err := d.st.MachineDrain(d.doc.MachineId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	d.doc = drain.doc
+	return nil
+}
+
+// AddReplacementUnit adds a unit of the given unit's service to replace
+// it, recording the replacement in the same transaction so that a unit
+// is never added without the drain knowing about it. If the unit has
+// already been replaced, the existing replacement is returned. The new
+// unit is not assigned to a machine. It is an error to call
+// AddReplacementUnit on a drain that is not pending.
+func (d *MachineDrain) AddReplacementUnit(original *Unit) (*Unit, error) {
+	service, err := original.Service()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := d.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+			if err := service.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if d.doc.Status != MachineDrainPending {
+			return nil, errors.New("drain is not pending")
+		}
+		if _, ok := d.replacementFor(original.Name()); ok {
+			return nil, jujutxn.ErrNoOperations
+		}
+		name, ops, err := service.addUnitOps("", nil)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, txn.Op{
+			C:  machineDrainsC,
+			Id: d.doc.DocID,
+			Assert: bson.D{
+				{"status", MachineDrainPending},
+				{"replacements.original", bson.D{{"$ne", original.Name()}}},
+			},
+			Update: bson.D{{"$push", bson.D{{"replacements", unitReplacementDoc{
+				Original:    original.Name(),
+				Replacement: name,
+				Added:       nowToTheSecond(),
+			}}}}},
+		}), nil
+	}
+	if err := d.st.run(buildTxn); err != nil {
+		return nil, errors.Annotatef(err, "cannot add replacement for unit %q", original.Name())
+	}
+	if err := d.Refresh(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	name, ok := d.replacementFor(original.Name())
+	if !ok {
+		return nil, errors.Errorf("cannot add replacement for unit %q: drain is not pending", original.Name())
+	}
+	return d.st.Unit(name)
+}
+
+// replacementFor returns the name of the unit recorded as replacing the
+// named unit, if any.
+func (d *MachineDrain) replacementFor(original string) (string, bool) {
+	for _, r := range d.doc.Replacements {
+		if r.Original == original {
+			return r.Replacement, true
+		}
+	}
+	return "", false
+}
+
+// SetReplacing records that replacements have been added for all the
+// units on the machine, and moves the drain into the
+// MachineDrainReplacing status. It is an error to call SetReplacing on
+// a drain that is not pending.
+func (d *MachineDrain) SetReplacing() error {
+	now := nowToTheSecond()
+	ops := []txn.Op{{
+		C:      machineDrainsC,
+		Id:     d.doc.DocID,
+		Assert: bson.D{{"status", MachineDrainPending}},
+		Update: bson.D{{"$set", bson.D{
+			{"status", MachineDrainReplacing},
+			{"updated", now},
+		}}},
+	}}
+	if err := d.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(
+			onAbort(err, errors.New("drain is not pending")),
+			"cannot set machine %q drain replacing", d.doc.MachineId,
+		)
+	}
+	d.doc.Status = MachineDrainReplacing
+	d.doc.Updated = now
+	return nil
+}
+
+// SetStatus updates the progress of the drain. A drain that is done
+// cannot be updated.
+func (d *MachineDrain) SetStatus(status MachineDrainStatus, message string) error {
+	now := nowToTheSecond()
+	ops := []txn.Op{{
+		C:      machineDrainsC,
+		Id:     d.doc.DocID,
+		Assert: drainNotDoneDoc,
+		Update: bson.D{{"$set", bson.D{
+			{"status", status},
+			{"message", message},
+			{"updated", now},
+		}}},
+	}}
+	if err := d.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(
+			onAbort(err, errors.New("drain is already done")),
+			"cannot set status of machine %q drain", d.doc.MachineId,
+		)
+	}
+	d.doc.Status = status
+	d.doc.Message = message
+	d.doc.Updated = now
+	return nil
+}
+
+// MachineDrain returns the most recent drain of the machine with the
+// given id.
+func (st *State) MachineDrain(machineId string) (*MachineDrain, error) {
+	drains, closer := st.getCollection(machineDrainsC)
+	defer closer()
+
+	var doc machineDrainDoc
+	err := drains.FindId(machineId).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("drain for machine %q", machineId)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get drain for machine %q", machineId)
+	}
+	return &MachineDrain{st, doc}, nil
+}
+
+// ActiveMachineDrains returns all machine drains that are not yet done.
+func (st *State) ActiveMachineDrains() ([]*MachineDrain, error) {
+	drains, closer := st.getCollection(machineDrainsC)
+	defer closer()
+
+	var docs []machineDrainDoc
+	if err := drains.Find(drainNotDoneDoc).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get machine drains")
+	}
+	result := make([]*MachineDrain, len(docs))
+	for i, doc := range docs {
+		result[i] = &MachineDrain{st, doc}
+	}
+	return result, nil
+}
+
+// Unschedulable reports whether the machine has been marked as unable
+// to accept new units, as happens when it is drained.
+func (m *Machine) Unschedulable() bool {
+	return m.doc.Unschedulable
+}
+
+// Drain marks the machine as unschedulable, so that no new units will
+// be assigned to it, and records a pending drain that will cause its
+// units to be replaced by units on other machines.
+func (m *Machine) Drain() (*MachineDrain, error) {
+	now := nowToTheSecond()
+	doc := machineDrainDoc{
+		DocID:     m.st.docID(m.doc.Id),
+		EnvUUID:   m.st.EnvironUUID(),
+		MachineId: m.doc.Id,
+		Status:    MachineDrainPending,
+		Started:   now,
+		Updated:   now,
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if m.doc.Life != Alive {
+			return nil, errors.Errorf("machine is not alive")
+		}
+		ops := []txn.Op{{
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: isAliveDoc,
+			Update: bson.D{{"$set", bson.D{{"unschedulable", true}}}},
+		}}
+		existing, err := m.st.MachineDrain(m.doc.Id)
+		if errors.IsNotFound(err) {
+			return append(ops, txn.Op{
+				C:      machineDrainsC,
+				Id:     doc.DocID,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			}), nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !existing.Status().Done() {
+			return nil, errors.AlreadyExistsf("drain for machine %q", m.doc.Id)
+		}
+		return append(ops, txn.Op{
+			C:      machineDrainsC,
+			Id:     doc.DocID,
+			Assert: drainDoneDoc,
+			Update: bson.D{
+				{"$set", bson.D{
+					{"status", doc.Status},
+					{"started", doc.Started},
+					{"updated", doc.Updated},
+				}},
+				{"$unset", bson.D{
+					{"message", nil},
+					{"replacements", nil},
+				}},
+			},
+		}), nil
+	}
+	if err := m.st.run(buildTxn); err != nil {
+		return nil, errors.Annotatef(err, "cannot drain machine %q", m.doc.Id)
+	}
+	m.doc.Unschedulable = true
+	return &MachineDrain{m.st, doc}, nil
+}
+
+// Undrain marks the machine as able to accept new units again. If a
+// drain of the machine is in progress, it is abandoned: any replacement
+// units already added are left in place, and any original units that
+// have not yet been removed remain on the machine.
+func (m *Machine) Undrain() error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		ops := []txn.Op{{
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{{"unschedulable", false}}}},
+		}}
+		drain, err := m.st.MachineDrain(m.doc.Id)
+		if errors.IsNotFound(err) {
+			return ops, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if drain.Status().Done() {
+			if !m.doc.Unschedulable {
+				return nil, jujutxn.ErrNoOperations
+			}
+			return ops, nil
+		}
+		return append(ops, txn.Op{
+			C:      machineDrainsC,
+			Id:     drain.doc.DocID,
+			Assert: drainNotDoneDoc,
+			Update: bson.D{{"$set", bson.D{
+				{"status", MachineDrainFailed},
+				{"message", "drain cancelled"},
+				{"updated", nowToTheSecond()},
+			}}},
+		}), nil
+	}
+	if err := m.st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot undrain machine %q", m.doc.Id)
+	}
+	m.doc.Unschedulable = false
+	return nil
+}
+
+// removeMachineDrainOp returns an operation that removes the drain
+// record of the machine with the given id, if any.
+func removeMachineDrainOp(st *State, machineId string) txn.Op {
+	return txn.Op{
+		C:      machineDrainsC,
+		Id:     st.docID(machineId),
+		Remove: true,
+	}
+}
+
+// AdvanceMachineDrains moves every machine drain in progress on by at
+// most one step: adding replacement units on other machines, waiting for
+// them to settle, and then removing the units from the drained machine.
+// A drain fails if any replacement unit has not settled within
+// replacementTimeout of being added. Problems with a drain itself are
+// recorded by failing that drain; only errors accessing state are
+// returned.
+func (st *State) AdvanceMachineDrains(replacementTimeout time.Duration) error {
+	return st.advanceMachineDrains(replacementTimeout, time.Now())
+}
+
+func (st *State) advanceMachineDrains(replacementTimeout time.Duration, now time.Time) error {
+	drains, err := st.ActiveMachineDrains()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, drain := range drains {
+		if err := drain.advance(replacementTimeout, now); err != nil {
+			return errors.Annotatef(err, "draining machine %q", drain.MachineId())
+		}
+	}
+	return nil
+}
+
+// advance moves the drain on by at most one step.
+func (d *MachineDrain) advance(replacementTimeout time.Duration, now time.Time) error {
+	var err error
+	switch status := d.Status(); status {
+	case MachineDrainPending:
+		err = d.addReplacements()
+	case MachineDrainReplacing:
+		err = d.removeReplacedUnits(replacementTimeout, now)
+	case MachineDrainRemoving:
+		err = d.checkRemoved()
+	default:
+		return errors.Errorf("unexpected drain status %q", status)
+	}
+	if failure, ok := errors.Cause(err).(drainFailure); ok {
+		logger.Warningf("drain of machine %q failed: %v", d.MachineId(), failure)
+		return errors.Trace(d.SetStatus(MachineDrainFailed, failure.Error()))
+	}
+	return errors.Trace(err)
+}
+
+// drainFailure is an error that causes a drain to be abandoned, rather
+// than being returned to the caller.
+type drainFailure string
+
+func (f drainFailure) Error() string {
+	return string(f)
+}
+
+func drainFailuref(format string, args ...interface{}) error {
+	return drainFailure(fmt.Sprintf(format, args...))
+}
+
+// addReplacements adds and assigns a new unit of the same service for
+// each principal unit on the machine or its containers. Each replacement
+// is recorded as it is added, so that if the drain is interrupted, the
+// next attempt only assigns the replacements already added and adds
+// those still missing.
+func (d *MachineDrain) addReplacements() error {
+	machine, err := d.st.Machine(d.MachineId())
+	if errors.IsNotFound(err) {
+		return drainFailuref("machine %q has been removed", d.MachineId())
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	units, err := drainedUnits(d.st, machine)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(units) == 0 && len(d.doc.Replacements) == 0 {
+		return errors.Trace(d.SetStatus(MachineDrainCompleted, "no units to evacuate"))
+	}
+	policy := d.st.replacementAssignmentPolicy()
+	for _, unit := range units {
+		replacement, err := d.AddReplacementUnit(unit)
+		if err != nil {
+			return drainFailuref("cannot add replacement for unit %q: %v", unit.Name(), err)
+		}
+		if _, err := replacement.AssignedMachineId(); err == nil {
+			continue
+		} else if !errors.IsNotAssigned(err) {
+			return errors.Trace(err)
+		}
+		if err := d.st.AssignUnit(replacement, policy); err != nil {
+			return drainFailuref("cannot assign replacement unit %q: %v", replacement.Name(), err)
+		}
+		logger.Infof("unit %q will replace %q", replacement.Name(), unit.Name())
+	}
+	return errors.Trace(d.SetReplacing())
+}
+
+// replacementAssignmentPolicy returns the policy used to assign
+// replacement units. Environments that cannot place units on existing
+// machines always get new ones.
+func (st *State) replacementAssignmentPolicy() AssignmentPolicy {
+	if err := st.supportsUnitPlacement(); err != nil {
+		logger.Debugf("assigning replacement units to new machines: %v", err)
+		return AssignNew
+	}
+	return AssignCleanEmpty
+}
+
+// removeReplacedUnits destroys the original units once every
+// replacement unit has settled.
+func (d *MachineDrain) removeReplacedUnits(replacementTimeout time.Duration, now time.Time) error {
+	for _, r := range d.Replacements() {
+		unit, err := d.st.Unit(r.Replacement)
+		if errors.IsNotFound(err) {
+			return drainFailuref("replacement unit %q has been removed", r.Replacement)
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if unit.Life() != Alive {
+			return drainFailuref("replacement unit %q is not alive", r.Replacement)
+		}
+		agentStatus, err := unit.AgentStatus()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		workloadStatus, err := unit.Status()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if agentStatus.Status != StatusIdle || !workloadSettled(workloadStatus.Status) {
+			if now.Sub(r.Added) > replacementTimeout {
+				return drainFailuref(
+					"replacement unit %q did not settle within %v (agent %q, workload %q)",
+					r.Replacement, replacementTimeout, agentStatus.Status, workloadStatus.Status,
+				)
+			}
+			logger.Debugf(
+				"waiting for unit %q to settle (agent %q, workload %q)",
+				r.Replacement, agentStatus.Status, workloadStatus.Status,
+			)
+			return nil
+		}
+	}
+	for _, r := range d.Replacements() {
+		unit, err := d.st.Unit(r.Original)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if err := unit.Destroy(); err != nil {
+			return errors.Annotatef(err, "removing unit %q", r.Original)
+		}
+	}
+	return errors.Trace(d.SetStatus(MachineDrainRemoving, ""))
+}
+
+// workloadSettled reports whether a replacement unit's workload status
+// shows it ready to take over from the original unit. Charms need not
+// set an active status, so any status is accepted unless it reports a
+// problem or work still in progress.
+func workloadSettled(status Status) bool {
+	switch status {
+	case StatusError, StatusBlocked, StatusMaintenance, StatusWaiting:
+		return false
+	}
+	return true
+}
+
+// checkRemoved completes the drain once all the original units have
+// been removed from state.
+func (d *MachineDrain) checkRemoved() error {
+	for _, r := range d.Replacements() {
+		_, err := d.st.Unit(r.Original)
+		if err == nil {
+			logger.Debugf("waiting for unit %q to be removed", r.Original)
+			return nil
+		} else if !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(d.SetStatus(MachineDrainCompleted, ""))
+}
+
+// drainedUnits returns the alive principal units on the machine and on
+// any containers within it.
+func drainedUnits(st *State, machine *Machine) ([]*Unit, error) {
+	units, err := machine.Units()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []*Unit
+	for _, unit := range units {
+		if unit.IsPrincipal() && unit.Life() == Alive {
+			result = append(result, unit)
+		}
+	}
+	containers, err := machine.Containers()
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+	for _, id := range containers {
+		container, err := st.Machine(id)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		containerUnits, err := drainedUnits(st, container)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result = append(result, containerUnits...)
+	}
+	return result, nil
+}
@@ -0,0 +1,377 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+type MachineDrainSuite struct {
+	ConnSuite
+	machine   *state.Machine
+	wordpress *state.Service
+}
+
+var _ = gc.Suite(&MachineDrainSuite{})
+
+func (s *MachineDrainSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	var err error
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.wordpress = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+func (s *MachineDrainSuite) TestDrain(c *gc.C) {
+	c.Assert(s.machine.Unschedulable(), jc.IsFalse)
+	drain, err := s.machine.Drain()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(drain.MachineId(), gc.Equals, s.machine.Id())
+	c.Assert(drain.Status(), gc.Equals, state.MachineDrainPending)
+	c.Assert(drain.Replacements(), gc.HasLen, 0)
+	c.Assert(s.machine.Unschedulable(), jc.IsTrue)
+
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.Unschedulable(), jc.IsTrue)
+
+	drains, err := s.State.ActiveMachineDrains()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(drains, gc.HasLen, 1)
+	c.Assert(drains[0].MachineId(), gc.Equals, s.machine.Id())
+}
+
+func (s *MachineDrainSuite) TestDrainTwice(c *gc.C) {
+	_, err := s.machine.Drain()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.machine.Drain()
+	c.Assert(err, gc.ErrorMatches, `cannot drain machine "0": drain for machine "0" already exists`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *MachineDrainSuite) TestDrainAfterCompletion(c *gc.C) {
+	drain, err := s.machine.Drain()
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = drain.AddReplacementUnit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = drain.SetStatus(state.MachineDrainCompleted, "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	drain, err = s.machine.Drain()
+	c.Assert(err, jc.ErrorIsNil)
+	err = drain.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(drain.Status(), gc.Equals, state.MachineDrainPending)
+	c.Assert(drain.Replacements(), gc.HasLen, 0)
+}
+
+func (s *MachineDrainSuite) TestAddReplacementUnit(c *gc.C) {
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	drain, err := s.machine.Drain()
+	c.Assert(err, jc.ErrorIsNil)
+
+	replacement, err := drain.AddReplacementUnit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(replacement.Name(), gc.Equals, "wordpress/1")
+	replacements := drain.Replacements()
+	c.Assert(replacements, gc.HasLen, 1)
+	c.Assert(replacements[0].Original, gc.Equals, "wordpress/0")
+	c.Assert(replacements[0].Replacement, gc.Equals, "wordpress/1")
+	c.Assert(replacements[0].Added.IsZero(), jc.IsFalse)
+
+	// Adding a replacement again returns the existing one.
+	replacement, err = drain.AddReplacementUnit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(replacement.Name(), gc.Equals, "wordpress/1")
+	units, err := s.wordpress.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 2)
+}
+
+func (s *MachineDrainSuite) TestSetReplacingRequiresPending(c *gc.C) {
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	drain, err := s.machine.Drain()
+	c.Assert(err, jc.ErrorIsNil)
+	err = drain.SetReplacing()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(drain.Status(), gc.Equals, state.MachineDrainReplacing)
+
+	err = drain.SetReplacing()
+	c.Assert(err, gc.ErrorMatches, `cannot set machine "0" drain replacing: drain is not pending`)
+	_, err = drain.AddReplacementUnit(unit)
+	c.Assert(err, gc.ErrorMatches, `cannot add replacement for unit "wordpress/0": drain is not pending`)
+}
+
+func (s *MachineDrainSuite) TestSetStatusWhenDone(c *gc.C) {
+	drain, err := s.machine.Drain()
+	c.Assert(err, jc.ErrorIsNil)
+	err = drain.SetStatus(state.MachineDrainFailed, "oops")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(drain.Message(), gc.Equals, "oops")
+	err = drain.SetStatus(state.MachineDrainRemoving, "")
+	c.Assert(err, gc.ErrorMatches, `cannot set status of machine "0" drain: drain is already done`)
+
+	drains, err := s.State.ActiveMachineDrains()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(drains, gc.HasLen, 0)
+}
+
+func (s *MachineDrainSuite) TestUndrainCancelsDrain(c *gc.C) {
+	_, err := s.machine.Drain()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Undrain()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.Unschedulable(), jc.IsFalse)
+
+	drain, err := s.State.MachineDrain(s.machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(drain.Status(), gc.Equals, state.MachineDrainFailed)
+	c.Assert(drain.Message(), gc.Equals, "drain cancelled")
+
+	// Undraining again is a no-op.
+	err = s.machine.Undrain()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MachineDrainSuite) TestMachineDrainNotFound(c *gc.C) {
+	_, err := s.State.MachineDrain(s.machine.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *MachineDrainSuite) TestAssignToUnschedulableMachine(c *gc.C) {
+	_, err := s.machine.Drain()
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = unit.AssignToMachine(s.machine)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/0" to machine 0: machine is unschedulable`)
+
+	placement := &instance.Placement{Scope: instance.MachineScope, Directive: s.machine.Id()}
+	err = s.State.AssignUnitWithPlacement(unit, placement, nil)
+	c.Assert(err, gc.ErrorMatches, `machine "0" is unschedulable`)
+}
+
+func (s *MachineDrainSuite) TestAssignCleanSkipsUnschedulableMachine(c *gc.C) {
+	_, err := s.machine.Drain()
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Not(gc.Equals), s.machine.Id())
+
+	err = s.machine.Undrain()
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err = s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err = unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Equals, s.machine.Id())
+}
+
+const replacementTimeout = 30 * time.Minute
+
+func (s *MachineDrainSuite) advanceDrains(c *gc.C, now time.Time) {
+	err := state.AdvanceMachineDrains(s.State, replacementTimeout, now)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MachineDrainSuite) assertDrainStatus(c *gc.C, expect state.MachineDrainStatus) *state.MachineDrain {
+	drain, err := s.State.MachineDrain(s.machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(drain.Status(), gc.Equals, expect, gc.Commentf("message: %q", drain.Message()))
+	return drain
+}
+
+func (s *MachineDrainSuite) addAssignedUnit(c *gc.C) *state.Unit {
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+	return unit
+}
+
+func (s *MachineDrainSuite) TestAdvanceDrainEvacuatesUnits(c *gc.C) {
+	unit := s.addAssignedUnit(c)
+	_, err := s.machine.Drain()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The first step adds a replacement unit on another machine.
+	s.advanceDrains(c, time.Now())
+	drain := s.assertDrainStatus(c, state.MachineDrainReplacing)
+	replacements := drain.Replacements()
+	c.Assert(replacements, gc.HasLen, 1)
+	c.Assert(replacements[0].Original, gc.Equals, "wordpress/0")
+	c.Assert(replacements[0].Replacement, gc.Equals, "wordpress/1")
+	replacement, err := s.State.Unit("wordpress/1")
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := replacement.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Not(gc.Equals), s.machine.Id())
+
+	// Nothing happens until the replacement has settled.
+	s.advanceDrains(c, time.Now())
+	s.assertDrainStatus(c, state.MachineDrainReplacing)
+	err = replacement.SetAgentStatus(state.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = replacement.SetStatus(state.StatusMaintenance, "installing", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.advanceDrains(c, time.Now())
+	s.assertDrainStatus(c, state.MachineDrainReplacing)
+
+	// A charm that never reports an active status still settles.
+	err = replacement.SetStatus(state.StatusUnknown, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Then the original unit is destroyed.
+	s.advanceDrains(c, time.Now())
+	s.assertDrainStatus(c, state.MachineDrainRemoving)
+	err = unit.Refresh()
+	if err == nil {
+		c.Assert(unit.Life(), gc.Not(gc.Equals), state.Alive)
+		err = unit.EnsureDead()
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.Remove()
+		c.Assert(err, jc.ErrorIsNil)
+	} else {
+		c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	}
+
+	// And the drain completes once it has gone.
+	s.advanceDrains(c, time.Now())
+	s.assertDrainStatus(c, state.MachineDrainCompleted)
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.Unschedulable(), jc.IsTrue)
+}
+
+func (s *MachineDrainSuite) TestAdvanceDrainEmptyMachine(c *gc.C) {
+	_, err := s.machine.Drain()
+	c.Assert(err, jc.ErrorIsNil)
+	s.advanceDrains(c, time.Now())
+	drain := s.assertDrainStatus(c, state.MachineDrainCompleted)
+	c.Assert(drain.Message(), gc.Equals, "no units to evacuate")
+}
+
+func (s *MachineDrainSuite) TestAdvanceDrainReplacementRemoved(c *gc.C) {
+	unit := s.addAssignedUnit(c)
+	_, err := s.machine.Drain()
+	c.Assert(err, jc.ErrorIsNil)
+	s.advanceDrains(c, time.Now())
+
+	replacement, err := s.State.Unit("wordpress/1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = replacement.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.advanceDrains(c, time.Now())
+	drain := s.assertDrainStatus(c, state.MachineDrainFailed)
+	c.Assert(drain.Message(), gc.Matches, `replacement unit "wordpress/1" (has been removed|is not alive)`)
+
+	// The original unit is left in place.
+	err = unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit.Life(), gc.Equals, state.Alive)
+}
+
+func (s *MachineDrainSuite) TestAdvanceDrainResumesAddingReplacements(c *gc.C) {
+	// Simulate a drain having been interrupted after adding a
+	// replacement for the first of two units.
+	unit := s.addAssignedUnit(c)
+	s.addAssignedUnit(c)
+	drain, err := s.machine.Drain()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = drain.AddReplacementUnit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.advanceDrains(c, time.Now())
+	drain = s.assertDrainStatus(c, state.MachineDrainReplacing)
+	replacements := drain.Replacements()
+	c.Assert(replacements, gc.HasLen, 2)
+	c.Assert(replacements[0].Original, gc.Equals, "wordpress/0")
+	c.Assert(replacements[0].Replacement, gc.Equals, "wordpress/2")
+	c.Assert(replacements[1].Original, gc.Equals, "wordpress/1")
+	c.Assert(replacements[1].Replacement, gc.Equals, "wordpress/3")
+
+	// The replacement added before the interruption has been assigned,
+	// and no other units were added.
+	for _, r := range replacements {
+		replacement, err := s.State.Unit(r.Replacement)
+		c.Assert(err, jc.ErrorIsNil)
+		machineId, err := replacement.AssignedMachineId()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(machineId, gc.Not(gc.Equals), s.machine.Id())
+	}
+	units, err := s.wordpress.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 4)
+}
+
+func (s *MachineDrainSuite) TestAdvanceDrainReplacementTimeout(c *gc.C) {
+	unit := s.addAssignedUnit(c)
+	_, err := s.machine.Drain()
+	c.Assert(err, jc.ErrorIsNil)
+	s.advanceDrains(c, time.Now())
+	s.assertDrainStatus(c, state.MachineDrainReplacing)
+
+	replacement, err := s.State.Unit("wordpress/1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = replacement.SetAgentStatus(state.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = replacement.SetStatus(state.StatusBlocked, "need a database", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.advanceDrains(c, time.Now().Add(replacementTimeout+time.Minute))
+	drain := s.assertDrainStatus(c, state.MachineDrainFailed)
+	c.Assert(drain.Message(), gc.Equals,
+		`replacement unit "wordpress/1" did not settle within 30m0s (agent "idle", workload "blocked")`)
+
+	// The original unit is left in place.
+	err = unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit.Life(), gc.Equals, state.Alive)
+}
+
+func (s *MachineDrainSuite) TestAdvanceDrainWithoutUnitPlacement(c *gc.C) {
+	s.addAssignedUnit(c)
+	empty, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.machine.Drain()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The clean, empty machine is not used, as the environment
+	// cannot place units on existing machines.
+	s.policy.GetEnvironCapability = func(*config.Config) (state.EnvironCapability, error) {
+		return &mockEnvironCapability{
+			supportsUnitPlacementError: fmt.Errorf("no unit placement for you"),
+		}, nil
+	}
+	s.advanceDrains(c, time.Now())
+	s.assertDrainStatus(c, state.MachineDrainReplacing)
+	replacement, err := s.State.Unit("wordpress/1")
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := replacement.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Not(gc.Equals), s.machine.Id())
+	c.Assert(machineId, gc.Not(gc.Equals), empty.Id())
+}
@@ -77,6 +77,11 @@ type meterStatusDoc struct {
 	EnvUUID string `bson:"env-uuid"`
 	Code    string `bson:"code"`
 	Info    string `bson:"info"`
+
+	// ThresholdCode and ThresholdInfo hold the meter status derived
+	// from the metric alerts active for the unit, if any.
+	ThresholdCode string `bson:"threshold-code,omitempty"`
+	ThresholdInfo string `bson:"threshold-info,omitempty"`
 }
 
 // SetMeterStatus sets the meter status for the unit.
@@ -124,6 +129,50 @@ func (u *Unit) SetMeterStatus(codeStr, info string) error {
 	return errors.Annotatef(u.st.run(buildTxn), "cannot set meter state for unit %s", u.Name())
 }
 
+// setThresholdMeterStatus records the meter status derived from the
+// unit's active metric alerts, or clears it if status is nil.
+func (u *Unit) setThresholdMeterStatus(status *MeterStatus) error {
+	var code, info string
+	if status != nil {
+		code, info = status.Code.String(), status.Info
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := u.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		meterDoc, err := u.getMeterStatusDoc()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if meterDoc.ThresholdCode == code && meterDoc.ThresholdInfo == info {
+			return nil, jujutxn.ErrNoOperations
+		}
+		update := bson.D{{"$set", bson.D{
+			{"threshold-code", code},
+			{"threshold-info", info},
+		}}}
+		if status == nil {
+			update = bson.D{{"$unset", bson.D{
+				{"threshold-code", nil},
+				{"threshold-info", nil},
+			}}}
+		}
+		return []txn.Op{{
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: isAliveDoc,
+		}, {
+			C:      meterStatusC,
+			Id:     u.st.docID(u.globalMeterStatusKey()),
+			Assert: txn.DocExists,
+			Update: update,
+		}}, nil
+	}
+	return errors.Annotatef(u.st.run(buildTxn), "cannot set threshold meter status for unit %s", u.Name())
+}
+
 // createMeterStatusOp returns the operation needed to create the meter status
 // document associated with the given globalKey.
 func createMeterStatusOp(st *State, globalKey string, doc *meterStatusDoc) txn.Op {
@@ -166,6 +215,12 @@ func (u *Unit) GetMeterStatus() (MeterStatus, error) {
 	code := MeterStatusFromString(status.Code)
 
 	unitMeterStatus := MeterStatus{code, status.Info}
+	if status.ThresholdCode != "" {
+		// A metric threshold breached by the unit overrides any
+		// less severe status set by the collector.
+		thresholdStatus := MeterStatus{MeterStatusFromString(status.ThresholdCode), status.ThresholdInfo}
+		unitMeterStatus = combineMeterStatus(unitMeterStatus, thresholdStatus)
+	}
 	return combineMeterStatus(mmStatus, unitMeterStatus), nil
 }
 
@@ -5,6 +5,7 @@ package state
 
 import (
 	"encoding/json"
+	"regexp"
 	"time"
 
 	"github.com/juju/errors"
@@ -171,6 +172,49 @@ func (st *State) MetricBatches() ([]MetricBatch, error) {
 	return results, nil
 }
 
+// MetricBatchesForUnit returns the metric batches stored for the
+// given unit that were created at or after the given time.
+func (st *State) MetricBatchesForUnit(unit string, since time.Time) ([]MetricBatch, error) {
+	if _, err := st.Unit(unit); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return st.metricBatches(bson.M{"unit": unit}, since)
+}
+
+// MetricBatchesForService returns the metric batches stored for all
+// units of the given service that were created at or after the given
+// time. Batches from units that have since been removed are included.
+func (st *State) MetricBatchesForService(service string, since time.Time) ([]MetricBatch, error) {
+	if _, err := st.Service(service); err != nil {
+		return nil, errors.Trace(err)
+	}
+	unitPattern := "^" + regexp.QuoteMeta(service) + "/[0-9]+$"
+	return st.metricBatches(bson.M{"unit": bson.M{"$regex": unitPattern}}, since)
+}
+
+// metricBatches returns the metric batches in the current environment
+// that match the given selector and were created at or after the given
+// time, ordered by creation time.
+func (st *State) metricBatches(sel bson.M, since time.Time) ([]MetricBatch, error) {
+	c, closer := st.getCollection(metricsC)
+	defer closer()
+	// The metrics collection is global, so restrict
+	// the query to the current environment.
+	sel["env-uuid"] = st.EnvironUUID()
+	if !since.IsZero() {
+		sel["created"] = bson.M{"$gte": since}
+	}
+	var docs []metricBatchDoc
+	if err := c.Find(sel).Sort("created").All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]MetricBatch, len(docs))
+	for i, doc := range docs {
+		results[i] = MetricBatch{st: st, doc: doc}
+	}
+	return results, nil
+}
+
 // MetricBatch returns the metric batch with the given id.
 func (st *State) MetricBatch(id string) (*MetricBatch, error) {
 	c, closer := st.getCollection(metricsC)
@@ -295,6 +295,67 @@ func (s *MetricSuite) TestMetricBatchesCustomCharmURLAndUUID(c *gc.C) {
 	c.Assert(metricBatches[0].Metrics(), gc.HasLen, 1)
 }
 
+func (s *MetricSuite) addMetricBatch(c *gc.C, unit *state.Unit, created time.Time, metrics ...state.Metric) *state.MetricBatch {
+	batch, err := s.State.AddMetrics(
+		state.BatchParam{
+			UUID:     utils.MustNewUUID().String(),
+			Created:  created,
+			CharmURL: s.meteredCharm.URL().String(),
+			Metrics:  metrics,
+			Unit:     unit.UnitTag(),
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	return batch
+}
+
+func (s *MetricSuite) TestMetricBatchesForUnit(c *gc.C) {
+	now := state.NowToTheSecond()
+	old := now.Add(-time.Hour)
+	unit2 := s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service, SetCharmURL: true})
+	s.addMetricBatch(c, s.unit, old, state.Metric{"pings", "1", old})
+	recent := s.addMetricBatch(c, s.unit, now, state.Metric{"pings", "2", now})
+	s.addMetricBatch(c, unit2, now, state.Metric{"pings", "3", now})
+
+	batches, err := s.State.MetricBatchesForUnit("metered/0", time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(batches, gc.HasLen, 2)
+	c.Assert(batches[0].Created().Equal(old), jc.IsTrue)
+	c.Assert(batches[1].UUID(), gc.Equals, recent.UUID())
+
+	batches, err = s.State.MetricBatchesForUnit("metered/0", now.Add(-time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(batches, gc.HasLen, 1)
+	c.Assert(batches[0].UUID(), gc.Equals, recent.UUID())
+}
+
+func (s *MetricSuite) TestMetricBatchesForUnitNotFound(c *gc.C) {
+	_, err := s.State.MetricBatchesForUnit("metered/42", time.Time{})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *MetricSuite) TestMetricBatchesForService(c *gc.C) {
+	now := state.NowToTheSecond()
+	unit2 := s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service, SetCharmURL: true})
+	s.addMetricBatch(c, s.unit, now, state.Metric{"pings", "1", now})
+	s.addMetricBatch(c, unit2, now, state.Metric{"pings", "2", now})
+
+	// Batches from another service with a common name prefix
+	// are not included.
+	otherService := s.Factory.MakeService(c, &factory.ServiceParams{Name: "metered-too", Charm: s.meteredCharm})
+	otherUnit := s.Factory.MakeUnit(c, &factory.UnitParams{Service: otherService, SetCharmURL: true})
+	s.addMetricBatch(c, otherUnit, now, state.Metric{"pings", "3", now})
+
+	batches, err := s.State.MetricBatchesForService("metered", time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(batches, gc.HasLen, 2)
+	units := []string{batches[0].Unit(), batches[1].Unit()}
+	c.Assert(units, jc.SameContents, []string{"metered/0", "metered/1"})
+
+	_, err = s.State.MetricBatchesForService("unknown", time.Time{})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *MetricSuite) TestMetricCredentials(c *gc.C) {
 	now := state.NowToTheSecond()
 	m := state.Metric{"pings", "5", now}
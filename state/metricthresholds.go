@@ -0,0 +1,658 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// Operators that may be used to compare metric values against
+// a threshold.
+const (
+	ThresholdGreaterThan        = ">"
+	ThresholdGreaterThanOrEqual = ">="
+	ThresholdLessThan           = "<"
+	ThresholdLessThanOrEqual    = "<="
+)
+
+// MetricThresholdParams contains the parameters used to define
+// a metric threshold.
+type MetricThresholdParams struct {
+	// Service is the name of the service whose units' metrics
+	// are checked against the threshold.
+	Service string
+
+	// Key is the metric key to check.
+	Key string
+
+	// Operator is one of the threshold comparison operators.
+	Operator string
+
+	// Value is the value metrics are compared against.
+	Value float64
+
+	// Duration is the period for which the metric must breach the
+	// threshold before an alert is raised. A zero duration raises
+	// an alert as soon as the most recent value breaches.
+	Duration time.Duration
+
+	// Status is the meter status, AMBER or RED, set on units
+	// that breach the threshold.
+	Status string
+
+	// Info is the meter status information set on units that
+	// breach the threshold.
+	Info string
+}
+
+// MetricThreshold is a locally defined rule that sets the meter
+// status of units whose metrics breach it.
+type MetricThreshold struct {
+	doc metricThresholdDoc
+}
+
+type metricThresholdDoc struct {
+	DocID    string        `bson:"_id"`
+	Id       string        `bson:"id"`
+	EnvUUID  string        `bson:"env-uuid"`
+	Service  string        `bson:"service"`
+	Key      string        `bson:"key"`
+	Operator string        `bson:"operator"`
+	Value    float64       `bson:"value"`
+	Duration time.Duration `bson:"duration"`
+	Status   string        `bson:"status"`
+	Info     string        `bson:"info"`
+}
+
+// Id returns the ID of the threshold.
+func (t *MetricThreshold) Id() string {
+	return t.doc.Id
+}
+
+// Service returns the name of the service the threshold applies to.
+func (t *MetricThreshold) Service() string {
+	return t.doc.Service
+}
+
+// Key returns the metric key checked by the threshold.
+func (t *MetricThreshold) Key() string {
+	return t.doc.Key
+}
+
+// Operator returns the operator used to compare metric values
+// against the threshold.
+func (t *MetricThreshold) Operator() string {
+	return t.doc.Operator
+}
+
+// Value returns the threshold value.
+func (t *MetricThreshold) Value() float64 {
+	return t.doc.Value
+}
+
+// Duration returns the period for which metrics must breach the
+// threshold before an alert is raised.
+func (t *MetricThreshold) Duration() time.Duration {
+	return t.doc.Duration
+}
+
+// Status returns the meter status set on units breaching the threshold.
+func (t *MetricThreshold) Status() string {
+	return t.doc.Status
+}
+
+// Info returns the meter status information set on units breaching
+// the threshold.
+func (t *MetricThreshold) Info() string {
+	return t.doc.Info
+}
+
+// String returns a human readable description of the threshold.
+func (t *MetricThreshold) String() string {
+	s := fmt.Sprintf("%s %s %s %v", t.doc.Service, t.doc.Key, t.doc.Operator, t.doc.Value)
+	if t.doc.Duration > 0 {
+		s += fmt.Sprintf(" for %v", t.doc.Duration)
+	}
+	return s
+}
+
+// breached reports whether the given metric value breaches
+// the threshold.
+func (t *MetricThreshold) breached(value float64) bool {
+	switch t.doc.Operator {
+	case ThresholdGreaterThan:
+		return value > t.doc.Value
+	case ThresholdGreaterThanOrEqual:
+		return value >= t.doc.Value
+	case ThresholdLessThan:
+		return value < t.doc.Value
+	case ThresholdLessThanOrEqual:
+		return value <= t.doc.Value
+	}
+	return false
+}
+
+func validateMetricThresholdParams(st *State, p MetricThresholdParams) error {
+	switch p.Operator {
+	case ThresholdGreaterThan, ThresholdGreaterThanOrEqual, ThresholdLessThan, ThresholdLessThanOrEqual:
+	default:
+		return errors.NotValidf("operator %q", p.Operator)
+	}
+	switch MeterStatusFromString(p.Status) {
+	case MeterAmber, MeterRed:
+	default:
+		return errors.NotValidf("meter status %q", p.Status)
+	}
+	if p.Duration < 0 {
+		return errors.NotValidf("negative duration")
+	}
+	service, err := st.Service(p.Service)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ch, _, err := service.Charm()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	metrics := ch.Metrics()
+	if metrics == nil {
+		return errors.Errorf("charm %q does not declare metrics", ch.URL())
+	}
+	if _, ok := metrics.Metrics[p.Key]; !ok {
+		return errors.NotFoundf("metric %q in charm %q", p.Key, ch.URL())
+	}
+	return nil
+}
+
+// AddMetricThreshold adds a metric threshold to the environment.
+func (st *State) AddMetricThreshold(p MetricThresholdParams) (_ *MetricThreshold, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add metric threshold")
+	if err := validateMetricThresholdParams(st, p); err != nil {
+		return nil, errors.Trace(err)
+	}
+	seq, err := st.sequence("metricthreshold")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	id := strconv.Itoa(seq)
+	doc := metricThresholdDoc{
+		DocID:    st.docID(id),
+		Id:       id,
+		EnvUUID:  st.EnvironUUID(),
+		Service:  p.Service,
+		Key:      p.Key,
+		Operator: p.Operator,
+		Value:    p.Value,
+		Duration: p.Duration,
+		Status:   MeterStatusFromString(p.Status).String(),
+		Info:     p.Info,
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     p.Service,
+		Assert: isAliveDoc,
+	}, {
+		C:      metricThresholdsC,
+		Id:     id,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return nil, errors.Errorf("service %q is not alive", p.Service)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &MetricThreshold{doc}, nil
+}
+
+// MetricThreshold returns the metric threshold with the given ID.
+func (st *State) MetricThreshold(id string) (*MetricThreshold, error) {
+	coll, closer := st.getCollection(metricThresholdsC)
+	defer closer()
+	var doc metricThresholdDoc
+	err := coll.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("metric threshold %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get metric threshold %q", id)
+	}
+	return &MetricThreshold{doc}, nil
+}
+
+// MetricThresholds returns all metric thresholds in the environment.
+func (st *State) MetricThresholds() ([]*MetricThreshold, error) {
+	coll, closer := st.getCollection(metricThresholdsC)
+	defer closer()
+	var docs []metricThresholdDoc
+	if err := coll.Find(nil).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get metric thresholds")
+	}
+	thresholds := make([]*MetricThreshold, len(docs))
+	for i, doc := range docs {
+		thresholds[i] = &MetricThreshold{doc}
+	}
+	return thresholds, nil
+}
+
+// RemoveMetricThreshold removes the metric threshold with the given
+// ID. Any alerts raised by the threshold that are still active are
+// resolved the next time thresholds are evaluated.
+func (st *State) RemoveMetricThreshold(id string) error {
+	ops := []txn.Op{{
+		C:      metricThresholdsC,
+		Id:     id,
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("metric threshold %q", id)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot remove metric threshold %q", id)
+	}
+	return nil
+}
+
+// MetricAlert records a unit breaching a metric threshold.
+type MetricAlert struct {
+	doc metricAlertDoc
+}
+
+type metricAlertDoc struct {
+	DocID     string    `bson:"_id"`
+	Id        string    `bson:"id"`
+	EnvUUID   string    `bson:"env-uuid"`
+	Threshold string    `bson:"threshold"`
+	Unit      string    `bson:"unit"`
+	Key       string    `bson:"key"`
+	Value     string    `bson:"value"`
+	Status    string    `bson:"status"`
+	Info      string    `bson:"info"`
+	Raised    time.Time `bson:"raised"`
+	Active    bool      `bson:"active"`
+	Resolved  time.Time `bson:"resolved,omitempty"`
+}
+
+// Id returns the ID of the alert.
+func (a *MetricAlert) Id() string {
+	return a.doc.Id
+}
+
+// Threshold returns the ID of the threshold that raised the alert.
+func (a *MetricAlert) Threshold() string {
+	return a.doc.Threshold
+}
+
+// Unit returns the tag of the unit that breached the threshold.
+func (a *MetricAlert) Unit() names.UnitTag {
+	return names.NewUnitTag(a.doc.Unit)
+}
+
+// Key returns the metric key that breached the threshold.
+func (a *MetricAlert) Key() string {
+	return a.doc.Key
+}
+
+// Value returns the metric value that raised the alert.
+func (a *MetricAlert) Value() string {
+	return a.doc.Value
+}
+
+// Status returns the meter status set by the alert.
+func (a *MetricAlert) Status() string {
+	return a.doc.Status
+}
+
+// Info returns the meter status information set by the alert.
+func (a *MetricAlert) Info() string {
+	return a.doc.Info
+}
+
+// Raised returns the time the alert was raised.
+func (a *MetricAlert) Raised() time.Time {
+	return a.doc.Raised
+}
+
+// Resolved returns the time the alert was resolved, and true; or
+// false if the alert is still active.
+func (a *MetricAlert) Resolved() (time.Time, bool) {
+	return a.doc.Resolved, !a.doc.Active
+}
+
+// MetricAlerts returns the metric alerts in the environment that were
+// raised at or after the given time, ordered by the time they were
+// raised. Active alerts are always returned.
+func (st *State) MetricAlerts(since time.Time) ([]*MetricAlert, error) {
+	coll, closer := st.getCollection(metricAlertsC)
+	defer closer()
+	sel := bson.D{{"$or", []bson.D{
+		{{"active", true}},
+		{{"raised", bson.D{{"$gte", since}}}},
+	}}}
+	var docs []metricAlertDoc
+	if err := coll.Find(sel).Sort("raised").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get metric alerts")
+	}
+	alerts := make([]*MetricAlert, len(docs))
+	for i, doc := range docs {
+		alerts[i] = &MetricAlert{doc}
+	}
+	return alerts, nil
+}
+
+// activeMetricAlerts returns the active alerts in the environment.
+func (st *State) activeMetricAlerts() ([]metricAlertDoc, error) {
+	coll, closer := st.getCollection(metricAlertsC)
+	defer closer()
+	var docs []metricAlertDoc
+	if err := coll.Find(bson.D{{"active", true}}).All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return docs, nil
+}
+
+// EvaluateMetricThresholds checks the metrics recorded by units against
+// the thresholds defined in the environment, as of the given time.
+//
+// A unit breaches a threshold when the values it has recorded for the
+// threshold's metric have breached the threshold continuously for the
+// threshold's duration; a zero duration considers only the most recent
+// value. An alert is raised for each newly breached threshold, and
+// resolved once the unit no longer breaches it. Resolved alerts are
+// removed after a week. The threshold meter status of every unit whose
+// alerts changed is set to that of its most severe active alert, or
+// cleared if none remain active; this causes the meter-status-changed
+// hook to run.
+func (st *State) EvaluateMetricThresholds(now time.Time) error {
+	thresholds, err := st.MetricThresholds()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	active, err := st.activeMetricAlerts()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Index the active alerts by threshold and unit.
+	activeAlerts := make(map[string]map[string]metricAlertDoc)
+	for _, doc := range active {
+		if activeAlerts[doc.Threshold] == nil {
+			activeAlerts[doc.Threshold] = make(map[string]metricAlertDoc)
+		}
+		activeAlerts[doc.Threshold][doc.Unit] = doc
+	}
+
+	var ops []txn.Op
+	changedUnits := make(map[string]bool)
+	for _, t := range thresholds {
+		alerts := activeAlerts[t.Id()]
+		delete(activeAlerts, t.Id())
+		breaching, err := st.unitsBreachingThreshold(t, now)
+		if err != nil {
+			return errors.Annotatef(err, "cannot evaluate metric threshold %q", t.Id())
+		}
+		for unit, value := range breaching {
+			if _, ok := alerts[unit]; ok {
+				continue
+			}
+			op, err := st.raiseMetricAlertOp(t, unit, value, now)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			ops = append(ops, op)
+			changedUnits[unit] = true
+		}
+		for unit, doc := range alerts {
+			if _, ok := breaching[unit]; ok {
+				continue
+			}
+			ops = append(ops, resolveMetricAlertOp(doc.Id, now))
+			changedUnits[unit] = true
+		}
+	}
+	// Resolve any alerts raised by thresholds that have since
+	// been removed.
+	for _, alerts := range activeAlerts {
+		for unit, doc := range alerts {
+			ops = append(ops, resolveMetricAlertOp(doc.Id, now))
+			changedUnits[unit] = true
+		}
+	}
+	pruneOps, err := st.pruneMetricAlertsOps(now)
+	if err != nil {
+		return errors.Annotate(err, "cannot prune metric alerts")
+	}
+	ops = append(ops, pruneOps...)
+	if len(ops) == 0 {
+		return nil
+	}
+	if err := st.runTransaction(ops); err != nil {
+		return errors.Annotate(err, "cannot update metric alerts")
+	}
+	for unitName := range changedUnits {
+		if err := st.updateMeterStatusFromAlerts(unitName); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// unitsBreachingThreshold returns the values that caused each unit
+// of the threshold's service to breach the threshold, keyed by unit
+// name. A unit breaches the threshold only if it has been breaching it
+// continuously for the threshold's duration: its most recent value
+// recorded at or before now-Duration, and every value recorded since,
+// must breach the threshold.
+func (st *State) unitsBreachingThreshold(t *MetricThreshold, now time.Time) (map[string]string, error) {
+	since := now.Add(-t.Duration())
+	service, err := st.Service(t.Service())
+	if errors.IsNotFound(err) {
+		// The service has been removed; nothing can breach.
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	units, err := service.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	batches, err := st.thresholdMetricBatches(t, units, since)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Gather the values recorded for the threshold's
+	// metric by each unit.
+	values := make(map[string][]Metric)
+	for _, batch := range batches {
+		for _, m := range batch.Metrics() {
+			if m.Key != t.Key() || m.Time.After(now) {
+				continue
+			}
+			values[batch.Unit()] = append(values[batch.Unit()], m)
+		}
+	}
+	breaching := make(map[string]string)
+	for unit, metrics := range values {
+		sort.Sort(metricsByTime(metrics))
+		// Walk back from the most recent value until one is found
+		// that does not breach the threshold, or one that breaches
+		// it and was recorded at or before the start of the window.
+		breached := false
+		for i := len(metrics) - 1; i >= 0; i-- {
+			v, err := strconv.ParseFloat(metrics[i].Value, 64)
+			if err != nil || !t.breached(v) {
+				break
+			}
+			if !metrics[i].Time.After(since) {
+				breached = true
+				break
+			}
+		}
+		if breached {
+			breaching[unit] = metrics[len(metrics)-1].Value
+		}
+	}
+	return breaching, nil
+}
+
+// thresholdMetricBatches returns the batches recording the threshold's
+// metric for the given units that can affect whether they breach the
+// threshold: those created at or after the start of the window, and
+// for each unit the most recent one created before it, which holds the
+// value in effect at the start of the window. Older batches are never
+// loaded.
+func (st *State) thresholdMetricBatches(t *MetricThreshold, units []*Unit, since time.Time) ([]MetricBatch, error) {
+	if len(units) == 0 {
+		return nil, nil
+	}
+	unitNames := make([]string, len(units))
+	for i, unit := range units {
+		unitNames[i] = unit.Name()
+	}
+	batches, err := st.metricBatches(bson.M{
+		"unit":        bson.M{"$in": unitNames},
+		"metrics.key": t.Key(),
+	}, since)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	c, closer := st.getCollection(metricsC)
+	defer closer()
+	for _, unitName := range unitNames {
+		var doc metricBatchDoc
+		err := c.Find(bson.M{
+			"env-uuid":    st.EnvironUUID(),
+			"unit":        unitName,
+			"metrics.key": t.Key(),
+			"created":     bson.M{"$lt": since},
+		}).Sort("-created").One(&doc)
+		if err == mgo.ErrNotFound {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		batches = append(batches, MetricBatch{st: st, doc: doc})
+	}
+	return batches, nil
+}
+
+// metricsByTime sorts metrics by the time they were recorded.
+type metricsByTime []Metric
+
+func (m metricsByTime) Len() int           { return len(m) }
+func (m metricsByTime) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+func (m metricsByTime) Less(i, j int) bool { return m[i].Time.Before(m[j].Time) }
+
+func (st *State) raiseMetricAlertOp(t *MetricThreshold, unit, value string, now time.Time) (txn.Op, error) {
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return txn.Op{}, errors.Trace(err)
+	}
+	id := uuid.String()
+	info := t.Info()
+	if info == "" {
+		info = t.String()
+	}
+	return txn.Op{
+		C:      metricAlertsC,
+		Id:     id,
+		Assert: txn.DocMissing,
+		Insert: &metricAlertDoc{
+			DocID:     st.docID(id),
+			Id:        id,
+			EnvUUID:   st.EnvironUUID(),
+			Threshold: t.Id(),
+			Unit:      unit,
+			Key:       t.Key(),
+			Value:     value,
+			Status:    t.Status(),
+			Info:      info,
+			Raised:    now,
+			Active:    true,
+		},
+	}, nil
+}
+
+func resolveMetricAlertOp(id string, now time.Time) txn.Op {
+	return txn.Op{
+		C:      metricAlertsC,
+		Id:     id,
+		Assert: bson.D{{"active", true}},
+		Update: bson.D{{"$set", bson.D{
+			{"active", false},
+			{"resolved", now},
+		}}},
+	}
+}
+
+// updateMeterStatusFromAlerts records the meter status of the unit's
+// most severe active alert as its threshold meter status, or clears it
+// if the unit has no active alerts. The threshold meter status is kept
+// apart from the status set by the collector, and the two are combined
+// by GetMeterStatus.
+func (st *State) updateMeterStatusFromAlerts(unitName string) error {
+	unit, err := st.Unit(unitName)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if unit.Life() != Alive {
+		return nil
+	}
+	coll, closer := st.getCollection(metricAlertsC)
+	defer closer()
+	var docs []metricAlertDoc
+	if err := coll.Find(bson.D{{"unit", unitName}, {"active", true}}).All(&docs); err != nil {
+		return errors.Trace(err)
+	}
+	var status *MeterStatus
+	for _, doc := range docs {
+		alertStatus := MeterStatus{MeterStatusFromString(doc.Status), doc.Info}
+		if status == nil {
+			status = &alertStatus
+			continue
+		}
+		combined := combineMeterStatus(*status, alertStatus)
+		status = &combined
+	}
+	return unit.setThresholdMeterStatus(status)
+}
+
+// metricAlertRetention is how long resolved metric alerts are kept
+// before they are removed.
+const metricAlertRetention = 7 * 24 * time.Hour
+
+// pruneMetricAlertsOps returns the operations needed to remove alerts
+// that were resolved more than metricAlertRetention before now.
+func (st *State) pruneMetricAlertsOps(now time.Time) ([]txn.Op, error) {
+	coll, closer := st.getCollection(metricAlertsC)
+	defer closer()
+	var docs []metricAlertDoc
+	sel := bson.D{
+		{"active", false},
+		{"resolved", bson.D{{"$lt", now.Add(-metricAlertRetention)}}},
+	}
+	if err := coll.Find(sel).Select(bson.D{{"id", 1}}).All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      metricAlertsC,
+			Id:     doc.Id,
+			Assert: bson.D{{"active", false}},
+			Remove: true,
+		}
+	}
+	return ops, nil
+}
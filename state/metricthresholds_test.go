@@ -0,0 +1,335 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type MetricThresholdSuite struct {
+	ConnSuite
+	service *state.Service
+	unit    *state.Unit
+	now     time.Time
+}
+
+var _ = gc.Suite(&MetricThresholdSuite{})
+
+func (s *MetricThresholdSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	meteredCharm := s.Factory.MakeCharm(c, &factory.CharmParams{Name: "metered", URL: "cs:quantal/metered"})
+	s.service = s.Factory.MakeService(c, &factory.ServiceParams{Charm: meteredCharm})
+	s.unit = s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service, SetCharmURL: true})
+	s.now = state.NowToTheSecond()
+}
+
+func (s *MetricThresholdSuite) addThreshold(c *gc.C, op string, value float64, d time.Duration, status string) *state.MetricThreshold {
+	t, err := s.State.AddMetricThreshold(state.MetricThresholdParams{
+		Service:  "metered",
+		Key:      "pings",
+		Operator: op,
+		Value:    value,
+		Duration: d,
+		Status:   status,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return t
+}
+
+// addPings records a ping metric for the unit, ago before now.
+func (s *MetricThresholdSuite) addPings(c *gc.C, unit *state.Unit, value string, ago time.Duration) {
+	t := s.now.Add(-ago)
+	s.Factory.MakeMetric(c, &factory.MetricParams{
+		Unit:    unit,
+		Time:    &t,
+		Metrics: []state.Metric{{"pings", value, t}},
+	})
+}
+
+func (s *MetricThresholdSuite) assertMeterStatus(c *gc.C, unit *state.Unit, code state.MeterStatusCode, info string) {
+	status, err := unit.GetMeterStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Code, gc.Equals, code)
+	c.Assert(status.Info, gc.Equals, info)
+}
+
+func (s *MetricThresholdSuite) TestAddMetricThreshold(c *gc.C) {
+	t, err := s.State.AddMetricThreshold(state.MetricThresholdParams{
+		Service:  "metered",
+		Key:      "pings",
+		Operator: ">",
+		Value:    500,
+		Duration: 5 * time.Minute,
+		Status:   "AMBER",
+		Info:     "too many pings",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(t.Service(), gc.Equals, "metered")
+	c.Assert(t.Key(), gc.Equals, "pings")
+	c.Assert(t.Operator(), gc.Equals, ">")
+	c.Assert(t.Value(), gc.Equals, 500.0)
+	c.Assert(t.Duration(), gc.Equals, 5*time.Minute)
+	c.Assert(t.Status(), gc.Equals, "AMBER")
+	c.Assert(t.Info(), gc.Equals, "too many pings")
+	c.Assert(t.String(), gc.Equals, "metered pings > 500 for 5m0s")
+
+	t2, err := s.State.MetricThreshold(t.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(t2, jc.DeepEquals, t)
+	all, err := s.State.MetricThresholds()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 1)
+}
+
+func (s *MetricThresholdSuite) TestAddMetricThresholdInvalid(c *gc.C) {
+	valid := state.MetricThresholdParams{
+		Service:  "metered",
+		Key:      "pings",
+		Operator: ">",
+		Value:    500,
+		Status:   "RED",
+	}
+	for i, test := range []struct {
+		mutate func(*state.MetricThresholdParams)
+		err    string
+	}{{
+		func(p *state.MetricThresholdParams) { p.Operator = "!=" },
+		`cannot add metric threshold: operator "!=" not valid`,
+	}, {
+		func(p *state.MetricThresholdParams) { p.Status = "GREEN" },
+		`cannot add metric threshold: meter status "GREEN" not valid`,
+	}, {
+		func(p *state.MetricThresholdParams) { p.Duration = -time.Second },
+		`cannot add metric threshold: negative duration not valid`,
+	}, {
+		func(p *state.MetricThresholdParams) { p.Service = "unknown" },
+		`cannot add metric threshold: service "unknown" not found`,
+	}, {
+		func(p *state.MetricThresholdParams) { p.Key = "pongs" },
+		`cannot add metric threshold: metric "pongs" in charm "cs:quantal/metered" not found`,
+	}} {
+		c.Logf("test %d", i)
+		p := valid
+		test.mutate(&p)
+		_, err := s.State.AddMetricThreshold(p)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *MetricThresholdSuite) TestRemoveMetricThreshold(c *gc.C) {
+	t := s.addThreshold(c, ">", 500, 0, "AMBER")
+	err := s.State.RemoveMetricThreshold(t.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.MetricThreshold(t.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.State.RemoveMetricThreshold(t.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *MetricThresholdSuite) TestEvaluateRaisesAndResolvesAlert(c *gc.C) {
+	t := s.addThreshold(c, ">", 500, 5*time.Minute, "AMBER")
+	s.addPings(c, s.unit, "550", 6*time.Minute)
+	s.addPings(c, s.unit, "600", 4*time.Minute)
+	s.addPings(c, s.unit, "700", time.Minute)
+
+	err := s.State.EvaluateMetricThresholds(s.now)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, s.unit, state.MeterAmber, "metered pings > 500 for 5m0s")
+	alerts, err := s.State.MetricAlerts(time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alerts, gc.HasLen, 1)
+	c.Assert(alerts[0].Threshold(), gc.Equals, t.Id())
+	c.Assert(alerts[0].Unit(), gc.Equals, s.unit.UnitTag())
+	c.Assert(alerts[0].Value(), gc.Equals, "700")
+	c.Assert(alerts[0].Status(), gc.Equals, "AMBER")
+	c.Assert(alerts[0].Raised().Equal(s.now), jc.IsTrue)
+	_, resolved := alerts[0].Resolved()
+	c.Assert(resolved, jc.IsFalse)
+
+	// Evaluating again does not raise another alert.
+	err = s.State.EvaluateMetricThresholds(s.now)
+	c.Assert(err, jc.ErrorIsNil)
+	alerts, err = s.State.MetricAlerts(time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alerts, gc.HasLen, 1)
+
+	// Once a value within the window no longer breaches, the
+	// alert is resolved and the unit's meter status reverts to
+	// the one set by the collector.
+	s.addPings(c, s.unit, "100", 0)
+	later := s.now.Add(time.Second)
+	err = s.State.EvaluateMetricThresholds(later)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, s.unit, state.MeterNotSet, "")
+	alerts, err = s.State.MetricAlerts(time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alerts, gc.HasLen, 1)
+	resolvedAt, resolved := alerts[0].Resolved()
+	c.Assert(resolved, jc.IsTrue)
+	c.Assert(resolvedAt.Equal(later), jc.IsTrue)
+}
+
+func (s *MetricThresholdSuite) TestEvaluateNotAllValuesBreach(c *gc.C) {
+	s.addThreshold(c, ">", 500, 5*time.Minute, "AMBER")
+	s.addPings(c, s.unit, "100", 4*time.Minute)
+	s.addPings(c, s.unit, "700", time.Minute)
+	// Values outside the window are ignored.
+	s.addPings(c, s.unit, "1000", 10*time.Minute)
+
+	err := s.State.EvaluateMetricThresholds(s.now)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, s.unit, state.MeterNotSet, "")
+	alerts, err := s.State.MetricAlerts(time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alerts, gc.HasLen, 0)
+}
+
+func (s *MetricThresholdSuite) TestEvaluateValueRecordedBeforeWindow(c *gc.C) {
+	s.addThreshold(c, ">", 500, 5*time.Minute, "AMBER")
+	// The value in effect at the start of the window was recorded
+	// long before it, after older values that did not breach.
+	s.addPings(c, s.unit, "100", 3*time.Hour)
+	s.addPings(c, s.unit, "600", 2*time.Hour)
+
+	err := s.State.EvaluateMetricThresholds(s.now)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, s.unit, state.MeterAmber, "metered pings > 500 for 5m0s")
+	alerts, err := s.State.MetricAlerts(time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alerts, gc.HasLen, 1)
+	c.Assert(alerts[0].Value(), gc.Equals, "600")
+}
+
+func (s *MetricThresholdSuite) TestEvaluateBreachNotContinuous(c *gc.C) {
+	s.addThreshold(c, ">", 500, 5*time.Minute, "AMBER")
+	// The unit has only been breaching the threshold
+	// since well within the threshold's duration.
+	s.addPings(c, s.unit, "100", 6*time.Minute)
+	s.addPings(c, s.unit, "600", 4*time.Minute)
+	s.addPings(c, s.unit, "700", time.Minute)
+
+	err := s.State.EvaluateMetricThresholds(s.now)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, s.unit, state.MeterNotSet, "")
+
+	// Once the breach has lasted for the whole
+	// duration, an alert is raised.
+	err = s.State.EvaluateMetricThresholds(s.now.Add(time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, s.unit, state.MeterAmber, "metered pings > 500 for 5m0s")
+}
+
+func (s *MetricThresholdSuite) TestEvaluateKeepsCollectorMeterStatus(c *gc.C) {
+	err := s.unit.SetMeterStatus("AMBER", "collector says so")
+	c.Assert(err, jc.ErrorIsNil)
+	s.addThreshold(c, ">", 500, 0, "RED")
+	s.addPings(c, s.unit, "600", time.Minute)
+
+	err = s.State.EvaluateMetricThresholds(s.now)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, s.unit, state.MeterRed, "metered pings > 500")
+
+	// The collector setting the unit's meter status
+	// does not clear the status set by the alert.
+	err = s.unit.SetMeterStatus("GREEN", "all good")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, s.unit, state.MeterRed, "metered pings > 500")
+
+	// Once the alert is resolved, the collector's
+	// status is restored.
+	s.addPings(c, s.unit, "100", 0)
+	err = s.State.EvaluateMetricThresholds(s.now.Add(time.Second))
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, s.unit, state.MeterGreen, "all good")
+}
+
+func (s *MetricThresholdSuite) TestEvaluatePrunesResolvedAlerts(c *gc.C) {
+	s.addThreshold(c, ">", 500, 0, "AMBER")
+	s.addPings(c, s.unit, "600", time.Minute)
+	err := s.State.EvaluateMetricThresholds(s.now)
+	c.Assert(err, jc.ErrorIsNil)
+	s.addPings(c, s.unit, "100", 0)
+	err = s.State.EvaluateMetricThresholds(s.now.Add(time.Second))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The resolved alert is kept for a while.
+	err = s.State.EvaluateMetricThresholds(s.now.Add(24 * time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	alerts, err := s.State.MetricAlerts(time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alerts, gc.HasLen, 1)
+
+	err = s.State.EvaluateMetricThresholds(s.now.Add(8 * 24 * time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	alerts, err = s.State.MetricAlerts(time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alerts, gc.HasLen, 0)
+}
+
+func (s *MetricThresholdSuite) TestEvaluateLatestValue(c *gc.C) {
+	s.addThreshold(c, "<=", 10, 0, "RED")
+	unit2 := s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service, SetCharmURL: true})
+	s.addPings(c, s.unit, "100", 2*time.Minute)
+	s.addPings(c, s.unit, "5", time.Minute)
+	s.addPings(c, unit2, "5", 2*time.Minute)
+	s.addPings(c, unit2, "100", time.Minute)
+
+	err := s.State.EvaluateMetricThresholds(s.now)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, s.unit, state.MeterRed, "metered pings <= 10")
+	s.assertMeterStatus(c, unit2, state.MeterNotSet, "")
+}
+
+func (s *MetricThresholdSuite) TestEvaluateMostSevereAlert(c *gc.C) {
+	s.addThreshold(c, ">", 500, 0, "AMBER")
+	s.addThreshold(c, ">", 1000, 0, "RED")
+	s.addPings(c, s.unit, "2000", time.Minute)
+
+	err := s.State.EvaluateMetricThresholds(s.now)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, s.unit, state.MeterRed, "metered pings > 1000")
+	alerts, err := s.State.MetricAlerts(time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alerts, gc.HasLen, 2)
+}
+
+func (s *MetricThresholdSuite) TestEvaluateRemovedThreshold(c *gc.C) {
+	t := s.addThreshold(c, ">", 500, 0, "AMBER")
+	s.addPings(c, s.unit, "600", time.Minute)
+	err := s.State.EvaluateMetricThresholds(s.now)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, s.unit, state.MeterAmber, "metered pings > 500")
+
+	err = s.State.RemoveMetricThreshold(t.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.EvaluateMetricThresholds(s.now)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, s.unit, state.MeterNotSet, "")
+}
+
+func (s *MetricThresholdSuite) TestMetricAlertsSince(c *gc.C) {
+	s.addThreshold(c, ">", 500, 0, "AMBER")
+	s.addPings(c, s.unit, "600", time.Minute)
+	err := s.State.EvaluateMetricThresholds(s.now)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Active alerts are returned regardless of when they were raised.
+	alerts, err := s.State.MetricAlerts(s.now.Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alerts, gc.HasLen, 1)
+
+	s.addPings(c, s.unit, "100", 0)
+	err = s.State.EvaluateMetricThresholds(s.now.Add(time.Second))
+	c.Assert(err, jc.ErrorIsNil)
+	alerts, err = s.State.MetricAlerts(s.now.Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alerts, gc.HasLen, 0)
+}
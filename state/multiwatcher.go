@@ -6,9 +6,13 @@ package state
 import (
 	"container/list"
 	stderrors "errors"
+	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/juju/errors"
+	"github.com/juju/utils"
 	"launchpad.net/tomb"
 
 	"github.com/juju/juju/state/multiwatcher"
@@ -45,6 +49,52 @@ func (w *Multiwatcher) Stop() error {
 
 var ErrStopped = stderrors.New("watcher was stopped")
 
+// Position returns an opaque token identifying the changes the
+// watcher has returned so far. A watcher created with WatchFrom and
+// this position will return only changes made after it. Position must
+// not be called concurrently with Next.
+func (w *Multiwatcher) Position() string {
+	return fmt.Sprintf("%s:%d", w.all.id, w.revno)
+}
+
+// newMultiwatcherFrom returns a watcher on the given store manager that
+// continues from the given position. If the position cannot be resumed,
+// because it was issued by a different store manager or because changes
+// made since have been forgotten, the watcher starts from the beginning
+// and false is returned.
+func newMultiwatcherFrom(all *storeManager, position string) (*Multiwatcher, bool, error) {
+	w := NewMultiwatcher(all)
+	if position == "" {
+		return w, false, nil
+	}
+	sep := strings.LastIndex(position, ":")
+	if sep == -1 {
+		return nil, false, errors.NotValidf("watcher position %q", position)
+	}
+	revno, err := strconv.ParseInt(position[sep+1:], 10, 64)
+	if err != nil || revno < 0 {
+		return nil, false, errors.NotValidf("watcher position %q", position)
+	}
+	if position[:sep] != all.id || revno == 0 {
+		return w, false, nil
+	}
+	req := &request{
+		w:          w,
+		reply:      make(chan bool),
+		resumeFrom: revno,
+	}
+	select {
+	case all.request <- req:
+	case <-all.tomb.Dead():
+		err := all.tomb.Err()
+		if err == nil {
+			err = errors.Errorf("shared state watcher was stopped")
+		}
+		return nil, false, err
+	}
+	return w, <-req.reply, nil
+}
+
 // Next retrieves all changes that have happened since the last
 // time it was called, blocking until there are some changes available.
 func (w *Multiwatcher) Next() ([]multiwatcher.Delta, error) {
@@ -72,6 +122,10 @@ func (w *Multiwatcher) Next() ([]multiwatcher.Delta, error) {
 type storeManager struct {
 	tomb tomb.Tomb
 
+	// id distinguishes the positions of watchers on this storeManager
+	// from those issued by any other.
+	id string
+
 	// backing knows how to fetch information from
 	// the underlying state.
 	backing Backing
@@ -133,12 +187,18 @@ type request struct {
 	// requests on a given watcher.  It is used only by the central
 	// storeManager goroutine.
 	next *request
+
+	// resumeFrom, if non-zero, asks for the newly created watcher
+	// to be treated as though it had already seen all changes up to
+	// the given revno. The reply is true if that was possible.
+	resumeFrom int64
 }
 
 // newStoreManagerNoRun creates the store manager
 // but does not start its run loop.
 func newStoreManagerNoRun(backing Backing) *storeManager {
 	return &storeManager{
+		id:      utils.MustNewUUID().String(),
 		backing: backing,
 		request: make(chan *request),
 		all:     newStore(),
@@ -214,6 +274,10 @@ func (sm *storeManager) handle(req *request) {
 		}
 		return
 	}
+	if req.resumeFrom > 0 {
+		req.reply <- sm.resume(req.w, req.resumeFrom)
+		return
+	}
 	if req.reply == nil {
 		// This is a request to stop the watcher.
 		for req := sm.waiting[req.w]; req != nil; req = req.next {
@@ -277,6 +341,30 @@ func (sm *storeManager) seen(revno int64) {
 	}
 }
 
+// resume positions a new watcher as though it had seen all changes up
+// to the given revno, taking a reference to each entity that such a
+// watcher would hold. It returns false, leaving the watcher untouched,
+// if the store cannot report every change made since the revno.
+func (sm *storeManager) resume(w *Multiwatcher, revno int64) bool {
+	if w.revno != 0 || revno > sm.all.latestRevno || revno < sm.all.forgottenRevno {
+		return false
+	}
+	for e := sm.all.list.Front(); e != nil; e = e.Next() {
+		entry := e.Value.(*entityEntry)
+		if entry.creationRevno > revno {
+			// The watcher has not seen this entity.
+			continue
+		}
+		if entry.removed && entry.revno <= revno {
+			// The watcher has already seen the removal.
+			continue
+		}
+		entry.refCount++
+	}
+	w.revno = revno
+	return true
+}
+
 // leave is called when the given watcher leaves.  It decrements the reference
 // counts of any entities that have been seen by the watcher.
 func (sm *storeManager) leave(w *Multiwatcher) {
@@ -333,6 +421,11 @@ type multiwatcherStore struct {
 	latestRevno int64
 	entities    map[interface{}]*list.Element
 	list        *list.List
+
+	// forgottenRevno holds the revno of the most recent removal
+	// that has been discarded from the store. Watchers cannot be
+	// resumed from before it, because they would miss the removal.
+	forgottenRevno int64
 }
 
 // newStore returns an Store instance holding information about the
@@ -393,6 +486,9 @@ func (a *multiwatcherStore) decRef(entry *entityEntry) {
 	}
 	delete(a.entities, id)
 	a.list.Remove(elem)
+	if entry.revno > a.forgottenRevno {
+		a.forgottenRevno = entry.revno
+	}
 }
 
 // delete deletes the entry with the given info id.
@@ -417,6 +513,7 @@ func (a *multiwatcherStore) Remove(id multiwatcher.EntityId) {
 		a.latestRevno++
 		if entry.refCount == 0 {
 			a.delete(id)
+			a.forgottenRevno = a.latestRevno
 			return
 		}
 		entry.revno = a.latestRevno
@@ -106,6 +106,8 @@ func (d *Delta) UnmarshalJSON(data []byte) error {
 		d.Entity = new(BlockInfo)
 	case "action":
 		d.Entity = new(ActionInfo)
+	case "payload":
+		d.Entity = new(PayloadInfo)
 	default:
 		return fmt.Errorf("Unexpected entity name %q", entityKind)
 	}
@@ -206,6 +208,7 @@ type UnitInfo struct {
 	Ports          []network.Port
 	PortRanges     []network.PortRange
 	Subordinate    bool
+	Principal      string
 	// The following 3 status values are deprecated.
 	Status     Status
 	StatusInfo string
@@ -343,6 +346,32 @@ func (i *BlockInfo) EntityId() EntityId {
 	}
 }
 
+// PayloadInfo holds the information about a charm payload that is
+// tracked by multiwatcherStore.
+type PayloadInfo struct {
+	EnvUUID string
+	// Id identifies the payload within the environment; it is not
+	// the ID of the payload in the underlying technology, which is
+	// held in RawId.
+	Id     string
+	Unit   string
+	Class  string
+	Type   string
+	RawId  string
+	Status string
+	Labels []string
+}
+
+// EntityId returns a unique identifier for a payload across
+// environments.
+func (i *PayloadInfo) EntityId() EntityId {
+	return EntityId{
+		Kind:    "payload",
+		EnvUUID: i.EnvUUID,
+		Id:      i.Id,
+	}
+}
+
 // BlockType values define environment block type.
 type BlockType string
 
@@ -718,6 +718,91 @@ func (*storeManagerSuite) TestMultiwatcherStopBecauseStoreManagerError(c *gc.C)
 	checkNext(c, w, nil, "some error")
 }
 
+func (*storeManagerSuite) TestMultiwatcherResume(c *gc.C) {
+	b := newTestBacking([]multiwatcher.EntityInfo{
+		&multiwatcher.MachineInfo{EnvUUID: "uuid", Id: "0"},
+		&multiwatcher.MachineInfo{EnvUUID: "uuid", Id: "1"},
+	})
+	sm := newStoreManager(b)
+	defer func() {
+		c.Check(sm.Stop(), gc.IsNil)
+	}()
+	w0 := &Multiwatcher{all: sm}
+	checkNext(c, w0, []multiwatcher.Delta{
+		{Entity: &multiwatcher.MachineInfo{EnvUUID: "uuid", Id: "0"}},
+		{Entity: &multiwatcher.MachineInfo{EnvUUID: "uuid", Id: "1"}},
+	}, "")
+	position := w0.Position()
+
+	// The first watcher holds references to the machines, so
+	// the removal of machine 1 is kept for the second.
+	b.updateEntity(&multiwatcher.MachineInfo{EnvUUID: "uuid", Id: "0", InstanceId: "i-0"})
+	b.deleteEntity(multiwatcher.EntityId{"machine", "uuid", "1"})
+	w1, resumed, err := newMultiwatcherFrom(sm, position)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resumed, jc.IsTrue)
+	checkNext(c, w1, []multiwatcher.Delta{
+		{Entity: &multiwatcher.MachineInfo{EnvUUID: "uuid", Id: "0", InstanceId: "i-0"}},
+		{Removed: true, Entity: &multiwatcher.MachineInfo{EnvUUID: "uuid", Id: "1"}},
+	}, "")
+	c.Assert(w1.Position(), gc.Not(gc.Equals), position)
+}
+
+func (*storeManagerSuite) TestMultiwatcherResumeForgotten(c *gc.C) {
+	b := newTestBacking([]multiwatcher.EntityInfo{
+		&multiwatcher.MachineInfo{EnvUUID: "uuid", Id: "0"},
+	})
+	sm := newStoreManager(b)
+	defer func() {
+		c.Check(sm.Stop(), gc.IsNil)
+	}()
+	w0 := &Multiwatcher{all: sm}
+	checkNext(c, w0, []multiwatcher.Delta{
+		{Entity: &multiwatcher.MachineInfo{EnvUUID: "uuid", Id: "0"}},
+	}, "")
+	position := w0.Position()
+	c.Assert(w0.Stop(), jc.ErrorIsNil)
+
+	// Nothing holds a reference to the machine, so its removal is
+	// forgotten as soon as it happens.
+	b.deleteEntity(multiwatcher.EntityId{"machine", "uuid", "0"})
+	b.updateEntity(&multiwatcher.MachineInfo{EnvUUID: "uuid", Id: "1"})
+	w1 := &Multiwatcher{all: sm}
+	checkNext(c, w1, []multiwatcher.Delta{
+		{Entity: &multiwatcher.MachineInfo{EnvUUID: "uuid", Id: "1"}},
+	}, "")
+
+	w2, resumed, err := newMultiwatcherFrom(sm, position)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resumed, jc.IsFalse)
+	checkNext(c, w2, []multiwatcher.Delta{
+		{Entity: &multiwatcher.MachineInfo{EnvUUID: "uuid", Id: "1"}},
+	}, "")
+}
+
+func (*storeManagerSuite) TestMultiwatcherResumeOtherStoreManager(c *gc.C) {
+	sm := newStoreManager(newTestBacking([]multiwatcher.EntityInfo{
+		&multiwatcher.MachineInfo{EnvUUID: "uuid", Id: "0"},
+	}))
+	defer func() {
+		c.Check(sm.Stop(), gc.IsNil)
+	}()
+	w, resumed, err := newMultiwatcherFrom(sm, "some-other-id:1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resumed, jc.IsFalse)
+	checkNext(c, w, []multiwatcher.Delta{
+		{Entity: &multiwatcher.MachineInfo{EnvUUID: "uuid", Id: "0"}},
+	}, "")
+}
+
+func (*storeManagerSuite) TestMultiwatcherResumeInvalidPosition(c *gc.C) {
+	sm := newStoreManagerNoRun(newTestBacking(nil))
+	for _, position := range []string{"no-revno", sm.id + ":x", sm.id + ":-1"} {
+		_, _, err := newMultiwatcherFrom(sm, position)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
 func StoreIncRef(a *multiwatcherStore, id interface{}) {
 	entry := a.entities[id].Value.(*entityEntry)
 	entry.refCount++
@@ -4,7 +4,10 @@
 package state
 
 import (
+	"time"
+
 	"github.com/juju/errors"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/payload"
 )
@@ -114,7 +117,72 @@ func (st *State) UnitPayloads(unit *Unit) (UnitPayloads, error) {
 		return nil, errors.Trace(err)
 	}
 
-	return unitPayloads, nil
+	return &unitPayloadsWithHistory{
+		UnitPayloads: unitPayloads,
+		st:           st,
+		unit:         unitID,
+	}, nil
+}
+
+// payloadGlobalKey returns the global database key under which the
+// status history of the identified payload is recorded.
+func payloadGlobalKey(unit, class, rawID string) string {
+	return "u#" + unit + "#payload#" + payload.BuildID(class, rawID)
+}
+
+// unitPayloadsWithHistory records the statuses that a unit's payloads
+// are given in status history.
+type unitPayloadsWithHistory struct {
+	UnitPayloads
+	st   *State
+	unit string
+}
+
+// Track implements UnitPayloads.
+func (up *unitPayloadsWithHistory) Track(pl payload.Payload) error {
+	if err := up.UnitPayloads.Track(pl); err != nil {
+		return err
+	}
+	up.recordStatus(pl.Name, pl.ID, pl.Status)
+	return nil
+}
+
+// SetStatus implements UnitPayloads.
+func (up *unitPayloadsWithHistory) SetStatus(id, status string) error {
+	if err := up.UnitPayloads.SetStatus(id, status); err != nil {
+		return err
+	}
+	results, err := up.UnitPayloads.List(id)
+	if err != nil {
+		logger.Errorf("cannot record status history of payload %q: %v", id, err)
+		return nil
+	}
+	for _, result := range results {
+		if result.Payload != nil {
+			up.recordStatus(result.Payload.Name, result.Payload.ID, status)
+		}
+	}
+	return nil
+}
+
+func (up *unitPayloadsWithHistory) recordStatus(class, rawID, status string) {
+	doc := statusDoc{
+		Status:  Status(status),
+		Updated: time.Now().UnixNano(),
+	}
+	probablyUpdateStatusHistory(up.st, payloadGlobalKey(up.unit, class, rawID), doc)
+}
+
+// PayloadStatusHistory returns at most size of the most recent statuses
+// of the identified payload of the named unit, newest first.
+func (st *State) PayloadStatusHistory(unitName, class, rawID string, size int) ([]StatusInfo, error) {
+	if !names.IsValidUnit(unitName) {
+		return nil, errors.NotValidf("unit name %q", unitName)
+	}
+	if size < 1 {
+		return nil, errors.NotValidf("history size %d", size)
+	}
+	return statusHistory(st, payloadGlobalKey(unitName, class, rawID), size)
 }
 
 type payloadsEnvPersistence struct {
@@ -4,6 +4,8 @@
 package state_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -12,6 +14,8 @@ import (
 	"github.com/juju/juju/component/all"
 	"github.com/juju/juju/payload"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/multiwatcher"
+	coretesting "github.com/juju/juju/testing"
 )
 
 func init() {
@@ -179,6 +183,114 @@ func (s *unitPayloadsSuite) TestFunctional(c *gc.C) {
 	c.Check(results, gc.HasLen, 0)
 }
 
+func (s *unitPayloadsSuite) TestStatusHistory(c *gc.C) {
+	unit := addUnit(c, s.ConnSuite, unitArgs{
+		charm:    "dummy",
+		service:  "a-service",
+		metadata: payloadsMetaYAML,
+		machine:  "0",
+	})
+	st, err := s.State.UnitPayloads(unit)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = st.Track(payload.Payload{
+		PayloadClass: charm.PayloadClass{
+			Name: "payloadA",
+			Type: "docker",
+		},
+		ID:     "xyz",
+		Status: payload.StateStarting,
+		Unit:   "a-service/0",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := st.LookUp("payloadA", "xyz")
+	c.Assert(err, jc.ErrorIsNil)
+	err = st.SetStatus(id, payload.StateRunning)
+	c.Assert(err, jc.ErrorIsNil)
+	err = st.SetStatus(id, payload.StateStopping)
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.State.PayloadStatusHistory("a-service/0", "payloadA", "xyz", 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 3)
+	c.Check(history[0].Status, gc.Equals, state.Status(payload.StateStopping))
+	c.Check(history[1].Status, gc.Equals, state.Status(payload.StateRunning))
+	c.Check(history[2].Status, gc.Equals, state.Status(payload.StateStarting))
+
+	history, err = s.State.PayloadStatusHistory("a-service/0", "payloadA", "xyz", 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Check(history[0].Status, gc.Equals, state.Status(payload.StateStopping))
+
+	// Other payloads have their own history.
+	history, err = s.State.PayloadStatusHistory("a-service/0", "payloadA", "abc", 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(history, gc.HasLen, 0)
+
+	// A failed status change is not recorded.
+	err = st.SetStatus(id, "bogus")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	history, err = s.State.PayloadStatusHistory("a-service/0", "payloadA", "xyz", 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(history, gc.HasLen, 3)
+}
+
+func (s *unitPayloadsSuite) TestStatusHistoryInvalidArgs(c *gc.C) {
+	_, err := s.State.PayloadStatusHistory("a-service", "payloadA", "xyz", 10)
+	c.Check(err, gc.ErrorMatches, `unit name "a-service" not valid`)
+	_, err = s.State.PayloadStatusHistory("a-service/0", "payloadA", "xyz", 0)
+	c.Check(err, gc.ErrorMatches, "history size 0 not valid")
+}
+
+func (s *unitPayloadsSuite) TestWatchAll(c *gc.C) {
+	unit := addUnit(c, s.ConnSuite, unitArgs{
+		charm:    "dummy",
+		service:  "a-service",
+		metadata: payloadsMetaYAML,
+		machine:  "0",
+	})
+	st, err := s.State.UnitPayloads(unit)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := s.State.Watch()
+	defer w.Stop()
+	deltasC := makeMultiwatcherOutput(w)
+
+	err = st.Track(payload.Payload{
+		PayloadClass: charm.PayloadClass{
+			Name: "payloadA",
+			Type: "docker",
+		},
+		ID:     "xyz",
+		Status: payload.StateRunning,
+		Unit:   "a-service/0",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.State.StartSync()
+
+	timeout := time.After(coretesting.LongWait)
+	for {
+		select {
+		case deltas := <-deltasC:
+			for _, delta := range deltas {
+				info, ok := delta.Entity.(*multiwatcher.PayloadInfo)
+				if !ok {
+					continue
+				}
+				c.Check(delta.Removed, jc.IsFalse)
+				c.Check(info.EnvUUID, gc.Equals, s.State.EnvironUUID())
+				c.Check(info.Unit, gc.Equals, "a-service/0")
+				c.Check(info.Class, gc.Equals, "payloadA")
+				c.Check(info.RawId, gc.Equals, "xyz")
+				c.Check(info.Status, gc.Equals, payload.StateRunning)
+				return
+			}
+		case <-timeout:
+			c.Fatalf("timed out waiting for payload delta")
+		}
+	}
+}
+
 const payloadsMetaYAML = `
 name: a-charm
 summary: a charm...
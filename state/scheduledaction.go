@@ -0,0 +1,339 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/utils/cron"
+)
+
+// maxScheduledActionRuns is the number of runs recorded in the
+// history of each scheduled action; older runs are discarded.
+const maxScheduledActionRuns = 20
+
+// scheduledActionDoc describes an action that is enqueued on a unit
+// repeatedly, according to a cron schedule.
+type scheduledActionDoc struct {
+	DocId      string                 `bson:"_id"`
+	EnvUUID    string                 `bson:"env-uuid"`
+	Receiver   string                 `bson:"receiver"`
+	Name       string                 `bson:"name"`
+	Parameters map[string]interface{} `bson:"parameters"`
+	Schedule   string                 `bson:"schedule"`
+	Created    time.Time              `bson:"created"`
+	NextRun    time.Time              `bson:"nextrun"`
+	Runs       []scheduledRunDoc      `bson:"runs,omitempty"`
+}
+
+// scheduledRunDoc records a single run of a scheduled action.
+type scheduledRunDoc struct {
+	ActionId string    `bson:"actionid,omitempty"`
+	Enqueued time.Time `bson:"enqueued"`
+	Error    string    `bson:"error,omitempty"`
+}
+
+// ScheduledActionRun records a single run of a scheduled action.
+type ScheduledActionRun struct {
+	// ActionId is the id of the action that was enqueued, if any.
+	ActionId string
+
+	// Enqueued is the time at which the run took place.
+	Enqueued time.Time
+
+	// Error holds the reason the action could not be enqueued, if any.
+	Error string
+}
+
+// ScheduledAction represents an action that is enqueued repeatedly
+// according to a cron schedule.
+type ScheduledAction struct {
+	st  *State
+	doc scheduledActionDoc
+}
+
+// Id returns the id of the scheduled action.
+func (sa *ScheduledAction) Id() string {
+	return sa.st.localID(sa.doc.DocId)
+}
+
+// Receiver returns the name of the unit the action is enqueued on.
+func (sa *ScheduledAction) Receiver() string {
+	return sa.doc.Receiver
+}
+
+// Name returns the name of the action, as defined in the charm.
+func (sa *ScheduledAction) Name() string {
+	return sa.doc.Name
+}
+
+// Parameters returns the parameters passed to each enqueued action.
+func (sa *ScheduledAction) Parameters() map[string]interface{} {
+	return sa.doc.Parameters
+}
+
+// Schedule returns the cron specification of the schedule.
+func (sa *ScheduledAction) Schedule() string {
+	return sa.doc.Schedule
+}
+
+// Created returns the time the action was scheduled.
+func (sa *ScheduledAction) Created() time.Time {
+	return sa.doc.Created
+}
+
+// NextRun returns the time at which the action will next be enqueued.
+func (sa *ScheduledAction) NextRun() time.Time {
+	return sa.doc.NextRun
+}
+
+// Runs returns the most recent runs of the scheduled action, oldest
+// first.
+func (sa *ScheduledAction) Runs() []ScheduledActionRun {
+	runs := make([]ScheduledActionRun, len(sa.doc.Runs))
+	for i, r := range sa.doc.Runs {
+		runs[i] = ScheduledActionRun{r.ActionId, r.Enqueued, r.Error}
+	}
+	return runs
+}
+
+// Refresh refreshes the contents of the ScheduledAction from the
+// underlying state.
+func (sa *ScheduledAction) Refresh() error {
+	fresh, err := sa.st.ScheduledAction(sa.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	sa.doc = fresh.doc
+	return nil
+}
+
+// Run enqueues the action on its unit and advances the schedule to the
+// first activation after now. The outcome, including any failure to
+// enqueue the action, is recorded in the run history. If the unit no
+// longer exists, the scheduled action is removed and an error
+// satisfying errors.IsNotFound is returned.
+func (sa *ScheduledAction) Run(now time.Time) (*Action, error) {
+	schedule, err := cron.Parse(sa.doc.Schedule)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	next := schedule.Next(now.UTC())
+
+	// Claim the run by advancing the schedule, so that the action
+	// is enqueued at most once per activation.
+	ops := []txn.Op{{
+		C:      scheduledActionsC,
+		Id:     sa.doc.DocId,
+		Assert: bson.D{{"nextrun", sa.doc.NextRun}},
+		Update: bson.D{{"$set", bson.D{{"nextrun", next}}}},
+	}}
+	if err := sa.st.runTransaction(ops); err != nil {
+		return nil, errors.Annotatef(
+			onAbort(err, errors.New("already run or removed")),
+			"cannot run scheduled action %q", sa.Id(),
+		)
+	}
+	sa.doc.NextRun = next
+
+	run := scheduledRunDoc{Enqueued: nowToTheSecond()}
+	unit, err := sa.st.Unit(sa.doc.Receiver)
+	if errors.IsNotFound(err) {
+		if err := sa.Remove(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return nil, errors.Annotatef(err, "cannot run scheduled action %q", sa.Id())
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	action, enqueueErr := unit.AddAction(sa.doc.Name, sa.doc.Parameters)
+	if enqueueErr != nil {
+		run.Error = enqueueErr.Error()
+	} else {
+		run.ActionId = action.Id()
+	}
+	ops = []txn.Op{{
+		C:      scheduledActionsC,
+		Id:     sa.doc.DocId,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$push", bson.D{{"runs", bson.D{
+			{"$each", []scheduledRunDoc{run}},
+			{"$slice", -maxScheduledActionRuns},
+		}}}}},
+	}}
+	if err := sa.st.runTransaction(ops); err != nil && err != txn.ErrAborted {
+		return nil, errors.Annotatef(err, "cannot record run of scheduled action %q", sa.Id())
+	}
+	sa.doc.Runs = append(sa.doc.Runs, run)
+	if len(sa.doc.Runs) > maxScheduledActionRuns {
+		sa.doc.Runs = sa.doc.Runs[len(sa.doc.Runs)-maxScheduledActionRuns:]
+	}
+	if enqueueErr != nil {
+		return nil, errors.Annotatef(enqueueErr, "cannot run scheduled action %q", sa.Id())
+	}
+	return action, nil
+}
+
+// Remove removes the scheduled action. Actions it has already enqueued
+// are not affected. It is not an error to remove a scheduled action
+// that has already been removed.
+func (sa *ScheduledAction) Remove() error {
+	ops := []txn.Op{{
+		C:      scheduledActionsC,
+		Id:     sa.doc.DocId,
+		Remove: true,
+	}}
+	if err := sa.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot remove scheduled action %q", sa.Id())
+	}
+	return nil
+}
+
+// ScheduleAction arranges for the named action to be enqueued on the
+// unit with the given payload each time the cron schedule fires.
+// Schedules are evaluated in UTC.
+func (u *Unit) ScheduleAction(name, schedule string, payload map[string]interface{}) (*ScheduledAction, error) {
+	if len(name) == 0 {
+		return nil, errors.New("no action name given")
+	}
+	specs, err := u.ActionSpecs()
+	if err != nil {
+		return nil, err
+	}
+	spec, ok := specs[name]
+	if !ok {
+		return nil, errors.Errorf("action %q not defined on unit %q", name, u.Name())
+	}
+	if err := spec.ValidateParams(payload); err != nil {
+		return nil, err
+	}
+	return u.st.addScheduledAction(u.UnitTag(), name, schedule, payload)
+}
+
+// addScheduledAction records a new scheduled action for the receiver.
+func (st *State) addScheduledAction(receiver names.UnitTag, name, schedule string, payload map[string]interface{}) (*ScheduledAction, error) {
+	parsed, err := cron.Parse(schedule)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	now := nowToTheSecond()
+	next := parsed.Next(now)
+	if next.IsZero() {
+		return nil, errors.Errorf("schedule %q never fires", schedule)
+	}
+	id, err := NewUUID()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	doc := scheduledActionDoc{
+		DocId:      st.docID(id.String()),
+		EnvUUID:    st.EnvironUUID(),
+		Receiver:   receiver.Id(),
+		Name:       name,
+		Parameters: payload,
+		Schedule:   schedule,
+		Created:    now,
+		NextRun:    next,
+	}
+	unitDocId := st.docID(receiver.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if notDead, err := isNotDead(st, unitsC, unitDocId); err != nil {
+			return nil, err
+		} else if !notDead {
+			return nil, ErrDead
+		}
+		return []txn.Op{{
+			C:      unitsC,
+			Id:     unitDocId,
+			Assert: notDeadDoc,
+		}, {
+			C:      scheduledActionsC,
+			Id:     doc.DocId,
+			Assert: txn.DocMissing,
+			Insert: &doc,
+		}}, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return nil, errors.Annotatef(err, "cannot schedule action %q on unit %q", name, receiver.Id())
+	}
+	return &ScheduledAction{st, doc}, nil
+}
+
+// ScheduledAction returns the scheduled action with the given id.
+func (st *State) ScheduledAction(id string) (*ScheduledAction, error) {
+	scheduled, closer := st.getCollection(scheduledActionsC)
+	defer closer()
+
+	var doc scheduledActionDoc
+	err := scheduled.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("scheduled action %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get scheduled action %q", id)
+	}
+	return &ScheduledAction{st, doc}, nil
+}
+
+// ScheduledActions returns all scheduled actions in the environment.
+func (st *State) ScheduledActions() ([]*ScheduledAction, error) {
+	return st.scheduledActions(nil)
+}
+
+// ScheduledActionsForUnit returns the actions scheduled on the named
+// unit.
+func (st *State) ScheduledActionsForUnit(unitName string) ([]*ScheduledAction, error) {
+	return st.scheduledActions(bson.D{{"receiver", unitName}})
+}
+
+// ScheduledActionsDue returns the scheduled actions whose next run is
+// no later than the given time.
+func (st *State) ScheduledActionsDue(now time.Time) ([]*ScheduledAction, error) {
+	return st.scheduledActions(bson.D{{"nextrun", bson.D{{"$lte", now}}}})
+}
+
+// RunDueScheduledActions enqueues every scheduled action whose next run
+// time has passed. Failures to enqueue an action are recorded in the
+// scheduled action's history and do not stop the others from running;
+// only errors accessing state are returned.
+func (st *State) RunDueScheduledActions() error {
+	return st.runDueScheduledActions(time.Now())
+}
+
+func (st *State) runDueScheduledActions(now time.Time) error {
+	due, err := st.ScheduledActionsDue(now)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, sa := range due {
+		action, err := sa.Run(now)
+		if err != nil {
+			logger.Warningf("%v", err)
+			continue
+		}
+		logger.Debugf("enqueued action %q (%s) on unit %q for schedule %q",
+			action.Id(), sa.Name(), sa.Receiver(), sa.Id())
+	}
+	return nil
+}
+
+func (st *State) scheduledActions(query bson.D) ([]*ScheduledAction, error) {
+	scheduled, closer := st.getCollection(scheduledActionsC)
+	defer closer()
+
+	var docs []scheduledActionDoc
+	if err := scheduled.Find(query).Sort("nextrun").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get scheduled actions")
+	}
+	result := make([]*ScheduledAction, len(docs))
+	for i, doc := range docs {
+		result[i] = &ScheduledAction{st, doc}
+	}
+	return result, nil
+}
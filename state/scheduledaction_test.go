@@ -0,0 +1,219 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type ScheduledActionSuite struct {
+	ConnSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&ScheduledActionSuite{})
+
+func (s *ScheduledActionSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	var err error
+	s.unit, err = service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ScheduledActionSuite) TestScheduleAction(c *gc.C) {
+	params := map[string]interface{}{"outfile": "nightly.bz2"}
+	sa, err := s.unit.ScheduleAction("snapshot", "30 2 * * *", params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sa.Receiver(), gc.Equals, "dummy/0")
+	c.Assert(sa.Name(), gc.Equals, "snapshot")
+	c.Assert(sa.Schedule(), gc.Equals, "30 2 * * *")
+	c.Assert(sa.Parameters(), jc.DeepEquals, params)
+	c.Assert(sa.Runs(), gc.HasLen, 0)
+
+	next := sa.NextRun().UTC()
+	c.Assert(next.After(sa.Created()), jc.IsTrue)
+	c.Assert(next.Hour(), gc.Equals, 2)
+	c.Assert(next.Minute(), gc.Equals, 30)
+
+	fetched, err := s.State.ScheduledAction(sa.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fetched.Name(), gc.Equals, "snapshot")
+
+	all, err := s.State.ScheduledActionsForUnit("dummy/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 1)
+	c.Assert(all[0].Id(), gc.Equals, sa.Id())
+}
+
+func (s *ScheduledActionSuite) TestScheduleActionErrors(c *gc.C) {
+	_, err := s.unit.ScheduleAction("missing", "@daily", nil)
+	c.Assert(err, gc.ErrorMatches, `action "missing" not defined on unit "dummy/0"`)
+
+	_, err = s.unit.ScheduleAction("snapshot", "@daily", map[string]interface{}{"outfile": 5.0})
+	c.Assert(err, gc.ErrorMatches, `validation failed: .*`)
+
+	_, err = s.unit.ScheduleAction("snapshot", "61 * * * *", nil)
+	c.Assert(err, gc.ErrorMatches, `cron spec "61 \* \* \* \*": minute 61 out of range \[0, 59\]`)
+
+	_, err = s.unit.ScheduleAction("snapshot", "0 0 30 2 *", nil)
+	c.Assert(err, gc.ErrorMatches, `schedule "0 0 30 2 \*" never fires`)
+
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.unit.ScheduleAction("snapshot", "@daily", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot schedule action "snapshot" on unit "dummy/0": not found or dead`)
+}
+
+func (s *ScheduledActionSuite) TestRun(c *gc.C) {
+	sa, err := s.unit.ScheduleAction("snapshot", "@hourly", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	due, err := s.State.ScheduledActionsDue(sa.NextRun().Add(-time.Second))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(due, gc.HasLen, 0)
+	due, err = s.State.ScheduledActionsDue(sa.NextRun())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(due, gc.HasLen, 1)
+
+	now := sa.NextRun()
+	action, err := due[0].Run(now)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Name(), gc.Equals, "snapshot")
+	c.Assert(action.Receiver(), gc.Equals, "dummy/0")
+
+	err = sa.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sa.NextRun().Equal(now.Add(time.Hour)), jc.IsTrue)
+	runs := sa.Runs()
+	c.Assert(runs, gc.HasLen, 1)
+	c.Assert(runs[0].ActionId, gc.Equals, action.Id())
+	c.Assert(runs[0].Error, gc.Equals, "")
+
+	// A stale copy cannot run the same activation again.
+	_, err = due[0].Run(now)
+	c.Assert(err, gc.ErrorMatches, `cannot run scheduled action ".*": already run or removed`)
+}
+
+func (s *ScheduledActionSuite) TestRunRecordsFailure(c *gc.C) {
+	sa, err := s.unit.ScheduleAction("snapshot", "@hourly", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = sa.Run(sa.NextRun())
+	c.Assert(err, gc.ErrorMatches, `cannot run scheduled action ".*": .*`)
+	err = sa.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	runs := sa.Runs()
+	c.Assert(runs, gc.HasLen, 1)
+	c.Assert(runs[0].ActionId, gc.Equals, "")
+	c.Assert(runs[0].Error, gc.Not(gc.Equals), "")
+}
+
+func (s *ScheduledActionSuite) TestRunRemovesScheduleForMissingUnit(c *gc.C) {
+	sa, err := s.unit.ScheduleAction("snapshot", "@hourly", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = sa.Run(sa.NextRun())
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.ScheduledAction(sa.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ScheduledActionSuite) TestRemove(c *gc.C) {
+	sa, err := s.unit.ScheduleAction("snapshot", "@daily", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = sa.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	err = sa.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	all, err := s.State.ScheduledActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 0)
+}
+
+func (s *ScheduledActionSuite) TestUnitRemovalCleansUpSchedules(c *gc.C) {
+	sa, err := s.unit.ScheduleAction("snapshot", "@daily", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.ScheduledAction(sa.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ScheduledActionSuite) TestRunDueScheduledActions(c *gc.C) {
+	hourly, err := s.unit.ScheduleAction("snapshot", "@hourly", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	yearly, err := s.unit.ScheduleAction("snapshot", "@yearly", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	if !yearly.NextRun().After(hourly.NextRun()) {
+		c.Skip("yearly schedule is due within the hour")
+	}
+
+	// Nothing is due yet.
+	err = state.RunDueScheduledActions(s.State, hourly.NextRun().Add(-time.Second))
+	c.Assert(err, jc.ErrorIsNil)
+	actions, err := s.unit.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 0)
+
+	// Only the hourly schedule fires.
+	err = state.RunDueScheduledActions(s.State, hourly.NextRun())
+	c.Assert(err, jc.ErrorIsNil)
+	err = hourly.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hourly.Runs(), gc.HasLen, 1)
+	err = yearly.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(yearly.Runs(), gc.HasLen, 0)
+
+	actions, err = s.unit.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Assert(hourly.Runs()[0].ActionId, gc.Equals, actions[0].Id())
+}
+
+func (s *ScheduledActionSuite) TestRunDueScheduledActionsContinuesAfterFailure(c *gc.C) {
+	service, err := s.unit.Service()
+	c.Assert(err, jc.ErrorIsNil)
+	unit2, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	failing, err := s.unit.ScheduleAction("snapshot", "@hourly", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	working, err := unit2.ScheduleAction("snapshot", "@hourly", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = state.RunDueScheduledActions(s.State, failing.NextRun())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = failing.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(failing.Runs(), gc.HasLen, 1)
+	c.Assert(failing.Runs()[0].Error, gc.Not(gc.Equals), "")
+
+	err = working.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(working.Runs(), gc.HasLen, 1)
+	c.Assert(working.Runs()[0].ActionId, gc.Not(gc.Equals), "")
+}
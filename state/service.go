@@ -170,7 +170,11 @@ func (s *Service) destroyOps() ([]txn.Op, error) {
 	// removed, the service can also be removed.
 	if s.doc.UnitCount == 0 && s.doc.RelationCount == removeCount {
 		hasLastRefs := bson.D{{"life", Alive}, {"unitcount", 0}, {"relationcount", removeCount}}
-		return append(ops, s.removeOps(hasLastRefs)...), nil
+		removeOps, err := s.removeOps(hasLastRefs)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, removeOps...), nil
 	}
 	// In all other cases, service removal will be handled as a consequence
 	// of the removal of the last unit or relation referencing it. If any
@@ -208,7 +212,8 @@ func (s *Service) destroyOps() ([]txn.Op, error) {
 
 // removeOps returns the operations required to remove the service. Supplied
 // asserts will be included in the operation on the service document.
-func (s *Service) removeOps(asserts bson.D) []txn.Op {
+// Storage detached from the service's units is destroyed by a cleanup.
+func (s *Service) removeOps(asserts bson.D) ([]txn.Op, error) {
 	settingsDocID := s.st.docID(s.settingsKey())
 	ops := []txn.Op{
 		{
@@ -232,7 +237,14 @@ func (s *Service) removeOps(asserts bson.D) []txn.Op {
 		removeLeadershipSettingsOp(s.Tag().Id()),
 		removeStatusOp(s.st, s.globalKey()),
 	}
-	return ops
+	storageCount, err := s.st.countEntityStorageInstances(s.Tag())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if storageCount > 0 {
+		ops = append(ops, s.st.newCleanupOp(cleanupStorageForRemovedService, s.doc.Name))
+	}
+	return ops, nil
 }
 
 // IsExposed returns whether this service is exposed. The explicitly open
@@ -661,6 +673,14 @@ const MessageWaitForAgentInit = "Waiting for agent initialization to finish"
 // to include additional assertions for the service document.  This method
 // assumes that the service already exists in the db.
 func (s *Service) addUnitOps(principalName string, asserts bson.D) (string, []txn.Op, error) {
+	return s.addUnitOpsAttachingStorage(principalName, nil, asserts)
+}
+
+// addUnitOpsAttachingStorage is just like addUnitOps, but attaches the
+// specified detached storage instances to the new unit.
+func (s *Service) addUnitOpsAttachingStorage(
+	principalName string, attachStorage []names.StorageTag, asserts bson.D,
+) (string, []txn.Op, error) {
 	var cons constraints.Value
 	if !s.doc.Subordinate {
 		scons, err := s.Constraints()
@@ -683,21 +703,23 @@ func (s *Service) addUnitOps(principalName string, asserts bson.D) (string, []tx
 		cons:          cons,
 		principalName: principalName,
 		storageCons:   storageCons,
+		attachStorage: attachStorage,
 	}
-	names, ops, err := s.addUnitOpsWithCons(args)
+	name, ops, err := s.addUnitOpsWithCons(args)
 	if err != nil {
-		return names, ops, err
+		return name, ops, err
 	}
 	// we verify the service is alive
 	asserts = append(isAliveDoc, asserts...)
 	ops = append(ops, s.incUnitCountOp(asserts))
-	return names, ops, err
+	return name, ops, err
 }
 
 type addUnitOpsArgs struct {
 	principalName string
 	cons          constraints.Value
 	storageCons   map[string]StorageConstraints
+	attachStorage []names.StorageTag
 }
 
 // addServiceUnitOps is just like addUnitOps but explicitly takes a
@@ -722,11 +744,24 @@ func (s *Service) addUnitOpsWithCons(args addUnitOpsArgs) (string, []txn.Op, err
 		return "", nil, err
 	}
 
-	// Create instances of the charm's declared stores.
-	storageOps, numStorageAttachments, err := s.unitStorageOps(name, args.storageCons)
+	// Attach any detached storage, and create instances of the charm's
+	// declared stores that are not satisfied by the attached storage.
+	storageCons := args.storageCons
+	var attachStorageOps []txn.Op
+	if len(args.attachStorage) > 0 {
+		attachStorageOps, storageCons, err = s.unitAttachStorageOps(
+			name, args.attachStorage, storageCons,
+		)
+		if err != nil {
+			return "", nil, errors.Trace(err)
+		}
+	}
+	storageOps, numStorageAttachments, err := s.unitStorageOps(name, storageCons)
 	if err != nil {
 		return "", nil, errors.Trace(err)
 	}
+	storageOps = append(storageOps, attachStorageOps...)
+	numStorageAttachments += len(args.attachStorage)
 
 	docID := s.st.docID(name)
 	globalKey := unitGlobalKey(name)
@@ -844,7 +879,21 @@ func (s *Service) GetOwnerTag() string {
 // AddUnit adds a new principal unit to the service.
 func (s *Service) AddUnit() (unit *Unit, err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot add unit to service %q", s)
-	name, ops, err := s.addUnitOps("", nil)
+	return s.addUnit(nil)
+}
+
+// AddUnitAttachingStorage adds a new principal unit to the service, and
+// attaches the specified storage instances to it. The storage instances
+// must have been detached from other units of the service with
+// DetachStorage; storage is not created for the new unit in place of
+// the attached storage.
+func (s *Service) AddUnitAttachingStorage(storage []names.StorageTag) (unit *Unit, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add unit to service %q", s)
+	return s.addUnit(storage)
+}
+
+func (s *Service) addUnit(attachStorage []names.StorageTag) (*Unit, error) {
+	name, ops, err := s.addUnitOpsAttachingStorage("", attachStorage, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -894,6 +943,7 @@ func (s *Service) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 		removeStatusOp(s.st, u.globalKey()),
 		removeConstraintsOp(s.st, u.globalAgentKey()),
 		annotationRemoveOp(s.st, u.globalKey()),
+		removeLoggingOverrideOp(s.st, u.Tag()),
 		s.st.newCleanupOp(cleanupRemovedUnit, u.doc.Name),
 	)
 	ops = append(ops, portsOps...)
@@ -909,7 +959,11 @@ func (s *Service) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 	}
 	if s.doc.Life == Dying && s.doc.RelationCount == 0 && s.doc.UnitCount == 1 {
 		hasLastRef := bson.D{{"life", Dying}, {"relationcount", 0}, {"unitcount", 1}}
-		return append(ops, s.removeOps(hasLastRef)...), nil
+		removeOps, err := s.removeOps(hasLastRef)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, removeOps...), nil
 	}
 	svcOp := txn.Op{
 		C:      servicesC,
@@ -307,12 +307,27 @@ func (st *State) MongoSession() *mgo.Session {
 type closeFunc func()
 
 func (st *State) Watch() *Multiwatcher {
+	return NewMultiwatcher(st.getAllManager())
+}
+
+// WatchFrom returns a Multiwatcher that continues from a position
+// previously returned by Multiwatcher.Position, so that only changes
+// made since are reported. If the position cannot be resumed -- for
+// example because it was issued by another API server -- the watcher
+// starts from the beginning, reporting the whole environment, and
+// false is returned. An empty position always starts from the
+// beginning.
+func (st *State) WatchFrom(position string) (*Multiwatcher, bool, error) {
+	return newMultiwatcherFrom(st.getAllManager(), position)
+}
+
+func (st *State) getAllManager() *storeManager {
 	st.mu.Lock()
+	defer st.mu.Unlock()
 	if st.allManager == nil {
 		st.allManager = newStoreManager(newAllWatcherStateBacking(st))
 	}
-	st.mu.Unlock()
-	return NewMultiwatcher(st.allManager)
+	return st.allManager
 }
 
 func (st *State) WatchAllEnvs() *Multiwatcher {
@@ -1427,6 +1442,22 @@ func (st *State) addMachineWithPlacement(unit *Unit, placement *instance.Placeme
 	// transaction as adding a machine.  See bug
 	// https://launchpad.net/bugs/1506994
 
+	// Honour drains and anti-affinity constraints for any existing
+	// machine that was explicitly requested, whether directly or as a
+	// container host.
+	if data.machineId != "" {
+		m, err := st.Machine(data.machineId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if m.Unschedulable() {
+			return nil, errors.Errorf("machine %q is unschedulable", m.Id())
+		}
+		if err := checkAntiAffinity(unit, data.machineId); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
 	switch data.placementType() {
 	case containerPlacement:
 		// If a container is to be used, create it.
@@ -2073,11 +2104,17 @@ func (st *State) AssignUnit(u *Unit, policy AssignmentPolicy) (err error) {
 		}
 		return u.AssignToMachine(m)
 	case AssignClean:
+		if _, err = u.assignToPackedMachine(); err != noCleanMachines {
+			return errors.Trace(err)
+		}
 		if _, err = u.AssignToCleanMachine(); err != noCleanMachines {
 			return errors.Trace(err)
 		}
 		return u.AssignToNewMachineOrContainer()
 	case AssignCleanEmpty:
+		if _, err = u.assignToPackedMachine(); err != noCleanMachines {
+			return errors.Trace(err)
+		}
 		if _, err = u.AssignToCleanEmptyMachine(); err != noCleanMachines {
 			return errors.Trace(err)
 		}
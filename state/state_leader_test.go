@@ -6,9 +6,11 @@ package state_test
 import (
 	"time"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/leadership"
 	coretesting "github.com/juju/juju/testing"
 )
 
@@ -37,3 +39,41 @@ func (s *StateLeadershipSuite) TestHackLeadershipUnblocksClaimer(c *gc.C) {
 	case <-unblocked:
 	}
 }
+
+func (s *StateLeadershipSuite) TestLeadershipInspector(c *gc.C) {
+	err := s.State.LeadershipClaimer().ClaimLeadership("blah", "blah/0", time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+
+	leases, err := s.State.LeadershipInspector().Leases()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(leases, gc.HasLen, 1)
+	c.Assert(leases["blah"].Holder, gc.Equals, "blah/0")
+	c.Assert(s.State.LeadershipNamespace(), gc.Equals, "service-leadership")
+}
+
+func (s *StateLeadershipSuite) TestLeadershipRevoker(c *gc.C) {
+	claimer := s.State.LeadershipClaimer()
+	err := claimer.ClaimLeadership("blah", "blah/0", time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.LeadershipRevoker().RevokeLeadership("blah")
+	c.Assert(err, jc.ErrorIsNil)
+	leases, err := s.State.LeadershipInspector().Leases()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(leases, gc.HasLen, 0)
+
+	// No unit can claim leadership until the revoked lease expires.
+	err = claimer.ClaimLeadership("blah", "blah/0", time.Minute)
+	c.Assert(err, gc.Equals, leadership.ErrClaimDenied)
+	err = claimer.ClaimLeadership("blah", "blah/1", time.Minute)
+	c.Assert(err, gc.Equals, leadership.ErrClaimDenied)
+	err = s.State.LeadershipChecker().LeadershipCheck("blah", "blah/0").Check(nil)
+	c.Assert(err, gc.ErrorMatches, `"blah/0" is not leader of "blah"`)
+
+	// Revoking it again finds no leader.
+	err = s.State.LeadershipRevoker().RevokeLeadership("blah")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.LeadershipRevoker().RevokeLeadership("unknown")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -4,9 +4,11 @@
 package state
 
 import (
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	jujutxn "github.com/juju/txn"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
@@ -242,6 +244,128 @@ func statusHistory(st *State, globalKey string, size int) ([]StatusInfo, error)
 	return results, nil
 }
 
+// HistoricalStatus is an entry in the status history of an
+// environment.
+type HistoricalStatus struct {
+	StatusInfo
+
+	// Tag identifies the machine, service or unit whose status
+	// changed.
+	Tag names.Tag
+
+	// Agent is true if the entry records the status of a unit's
+	// agent, rather than of its workload.
+	Agent bool
+}
+
+// StatusHistoryFilter restricts the entries returned by
+// EnvironStatusHistory.
+type StatusHistoryFilter struct {
+	// Size is the maximum number of entries to return. The most
+	// recent matching entries are returned.
+	Size int
+
+	// From and To, if not zero, exclude entries recorded before
+	// and after the given times respectively.
+	From time.Time
+	To   time.Time
+
+	// Statuses, if not empty, excludes entries with any other
+	// status.
+	Statuses []Status
+}
+
+// environStatusHistoryKeys matches the global keys of the machines,
+// services, units and unit agents whose status history makes up the
+// status history of an environment, along with the keys under which
+// service leadership changes are recorded.
+const environStatusHistoryKeys = `^(m#[^#]+|s#[^#]+(#leader)?|u#[^#]+(#charm)?)$`
+
+// EnvironStatusHistory returns at most filter.Size of the most recent
+// matching status history entries of all the machines, services, units
+// and unit agents in the environment, oldest first.
+func (st *State) EnvironStatusHistory(filter StatusHistoryFilter) ([]HistoricalStatus, error) {
+	if filter.Size < 1 {
+		return nil, errors.NotValidf("history size %d", filter.Size)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return nil, errors.NotValidf("history ending before it starts")
+	}
+	query := bson.D{{"globalkey", bson.RegEx{Pattern: environStatusHistoryKeys}}}
+	updated := bson.M{}
+	if !filter.From.IsZero() {
+		updated["$gte"] = filter.From.UnixNano()
+	}
+	if !filter.To.IsZero() {
+		updated["$lte"] = filter.To.UnixNano()
+	}
+	if len(updated) > 0 {
+		query = append(query, bson.DocElem{"updated", updated})
+	}
+	if len(filter.Statuses) > 0 {
+		query = append(query, bson.DocElem{"status", bson.M{"$in": filter.Statuses}})
+	}
+
+	history, closer := st.getCollection(statusesHistoryC)
+	defer closer()
+
+	var docs []historicalStatusDoc
+	if err := history.Find(query).Sort("-updated").Limit(filter.Size).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get environment status history")
+	}
+	results := make([]HistoricalStatus, 0, len(docs))
+	for i := len(docs) - 1; i >= 0; i-- {
+		doc := docs[i]
+		tag, agent, ok := statusHistoryEntity(doc.GlobalKey)
+		if !ok {
+			continue
+		}
+		results = append(results, HistoricalStatus{
+			StatusInfo: StatusInfo{
+				Status:  doc.Status,
+				Message: doc.StatusInfo,
+				Data:    unescapeKeys(doc.StatusData),
+				Since:   unixNanoToTime(doc.Updated),
+			},
+			Tag:   tag,
+			Agent: agent,
+		})
+	}
+	return results, nil
+}
+
+// statusHistoryEntity returns the tag of the entity whose status is
+// recorded under the given global key, and whether that status is
+// that of a unit agent. It returns false if the key is not that of a
+// machine, service, unit or unit agent.
+func statusHistoryEntity(globalKey string) (names.Tag, bool, bool) {
+	if len(globalKey) < 3 || globalKey[1] != '#' {
+		return nil, false, false
+	}
+	id := globalKey[2:]
+	switch globalKey[0] {
+	case 'm':
+		if names.IsValidMachine(id) {
+			return names.NewMachineTag(id), false, true
+		}
+	case 's':
+		// Leadership changes are recorded as history of the service.
+		id = strings.TrimSuffix(id, "#leader")
+		if names.IsValidService(id) {
+			return names.NewServiceTag(id), false, true
+		}
+	case 'u':
+		// Unit workload statuses are recorded under the unit's
+		// key, and unit agent statuses under the agent's.
+		agent := !strings.HasSuffix(id, "#charm")
+		id = strings.TrimSuffix(id, "#charm")
+		if names.IsValidUnit(id) {
+			return names.NewUnitTag(id), agent, true
+		}
+	}
+	return nil, false, false
+}
+
 // PruneStatusHistory removes status history entries until
 // only the maxLogsPerEntity newest records per unit remain.
 func PruneStatusHistory(st *State, maxLogsPerEntity int) error {
@@ -4,6 +4,9 @@
 package state_test
 
 import (
+	"time"
+
+	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -92,3 +95,86 @@ func (s *StatusHistorySuite) TestPruneStatusHistory(c *gc.C) {
 		checkPrimedUnitAgentStatus(c, statusInfo, 9-i)
 	}
 }
+
+func (s *StatusHistorySuite) TestEnvironStatusHistory(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Machine: machine})
+	err := unit.SetStatus(state.StatusBlocked, "waiting for db", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	mid := time.Now()
+	err = unit.Agent().SetStatus(state.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetStatus(state.StatusError, "disk full", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	type entry struct {
+		tag    names.Tag
+		agent  bool
+		status state.Status
+	}
+	check := func(filter state.StatusHistoryFilter, expected ...entry) {
+		history, err := s.State.EnvironStatusHistory(filter)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(history, gc.HasLen, len(expected))
+		for i, h := range history {
+			c.Check(h.Tag, gc.Equals, expected[i].tag)
+			c.Check(h.Agent, gc.Equals, expected[i].agent)
+			c.Check(h.Status, gc.Equals, expected[i].status)
+			c.Check(h.Since, gc.NotNil)
+		}
+	}
+	blocked := entry{unit.UnitTag(), false, state.StatusBlocked}
+	idle := entry{unit.UnitTag(), true, state.StatusIdle}
+	failed := entry{machine.MachineTag(), false, state.StatusError}
+
+	// The most recent entries are returned, oldest first.
+	check(state.StatusHistoryFilter{Size: 3}, blocked, idle, failed)
+	check(state.StatusHistoryFilter{Size: 10, From: mid}, idle, failed)
+	check(state.StatusHistoryFilter{
+		Size:     10,
+		Statuses: []state.Status{state.StatusBlocked, state.StatusError},
+	}, blocked, failed)
+
+	// Earlier entries include those of the service, and those
+	// recorded when each entity was created.
+	history, err := s.State.EnvironStatusHistory(state.StatusHistoryFilter{Size: 10, To: mid})
+	c.Assert(err, jc.ErrorIsNil)
+	kinds := make(map[string]bool)
+	for _, h := range history {
+		kinds[h.Tag.Kind()] = true
+	}
+	c.Assert(kinds, jc.DeepEquals, map[string]bool{
+		names.MachineTagKind: true,
+		names.ServiceTagKind: true,
+		names.UnitTagKind:    true,
+	})
+	c.Assert(history[len(history)-1].Status, gc.Equals, state.StatusBlocked)
+}
+
+func (s *StatusHistorySuite) TestEnvironStatusHistoryIncludesLeadershipChanges(c *gc.C) {
+	service := s.Factory.MakeService(c, nil)
+	err := s.State.LeadershipClaimer().ClaimLeadership(service.Name(), service.Name()+"/0", time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.State.EnvironStatusHistory(state.StatusHistoryFilter{
+		Size:     10,
+		Statuses: []state.Status{state.StatusLeaderElected},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Check(history[0].Tag, gc.Equals, service.Tag())
+	c.Check(history[0].Agent, jc.IsFalse)
+	c.Check(history[0].Message, gc.Equals, service.Name()+"/0 elected leader")
+}
+
+func (s *StatusHistorySuite) TestEnvironStatusHistoryInvalidFilter(c *gc.C) {
+	_, err := s.State.EnvironStatusHistory(state.StatusHistoryFilter{})
+	c.Assert(err, gc.ErrorMatches, "history size 0 not valid")
+	now := time.Now()
+	_, err = s.State.EnvironStatusHistory(state.StatusHistoryFilter{
+		Size: 1,
+		From: now,
+		To:   now.Add(-time.Minute),
+	})
+	c.Assert(err, gc.ErrorMatches, "history ending before it starts not valid")
+}
@@ -128,6 +128,10 @@ type storageInstanceDoc struct {
 	StorageName     string      `bson:"storagename"`
 	AttachmentCount int         `bson:"attachmentcount"`
 	CharmURL        *charm.URL  `bson:"charmurl"`
+
+	// Resize is the ID of the pending request to resize the
+	// storage instance, if any.
+	Resize string `bson:"resize,omitempty"`
 }
 
 type storageAttachment struct {
@@ -688,6 +692,17 @@ func removeStorageAttachmentOps(
 			return ops, nil
 		}
 	}
+	if si.doc.AttachmentCount == 1 && si.doc.Life == Alive {
+		// The storage instance has been detached from the unit,
+		// and will outlive it; detach the storage instance's
+		// volume or filesystem from the unit's machine so that
+		// it may be attached to another unit.
+		detachOps, err := detachUnitMachineStorageOps(st, si, names.NewUnitTag(s.doc.Unit))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, detachOps...)
+	}
 	decrefOp := txn.Op{
 		C:      storageInstancesC,
 		Id:     si.doc.Id,
@@ -756,6 +771,11 @@ type StorageConstraints struct {
 
 	// Count is the required number of storage instances.
 	Count uint64 `bson:"count"`
+
+	// snapshot, if non-empty, is the ID of the volume snapshot from
+	// which the storage instances' volumes are to be created. It is
+	// set only by RestoreVolumeSnapshot, and is never stored.
+	snapshot string
 }
 
 func createStorageConstraintsOp(key string, cons map[string]StorageConstraints) txn.Op {
@@ -941,6 +961,15 @@ func poolStorageProvider(st *State, poolName string) (storage.ProviderType, stor
 			// so return the original "pool not found" error.
 			return "", nil, errors.Trace(err)
 		}
+		// The provider is used without any pool attributes, so
+		// providers that require them cannot be used directly.
+		cfg, err := storage.NewConfig(poolName, providerType, map[string]interface{}{})
+		if err != nil {
+			return "", nil, errors.Trace(err)
+		}
+		if err := provider.ValidateConfig(cfg); err != nil {
+			return "", nil, errors.Annotatef(err, "invalid storage provider %q", providerType)
+		}
 		return providerType, provider, nil
 	} else if err != nil {
 		return "", nil, errors.Trace(err)
@@ -1203,6 +1232,12 @@ func (st *State) constructAddUnitStorageOps(
 	return append(ops, storageOps...), nil
 }
 
+func (st *State) countEntityStorageInstances(tag names.Tag) (int, error) {
+	storageCollection, closer := st.getCollection(storageInstancesC)
+	defer closer()
+	return storageCollection.Find(bson.D{{"owner", tag.String()}}).Count()
+}
+
 func (st *State) countEntityStorageInstancesForName(
 	tag names.Tag,
 	name string,
@@ -526,6 +526,17 @@ func (s *StorageStateSuite) TestProviderFallbackToType(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *StorageStateSuite) TestProviderFallbackToTypeValidated(c *gc.C) {
+	ch := s.AddTestingCharm(c, "storage-block")
+	_, err := s.State.AddService(state.AddServiceArgs{
+		Name: "storage-block", Owner: "user-test-admin@local", Charm: ch,
+		Storage: map[string]state.StorageConstraints{
+			"data": makeStorageCons("lvm", 1024, 1),
+		},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add service "storage-block": invalid storage provider "lvm": volume group not specified`)
+}
+
 func (s *StorageStateSuite) TestAddUnit(c *gc.C) {
 	s.assertStorageUnitsAdded(c)
 }
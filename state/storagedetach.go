@@ -0,0 +1,392 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// DetachStorage detaches the storage instance from the specified unit,
+// transferring ownership of the storage instance to the unit's service.
+//
+// The unit's storage attachment is destroyed, as it would be by
+// DestroyStorageAttachment, but the storage instance and its volume or
+// filesystem will outlive the unit. When the storage attachment is
+// removed, the volume or filesystem is detached from the unit's machine,
+// and may then be attached to another unit of the same service with
+// AttachStorage. Detached storage is destroyed along with its service.
+//
+// Storage may be detached from a Dying unit, so long as the unit has
+// not yet finished with the storage attachment.
+func (st *State) DetachStorage(storage names.StorageTag, unit names.UnitTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot detach storage %s from unit %s", storage.Id(), unit.Id())
+	serviceName, err := names.UnitService(unit.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	serviceTag := names.NewServiceTag(serviceName)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		s, err := st.storageInstance(storage)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.doc.Life != Alive {
+			return nil, errors.New("storage is not alive")
+		}
+		if s.doc.Owner != unit.String() {
+			if attempt > 0 && s.doc.Owner == serviceTag.String() {
+				// The storage was detached concurrently.
+				return nil, jujutxn.ErrNoOperations
+			}
+			return nil, errors.Errorf("storage is not owned by unit %s", unit.Id())
+		}
+		if err := validateStorageDetachable(st, s); err != nil {
+			return nil, errors.Trace(err)
+		}
+		a, err := st.storageAttachment(storage, unit)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:      storageInstancesC,
+			Id:     s.doc.Id,
+			Assert: bson.D{{"life", Alive}, {"owner", unit.String()}},
+			Update: bson.D{{"$set", bson.D{{"owner", serviceTag.String()}}}},
+		}}
+		if a.doc.Life == Alive {
+			ops = append(ops, destroyStorageAttachmentOps(storage, unit)...)
+		} else {
+			// The storage attachment is already Dying; we must
+			// only ensure it is not removed before ownership of
+			// the storage instance is transferred.
+			ops = append(ops, txn.Op{
+				C:      storageAttachmentsC,
+				Id:     storageAttachmentId(unit.Id(), storage.Id()),
+				Assert: txn.DocExists,
+			})
+		}
+		return ops, nil
+	}
+	return st.run(buildTxn)
+}
+
+// validateStorageDetachable returns an error if the volume or filesystem
+// assigned to the storage instance cannot be moved between machines.
+func validateStorageDetachable(st *State, s *storageInstance) error {
+	var machineScoped []names.Tag
+	switch s.Kind() {
+	case StorageKindBlock:
+		v, err := st.storageInstanceVolume(s.StorageTag())
+		if errors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if _, ok := names.VolumeMachine(v.VolumeTag()); ok {
+			machineScoped = append(machineScoped, v.VolumeTag())
+		}
+	case StorageKindFilesystem:
+		f, err := st.storageInstanceFilesystem(s.StorageTag())
+		if errors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if _, ok := names.FilesystemMachine(f.FilesystemTag()); ok {
+			machineScoped = append(machineScoped, f.FilesystemTag())
+		}
+		volumeTag, err := f.Volume()
+		if err == nil {
+			if _, ok := names.VolumeMachine(volumeTag); ok {
+				machineScoped = append(machineScoped, volumeTag)
+			}
+		} else if err != ErrNoBackingVolume {
+			return errors.Trace(err)
+		}
+	default:
+		return errors.Errorf("invalid storage kind %v", s.Kind())
+	}
+	if len(machineScoped) > 0 {
+		return errors.Errorf(
+			"%s is machine-scoped, storage cannot be detached",
+			names.ReadableString(machineScoped[0]),
+		)
+	}
+	return nil
+}
+
+// AttachStorage attaches storage that was previously detached with
+// DetachStorage to the specified unit, which must belong to the service
+// that owns the storage instance. Ownership of the storage instance is
+// transferred to the unit, and if the unit is assigned to a machine, the
+// storage instance's existing volume or filesystem will be attached to
+// the machine.
+func (st *State) AttachStorage(storage names.StorageTag, unit names.UnitTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot attach storage %s to unit %s", storage.Id(), unit.Id())
+	u, err := st.Unit(unit.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := u.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if u.Life() != Alive {
+			return nil, unitNotAliveErr
+		}
+		s, err := st.storageInstance(storage)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if attempt > 0 && s.doc.Owner == unit.String() {
+			// The storage was attached concurrently.
+			return nil, jujutxn.ErrNoOperations
+		}
+		serviceTag := names.NewServiceTag(u.ServiceName())
+		if err := validateStorageAttachable(s, serviceTag); err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops, err := st.attachStorageOps(u, s)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return ops, nil
+	}
+	return st.run(buildTxn)
+}
+
+func (st *State) attachStorageOps(u *Unit, s *storageInstance) ([]txn.Op, error) {
+	svc, err := u.Service()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ch, _, err := svc.Charm()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	charmMeta := ch.Meta()
+	charmStorage, ok := charmMeta.Storage[s.doc.StorageName]
+	if !ok {
+		return nil, errors.NotFoundf("charm storage %q", s.doc.StorageName)
+	}
+	if charmStorage.CountMax >= 0 {
+		count, err := st.countEntityStorageInstancesForName(u.Tag(), s.doc.StorageName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if count+1 > uint64(charmStorage.CountMax) {
+			return nil, errors.Errorf(
+				"charm %q store %q: at most %d instances supported",
+				charmMeta.Name, s.doc.StorageName, charmStorage.CountMax,
+			)
+		}
+	}
+
+	unitTag := u.UnitTag()
+	attachmentsUnchanged := bson.D{{"storageattachmentcount", u.doc.StorageAttachmentCount}}
+	ops := []txn.Op{
+		createStorageAttachmentOp(s.StorageTag(), unitTag),
+		attachStorageInstanceOp(s, unitTag),
+		{
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: append(attachmentsUnchanged, isAliveDoc...),
+			Update: bson.D{{"$inc", bson.D{{"storageattachmentcount", 1}}}},
+		},
+	}
+
+	// If the unit is already assigned to a machine, attach the storage
+	// instance's volume or filesystem to it.
+	cons, err := u.StorageConstraints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	attached := *s
+	attached.doc.Owner = unitTag.String()
+	machineOps, err := unitAssignedMachineStorageOps(
+		st, unitTag, charmMeta, cons, u.Series(), &attached,
+	)
+	if err == nil {
+		ops = append(ops, machineOps...)
+	} else if !errors.IsNotAssigned(err) {
+		return nil, errors.Annotate(err, "attaching machine storage")
+	}
+	return ops, nil
+}
+
+// validateStorageAttachable returns an error if the storage instance
+// cannot be attached to a unit of the specified service.
+func validateStorageAttachable(s *storageInstance, service names.ServiceTag) error {
+	if s.doc.Life != Alive {
+		return errors.New("storage is not alive")
+	}
+	if s.doc.Owner != service.String() {
+		return errors.Errorf("storage is not detached from service %q", service.Id())
+	}
+	if s.doc.AttachmentCount > 0 {
+		return errors.New("storage is still attached to another unit")
+	}
+	return nil
+}
+
+// attachStorageInstanceOp returns a txn.Op to transfer ownership of a
+// detached storage instance to the specified unit, accounting for the
+// unit's new storage attachment. The caller is responsible for creating
+// the storage attachment.
+func attachStorageInstanceOp(s *storageInstance, unit names.UnitTag) txn.Op {
+	return txn.Op{
+		C:  storageInstancesC,
+		Id: s.doc.Id,
+		Assert: bson.D{
+			{"life", Alive},
+			{"owner", s.doc.Owner},
+			{"attachmentcount", 0},
+		},
+		Update: bson.D{
+			{"$set", bson.D{{"owner", unit.String()}}},
+			{"$inc", bson.D{{"attachmentcount", 1}}},
+		},
+	}
+}
+
+// unitAttachStorageOps returns txn.Ops to attach the specified detached
+// storage instances to a new unit of the service, along with the unit's
+// storage constraints reduced by the attached storage, so that storage
+// is not created in place of that being attached.
+func (s *Service) unitAttachStorageOps(
+	unitName string,
+	storage []names.StorageTag,
+	cons map[string]StorageConstraints,
+) ([]txn.Op, map[string]StorageConstraints, error) {
+	ch, _, err := s.Charm()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	charmMeta := ch.Meta()
+	serviceTag := names.NewServiceTag(s.doc.Name)
+	unitTag := names.NewUnitTag(unitName)
+
+	counts := make(map[string]uint64)
+	ops := make([]txn.Op, 0, len(storage)*2)
+	for _, tag := range storage {
+		si, err := s.st.storageInstance(tag)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		if err := validateStorageAttachable(si, serviceTag); err != nil {
+			return nil, nil, errors.Annotatef(err, "cannot attach storage %s", tag.Id())
+		}
+		charmStorage, ok := charmMeta.Storage[si.doc.StorageName]
+		if !ok {
+			return nil, nil, errors.NotFoundf("charm storage %q", si.doc.StorageName)
+		}
+		counts[si.doc.StorageName]++
+		if charmStorage.CountMax >= 0 && counts[si.doc.StorageName] > uint64(charmStorage.CountMax) {
+			return nil, nil, errors.Errorf(
+				"charm %q store %q: at most %d instances supported",
+				charmMeta.Name, si.doc.StorageName, charmStorage.CountMax,
+			)
+		}
+		ops = append(ops,
+			createStorageAttachmentOp(tag, unitTag),
+			attachStorageInstanceOp(si, unitTag),
+		)
+	}
+
+	remaining := make(map[string]StorageConstraints)
+	for name, c := range cons {
+		if n := counts[name]; n >= c.Count {
+			c.Count = 0
+		} else {
+			c.Count -= n
+		}
+		remaining[name] = c
+	}
+	return ops, remaining, nil
+}
+
+// detachUnitMachineStorageOps returns txn.Ops to detach the volume or
+// filesystem assigned to the storage instance from the machine that the
+// unit is assigned to, if it is attached.
+func detachUnitMachineStorageOps(st *State, si *storageInstance, unit names.UnitTag) ([]txn.Op, error) {
+	u, err := st.Unit(unit.Id())
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machineId, err := u.AssignedMachineId()
+	if errors.IsNotAssigned(err) || errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machineTag := names.NewMachineTag(machineId)
+
+	switch si.Kind() {
+	case StorageKindBlock:
+		v, err := st.storageInstanceVolume(si.StorageTag())
+		if errors.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		va, err := st.VolumeAttachment(machineTag, v.VolumeTag())
+		if errors.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if va.Life() != Alive {
+			return nil, nil
+		}
+		return detachVolumeOps(machineTag, v.VolumeTag()), nil
+	case StorageKindFilesystem:
+		// Detaching a volume-backed filesystem will cause the
+		// volume to be detached once the filesystem attachment
+		// is removed.
+		f, err := st.storageInstanceFilesystem(si.StorageTag())
+		if errors.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		fa, err := st.FilesystemAttachment(machineTag, f.FilesystemTag())
+		if errors.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if fa.Life() != Alive {
+			return nil, nil
+		}
+		return detachFilesystemOps(machineTag, f.FilesystemTag()), nil
+	}
+	return nil, errors.Errorf("invalid storage kind %v", si.Kind())
+}
+
+// destroyServiceStorage destroys all storage instances owned by the
+// specified service, i.e. storage that was detached from the service's
+// units and not subsequently reattached.
+func (st *State) destroyServiceStorage(service names.ServiceTag) (err error) {
+	coll, closer := st.getCollection(storageInstancesC)
+	defer closer()
+
+	var doc storageInstanceDoc
+	fields := bson.D{{"id", 1}}
+	iter := coll.Find(bson.D{{"owner", service.String()}}).Select(fields).Iter()
+	defer closeIter(iter, &err, "reading storage instance document")
+	for iter.Next(&doc) {
+		if err := st.DestroyStorageInstance(names.NewStorageTag(doc.Id)); err != nil {
+			return errors.Annotate(err, "destroying storage")
+		}
+	}
+	return nil
+}
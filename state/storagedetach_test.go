@@ -0,0 +1,203 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type StorageDetachStateSuite struct {
+	StorageStateSuiteBase
+}
+
+var _ = gc.Suite(&StorageDetachStateSuite{})
+
+// setupAssignedStorage adds a unit with a single storage instance of
+// the given kind, and assigns the unit to a machine.
+func (s *StorageDetachStateSuite) setupAssignedStorage(c *gc.C, kind, pool string) (*state.Service, *state.Unit, names.StorageTag) {
+	service, u, storageTag := s.setupSingleStorage(c, kind, pool)
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	return service, u, storageTag
+}
+
+// detachStorage detaches the storage from the unit, and removes the
+// unit's storage attachment.
+func (s *StorageDetachStateSuite) detachStorage(c *gc.C, storageTag names.StorageTag, u *state.Unit) {
+	err := s.State.DetachStorage(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveStorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *StorageDetachStateSuite) assertStorageOwner(c *gc.C, storageTag names.StorageTag, owner names.Tag) {
+	si, err := s.State.StorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(si.Owner(), gc.Equals, owner)
+	c.Assert(si.Life(), gc.Equals, state.Alive)
+}
+
+func (s *StorageDetachStateSuite) TestDetachStorageVolume(c *gc.C) {
+	service, u, storageTag := s.setupAssignedStorage(c, "block", "persistent-block")
+	machineId, err := u.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	machineTag := names.NewMachineTag(machineId)
+	volume := s.storageInstanceVolume(c, storageTag)
+
+	err = s.State.DetachStorage(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStorageOwner(c, storageTag, service.Tag())
+	att, err := s.State.StorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(att.Life(), gc.Equals, state.Dying)
+
+	// Removing the storage attachment leaves the storage instance and
+	// its volume intact, but detaches the volume from the machine.
+	err = s.State.RemoveStorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStorageOwner(c, storageTag, service.Tag())
+	volume = s.volume(c, volume.VolumeTag())
+	c.Assert(volume.Life(), gc.Equals, state.Alive)
+	volumeAttachment := s.volumeAttachment(c, machineTag, volume.VolumeTag())
+	c.Assert(volumeAttachment.Life(), gc.Equals, state.Dying)
+}
+
+func (s *StorageDetachStateSuite) TestDetachStorageFilesystem(c *gc.C) {
+	service, u, storageTag := s.setupAssignedStorage(c, "filesystem", "environscoped")
+	machineId, err := u.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	machineTag := names.NewMachineTag(machineId)
+	filesystem := s.storageInstanceFilesystem(c, storageTag)
+
+	s.detachStorage(c, storageTag, u)
+	s.assertStorageOwner(c, storageTag, service.Tag())
+	filesystem = s.filesystem(c, filesystem.FilesystemTag())
+	c.Assert(filesystem.Life(), gc.Equals, state.Alive)
+	filesystemAttachment := s.filesystemAttachment(c, machineTag, filesystem.FilesystemTag())
+	c.Assert(filesystemAttachment.Life(), gc.Equals, state.Dying)
+}
+
+func (s *StorageDetachStateSuite) TestDetachStorageDyingUnit(c *gc.C) {
+	service, u, storageTag := s.setupAssignedStorage(c, "block", "persistent-block")
+	err := u.SetAgentStatus(state.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.DestroyUnitStorageAttachments(u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.detachStorage(c, storageTag, u)
+	s.assertStorageOwner(c, storageTag, service.Tag())
+}
+
+func (s *StorageDetachStateSuite) TestDetachStorageMachineScoped(c *gc.C) {
+	_, u, storageTag := s.setupAssignedStorage(c, "block", "loop-pool")
+	err := s.State.DetachStorage(storageTag, u.UnitTag())
+	c.Assert(err, gc.ErrorMatches, `cannot detach storage data/0 from unit storage-block/0: volume 0/0 is machine-scoped, storage cannot be detached`)
+	s.assertStorageOwner(c, storageTag, u.Tag())
+}
+
+func (s *StorageDetachStateSuite) TestDetachStorageNotOwned(c *gc.C) {
+	service, _, storageTag := s.setupAssignedStorage(c, "block", "persistent-block")
+	u2, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.DetachStorage(storageTag, u2.UnitTag())
+	c.Assert(err, gc.ErrorMatches, `cannot detach storage data/0 from unit storage-block/1: storage is not owned by unit storage-block/1`)
+}
+
+func (s *StorageDetachStateSuite) TestDetachStorageNotFound(c *gc.C) {
+	_, u, _ := s.setupAssignedStorage(c, "block", "persistent-block")
+	err := s.State.DetachStorage(names.NewStorageTag("data/1"), u.UnitTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *StorageDetachStateSuite) TestAddUnitAttachingStorage(c *gc.C) {
+	service, u, storageTag := s.setupAssignedStorage(c, "block", "persistent-block")
+	volume := s.storageInstanceVolume(c, storageTag)
+	s.detachStorage(c, storageTag, u)
+
+	u2, err := service.AddUnitAttachingStorage([]names.StorageTag{storageTag})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStorageOwner(c, storageTag, u2.Tag())
+
+	// No storage is created for the new unit in place of the
+	// attached storage.
+	attachments, err := s.State.UnitStorageAttachments(u2.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 1)
+	c.Assert(attachments[0].StorageInstance(), gc.Equals, storageTag)
+
+	// Assigning the unit to a machine attaches the existing volume.
+	err = s.State.AssignUnit(u2, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := u2.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	volumeAttachment := s.volumeAttachment(c, names.NewMachineTag(machineId), volume.VolumeTag())
+	c.Assert(volumeAttachment.Life(), gc.Equals, state.Alive)
+	c.Assert(s.storageInstanceVolume(c, storageTag).VolumeTag(), gc.Equals, volume.VolumeTag())
+}
+
+func (s *StorageDetachStateSuite) TestAddUnitAttachingStorageNotDetached(c *gc.C) {
+	service, _, storageTag := s.setupAssignedStorage(c, "block", "persistent-block")
+	_, err := service.AddUnitAttachingStorage([]names.StorageTag{storageTag})
+	c.Assert(err, gc.ErrorMatches, `cannot add unit to service "storage-block": cannot attach storage data/0: storage is not detached from service "storage-block"`)
+}
+
+func (s *StorageDetachStateSuite) TestAttachStorageAssignedUnit(c *gc.C) {
+	_, u, storageTag := s.setupAssignedStorage(c, "block", "persistent-block")
+	machineId, err := u.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	machineTag := names.NewMachineTag(machineId)
+	volume := s.storageInstanceVolume(c, storageTag)
+	s.detachStorage(c, storageTag, u)
+	err = s.State.RemoveVolumeAttachment(machineTag, volume.VolumeTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.AttachStorage(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStorageOwner(c, storageTag, u.Tag())
+	att, err := s.State.StorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(att.Life(), gc.Equals, state.Alive)
+	volumeAttachment := s.volumeAttachment(c, machineTag, volume.VolumeTag())
+	c.Assert(volumeAttachment.Life(), gc.Equals, state.Alive)
+}
+
+func (s *StorageDetachStateSuite) TestAttachStorageCountExceeded(c *gc.C) {
+	service, u, storageTag := s.setupAssignedStorage(c, "block", "persistent-block")
+	u2, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	s.detachStorage(c, storageTag, u)
+
+	err = s.State.AttachStorage(storageTag, u2.UnitTag())
+	c.Assert(err, gc.ErrorMatches, `cannot attach storage data/0 to unit storage-block/1: charm "storage-block" store "data": at most 1 instances supported`)
+}
+
+func (s *StorageDetachStateSuite) TestAttachStorageStillAttached(c *gc.C) {
+	service, u, storageTag := s.setupAssignedStorage(c, "block", "persistent-block")
+	err := s.State.DetachStorage(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = service.AddUnitAttachingStorage([]names.StorageTag{storageTag})
+	c.Assert(err, gc.ErrorMatches, `.*storage is still attached to another unit`)
+}
+
+func (s *StorageDetachStateSuite) TestDetachedStorageDestroyedWithService(c *gc.C) {
+	service, u, storageTag := s.setupAssignedStorage(c, "block", "persistent-block")
+	s.detachStorage(c, storageTag, u)
+	err := u.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStorageOwner(c, storageTag, service.Tag())
+
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.StorageInstance(storageTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
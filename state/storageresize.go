@@ -0,0 +1,358 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// StorageResize describes a pending request to grow the volume or
+// filesystem assigned to a storage instance.
+//
+// Resize requests are scoped in the same way as the storage they
+// resize: requests for machine-scoped volumes and filesystems have
+// IDs of the form "<machine>/<n>"; all others have IDs of the form
+// "<n>". A request is removed once the storage has been resized.
+type StorageResize interface {
+	Lifer
+
+	// Id returns the unique ID of the resize request.
+	Id() string
+
+	// Machine returns the tag of the machine that the request is
+	// scoped to, and true; or false if the request is not scoped
+	// to a machine.
+	Machine() (names.MachineTag, bool)
+
+	// StorageInstance returns the tag of the storage instance being
+	// resized.
+	StorageInstance() names.StorageTag
+
+	// Volume returns the tag of the volume to grow, and true; or
+	// false if the storage is a filesystem not backed by a volume.
+	Volume() (names.VolumeTag, bool)
+
+	// Filesystem returns the tag of the filesystem being resized,
+	// and true; or false if the storage is a block device.
+	Filesystem() (names.FilesystemTag, bool)
+
+	// Size returns the requested size of the storage, in MiB.
+	Size() uint64
+}
+
+type storageResize struct {
+	doc storageResizeDoc
+}
+
+// storageResizeDoc records a request to resize a storage instance.
+type storageResizeDoc struct {
+	DocID      string `bson:"_id"`
+	Name       string `bson:"name"`
+	EnvUUID    string `bson:"env-uuid"`
+	Life       Life   `bson:"life"`
+	StorageId  string `bson:"storageid"`
+	Volume     string `bson:"volumeid,omitempty"`
+	Filesystem string `bson:"filesystemid,omitempty"`
+	Size       uint64 `bson:"size"`
+}
+
+// Id is required to implement StorageResize.
+func (r *storageResize) Id() string {
+	return r.doc.Name
+}
+
+// Machine is required to implement StorageResize.
+func (r *storageResize) Machine() (names.MachineTag, bool) {
+	return StorageResizeMachine(r.doc.Name)
+}
+
+// StorageInstance is required to implement StorageResize.
+func (r *storageResize) StorageInstance() names.StorageTag {
+	return names.NewStorageTag(r.doc.StorageId)
+}
+
+// Volume is required to implement StorageResize.
+func (r *storageResize) Volume() (names.VolumeTag, bool) {
+	if r.doc.Volume == "" {
+		return names.VolumeTag{}, false
+	}
+	return names.NewVolumeTag(r.doc.Volume), true
+}
+
+// Filesystem is required to implement StorageResize.
+func (r *storageResize) Filesystem() (names.FilesystemTag, bool) {
+	if r.doc.Filesystem == "" {
+		return names.FilesystemTag{}, false
+	}
+	return names.NewFilesystemTag(r.doc.Filesystem), true
+}
+
+// Size is required to implement StorageResize.
+func (r *storageResize) Size() uint64 {
+	return r.doc.Size
+}
+
+// Life is required to implement StorageResize.
+func (r *storageResize) Life() Life {
+	return r.doc.Life
+}
+
+// StorageResizeMachine returns the tag of the machine that the
+// storage resize request with the specified ID is scoped to, if any.
+func StorageResizeMachine(id string) (names.MachineTag, bool) {
+	return machineScopedIdMachine(id)
+}
+
+// IsValidStorageResizeId reports whether the specified string is a
+// valid storage resize request ID.
+func IsValidStorageResizeId(id string) bool {
+	return isValidMachineScopedId(id)
+}
+
+// StorageResize returns the StorageResize with the specified ID.
+func (st *State) StorageResize(id string) (StorageResize, error) {
+	r, err := st.storageResize(id)
+	return r, err
+}
+
+func (st *State) storageResize(id string) (*storageResize, error) {
+	coll, cleanup := st.getCollection(storageResizesC)
+	defer cleanup()
+
+	var r storageResize
+	err := coll.FindId(id).One(&r.doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("storage resize %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "getting storage resize %q", id)
+	}
+	return &r, nil
+}
+
+// ResizeStorageInstance requests that the volume or filesystem
+// assigned to the specified storage instance be grown to the given
+// size, in MiB. The storage must be provisioned, and may not be
+// shrunk. The resize will be carried out by the storage provisioner
+// responsible for the storage, after which the unit that owns the
+// storage will run its "storage-resized" hook.
+//
+// Filesystems backed by volumes are resized by growing the volume;
+// the charm is expected to grow the filesystem itself when handling
+// the "storage-resized" hook.
+func (st *State) ResizeStorageInstance(tag names.StorageTag, size uint64) (_ StorageResize, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot resize storage %s", tag.Id())
+	s, err := st.storageInstance(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if s.Life() != Alive {
+		return nil, errors.New("storage is not alive")
+	}
+	if s.doc.Resize != "" {
+		return nil, errAlreadyResizing
+	}
+
+	doc := storageResizeDoc{
+		StorageId: tag.Id(),
+		Size:      size,
+	}
+	// The storage instance records the pending request, so that at
+	// most one request may be made for it at a time.
+	storageOp := txn.Op{
+		C:  storageInstancesC,
+		Id: tag.Id(),
+		Assert: append(bson.D{
+			{"resize", bson.D{{"$exists", false}}},
+		}, isAliveDoc...),
+	}
+	var ops []txn.Op
+	var scope names.MachineTag
+	var machineScoped bool
+	switch s.Kind() {
+	case StorageKindBlock:
+		v, err := st.storageInstanceVolume(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := validateVolumeResize(v, size); err != nil {
+			return nil, errors.Trace(err)
+		}
+		doc.Volume = v.doc.Name
+		scope, machineScoped = names.VolumeMachine(v.VolumeTag())
+	case StorageKindFilesystem:
+		f, err := st.storageInstanceFilesystem(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if f.Life() != Alive {
+			return nil, errors.New("filesystem is not alive")
+		}
+		info, err := f.Info()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if size <= info.Size {
+			return nil, errors.Errorf("filesystem is already %dMiB, storage can only be grown", info.Size)
+		}
+		doc.Filesystem = f.doc.FilesystemId
+		ops = append(ops, txn.Op{
+			C:      filesystemsC,
+			Id:     f.doc.FilesystemId,
+			Assert: isAliveDoc,
+		})
+		volumeTag, err := f.Volume()
+		if err == ErrNoBackingVolume {
+			scope, machineScoped = names.FilesystemMachine(f.FilesystemTag())
+			break
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		v, err := st.volumeByTag(volumeTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := validateVolumeResize(v, size); err != nil {
+			return nil, errors.Trace(err)
+		}
+		doc.Volume = v.doc.Name
+		scope, machineScoped = names.VolumeMachine(volumeTag)
+	default:
+		return nil, errors.Errorf("invalid storage kind %v", s.Kind())
+	}
+	if doc.Volume != "" {
+		ops = append(ops, txn.Op{
+			C:      volumesC,
+			Id:     doc.Volume,
+			Assert: isAliveDoc,
+		})
+	}
+
+	seq, err := st.sequence("storageresize")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	doc.Name = fmt.Sprint(seq)
+	if machineScoped {
+		doc.Name = scope.Id() + "/" + doc.Name
+	}
+	storageOp.Update = bson.D{{"$set", bson.D{{"resize", doc.Name}}}}
+	ops = append(ops, storageOp, txn.Op{
+		C:      storageResizesC,
+		Id:     doc.Name,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	})
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		if s, err := st.storageInstance(tag); err == nil && s.doc.Resize != "" {
+			return nil, errAlreadyResizing
+		}
+		return nil, errors.New("storage is not alive")
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &storageResize{doc}, nil
+}
+
+var errAlreadyResizing = errors.New("storage is already being resized")
+
+func validateVolumeResize(v *volume, size uint64) error {
+	if v.Life() != Alive {
+		return errors.New("volume is not alive")
+	}
+	info, err := v.Info()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if size <= info.Size {
+		return errors.Errorf("volume is already %dMiB, storage can only be grown", info.Size)
+	}
+	return nil
+}
+
+// CompleteStorageResize records the new size of the volume and
+// filesystem resized by the specified request, and removes the
+// request from state. Volumes and filesystems that have been
+// removed since the request was made are ignored.
+func (st *State) CompleteStorageResize(id string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "completing storage resize %s", id)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		r, err := st.storageResize(id)
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var ops []txn.Op
+		if volumeTag, ok := r.Volume(); ok {
+			v, err := st.volumeByTag(volumeTag)
+			if err == nil {
+				if info, err := v.Info(); err == nil && info.Size < r.Size() {
+					ops = append(ops, txn.Op{
+						C:      volumesC,
+						Id:     volumeTag.Id(),
+						Assert: bson.D{{"info", bson.D{{"$exists", true}}}},
+						Update: bson.D{{"$set", bson.D{{"info.size", r.Size()}}}},
+					})
+				}
+			} else if !errors.IsNotFound(err) {
+				return nil, errors.Trace(err)
+			}
+		}
+		if filesystemTag, ok := r.Filesystem(); ok {
+			f, err := st.filesystemByTag(filesystemTag)
+			if err == nil {
+				if info, err := f.Info(); err == nil && info.Size < r.Size() {
+					ops = append(ops, txn.Op{
+						C:      filesystemsC,
+						Id:     filesystemTag.Id(),
+						Assert: bson.D{{"info", bson.D{{"$exists", true}}}},
+						Update: bson.D{{"$set", bson.D{{"info.size", r.Size()}}}},
+					})
+				}
+			} else if !errors.IsNotFound(err) {
+				return nil, errors.Trace(err)
+			}
+		}
+		s, err := st.storageInstance(r.StorageInstance())
+		if err == nil && s.doc.Resize == id {
+			ops = append(ops, txn.Op{
+				C:      storageInstancesC,
+				Id:     s.doc.Id,
+				Assert: bson.D{{"resize", id}},
+				Update: bson.D{{"$unset", bson.D{{"resize", nil}}}},
+			})
+		} else if err != nil && !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, txn.Op{
+			C:      storageResizesC,
+			Id:     id,
+			Assert: txn.DocExists,
+			Remove: true,
+		})
+		return ops, nil
+	}
+	return st.run(buildTxn)
+}
+
+// WatchEnvironStorageResizes returns a StringsWatcher that notifies
+// of the addition and removal of storage resize requests that are not
+// scoped to a machine.
+func (st *State) WatchEnvironStorageResizes() StringsWatcher {
+	return st.watchEnvironMachineStorage(storageResizesC)
+}
+
+// WatchMachineStorageResizes returns a StringsWatcher that notifies
+// of the addition and removal of storage resize requests scoped to
+// the specified machine.
+func (st *State) WatchMachineStorageResizes(m names.MachineTag) StringsWatcher {
+	return st.watchMachineStorage(m, storageResizesC)
+}
@@ -0,0 +1,180 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+)
+
+type StorageResizeStateSuite struct {
+	StorageStateSuiteBase
+}
+
+var _ = gc.Suite(&StorageResizeStateSuite{})
+
+// setupProvisionedStorage adds a unit with a single provisioned
+// storage instance of the given kind, returning the storage tag.
+func (s *StorageResizeStateSuite) setupProvisionedStorage(c *gc.C, kind, pool string) names.StorageTag {
+	_, u, storageTag := s.setupSingleStorage(c, kind, pool)
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	if kind == "block" {
+		volume := s.storageInstanceVolume(c, storageTag)
+		err = s.State.SetVolumeInfo(volume.VolumeTag(), state.VolumeInfo{VolumeId: "vol-ume", Size: 1024})
+		c.Assert(err, jc.ErrorIsNil)
+		return storageTag
+	}
+	filesystem := s.storageInstanceFilesystem(c, storageTag)
+	if volumeTag, err := filesystem.Volume(); err == nil {
+		err = s.State.SetVolumeInfo(volumeTag, state.VolumeInfo{VolumeId: "vol-ume", Size: 1024})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err = s.State.SetFilesystemInfo(filesystem.FilesystemTag(), state.FilesystemInfo{FilesystemId: "fs-id", Size: 1024})
+	c.Assert(err, jc.ErrorIsNil)
+	return storageTag
+}
+
+func (s *StorageResizeStateSuite) TestResizeStorageInstanceVolume(c *gc.C) {
+	storageTag := s.setupProvisionedStorage(c, "block", "loop-pool")
+
+	r, err := s.State.ResizeStorageInstance(storageTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.Id(), gc.Equals, "0/0")
+	c.Assert(r.Life(), gc.Equals, state.Alive)
+	c.Assert(r.StorageInstance(), gc.Equals, storageTag)
+	c.Assert(r.Size(), gc.Equals, uint64(2048))
+	machineTag, ok := r.Machine()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(machineTag, gc.Equals, names.NewMachineTag("0"))
+	volumeTag, ok := r.Volume()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(volumeTag, gc.Equals, names.NewVolumeTag("0/0"))
+	_, ok = r.Filesystem()
+	c.Assert(ok, jc.IsFalse)
+
+	_, err = s.State.ResizeStorageInstance(storageTag, 4096)
+	c.Assert(err, gc.ErrorMatches, "cannot resize storage data/0: storage is already being resized")
+}
+
+func (s *StorageResizeStateSuite) TestResizeStorageInstanceVolumeBackedFilesystem(c *gc.C) {
+	storageTag := s.setupProvisionedStorage(c, "filesystem", "loop-pool")
+
+	r, err := s.State.ResizeStorageInstance(storageTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.Id(), gc.Equals, "0/0")
+	volumeTag, ok := r.Volume()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(volumeTag, gc.Equals, names.NewVolumeTag("0/0"))
+	filesystemTag, ok := r.Filesystem()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(filesystemTag, gc.Equals, names.NewFilesystemTag("0/0"))
+}
+
+func (s *StorageResizeStateSuite) TestResizeStorageInstanceFilesystem(c *gc.C) {
+	storageTag := s.setupProvisionedStorage(c, "filesystem", "environscoped")
+
+	r, err := s.State.ResizeStorageInstance(storageTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.Id(), gc.Equals, "0")
+	_, ok := r.Machine()
+	c.Assert(ok, jc.IsFalse)
+	_, ok = r.Volume()
+	c.Assert(ok, jc.IsFalse)
+	filesystemTag, ok := r.Filesystem()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(filesystemTag, gc.Equals, names.NewFilesystemTag("0"))
+}
+
+func (s *StorageResizeStateSuite) TestResizeStorageInstanceShrink(c *gc.C) {
+	storageTag := s.setupProvisionedStorage(c, "block", "loop-pool")
+	_, err := s.State.ResizeStorageInstance(storageTag, 1024)
+	c.Assert(err, gc.ErrorMatches, "cannot resize storage data/0: volume is already 1024MiB, storage can only be grown")
+}
+
+func (s *StorageResizeStateSuite) TestResizeStorageInstanceUnprovisioned(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.ResizeStorageInstance(storageTag, 2048)
+	c.Assert(err, gc.ErrorMatches, `cannot resize storage data/0: volume "0/0" not provisioned`)
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+}
+
+func (s *StorageResizeStateSuite) TestCompleteStorageResize(c *gc.C) {
+	storageTag := s.setupProvisionedStorage(c, "filesystem", "loop-pool")
+	r, err := s.State.ResizeStorageInstance(storageTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := s.State.WatchVolume(names.NewVolumeTag("0/0"))
+	defer testing.AssertStop(c, w)
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err = s.State.CompleteStorageResize(r.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	_, err = s.State.StorageResize(r.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	s.assertVolumeInfo(c, names.NewVolumeTag("0/0"), state.VolumeInfo{
+		VolumeId: "vol-ume",
+		Pool:     "loop-pool",
+		Size:     2048,
+	})
+	filesystem := s.filesystem(c, names.NewFilesystemTag("0/0"))
+	info, err := filesystem.Info()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Size, gc.Equals, uint64(2048))
+
+	// Completing a missing request is a no-op.
+	err = s.State.CompleteStorageResize(r.Id())
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Once the request is complete, the storage may be resized again.
+	_, err = s.State.ResizeStorageInstance(storageTag, 4096)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *StorageResizeStateSuite) TestResizeStorageInstanceConcurrent(c *gc.C) {
+	storageTag := s.setupProvisionedStorage(c, "block", "loop-pool")
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		_, err := s.State.ResizeStorageInstance(storageTag, 2048)
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	_, err := s.State.ResizeStorageInstance(storageTag, 4096)
+	c.Assert(err, gc.ErrorMatches, "cannot resize storage data/0: storage is already being resized")
+}
+
+func (s *StorageResizeStateSuite) TestWatchMachineStorageResizes(c *gc.C) {
+	storageTag := s.setupProvisionedStorage(c, "block", "loop-pool")
+	w := s.State.WatchMachineStorageResizes(names.NewMachineTag("0"))
+	defer testing.AssertStop(c, w)
+	wc := testing.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChangeInSingleEvent() // initial
+	wc.AssertNoChange()
+
+	r, err := s.State.ResizeStorageInstance(storageTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent("0/0")
+	wc.AssertNoChange()
+
+	err = s.State.CompleteStorageResize(r.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent("0/0")
+	wc.AssertNoChange()
+
+	environWatcher := s.State.WatchEnvironStorageResizes()
+	defer testing.AssertStop(c, environWatcher)
+	environWC := testing.NewStringsWatcherC(c, s.State, environWatcher)
+	environWC.AssertChangeInSingleEvent() // machine-scoped requests are excluded
+	environWC.AssertNoChange()
+}
@@ -6,6 +6,7 @@ package state
 import (
 	stderrors "errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/juju/errors"
@@ -1124,20 +1125,23 @@ func (u *Unit) AssignedMachineId() (id string, err error) {
 }
 
 var (
-	machineNotAliveErr = stderrors.New("machine is not alive")
-	machineNotCleanErr = stderrors.New("machine is dirty")
-	unitNotAliveErr    = stderrors.New("unit is not alive")
-	alreadyAssignedErr = stderrors.New("unit is already assigned to a machine")
-	inUseErr           = stderrors.New("machine is not unused")
+	machineNotAliveErr      = stderrors.New("machine is not alive")
+	machineNotCleanErr      = stderrors.New("machine is dirty")
+	machineUnschedulableErr = stderrors.New("machine is unschedulable")
+	unitNotAliveErr         = stderrors.New("unit is not alive")
+	alreadyAssignedErr      = stderrors.New("unit is already assigned to a machine")
+	inUseErr                = stderrors.New("machine is not unused")
 )
 
 // assignToMachine is the internal version of AssignToMachine,
 // also used by AssignToUnusedMachine. It returns specific errors
 // in some cases:
 // - machineNotAliveErr when the machine is not alive.
+// - machineUnschedulableErr when the machine is being drained.
 // - unitNotAliveErr when the unit is not alive.
 // - alreadyAssignedErr when the unit has already been assigned
 // - inUseErr when the machine already has a unit assigned (if unused is true)
+// - *antiAffinityError when the unit's anti-affinity forbids the machine
 func (u *Unit) assignToMachine(m *Machine, unused bool) (err error) {
 	originalm := m
 	buildTxn := func(attempt int) ([]txn.Op, error) {
@@ -1166,6 +1170,9 @@ func (u *Unit) assignToMachineOps(m *Machine, unused bool) ([]txn.Op, error) {
 	if m.Life() != Alive {
 		return nil, machineNotAliveErr
 	}
+	if m.doc.Unschedulable {
+		return nil, machineUnschedulableErr
+	}
 	if u.doc.Series != m.doc.Series {
 		return nil, fmt.Errorf("series does not match")
 	}
@@ -1220,13 +1227,21 @@ func (u *Unit) assignToMachineOps(m *Machine, unused bool) ([]txn.Op, error) {
 	}
 	storageOps = append(storageOps, attachmentOps...)
 
+	// Check anti-affinity constraints within the transaction, so they
+	// hold however the machine was chosen.
+	affinityOps, err := antiAffinityOps(u, m)
+	if err != nil {
+		return nil, err
+	}
+	storageOps = append(storageOps, affinityOps...)
+
 	assert := append(isAliveDoc, bson.D{
 		{"$or", []bson.D{
 			{{"machineid", ""}},
 			{{"machineid", m.Id()}},
 		}},
 	}...)
-	massert := isAliveDoc
+	massert := append(isAliveDoc, bson.DocElem{"unschedulable", bson.D{{"$ne", true}}})
 	if unused {
 		massert = append(massert, bson.D{{"clean", bson.D{{"$ne", false}}}}...)
 	}
@@ -1650,28 +1665,29 @@ func machineStorageParamsForStorageInstance(
 		volumeAttachmentParams := VolumeAttachmentParams{
 			charmStorage.ReadOnly,
 		}
-		if unit == storage.Owner() {
+		volume, err := st.storageInstanceVolume(storage.StorageTag())
+		switch {
+		case err == nil:
+			// The storage instance is owned by the service, or was
+			// detached from another unit, so there is a volume already,
+			// for which we will just add an attachment.
+			volumeAttachments[volume.VolumeTag()] = volumeAttachmentParams
+		case errors.IsNotFound(err) && unit == storage.Owner():
 			// The storage instance is owned by the unit, so we'll need
 			// to create a volume.
 			cons := allCons[storage.StorageName()]
 			volumeParams := VolumeParams{
-				storage: storage.StorageTag(),
-				binding: storage.StorageTag(),
-				Pool:    cons.Pool,
-				Size:    cons.Size,
+				storage:  storage.StorageTag(),
+				binding:  storage.StorageTag(),
+				Pool:     cons.Pool,
+				Size:     cons.Size,
+				Snapshot: cons.snapshot,
 			}
 			volumes = append(volumes, MachineVolumeParams{
 				volumeParams, volumeAttachmentParams,
 			})
-		} else {
-			// The storage instance is owned by the service, so there
-			// should be a (shared) volume already, for which we will
-			// just add an attachment.
-			volume, err := st.StorageInstanceVolume(storage.StorageTag())
-			if err != nil {
-				return nil, errors.Annotatef(err, "getting volume for storage %q", storage.Tag().Id())
-			}
-			volumeAttachments[volume.VolumeTag()] = volumeAttachmentParams
+		default:
+			return nil, errors.Annotatef(err, "getting volume for storage %q", storage.Tag().Id())
 		}
 	case StorageKindFilesystem:
 		location, err := filesystemMountPoint(charmStorage, storage.StorageTag(), series)
@@ -1686,7 +1702,14 @@ func machineStorageParamsForStorageInstance(
 			location,
 			charmStorage.ReadOnly,
 		}
-		if unit == storage.Owner() {
+		filesystem, err := st.storageInstanceFilesystem(storage.StorageTag())
+		switch {
+		case err == nil:
+			// The storage instance is owned by the service, or was
+			// detached from another unit, so there is a filesystem
+			// already, for which we will just add an attachment.
+			filesystemAttachments[filesystem.FilesystemTag()] = filesystemAttachmentParams
+		case errors.IsNotFound(err) && unit == storage.Owner():
 			// The storage instance is owned by the unit, so we'll need
 			// to create a filesystem.
 			cons := allCons[storage.StorageName()]
@@ -1699,15 +1722,8 @@ func machineStorageParamsForStorageInstance(
 			filesystems = append(filesystems, MachineFilesystemParams{
 				filesystemParams, filesystemAttachmentParams,
 			})
-		} else {
-			// The storage instance is owned by the service, so there
-			// should be a (shared) filesystem already, for which we will
-			// just add an attachment.
-			filesystem, err := st.StorageInstanceFilesystem(storage.StorageTag())
-			if err != nil {
-				return nil, errors.Annotatef(err, "getting filesystem for storage %q", storage.Tag().Id())
-			}
-			filesystemAttachments[filesystem.FilesystemTag()] = filesystemAttachmentParams
+		default:
+			return nil, errors.Annotatef(err, "getting filesystem for storage %q", storage.Tag().Id())
 		}
 	default:
 		return nil, errors.Errorf("invalid storage kind %v", storage.Kind())
@@ -1758,6 +1774,13 @@ var hasNoContainersTerm = bson.DocElem{
 // findCleanMachineQuery returns a Mongo query to find clean (and possibly empty) machines with
 // characteristics matching the specified constraints.
 func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value) (bson.D, error) {
+	return u.findMachineQuery(true, requireEmpty, cons)
+}
+
+// findMachineQuery returns a Mongo query to find machines with the
+// given cleanliness, and possibly empty, with characteristics matching
+// the specified constraints.
+func (u *Unit) findMachineQuery(clean, requireEmpty bool, cons *constraints.Value) (bson.D, error) {
 	db, closer := u.st.newDB()
 	defer closer()
 	containerRefsCollection, closer := db.GetCollection(containerRefsC)
@@ -1781,7 +1804,8 @@ func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value)
 		{"life", Alive},
 		{"series", u.doc.Series},
 		{"jobs", []MachineJob{JobHostUnits}},
-		{"clean", true},
+		{"clean", clean},
+		{"unschedulable", bson.D{{"$ne", true}}},
 		{"machineid", bson.D{{"$nin", machinesWithContainers}}},
 	}
 	// Add the container filter term if necessary.
@@ -1952,7 +1976,7 @@ func (u *Unit) assignToCleanMaybeEmptyMachine(requireEmpty bool) (m *Machine, er
 		if err == nil {
 			return m, nil
 		}
-		if err != inUseErr && err != machineNotAliveErr {
+		if err != inUseErr && err != machineNotAliveErr && err != machineUnschedulableErr && !isAntiAffinityError(err) {
 			assignContextf(&err, u, context)
 			return nil, err
 		}
@@ -1960,6 +1984,114 @@ func (u *Unit) assignToCleanMaybeEmptyMachine(requireEmpty bool) (m *Machine, er
 	return nil, noCleanMachines
 }
 
+// assignToPackedMachine assigns u to a machine that is already in use,
+// if the unit's placement constraint requests that units be packed onto
+// as few machines as possible. Machines hosting the most principal units
+// are preferred; machines already hosting a unit of the same service, or
+// of a service with which the unit has an anti-affinity, are never
+// chosen. If the placement policy is not "pack", or there is no suitable
+// machine, noCleanMachines is returned.
+func (u *Unit) assignToPackedMachine() (m *Machine, err error) {
+	context := "packed machine"
+	if u.doc.Principal != "" {
+		err = fmt.Errorf("unit is a subordinate")
+		assignContextf(&err, u, context)
+		return nil, err
+	}
+	cons, err := u.Constraints()
+	if err != nil {
+		assignContextf(&err, u, context)
+		return nil, err
+	}
+	if cons.Placement == nil || *cons.Placement != constraints.PlacementPack {
+		return nil, noCleanMachines
+	}
+
+	// If required storage is not all dynamic, then assigning
+	// to a new machine is required.
+	storageParams, err := u.machineStorageParams()
+	if err != nil {
+		assignContextf(&err, u, context)
+		return nil, err
+	}
+	if err := validateDynamicStorageParams(u.st, storageParams); err != nil {
+		if errors.IsNotSupported(err) {
+			return nil, noCleanMachines
+		}
+		assignContextf(&err, u, context)
+		return nil, err
+	}
+
+	query, err := u.findMachineQuery(false, false, cons)
+	if err != nil {
+		assignContextf(&err, u, context)
+		return nil, err
+	}
+	machinesCollection, closer := u.st.getCollection(machinesC)
+	defer closer()
+	var mdocs []*machineDoc
+	if err := machinesCollection.Find(query).All(&mdocs); err != nil {
+		assignContextf(&err, u, context)
+		return nil, err
+	}
+	excluded, err := antiAffinityMachines(u, cons)
+	if err != nil {
+		assignContextf(&err, u, context)
+		return nil, err
+	}
+	var machines []*Machine
+	for _, mdoc := range mdocs {
+		if _, ok := excluded[TopParentId(mdoc.Id)]; ok {
+			continue
+		}
+		if hostsServiceUnit(mdoc, u.doc.Service) {
+			continue
+		}
+		machines = append(machines, newMachine(u.st, mdoc))
+	}
+	sort.Stable(byPrincipalCount(machines))
+
+	for _, m := range machines {
+		if err := validateDynamicMachineStorageParams(m, storageParams); err != nil {
+			if errors.IsNotSupported(err) {
+				continue
+			}
+			assignContextf(&err, u, context)
+			return nil, err
+		}
+		err := u.assignToMachine(m, false)
+		if err == nil {
+			return m, nil
+		}
+		if err != machineNotAliveErr && err != machineUnschedulableErr && !isAntiAffinityError(err) {
+			assignContextf(&err, u, context)
+			return nil, err
+		}
+	}
+	return nil, noCleanMachines
+}
+
+// hostsServiceUnit reports whether the machine document records a
+// principal unit of the named service.
+func hostsServiceUnit(mdoc *machineDoc, service string) bool {
+	for _, unitName := range mdoc.Principals {
+		if unitService, err := names.UnitService(unitName); err == nil && unitService == service {
+			return true
+		}
+	}
+	return false
+}
+
+// byPrincipalCount sorts machines so that those hosting the most
+// principal units come first.
+type byPrincipalCount []*Machine
+
+func (b byPrincipalCount) Len() int      { return len(b) }
+func (b byPrincipalCount) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byPrincipalCount) Less(i, j int) bool {
+	return len(b[i].doc.Principals) > len(b[j].doc.Principals)
+}
+
 // UnassignFromMachine removes the assignment between this unit and the
 // machine it's assigned to.
 func (u *Unit) UnassignFromMachine() (err error) {
@@ -1994,6 +2126,21 @@ type ActionSpecsByName map[string]charm.ActionSpec
 // this Unit, and returns its ID.  Note that the use of spec.InsertDefaults
 // mutates payload.
 func (u *Unit) AddAction(name string, payload map[string]interface{}) (*Action, error) {
+	return u.addAction(name, payload, actionOptions{})
+}
+
+// AddActionWithTimeout adds a new Action as AddAction does, which is
+// stopped and marked failed if it runs for longer than timeout.
+func (u *Unit) AddActionWithTimeout(name string, payload map[string]interface{}, timeout time.Duration) (*Action, error) {
+	if timeout < 0 {
+		return nil, errors.Errorf("invalid timeout %v", timeout)
+	}
+	return u.addAction(name, payload, actionOptions{timeout: timeout})
+}
+
+// addAction validates and enqueues the named action, with the given
+// optional attributes.
+func (u *Unit) addAction(name string, payload map[string]interface{}, opts actionOptions) (*Action, error) {
 	if len(name) == 0 {
 		return nil, errors.New("no action name given")
 	}
@@ -2014,7 +2161,7 @@ func (u *Unit) AddAction(name string, payload map[string]interface{}) (*Action,
 	if err != nil {
 		return nil, err
 	}
-	return u.st.EnqueueAction(u.Tag(), name, payloadWithDefaults)
+	return u.st.enqueueAction(u.Tag(), name, payloadWithDefaults, opts)
 }
 
 // ActionSpecs gets the ActionSpec map for the Unit's charm.
@@ -2044,9 +2191,10 @@ func (u *Unit) ActionSpecs() (ActionSpecsByName, error) {
 }
 
 // CancelAction removes a pending Action from the queue for this
-// ActionReceiver and marks it as cancelled.
+// ActionReceiver and marks it as cancelled, or requests that a running
+// Action be stopped.
 func (u *Unit) CancelAction(action *Action) (*Action, error) {
-	return action.Finish(ActionResults{Status: ActionCancelled})
+	return action.Cancel()
 }
 
 // WatchActionNotifications starts and returns a StringsWatcher that
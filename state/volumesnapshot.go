@@ -0,0 +1,485 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// VolumeSnapshot describes a point-in-time snapshot of the volume
+// assigned to a storage instance.
+//
+// Snapshots of machine-scoped volumes are themselves scoped to the
+// machine, and have IDs of the form "<machine>/<n>"; all others have
+// IDs of the form "<n>".
+type VolumeSnapshot interface {
+	Lifer
+
+	// Id returns the unique ID of the snapshot.
+	Id() string
+
+	// Machine returns the tag of the machine that the snapshot is
+	// scoped to, and true; or false if the snapshot is not scoped
+	// to a machine.
+	Machine() (names.MachineTag, bool)
+
+	// StorageInstance returns the tag of the storage instance whose
+	// volume was snapshotted.
+	StorageInstance() names.StorageTag
+
+	// Volume returns the tag of the snapshotted volume.
+	Volume() names.VolumeTag
+
+	// Pool returns the name of the storage pool of the snapshotted
+	// volume. Volumes restored from the snapshot are created in the
+	// same pool.
+	Pool() string
+
+	// Created returns the time at which the snapshot was requested.
+	Created() time.Time
+
+	// Info returns the snapshot's VolumeSnapshotInfo, or a
+	// NotProvisioned error if the snapshot has not yet been taken.
+	Info() (VolumeSnapshotInfo, error)
+
+	// Status returns the status of the snapshot: StatusPending until
+	// it has been taken, StatusActive once it has, or StatusError if
+	// it cannot be taken, along with a message describing the error.
+	Status() (Status, string)
+}
+
+type volumeSnapshot struct {
+	doc volumeSnapshotDoc
+}
+
+// volumeSnapshotDoc records information about a volume snapshot.
+type volumeSnapshotDoc struct {
+	DocID     string              `bson:"_id"`
+	Name      string              `bson:"name"`
+	EnvUUID   string              `bson:"env-uuid"`
+	Life      Life                `bson:"life"`
+	StorageId string              `bson:"storageid"`
+	Volume    string              `bson:"volumeid"`
+	Pool      string              `bson:"pool"`
+	Created   time.Time           `bson:"created"`
+	Info      *VolumeSnapshotInfo `bson:"info,omitempty"`
+	Error     string              `bson:"error,omitempty"`
+}
+
+// VolumeSnapshotInfo describes information about a volume snapshot.
+type VolumeSnapshotInfo struct {
+	SnapshotId string `bson:"snapshotid"`
+	Size       uint64 `bson:"size"`
+}
+
+// Id is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) Id() string {
+	return s.doc.Name
+}
+
+// Machine is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) Machine() (names.MachineTag, bool) {
+	return VolumeSnapshotMachine(s.doc.Name)
+}
+
+// StorageInstance is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) StorageInstance() names.StorageTag {
+	return names.NewStorageTag(s.doc.StorageId)
+}
+
+// Volume is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) Volume() names.VolumeTag {
+	return names.NewVolumeTag(s.doc.Volume)
+}
+
+// Pool is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) Pool() string {
+	return s.doc.Pool
+}
+
+// Created is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) Created() time.Time {
+	return s.doc.Created
+}
+
+// Life is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) Life() Life {
+	return s.doc.Life
+}
+
+// Info is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) Info() (VolumeSnapshotInfo, error) {
+	if s.doc.Info == nil {
+		return VolumeSnapshotInfo{}, errors.NotProvisionedf("volume snapshot %q", s.doc.Name)
+	}
+	return *s.doc.Info, nil
+}
+
+// Status is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) Status() (Status, string) {
+	switch {
+	case s.doc.Info != nil:
+		return StatusActive, ""
+	case s.doc.Error != "":
+		return StatusError, s.doc.Error
+	}
+	return StatusPending, ""
+}
+
+// VolumeSnapshotMachine returns the tag of the machine that the
+// volume snapshot with the specified ID is scoped to, if any.
+func VolumeSnapshotMachine(id string) (names.MachineTag, bool) {
+	return machineScopedIdMachine(id)
+}
+
+// IsValidVolumeSnapshotId reports whether the specified string is a
+// valid volume snapshot ID.
+func IsValidVolumeSnapshotId(id string) bool {
+	return isValidMachineScopedId(id)
+}
+
+// machineScopedIdMachine returns the tag of the machine that the
+// entity with the specified ID is scoped to, if any. Machine-scoped
+// IDs have the form "<machine>/<n>"; all others have the form "<n>".
+func machineScopedIdMachine(id string) (names.MachineTag, bool) {
+	slash := strings.LastIndex(id, "/")
+	if slash == -1 {
+		return names.MachineTag{}, false
+	}
+	return names.NewMachineTag(id[:slash]), true
+}
+
+var validMachineScopedNumber = regexp.MustCompile("^" + names.NumberSnippet + "$")
+
+// isValidMachineScopedId reports whether the specified string is a
+// valid, optionally machine-scoped, ID.
+func isValidMachineScopedId(id string) bool {
+	slash := strings.LastIndex(id, "/")
+	if slash != -1 {
+		if !names.IsValidMachine(id[:slash]) {
+			return false
+		}
+		id = id[slash+1:]
+	}
+	return validMachineScopedNumber.MatchString(id)
+}
+
+// VolumeSnapshot returns the VolumeSnapshot with the specified ID.
+func (st *State) VolumeSnapshot(id string) (VolumeSnapshot, error) {
+	s, err := st.volumeSnapshot(id)
+	return s, err
+}
+
+func (st *State) volumeSnapshot(id string) (*volumeSnapshot, error) {
+	coll, cleanup := st.getCollection(volumeSnapshotsC)
+	defer cleanup()
+
+	var s volumeSnapshot
+	err := coll.FindId(id).One(&s.doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("volume snapshot %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "getting volume snapshot %q", id)
+	}
+	return &s, nil
+}
+
+func (st *State) volumeSnapshots(query interface{}) ([]VolumeSnapshot, error) {
+	coll, cleanup := st.getCollection(volumeSnapshotsC)
+	defer cleanup()
+
+	var docs []volumeSnapshotDoc
+	if err := coll.Find(query).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "querying volume snapshots")
+	}
+	snapshots := make([]VolumeSnapshot, len(docs))
+	for i, doc := range docs {
+		snapshots[i] = &volumeSnapshot{doc}
+	}
+	return snapshots, nil
+}
+
+// StorageInstanceVolumeSnapshots returns all of the VolumeSnapshots
+// taken of the volume assigned to the specified storage instance.
+func (st *State) StorageInstanceVolumeSnapshots(tag names.StorageTag) ([]VolumeSnapshot, error) {
+	snapshots, err := st.volumeSnapshots(bson.D{{"storageid", tag.Id()}})
+	if err != nil {
+		return nil, errors.Annotatef(err, "getting volume snapshots for storage %q", tag.Id())
+	}
+	return snapshots, nil
+}
+
+// AllVolumeSnapshots returns all VolumeSnapshots in the environment.
+func (st *State) AllVolumeSnapshots() ([]VolumeSnapshot, error) {
+	snapshots, err := st.volumeSnapshots(nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get volume snapshots")
+	}
+	return snapshots, nil
+}
+
+// AddVolumeSnapshot requests a snapshot of the volume assigned to
+// the specified storage instance. The volume must be provisioned;
+// the snapshot will be taken by the storage provisioner responsible
+// for the volume.
+func (st *State) AddVolumeSnapshot(tag names.StorageTag) (_ VolumeSnapshot, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot snapshot storage %s", tag.Id())
+	v, err := st.storageInstanceVolume(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if v.Life() != Alive {
+		return nil, errors.New("volume is not alive")
+	}
+	// A volume cannot go from being provisioned to unprovisioned,
+	// so there is no txn.Op for this below.
+	info, err := v.Info()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	seq, err := st.sequence("volumesnapshot")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	name := fmt.Sprint(seq)
+	if machineTag, ok := names.VolumeMachine(v.VolumeTag()); ok {
+		name = machineTag.Id() + "/" + name
+	}
+	doc := volumeSnapshotDoc{
+		Name:      name,
+		StorageId: tag.Id(),
+		Volume:    v.doc.Name,
+		Pool:      info.Pool,
+		Created:   nowToTheSecond(),
+	}
+	ops := []txn.Op{{
+		C:      volumesC,
+		Id:     v.doc.Name,
+		Assert: isAliveDoc,
+	}, {
+		C:      volumeSnapshotsC,
+		Id:     name,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return nil, errors.New("volume is not alive")
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &volumeSnapshot{doc}, nil
+}
+
+// SetVolumeSnapshotInfo records the VolumeSnapshotInfo for the
+// specified volume snapshot, once the snapshot has been taken.
+func (st *State) SetVolumeSnapshotInfo(id string, info VolumeSnapshotInfo) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set info for volume snapshot %q", id)
+	if info.SnapshotId == "" {
+		return errors.New("snapshot ID not set")
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		s, err := st.volumeSnapshot(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.Life() == Dead {
+			return nil, errors.New("volume snapshot is dead")
+		}
+		if oldInfo, err := s.Info(); err == nil {
+			if oldInfo.SnapshotId != info.SnapshotId {
+				return nil, errors.Errorf(
+					"cannot change snapshot ID from %q to %q",
+					oldInfo.SnapshotId, info.SnapshotId,
+				)
+			}
+		}
+		return []txn.Op{{
+			C:      volumeSnapshotsC,
+			Id:     id,
+			Assert: notDeadDoc,
+			Update: bson.D{
+				{"$set", bson.D{{"info", &info}}},
+				{"$unset", bson.D{{"error", nil}}},
+			},
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
+// SetVolumeSnapshotError records that the specified volume snapshot
+// cannot be taken, and why. The snapshot remains until it is destroyed.
+func (st *State) SetVolumeSnapshotError(id, message string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set error for volume snapshot %q", id)
+	if message == "" {
+		return errors.New("error message not set")
+	}
+	ops := []txn.Op{{
+		C:      volumeSnapshotsC,
+		Id:     id,
+		Assert: append(bson.D{{"info", bson.D{{"$exists", false}}}}, notDeadDoc...),
+		Update: bson.D{{"$set", bson.D{{"error", message}}}},
+	}}
+	if err := st.runTransaction(ops); err != txn.ErrAborted {
+		return errors.Trace(err)
+	}
+	s, err := st.volumeSnapshot(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if s.Life() == Dead {
+		return errors.New("volume snapshot is dead")
+	}
+	return errors.New("volume snapshot has already been taken")
+}
+
+// DestroyVolumeSnapshot ensures that the volume snapshot is no longer
+// Alive. The storage provisioner will delete the snapshot from the
+// storage provider, and then remove it from state.
+func (st *State) DestroyVolumeSnapshot(id string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "destroying volume snapshot %s", id)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		s, err := st.volumeSnapshot(id)
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.Life() != Alive {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return []txn.Op{{
+			C:      volumeSnapshotsC,
+			Id:     id,
+			Assert: isAliveDoc,
+			Update: bson.D{{"$set", bson.D{{"life", Dying}}}},
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
+// RemoveVolumeSnapshot removes the volume snapshot from state.
+// RemoveVolumeSnapshot will fail if the snapshot is Alive.
+func (st *State) RemoveVolumeSnapshot(id string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "removing volume snapshot %s", id)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		s, err := st.volumeSnapshot(id)
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.Life() == Alive {
+			return nil, errors.New("volume snapshot is alive")
+		}
+		return []txn.Op{{
+			C:      volumeSnapshotsC,
+			Id:     id,
+			Assert: bson.D{{"life", bson.D{{"$ne", Alive}}}},
+			Remove: true,
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
+// removeMachineVolumeSnapshotsOps returns txn.Ops to remove the volume
+// snapshots scoped to the specified machine. This is used when the given
+// machine is being removed from state; machine-scoped snapshots cannot
+// outlive the machine's storage.
+func (st *State) removeMachineVolumeSnapshotsOps(machine names.MachineTag) ([]txn.Op, error) {
+	pattern := fmt.Sprintf("^%s/%s$", regexp.QuoteMeta(machine.Id()), names.NumberSnippet)
+	snapshots, err := st.volumeSnapshots(bson.D{{"name", bson.D{{"$regex", pattern}}}})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := make([]txn.Op, len(snapshots))
+	for i, s := range snapshots {
+		ops[i] = txn.Op{
+			C:      volumeSnapshotsC,
+			Id:     s.Id(),
+			Remove: true,
+		}
+	}
+	return ops, nil
+}
+
+// RestoreVolumeSnapshot adds a block storage instance with the given
+// storage name to the specified unit, whose volume will be created
+// from the specified volume snapshot. The unit must be assigned to a
+// machine; if the snapshot is scoped to a machine, then the unit must
+// be assigned to that machine.
+func (st *State) RestoreVolumeSnapshot(unitTag names.UnitTag, storageName, id string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot restore volume snapshot %s", id)
+	s, err := st.volumeSnapshot(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if s.Life() != Alive {
+		return errors.New("volume snapshot is not alive")
+	}
+	info, err := s.Info()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	u, err := st.Unit(unitTag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	machineId, err := u.AssignedMachineId()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if machineTag, ok := s.Machine(); ok && machineTag.Id() != machineId {
+		return errors.Errorf(
+			"snapshot is scoped to machine %s, but unit %s is assigned to machine %s",
+			machineTag.Id(), u.Name(), machineId,
+		)
+	}
+	svc, err := u.Service()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ch, _, err := svc.Charm()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	charmStorage, ok := ch.Meta().Storage[storageName]
+	if !ok {
+		return errors.NotFoundf("charm storage %q", storageName)
+	}
+	if charmStorage.Type != charm.StorageBlock {
+		return errors.NotSupportedf("restoring snapshot to %s storage", charmStorage.Type)
+	}
+	cons := StorageConstraints{
+		Pool:     s.Pool(),
+		Size:     info.Size,
+		Count:    1,
+		snapshot: id,
+	}
+	return st.addStorageForUnit(ch, u, storageName, cons)
+}
+
+// WatchEnvironVolumeSnapshots returns a StringsWatcher that notifies of
+// changes to the lifecycles of all volume snapshots that are not scoped
+// to a machine.
+func (st *State) WatchEnvironVolumeSnapshots() StringsWatcher {
+	return st.watchEnvironMachineStorage(volumeSnapshotsC)
+}
+
+// WatchMachineVolumeSnapshots returns a StringsWatcher that notifies of
+// changes to the lifecycles of all volume snapshots scoped to the
+// specified machine.
+func (st *State) WatchMachineVolumeSnapshots(m names.MachineTag) StringsWatcher {
+	return st.watchMachineStorage(m, volumeSnapshotsC)
+}
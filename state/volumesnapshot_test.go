@@ -0,0 +1,254 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+)
+
+type VolumeSnapshotStateSuite struct {
+	StorageStateSuiteBase
+}
+
+var _ = gc.Suite(&VolumeSnapshotStateSuite{})
+
+// setupProvisionedVolume adds a unit with a single provisioned
+// machine-scoped volume, returning the unit and its storage tag.
+func (s *VolumeSnapshotStateSuite) setupProvisionedVolume(c *gc.C) (*state.Unit, names.StorageTag) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	volume := s.storageInstanceVolume(c, storageTag)
+	err = s.State.SetVolumeInfo(volume.VolumeTag(), state.VolumeInfo{
+		VolumeId: "vol-ume",
+		Size:     1024,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return u, storageTag
+}
+
+func (s *VolumeSnapshotStateSuite) TestAddVolumeSnapshot(c *gc.C) {
+	_, storageTag := s.setupProvisionedVolume(c)
+
+	snapshot, err := s.State.AddVolumeSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshot.Id(), gc.Equals, "0/0")
+	c.Assert(snapshot.Life(), gc.Equals, state.Alive)
+	c.Assert(snapshot.StorageInstance(), gc.Equals, storageTag)
+	c.Assert(snapshot.Volume(), gc.Equals, names.NewVolumeTag("0/0"))
+	c.Assert(snapshot.Pool(), gc.Equals, "loop-pool")
+	machineTag, ok := snapshot.Machine()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(machineTag, gc.Equals, names.NewMachineTag("0"))
+	_, err = snapshot.Info()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+
+	snapshots, err := s.State.StorageInstanceVolumeSnapshots(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshots, gc.HasLen, 1)
+	c.Assert(snapshots[0].Id(), gc.Equals, "0/0")
+}
+
+func (s *VolumeSnapshotStateSuite) TestAddVolumeSnapshotUnprovisioned(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.AddVolumeSnapshot(storageTag)
+	c.Assert(err, gc.ErrorMatches, `cannot snapshot storage data/0: volume "0/0" not provisioned`)
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+}
+
+func (s *VolumeSnapshotStateSuite) TestSetVolumeSnapshotInfo(c *gc.C) {
+	_, storageTag := s.setupProvisionedVolume(c)
+	snapshot, err := s.State.AddVolumeSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	info := state.VolumeSnapshotInfo{SnapshotId: "snap-0", Size: 1024}
+	err = s.State.SetVolumeSnapshotInfo(snapshot.Id(), info)
+	c.Assert(err, jc.ErrorIsNil)
+	snapshot, err = s.State.VolumeSnapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	snapshotInfo, err := snapshot.Info()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshotInfo, jc.DeepEquals, info)
+
+	err = s.State.SetVolumeSnapshotInfo(snapshot.Id(), state.VolumeSnapshotInfo{SnapshotId: "snap-1"})
+	c.Assert(err, gc.ErrorMatches, `cannot set info for volume snapshot "0/0": cannot change snapshot ID from "snap-0" to "snap-1"`)
+	err = s.State.SetVolumeSnapshotInfo(snapshot.Id(), state.VolumeSnapshotInfo{})
+	c.Assert(err, gc.ErrorMatches, `cannot set info for volume snapshot "0/0": snapshot ID not set`)
+}
+
+func (s *VolumeSnapshotStateSuite) TestSetVolumeSnapshotError(c *gc.C) {
+	_, storageTag := s.setupProvisionedVolume(c)
+	snapshot, err := s.State.AddVolumeSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	status, message := snapshot.Status()
+	c.Assert(status, gc.Equals, state.StatusPending)
+	c.Assert(message, gc.Equals, "")
+
+	err = s.State.SetVolumeSnapshotError(snapshot.Id(), "snapshots not supported")
+	c.Assert(err, jc.ErrorIsNil)
+	snapshot, err = s.State.VolumeSnapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	status, message = snapshot.Status()
+	c.Assert(status, gc.Equals, state.StatusError)
+	c.Assert(message, gc.Equals, "snapshots not supported")
+
+	err = s.State.SetVolumeSnapshotInfo(snapshot.Id(), state.VolumeSnapshotInfo{SnapshotId: "snap-0"})
+	c.Assert(err, jc.ErrorIsNil)
+	snapshot, err = s.State.VolumeSnapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	status, message = snapshot.Status()
+	c.Assert(status, gc.Equals, state.StatusActive)
+	c.Assert(message, gc.Equals, "")
+
+	err = s.State.SetVolumeSnapshotError(snapshot.Id(), "too late")
+	c.Assert(err, gc.ErrorMatches, `cannot set error for volume snapshot "0/0": volume snapshot has already been taken`)
+}
+
+func (s *VolumeSnapshotStateSuite) TestDestroyAndRemoveVolumeSnapshot(c *gc.C) {
+	_, storageTag := s.setupProvisionedVolume(c)
+	snapshot, err := s.State.AddVolumeSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveVolumeSnapshot(snapshot.Id())
+	c.Assert(err, gc.ErrorMatches, "removing volume snapshot 0/0: volume snapshot is alive")
+
+	err = s.State.DestroyVolumeSnapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	snapshot, err = s.State.VolumeSnapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshot.Life(), gc.Equals, state.Dying)
+
+	err = s.State.RemoveVolumeSnapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.VolumeSnapshot(snapshot.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Destroying or removing a missing snapshot is a no-op.
+	err = s.State.DestroyVolumeSnapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveVolumeSnapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *VolumeSnapshotStateSuite) TestRemoveMachineRemovesVolumeSnapshots(c *gc.C) {
+	u, storageTag := s.setupProvisionedVolume(c)
+	snapshot, err := s.State.AddVolumeSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	machineId, err := u.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.Machine(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	s.obliterateUnit(c, u.UnitTag())
+	c.Assert(machine.Destroy(), jc.ErrorIsNil)
+	c.Assert(machine.EnsureDead(), jc.ErrorIsNil)
+	c.Assert(machine.Remove(), jc.ErrorIsNil)
+
+	_, err = s.State.VolumeSnapshot(snapshot.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *VolumeSnapshotStateSuite) TestRestoreVolumeSnapshot(c *gc.C) {
+	u, storageTag := s.setupProvisionedVolume(c)
+	snapshot, err := s.State.AddVolumeSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RestoreVolumeSnapshot(u.UnitTag(), "allecto", snapshot.Id())
+	c.Assert(err, gc.ErrorMatches, `cannot restore volume snapshot 0/0: volume snapshot "0/0" not provisioned`)
+
+	err = s.State.SetVolumeSnapshotInfo(snapshot.Id(), state.VolumeSnapshotInfo{
+		SnapshotId: "snap-0",
+		Size:       2048,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RestoreVolumeSnapshot(u.UnitTag(), "allecto", snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+
+	storageAttachments, err := s.State.UnitStorageAttachments(u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(storageAttachments, gc.HasLen, 2)
+	var restoredTag names.StorageTag
+	for _, a := range storageAttachments {
+		if a.StorageInstance() != storageTag {
+			restoredTag = a.StorageInstance()
+		}
+	}
+	volume := s.storageInstanceVolume(c, restoredTag)
+	params, ok := volume.Params()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(params, jc.DeepEquals, state.VolumeParams{
+		Pool:     "loop-pool",
+		Size:     2048,
+		Snapshot: snapshot.Id(),
+	})
+}
+
+func (s *VolumeSnapshotStateSuite) TestRestoreVolumeSnapshotOtherMachine(c *gc.C) {
+	_, storageTag := s.setupProvisionedVolume(c)
+	snapshot, err := s.State.AddVolumeSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetVolumeSnapshotInfo(snapshot.Id(), state.VolumeSnapshotInfo{SnapshotId: "snap-0", Size: 1024})
+	c.Assert(err, jc.ErrorIsNil)
+
+	service, err := s.State.Service("storage-block")
+	c.Assert(err, jc.ErrorIsNil)
+	u, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RestoreVolumeSnapshot(u.UnitTag(), "allecto", snapshot.Id())
+	c.Assert(err, gc.ErrorMatches, `cannot restore volume snapshot 0/0: unit "storage-block/1" is not assigned to a machine`)
+
+	err = s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RestoreVolumeSnapshot(u.UnitTag(), "allecto", snapshot.Id())
+	c.Assert(err, gc.ErrorMatches, `cannot restore volume snapshot 0/0: snapshot is scoped to machine 0, but unit storage-block/1 is assigned to machine 1`)
+}
+
+func (s *VolumeSnapshotStateSuite) TestWatchMachineVolumeSnapshots(c *gc.C) {
+	_, storageTag := s.setupProvisionedVolume(c)
+	w := s.State.WatchMachineVolumeSnapshots(names.NewMachineTag("0"))
+	defer testing.AssertStop(c, w)
+	wc := testing.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChangeInSingleEvent() // initial
+	wc.AssertNoChange()
+
+	snapshot, err := s.State.AddVolumeSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent("0/0")
+	wc.AssertNoChange()
+
+	err = s.State.DestroyVolumeSnapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent("0/0")
+	wc.AssertNoChange()
+
+	environWatcher := s.State.WatchEnvironVolumeSnapshots()
+	defer testing.AssertStop(c, environWatcher)
+	environWC := testing.NewStringsWatcherC(c, s.State, environWatcher)
+	environWC.AssertChangeInSingleEvent() // machine-scoped snapshots are excluded
+	environWC.AssertNoChange()
+}
+
+func (s *VolumeSnapshotStateSuite) TestIsValidVolumeSnapshotId(c *gc.C) {
+	for id, valid := range map[string]bool{
+		"0":         true,
+		"0/1":       true,
+		"0/lxc/1/2": true,
+		"":          false,
+		"a":         false,
+		"0/":        false,
+		"x/1":       false,
+		"/1":        false,
+	} {
+		c.Check(state.IsValidVolumeSnapshotId(id), gc.Equals, valid, gc.Commentf("%q", id))
+	}
+}
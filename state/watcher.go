@@ -1431,6 +1431,14 @@ func (st *State) WatchForEnvironConfigChanges() NotifyWatcher {
 	return newEntityWatcher(st, settingsC, st.docID(environGlobalKey))
 }
 
+// WatchAgentLoggingConfig returns a NotifyWatcher waiting for the logging
+// config of the agent with the given tag to change: either because the
+// environment's logging-config changed, or because the agent's logging
+// override was set, removed or expired.
+func (st *State) WatchAgentLoggingConfig(tag names.Tag) NotifyWatcher {
+	return newAgentLoggingConfigWatcher(st, tag)
+}
+
 // WatchForUnitAssignment watches for new services that request units to be
 // assigned to machines.
 func (st *State) WatchForUnitAssignment() StringsWatcher {
@@ -1450,6 +1458,17 @@ func (st *State) WatchStorageAttachment(s names.StorageTag, u names.UnitTag) Not
 	return newEntityWatcher(st, storageAttachmentsC, st.docID(id))
 }
 
+// WatchVolume returns a watcher for observing changes to a volume.
+func (st *State) WatchVolume(v names.VolumeTag) NotifyWatcher {
+	return newEntityWatcher(st, volumesC, st.docID(v.Id()))
+}
+
+// WatchFilesystem returns a watcher for observing changes to a
+// filesystem.
+func (st *State) WatchFilesystem(f names.FilesystemTag) NotifyWatcher {
+	return newEntityWatcher(st, filesystemsC, st.docID(f.Id()))
+}
+
 // WatchVolumeAttachment returns a watcher for observing changes
 // to a volume attachment.
 func (st *State) WatchVolumeAttachment(m names.MachineTag, v names.VolumeTag) NotifyWatcher {
@@ -1580,6 +1599,97 @@ func (w *docWatcher) loop(docKeys []docKey) error {
 	}
 }
 
+// agentLoggingConfigWatcher notifies of changes to the logging config of
+// an agent. As well as watching the documents that make up the config,
+// it notifies when the agent's logging override expires.
+type agentLoggingConfigWatcher struct {
+	commonWatcher
+	tag names.Tag
+	out chan struct{}
+}
+
+var _ Watcher = (*agentLoggingConfigWatcher)(nil)
+
+func newAgentLoggingConfigWatcher(st *State, tag names.Tag) NotifyWatcher {
+	w := &agentLoggingConfigWatcher{
+		commonWatcher: commonWatcher{st: st},
+		tag:           tag,
+		out:           make(chan struct{}),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *agentLoggingConfigWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+// expiry returns a channel that receives a value when the agent's
+// logging override expires, or nil if it has none that is yet to
+// expire.
+func (w *agentLoggingConfigWatcher) expiry() (<-chan time.Time, error) {
+	doc, err := w.st.loggingOverrideDoc(w.tag)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	clock := GetClock()
+	if d := doc.Expires.Sub(clock.Now()); d > 0 {
+		return clock.After(d), nil
+	}
+	return nil, nil
+}
+
+func (w *agentLoggingConfigWatcher) loop() error {
+	docKeys := []docKey{
+		{settingsC, w.st.docID(environGlobalKey)},
+		{loggingOverridesC, w.st.docID(w.tag.String())},
+	}
+	in := make(chan watcher.Change)
+	for _, k := range docKeys {
+		coll, closer := w.st.getCollection(k.coll)
+		txnRevno, err := getTxnRevno(coll, k.docId)
+		closer()
+		if err != nil {
+			return err
+		}
+		w.st.watcher.Watch(coll.Name(), k.docId, txnRevno, in)
+		defer w.st.watcher.Unwatch(coll.Name(), k.docId, in)
+	}
+	expired, err := w.expiry()
+	if err != nil {
+		return err
+	}
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.st.watcher.Dead():
+			return stateWatcherDeadError(w.st.watcher.Err())
+		case ch := <-in:
+			if _, ok := collect(ch, in, w.tomb.Dying()); !ok {
+				return tomb.ErrDying
+			}
+			if expired, err = w.expiry(); err != nil {
+				return err
+			}
+			out = w.out
+		case <-expired:
+			expired = nil
+			out = w.out
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
 // machineUnitsWatcher notifies about assignments and lifecycle changes
 // for all units of a machine.
 //
@@ -1863,6 +1973,101 @@ func (w *cleanupWatcher) loop() (err error) {
 	}
 }
 
+// leadershipChangesWatcher notifies of services whose leadership
+// changes have been recorded in status history.
+type leadershipChangesWatcher struct {
+	commonWatcher
+	out chan []string
+}
+
+var _ Watcher = (*leadershipChangesWatcher)(nil)
+
+// WatchLeadershipChanges returns a StringsWatcher that notifies of the
+// names of services whose leader changes. Unlike most StringsWatchers,
+// the initial event is always empty: leadership changes that happened
+// before the watcher was started can be read with LeadershipHistory.
+func (st *State) WatchLeadershipChanges() StringsWatcher {
+	return newLeadershipChangesWatcher(st)
+}
+
+func newLeadershipChangesWatcher(st *State) StringsWatcher {
+	w := &leadershipChangesWatcher{
+		commonWatcher: commonWatcher{st: st},
+		out:           make(chan []string),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *leadershipChangesWatcher) Changes() <-chan []string {
+	return w.out
+}
+
+// serviceName returns the name of the service whose leadership change
+// is recorded in the status history document with the supplied id, and
+// whether the document records a leadership change in w's environment.
+func (w *leadershipChangesWatcher) serviceName(id interface{}) (string, bool) {
+	docID, ok := id.(string)
+	if !ok {
+		return "", false
+	}
+	localID, err := w.st.strictLocalID(docID)
+	if err != nil {
+		return "", false
+	}
+	match := leadershipHistoryIdRE.FindStringSubmatch(localID)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+func (w *leadershipChangesWatcher) loop() (err error) {
+	in := make(chan watcher.Change)
+	filter := func(id interface{}) bool {
+		_, ok := w.serviceName(id)
+		return ok
+	}
+	w.st.watcher.WatchCollectionWithFilter(statusesHistoryC, in, filter)
+	defer w.st.watcher.UnwatchCollection(statusesHistoryC, in)
+
+	changes := set.NewStrings()
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.st.watcher.Dead():
+			return stateWatcherDeadError(w.st.watcher.Err())
+		case ch := <-in:
+			updates, ok := collect(ch, in, w.tomb.Dying())
+			if !ok {
+				return tomb.ErrDying
+			}
+			for id, exists := range updates {
+				if !exists {
+					// Pruned history is not a leadership change.
+					continue
+				}
+				if serviceName, ok := w.serviceName(id); ok {
+					changes.Add(serviceName)
+				}
+			}
+			if !changes.IsEmpty() {
+				out = w.out
+			}
+		case out <- changes.SortedValues():
+			changes = set.NewStrings()
+			out = nil
+		}
+	}
+}
+
 // actionStatusWatcher is a StringsWatcher that filters notifications
 // to Action Id's that match the ActionReceiver and ActionStatus set
 // provided.
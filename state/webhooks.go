@@ -0,0 +1,297 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// maxWebhookDeadLetters is the number of dead letters kept for each
+// webhook; older dead letters are discarded.
+const maxWebhookDeadLetters = 100
+
+// webhookDoc describes a subscription that delivers environment
+// changes to a URL.
+type webhookDoc struct {
+	DocId    string    `bson:"_id"`
+	EnvUUID  string    `bson:"env-uuid"`
+	URL      string    `bson:"url"`
+	Kinds    []string  `bson:"kinds,omitempty"`
+	Statuses []string  `bson:"statuses,omitempty"`
+	Secret   string    `bson:"secret"`
+	Owner    string    `bson:"owner"`
+	Created  time.Time `bson:"created"`
+}
+
+// webhookDeadLetterDoc records a delivery that could not be made to a
+// webhook.
+type webhookDeadLetterDoc struct {
+	Id        bson.ObjectId `bson:"_id,omitempty"`
+	EnvUUID   string        `bson:"env-uuid"`
+	WebhookId string        `bson:"webhookid"`
+	Payload   string        `bson:"payload"`
+	Attempts  int           `bson:"attempts"`
+	Error     string        `bson:"error"`
+	Time      time.Time     `bson:"time"`
+}
+
+// AddWebhookArgs holds the parameters for creating a webhook.
+type AddWebhookArgs struct {
+	// URL is the http or https URL that changes are POSTed to.
+	URL string
+
+	// Kinds restricts delivery to changes to entities of the given
+	// kinds (e.g. "unit", "machine"). If empty, all kinds are
+	// delivered.
+	Kinds []string
+
+	// Statuses restricts delivery to changes to entities with one
+	// of the given statuses. If empty, changes are delivered
+	// regardless of status.
+	Statuses []string
+
+	// Secret is the key used to sign each delivery.
+	Secret string
+
+	// Owner is the name of the user that created the webhook.
+	Owner string
+}
+
+// Webhook represents a subscription that delivers environment changes,
+// as signed JSON, to a URL.
+type Webhook struct {
+	st  *State
+	doc webhookDoc
+}
+
+// Id returns the id of the webhook.
+func (w *Webhook) Id() string {
+	return w.st.localID(w.doc.DocId)
+}
+
+// URL returns the URL that changes are delivered to.
+func (w *Webhook) URL() string {
+	return w.doc.URL
+}
+
+// Kinds returns the entity kinds delivered to the webhook. An empty
+// result means that all kinds are delivered.
+func (w *Webhook) Kinds() []string {
+	return w.doc.Kinds
+}
+
+// Statuses returns the statuses delivered to the webhook. An empty
+// result means that changes are delivered regardless of status.
+func (w *Webhook) Statuses() []string {
+	return w.doc.Statuses
+}
+
+// Secret returns the key used to sign deliveries to the webhook.
+func (w *Webhook) Secret() string {
+	return w.doc.Secret
+}
+
+// Owner returns the name of the user that created the webhook.
+func (w *Webhook) Owner() string {
+	return w.doc.Owner
+}
+
+// Created returns the time the webhook was created.
+func (w *Webhook) Created() time.Time {
+	return w.doc.Created
+}
+
+// Remove removes the webhook, along with its dead letters. It is not
+// an error to remove a webhook that has already been removed.
+func (w *Webhook) Remove() error {
+	ops := []txn.Op{{
+		C:      webhooksC,
+		Id:     w.doc.DocId,
+		Remove: true,
+	}}
+	if err := w.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot remove webhook %q", w.Id())
+	}
+	deadLetters, closer := w.st.getCollection(webhookDeadLettersC)
+	defer closer()
+	_, err := deadLetters.Writeable().RemoveAll(bson.D{{"webhookid", w.Id()}})
+	if err != nil {
+		return errors.Annotatef(err, "cannot remove dead letters for webhook %q", w.Id())
+	}
+	return nil
+}
+
+// WebhookDeadLetter records a delivery that could not be made to a
+// webhook after all attempts were exhausted.
+type WebhookDeadLetter struct {
+	// WebhookId is the id of the webhook the delivery was for.
+	WebhookId string
+
+	// Payload holds the body of the undelivered request.
+	Payload string
+
+	// Attempts is the number of delivery attempts made.
+	Attempts int
+
+	// Error describes the failure of the last attempt.
+	Error string
+
+	// Time is the time at which delivery was abandoned.
+	Time time.Time
+}
+
+// AddDeadLetter records a delivery that could not be made to the
+// webhook. Only the most recent dead letters are kept.
+func (w *Webhook) AddDeadLetter(payload string, attempts int, deliveryErr string) error {
+	doc := &webhookDeadLetterDoc{
+		WebhookId: w.Id(),
+		Payload:   payload,
+		Attempts:  attempts,
+		Error:     deliveryErr,
+		Time:      nowToTheSecond(),
+	}
+	deadLetters, closer := w.st.getCollection(webhookDeadLettersC)
+	defer closer()
+	deadLettersW := deadLetters.Writeable()
+	if err := deadLettersW.Insert(doc); err != nil {
+		return errors.Annotatef(err, "cannot record dead letter for webhook %q", w.Id())
+	}
+
+	// Discard the oldest dead letters beyond the limit.
+	var expired []webhookDeadLetterDoc
+	query := deadLetters.Find(bson.D{{"webhookid", w.Id()}})
+	err := query.Sort("-time", "-_id").Skip(maxWebhookDeadLetters).Select(bson.D{{"_id", 1}}).All(&expired)
+	if err != nil {
+		return errors.Annotatef(err, "cannot prune dead letters for webhook %q", w.Id())
+	}
+	for _, doc := range expired {
+		if err := deadLettersW.RemoveId(doc.Id); err != nil && err != mgo.ErrNotFound {
+			return errors.Annotatef(err, "cannot prune dead letters for webhook %q", w.Id())
+		}
+	}
+	return nil
+}
+
+// DeadLetters returns the deliveries that could not be made to the
+// webhook, oldest first.
+func (w *Webhook) DeadLetters() ([]WebhookDeadLetter, error) {
+	deadLetters, closer := w.st.getCollection(webhookDeadLettersC)
+	defer closer()
+
+	var docs []webhookDeadLetterDoc
+	query := deadLetters.Find(bson.D{{"webhookid", w.Id()}})
+	if err := query.Sort("time", "_id").All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get dead letters for webhook %q", w.Id())
+	}
+	result := make([]WebhookDeadLetter, len(docs))
+	for i, doc := range docs {
+		result[i] = WebhookDeadLetter{
+			WebhookId: doc.WebhookId,
+			Payload:   doc.Payload,
+			Attempts:  doc.Attempts,
+			Error:     doc.Error,
+			Time:      doc.Time,
+		}
+	}
+	return result, nil
+}
+
+// AddWebhook creates a webhook that delivers matching environment
+// changes to the given URL.
+func (st *State) AddWebhook(args AddWebhookArgs) (*Webhook, error) {
+	if err := validateWebhookURL(args.URL); err != nil {
+		return nil, errors.Annotate(err, "cannot add webhook")
+	}
+	if args.Secret == "" {
+		return nil, errors.New("cannot add webhook: no secret given")
+	}
+	for _, kind := range args.Kinds {
+		if kind == "" {
+			return nil, errors.New("cannot add webhook: empty entity kind")
+		}
+	}
+	for _, status := range args.Statuses {
+		if status == "" {
+			return nil, errors.New("cannot add webhook: empty status")
+		}
+	}
+	id, err := NewUUID()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	doc := webhookDoc{
+		DocId:    st.docID(id.String()),
+		EnvUUID:  st.EnvironUUID(),
+		URL:      args.URL,
+		Kinds:    args.Kinds,
+		Statuses: args.Statuses,
+		Secret:   args.Secret,
+		Owner:    args.Owner,
+		Created:  nowToTheSecond(),
+	}
+	ops := []txn.Op{
+		assertEnvAliveOp(st.EnvironUUID()),
+		{
+			C:      webhooksC,
+			Id:     doc.DocId,
+			Assert: txn.DocMissing,
+			Insert: &doc,
+		},
+	}
+	if err := st.runTransaction(ops); err != nil {
+		return nil, errors.Annotate(onAbort(err, errors.New("environment is no longer alive")), "cannot add webhook")
+	}
+	return &Webhook{st, doc}, nil
+}
+
+func validateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.NotValidf("URL %q", rawURL)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.NotValidf("URL %q (scheme must be http or https)", rawURL)
+	}
+	if u.Host == "" {
+		return errors.NotValidf("URL %q (no host)", rawURL)
+	}
+	return nil
+}
+
+// Webhook returns the webhook with the given id.
+func (st *State) Webhook(id string) (*Webhook, error) {
+	webhooks, closer := st.getCollection(webhooksC)
+	defer closer()
+
+	var doc webhookDoc
+	err := webhooks.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("webhook %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get webhook %q", id)
+	}
+	return &Webhook{st, doc}, nil
+}
+
+// Webhooks returns all webhooks in the environment, oldest first.
+func (st *State) Webhooks() ([]*Webhook, error) {
+	webhooks, closer := st.getCollection(webhooksC)
+	defer closer()
+
+	var docs []webhookDoc
+	if err := webhooks.Find(nil).Sort("created").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get webhooks")
+	}
+	result := make([]*Webhook, len(docs))
+	for i, doc := range docs {
+		result[i] = &Webhook{st, doc}
+	}
+	return result, nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type WebhookSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&WebhookSuite{})
+
+func (s *WebhookSuite) addWebhook(c *gc.C) *state.Webhook {
+	w, err := s.State.AddWebhook(state.AddWebhookArgs{
+		URL:      "https://example.com/hook",
+		Kinds:    []string{"unit"},
+		Statuses: []string{"error", "blocked"},
+		Secret:   "s3cret",
+		Owner:    "admin",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return w
+}
+
+func (s *WebhookSuite) TestAddWebhook(c *gc.C) {
+	w := s.addWebhook(c)
+	c.Assert(w.URL(), gc.Equals, "https://example.com/hook")
+	c.Assert(w.Kinds(), jc.DeepEquals, []string{"unit"})
+	c.Assert(w.Statuses(), jc.DeepEquals, []string{"error", "blocked"})
+	c.Assert(w.Secret(), gc.Equals, "s3cret")
+	c.Assert(w.Owner(), gc.Equals, "admin")
+	c.Assert(w.Created().IsZero(), jc.IsFalse)
+
+	fetched, err := s.State.Webhook(w.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fetched.URL(), gc.Equals, w.URL())
+	c.Assert(fetched.Secret(), gc.Equals, w.Secret())
+
+	all, err := s.State.Webhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 1)
+	c.Assert(all[0].Id(), gc.Equals, w.Id())
+}
+
+func (s *WebhookSuite) TestAddWebhookInvalid(c *gc.C) {
+	for i, test := range []struct {
+		args state.AddWebhookArgs
+		err  string
+	}{{
+		args: state.AddWebhookArgs{URL: "ftp://example.com", Secret: "x"},
+		err:  `cannot add webhook: URL "ftp://example.com" \(scheme must be http or https\) not valid`,
+	}, {
+		args: state.AddWebhookArgs{URL: "http://", Secret: "x"},
+		err:  `cannot add webhook: URL "http://" \(no host\) not valid`,
+	}, {
+		args: state.AddWebhookArgs{URL: "http://example.com"},
+		err:  "cannot add webhook: no secret given",
+	}, {
+		args: state.AddWebhookArgs{URL: "http://example.com", Secret: "x", Kinds: []string{""}},
+		err:  "cannot add webhook: empty entity kind",
+	}, {
+		args: state.AddWebhookArgs{URL: "http://example.com", Secret: "x", Statuses: []string{""}},
+		err:  "cannot add webhook: empty status",
+	}} {
+		c.Logf("test %d", i)
+		_, err := s.State.AddWebhook(test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+	all, err := s.State.Webhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 0)
+}
+
+func (s *WebhookSuite) TestWebhookNotFound(c *gc.C) {
+	_, err := s.State.Webhook("nope")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *WebhookSuite) TestDeadLetters(c *gc.C) {
+	w := s.addWebhook(c)
+	other := s.addWebhook(c)
+
+	err := w.AddDeadLetter(`[["unit","change",{}]]`, 5, "500 Internal Server Error")
+	c.Assert(err, jc.ErrorIsNil)
+	err = other.AddDeadLetter("[]", 1, "connection refused")
+	c.Assert(err, jc.ErrorIsNil)
+
+	letters, err := w.DeadLetters()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(letters, gc.HasLen, 1)
+	c.Assert(letters[0].WebhookId, gc.Equals, w.Id())
+	c.Assert(letters[0].Payload, gc.Equals, `[["unit","change",{}]]`)
+	c.Assert(letters[0].Attempts, gc.Equals, 5)
+	c.Assert(letters[0].Error, gc.Equals, "500 Internal Server Error")
+	c.Assert(letters[0].Time.IsZero(), jc.IsFalse)
+}
+
+func (s *WebhookSuite) TestRemove(c *gc.C) {
+	w := s.addWebhook(c)
+	err := w.AddDeadLetter("[]", 1, "connection refused")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = w.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.Webhook(w.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	letters, err := w.DeadLetters()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(letters, gc.HasLen, 0)
+
+	// Removing again is not an error.
+	err = w.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -108,6 +108,33 @@ type VolumeSource interface {
 	DetachVolumes(params []VolumeAttachmentParams) ([]error, error)
 }
 
+// VolumeResizer is an optional interface that a VolumeSource may
+// implement if it is able to grow existing volumes.
+type VolumeResizer interface {
+	// ResizeVolumes grows the volumes with the specified parameters
+	// to the corresponding sizes.
+	ResizeVolumes(params []VolumeResizeParams) ([]error, error)
+}
+
+// VolumeSnapshotter is an optional interface that a VolumeSource may
+// implement if it is able to take point-in-time snapshots of volumes,
+// and to create volumes from those snapshots. A VolumeSource that
+// implements VolumeSnapshotter must honour VolumeParams.Snapshot in
+// CreateVolumes.
+type VolumeSnapshotter interface {
+	// CreateSnapshots takes snapshots of the volumes with the
+	// specified parameters.
+	CreateSnapshots(params []SnapshotParams) ([]CreateSnapshotsResult, error)
+
+	// ListSnapshots lists the provider-supplied IDs of all snapshots
+	// created by this source.
+	ListSnapshots() ([]string, error)
+
+	// DeleteSnapshots deletes the snapshots with the specified
+	// provider-supplied snapshot IDs.
+	DeleteSnapshots(snapshotIds []string) ([]error, error)
+}
+
 // FilesystemSource provides an interface for creating, destroying and
 // describing filesystems in the environment. A FilesystemSource is
 // configured in a particular way, and corresponds to a storage "pool".
@@ -141,6 +168,14 @@ type FilesystemSource interface {
 	DetachFilesystems(params []FilesystemAttachmentParams) ([]error, error)
 }
 
+// FilesystemResizer is an optional interface that a FilesystemSource
+// may implement if it is able to grow existing filesystems.
+type FilesystemResizer interface {
+	// ResizeFilesystems grows the filesystems with the specified
+	// parameters to the corresponding sizes.
+	ResizeFilesystems(params []FilesystemResizeParams) ([]error, error)
+}
+
 // VolumeParams is a fully specified set of parameters for volume creation,
 // derived from one or more of user-specified storage constraints, a
 // storage pool definition, and charm storage metadata.
@@ -175,6 +210,12 @@ type VolumeParams struct {
 	// once the instance is created there are still unprovisioned volumes,
 	// the dynamic storage provisioner will take care of creating them.
 	Attachment *VolumeAttachmentParams
+
+	// Snapshot is the provider-supplied ID of the snapshot from which
+	// the volume should be created, or empty if the volume should be
+	// created empty. Only VolumeSources that implement VolumeSnapshotter
+	// will be asked to create volumes from snapshots.
+	Snapshot string
 }
 
 // VolumeAttachmentParams is a set of parameters for volume attachment or
@@ -191,6 +232,50 @@ type VolumeAttachmentParams struct {
 	VolumeId string
 }
 
+// VolumeResizeParams is a set of parameters for growing a volume.
+type VolumeResizeParams struct {
+	// Tag is the unique tag assigned by Juju for the volume.
+	Tag names.VolumeTag
+
+	// VolumeId is the unique provider-supplied ID for the volume.
+	VolumeId string
+
+	// Size is the new minimum size of the volume in MiB.
+	Size uint64
+}
+
+// FilesystemResizeParams is a set of parameters for growing a
+// filesystem.
+type FilesystemResizeParams struct {
+	// Tag is the unique tag assigned by Juju for the filesystem.
+	Tag names.FilesystemTag
+
+	// FilesystemId is the unique provider-supplied ID for the
+	// filesystem.
+	FilesystemId string
+
+	// Size is the new minimum size of the filesystem in MiB.
+	Size uint64
+}
+
+// SnapshotParams is a set of parameters for taking a snapshot of a
+// volume.
+type SnapshotParams struct {
+	// Id is the unique ID assigned by Juju for the snapshot.
+	Id string
+
+	// Volume is the unique tag assigned by Juju for the volume to
+	// snapshot.
+	Volume names.VolumeTag
+
+	// VolumeId is the unique provider-supplied ID for the volume to
+	// snapshot.
+	VolumeId string
+
+	// Size is the size of the volume in MiB.
+	Size uint64
+}
+
 // AttachmentParams describes the parameters for attaching a volume or
 // filesystem to a machine.
 type AttachmentParams struct {
@@ -280,6 +365,14 @@ type AttachVolumesResult struct {
 	Error            error
 }
 
+// CreateSnapshotsResult contains the result of a
+// VolumeSnapshotter.CreateSnapshots call for one snapshot. Snapshot
+// should only be used if Error is nil.
+type CreateSnapshotsResult struct {
+	Snapshot *SnapshotInfo
+	Error    error
+}
+
 // CreateFilesystemsResult contains the result of a FilesystemSource.CreateFilesystems call
 // for one filesystem. Filesystem should only be used if Error is nil.
 type CreateFilesystemsResult struct {
@@ -5,6 +5,7 @@ package provider
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
@@ -23,6 +24,15 @@ const (
 	HostLoopProviderType = storage.ProviderType("hostloop")
 )
 
+const (
+	// loopSnapshotsDir is the name of the directory, relative to the
+	// storage directory, in which loop volume snapshots are kept.
+	loopSnapshotsDir = "snapshots"
+
+	// loopSnapshotPrefix is the prefix of all loop snapshot IDs.
+	loopSnapshotPrefix = "snapshot-"
+)
+
 // loopProviders create volume sources which use loop devices.
 type loopProvider struct {
 	// run is a function used for running commands on the local machine.
@@ -100,6 +110,8 @@ type loopVolumeSource struct {
 }
 
 var _ storage.VolumeSource = (*loopVolumeSource)(nil)
+var _ storage.VolumeSnapshotter = (*loopVolumeSource)(nil)
+var _ storage.VolumeResizer = (*loopVolumeSource)(nil)
 
 // CreateVolumes is defined on the VolumeSource interface.
 func (lvs *loopVolumeSource) CreateVolumes(args []storage.VolumeParams) ([]storage.CreateVolumesResult, error) {
@@ -120,6 +132,17 @@ func (lvs *loopVolumeSource) createVolume(params storage.VolumeParams) (storage.
 	if err := ensureDir(lvs.dirFuncs, filepath.Dir(loopFilePath)); err != nil {
 		return storage.Volume{}, errors.Trace(err)
 	}
+	if params.Snapshot != "" {
+		if err := validateLoopSnapshotId(params.Snapshot); err != nil {
+			return storage.Volume{}, errors.Trace(err)
+		}
+		snapshotFilePath := lvs.snapshotFilePath(params.Snapshot)
+		if err := copyBlockFile(lvs.run, snapshotFilePath, loopFilePath); err != nil {
+			return storage.Volume{}, errors.Annotate(err, "could not restore snapshot")
+		}
+	}
+	// If the volume was restored from a snapshot, createBlockFile
+	// will grow it to the requested size.
 	if err := createBlockFile(lvs.run, loopFilePath, params.Size); err != nil {
 		return storage.Volume{}, errors.Annotate(err, "could not create block file")
 	}
@@ -136,6 +159,10 @@ func (lvs *loopVolumeSource) volumeFilePath(tag names.VolumeTag) string {
 	return filepath.Join(lvs.storageDir, tag.String())
 }
 
+func (lvs *loopVolumeSource) snapshotFilePath(snapshotId string) string {
+	return filepath.Join(lvs.storageDir, loopSnapshotsDir, snapshotId)
+}
+
 // ListVolumes is defined on the VolumeSource interface.
 func (lvs *loopVolumeSource) ListVolumes() ([]string, error) {
 	// TODO(axw) implement this when we need it.
@@ -239,6 +266,133 @@ func (lvs *loopVolumeSource) detachVolume(tag names.VolumeTag) error {
 	return nil
 }
 
+// ResizeVolumes is defined on the VolumeResizer interface.
+func (lvs *loopVolumeSource) ResizeVolumes(args []storage.VolumeResizeParams) ([]error, error) {
+	results := make([]error, len(args))
+	for i, arg := range args {
+		if err := lvs.resizeVolume(arg); err != nil {
+			results[i] = errors.Annotatef(err, "resizing volume %s", arg.Tag.Id())
+		}
+	}
+	return results, nil
+}
+
+func (lvs *loopVolumeSource) resizeVolume(arg storage.VolumeResizeParams) error {
+	loopFilePath := lvs.volumeFilePath(arg.Tag)
+	if _, err := os.Stat(loopFilePath); err != nil {
+		return errors.Trace(err)
+	}
+	// fallocate only ever grows the file, so the data
+	// in the volume is preserved.
+	if err := createBlockFile(lvs.run, loopFilePath, arg.Size); err != nil {
+		return errors.Trace(err)
+	}
+	// Any attached loop devices must be told to re-read
+	// the size of the backing file.
+	deviceNames, err := associatedLoopDevices(lvs.run, loopFilePath)
+	if err != nil {
+		return errors.Annotate(err, "locating loop device")
+	}
+	for _, deviceName := range deviceNames {
+		if _, err := lvs.run("losetup", "-c", path.Join("/dev", deviceName)); err != nil {
+			return errors.Annotatef(err, "updating size of loop device %q", deviceName)
+		}
+	}
+	return nil
+}
+
+// CreateSnapshots is defined on the VolumeSnapshotter interface.
+//
+// Loop snapshots are sparse copies of the volumes' backing files. The
+// copies are taken while the volumes may be in use, so the snapshots
+// are only crash-consistent.
+func (lvs *loopVolumeSource) CreateSnapshots(args []storage.SnapshotParams) ([]storage.CreateSnapshotsResult, error) {
+	results := make([]storage.CreateSnapshotsResult, len(args))
+	for i, arg := range args {
+		snapshot, err := lvs.createSnapshot(arg)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "creating snapshot %s", arg.Id)
+			continue
+		}
+		results[i].Snapshot = snapshot
+	}
+	return results, nil
+}
+
+func (lvs *loopVolumeSource) createSnapshot(arg storage.SnapshotParams) (*storage.SnapshotInfo, error) {
+	snapshotId := loopSnapshotPrefix + strings.Replace(arg.Id, "/", "-", -1)
+	snapshotFilePath := lvs.snapshotFilePath(snapshotId)
+	if err := ensureDir(lvs.dirFuncs, filepath.Dir(snapshotFilePath)); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := copyBlockFile(lvs.run, lvs.volumeFilePath(arg.Volume), snapshotFilePath); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &storage.SnapshotInfo{
+		SnapshotId: snapshotId,
+		Size:       arg.Size,
+	}, nil
+}
+
+// ListSnapshots is defined on the VolumeSnapshotter interface.
+func (lvs *loopVolumeSource) ListSnapshots() ([]string, error) {
+	fileInfos, err := ioutil.ReadDir(filepath.Join(lvs.storageDir, loopSnapshotsDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Annotate(err, "listing snapshots")
+	}
+	var snapshotIds []string
+	for _, fi := range fileInfos {
+		if fi.Mode().IsRegular() && strings.HasPrefix(fi.Name(), loopSnapshotPrefix) {
+			snapshotIds = append(snapshotIds, fi.Name())
+		}
+	}
+	return snapshotIds, nil
+}
+
+// DeleteSnapshots is defined on the VolumeSnapshotter interface.
+func (lvs *loopVolumeSource) DeleteSnapshots(snapshotIds []string) ([]error, error) {
+	results := make([]error, len(snapshotIds))
+	for i, snapshotId := range snapshotIds {
+		if err := lvs.deleteSnapshot(snapshotId); err != nil {
+			results[i] = errors.Annotatef(err, "deleting %q", snapshotId)
+		}
+	}
+	return results, nil
+}
+
+func (lvs *loopVolumeSource) deleteSnapshot(snapshotId string) error {
+	if err := validateLoopSnapshotId(snapshotId); err != nil {
+		return errors.Trace(err)
+	}
+	err := os.Remove(lvs.snapshotFilePath(snapshotId))
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Annotate(err, "removing snapshot file")
+	}
+	return nil
+}
+
+// validateLoopSnapshotId checks that the given string is a snapshot
+// ID created by the loop provider, so that it may not be used to refer
+// to files outside of the snapshots directory.
+func validateLoopSnapshotId(snapshotId string) error {
+	if !strings.HasPrefix(snapshotId, loopSnapshotPrefix) || strings.ContainsRune(snapshotId, os.PathSeparator) {
+		return errors.Errorf("invalid loop snapshot ID %q", snapshotId)
+	}
+	return nil
+}
+
+// copyBlockFile copies the file at the source path to the target
+// path, preserving any holes in the file.
+func copyBlockFile(run runCommandFunc, source, target string) error {
+	_, err := run("cp", "--sparse=always", source, target)
+	if err != nil {
+		return errors.Annotatef(err, "copying %q to %q", source, target)
+	}
+	return nil
+}
+
 // createBlockFile creates a file at the specified path, with the
 // given size in mebibytes.
 func createBlockFile(run runCommandFunc, filePath string, sizeInMiB uint64) error {
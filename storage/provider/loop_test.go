@@ -155,6 +155,120 @@ func (s *loopSuite) TestDescribeVolumes(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
 }
 
+func (s *loopSuite) TestCreateSnapshots(c *gc.C) {
+	source, dirFuncs := s.loopVolumeSource(c)
+	snapshotsDir := filepath.Join(s.storageDir, "snapshots")
+	s.commands.expect("cp", "--sparse=always",
+		filepath.Join(s.storageDir, "volume-0-1"),
+		filepath.Join(snapshotsDir, "snapshot-0-3"),
+	)
+
+	results, err := source.(storage.VolumeSnapshotter).CreateSnapshots([]storage.SnapshotParams{{
+		Id:       "0/3",
+		Volume:   names.NewVolumeTag("0/1"),
+		VolumeId: "volume-0-1",
+		Size:     2,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.CreateSnapshotsResult{{
+		Snapshot: &storage.SnapshotInfo{
+			SnapshotId: "snapshot-0-3",
+			Size:       2,
+		},
+	}})
+	c.Assert(dirFuncs.Dirs.Contains(snapshotsDir), jc.IsTrue)
+}
+
+func (s *loopSuite) TestCreateVolumesFromSnapshot(c *gc.C) {
+	source, _ := s.loopVolumeSource(c)
+	volumeFile := filepath.Join(s.storageDir, "volume-0")
+	s.commands.expect("cp", "--sparse=always", filepath.Join(s.storageDir, "snapshots", "snapshot-3"), volumeFile)
+	s.commands.expect("fallocate", "-l", "4MiB", volumeFile)
+
+	results, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		Size:     4,
+		Snapshot: "snapshot-3",
+	}, {
+		Tag:      names.NewVolumeTag("1"),
+		Size:     4,
+		Snapshot: "../volume-2",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume.VolumeId, gc.Equals, "volume-0")
+	c.Assert(results[1].Error, gc.ErrorMatches, `creating volume: invalid loop snapshot ID "../volume-2"`)
+}
+
+func (s *loopSuite) TestListSnapshots(c *gc.C) {
+	source, _ := s.loopVolumeSource(c)
+	snapshotter := source.(storage.VolumeSnapshotter)
+
+	snapshotIds, err := snapshotter.ListSnapshots()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshotIds, gc.HasLen, 0)
+
+	snapshotsDir := filepath.Join(s.storageDir, "snapshots")
+	err = os.Mkdir(snapshotsDir, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, name := range []string{"snapshot-0", "snapshot-1-2", "junk"} {
+		err := ioutil.WriteFile(filepath.Join(snapshotsDir, name), nil, 0644)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	snapshotIds, err = snapshotter.ListSnapshots()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshotIds, jc.SameContents, []string{"snapshot-0", "snapshot-1-2"})
+}
+
+func (s *loopSuite) TestDeleteSnapshots(c *gc.C) {
+	source, _ := s.loopVolumeSource(c)
+	snapshotsDir := filepath.Join(s.storageDir, "snapshots")
+	err := os.Mkdir(snapshotsDir, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	fileName := filepath.Join(snapshotsDir, "snapshot-0")
+	err = ioutil.WriteFile(fileName, nil, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	errs, err := source.(storage.VolumeSnapshotter).DeleteSnapshots([]string{
+		"snapshot-0", "snapshot-1", "volume-0",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, gc.HasLen, 3)
+	c.Assert(errs[0], jc.ErrorIsNil)
+	c.Assert(errs[1], jc.ErrorIsNil)
+	c.Assert(errs[2], gc.ErrorMatches, `deleting "volume-0": invalid loop snapshot ID "volume-0"`)
+
+	_, err = os.Stat(fileName)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *loopSuite) TestResizeVolumes(c *gc.C) {
+	source, _ := s.loopVolumeSource(c)
+	fileName := filepath.Join(s.storageDir, "volume-0")
+	err := ioutil.WriteFile(fileName, nil, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.commands.expect("fallocate", "-l", "8MiB", fileName)
+	cmd := s.commands.expect("losetup", "-j", fileName)
+	cmd.respond("/dev/loop0: foo\n", nil)
+	s.commands.expect("losetup", "-c", "/dev/loop0")
+
+	errs, err := source.(storage.VolumeResizer).ResizeVolumes([]storage.VolumeResizeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		VolumeId: "volume-0",
+		Size:     8,
+	}, {
+		Tag:      names.NewVolumeTag("1"),
+		VolumeId: "volume-1",
+		Size:     8,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, gc.HasLen, 2)
+	c.Assert(errs[0], jc.ErrorIsNil)
+	c.Assert(errs[1], gc.ErrorMatches, "resizing volume 1: .*no such file or directory")
+}
+
 func (s *loopSuite) TestAttachVolumes(c *gc.C) {
 	source, _ := s.loopVolumeSource(c)
 	cmd := s.commands.expect("losetup", "-j", filepath.Join(s.storageDir, "volume-0"))
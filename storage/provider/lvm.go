@@ -0,0 +1,347 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	// LVMProviderType is the provider type for volumes which are
+	// logical volumes in an LVM volume group on the machine.
+	LVMProviderType = storage.ProviderType("lvm")
+
+	// LVMVolumeGroup is the name of the pool attribute holding the
+	// name of the volume group in which logical volumes are created.
+	LVMVolumeGroup = "volume-group"
+
+	// LVMThinPool is the name of the optional pool attribute holding
+	// the name of a thin pool within the volume group. If specified,
+	// volumes are created as thinly provisioned logical volumes in
+	// the thin pool.
+	LVMThinPool = "thin-pool"
+)
+
+// lvmNameRule matches the names LVM accepts for volume groups and
+// logical volumes, other than "." and "..", which it rejects.
+var lvmNameRule = regexp.MustCompile(`^[a-zA-Z0-9+_.][a-zA-Z0-9+_.-]*$`)
+
+// validLVMName reports whether name is acceptable to LVM as the name
+// of a volume group or logical volume.
+func validLVMName(name string) bool {
+	return name != "." && name != ".." && lvmNameRule.MatchString(name)
+}
+
+// lvmProvider creates volume sources which use LVM logical volumes.
+type lvmProvider struct {
+	// run is a function used for running commands on the local machine.
+	run runCommandFunc
+}
+
+var _ storage.Provider = (*lvmProvider)(nil)
+
+// ValidateConfig is defined on the Provider interface.
+func (*lvmProvider) ValidateConfig(cfg *storage.Config) error {
+	_, _, err := lvmAttributes(cfg.Attrs())
+	return err
+}
+
+// lvmAttributes returns the volume group and thin pool specified in the
+// given pool attributes, validating them.
+func lvmAttributes(attrs map[string]interface{}) (volumeGroup, thinPool string, _ error) {
+	volumeGroup, _ = attrs[LVMVolumeGroup].(string)
+	if volumeGroup == "" {
+		return "", "", errors.New("volume group not specified")
+	}
+	if !validLVMName(volumeGroup) {
+		return "", "", errors.Errorf("invalid volume group name %q", volumeGroup)
+	}
+	if value, ok := attrs[LVMThinPool]; ok {
+		thinPool, _ = value.(string)
+		if !validLVMName(thinPool) {
+			return "", "", errors.Errorf("invalid thin pool name %v", value)
+		}
+	}
+	return volumeGroup, thinPool, nil
+}
+
+// VolumeSource is defined on the Provider interface.
+func (lp *lvmProvider) VolumeSource(
+	environConfig *config.Config,
+	sourceConfig *storage.Config,
+) (storage.VolumeSource, error) {
+	// The volume group and thin pool are taken from each volume's
+	// attributes, and recorded in its volume ID, so the source
+	// itself needs no configuration.
+	return &lvmVolumeSource{lp.run}, nil
+}
+
+// FilesystemSource is defined on the Provider interface.
+func (lp *lvmProvider) FilesystemSource(
+	environConfig *config.Config,
+	providerConfig *storage.Config,
+) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+// Supports is defined on the Provider interface.
+func (*lvmProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// Scope is defined on the Provider interface.
+func (*lvmProvider) Scope() storage.Scope {
+	return storage.ScopeMachine
+}
+
+// Dynamic is defined on the Provider interface.
+func (*lvmProvider) Dynamic() bool {
+	return true
+}
+
+// lvmVolumeSource creates and manages logical volumes, optionally
+// thinly provisioned from a thin pool. The volume ID of each volume
+// is its volume group-qualified logical volume name, e.g.
+// "vg0/volume-0-1".
+type lvmVolumeSource struct {
+	run runCommandFunc
+}
+
+var _ storage.VolumeSource = (*lvmVolumeSource)(nil)
+var _ storage.VolumeResizer = (*lvmVolumeSource)(nil)
+
+// CreateVolumes is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) CreateVolumes(args []storage.VolumeParams) ([]storage.CreateVolumesResult, error) {
+	results := make([]storage.CreateVolumesResult, len(args))
+	for i, arg := range args {
+		volume, err := lvs.createVolume(arg)
+		if err != nil {
+			results[i].Error = errors.Annotate(err, "creating volume")
+			continue
+		}
+		results[i].Volume = &volume
+	}
+	return results, nil
+}
+
+func (lvs *lvmVolumeSource) createVolume(params storage.VolumeParams) (storage.Volume, error) {
+	volumeGroup, thinPool, err := lvmAttributes(params.Attributes)
+	if err != nil {
+		return storage.Volume{}, errors.Trace(err)
+	}
+	// Logical volumes are named after the volume tag, so that the
+	// volumes belonging to Juju can be identified in the volume group.
+	name := params.Tag.String()
+	size := fmt.Sprintf("%dm", params.Size)
+	args := []string{"--yes", "-n", name}
+	if thinPool != "" {
+		// -V gives the virtual size of a thin volume, and -T the
+		// thin pool from which its blocks are allocated on demand.
+		args = append(args, "-V", size, "-T", volumeGroup+"/"+thinPool)
+	} else {
+		args = append(args, "-L", size, volumeGroup)
+	}
+	if _, err := lvs.run("lvcreate", args...); err != nil {
+		return storage.Volume{}, errors.Annotatef(err, "creating logical volume %q", name)
+	}
+	return storage.Volume{
+		params.Tag,
+		storage.VolumeInfo{
+			VolumeId: volumeGroup + "/" + name,
+			Size:     params.Size,
+		},
+	}, nil
+}
+
+// validateVolumeId checks that the specified volume ID identifies a
+// logical volume created by Juju.
+func validateVolumeId(volumeId string) error {
+	parts := strings.Split(volumeId, "/")
+	if len(parts) != 2 || !validLVMName(parts[0]) {
+		return errors.Errorf("invalid lvm volume ID %q", volumeId)
+	}
+	if _, err := names.ParseVolumeTag(parts[1]); err != nil {
+		return errors.Errorf("invalid lvm volume ID %q", volumeId)
+	}
+	return nil
+}
+
+// ListVolumes is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) ListVolumes() ([]string, error) {
+	sizes, err := lvs.volumeSizes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	volumeIds := make([]string, 0, len(sizes))
+	for volumeId := range sizes {
+		volumeIds = append(volumeIds, volumeId)
+	}
+	return volumeIds, nil
+}
+
+// DescribeVolumes is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) DescribeVolumes(volumeIds []string) ([]storage.DescribeVolumesResult, error) {
+	sizes, err := lvs.volumeSizes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]storage.DescribeVolumesResult, len(volumeIds))
+	for i, volumeId := range volumeIds {
+		size, ok := sizes[volumeId]
+		if !ok {
+			results[i].Error = errors.NotFoundf("logical volume %q", volumeId)
+			continue
+		}
+		results[i].VolumeInfo = &storage.VolumeInfo{
+			VolumeId: volumeId,
+			Size:     size,
+		}
+	}
+	return results, nil
+}
+
+// volumeSizes returns the sizes, in MiB, of the Juju-managed logical
+// volumes on the machine, keyed by volume ID.
+func (lvs *lvmVolumeSource) volumeSizes() (map[string]uint64, error) {
+	stdout, err := lvs.run(
+		"lvs", "--noheadings", "--nosuffix", "--units", "m",
+		"-o", "vg_name,lv_name,lv_size",
+	)
+	if err != nil {
+		return nil, errors.Annotate(err, "listing logical volumes")
+	}
+	// The output will be zero or more lines with the format:
+	//    "  vg0   volume-0     1024.00"
+	sizes := make(map[string]uint64)
+	for _, line := range strings.Split(stdout, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, errors.Errorf("unexpected output %q", line)
+		}
+		volumeId := fields[0] + "/" + fields[1]
+		if err := validateVolumeId(volumeId); err != nil {
+			// Not a volume created by Juju.
+			continue
+		}
+		size, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return nil, errors.Errorf("unexpected size in output %q", line)
+		}
+		sizes[volumeId] = uint64(size)
+	}
+	return sizes, nil
+}
+
+// DestroyVolumes is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) DestroyVolumes(volumeIds []string) ([]error, error) {
+	results := make([]error, len(volumeIds))
+	for i, volumeId := range volumeIds {
+		if err := lvs.destroyVolume(volumeId); err != nil {
+			results[i] = errors.Annotatef(err, "destroying %q", volumeId)
+		}
+	}
+	return results, nil
+}
+
+func (lvs *lvmVolumeSource) destroyVolume(volumeId string) error {
+	if err := validateVolumeId(volumeId); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := lvs.run("lvremove", "-f", volumeId); err != nil {
+		return errors.Annotate(err, "removing logical volume")
+	}
+	return nil
+}
+
+// ValidateVolumeParams is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	// ValidateVolumeParams may be called on a machine other than the
+	// machine where the logical volume will be created, so we cannot
+	// check the free space in the volume group until CreateVolumes.
+	_, _, err := lvmAttributes(params.Attributes)
+	return err
+}
+
+// AttachVolumes is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) AttachVolumes(args []storage.VolumeAttachmentParams) ([]storage.AttachVolumesResult, error) {
+	results := make([]storage.AttachVolumesResult, len(args))
+	for i, arg := range args {
+		attachment, err := lvs.attachVolume(arg)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "attaching volume %v", arg.Volume.Id())
+			continue
+		}
+		results[i].VolumeAttachment = attachment
+	}
+	return results, nil
+}
+
+func (lvs *lvmVolumeSource) attachVolume(arg storage.VolumeAttachmentParams) (*storage.VolumeAttachment, error) {
+	if err := validateVolumeId(arg.VolumeId); err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Logical volumes exist only on the machine that created them, so
+	// attaching a volume means activating it, with the requested
+	// permissions.
+	permission := "rw"
+	if arg.ReadOnly {
+		permission = "r"
+	}
+	if _, err := lvs.run("lvchange", "-p", permission, arg.VolumeId); err != nil {
+		// lvchange fails if the permission is unchanged, which
+		// is expected when reattaching after a restart.
+		logger.Debugf("setting permission of %q: %v", arg.VolumeId, err)
+	}
+	if _, err := lvs.run("lvchange", "-a", "y", arg.VolumeId); err != nil {
+		return nil, errors.Annotatef(err, "activating logical volume %q", arg.VolumeId)
+	}
+	return &storage.VolumeAttachment{
+		arg.Volume,
+		arg.Machine,
+		storage.VolumeAttachmentInfo{
+			// The device-mapper device name may change across
+			// restarts, but the volume group's link will not.
+			DeviceLink: path.Join("/dev", arg.VolumeId),
+			ReadOnly:   arg.ReadOnly,
+		},
+	}, nil
+}
+
+// DetachVolumes is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) DetachVolumes(args []storage.VolumeAttachmentParams) ([]error, error) {
+	results := make([]error, len(args))
+	for i, arg := range args {
+		if _, err := lvs.run("lvchange", "-a", "n", arg.VolumeId); err != nil {
+			results[i] = errors.Annotatef(err, "detaching volume %s", arg.Volume.Id())
+		}
+	}
+	return results, nil
+}
+
+// ResizeVolumes is defined on the VolumeResizer interface.
+func (lvs *lvmVolumeSource) ResizeVolumes(args []storage.VolumeResizeParams) ([]error, error) {
+	results := make([]error, len(args))
+	for i, arg := range args {
+		// lvextend refuses to shrink a volume, which would
+		// destroy any data stored past the new size.
+		size := fmt.Sprintf("%dm", arg.Size)
+		if _, err := lvs.run("lvextend", "-L", size, arg.VolumeId); err != nil {
+			results[i] = errors.Annotatef(err, "resizing volume %s", arg.Tag.Id())
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,235 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider_test
+
+import (
+	"errors"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&lvmSuite{})
+
+type lvmSuite struct {
+	testing.BaseSuite
+	commands *mockRunCommand
+}
+
+func (s *lvmSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.commands = &mockRunCommand{c: c}
+}
+
+func (s *lvmSuite) TearDownTest(c *gc.C) {
+	s.commands.assertDrained()
+	s.BaseSuite.TearDownTest(c)
+}
+
+func (s *lvmSuite) lvmVolumeSource(c *gc.C) storage.VolumeSource {
+	p := provider.LVMProvider(s.commands.run)
+	cfg, err := storage.NewConfig("lvm", provider.LVMProviderType, map[string]interface{}{})
+	c.Assert(err, jc.ErrorIsNil)
+	source, err := p.VolumeSource(nil, cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	return source
+}
+
+var lvmAttributes = map[string]interface{}{"volume-group": "vg0"}
+
+func (s *lvmSuite) TestValidateConfig(c *gc.C) {
+	p := provider.LVMProvider(s.commands.run)
+	for i, test := range []struct {
+		attrs map[string]interface{}
+		err   string
+	}{{
+		attrs: map[string]interface{}{},
+		err:   "volume group not specified",
+	}, {
+		attrs: map[string]interface{}{"volume-group": "-vg"},
+		err:   `invalid volume group name "-vg"`,
+	}, {
+		attrs: map[string]interface{}{"volume-group": "."},
+		err:   `invalid volume group name "."`,
+	}, {
+		attrs: map[string]interface{}{"volume-group": "vg0", "thin-pool": ".."},
+		err:   `invalid thin pool name ..`,
+	}, {
+		attrs: map[string]interface{}{"volume-group": "vg0", "thin-pool": 5},
+		err:   "invalid thin pool name 5",
+	}, {
+		attrs: map[string]interface{}{"volume-group": "vg0"},
+	}, {
+		attrs: map[string]interface{}{"volume-group": "vg0", "thin-pool": "pool0"},
+	}} {
+		c.Logf("test %d: %v", i, test.attrs)
+		cfg, err := storage.NewConfig("name", provider.LVMProviderType, test.attrs)
+		c.Assert(err, jc.ErrorIsNil)
+		err = p.ValidateConfig(cfg)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *lvmSuite) TestSupports(c *gc.C) {
+	p := provider.LVMProvider(s.commands.run)
+	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsTrue)
+	c.Assert(p.Supports(storage.StorageKindFilesystem), jc.IsFalse)
+	c.Assert(p.Scope(), gc.Equals, storage.ScopeMachine)
+	c.Assert(p.Dynamic(), jc.IsTrue)
+}
+
+func (s *lvmSuite) TestCreateVolumes(c *gc.C) {
+	source := s.lvmVolumeSource(c)
+	s.commands.expect("lvcreate", "--yes", "-n", "volume-0", "-L", "2048m", "vg0")
+	s.commands.expect("lvcreate", "--yes", "-n", "volume-1", "-L", "1024m", "vg0").respond("", errors.New("insufficient free space"))
+
+	results, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("0"),
+		Size:       2048,
+		Attributes: lvmAttributes,
+	}, {
+		Tag:        names.NewVolumeTag("1"),
+		Size:       1024,
+		Attributes: lvmAttributes,
+	}, {
+		Tag:  names.NewVolumeTag("2"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 3)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume, jc.DeepEquals, &storage.Volume{
+		names.NewVolumeTag("0"),
+		storage.VolumeInfo{
+			VolumeId: "vg0/volume-0",
+			Size:     2048,
+		},
+	})
+	c.Assert(results[1].Error, gc.ErrorMatches, `creating volume: creating logical volume "volume-1": insufficient free space`)
+	c.Assert(results[2].Error, gc.ErrorMatches, `creating volume: volume group not specified`)
+}
+
+func (s *lvmSuite) TestCreateVolumesThinPool(c *gc.C) {
+	source := s.lvmVolumeSource(c)
+	s.commands.expect("lvcreate", "--yes", "-n", "volume-0-1", "-V", "2048m", "-T", "vg0/pool0")
+
+	results, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0/1"),
+		Size: 2048,
+		Attributes: map[string]interface{}{
+			"volume-group": "vg0",
+			"thin-pool":    "pool0",
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume.VolumeId, gc.Equals, "vg0/volume-0-1")
+}
+
+func (s *lvmSuite) TestValidateVolumeParams(c *gc.C) {
+	source := s.lvmVolumeSource(c)
+	err := source.ValidateVolumeParams(storage.VolumeParams{Attributes: lvmAttributes})
+	c.Assert(err, jc.ErrorIsNil)
+	err = source.ValidateVolumeParams(storage.VolumeParams{})
+	c.Assert(err, gc.ErrorMatches, "volume group not specified")
+}
+
+func (s *lvmSuite) TestDescribeVolumes(c *gc.C) {
+	source := s.lvmVolumeSource(c)
+	lvs := s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "-o", "vg_name,lv_name,lv_size")
+	lvs.respond("  vg0 pool0      10240.00\n  vg0 volume-0    2048.00\n  vg1 volume-0-1  1024.00\n", nil)
+
+	results, err := source.DescribeVolumes([]string{"vg1/volume-0-1", "vg0/volume-2"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].VolumeInfo, jc.DeepEquals, &storage.VolumeInfo{
+		VolumeId: "vg1/volume-0-1",
+		Size:     1024,
+	})
+	c.Assert(results[1].Error, gc.ErrorMatches, `logical volume "vg0/volume-2" not found`)
+}
+
+func (s *lvmSuite) TestListVolumes(c *gc.C) {
+	source := s.lvmVolumeSource(c)
+	lvs := s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "-o", "vg_name,lv_name,lv_size")
+	lvs.respond("  vg0 pool0      10240.00\n  vg0 volume-0    2048.00\n", nil)
+
+	volumeIds, err := source.ListVolumes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeIds, jc.DeepEquals, []string{"vg0/volume-0"})
+}
+
+func (s *lvmSuite) TestDestroyVolumes(c *gc.C) {
+	source := s.lvmVolumeSource(c)
+	s.commands.expect("lvremove", "-f", "vg0/volume-0")
+
+	errs, err := source.DestroyVolumes([]string{"vg0/volume-0", "vg0/pool0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, gc.HasLen, 2)
+	c.Assert(errs[0], jc.ErrorIsNil)
+	c.Assert(errs[1], gc.ErrorMatches, `destroying "vg0/pool0": invalid lvm volume ID "vg0/pool0"`)
+}
+
+func (s *lvmSuite) TestAttachVolumes(c *gc.C) {
+	source := s.lvmVolumeSource(c)
+	s.commands.expect("lvchange", "-p", "r", "vg0/volume-0").respond("", errors.New("already read-only"))
+	s.commands.expect("lvchange", "-a", "y", "vg0/volume-0")
+
+	results, err := source.AttachVolumes([]storage.VolumeAttachmentParams{{
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "vg0/volume-0",
+		AttachmentParams: storage.AttachmentParams{
+			Machine:  names.NewMachineTag("0"),
+			ReadOnly: true,
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].VolumeAttachment, jc.DeepEquals, &storage.VolumeAttachment{
+		names.NewVolumeTag("0"),
+		names.NewMachineTag("0"),
+		storage.VolumeAttachmentInfo{
+			DeviceLink: "/dev/vg0/volume-0",
+			ReadOnly:   true,
+		},
+	})
+}
+
+func (s *lvmSuite) TestDetachVolumes(c *gc.C) {
+	source := s.lvmVolumeSource(c)
+	s.commands.expect("lvchange", "-a", "n", "vg0/volume-0").respond("", errors.New("device busy"))
+
+	errs, err := source.DetachVolumes([]storage.VolumeAttachmentParams{{
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "vg0/volume-0",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, gc.HasLen, 1)
+	c.Assert(errs[0], gc.ErrorMatches, "detaching volume 0: device busy")
+}
+
+func (s *lvmSuite) TestResizeVolumes(c *gc.C) {
+	source := s.lvmVolumeSource(c).(storage.VolumeResizer)
+	s.commands.expect("lvextend", "-L", "4096m", "vg0/volume-0")
+
+	errs, err := source.ResizeVolumes([]storage.VolumeResizeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		VolumeId: "vg0/volume-0",
+		Size:     4096,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil})
+}
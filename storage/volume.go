@@ -73,3 +73,12 @@ type VolumeAttachmentInfo struct {
 	// ReadOnly signifies whether the volume is read only or writable.
 	ReadOnly bool
 }
+
+// SnapshotInfo describes a point-in-time snapshot of a volume.
+type SnapshotInfo struct {
+	// SnapshotId is a unique provider-supplied ID for the snapshot.
+	SnapshotId string
+
+	// Size is the size of the snapshotted volume, in MiB.
+	Size uint64
+}
@@ -0,0 +1,108 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cron_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/utils/cron"
+)
+
+type cronSuite struct{}
+
+var _ = gc.Suite(&cronSuite{})
+
+var parseErrorTests = []struct {
+	spec string
+	err  string
+}{{
+	spec: "",
+	err:  `cron spec "": expected 5 fields, got 0`,
+}, {
+	spec: "* * * *",
+	err:  `cron spec "\* \* \* \*": expected 5 fields, got 4`,
+}, {
+	spec: "60 * * * *",
+	err:  `cron spec "60 \* \* \* \*": minute 60 out of range \[0, 59\]`,
+}, {
+	spec: "* 1-x * * *",
+	err:  `cron spec "\* 1-x \* \* \*": invalid hour "x"`,
+}, {
+	spec: "* * 5-1 * *",
+	err:  `cron spec "\* \* 5-1 \* \*": invalid range in day of month "5-1"`,
+}, {
+	spec: "*/0 * * * *",
+	err:  `cron spec "\*/0 \* \* \* \*": invalid step in minute "\*/0"`,
+}, {
+	spec: "* * * 0 *",
+	err:  `cron spec "\* \* \* 0 \*": month 0 out of range \[1, 12\]`,
+}, {
+	spec: "@fortnightly",
+	err:  `cron spec "@fortnightly": expected 5 fields, got 1`,
+}}
+
+func (*cronSuite) TestParseErrors(c *gc.C) {
+	for i, test := range parseErrorTests {
+		c.Logf("test %d: %q", i, test.spec)
+		_, err := cron.Parse(test.spec)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+var nextTests = []struct {
+	spec string
+	from string
+	next string
+}{{
+	spec: "* * * * *",
+	from: "2015-10-01T12:00:30Z",
+	next: "2015-10-01T12:01:00Z",
+}, {
+	spec: "30 2 * * *",
+	from: "2015-10-01T12:00:00Z",
+	next: "2015-10-02T02:30:00Z",
+}, {
+	spec: "*/15 * * * *",
+	from: "2015-10-01T12:50:00Z",
+	next: "2015-10-01T13:00:00Z",
+}, {
+	spec: "0 9-17/4 * * 1-5",
+	from: "2015-10-02T18:00:00Z", // Friday
+	next: "2015-10-05T09:00:00Z", // Monday
+}, {
+	spec: "0 0 * * 7",
+	from: "2015-10-01T00:00:00Z",
+	next: "2015-10-04T00:00:00Z",
+}, {
+	spec: "0 0 1,15 * 3",
+	from: "2015-10-01T00:00:00Z",
+	next: "2015-10-07T00:00:00Z",
+}, {
+	spec: "@monthly",
+	from: "2015-12-31T23:59:00Z",
+	next: "2016-01-01T00:00:00Z",
+}, {
+	spec: "0 0 29 2 *",
+	from: "2015-03-01T00:00:00Z",
+	next: "2016-02-29T00:00:00Z",
+}, {
+	spec: "0 0 30 2 *",
+	from: "2015-03-01T00:00:00Z",
+	next: "0001-01-01T00:00:00Z",
+}}
+
+func (*cronSuite) TestNext(c *gc.C) {
+	for i, test := range nextTests {
+		c.Logf("test %d: %q from %s", i, test.spec, test.from)
+		schedule, err := cron.Parse(test.spec)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(schedule.String(), gc.Equals, test.spec)
+		from, err := time.Parse(time.RFC3339, test.from)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(schedule.Next(from).Format(time.RFC3339), gc.Equals, test.next)
+	}
+}
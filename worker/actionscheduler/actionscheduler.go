@@ -0,0 +1,52 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionscheduler
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/worker"
+)
+
+// DefaultInterval is the default period between checks for scheduled
+// actions that are due. Schedules have a resolution of one minute.
+const DefaultInterval = 15 * time.Second
+
+// Facade represents an API that runs scheduled actions.
+type Facade interface {
+	RunDueScheduledActions() error
+}
+
+// Config holds all necessary attributes to start an action scheduler.
+type Config struct {
+	Facade   Facade
+	Interval time.Duration
+	NewTimer worker.NewTimerFunc
+}
+
+// Validate will err unless basic requirements for a valid
+// config are met.
+func (c *Config) Validate() error {
+	if c.Facade == nil {
+		return errors.New("missing Facade")
+	}
+	if c.NewTimer == nil {
+		return errors.New("missing Timer")
+	}
+	return nil
+}
+
+// New returns a worker that periodically enqueues every scheduled
+// action whose next run time has passed.
+func New(conf Config) (worker.Worker, error) {
+	if err := conf.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	runDue := func(stop <-chan struct{}) error {
+		return errors.Trace(conf.Facade.RunDueScheduledActions())
+	}
+	return worker.NewPeriodicWorker(runDue, conf.Interval, conf.NewTimer), nil
+}
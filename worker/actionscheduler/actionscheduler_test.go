@@ -0,0 +1,90 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionscheduler_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/actionscheduler"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type schedulerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&schedulerSuite{})
+
+func (s *schedulerSuite) TestValidate(c *gc.C) {
+	_, err := actionscheduler.New(actionscheduler.Config{
+		NewTimer: worker.NewTimer,
+	})
+	c.Check(err, gc.ErrorMatches, "missing Facade")
+	_, err = actionscheduler.New(actionscheduler.Config{
+		Facade: newFakeFacade(),
+	})
+	c.Check(err, gc.ErrorMatches, "missing Timer")
+}
+
+func (s *schedulerSuite) TestWorkerRunsDueActions(c *gc.C) {
+	facade := newFakeFacade()
+	scheduler, err := actionscheduler.New(actionscheduler.Config{
+		Facade:   facade,
+		Interval: coretesting.ShortWait,
+		NewTimer: worker.NewTimer,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() {
+		c.Assert(worker.Stop(scheduler), jc.ErrorIsNil)
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-facade.called:
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for scheduled actions to run")
+		}
+	}
+}
+
+func (s *schedulerSuite) TestWorkerFailsOnError(c *gc.C) {
+	facade := newFakeFacade()
+	facade.err = errors.New("boom")
+	scheduler, err := actionscheduler.New(actionscheduler.Config{
+		Facade:   facade,
+		Interval: coretesting.ShortWait,
+		NewTimer: worker.NewTimer,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = scheduler.Wait()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type fakeFacade struct {
+	called chan struct{}
+	err    error
+}
+
+func newFakeFacade() *fakeFacade {
+	return &fakeFacade{called: make(chan struct{}, 10)}
+}
+
+// RunDueScheduledActions implements Facade.
+func (f *fakeFacade) RunDueScheduledActions() error {
+	select {
+	case f.called <- struct{}{}:
+	default:
+	}
+	return f.err
+}
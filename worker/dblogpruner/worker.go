@@ -18,19 +18,25 @@ type LogPruneParams struct {
 	MaxLogAge       time.Duration
 	MaxCollectionMB int
 	PruneInterval   time.Duration
+
+	// MaxImportedLogAge is how long logs imported from an archive
+	// are kept, regardless of their age.
+	MaxImportedLogAge time.Duration
 }
 
 const DefaultMaxLogAge = 3 * 24 * time.Hour // 3 days
 const DefaultMaxCollectionMB = 4 * 1024     // 4 GB
 const DefaultPruneInterval = 5 * time.Minute
+const DefaultMaxImportedLogAge = 24 * time.Hour
 
 // NewLogPruneParams returns a LogPruneParams initialised with default
 // values.
 func NewLogPruneParams() *LogPruneParams {
 	return &LogPruneParams{
-		MaxLogAge:       DefaultMaxLogAge,
-		MaxCollectionMB: DefaultMaxCollectionMB,
-		PruneInterval:   DefaultPruneInterval,
+		MaxLogAge:         DefaultMaxLogAge,
+		MaxCollectionMB:   DefaultMaxCollectionMB,
+		PruneInterval:     DefaultPruneInterval,
+		MaxImportedLogAge: DefaultMaxImportedLogAge,
 	}
 }
 
@@ -57,11 +63,55 @@ func (w *pruneWorker) loop(stopCh <-chan struct{}) error {
 		case <-stopCh:
 			return tomb.ErrDying
 		case <-time.After(p.PruneInterval):
-			minLogTime := time.Now().Add(-p.MaxLogAge)
-			err := state.PruneLogs(w.st, minLogTime, p.MaxCollectionMB)
+			now := time.Now()
+			policies, err := w.prunePolicies(now)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			minLogTime := now.Add(-p.MaxLogAge)
+			err = state.PruneLogs(w.st, minLogTime, p.MaxCollectionMB, policies)
 			if err != nil {
 				return errors.Trace(err)
 			}
 		}
 	}
 }
+
+// prunePolicies returns the log pruning policy of each environment, as
+// set by its log-retention, log-quota-mb, log-archive and
+// log-archive-retention config.
+func (w *pruneWorker) prunePolicies(now time.Time) (map[string]state.LogPrunePolicy, error) {
+	envs, err := w.st.AllEnvironments()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	policies := make(map[string]state.LogPrunePolicy)
+	for _, env := range envs {
+		cfg, err := env.Config()
+		if errors.IsNotFound(err) {
+			// The environment has been removed.
+			continue
+		} else if err != nil {
+			return nil, errors.Annotatef(err, "cannot get config for environment %q", env.UUID())
+		}
+		var policy state.LogPrunePolicy
+		for _, rule := range cfg.LogRetention() {
+			policy.Rules = append(policy.Rules, state.LogPruneRule{
+				Module:     rule.Module,
+				Level:      rule.Level,
+				MinLogTime: now.Add(-rule.MaxAge),
+			})
+		}
+		if quota, ok := cfg.LogQuotaMB(); ok {
+			// The quota is validated as positive when set.
+			policy.MaxLogsMB = quota
+		}
+		policy.Archive = cfg.LogArchive()
+		policy.MinArchiveTime = now.Add(-cfg.LogArchiveRetention())
+		if w.params.MaxImportedLogAge > 0 {
+			policy.MinImportTime = now.Add(-w.params.MaxImportedLogAge)
+		}
+		policies[env.UUID()] = policy
+	}
+	return policies, nil
+}
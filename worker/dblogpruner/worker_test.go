@@ -40,9 +40,10 @@ func (s *suite) SetUpTest(c *gc.C) {
 
 func (s *suite) StartWorker(c *gc.C, maxLogAge time.Duration, maxCollectionMB int) {
 	params := &dblogpruner.LogPruneParams{
-		MaxLogAge:       maxLogAge,
-		MaxCollectionMB: maxCollectionMB,
-		PruneInterval:   time.Millisecond, // Speed up pruning interval for testing
+		MaxLogAge:         maxLogAge,
+		MaxCollectionMB:   maxCollectionMB,
+		PruneInterval:     time.Millisecond, // Speed up pruning interval for testing
+		MaxImportedLogAge: time.Hour,
 	}
 	s.pruner = dblogpruner.New(s.State, params)
 	s.AddCleanup(func(*gc.C) {
@@ -86,6 +87,82 @@ func (s *suite) TestPrunesOldLogs(c *gc.C) {
 	c.Fatal("pruning didn't happen as expected")
 }
 
+func (s *suite) TestPrunesLogsByRetentionRule(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"log-retention": "some.module=1h",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	now := time.Now()
+	s.addLogs(c, now.Add(-2*time.Hour), "prune", 5)
+	s.addLogs(c, now, "keep", 5)
+	s.StartWorker(c, 24*time.Hour, int(1e9))
+
+	for attempt := testing.LongAttempt.Start(); attempt.Next(); {
+		pruneRemaining, err := s.logsColl.Find(bson.M{"x": "prune"}).Count()
+		c.Assert(err, jc.ErrorIsNil)
+		if pruneRemaining == 0 {
+			keepCount, err := s.logsColl.Find(bson.M{"x": "keep"}).Count()
+			c.Assert(err, jc.ErrorIsNil)
+			c.Assert(keepCount, gc.Equals, 5)
+			return
+		}
+	}
+	c.Fatal("pruning didn't happen as expected")
+}
+
+func (s *suite) TestArchivesPrunedLogs(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"log-archive": true,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	maxLogAge := 24 * time.Hour
+	s.addLogs(c, time.Now().Add(-maxLogAge-time.Hour), "prune", 5)
+	s.StartWorker(c, maxLogAge, int(1e9))
+
+	for attempt := testing.LongAttempt.Start(); attempt.Next(); {
+		count, err := s.logsColl.Count()
+		c.Assert(err, jc.ErrorIsNil)
+		if count == 0 {
+			// Logs are archived before they are removed.
+			archives, err := state.LogArchives(s.State, time.Time{}, time.Time{})
+			c.Assert(err, jc.ErrorIsNil)
+			c.Assert(archives, gc.HasLen, 1)
+			c.Assert(archives[0].Count, gc.Equals, 5)
+			_, reader, err := state.OpenLogArchive(s.State, archives[0].Id)
+			c.Assert(err, jc.ErrorIsNil)
+			reader.Close()
+			return
+		}
+	}
+	c.Fatal("archiving didn't happen as expected")
+}
+
+func (s *suite) TestPrunesExpiredArchives(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"log-archive":           true,
+		"log-archive-retention": "48h",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Logs older than the archive retention are archived and then
+	// the archive is removed.
+	s.addLogs(c, time.Now().Add(-72*time.Hour), "prune", 5)
+	s.StartWorker(c, 24*time.Hour, int(1e9))
+
+	for attempt := testing.LongAttempt.Start(); attempt.Next(); {
+		count, err := s.logsColl.Count()
+		c.Assert(err, jc.ErrorIsNil)
+		archives, err := state.LogArchives(s.State, time.Time{}, time.Time{})
+		c.Assert(err, jc.ErrorIsNil)
+		if count == 0 && len(archives) == 0 {
+			return
+		}
+	}
+	c.Fatal("archive pruning didn't happen as expected")
+}
+
 func (s *suite) TestPrunesLogsBySize(c *gc.C) {
 	startingLogCount := 25000
 	s.addLogs(c, time.Now(), "stuff", startingLogCount)
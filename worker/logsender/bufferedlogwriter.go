@@ -13,6 +13,7 @@ import (
 	"github.com/juju/utils/deque"
 
 	"github.com/juju/juju/feature"
+	"github.com/juju/juju/loglabels"
 )
 
 // LogRecord represents a log message in an agent which is to be
@@ -24,6 +25,10 @@ type LogRecord struct {
 	Level    loggo.Level
 	Message  string
 
+	// Labels holds structured key/value fields describing the
+	// record, such as the hook being run; see package loglabels.
+	Labels map[string]string
+
 	// Number of messages dropped after this one due to buffer limit.
 	DroppedAfter int
 }
@@ -132,13 +137,17 @@ func (w *BufferedLogWriter) loop() {
 }
 
 // Write sends a new log message to the writer. This implements the loggo.Writer interface.
+// Any labels attached to the message by a loglabels.Logger are recorded
+// with it.
 func (w *BufferedLogWriter) Write(level loggo.Level, module, filename string, line int, ts time.Time, message string) {
+	labels := loglabels.Attached(module, filename, line, message)
 	w.in <- &LogRecord{
 		Time:     ts,
 		Module:   module,
 		Location: fmt.Sprintf("%s:%d", filepath.Base(filename), line),
 		Level:    level,
 		Message:  message,
+		Labels:   labels,
 	}
 }
 
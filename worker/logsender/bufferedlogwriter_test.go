@@ -12,6 +12,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/loglabels"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/logsender"
 )
@@ -112,6 +113,27 @@ func (s *bufferedLogWriterSuite) TestLimiting(c *gc.C) {
 	}
 }
 
+func (s *bufferedLogWriterSuite) TestLabels(c *gc.C) {
+	err := loggo.RegisterWriter("buffered", s.writer, loggo.TRACE)
+	c.Assert(err, jc.ErrorIsNil)
+	defer loggo.RemoveWriter("buffered")
+
+	logger := loggo.GetLogger("unit.mysql/0.install")
+	logger.SetLogLevel(loggo.INFO)
+	loglabels.NewLogger(logger, map[string]string{"hook": "install"}).Infof("message")
+	rec := s.receiveOne(c)
+	c.Assert(rec.Module, gc.Equals, "unit.mysql/0.install")
+	c.Assert(rec.Location, gc.Matches, `bufferedlogwriter_test.go:\d+`)
+	c.Assert(rec.Message, gc.Equals, "message")
+	c.Assert(rec.Labels, jc.DeepEquals, map[string]string{"hook": "install"})
+
+	// Text that looks like labels is not taken to be labels.
+	logger.Infof(`[hook="install"] message`)
+	rec = s.receiveOne(c)
+	c.Assert(rec.Message, gc.Equals, `[hook="install"] message`)
+	c.Assert(rec.Labels, gc.IsNil)
+}
+
 func (s *bufferedLogWriterSuite) TestClose(c *gc.C) {
 	s.writer.Close()
 	s.shouldClose = false // Prevent the usual teardown (calling Close twice will panic)
@@ -182,3 +182,38 @@ func (s *workerSuite) TestDroppedLogs(c *gc.C) {
 	})
 	c.Assert(docs[2]["x"], gc.Equals, "message1")
 }
+
+func (s *workerSuite) TestLabels(c *gc.C) {
+	logsCh := make(logsender.LogRecordCh)
+
+	// Start the logsender worker.
+	worker := logsender.New(logsCh, s.logSenderAPI())
+	defer func() {
+		worker.Kill()
+		c.Check(worker.Wait(), jc.ErrorIsNil)
+	}()
+
+	logsCh <- &logsender.LogRecord{
+		Time:     time.Now(),
+		Module:   "logsender-test",
+		Location: "loc",
+		Level:    loggo.INFO,
+		Message:  "message",
+		Labels:   map[string]string{"hook": "install", "relation": "1"},
+	}
+
+	// Wait for the log to appear in the database.
+	var docs []bson.M
+	logsColl := s.State.MongoSession().DB("logs").C("logs")
+	for a := testing.LongAttempt.Start(); a.Next(); {
+		if !a.HasNext() {
+			c.Fatal("timed out waiting for logs")
+		}
+		err := logsColl.Find(bson.M{"m": "logsender-test"}).All(&docs)
+		c.Assert(err, jc.ErrorIsNil)
+		if len(docs) == 1 {
+			break
+		}
+	}
+	c.Assert(docs[0]["f"], gc.DeepEquals, bson.M{"hook": "install", "relation": "1"})
+}
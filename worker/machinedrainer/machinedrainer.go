@@ -0,0 +1,63 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinedrainer
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/worker"
+)
+
+// DefaultDrainInterval is the default period between checks on the
+// progress of machine drains.
+const DefaultDrainInterval = 10 * time.Second
+
+// DefaultReplacementTimeout is the default time a replacement unit has
+// to settle before the drain is abandoned.
+const DefaultReplacementTimeout = 30 * time.Minute
+
+// Facade represents an API that advances machine drains.
+type Facade interface {
+	AdvanceMachineDrains(replacementTimeout time.Duration) error
+}
+
+// Config holds all necessary attributes to start a machine drainer.
+type Config struct {
+	Facade             Facade
+	Interval           time.Duration
+	ReplacementTimeout time.Duration
+	NewTimer           worker.NewTimerFunc
+}
+
+// Validate will err unless basic requirements for a valid
+// config are met.
+func (c *Config) Validate() error {
+	if c.Facade == nil {
+		return errors.New("missing Facade")
+	}
+	if c.NewTimer == nil {
+		return errors.New("missing Timer")
+	}
+	if c.ReplacementTimeout <= 0 {
+		return errors.New("non-positive ReplacementTimeout")
+	}
+	return nil
+}
+
+// New returns a worker that periodically advances every machine drain
+// in progress: adding replacement units on other machines, waiting for
+// them to settle, and then removing the units from the drained machine.
+// A drain fails if any replacement unit has not settled within the
+// replacement timeout of being added.
+func New(conf Config) (worker.Worker, error) {
+	if err := conf.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	advance := func(stop <-chan struct{}) error {
+		return errors.Trace(conf.Facade.AdvanceMachineDrains(conf.ReplacementTimeout))
+	}
+	return worker.NewPeriodicWorker(advance, conf.Interval, conf.NewTimer), nil
+}
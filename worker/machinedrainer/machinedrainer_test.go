@@ -0,0 +1,113 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinedrainer_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/machinedrainer"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type drainerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&drainerSuite{})
+
+func (s *drainerSuite) TestValidate(c *gc.C) {
+	for i, test := range []struct {
+		mutate func(*machinedrainer.Config)
+		err    string
+	}{{
+		mutate: func(conf *machinedrainer.Config) { conf.Facade = nil },
+		err:    "missing Facade",
+	}, {
+		mutate: func(conf *machinedrainer.Config) { conf.NewTimer = nil },
+		err:    "missing Timer",
+	}, {
+		mutate: func(conf *machinedrainer.Config) { conf.ReplacementTimeout = 0 },
+		err:    "non-positive ReplacementTimeout",
+	}} {
+		c.Logf("test %d", i)
+		conf := machinedrainer.Config{
+			Facade:             newFakeFacade(),
+			Interval:           time.Second,
+			ReplacementTimeout: time.Minute,
+			NewTimer:           worker.NewTimer,
+		}
+		test.mutate(&conf)
+		_, err := machinedrainer.New(conf)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *drainerSuite) TestWorkerAdvancesDrains(c *gc.C) {
+	facade := newFakeFacade()
+	conf := machinedrainer.Config{
+		Facade:             facade,
+		Interval:           coretesting.ShortWait,
+		ReplacementTimeout: time.Minute,
+		NewTimer:           worker.NewTimer,
+	}
+	drainer, err := machinedrainer.New(conf)
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() {
+		c.Assert(worker.Stop(drainer), jc.ErrorIsNil)
+	}()
+
+	// The drains are advanced repeatedly, each time with the
+	// configured replacement timeout.
+	for i := 0; i < 2; i++ {
+		select {
+		case timeout := <-facade.timeouts:
+			c.Assert(timeout, gc.Equals, time.Minute)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for drains to be advanced")
+		}
+	}
+}
+
+func (s *drainerSuite) TestWorkerFailsOnError(c *gc.C) {
+	facade := newFakeFacade()
+	facade.err = errors.New("boom")
+	conf := machinedrainer.Config{
+		Facade:             facade,
+		Interval:           coretesting.ShortWait,
+		ReplacementTimeout: time.Minute,
+		NewTimer:           worker.NewTimer,
+	}
+	drainer, err := machinedrainer.New(conf)
+	c.Assert(err, jc.ErrorIsNil)
+	err = drainer.Wait()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type fakeFacade struct {
+	timeouts chan time.Duration
+	err      error
+}
+
+func newFakeFacade() *fakeFacade {
+	return &fakeFacade{timeouts: make(chan time.Duration, 10)}
+}
+
+// AdvanceMachineDrains implements Facade.
+func (f *fakeFacade) AdvanceMachineDrains(replacementTimeout time.Duration) error {
+	select {
+	case f.timeouts <- replacementTimeout:
+	default:
+	}
+	return f.err
+}
@@ -68,6 +68,16 @@ func (ctx *limitedContext) ActionData() (*context.ActionData, error) {
 	return nil, jujuc.ErrRestrictedContext
 }
 
+// StopAction implements runner.Context.
+func (ctx *limitedContext) StopAction(status, message string) error {
+	return jujuc.ErrRestrictedContext
+}
+
+// KillAction implements runner.Context.
+func (ctx *limitedContext) KillAction() error {
+	return jujuc.ErrRestrictedContext
+}
+
 // Flush implementes runner.Context.
 func (ctx *limitedContext) Flush(_ string, err error) error {
 	return err
@@ -74,6 +74,16 @@ func (ctx *hookContext) ActionData() (*context.ActionData, error) {
 	return nil, jujuc.ErrRestrictedContext
 }
 
+// StopAction implements runner.Context.
+func (ctx *hookContext) StopAction(status, message string) error {
+	return jujuc.ErrRestrictedContext
+}
+
+// KillAction implements runner.Context.
+func (ctx *hookContext) KillAction() error {
+	return jujuc.ErrRestrictedContext
+}
+
 // HasExecutionSetUnitStatus implements runner.Context.
 func (ctx *hookContext) HasExecutionSetUnitStatus() bool { return false }
 
@@ -0,0 +1,43 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricworker
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/api/metricsmanager"
+	"github.com/juju/juju/worker"
+)
+
+var (
+	thresholdsLogger = loggo.GetLogger("juju.worker.metricworker.thresholds")
+)
+
+const (
+	thresholdsPeriod = time.Minute
+)
+
+// NewThresholdEvaluator creates a new periodic worker that checks
+// recorded metrics against the environment's metric thresholds.
+func NewThresholdEvaluator(client metricsmanager.MetricsManagerClient) worker.Worker {
+	f := func(stopCh <-chan struct{}) error {
+		err := client.EvaluateThresholds()
+		if errors.IsNotImplemented(err) {
+			thresholdsLogger.Debugf("metric thresholds not supported by the controller")
+			return nil
+		} else if err != nil {
+			thresholdsLogger.Warningf("failed to evaluate metric thresholds %v - will retry later", err)
+			return nil
+		}
+		select {
+		case notify <- "thresholdsCalled":
+		default:
+		}
+		return nil
+	}
+	return worker.NewPeriodicWorker(f, thresholdsPeriod, worker.NewTimer)
+}
@@ -568,6 +568,7 @@ func constructStartInstanceParams(
 				},
 				Volume: volumeTag,
 			},
+			v.Snapshot,
 		}
 	}
 	var subnetsToZones map[network.Id][]string
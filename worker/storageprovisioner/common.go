@@ -187,3 +187,70 @@ func sourceParams(providerType storage.ProviderType, sourceName, baseStorageDir
 	}
 	return provider, sourceConfig, nil
 }
+
+// volumeSnapshotter returns the storage.VolumeSnapshotter implemented by
+// the volume source with the given name, provider type, environment config
+// and storage directory. An error satisfying errors.IsNotSupported is
+// returned if the volume source does not support snapshots.
+func volumeSnapshotter(
+	environConfig *config.Config,
+	baseStorageDir string,
+	sourceName string,
+	providerType storage.ProviderType,
+) (storage.VolumeSnapshotter, error) {
+	source, err := volumeSource(environConfig, baseStorageDir, sourceName, providerType)
+	if errors.Cause(err) == errNonDynamic {
+		return nil, errors.NotSupportedf("snapshots of non-dynamic storage %q", sourceName)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	snapshotter, ok := source.(storage.VolumeSnapshotter)
+	if !ok {
+		return nil, errors.NotSupportedf("snapshots of storage %q", sourceName)
+	}
+	return snapshotter, nil
+}
+
+// volumeResizer returns the storage.VolumeResizer implemented by the
+// volume source with the given name, provider type, environment config
+// and storage directory. An error satisfying errors.IsNotSupported is
+// returned if the volume source does not support resizing.
+func volumeResizer(
+	environConfig *config.Config,
+	baseStorageDir string,
+	sourceName string,
+	providerType storage.ProviderType,
+) (storage.VolumeResizer, error) {
+	source, err := volumeSource(environConfig, baseStorageDir, sourceName, providerType)
+	if errors.Cause(err) == errNonDynamic {
+		return nil, errors.NotSupportedf("resizing non-dynamic storage %q", sourceName)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	resizer, ok := source.(storage.VolumeResizer)
+	if !ok {
+		return nil, errors.NotSupportedf("resizing volumes of storage %q", sourceName)
+	}
+	return resizer, nil
+}
+
+// filesystemResizer returns the storage.FilesystemResizer implemented
+// by the filesystem source with the given name, provider type, environment
+// config and storage directory. An error satisfying errors.IsNotSupported
+// is returned if the filesystem source does not support resizing.
+func filesystemResizer(
+	environConfig *config.Config,
+	baseStorageDir string,
+	sourceName string,
+	providerType storage.ProviderType,
+) (storage.FilesystemResizer, error) {
+	source, err := filesystemSource(environConfig, baseStorageDir, sourceName, providerType)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	resizer, ok := source.(storage.FilesystemResizer)
+	if !ok {
+		return nil, errors.NotSupportedf("resizing filesystems of storage %q", sourceName)
+	}
+	return resizer, nil
+}
@@ -127,9 +127,18 @@ type mockVolumeAccessor struct {
 	provisionedVolumes     map[string]params.Volume
 	provisionedAttachments map[params.MachineStorageId]params.VolumeAttachment
 	blockDevices           map[params.MachineStorageId]storage.BlockDevice
+	snapshotsWatcher       *mockStringsWatcher
+	snapshotsWatcherErr    error
+	snapshots              map[string]params.VolumeSnapshotParams
+	resizesWatcher         *mockStringsWatcher
+	resizes                map[string]params.StorageResizeParams
 
 	setVolumeInfo           func([]params.Volume) ([]params.ErrorResult, error)
 	setVolumeAttachmentInfo func([]params.VolumeAttachment) ([]params.ErrorResult, error)
+	setVolumeSnapshotInfo   func([]params.VolumeSnapshotInfo) ([]params.ErrorResult, error)
+	setVolumeSnapshotErrors func([]params.VolumeSnapshotError) ([]params.ErrorResult, error)
+	removeVolumeSnapshots   func([]string) ([]params.ErrorResult, error)
+	completeStorageResizes  func([]string) ([]params.ErrorResult, error)
 }
 
 func (m *mockVolumeAccessor) provisionVolume(tag names.VolumeTag) params.Volume {
@@ -256,6 +265,73 @@ func (v *mockVolumeAccessor) SetVolumeAttachmentInfo(volumeAttachments []params.
 	return make([]params.ErrorResult, len(volumeAttachments)), nil
 }
 
+func (w *mockVolumeAccessor) WatchVolumeSnapshots() (apiwatcher.StringsWatcher, error) {
+	if w.snapshotsWatcherErr != nil {
+		return nil, w.snapshotsWatcherErr
+	}
+	return w.snapshotsWatcher, nil
+}
+
+func (v *mockVolumeAccessor) VolumeSnapshotParams(ids []string) ([]params.VolumeSnapshotParamsResult, error) {
+	var result []params.VolumeSnapshotParamsResult
+	for _, id := range ids {
+		if snapshot, ok := v.snapshots[id]; ok {
+			result = append(result, params.VolumeSnapshotParamsResult{Result: snapshot})
+		} else {
+			result = append(result, params.VolumeSnapshotParamsResult{
+				Error: common.ServerError(errors.NotFoundf("volume snapshot %q", id)),
+			})
+		}
+	}
+	return result, nil
+}
+
+func (v *mockVolumeAccessor) SetVolumeSnapshotInfo(snapshots []params.VolumeSnapshotInfo) ([]params.ErrorResult, error) {
+	if v.setVolumeSnapshotInfo != nil {
+		return v.setVolumeSnapshotInfo(snapshots)
+	}
+	return make([]params.ErrorResult, len(snapshots)), nil
+}
+
+func (v *mockVolumeAccessor) SetVolumeSnapshotErrors(snapshots []params.VolumeSnapshotError) ([]params.ErrorResult, error) {
+	if v.setVolumeSnapshotErrors != nil {
+		return v.setVolumeSnapshotErrors(snapshots)
+	}
+	return make([]params.ErrorResult, len(snapshots)), nil
+}
+
+func (v *mockVolumeAccessor) RemoveVolumeSnapshots(ids []string) ([]params.ErrorResult, error) {
+	if v.removeVolumeSnapshots != nil {
+		return v.removeVolumeSnapshots(ids)
+	}
+	return make([]params.ErrorResult, len(ids)), nil
+}
+
+func (w *mockVolumeAccessor) WatchStorageResizes() (apiwatcher.StringsWatcher, error) {
+	return w.resizesWatcher, nil
+}
+
+func (v *mockVolumeAccessor) StorageResizeParams(ids []string) ([]params.StorageResizeParamsResult, error) {
+	var result []params.StorageResizeParamsResult
+	for _, id := range ids {
+		if resize, ok := v.resizes[id]; ok {
+			result = append(result, params.StorageResizeParamsResult{Result: resize})
+		} else {
+			result = append(result, params.StorageResizeParamsResult{
+				Error: common.ServerError(errors.NotFoundf("storage resize %q", id)),
+			})
+		}
+	}
+	return result, nil
+}
+
+func (v *mockVolumeAccessor) CompleteStorageResizes(ids []string) ([]params.ErrorResult, error) {
+	if v.completeStorageResizes != nil {
+		return v.completeStorageResizes(ids)
+	}
+	return make([]params.ErrorResult, len(ids)), nil
+}
+
 func newMockVolumeAccessor() *mockVolumeAccessor {
 	return &mockVolumeAccessor{
 		volumesWatcher:         &mockStringsWatcher{make(chan []string, 1)},
@@ -265,6 +341,10 @@ func newMockVolumeAccessor() *mockVolumeAccessor {
 		provisionedVolumes:     make(map[string]params.Volume),
 		provisionedAttachments: make(map[params.MachineStorageId]params.VolumeAttachment),
 		blockDevices:           make(map[params.MachineStorageId]storage.BlockDevice),
+		snapshotsWatcher:       &mockStringsWatcher{make(chan []string, 1)},
+		snapshots:              make(map[string]params.VolumeSnapshotParams),
+		resizesWatcher:         &mockStringsWatcher{make(chan []string, 1)},
+		resizes:                make(map[string]params.StorageResizeParams),
 	}
 }
 
@@ -464,6 +544,9 @@ type dummyProvider struct {
 	destroyFilesystemsFunc       func([]string) ([]error, error)
 	validateVolumeParamsFunc     func(storage.VolumeParams) error
 	validateFilesystemParamsFunc func(storage.FilesystemParams) error
+	createSnapshotsFunc          func([]storage.SnapshotParams) ([]storage.CreateSnapshotsResult, error)
+	deleteSnapshotsFunc          func([]string) ([]error, error)
+	resizeVolumesFunc            func([]storage.VolumeResizeParams) ([]error, error)
 }
 
 type dummyVolumeSource struct {
@@ -571,6 +654,42 @@ func (s *dummyVolumeSource) DetachVolumes(params []storage.VolumeAttachmentParam
 	return make([]error, len(params)), nil
 }
 
+// CreateSnapshots takes volume snapshots.
+func (s *dummyVolumeSource) CreateSnapshots(params []storage.SnapshotParams) ([]storage.CreateSnapshotsResult, error) {
+	if s.provider.createSnapshotsFunc != nil {
+		return s.provider.createSnapshotsFunc(params)
+	}
+	results := make([]storage.CreateSnapshotsResult, len(params))
+	for i, p := range params {
+		results[i].Snapshot = &storage.SnapshotInfo{
+			SnapshotId: "snap-" + p.VolumeId,
+			Size:       p.Size,
+		}
+	}
+	return results, nil
+}
+
+// ListSnapshots lists volume snapshots.
+func (s *dummyVolumeSource) ListSnapshots() ([]string, error) {
+	return nil, nil
+}
+
+// DeleteSnapshots deletes volume snapshots.
+func (s *dummyVolumeSource) DeleteSnapshots(snapshotIds []string) ([]error, error) {
+	if s.provider.deleteSnapshotsFunc != nil {
+		return s.provider.deleteSnapshotsFunc(snapshotIds)
+	}
+	return make([]error, len(snapshotIds)), nil
+}
+
+// ResizeVolumes grows volumes.
+func (s *dummyVolumeSource) ResizeVolumes(params []storage.VolumeResizeParams) ([]error, error) {
+	if s.provider.resizeVolumesFunc != nil {
+		return s.provider.resizeVolumesFunc(params)
+	}
+	return make([]error, len(params)), nil
+}
+
 func (s *dummyFilesystemSource) ValidateFilesystemParams(params storage.FilesystemParams) error {
 	if s.provider != nil && s.provider.validateFilesystemParamsFunc != nil {
 		return s.provider.validateFilesystemParamsFunc(params)
@@ -0,0 +1,41 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage"
+)
+
+// storageResizesChanged is called when storage resize requests with
+// the provided IDs have been seen to have been added or removed.
+func storageResizesChanged(ctx *context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	results, err := ctx.volumeAccessor.StorageResizeParams(ids)
+	if err != nil {
+		return errors.Annotate(err, "getting storage resize parameters")
+	}
+	var ops []scheduleOp
+	for i, result := range results {
+		if params.IsCodeNotFound(result.Error) {
+			// The request has been completed; nothing to do.
+			continue
+		} else if result.Error != nil {
+			return errors.Annotatef(result.Error, "getting parameters for storage resize %s", ids[i])
+		}
+		if result.Result.Life != params.Alive {
+			continue
+		}
+		ops = append(ops, &resizeStorageOp{
+			provider: storage.ProviderType(result.Result.Provider),
+			args:     result.Result,
+		})
+	}
+	scheduleOperations(ctx, ops...)
+	return nil
+}
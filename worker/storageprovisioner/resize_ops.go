@@ -0,0 +1,141 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage"
+)
+
+// resizeStorage grows the volumes and filesystems described by the
+// specified storage resize requests, and then completes the requests.
+func resizeStorage(ctx *context, ops map[string]*resizeStorageOp) error {
+	volumeOps := make(map[storage.ProviderType][]*resizeStorageOp)
+	filesystemOps := make(map[storage.ProviderType][]*resizeStorageOp)
+	var complete []string
+	for _, op := range ops {
+		switch {
+		case op.args.VolumeTag != "":
+			if op.args.VolumeId == "" {
+				// The volume has been removed; there is
+				// nothing left to resize.
+				complete = append(complete, op.args.Id)
+				continue
+			}
+			volumeOps[op.provider] = append(volumeOps[op.provider], op)
+		case op.args.FilesystemTag != "":
+			if op.args.FilesystemId == "" {
+				// The filesystem has been removed; there is
+				// nothing left to resize.
+				complete = append(complete, op.args.Id)
+				continue
+			}
+			filesystemOps[op.provider] = append(filesystemOps[op.provider], op)
+		}
+	}
+	var reschedule []scheduleOp
+	for providerType, ops := range volumeOps {
+		sourceName := string(providerType)
+		resizer, err := volumeResizer(
+			ctx.environConfig, ctx.storageDir, sourceName, providerType,
+		)
+		if errors.IsNotSupported(err) {
+			// There is no point in retrying; the requests
+			// remain pending.
+			logger.Errorf("cannot resize volumes: %v", err)
+			continue
+		} else if err != nil {
+			return errors.Annotate(err, "getting volume resizer")
+		}
+		resizeParams := make([]storage.VolumeResizeParams, len(ops))
+		for i, op := range ops {
+			volumeTag, err := names.ParseVolumeTag(op.args.VolumeTag)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			resizeParams[i] = storage.VolumeResizeParams{
+				Tag:      volumeTag,
+				VolumeId: op.args.VolumeId,
+				Size:     op.args.Size,
+			}
+		}
+		logger.Debugf("resizing volumes: %v", resizeParams)
+		errs, err := resizer.ResizeVolumes(resizeParams)
+		if err != nil {
+			return errors.Annotatef(err, "resizing volumes from source %q", sourceName)
+		}
+		for i, err := range errs {
+			if err != nil {
+				logger.Debugf("failed to resize volume %s: %v", resizeParams[i].Tag.Id(), err)
+				reschedule = append(reschedule, ops[i])
+				continue
+			}
+			complete = append(complete, ops[i].args.Id)
+		}
+	}
+	for providerType, ops := range filesystemOps {
+		sourceName := string(providerType)
+		resizer, err := filesystemResizer(
+			ctx.environConfig, ctx.storageDir, sourceName, providerType,
+		)
+		if errors.IsNotSupported(err) {
+			logger.Errorf("cannot resize filesystems: %v", err)
+			continue
+		} else if err != nil {
+			return errors.Annotate(err, "getting filesystem resizer")
+		}
+		resizeParams := make([]storage.FilesystemResizeParams, len(ops))
+		for i, op := range ops {
+			filesystemTag, err := names.ParseFilesystemTag(op.args.FilesystemTag)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			resizeParams[i] = storage.FilesystemResizeParams{
+				Tag:          filesystemTag,
+				FilesystemId: op.args.FilesystemId,
+				Size:         op.args.Size,
+			}
+		}
+		logger.Debugf("resizing filesystems: %v", resizeParams)
+		errs, err := resizer.ResizeFilesystems(resizeParams)
+		if err != nil {
+			return errors.Annotatef(err, "resizing filesystems from source %q", sourceName)
+		}
+		for i, err := range errs {
+			if err != nil {
+				logger.Debugf("failed to resize filesystem %s: %v", resizeParams[i].Tag.Id(), err)
+				reschedule = append(reschedule, ops[i])
+				continue
+			}
+			complete = append(complete, ops[i].args.Id)
+		}
+	}
+	scheduleOperations(ctx, reschedule...)
+	if len(complete) == 0 {
+		return nil
+	}
+	errorResults, err := ctx.volumeAccessor.CompleteStorageResizes(complete)
+	if err != nil {
+		return errors.Annotate(err, "completing storage resizes")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			return errors.Annotatef(result.Error, "completing storage resize %s", complete[i])
+		}
+	}
+	return nil
+}
+
+type resizeStorageOp struct {
+	exponentialBackoff
+	provider storage.ProviderType
+	args     params.StorageResizeParams
+}
+
+func (op *resizeStorageOp) key() interface{} {
+	return op.args.Id
+}
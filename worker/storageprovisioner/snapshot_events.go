@@ -0,0 +1,82 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage"
+)
+
+// volumeSnapshotsChanged is called when the lifecycle states of the
+// volume snapshots with the provided IDs have been seen to have changed.
+func volumeSnapshotsChanged(ctx *context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	results, err := ctx.volumeAccessor.VolumeSnapshotParams(ids)
+	if err != nil {
+		return errors.Annotate(err, "getting volume snapshot parameters")
+	}
+	var ops []scheduleOp
+	for i, result := range results {
+		if params.IsCodeNotFound(result.Error) {
+			// The snapshot has been removed; nothing to do.
+			continue
+		} else if result.Error != nil {
+			return errors.Annotatef(result.Error, "getting parameters for volume snapshot %s", ids[i])
+		}
+		op, err := volumeSnapshotOp(result.Result)
+		if err != nil {
+			return errors.Annotatef(err, "processing volume snapshot %s", ids[i])
+		}
+		if op != nil {
+			ops = append(ops, op)
+		}
+	}
+	scheduleOperations(ctx, ops...)
+	return nil
+}
+
+// volumeSnapshotOp returns the operation required to bring the volume
+// snapshot with the specified parameters up to date, or nil if there
+// is nothing to do.
+func volumeSnapshotOp(in params.VolumeSnapshotParams) (scheduleOp, error) {
+	providerType := storage.ProviderType(in.Provider)
+	switch in.Life {
+	case params.Alive:
+		if in.SnapshotId != "" {
+			// Already taken.
+			return nil, nil
+		}
+		volumeTag, err := names.ParseVolumeTag(in.VolumeTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if in.VolumeId == "" {
+			// The volume was removed before the snapshot could
+			// be taken; the snapshot can only be destroyed.
+			logger.Warningf("%s is not provisioned, cannot take snapshot %s", names.ReadableString(volumeTag), in.Id)
+			return nil, nil
+		}
+		return &createSnapshotOp{
+			provider: providerType,
+			args: storage.SnapshotParams{
+				Id:       in.Id,
+				Volume:   volumeTag,
+				VolumeId: in.VolumeId,
+				Size:     in.Size,
+			},
+		}, nil
+	case params.Dying, params.Dead:
+		return &deleteSnapshotOp{
+			provider:   providerType,
+			id:         in.Id,
+			snapshotId: in.SnapshotId,
+		}, nil
+	}
+	return nil, errors.Errorf("unexpected life %q", in.Life)
+}
@@ -0,0 +1,183 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage"
+)
+
+// createSnapshots takes volume snapshots with the specified parameters.
+func createSnapshots(ctx *context, ops map[string]*createSnapshotOp) error {
+	opsByProvider := make(map[storage.ProviderType][]*createSnapshotOp)
+	for _, op := range ops {
+		opsByProvider[op.provider] = append(opsByProvider[op.provider], op)
+	}
+	var reschedule []scheduleOp
+	var snapshots []params.VolumeSnapshotInfo
+	var snapshotErrors []params.VolumeSnapshotError
+	for providerType, ops := range opsByProvider {
+		sourceName := string(providerType)
+		snapshotter, err := volumeSnapshotter(
+			ctx.environConfig, ctx.storageDir, sourceName, providerType,
+		)
+		if errors.IsNotSupported(err) {
+			// There is no point in retrying; the snapshots
+			// are marked as failed, and remain untaken until
+			// they are destroyed.
+			logger.Errorf("cannot take volume snapshots: %v", err)
+			for _, op := range ops {
+				snapshotErrors = append(snapshotErrors, params.VolumeSnapshotError{
+					Id:    op.args.Id,
+					Error: err.Error(),
+				})
+			}
+			continue
+		} else if err != nil {
+			return errors.Annotate(err, "getting volume snapshotter")
+		}
+		snapshotParams := make([]storage.SnapshotParams, len(ops))
+		for i, op := range ops {
+			snapshotParams[i] = op.args
+		}
+		logger.Debugf("creating volume snapshots: %v", snapshotParams)
+		results, err := snapshotter.CreateSnapshots(snapshotParams)
+		if err != nil {
+			return errors.Annotatef(err, "creating volume snapshots from source %q", sourceName)
+		}
+		for i, result := range results {
+			if result.Error != nil {
+				logger.Debugf("failed to create volume snapshot %s: %v", ops[i].args.Id, result.Error)
+				reschedule = append(reschedule, ops[i])
+				continue
+			}
+			snapshots = append(snapshots, params.VolumeSnapshotInfo{
+				Id:         ops[i].args.Id,
+				SnapshotId: result.Snapshot.SnapshotId,
+				Size:       result.Snapshot.Size,
+			})
+		}
+	}
+	scheduleOperations(ctx, reschedule...)
+	if err := setVolumeSnapshotErrors(ctx, snapshotErrors); err != nil {
+		return errors.Trace(err)
+	}
+	if len(snapshots) == 0 {
+		return nil
+	}
+	errorResults, err := ctx.volumeAccessor.SetVolumeSnapshotInfo(snapshots)
+	if err != nil {
+		return errors.Annotate(err, "publishing volume snapshots to state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			logger.Errorf(
+				"publishing volume snapshot %s to state: %v",
+				snapshots[i].Id, result.Error,
+			)
+		}
+	}
+	return nil
+}
+
+// setVolumeSnapshotErrors records that the specified volume snapshots
+// cannot be taken.
+func setVolumeSnapshotErrors(ctx *context, snapshotErrors []params.VolumeSnapshotError) error {
+	if len(snapshotErrors) == 0 {
+		return nil
+	}
+	errorResults, err := ctx.volumeAccessor.SetVolumeSnapshotErrors(snapshotErrors)
+	if err != nil {
+		return errors.Annotate(err, "publishing volume snapshot errors to state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			logger.Errorf(
+				"publishing volume snapshot %s error to state: %v",
+				snapshotErrors[i].Id, result.Error,
+			)
+		}
+	}
+	return nil
+}
+
+// deleteSnapshots deletes the volume snapshots with the specified
+// parameters, and removes them from state.
+func deleteSnapshots(ctx *context, ops map[string]*deleteSnapshotOp) error {
+	opsByProvider := make(map[storage.ProviderType][]*deleteSnapshotOp)
+	var remove []string
+	for _, op := range ops {
+		if op.snapshotId == "" {
+			// The snapshot was never taken, so there is
+			// nothing to delete from the provider.
+			remove = append(remove, op.id)
+			continue
+		}
+		opsByProvider[op.provider] = append(opsByProvider[op.provider], op)
+	}
+	var reschedule []scheduleOp
+	for providerType, ops := range opsByProvider {
+		sourceName := string(providerType)
+		snapshotter, err := volumeSnapshotter(
+			ctx.environConfig, ctx.storageDir, sourceName, providerType,
+		)
+		if err != nil {
+			return errors.Annotate(err, "getting volume snapshotter")
+		}
+		snapshotIds := make([]string, len(ops))
+		for i, op := range ops {
+			snapshotIds[i] = op.snapshotId
+		}
+		logger.Debugf("deleting volume snapshots: %v", snapshotIds)
+		errs, err := snapshotter.DeleteSnapshots(snapshotIds)
+		if err != nil {
+			return errors.Annotatef(err, "deleting volume snapshots from source %q", sourceName)
+		}
+		for i, err := range errs {
+			if err != nil {
+				logger.Debugf("failed to delete volume snapshot %s: %v", ops[i].id, err)
+				reschedule = append(reschedule, ops[i])
+				continue
+			}
+			remove = append(remove, ops[i].id)
+		}
+	}
+	scheduleOperations(ctx, reschedule...)
+	if len(remove) == 0 {
+		return nil
+	}
+	errorResults, err := ctx.volumeAccessor.RemoveVolumeSnapshots(remove)
+	if err != nil {
+		return errors.Annotate(err, "removing volume snapshots")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			return errors.Annotatef(result.Error, "removing volume snapshot %s from state", remove[i])
+		}
+	}
+	return nil
+}
+
+type createSnapshotOp struct {
+	exponentialBackoff
+	provider storage.ProviderType
+	args     storage.SnapshotParams
+}
+
+func (op *createSnapshotOp) key() interface{} {
+	return op.args.Id
+}
+
+type deleteSnapshotOp struct {
+	exponentialBackoff
+	provider   storage.ProviderType
+	id         string
+	snapshotId string
+}
+
+func (op *deleteSnapshotOp) key() interface{} {
+	return op.id
+}
@@ -88,6 +88,40 @@ type VolumeAccessor interface {
 	// SetVolumeAttachmentInfo records the details of newly provisioned
 	// volume attachments.
 	SetVolumeAttachmentInfo([]params.VolumeAttachment) ([]params.ErrorResult, error)
+
+	// WatchVolumeSnapshots watches for changes to volume snapshots
+	// that this storage provisioner is responsible for.
+	WatchVolumeSnapshots() (apiwatcher.StringsWatcher, error)
+
+	// VolumeSnapshotParams returns the parameters for taking or
+	// deleting the volume snapshots with the specified IDs.
+	VolumeSnapshotParams([]string) ([]params.VolumeSnapshotParamsResult, error)
+
+	// SetVolumeSnapshotInfo records the details of newly taken
+	// volume snapshots.
+	SetVolumeSnapshotInfo([]params.VolumeSnapshotInfo) ([]params.ErrorResult, error)
+
+	// SetVolumeSnapshotErrors records that volume snapshots cannot
+	// be taken, and why.
+	SetVolumeSnapshotErrors([]params.VolumeSnapshotError) ([]params.ErrorResult, error)
+
+	// RemoveVolumeSnapshots removes the volume snapshots with the
+	// specified IDs from state.
+	RemoveVolumeSnapshots([]string) ([]params.ErrorResult, error)
+
+	// WatchStorageResizes watches for the addition and removal of
+	// storage resize requests that this storage provisioner is
+	// responsible for.
+	WatchStorageResizes() (apiwatcher.StringsWatcher, error)
+
+	// StorageResizeParams returns the parameters for carrying out
+	// the storage resize requests with the specified IDs.
+	StorageResizeParams([]string) ([]params.StorageResizeParamsResult, error)
+
+	// CompleteStorageResizes records the new sizes of resized
+	// storage, and removes the storage resize requests with the
+	// specified IDs.
+	CompleteStorageResizes([]string) ([]params.ErrorResult, error)
 }
 
 // FilesystemAccessor defines an interface used to allow a storage provisioner
@@ -246,6 +280,10 @@ func (w *storageprovisioner) loop() error {
 	var filesystemAttachmentsChanges <-chan []params.MachineStorageId
 	var machineBlockDevicesWatcher apiwatcher.NotifyWatcher
 	var machineBlockDevicesChanges <-chan struct{}
+	var volumeSnapshotsWatcher apiwatcher.StringsWatcher
+	var volumeSnapshotsChanges <-chan []string
+	var storageResizesWatcher apiwatcher.StringsWatcher
+	var storageResizesChanges <-chan []string
 	machineChanges := make(chan names.MachineTag)
 
 	environConfigWatcher, err := w.environ.WatchForEnvironConfigChanges()
@@ -271,6 +309,8 @@ func (w *storageprovisioner) loop() error {
 	defer w.maybeStopWatcher(volumeAttachmentsWatcher)
 	defer w.maybeStopWatcher(filesystemsWatcher)
 	defer w.maybeStopWatcher(filesystemAttachmentsWatcher)
+	defer w.maybeStopWatcher(volumeSnapshotsWatcher)
+	defer w.maybeStopWatcher(storageResizesWatcher)
 
 	startWatchers := func() error {
 		var err error
@@ -290,10 +330,26 @@ func (w *storageprovisioner) loop() error {
 		if err != nil {
 			return errors.Annotate(err, "watching filesystem attachments")
 		}
+		volumeSnapshotsWatcher, err = w.volumes.WatchVolumeSnapshots()
+		if errors.IsNotImplemented(err) {
+			// The controller does not support volume snapshots;
+			// leave the snapshot changes channel nil.
+			logger.Debugf("volume snapshots not supported: %v", err)
+		} else if err != nil {
+			return errors.Annotate(err, "watching volume snapshots")
+		}
+		storageResizesWatcher, err = w.volumes.WatchStorageResizes()
+		if err != nil {
+			return errors.Annotate(err, "watching storage resizes")
+		}
 		volumesChanges = volumesWatcher.Changes()
 		filesystemsChanges = filesystemsWatcher.Changes()
 		volumeAttachmentsChanges = volumeAttachmentsWatcher.Changes()
 		filesystemAttachmentsChanges = filesystemAttachmentsWatcher.Changes()
+		if volumeSnapshotsWatcher != nil {
+			volumeSnapshotsChanges = volumeSnapshotsWatcher.Changes()
+		}
+		storageResizesChanges = storageResizesWatcher.Changes()
 		return nil
 	}
 
@@ -382,6 +438,20 @@ func (w *storageprovisioner) loop() error {
 			if err := filesystemAttachmentsChanged(&ctx, changes); err != nil {
 				return errors.Trace(err)
 			}
+		case changes, ok := <-volumeSnapshotsChanges:
+			if !ok {
+				return watcher.EnsureErr(volumeSnapshotsWatcher)
+			}
+			if err := volumeSnapshotsChanged(&ctx, changes); err != nil {
+				return errors.Trace(err)
+			}
+		case changes, ok := <-storageResizesChanges:
+			if !ok {
+				return watcher.EnsureErr(storageResizesWatcher)
+			}
+			if err := storageResizesChanged(&ctx, changes); err != nil {
+				return errors.Trace(err)
+			}
 		case _, ok := <-machineBlockDevicesChanges:
 			if !ok {
 				return watcher.EnsureErr(machineBlockDevicesWatcher)
@@ -413,6 +483,9 @@ func processSchedule(ctx *context) error {
 	destroyFilesystemOps := make(map[names.FilesystemTag]*destroyFilesystemOp)
 	attachFilesystemOps := make(map[params.MachineStorageId]*attachFilesystemOp)
 	detachFilesystemOps := make(map[params.MachineStorageId]*detachFilesystemOp)
+	createSnapshotOps := make(map[string]*createSnapshotOp)
+	deleteSnapshotOps := make(map[string]*deleteSnapshotOp)
+	resizeStorageOps := make(map[string]*resizeStorageOp)
 	for _, item := range ready {
 		op := item.(scheduleOp)
 		key := op.key()
@@ -433,6 +506,12 @@ func processSchedule(ctx *context) error {
 			attachFilesystemOps[key.(params.MachineStorageId)] = op
 		case *detachFilesystemOp:
 			detachFilesystemOps[key.(params.MachineStorageId)] = op
+		case *createSnapshotOp:
+			createSnapshotOps[key.(string)] = op
+		case *deleteSnapshotOp:
+			deleteSnapshotOps[key.(string)] = op
+		case *resizeStorageOp:
+			resizeStorageOps[key.(string)] = op
 		}
 	}
 	if len(destroyVolumeOps) > 0 {
@@ -475,6 +554,21 @@ func processSchedule(ctx *context) error {
 			return errors.Annotate(err, "attaching filesystems")
 		}
 	}
+	if len(deleteSnapshotOps) > 0 {
+		if err := deleteSnapshots(ctx, deleteSnapshotOps); err != nil {
+			return errors.Annotate(err, "deleting volume snapshots")
+		}
+	}
+	if len(createSnapshotOps) > 0 {
+		if err := createSnapshots(ctx, createSnapshotOps); err != nil {
+			return errors.Annotate(err, "creating volume snapshots")
+		}
+	}
+	if len(resizeStorageOps) > 0 {
+		if err := resizeStorage(ctx, resizeStorageOps); err != nil {
+			return errors.Annotate(err, "resizing storage")
+		}
+	}
 	return nil
 }
 
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"time"
 
+	jujuerrors "github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/clock"
@@ -739,6 +740,184 @@ func (s *storageProvisionerSuite) TestFilesystemAdded(c *gc.C) {
 	waitChannel(c, filesystemInfoSet, "waiting for filesystem info to be set")
 }
 
+func (s *storageProvisionerSuite) TestVolumeSnapshotCreated(c *gc.C) {
+	snapshotInfoSet := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.snapshots["1"] = params.VolumeSnapshotParams{
+		Id:        "1",
+		Life:      params.Alive,
+		VolumeTag: "volume-1",
+		VolumeId:  "vol-1",
+		Provider:  "dummy",
+		Size:      1024,
+	}
+	// Snapshots that have already been taken are left alone.
+	volumeAccessor.snapshots["2"] = params.VolumeSnapshotParams{
+		Id:         "2",
+		Life:       params.Alive,
+		VolumeTag:  "volume-2",
+		VolumeId:   "vol-2",
+		Provider:   "dummy",
+		SnapshotId: "snap-vol-2",
+	}
+	volumeAccessor.setVolumeSnapshotInfo = func(snapshots []params.VolumeSnapshotInfo) ([]params.ErrorResult, error) {
+		defer close(snapshotInfoSet)
+		c.Assert(snapshots, jc.DeepEquals, []params.VolumeSnapshotInfo{{
+			Id:         "1",
+			SnapshotId: "snap-vol-1",
+			Size:       1024,
+		}})
+		return nil, nil
+	}
+
+	args := &workerArgs{volumes: volumeAccessor}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	volumeAccessor.snapshotsWatcher.changes <- []string{"1", "2", "3"}
+	args.environ.watcher.changes <- struct{}{}
+	waitChannel(c, snapshotInfoSet, "waiting for volume snapshot info to be set")
+}
+
+func (s *storageProvisionerSuite) TestVolumeSnapshotNotSupported(c *gc.C) {
+	s.provider.dynamic = false
+	snapshotErrorSet := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.snapshots["1"] = params.VolumeSnapshotParams{
+		Id:        "1",
+		Life:      params.Alive,
+		VolumeTag: "volume-1",
+		VolumeId:  "vol-1",
+		Provider:  "dummy",
+		Size:      1024,
+	}
+	volumeAccessor.setVolumeSnapshotErrors = func(snapshots []params.VolumeSnapshotError) ([]params.ErrorResult, error) {
+		defer close(snapshotErrorSet)
+		c.Assert(snapshots, jc.DeepEquals, []params.VolumeSnapshotError{{
+			Id:    "1",
+			Error: `snapshots of non-dynamic storage "dummy" not supported`,
+		}})
+		return make([]params.ErrorResult, len(snapshots)), nil
+	}
+
+	args := &workerArgs{volumes: volumeAccessor}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	volumeAccessor.snapshotsWatcher.changes <- []string{"1"}
+	args.environ.watcher.changes <- struct{}{}
+	waitChannel(c, snapshotErrorSet, "waiting for volume snapshot error to be set")
+}
+
+func (s *storageProvisionerSuite) TestVolumeSnapshotsNotImplemented(c *gc.C) {
+	volumeInfoSet := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.snapshotsWatcherErr = jujuerrors.NotImplementedf("WatchVolumeSnapshots")
+	volumeAccessor.setVolumeInfo = func(volumes []params.Volume) ([]params.ErrorResult, error) {
+		defer close(volumeInfoSet)
+		return make([]params.ErrorResult, len(volumes)), nil
+	}
+
+	args := &workerArgs{volumes: volumeAccessor}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	// Volumes are still provisioned when the controller
+	// does not support volume snapshots.
+	volumeAccessor.volumesWatcher.changes <- []string{"1"}
+	args.environ.watcher.changes <- struct{}{}
+	waitChannel(c, volumeInfoSet, "waiting for volume info to be set")
+}
+
+func (s *storageProvisionerSuite) TestVolumeSnapshotDeleted(c *gc.C) {
+	var deleted []string
+	s.provider.deleteSnapshotsFunc = func(snapshotIds []string) ([]error, error) {
+		deleted = append(deleted, snapshotIds...)
+		return make([]error, len(snapshotIds)), nil
+	}
+	removed := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.snapshots["1"] = params.VolumeSnapshotParams{
+		Id:         "1",
+		Life:       params.Dying,
+		VolumeTag:  "volume-1",
+		Provider:   "dummy",
+		SnapshotId: "snap-vol-1",
+	}
+	// Snapshots that were never taken are removed without
+	// consulting the provider.
+	volumeAccessor.snapshots["2"] = params.VolumeSnapshotParams{
+		Id:        "2",
+		Life:      params.Dying,
+		VolumeTag: "volume-2",
+		Provider:  "dummy",
+	}
+	volumeAccessor.removeVolumeSnapshots = func(ids []string) ([]params.ErrorResult, error) {
+		defer close(removed)
+		c.Assert(ids, jc.SameContents, []string{"1", "2"})
+		return make([]params.ErrorResult, len(ids)), nil
+	}
+
+	args := &workerArgs{volumes: volumeAccessor}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	volumeAccessor.snapshotsWatcher.changes <- []string{"1", "2"}
+	args.environ.watcher.changes <- struct{}{}
+	waitChannel(c, removed, "waiting for volume snapshots to be removed")
+	c.Assert(deleted, jc.DeepEquals, []string{"snap-vol-1"})
+}
+
+func (s *storageProvisionerSuite) TestStorageResized(c *gc.C) {
+	var resized []storage.VolumeResizeParams
+	s.provider.resizeVolumesFunc = func(args []storage.VolumeResizeParams) ([]error, error) {
+		resized = append(resized, args...)
+		return make([]error, len(args)), nil
+	}
+	completed := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.resizes["1"] = params.StorageResizeParams{
+		Id:        "1",
+		Life:      params.Alive,
+		VolumeTag: "volume-1",
+		VolumeId:  "vol-1",
+		Provider:  "dummy",
+		Size:      2048,
+	}
+	// Requests to resize removed volumes are completed without
+	// consulting the provider.
+	volumeAccessor.resizes["2"] = params.StorageResizeParams{
+		Id:        "2",
+		Life:      params.Alive,
+		VolumeTag: "volume-2",
+		Provider:  "dummy",
+		Size:      2048,
+	}
+	volumeAccessor.completeStorageResizes = func(ids []string) ([]params.ErrorResult, error) {
+		defer close(completed)
+		c.Assert(ids, jc.SameContents, []string{"1", "2"})
+		return make([]params.ErrorResult, len(ids)), nil
+	}
+
+	args := &workerArgs{volumes: volumeAccessor}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	volumeAccessor.resizesWatcher.changes <- []string{"1", "2", "3"}
+	args.environ.watcher.changes <- struct{}{}
+	waitChannel(c, completed, "waiting for storage resizes to be completed")
+	c.Assert(resized, jc.DeepEquals, []storage.VolumeResizeParams{{
+		Tag:      names.NewVolumeTag("1"),
+		VolumeId: "vol-1",
+		Size:     2048,
+	}})
+}
+
 func (s *storageProvisionerSuite) TestVolumeNeedsInstance(c *gc.C) {
 	volumeInfoSet := make(chan interface{})
 	volumeAccessor := newMockVolumeAccessor()
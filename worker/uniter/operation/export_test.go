@@ -0,0 +1,9 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operation
+
+var (
+	CancelPollInterval = &cancelPollInterval
+	StopGracePeriod    = &stopGracePeriod
+)
@@ -149,6 +149,10 @@ type Callbacks interface {
 	// RunActions operations.
 	FailAction(actionId, message string) error
 
+	// ActionCancelRequested reports whether cancellation of the supplied
+	// action has been requested. It's only used by RunAction operations.
+	ActionCancelRequested(actionId string) (bool, error)
+
 	// GetArchiveInfo is used to find out how to download a charm archive. It's
 	// only used by Deploy operations.
 	GetArchiveInfo(charmURL *corecharm.URL) (charm.BundleInfo, error)
@@ -5,20 +5,33 @@ package operation
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/worker/uniter/runner"
 )
 
+var (
+	// cancelPollInterval is how often a running action is checked
+	// for a cancellation request.
+	cancelPollInterval = 5 * time.Second
+
+	// stopGracePeriod is how long a stopped action's process is given
+	// to exit before it is killed.
+	stopGracePeriod = 30 * time.Second
+)
+
 type runAction struct {
 	actionId string
 
 	callbacks     Callbacks
 	runnerFactory runner.Factory
 
-	name   string
-	runner runner.Runner
+	name    string
+	timeout time.Duration
+	runner  runner.Runner
 
 	RequiresMachineLock
 }
@@ -54,6 +67,7 @@ func (ra *runAction) Prepare(state State) (*State, error) {
 		return nil, errors.Trace(err)
 	}
 	ra.name = actionData.Name
+	ra.timeout = actionData.Timeout
 	ra.runner = rnr
 	return stateChange{
 		Kind:     RunAction,
@@ -72,7 +86,11 @@ func (ra *runAction) Execute(state State) (*State, error) {
 		return nil, err
 	}
 
-	err := ra.runner.RunAction(ra.name)
+	done := make(chan error, 1)
+	go func() {
+		done <- ra.runner.RunAction(ra.name)
+	}()
+	err := ra.wait(done)
 	if err != nil {
 		// This indicates an actual error -- an action merely failing should
 		// be handled inside the Runner, and returned as nil.
@@ -86,6 +104,61 @@ func (ra *runAction) Execute(state State) (*State, error) {
 	}.apply(state), nil
 }
 
+// wait waits for the action to finish, stopping it early if it runs past
+// its timeout or if its cancellation is requested.
+func (ra *runAction) wait(done <-chan error) error {
+	var timeout <-chan time.Time
+	if ra.timeout > 0 {
+		timeout = time.After(ra.timeout)
+	}
+	poll := time.After(cancelPollInterval)
+	for {
+		select {
+		case err := <-done:
+			return err
+		case <-timeout:
+			message := fmt.Sprintf("action timed out after %v", ra.timeout)
+			return ra.stop(done, params.ActionFailed, message)
+		case <-poll:
+			cancelled, err := ra.callbacks.ActionCancelRequested(ra.actionId)
+			switch {
+			case errors.IsNotImplemented(err) || params.IsCodeNotImplemented(err):
+				// The controller cannot cancel actions, so there
+				// is no point asking again.
+				logger.Debugf("not checking action %s for cancellation: %v", ra.actionId, err)
+				poll = nil
+				continue
+			case err != nil:
+				logger.Warningf("cannot check action %s for cancellation: %v", ra.actionId, err)
+			case cancelled:
+				return ra.stop(done, params.ActionCancelled, "action cancelled")
+			}
+			poll = time.After(cancelPollInterval)
+		}
+	}
+}
+
+// stop asks the action's process to exit, recording the supplied status
+// and message as the action's result, and kills the process if it has not
+// exited within stopGracePeriod. It waits for the action to finish.
+func (ra *runAction) stop(done <-chan error, status, message string) error {
+	logger.Infof("stopping action %s: %s", ra.actionId, message)
+	ctx := ra.runner.Context()
+	if err := ctx.StopAction(status, message); err != nil {
+		logger.Warningf("cannot ask action %s to stop: %v", ra.actionId, err)
+	} else {
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(stopGracePeriod):
+		}
+	}
+	if err := ctx.KillAction(); err != nil {
+		logger.Warningf("cannot kill action %s: %v", ra.actionId, err)
+	}
+	return <-done
+}
+
 // Commit preserves the recorded hook, and returns a neutral state.
 // Commit is part of the Operation interface.
 func (ra *runAction) Commit(state State) (*State, error) {
@@ -4,6 +4,8 @@
 package operation_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -227,6 +229,67 @@ func (s *RunActionSuite) TestExecuteSuccess(c *gc.C) {
 	}
 }
 
+func (s *RunActionSuite) executeBlocking(c *gc.C, runnerFactory *MockRunnerFactory, callbacks *RunActionCallbacks) *MockContext {
+	factory := operation.NewFactory(operation.FactoryParams{
+		RunnerFactory: runnerFactory,
+		Callbacks:     callbacks,
+	})
+	op, err := factory.NewAction(someActionId)
+	c.Assert(err, jc.ErrorIsNil)
+	midState, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	newState, err := op.Execute(*midState)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newState.Step, gc.Equals, operation.Done)
+	return runnerFactory.MockNewActionRunner.runner.context.(*MockContext)
+}
+
+func (s *RunActionSuite) TestExecuteTimeout(c *gc.C) {
+	runnerFactory := NewBlockingRunActionRunnerFactory(time.Millisecond, false)
+	ctx := s.executeBlocking(c, runnerFactory, &RunActionCallbacks{})
+	ctx.CheckCalls(c, []testing.StubCall{
+		{"Prepare", nil},
+		{"StopAction", []interface{}{"failed", "action timed out after 1ms"}},
+	})
+}
+
+func (s *RunActionSuite) TestExecuteCancelled(c *gc.C) {
+	s.PatchValue(operation.CancelPollInterval, time.Millisecond)
+	runnerFactory := NewBlockingRunActionRunnerFactory(0, false)
+	callbacks := &RunActionCallbacks{cancelRequested: true}
+	ctx := s.executeBlocking(c, runnerFactory, callbacks)
+	ctx.CheckCalls(c, []testing.StubCall{
+		{"Prepare", nil},
+		{"StopAction", []interface{}{"cancelled", "action cancelled"}},
+	})
+}
+
+func (s *RunActionSuite) TestExecuteCancelNotImplemented(c *gc.C) {
+	s.PatchValue(operation.CancelPollInterval, time.Millisecond)
+	runnerFactory := NewBlockingRunActionRunnerFactory(50*time.Millisecond, false)
+	callbacks := &RunActionCallbacks{
+		cancelErr: errors.NotImplementedf("ActionCancelRequested"),
+	}
+	ctx := s.executeBlocking(c, runnerFactory, callbacks)
+	ctx.CheckCalls(c, []testing.StubCall{
+		{"Prepare", nil},
+		{"StopAction", []interface{}{"failed", "action timed out after 50ms"}},
+	})
+	c.Assert(callbacks.cancelChecks, gc.Equals, 1)
+}
+
+func (s *RunActionSuite) TestExecuteKillsAfterGracePeriod(c *gc.C) {
+	s.PatchValue(operation.StopGracePeriod, time.Millisecond)
+	runnerFactory := NewBlockingRunActionRunnerFactory(time.Millisecond, true)
+	ctx := s.executeBlocking(c, runnerFactory, &RunActionCallbacks{})
+	ctx.CheckCalls(c, []testing.StubCall{
+		{"Prepare", nil},
+		{"StopAction", []interface{}{"failed", "action timed out after 1ms"}},
+		{"KillAction", nil},
+	})
+}
+
 func (s *RunActionSuite) TestCommit(c *gc.C) {
 	var stateChangeTests = []struct {
 		description string
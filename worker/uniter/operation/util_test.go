@@ -4,6 +4,8 @@
 package operation_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	utilexec "github.com/juju/utils/exec"
@@ -124,6 +126,9 @@ type RunActionCallbacks struct {
 	operation.Callbacks
 	*MockFailAction
 	executingMessage string
+	cancelRequested  bool
+	cancelErr        error
+	cancelChecks     int
 }
 
 func (cb *RunActionCallbacks) FailAction(actionId, message string) error {
@@ -135,6 +140,11 @@ func (cb *RunActionCallbacks) SetExecutingStatus(message string) error {
 	return nil
 }
 
+func (cb *RunActionCallbacks) ActionCancelRequested(actionId string) (bool, error) {
+	cb.cancelChecks++
+	return cb.cancelRequested, cb.cancelErr
+}
+
 type RunCommandsCallbacks struct {
 	operation.Callbacks
 	executingMessage string
@@ -290,6 +300,10 @@ type MockContext struct {
 	actionData      *context.ActionData
 	setStatusCalled bool
 	status          jujuc.StatusInfo
+	// released, if set, is closed by StopAction (or by KillAction, when
+	// ignoreStop is set) to let a blocked MockRunAction return.
+	released   chan struct{}
+	ignoreStop bool
 }
 
 func (mock *MockContext) ActionData() (*context.ActionData, error) {
@@ -326,13 +340,32 @@ func (mock *MockContext) Prepare() error {
 	return mock.NextErr()
 }
 
+func (mock *MockContext) StopAction(status, message string) error {
+	mock.MethodCall(mock, "StopAction", status, message)
+	if !mock.ignoreStop {
+		close(mock.released)
+	}
+	return mock.NextErr()
+}
+
+func (mock *MockContext) KillAction() error {
+	mock.MethodCall(mock, "KillAction")
+	close(mock.released)
+	return mock.NextErr()
+}
+
 type MockRunAction struct {
 	gotName *string
 	err     error
+	// wait, if set, blocks the action until it is closed.
+	wait <-chan struct{}
 }
 
 func (mock *MockRunAction) Call(actionName string) error {
 	mock.gotName = &actionName
+	if mock.wait != nil {
+		<-mock.wait
+	}
 	return mock.err
 }
 
@@ -422,6 +455,28 @@ func NewRunActionRunnerFactory(runErr error) *MockRunnerFactory {
 	}
 }
 
+// NewBlockingRunActionRunnerFactory returns a factory for action runners
+// whose actions run until the context's StopAction (or, if ignoreStop is
+// set, KillAction) is called.
+func NewBlockingRunActionRunnerFactory(timeout time.Duration, ignoreStop bool) *MockRunnerFactory {
+	released := make(chan struct{})
+	return &MockRunnerFactory{
+		MockNewActionRunner: &MockNewActionRunner{
+			runner: &MockRunner{
+				MockRunAction: &MockRunAction{wait: released},
+				context: &MockContext{
+					actionData: &context.ActionData{
+						Name:    "some-action-name",
+						Timeout: timeout,
+					},
+					released:   released,
+					ignoreStop: ignoreStop,
+				},
+			},
+		},
+	}
+}
+
 func NewRunCommandsRunnerFactory(runResponse *utilexec.ExecResponse, runErr error) *MockRunnerFactory {
 	return &MockRunnerFactory{
 		MockNewCommandRunner: &MockNewCommandRunner{
@@ -306,6 +306,7 @@ func (s *WatcherSuite) TestStorageChanged(c *gc.C) {
 		Life:       params.Dying,
 		Kind:       params.StorageKindFilesystem,
 		Location:   "somewhere",
+		Size:       1024,
 	}
 	delete(s.st.storageAttachment, storageAttachmentId1)
 	storageTag0Watcher.changes <- struct{}{}
@@ -318,6 +319,7 @@ func (s *WatcherSuite) TestStorageChanged(c *gc.C) {
 			Attached: true,
 			Kind:     params.StorageKindFilesystem,
 			Location: "somewhere",
+			Size:     1024,
 		},
 	})
 }
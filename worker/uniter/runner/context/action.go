@@ -4,17 +4,25 @@
 package context
 
 import (
+	"time"
+
 	"github.com/juju/names"
 )
 
 // ActionData contains the tag, parameters, and results of an Action.
+// If Timeout is positive, the Action is stopped if it runs for longer
+// than that. StopStatus and StopMessage are set when the Action is
+// stopped, and take precedence over the results it reports.
 type ActionData struct {
 	Name           string
 	Tag            names.ActionTag
 	Params         map[string]interface{}
+	Timeout        time.Duration
 	Failed         bool
 	ResultsMessage string
 	ResultsMap     map[string]interface{}
+	StopStatus     string
+	StopMessage    string
 }
 
 // NewActionData builds a suitable ActionData struct with no nil members.
@@ -7,6 +7,7 @@ package context
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -89,6 +90,7 @@ type meterStatus struct {
 type HookProcess interface {
 	Pid() int
 	Kill() error
+	Signal(os.Signal) error
 }
 
 // HookContext is the implementation of jujuc.Context.
@@ -562,6 +564,36 @@ func (c *HookContext) ActionData() (*ActionData, error) {
 	return c.actionData, nil
 }
 
+// StopAction records that the running action is being stopped, so that
+// it finishes with the given status and message whatever its process
+// reports, and asks the process to exit. It returns ErrNoProcess if no
+// process is running, and an error if the process cannot be asked to
+// exit, in which case it should be killed.
+func (ctx *HookContext) StopAction(status, message string) error {
+	if ctx.actionData == nil {
+		return errors.New("not running an action")
+	}
+	mutex.Lock()
+	ctx.actionData.StopStatus = status
+	ctx.actionData.StopMessage = message
+	mutex.Unlock()
+
+	proc := ctx.GetProcess()
+	if proc == nil {
+		return ErrNoProcess
+	}
+	logger.Infof("asking action process %v to exit", proc.Pid())
+	return proc.Signal(os.Interrupt)
+}
+
+// KillAction kills the process running the action.
+func (ctx *HookContext) KillAction() error {
+	if ctx.actionData == nil {
+		return errors.New("not running an action")
+	}
+	return ctx.killCharmHook()
+}
+
 // HookVars returns an os.Environ-style list of strings necessary to run a hook
 // such that it can know what environment it's operating in, and can call back
 // into context.
@@ -746,6 +778,15 @@ func (ctx *HookContext) finalizeAction(err, unhandledErr error) error {
 		status = params.ActionFailed
 	}
 
+	// If the action was stopped, its process was killed, and whatever
+	// it reported is beside the point.
+	mutex.Lock()
+	if ctx.actionData.StopStatus != "" {
+		status = ctx.actionData.StopStatus
+		message = ctx.actionData.StopMessage
+	}
+	mutex.Unlock()
+
 	callErr := ctx.state.ActionFinish(tag, status, results, message)
 	if callErr != nil {
 		unhandledErr = errors.Wrap(unhandledErr, callErr)
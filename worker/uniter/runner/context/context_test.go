@@ -5,6 +5,7 @@ package context_test
 
 import (
 	"errors"
+	"os"
 	"time"
 
 	"github.com/juju/testing"
@@ -272,9 +273,38 @@ func (s *InterfaceSuite) TestSetActionMessage(c *gc.C) {
 	c.Check(actionData.ResultsMessage, gc.Equals, "because reasons")
 }
 
+func (s *InterfaceSuite) TestStopAction(c *gc.C) {
+	var signalled os.Signal
+	hctx := context.GetStubActionContext(nil)
+	hctx.SetProcess(&mockProcess{signal: func(sig os.Signal) error {
+		signalled = sig
+		return nil
+	}})
+	err := hctx.StopAction(params.ActionCancelled, "action cancelled")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(signalled, gc.Equals, os.Interrupt)
+	actionData, err := hctx.ActionData()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(actionData.StopStatus, gc.Equals, params.ActionCancelled)
+	c.Check(actionData.StopMessage, gc.Equals, "action cancelled")
+}
+
+func (s *InterfaceSuite) TestStopActionNoProcess(c *gc.C) {
+	hctx := context.GetStubActionContext(nil)
+	err := hctx.StopAction(params.ActionFailed, "action timed out")
+	c.Assert(err, gc.Equals, context.ErrNoProcess)
+	actionData, err := hctx.ActionData()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(actionData.StopStatus, gc.Equals, params.ActionFailed)
+
+	ctx := &context.HookContext{}
+	err = ctx.StopAction(params.ActionFailed, "action timed out")
+	c.Assert(err, gc.ErrorMatches, "not running an action")
+}
+
 func (s *InterfaceSuite) TestRequestRebootAfterHook(c *gc.C) {
 	var killed bool
-	p := &mockProcess{func() error {
+	p := &mockProcess{kill: func() error {
 		killed = true
 		return nil
 	}}
@@ -292,7 +322,7 @@ func (s *InterfaceSuite) TestRequestRebootNow(c *gc.C) {
 
 	var stub testing.Stub
 	var p *mockProcess
-	p = &mockProcess{func() error {
+	p = &mockProcess{kill: func() error {
 		// Reboot priority should be set before the process
 		// is killed, or else the client waiting for the
 		// process to exit will race with the setting of
@@ -317,7 +347,7 @@ func (s *InterfaceSuite) TestRequestRebootNowTimeout(c *gc.C) {
 
 	var advanced bool
 	var p *mockProcess
-	p = &mockProcess{func() error {
+	p = &mockProcess{kill: func() error {
 		// Reboot priority should be set before the process
 		// is killed, or else the client waiting for the
 		// process to exit will race with the setting of
@@ -413,7 +443,8 @@ func assertStorageAddInContext(c *gc.C,
 }
 
 type mockProcess struct {
-	kill func() error
+	kill   func() error
+	signal func(os.Signal) error
 }
 
 func (p *mockProcess) Kill() error {
@@ -423,3 +454,10 @@ func (p *mockProcess) Kill() error {
 func (p *mockProcess) Pid() int {
 	return 123
 }
+
+func (p *mockProcess) Signal(sig os.Signal) error {
+	if p.signal == nil {
+		return errors.New("not supported")
+	}
+	return p.signal(sig)
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/loglabels"
 )
 
 // JujuLogCommand implements the juju-log command.
@@ -21,6 +23,10 @@ type JujuLogCommand struct {
 	Debug      bool
 	Level      string
 	formatFlag string // deprecated
+
+	// Labels holds the labels to attach to the logged message, such
+	// as the name of the hook being run; see package loglabels.
+	Labels map[string]string
 }
 
 func NewJujuLogCommand(ctx Context) (cmd.Command, error) {
@@ -54,7 +60,7 @@ func (c *JujuLogCommand) Run(ctx *cmd.Context) error {
 	if c.formatFlag != "" {
 		fmt.Fprintf(ctx.Stderr, "--format flag deprecated for command %q", c.Info().Name)
 	}
-	logger := loggo.GetLogger(fmt.Sprintf("unit.%s.juju-log", c.ctx.UnitName()))
+	logger := loglabels.NewLogger(loggo.GetLogger(fmt.Sprintf("unit.%s.juju-log", c.ctx.UnitName())), c.Labels)
 
 	logLevel := loggo.INFO
 	if c.Debug {
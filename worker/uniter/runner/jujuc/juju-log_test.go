@@ -12,6 +12,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/loglabels"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
 )
@@ -109,3 +110,17 @@ func (s *JujuLogSuite) TestLogDeprecation(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(testing.Stderr(ctx), gc.Equals, "--format flag deprecated for command \"juju-log\"")
 }
+
+func (s *JujuLogSuite) TestLabels(c *gc.C) {
+	tw := &loglabels.TestWriter{}
+	_, err := loggo.ReplaceDefaultWriter(tw)
+	c.Assert(err, jc.ErrorIsNil)
+	com := s.newJujuLogCommand(c)
+	com.(*jujuc.JujuLogCommand).Labels = map[string]string{"hook": "install"}
+	code := cmd.Main(com, &cmd.Context{}, []string{"the chickens"})
+	c.Assert(code, gc.Equals, 0)
+	log := tw.Log()
+	c.Assert(log, gc.HasLen, 1)
+	c.Assert(log[0].Message, gc.Equals, "the chickens")
+	c.Assert(tw.Labels()[0], jc.DeepEquals, map[string]string{"hook": "install"})
+}
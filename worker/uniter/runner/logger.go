@@ -9,7 +9,7 @@ import (
 	"sync"
 	"time"
 
-	"github.com/juju/loggo"
+	"github.com/juju/juju/loglabels"
 )
 
 type hookLogger struct {
@@ -17,7 +17,7 @@ type hookLogger struct {
 	done    chan struct{}
 	mu      sync.Mutex
 	stopped bool
-	logger  loggo.Logger
+	logger  loglabels.Logger
 }
 
 func (l *hookLogger) run() {
@@ -14,6 +14,7 @@ import (
 	"github.com/juju/loggo"
 	utilexec "github.com/juju/utils/exec"
 
+	"github.com/juju/juju/loglabels"
 	"github.com/juju/juju/worker/uniter/runner/context"
 	"github.com/juju/juju/worker/uniter/runner/debug"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
@@ -45,6 +46,8 @@ type Context interface {
 	HookVars(paths context.Paths) ([]string, error)
 	ActionData() (*context.ActionData, error)
 	SetProcess(process context.HookProcess)
+	StopAction(status, message string) error
+	KillAction() error
 	HasExecutionSetUnitStatus() bool
 	ResetExecutionSetUnitStatus()
 
@@ -69,7 +72,7 @@ func (runner *runner) Context() Context {
 
 // RunCommands exists to satisfy the Runner interface.
 func (runner *runner) RunCommands(commands string) (*utilexec.ExecResponse, error) {
-	srv, err := runner.startJujucServer()
+	srv, err := runner.startJujucServer(nil)
 	if err != nil {
 		return nil, err
 	}
@@ -110,7 +113,10 @@ func (runner *runner) RunHook(hookName string) error {
 }
 
 func (runner *runner) runCharmHookWithLocation(hookName, charmLocation string) error {
-	srv, err := runner.startJujucServer()
+	// Label the hook's output and juju-log messages, so the hook's
+	// logs can be found in debug-log.
+	labels := runner.logLabels(hookName, charmLocation)
+	srv, err := runner.startJujucServer(labels)
 	if err != nil {
 		return err
 	}
@@ -129,15 +135,33 @@ func (runner *runner) runCharmHookWithLocation(hookName, charmLocation string) e
 
 	debugctx := debug.NewHooksContext(runner.context.UnitName())
 	if session, _ := debugctx.FindSession(); session != nil && session.MatchHook(hookName) {
-		logger.Infof("executing %s via debug-hooks", hookName)
+		loglabels.NewLogger(logger, labels).Infof("executing %s via debug-hooks", hookName)
 		err = session.RunHook(hookName, runner.paths.GetCharmDir(), env)
 	} else {
-		err = runner.runCharmHook(hookName, env, charmLocation)
+		err = runner.runCharmHook(hookName, env, charmLocation, labels)
 	}
 	return runner.context.Flush(hookName, err)
 }
 
-func (runner *runner) runCharmHook(hookName string, env []string, charmLocation string) error {
+// logLabels returns the labels to attach to the logs of the named hook
+// or action. Labels that cannot be determined, such as the relation of
+// a hook run in a restricted context, are omitted rather than failing
+// the hook.
+func (runner *runner) logLabels(hookName, charmLocation string) map[string]string {
+	if charmLocation == "actions" {
+		if actionData, err := runner.context.ActionData(); err == nil {
+			return map[string]string{loglabels.Action: actionData.Tag.Id()}
+		}
+		return nil
+	}
+	labels := map[string]string{loglabels.Hook: hookName}
+	if r, err := runner.context.HookRelation(); err == nil {
+		labels[loglabels.Relation] = r.FakeId()
+	}
+	return labels
+}
+
+func (runner *runner) runCharmHook(hookName string, env []string, charmLocation string, labels map[string]string) error {
 	charmDir := runner.paths.GetCharmDir()
 	hook, err := searchHook(charmDir, filepath.Join(charmLocation, hookName))
 	if err != nil {
@@ -156,7 +180,7 @@ func (runner *runner) runCharmHook(hookName string, env []string, charmLocation
 	hookLogger := &hookLogger{
 		r:      outReader,
 		done:   make(chan struct{}),
-		logger: runner.getLogger(hookName),
+		logger: loglabels.NewLogger(runner.getLogger(hookName), labels),
 	}
 	go hookLogger.run()
 	err = ps.Start()
@@ -171,13 +195,17 @@ func (runner *runner) runCharmHook(hookName string, env []string, charmLocation
 	return errors.Trace(err)
 }
 
-func (runner *runner) startJujucServer() (*jujuc.Server, error) {
+func (runner *runner) startJujucServer(labels map[string]string) (*jujuc.Server, error) {
 	// Prepare server.
 	getCmd := func(ctxId, cmdName string) (cmd.Command, error) {
 		if ctxId != runner.context.Id() {
 			return nil, errors.Errorf("expected context id %q, got %q", runner.context.Id(), ctxId)
 		}
-		return jujuc.NewCommand(runner.context, cmdName)
+		command, err := jujuc.NewCommand(runner.context, cmdName)
+		if logCommand, ok := command.(*jujuc.JujuLogCommand); ok {
+			logCommand.Labels = labels
+		}
+		return command, err
 	}
 	srv, err := jujuc.NewServer(getCmd, runner.paths.GetJujucSocket())
 	if err != nil {
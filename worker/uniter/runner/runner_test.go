@@ -12,15 +12,19 @@ import (
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
 	envtesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/proxy"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6-unstable/hooks"
 
+	"github.com/juju/juju/loglabels"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/runner"
 	"github.com/juju/juju/worker/uniter/runner/context"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
 	runnertesting "github.com/juju/juju/worker/uniter/runner/testing"
 )
 
@@ -147,12 +151,22 @@ func (s *RunHookSuite) TestRunHook(c *gc.C) {
 type MockContext struct {
 	runner.Context
 	actionData   *context.ActionData
+	relation     jujuc.ContextRelation
 	expectPid    int
 	flushBadge   string
 	flushFailure error
 	flushResult  error
 }
 
+type mockRelation struct {
+	jujuc.ContextRelation
+	fakeId string
+}
+
+func (r *mockRelation) FakeId() string {
+	return r.fakeId
+}
+
 func (ctx *MockContext) UnitName() string {
 	return "some-unit/999"
 }
@@ -168,6 +182,13 @@ func (ctx *MockContext) ActionData() (*context.ActionData, error) {
 	return ctx.actionData, nil
 }
 
+func (ctx *MockContext) HookRelation() (jujuc.ContextRelation, error) {
+	if ctx.relation == nil {
+		return nil, errors.NotFoundf("relation")
+	}
+	return ctx.relation, nil
+}
+
 func (ctx *MockContext) SetProcess(process context.HookProcess) {
 	ctx.expectPid = process.Pid()
 }
@@ -274,6 +295,65 @@ func (s *RunMockContextSuite) TestRunActionFlushFailure(c *gc.C) {
 	s.assertRecordedPid(c, ctx.expectPid)
 }
 
+// assertOutputLabels checks that the single line of output logged by
+// a hook carries the expected labels.
+func (s *RunMockContextSuite) assertOutputLabels(c *gc.C, writer *loglabels.TestWriter, expectLabels map[string]string) {
+	var found bool
+	labels := writer.Labels()
+	for i, entry := range writer.Log() {
+		if entry.Module != "unit.some-unit/999.something-happened" {
+			continue
+		}
+		c.Assert(entry.Message, gc.Equals, "hello")
+		c.Assert(labels[i], jc.DeepEquals, expectLabels)
+		found = true
+	}
+	c.Assert(found, jc.IsTrue)
+}
+
+func (s *RunMockContextSuite) TestRunHookLogLabels(c *gc.C) {
+	var writer loglabels.TestWriter
+	err := loggo.RegisterWriter("test", &writer, loggo.INFO)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := &MockContext{
+		relation: &mockRelation{fakeId: "db:1"},
+	}
+	makeCharm(c, hookSpec{
+		dir:    "hooks",
+		name:   hookName,
+		perm:   0700,
+		stdout: "hello",
+	}, s.paths.GetCharmDir())
+	err = runner.NewRunner(ctx, s.paths).RunHook("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertOutputLabels(c, &writer, map[string]string{
+		"hook":     "something-happened",
+		"relation": "db:1",
+	})
+}
+
+func (s *RunMockContextSuite) TestRunActionLogLabels(c *gc.C) {
+	var writer loglabels.TestWriter
+	err := loggo.RegisterWriter("test", &writer, loggo.INFO)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := &MockContext{
+		actionData: &context.ActionData{
+			Tag: names.NewActionTag("7a5ea4d1-7e25-4cd2-8f43-f1b6e61e1e6a"),
+		},
+	}
+	makeCharm(c, hookSpec{
+		dir:    "actions",
+		name:   hookName,
+		perm:   0700,
+		stdout: "hello",
+	}, s.paths.GetCharmDir())
+	err = runner.NewRunner(ctx, s.paths).RunAction("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertOutputLabels(c, &writer, map[string]string{
+		"action": "7a5ea4d1-7e25-4cd2-8f43-f1b6e61e1e6a",
+	})
+}
+
 func (s *RunMockContextSuite) TestRunCommandsFlushSuccess(c *gc.C) {
 	expectErr := errors.New("pew pew pew")
 	ctx := &MockContext{
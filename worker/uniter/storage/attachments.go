@@ -315,7 +315,11 @@ func (a *Attachments) CommitHook(hi hook.Info) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	if err := storageState.CommitHook(hi); err != nil {
+	size := storageState.size
+	if context, ok := a.storageAttachments[names.NewStorageTag(hi.StorageId)].ContextStorageAttachment.(*contextStorage); ok {
+		size = context.size
+	}
+	if err := storageState.commitHook(hi, size); err != nil {
 		return err
 	}
 	storageTag := names.NewStorageTag(hi.StorageId)
@@ -343,7 +347,7 @@ func (a *Attachments) removeStorageAttachment(tag names.StorageTag, storageKnown
 }
 
 func (a *Attachments) storageStateForHook(hi hook.Info) (*stateFile, error) {
-	if !hi.Kind.IsStorage() {
+	if !hook.IsStorage(hi.Kind) {
 		return nil, errors.Errorf("not a storage hook: %#v", hi)
 	}
 	storageAttachment, ok := a.storageAttachments[names.NewStorageTag(hi.StorageId)]
@@ -129,11 +129,11 @@ func (s *storageResolver) nextHookOp(
 	}
 	switch snap.Life {
 	case params.Alive:
-		if storageAttachment.attached {
+		if storageAttachment.attached && snap.Size <= storageAttachment.size {
 			// Storage attachments currently do not change
-			// (apart from lifecycle) after being provisioned.
-			// We don't process unprovisioned storage here,
-			// so there's nothing to do.
+			// (apart from lifecycle and growth) after being
+			// provisioned. We don't process unprovisioned
+			// storage here, so there's nothing to do.
 			return nil, resolver.ErrNoOperation
 		}
 	case params.Dying:
@@ -152,15 +152,19 @@ func (s *storageResolver) nextHookOp(
 	hookInfo := hook.Info{
 		StorageId: tag.Id(),
 	}
-	if snap.Life == params.Alive {
-		hookInfo.Kind = hooks.StorageAttached
-	} else {
+	switch {
+	case snap.Life != params.Alive:
 		hookInfo.Kind = hooks.StorageDetaching
+	case storageAttachment.attached:
+		hookInfo.Kind = hook.StorageResized
+	default:
+		hookInfo.Kind = hooks.StorageAttached
 	}
 	context := &contextStorage{
 		tag:      tag,
 		kind:     storage.StorageKind(snap.Kind),
 		location: snap.Location,
+		size:     snap.Size,
 	}
 	storageAttachment.ContextStorageAttachment = context
 	s.storage.storageAttachments[tag] = storageAttachment
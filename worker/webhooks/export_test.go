@@ -0,0 +1,49 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhooks
+
+import (
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+var CheckAddress = &checkAddress
+
+// Dispatcher queues deliveries to the environment's webhooks.
+type Dispatcher struct {
+	d *dispatcher
+}
+
+// NewDispatcher returns a Dispatcher with no running queues.
+func NewDispatcher(config Config) *Dispatcher {
+	return &Dispatcher{newDispatcher(config)}
+}
+
+// Dispatch queues the deltas for delivery to the environment's
+// webhooks.
+func (d *Dispatcher) Dispatch(deltas []multiwatcher.Delta) error {
+	return d.d.dispatch(deltas)
+}
+
+// Drain waits for all queued deliveries to be made, and stops the
+// queues.
+func (d *Dispatcher) Drain() {
+	for id, q := range d.d.queues {
+		close(q.deliveries)
+		<-q.done
+		delete(d.d.queues, id)
+	}
+}
+
+// Stop stops the queues, abandoning any queued deliveries.
+func (d *Dispatcher) Stop() {
+	d.d.stopQueues()
+}
+
+// Dispatch delivers the deltas to the environment's webhooks, and
+// waits for the deliveries to be made.
+func Dispatch(config Config, deltas []multiwatcher.Delta) error {
+	d := NewDispatcher(config)
+	defer d.Drain()
+	return d.Dispatch(deltas)
+}
@@ -0,0 +1,522 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package webhooks provides a worker that delivers changes to an
+// environment, as reported by the allwatcher, to the webhooks
+// registered in that environment. It uses the WebhookDispatcher
+// facade.
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.webhooks")
+
+const (
+	// SignatureHeader holds the hex-encoded HMAC-SHA256 of the
+	// request's timestamp, a ".", and the request body, keyed with
+	// the webhook's secret and prefixed with "sha256=".
+	SignatureHeader = "X-Juju-Signature"
+
+	// TimestampHeader holds the time at which the request was sent,
+	// in seconds since the Unix epoch. It is covered by the
+	// signature, so that receivers can reject replayed requests.
+	TimestampHeader = "X-Juju-Timestamp"
+
+	// WebhookHeader holds the id of the webhook being delivered to.
+	WebhookHeader = "X-Juju-Webhook"
+)
+
+// Facade represents the API used by the webhooks worker.
+type Facade interface {
+	WatchAll() (*api.AllWatcher, error)
+	Webhooks() (params.WebhookTargetsResult, error)
+	AddDeadLetter(id, payload string, attempts int, deliveryErr string) error
+}
+
+// Config holds the dependencies and delivery settings for the webhooks
+// worker.
+type Config struct {
+	// Facade is used to watch the environment, read its webhooks
+	// and record dead letters.
+	Facade Facade
+
+	// EnvUUID identifies the environment in each delivery.
+	EnvUUID string
+
+	// Timeout limits the time taken by each delivery attempt.
+	Timeout time.Duration
+
+	// Attempts is the number of times a delivery is attempted
+	// before it is recorded as a dead letter.
+	Attempts int
+
+	// Delay is the time waited after the first failed attempt. It
+	// doubles after each subsequent failure, up to MaxDelay.
+	Delay time.Duration
+
+	// MaxDelay is the longest time waited between attempts.
+	MaxDelay time.Duration
+
+	// QueueSize is the number of deliveries that may wait for each
+	// webhook. Deliveries to a webhook whose queue is full are
+	// recorded as dead letters without being attempted.
+	QueueSize int
+}
+
+// Validate returns an error if the config cannot be used to start a
+// worker.
+func (c *Config) Validate() error {
+	if c.Facade == nil {
+		return errors.New("missing Facade")
+	}
+	if c.EnvUUID == "" {
+		return errors.New("missing EnvUUID")
+	}
+	if c.Attempts < 1 {
+		return errors.New("Attempts must be positive")
+	}
+	return nil
+}
+
+// DefaultConfig holds the delivery settings used by the machine agent.
+var DefaultConfig = Config{
+	Timeout:   30 * time.Second,
+	Attempts:  5,
+	Delay:     time.Second,
+	MaxDelay:  time.Minute,
+	QueueSize: 100,
+}
+
+// Payload is the body of each delivery.
+type Payload struct {
+	EnvUUID   string               `json:"env-uuid"`
+	WebhookId string               `json:"webhook-id"`
+	Deltas    []multiwatcher.Delta `json:"deltas"`
+}
+
+// checkAddress returns an error if deliveries must not be made to the
+// IP address, because it is local to the controller or is one of the
+// controller's addresses. It is a variable so that tests can deliver
+// to local servers.
+var checkAddress = func(ip net.IP, controller map[string]bool) error {
+	if controller[ip.String()] {
+		return errors.Errorf("cannot deliver to controller address %s", ip)
+	}
+	if network.IsLocalIP(ip) {
+		return errors.Errorf("cannot deliver to local address %s", ip)
+	}
+	return nil
+}
+
+// lookupIP is a variable so that tests need not rely on DNS.
+var lookupIP = net.LookupIP
+
+// New returns a worker that watches the environment and POSTs each
+// batch of changes that matches a webhook's filter to that webhook.
+// Deliveries are signed with the webhook's secret. Each webhook's
+// deliveries are made in order, independently of other webhooks, so
+// a slow or failing webhook delays only itself. Deliveries that still
+// fail after all attempts, or that cannot be queued, are recorded as
+// dead letters on the webhook.
+//
+// Deliveries are never made to loopback, link-local or unspecified
+// addresses, nor to the controller's addresses; the check is made
+// when connecting, so it also covers redirects and hosts whose DNS
+// records change after the webhook is added.
+//
+// Only changes made after the worker starts are delivered; the
+// initial contents of the environment are not.
+func New(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	d := newDispatcher(config)
+	return worker.NewSimpleWorker(d.loop), nil
+}
+
+type dispatcher struct {
+	config Config
+	client *http.Client
+
+	// mu guards controller, which holds the controller's addresses
+	// as last reported by the facade.
+	mu         sync.Mutex
+	controller map[string]bool
+
+	// queues holds the delivery queue of each webhook, keyed
+	// by webhook id.
+	queues map[string]*queue
+
+	// failed receives errors that stop a queue's goroutine, and
+	// must stop the worker.
+	failed chan error
+}
+
+func newDispatcher(config Config) *dispatcher {
+	d := &dispatcher{
+		config: config,
+		queues: make(map[string]*queue),
+		failed: make(chan error, 1),
+	}
+	d.client = &http.Client{
+		Timeout:   config.Timeout,
+		Transport: &http.Transport{Dial: d.dial},
+	}
+	return d
+}
+
+// dial connects to the address, refusing to connect if the host is,
+// or resolves to, an address that deliveries must not be made to.
+func (d *dispatcher) dial(network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	d.mu.Lock()
+	controller := d.controller
+	d.mu.Unlock()
+	if controller[host] {
+		return nil, errors.Errorf("cannot deliver to controller address %s", host)
+	}
+	ips, err := lookupIP(host)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(ips) == 0 {
+		return nil, errors.Errorf("no addresses found for %s", host)
+	}
+	for _, ip := range ips {
+		if err := checkAddress(ip, controller); err != nil {
+			return nil, err
+		}
+	}
+	// Connect to the checked address, rather than resolving the
+	// host again.
+	dialer := net.Dialer{Timeout: d.config.Timeout}
+	return dialer.Dial(network, net.JoinHostPort(ips[0].String(), port))
+}
+
+// setControllerAddresses records the addresses that deliveries must
+// not be made to.
+func (d *dispatcher) setControllerAddresses(addrs []string) {
+	controller := make(map[string]bool)
+	for _, addr := range addrs {
+		controller[addr] = true
+	}
+	d.mu.Lock()
+	d.controller = controller
+	d.mu.Unlock()
+}
+
+// delivery holds a signed request body to be POSTed to a webhook.
+type delivery struct {
+	hook    params.WebhookTarget
+	body    []byte
+	changes int
+}
+
+// queue delivers to a single webhook from its own goroutine.
+type queue struct {
+	deliveries chan delivery
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+func (d *dispatcher) loop(stop <-chan struct{}) error {
+	defer d.stopQueues()
+	w, err := d.config.Facade.WatchAll()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer w.Stop()
+
+	changes := make(chan []multiwatcher.Delta)
+	failed := make(chan error, 1)
+	go func() {
+		for {
+			deltas, err := w.Next()
+			if err != nil {
+				failed <- err
+				return
+			}
+			select {
+			case changes <- deltas:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	initial := true
+	for {
+		select {
+		case <-stop:
+			return nil
+		case err := <-failed:
+			return errors.Trace(err)
+		case err := <-d.failed:
+			return errors.Trace(err)
+		case deltas := <-changes:
+			if initial {
+				// The first batch describes the whole environment,
+				// rather than changes to it.
+				initial = false
+				continue
+			}
+			if err := d.dispatch(deltas); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+// dispatch queues the deltas matching each webhook's filter for
+// delivery to that webhook, starting a queue for any new webhook and
+// stopping those of removed webhooks. Deliveries that cannot be queued
+// are recorded as dead letters; only errors calling the API are
+// returned.
+func (d *dispatcher) dispatch(deltas []multiwatcher.Delta) error {
+	result, err := d.config.Facade.Webhooks()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	d.setControllerAddresses(result.ControllerAddresses)
+	hooks := result.Webhooks
+	current := make(map[string]bool)
+	for _, hook := range hooks {
+		current[hook.Id] = true
+	}
+	for id, q := range d.queues {
+		if !current[id] {
+			close(q.stop)
+			delete(d.queues, id)
+		}
+	}
+	for _, hook := range hooks {
+		matched := Filter(hook.Kinds, hook.Statuses, deltas)
+		if len(matched) == 0 {
+			continue
+		}
+		body, err := json.Marshal(Payload{
+			EnvUUID:   d.config.EnvUUID,
+			WebhookId: hook.Id,
+			Deltas:    matched,
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		q, ok := d.queues[hook.Id]
+		if !ok {
+			q = d.startQueue()
+			d.queues[hook.Id] = q
+		}
+		select {
+		case q.deliveries <- delivery{hook, body, len(matched)}:
+			continue
+		default:
+		}
+		logger.Warningf("delivery queue for webhook %q is full; dropping %d changes", hook.Id, len(matched))
+		if err := d.config.Facade.AddDeadLetter(hook.Id, string(body), 0, "delivery queue full"); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// startQueue starts a goroutine that makes the deliveries sent to the
+// returned queue, in order, until it is stopped or its deliveries
+// channel is closed and drained.
+func (d *dispatcher) startQueue() *queue {
+	q := &queue{
+		deliveries: make(chan delivery, d.config.QueueSize),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go func() {
+		defer close(q.done)
+		for {
+			select {
+			case <-q.stop:
+				return
+			case del, ok := <-q.deliveries:
+				if !ok {
+					return
+				}
+				if err := d.send(q.stop, del); err == worker.ErrKilled {
+					return
+				} else if err != nil {
+					select {
+					case d.failed <- err:
+					default:
+					}
+					return
+				}
+			}
+		}
+	}()
+	return q
+}
+
+// stopQueues stops every queue, abandoning the deliveries waiting in
+// them, and waits for their goroutines to finish.
+func (d *dispatcher) stopQueues() {
+	for id, q := range d.queues {
+		close(q.stop)
+		<-q.done
+		delete(d.queues, id)
+	}
+}
+
+// send makes the delivery, recording it as a dead letter if it fails.
+// Only errors calling the API are returned.
+func (d *dispatcher) send(stop <-chan struct{}, del delivery) error {
+	id := del.hook.Id
+	attempts, err := d.deliver(stop, id, del.hook.URL, del.hook.Secret, del.body)
+	if err == worker.ErrKilled {
+		return err
+	} else if err != nil {
+		logger.Warningf("giving up on delivery to webhook %q after %d attempts: %v", id, attempts, err)
+		if err := d.config.Facade.AddDeadLetter(id, string(del.body), attempts, err.Error()); err != nil {
+			return errors.Trace(err)
+		}
+		return nil
+	}
+	logger.Debugf("delivered %d changes to webhook %q", del.changes, id)
+	return nil
+}
+
+// deliver POSTs the body to the URL until it is accepted or the
+// attempts are exhausted, backing off between attempts. It returns the
+// number of attempts made, and the error from the last of them.
+func (d *dispatcher) deliver(stop <-chan struct{}, id, url, secret string, body []byte) (int, error) {
+	delay := d.config.Delay
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = post(d.client, id, url, secret, body); err == nil {
+			return attempt, nil
+		}
+		if attempt >= d.config.Attempts {
+			return attempt, err
+		}
+		logger.Debugf("delivery to webhook %q failed (attempt %d): %v", id, attempt, err)
+		select {
+		case <-stop:
+			return attempt, worker.ErrKilled
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > d.config.MaxDelay {
+			delay = d.config.MaxDelay
+		}
+	}
+}
+
+// post makes a single delivery attempt, signed with a fresh timestamp.
+func post(client *http.Client, id, url, secret string, body []byte) error {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookHeader, id)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, "sha256="+Sign(secret, timestamp, body))
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s", resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of the timestamp, a ".",
+// and the body, keyed with secret.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Filter returns the deltas concerning entities of one of the given
+// kinds that have one of the given statuses. An empty list of kinds or
+// statuses matches everything.
+func Filter(kinds, statuses []string, deltas []multiwatcher.Delta) []multiwatcher.Delta {
+	var matched []multiwatcher.Delta
+	for _, delta := range deltas {
+		if len(kinds) > 0 && !contains(kinds, delta.Entity.EntityId().Kind) {
+			continue
+		}
+		if len(statuses) > 0 && !anyContained(statuses, entityStatuses(delta.Entity)) {
+			continue
+		}
+		matched = append(matched, delta)
+	}
+	return matched
+}
+
+// entityStatuses returns the statuses reported for the entity. Units
+// report both their workload and agent status.
+func entityStatuses(info multiwatcher.EntityInfo) []string {
+	switch info := info.(type) {
+	case *multiwatcher.MachineInfo:
+		return []string{string(info.Status)}
+	case *multiwatcher.ServiceInfo:
+		return []string{string(info.Status.Current)}
+	case *multiwatcher.UnitInfo:
+		return []string{
+			string(info.WorkloadStatus.Current),
+			string(info.AgentStatus.Current),
+		}
+	case *multiwatcher.ActionInfo:
+		return []string{info.Status}
+	case *multiwatcher.PayloadInfo:
+		return []string{info.Status}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func anyContained(values, candidates []string) bool {
+	for _, c := range candidates {
+		if c != "" && contains(values, c) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,342 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhooks_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	apiwebhookdispatcher "github.com/juju/juju/api/webhookdispatcher"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/multiwatcher"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/webhooks"
+)
+
+func TestPackage(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+// checkAddress holds the address check made by the worker, which the
+// suite disables.
+var checkAddress = *webhooks.CheckAddress
+
+type request struct {
+	header http.Header
+	body   []byte
+}
+
+type webhooksSuite struct {
+	testing.JujuConnSuite
+	server   *httptest.Server
+	requests chan request
+	status   int
+	config   webhooks.Config
+}
+
+var _ = gc.Suite(&webhooksSuite{})
+
+func (s *webhooksSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.requests = make(chan request, 10)
+	s.status = http.StatusOK
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		c.Check(err, jc.ErrorIsNil)
+		s.requests <- request{r.Header, body}
+		w.WriteHeader(s.status)
+	}))
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+	apiSt, _ := s.OpenAPIAsNewMachine(c, state.JobManageEnviron)
+	s.config = webhooks.Config{
+		Facade:    apiwebhookdispatcher.NewFacade(apiSt),
+		EnvUUID:   s.State.EnvironUUID(),
+		Timeout:   coretesting.LongWait,
+		Attempts:  3,
+		Delay:     time.Millisecond,
+		MaxDelay:  2 * time.Millisecond,
+		QueueSize: 10,
+	}
+	// The test servers listen on the loopback address.
+	s.PatchValue(webhooks.CheckAddress, func(net.IP, map[string]bool) error {
+		return nil
+	})
+}
+
+func (s *webhooksSuite) addWebhook(c *gc.C, kinds, statuses []string) *state.Webhook {
+	return s.addWebhookURL(c, s.server.URL, kinds, statuses)
+}
+
+func (s *webhooksSuite) addWebhookURL(c *gc.C, url string, kinds, statuses []string) *state.Webhook {
+	hook, err := s.State.AddWebhook(state.AddWebhookArgs{
+		URL:      url,
+		Kinds:    kinds,
+		Statuses: statuses,
+		Secret:   "s3cret",
+		Owner:    "admin",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return hook
+}
+
+var (
+	unitError = multiwatcher.Delta{Entity: &multiwatcher.UnitInfo{
+		Name:           "wordpress/0",
+		WorkloadStatus: multiwatcher.StatusInfo{Current: "error"},
+		AgentStatus:    multiwatcher.StatusInfo{Current: "idle"},
+	}}
+	unitActive = multiwatcher.Delta{Entity: &multiwatcher.UnitInfo{
+		Name:           "wordpress/1",
+		WorkloadStatus: multiwatcher.StatusInfo{Current: "active"},
+		AgentStatus:    multiwatcher.StatusInfo{Current: "idle"},
+	}}
+	machineError = multiwatcher.Delta{Entity: &multiwatcher.MachineInfo{
+		Id:     "0",
+		Status: "error",
+	}}
+	machineRemoved = multiwatcher.Delta{Removed: true, Entity: &multiwatcher.MachineInfo{
+		Id:     "1",
+		Status: "started",
+	}}
+)
+
+func (s *webhooksSuite) TestFilter(c *gc.C) {
+	deltas := []multiwatcher.Delta{unitError, unitActive, machineError, machineRemoved}
+	for i, test := range []struct {
+		kinds    []string
+		statuses []string
+		expect   []multiwatcher.Delta
+	}{{
+		expect: deltas,
+	}, {
+		kinds:  []string{"unit"},
+		expect: []multiwatcher.Delta{unitError, unitActive},
+	}, {
+		statuses: []string{"error"},
+		expect:   []multiwatcher.Delta{unitError, machineError},
+	}, {
+		kinds:    []string{"machine"},
+		statuses: []string{"error", "started"},
+		expect:   []multiwatcher.Delta{machineError, machineRemoved},
+	}, {
+		statuses: []string{"idle"},
+		expect:   []multiwatcher.Delta{unitError, unitActive},
+	}, {
+		kinds: []string{"service"},
+	}} {
+		c.Logf("test %d", i)
+		c.Check(webhooks.Filter(test.kinds, test.statuses, deltas), jc.DeepEquals, test.expect)
+	}
+}
+
+func (s *webhooksSuite) TestDispatchSigned(c *gc.C) {
+	hook := s.addWebhook(c, []string{"unit"}, []string{"error"})
+	deltas := []multiwatcher.Delta{unitError, unitActive, machineError}
+	err := webhooks.Dispatch(s.config, deltas)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var req request
+	select {
+	case req = <-s.requests:
+	default:
+		c.Fatalf("no delivery made")
+	}
+	c.Assert(req.header.Get("Content-Type"), gc.Equals, "application/json")
+	c.Assert(req.header.Get(webhooks.WebhookHeader), gc.Equals, hook.Id())
+	timestamp := req.header.Get(webhooks.TimestampHeader)
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(time.Since(time.Unix(sent, 0)) < time.Minute, jc.IsTrue)
+	c.Assert(req.header.Get(webhooks.SignatureHeader), gc.Equals, "sha256="+webhooks.Sign("s3cret", timestamp, req.body))
+	// The signature covers the timestamp.
+	c.Assert(webhooks.Sign("s3cret", "0", req.body), gc.Not(gc.Equals), webhooks.Sign("s3cret", timestamp, req.body))
+
+	var payload struct {
+		EnvUUID   string               `json:"env-uuid"`
+		WebhookId string               `json:"webhook-id"`
+		Deltas    []multiwatcher.Delta `json:"deltas"`
+	}
+	err = json.Unmarshal(req.body, &payload)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(payload.EnvUUID, gc.Equals, s.State.EnvironUUID())
+	c.Assert(payload.WebhookId, gc.Equals, hook.Id())
+	c.Assert(payload.Deltas, gc.HasLen, 1)
+	c.Assert(payload.Deltas[0].Entity.EntityId().Id, gc.Equals, "wordpress/0")
+	s.assertNoMoreRequests(c)
+}
+
+func (s *webhooksSuite) TestDispatchNoMatch(c *gc.C) {
+	s.addWebhook(c, []string{"service"}, nil)
+	err := webhooks.Dispatch(s.config, []multiwatcher.Delta{unitError})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNoMoreRequests(c)
+}
+
+func (s *webhooksSuite) TestDispatchDeadLetter(c *gc.C) {
+	s.status = http.StatusServiceUnavailable
+	hook := s.addWebhook(c, nil, nil)
+	err := webhooks.Dispatch(s.config, []multiwatcher.Delta{machineError})
+	c.Assert(err, jc.ErrorIsNil)
+
+	var bodies []string
+	for i := 0; i < 3; i++ {
+		select {
+		case req := <-s.requests:
+			bodies = append(bodies, string(req.body))
+		default:
+			c.Fatalf("expected 3 attempts, got %d", i)
+		}
+	}
+	s.assertNoMoreRequests(c)
+
+	letters, err := hook.DeadLetters()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(letters, gc.HasLen, 1)
+	c.Assert(letters[0].Attempts, gc.Equals, 3)
+	c.Assert(letters[0].Error, gc.Equals, "503 Service Unavailable")
+	c.Assert(letters[0].Payload, gc.Equals, bodies[2])
+}
+
+// blockingServer returns a server that does not respond to requests
+// until the returned channel is closed.
+func (s *webhooksSuite) blockingServer(c *gc.C) (*httptest.Server, chan struct{}) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	s.AddCleanup(func(*gc.C) { server.Close() })
+	return server, release
+}
+
+func (s *webhooksSuite) TestSlowWebhookDoesNotDelayOthers(c *gc.C) {
+	slow, release := s.blockingServer(c)
+	s.addWebhookURL(c, slow.URL, nil, nil)
+	s.addWebhook(c, nil, nil)
+	d := webhooks.NewDispatcher(s.config)
+	defer d.Stop()
+	defer close(release)
+
+	err := d.Dispatch([]multiwatcher.Delta{machineError})
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case <-s.requests:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("delivery delayed by slow webhook")
+	}
+}
+
+func (s *webhooksSuite) TestDispatchQueueFull(c *gc.C) {
+	slow, release := s.blockingServer(c)
+	hook := s.addWebhookURL(c, slow.URL, nil, nil)
+	s.config.QueueSize = 1
+	d := webhooks.NewDispatcher(s.config)
+	defer d.Stop()
+	defer close(release)
+
+	// At most one delivery can be in progress and one queued,
+	// so at least one of these is dropped.
+	for i := 0; i < 3; i++ {
+		err := d.Dispatch([]multiwatcher.Delta{machineError})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	letters, err := hook.DeadLetters()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(letters, gc.Not(gc.HasLen), 0)
+	c.Assert(letters[0].Attempts, gc.Equals, 0)
+	c.Assert(letters[0].Error, gc.Equals, "delivery queue full")
+}
+
+func (s *webhooksSuite) TestWorkerDeliversChanges(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.addWebhook(c, []string{"machine"}, nil)
+
+	w, err := webhooks.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() {
+		w.Kill()
+		c.Assert(w.Wait(), jc.ErrorIsNil)
+	}()
+
+	// Changes made before the worker has read the initial contents
+	// of the environment are not delivered, so keep changing the
+	// machine until a delivery arrives.
+	timeout := time.After(coretesting.LongWait)
+	for i := 0; ; i++ {
+		err := machine.SetStatus(state.StatusStarted, fmt.Sprintf("change %d", i), nil)
+		c.Assert(err, jc.ErrorIsNil)
+		select {
+		case req := <-s.requests:
+			c.Assert(string(req.body), jc.Contains, `"machine","change"`)
+			return
+		case <-time.After(coretesting.ShortWait):
+		case <-timeout:
+			c.Fatalf("no delivery made")
+		}
+	}
+}
+
+func (s *webhooksSuite) TestNewInvalidConfig(c *gc.C) {
+	s.config.Facade = nil
+	w, err := webhooks.New(s.config)
+	c.Assert(w, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "missing Facade")
+}
+
+func (s *webhooksSuite) TestDeliveryToLocalAddressRefused(c *gc.C) {
+	s.PatchValue(webhooks.CheckAddress, checkAddress)
+	hook := s.addWebhook(c, nil, nil)
+	err := webhooks.Dispatch(s.config, []multiwatcher.Delta{machineError})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNoMoreRequests(c)
+
+	letters, err := hook.DeadLetters()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(letters, gc.HasLen, 1)
+	c.Assert(letters[0].Error, jc.Contains, "cannot deliver to local address 127.0.0.1")
+}
+
+func (s *webhooksSuite) TestDeliveryToControllerAddressRefused(c *gc.C) {
+	// Allow local addresses, so that only the controller
+	// address check can refuse the delivery.
+	s.PatchValue(webhooks.CheckAddress, func(ip net.IP, controller map[string]bool) error {
+		if ip.IsLoopback() && !controller[ip.String()] {
+			return nil
+		}
+		return checkAddress(ip, controller)
+	})
+	err := s.State.SetAPIHostPorts([][]network.HostPort{
+		network.NewHostPorts(17070, "127.0.0.1"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	hook := s.addWebhook(c, nil, nil)
+	err = webhooks.Dispatch(s.config, []multiwatcher.Delta{machineError})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNoMoreRequests(c)
+
+	letters, err := hook.DeadLetters()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(letters, gc.HasLen, 1)
+	c.Assert(letters[0].Error, jc.Contains, "cannot deliver to controller address 127.0.0.1")
+}
+
+func (s *webhooksSuite) assertNoMoreRequests(c *gc.C) {
+	select {
+	case req := <-s.requests:
+		c.Fatalf("unexpected delivery: %s", req.body)
+	default:
+	}
+}